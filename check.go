@@ -0,0 +1,245 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/flokiorg/go-flokicoin/chaincfg"
+	"github.com/flokiorg/twallet/config"
+	"github.com/flokiorg/twallet/flnd"
+	"github.com/flokiorg/twallet/load"
+	. "github.com/flokiorg/twallet/utils"
+)
+
+const (
+	// checkFeeTimeout bounds the request reaching the fee API.
+	checkFeeTimeout = 15 * time.Second
+	// checkSeedTimeout bounds the resolution of a DNS seed.
+	checkSeedTimeout = 10 * time.Second
+)
+
+// configCheck prints the findings of --check-config and counts the
+// problems among them.
+type configCheck struct {
+	out      io.Writer
+	problems int
+}
+
+func (c *configCheck) ok(name, format string, args ...any) {
+	c.report("ok", name, format, args...)
+}
+
+func (c *configCheck) warn(name, format string, args ...any) {
+	c.report("warn", name, format, args...)
+}
+
+func (c *configCheck) fail(name, format string, args ...any) {
+	c.problems++
+	c.report("FAIL", name, format, args...)
+}
+
+func (c *configCheck) report(status, name, format string, args ...any) {
+	fmt.Fprintf(c.out, "  %-5s %-11s %s\n", status, name, fmt.Sprintf(format, args...))
+}
+
+// checkConfig reports on the resolved options without starting the wallet
+// and returns the number of problems found. Warnings are not problems.
+func checkConfig(out io.Writer, opts *cliOptions, dirs config.Dirs) int {
+	c := &configCheck{out: out}
+	fmt.Fprintln(out, "Checking the configuration")
+
+	if FileExists(opts.ConfigFile) {
+		c.ok("config", "read %s", opts.ConfigFile)
+	} else {
+		c.warn("config", "no file at %s, running on the defaults", opts.ConfigFile)
+	}
+	c.ok("network", "%s (peer port %s)", opts.NetworkName, opts.Network.DefaultPort)
+
+	c.checkPeers("connect", opts.ConnectPeers)
+	c.checkPeers("addpeer", opts.AddPeers)
+	c.checkDNSSeeds(opts)
+	if len(opts.ConnectPeers) == 0 && len(opts.AddPeers) == 0 && len(opts.DNSSeeds) == 0 {
+		c.ok("peers", "none configured, peers are discovered")
+	}
+
+	used := map[string]string{}
+	c.checkListeners("rpclisten", opts.RawRPCListeners, used)
+	c.checkListeners("restlisten", opts.RawRESTListeners, used)
+	c.checkListeners("listen", opts.RawListeners, used)
+
+	c.checkFeeURL(opts)
+
+	c.checkDir("walletdir", opts.Walletdir)
+	c.checkDir("logdir", logDir(opts, dirs))
+	c.checkDir("metadatadir", metadataDir(opts))
+
+	switch c.problems {
+	case 0:
+		fmt.Fprintln(out, "\nConfiguration OK.")
+	case 1:
+		fmt.Fprintln(out, "\n1 problem found.")
+	default:
+		fmt.Fprintf(out, "\n%d problems found.\n", c.problems)
+	}
+	return c.problems
+}
+
+func (c *configCheck) checkPeers(name string, peers []string) {
+	for _, peer := range peers {
+		if err := flnd.ValidatePeer(peer); err != nil {
+			c.fail(name, "%q: %v", peer, err)
+			continue
+		}
+		c.ok(name, "%s", peer)
+	}
+}
+
+// checkDNSSeeds resolves every seed, as the wallet does at startup.
+func (c *configCheck) checkDNSSeeds(opts *cliOptions) {
+	if len(opts.DNSSeeds) == 0 {
+		return
+	}
+	if opts.TorActive && opts.TorNoClearnet {
+		c.warn("dnsseed", "not resolved with tor.noclearnet, the lookups would leave Tor")
+		return
+	}
+	for _, seed := range opts.DNSSeeds {
+		ctx, cancel := context.WithTimeout(context.Background(), checkSeedTimeout)
+		peers, err := flnd.ResolveSeeds(ctx, []string{seed}, opts.Network.DefaultPort)
+		cancel()
+		switch {
+		case err != nil:
+			c.fail("dnsseed", "%v", err)
+		case len(peers) == 0:
+			c.warn("dnsseed", "%s resolves to no address", seed)
+		default:
+			c.ok("dnsseed", "%s: %d peers", seed, len(peers))
+		}
+	}
+}
+
+// checkListeners validates the listeners of option name and tries to
+// listen on them. used maps the addresses seen so far to their option, as
+// two listeners cannot share one.
+func (c *configCheck) checkListeners(name string, listeners []string, used map[string]string) {
+	for _, addr := range listeners {
+		if err := flnd.ValidateListener(addr); err != nil {
+			c.fail(name, "%q: %v", addr, err)
+			continue
+		}
+		if other, ok := used[addr]; ok {
+			c.fail(name, "%s is also a %s address", addr, other)
+			continue
+		}
+		used[addr] = name
+		if strings.HasPrefix(addr, "unix://") {
+			c.ok(name, "%s", addr)
+			continue
+		}
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			// Taken by a running twallet, or another program.
+			c.warn(name, "%s cannot be listened on now: %v", addr, err)
+			continue
+		}
+		l.Close()
+		c.ok(name, "%s", addr)
+	}
+}
+
+// checkFeeURL validates the fee APIs and reads each once. One of them
+// answering is enough, the daemon fails over to it.
+func (c *configCheck) checkFeeURL(opts *cliOptions) {
+	sources := flnd.FeeSources(opts.Feeurl, opts.FeeURLFallbacks)
+	if len(sources) == 0 {
+		if opts.Network.Name == chaincfg.MainNetParams.Name {
+			c.fail("feeurl", "not set, the daemon needs it on mainnet")
+		} else {
+			c.ok("feeurl", "not set")
+		}
+		return
+	}
+
+	var down []string
+	answered := false
+	for _, source := range sources {
+		if err := (flnd.ConfigChange{Feeurl: &source}).Validate(); err != nil {
+			c.fail("feeurl", "%q is not an http(s) URL", source)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), checkFeeTimeout)
+		estimates, err := load.FetchFeeEstimates(ctx, &http.Client{Transport: opts.HTTPTransport()}, source)
+		cancel()
+		if err != nil {
+			down = append(down, fmt.Sprintf("%s is not reachable: %v", source, err))
+			continue
+		}
+		answered = true
+		c.ok("feeurl", "%s answered, next block %.0f loki/vB", source, estimates.FastestFee)
+	}
+	for _, reason := range down {
+		if answered {
+			c.warn("feeurl", "%s", reason)
+		} else {
+			c.fail("feeurl", "%s", reason)
+		}
+	}
+}
+
+// checkDir makes sure twallet can write into dir, or create it. The wallet
+// keeps its keys there, so a directory other users can read is a warning.
+func (c *configCheck) checkDir(name, dir string) {
+	info, err := os.Stat(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		parent := filepath.Dir(dir)
+		for !FileExists(parent) && parent != filepath.Dir(parent) {
+			parent = filepath.Dir(parent)
+		}
+		if err := writable(parent); err != nil {
+			c.fail(name, "%s cannot be created: %v", dir, err)
+			return
+		}
+		c.ok(name, "%s will be created", dir)
+		return
+	}
+	if err != nil {
+		c.fail(name, "%v", err)
+		return
+	}
+	if !info.IsDir() {
+		c.fail(name, "%s is not a directory", dir)
+		return
+	}
+	if err := writable(dir); err != nil {
+		c.fail(name, "%s is not writable: %v", dir, err)
+		return
+	}
+	if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm&0o077 != 0 {
+		c.warn(name, "%s is open to other users (mode %04o), 0700 keeps them out", dir, perm)
+		return
+	}
+	c.ok(name, "%s", dir)
+}
+
+func writable(dir string) error {
+	f, err := os.CreateTemp(dir, ".twallet-check-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flokiorg/go-flokicoin/chainutil"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/shared"
+)
+
+// AmountField is an input field for an amount. It takes digits and as many
+// decimals as its unit has, groups the thousands as they are typed and
+// switches between FLC and loki with 'u', converting what was typed.
+type AmountField struct {
+	*tview.InputField
+	label   string
+	unit    shared.Denomination
+	digits  string
+	changed func(text string)
+	toggled func(unit shared.Denomination)
+}
+
+// NewAmountField returns an empty field in the active denomination, its
+// unit shown after label.
+func NewAmountField(label string) *AmountField {
+	f := &AmountField{
+		InputField: tview.NewInputField(),
+		label:      label,
+	}
+	f.InputField.SetAcceptanceFunc(f.accept)
+	f.InputField.SetChangedFunc(f.format)
+	f.SetUnit(shared.CurrentDenomination())
+	return f
+}
+
+// SetUnit switches the unit amounts are typed in, converting the amount
+// typed so far.
+func (f *AmountField) SetUnit(unit shared.Denomination) *AmountField {
+	amount, err := f.Amount()
+	f.unit = unit
+	f.InputField.SetLabel(fmt.Sprintf("%s (%s):", f.label, unit.Label()))
+	if err == nil {
+		f.SetAmount(amount)
+	}
+	return f
+}
+
+// Unit returns the unit amounts are typed in.
+func (f *AmountField) Unit() shared.Denomination {
+	return f.unit
+}
+
+// Amount returns the amount typed.
+func (f *AmountField) Amount() (chainutil.Amount, error) {
+	return shared.ParseAmountIn(f.GetText(), f.unit)
+}
+
+// SetAmount shows amount in the unit of the field.
+func (f *AmountField) SetAmount(amount chainutil.Amount) *AmountField {
+	f.InputField.SetText(shared.FormatAmountIn(amount, f.unit))
+	return f
+}
+
+// InRange is AmountRange for the field, reading the amount in its unit
+// rather than the active denomination.
+func (f *AmountField) InRange(minimum, maximum chainutil.Amount) Validator {
+	return func(string) error {
+		amount, err := f.Amount()
+		if err != nil {
+			return err
+		}
+		return checkAmountRange(amount, minimum, maximum)
+	}
+}
+
+// SetChangedFunc sets the handler called with the text once it is
+// formatted.
+func (f *AmountField) SetChangedFunc(handler func(text string)) *AmountField {
+	f.changed = handler
+	return f
+}
+
+// SetToggledFunc sets the handler called with the unit switched to with 'u',
+// once the label and the amount are in it.
+func (f *AmountField) SetToggledFunc(handler func(unit shared.Denomination)) *AmountField {
+	f.toggled = handler
+	return f
+}
+
+// accept takes digits and a decimal point, without more decimals than the
+// unit has.
+func (f *AmountField) accept(text string, last rune) bool {
+	if text == "" {
+		return true
+	}
+	if last != 0 && last != '.' && last != ',' && (last < '0' || last > '9') {
+		return false
+	}
+	whole, frac, hasPoint := strings.Cut(strings.ReplaceAll(text, ",", ""), ".")
+	if hasPoint && (f.unit.Precision() == 0 || strings.Contains(frac, ".")) {
+		return false
+	}
+	if len(frac) > f.unit.Precision() {
+		return false
+	}
+	for _, r := range whole + frac {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// format groups the thousands of the text. Only edits at its end are
+// formatted as they are typed, since that moves the cursor to the end; an
+// edit in the middle is formatted when the field loses the focus.
+func (f *AmountField) format(text string) {
+	digits := strings.ReplaceAll(text, ",", "")
+	atEnd := strings.HasPrefix(digits, f.digits) || strings.HasPrefix(f.digits, digits)
+	f.digits = digits
+
+	if grouped := shared.GroupThousands(text); atEnd && grouped != text {
+		// Changed again with the grouped text.
+		f.InputField.SetText(grouped)
+		return
+	}
+	if f.changed != nil {
+		f.changed(text)
+	}
+}
+
+func (f *AmountField) Blur() {
+	if text := f.GetText(); shared.GroupThousands(text) != text {
+		f.InputField.SetText(shared.GroupThousands(text))
+	}
+	f.InputField.Blur()
+}
+
+func (f *AmountField) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	handler := f.InputField.InputHandler()
+	return func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'u' {
+			next := shared.DenominationLoki
+			if f.unit == shared.DenominationLoki {
+				next = shared.DenominationFLC
+			}
+			f.SetUnit(next)
+			if f.toggled != nil {
+				f.toggled(next)
+			}
+			return
+		}
+		handler(event, setFocus)
+	}
+}
@@ -0,0 +1,32 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/shared"
+)
+
+func TestAmountFieldToggle(t *testing.T) {
+	shared.SetDenomination(shared.DenominationFLC)
+	f := NewAmountField("Amount")
+	f.SetText("1.5")
+
+	var toggled []shared.Denomination
+	f.SetToggledFunc(func(unit shared.Denomination) {
+		if got, want := f.GetLabel(), "Amount (loki):"; got != want {
+			t.Errorf("label in the toggled func = %q, want %q", got, want)
+		}
+		toggled = append(toggled, unit)
+	})
+
+	f.InputHandler()(tcell.NewEventKey(tcell.KeyRune, 'u', tcell.ModNone), func(tview.Primitive) {})
+	if got, want := f.GetText(), "150,000,000"; got != want {
+		t.Fatalf("amount = %q, want %q", got, want)
+	}
+	if len(toggled) != 1 || toggled[0] != shared.DenominationLoki {
+		t.Fatalf("toggled = %v, want loki once", toggled)
+	}
+}
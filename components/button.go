@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/flokiorg/twallet/shared"
 	"github.com/rivo/tview"
 
 	"github.com/gdamore/tcell/v2"
@@ -26,15 +27,16 @@ type SwitchButton struct {
 }
 
 func NewSwitchButton(id int, label string, active bool) *SwitchButton {
+	theme := shared.CurrentTheme()
 	b := &SwitchButton{
 		Grid:          tview.NewGrid(),
 		TextView:      tview.NewTextView(),
 		ID:            id,
 		Label:         label,
-		ActiveColor:   tcell.ColorOrange,
-		InactiveColor: tcell.ColorGray,
-		ActiveText:    tcell.ColorOrange,
-		InactiveText:  tcell.ColorWhite,
+		ActiveColor:   theme.Primary,
+		InactiveColor: theme.Muted,
+		ActiveText:    theme.Primary,
+		InactiveText:  theme.Text,
 		active:        active,
 	}
 	b.TextView.SetDynamicColors(true).
@@ -131,28 +133,29 @@ func (b *ConfirmButton) OnClick(callback func()) {
 }
 
 func (b *ConfirmButton) render() {
+	theme := shared.CurrentTheme()
 	if b.isPressed {
 		if b.borderEffect {
 			// Use border effect if the border is enabled
 			b.textView.
-				SetText(fmt.Sprintf("[%s::b]%s", tcell.ColorOrange, b.label)).
-				SetBorderColor(tcell.ColorOrange)
+				SetText(fmt.Sprintf("[%s::b]%s", theme.Primary, b.label)).
+				SetBorderColor(theme.Primary)
 		} else {
 			// Use background effect if the border is disabled
 			b.textView.
-				SetText(fmt.Sprintf("[%s::b]%s", tcell.ColorGray, b.label)).
-				SetBackgroundColor(tcell.ColorOrange)
+				SetText(fmt.Sprintf("[%s::b]%s", theme.Muted, b.label)).
+				SetBackgroundColor(theme.Primary)
 			b.textView.SetBorder(true)
 		}
 	} else {
 		if b.borderEffect {
 			b.textView.
-				SetText(fmt.Sprintf("[%s::b]%s", tcell.ColorOrange, b.label)).
-				SetBorderColor(tcell.ColorOrange).Blur()
+				SetText(fmt.Sprintf("[%s::b]%s", theme.Primary, b.label)).
+				SetBorderColor(theme.Primary).Blur()
 		} else {
 			b.textView.
-				SetText(fmt.Sprintf("[%s::b]%s", tcell.ColorGray, b.label)).
-				SetBackgroundColor(tcell.ColorOrange).Blur()
+				SetText(fmt.Sprintf("[%s::b]%s", theme.Muted, b.label)).
+				SetBackgroundColor(theme.Primary).Blur()
 			b.textView.SetBorder(false)
 
 		}
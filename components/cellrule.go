@@ -0,0 +1,91 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// CellMatcher reports whether a cell value, as the provider returned it, is
+// styled by a rule.
+type CellMatcher func(value string) bool
+
+// cellRule colors the cells its matcher accepts.
+type cellRule struct {
+	match CellMatcher
+	color tcell.Color
+}
+
+// AddCellRule colors the cells of the column with key whose value match
+// accepts. Rules are tried in the order they were added and the first match
+// wins, so rows can carry plain values that sort and export as they read.
+func (t *Table) AddCellRule(key string, match CellMatcher, color tcell.Color) *Table {
+	key = strings.ToLower(strings.TrimSpace(key))
+	t.columnsMu.Lock()
+	if t.rules == nil {
+		t.rules = make(map[string][]cellRule)
+	}
+	t.rules[key] = append(t.rules[key], cellRule{match: match, color: color})
+	t.columnsMu.Unlock()
+
+	t.redraw()
+	return t
+}
+
+// CellNumber reads the number a cell value starts with, signed or not, such
+// as -1,234.5 in
+// "-1,234.5 𝔽 (≈ 3.10 USD)".
+func CellNumber(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	end := 0
+	for end < len(value) {
+		c := value[end]
+		if (c < '0' || c > '9') && c != ',' && c != '.' && !((c == '-' || c == '+') && end == 0) {
+			break
+		}
+		end++
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(value[:end], ",", ""), 64)
+	return n, err == nil
+}
+
+// Above matches the values whose number is greater than n.
+func Above(n float64) CellMatcher {
+	return func(value string) bool {
+		v, ok := CellNumber(value)
+		return ok && v > n
+	}
+}
+
+// Below matches the values whose number is less than n.
+func Below(n float64) CellMatcher {
+	return func(value string) bool {
+		v, ok := CellNumber(value)
+		return ok && v < n
+	}
+}
+
+// Equals matches the values that are text.
+func Equals(text string) CellMatcher {
+	return func(value string) bool {
+		return value == text
+	}
+}
+
+// HasSuffix matches the values ending with suffix.
+func HasSuffix(suffix string) CellMatcher {
+	return func(value string) bool {
+		return strings.HasSuffix(value, suffix)
+	}
+}
+
+// Any matches every value, as the last rule of a column to color the cells
+// no other rule matched.
+func Any(string) bool {
+	return true
+}
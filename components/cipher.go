@@ -7,10 +7,10 @@ package components
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/rivo/tview"
 
+	"github.com/flokiorg/twallet/i18n"
 	"github.com/flokiorg/twallet/load"
 	"github.com/flokiorg/twallet/shared"
 	. "github.com/flokiorg/twallet/shared"
@@ -24,6 +24,7 @@ type Cipher struct {
 	wordsGrid *tview.Grid
 	hexView   *tview.TextView
 	copyBtn   *ConfirmButton
+	qrBtn     *ConfirmButton
 
 	container *tview.Grid
 	words     []string
@@ -53,7 +54,8 @@ func NewCipher(l *load.Load, mnemonic []string, hex string) (*Cipher, int, error
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignLeft).SetWrap(true)
 
-	c.copyBtn = NewConfirmButton(l.Application, "copy", false, tcell.ColorBlack, 1, c.copyToClipboard)
+	c.copyBtn = NewConfirmButton(l.Application, i18n.T("cipher.copy"), false, tcell.ColorBlack, 1, c.copyToClipboard)
+	c.qrBtn = NewConfirmButton(l.Application, i18n.T("cipher.qr"), false, tcell.ColorBlack, 1, c.confirmShowQR)
 
 	if err := c.Update(mnemonic, hex); err != nil {
 		return nil, 0, err
@@ -63,9 +65,10 @@ func NewCipher(l *load.Load, mnemonic []string, hex string) (*Cipher, int, error
 		SetColumns(0, 0, 0).
 		AddItem(c.wordsGrid, 0, 0, 1, 3, 0, 0, false).
 		AddItem(c.hexView, 1, 0, 1, 3, 0, 0, false).
+		AddItem(c.qrBtn, 2, 0, 1, 1, 0, 0, false).
 		AddItem(c.copyBtn, 2, 2, 1, 1, 0, 0, false)
 
-	c.container.SetBorder(true).SetTitle("Cipher Card")
+	c.container.SetBorder(true).SetTitle(i18n.T("cipher.title"))
 
 	c.AddItem(c.container, 0, 1, true)
 
@@ -74,12 +77,18 @@ func NewCipher(l *load.Load, mnemonic []string, hex string) (*Cipher, int, error
 
 // SetWords sets the cipher words and updates the hex
 // It validates the word count and formats the displays accordingly.
+// Words are given in English and shown in the configured seed language.
 func (c *Cipher) Update(words []string, hex string) error {
 	// Validate word count
 	if !IsValidMnemonicLen(MnemonicLen(len(words))) {
 		return fmt.Errorf("invalid seed phrase: %d words", len(words))
 	}
 
+	words, err := i18n.LocalizeMnemonic(words)
+	if err != nil {
+		return err
+	}
+
 	c.wordsGrid.Clear()
 	c.wordsGrid.SetRows(makeRows(len(words))...)
 
@@ -98,7 +107,7 @@ func (c *Cipher) Update(words []string, hex string) error {
 		c.wordsGrid.AddItem(tv, row, col, 1, 1, 0, 0, false)
 	}
 
-	c.hexView.SetText(fmt.Sprintf("[orange:-:-]%s[-:-:-] %s", "Hex: ", hex)).SetBorderPadding(1, 1, 1, 1)
+	c.hexView.SetText(fmt.Sprintf("[orange:-:-]%s[-:-:-] %s", i18n.T("cipher.hex"), hex)).SetBorderPadding(1, 1, 1, 1)
 
 	c.words = words
 	c.hex = hex
@@ -110,7 +119,10 @@ func (c *Cipher) copyToClipboard() {
 	var sb strings.Builder
 
 	// Gather cipher words
-	sb.WriteString("Mnemonic:\n")
+	if lang := i18n.CurrentSeedLanguage(); lang != i18n.English {
+		sb.WriteString(i18n.T("cipher.wordlist_note", i18n.T("language."+string(lang))) + "\n")
+	}
+	sb.WriteString(i18n.T("cipher.mnemonic") + "\n")
 	for i, word := range c.words {
 		sb.WriteString(fmt.Sprintf("[%d] %s ", i+1, word))
 		if (i+1)%3 == 0 {
@@ -118,24 +130,68 @@ func (c *Cipher) copyToClipboard() {
 		}
 	}
 
-	sb.WriteString(fmt.Sprintf("\nInline Mnemonic:\n%s\n", strings.Join(c.words, " ")))
+	sb.WriteString(fmt.Sprintf("\n%s\n%s\n", i18n.T("cipher.inline_mnemonic"), strings.Join(c.words, " ")))
 
 	// Gather hex data
 	hexText := c.hexView.GetText(true)
 	sb.WriteString("\n" + hexText + "\n")
 
-	// Copy to clipboard
-	err := shared.ClipboardCopy(sb.String())
+	c.load.Notif.CancelToast()
+	CopyText(c.load, sb.String(), i18n.T("cipher.copied_what"))
+}
+
+// confirmShowQR warns that the QR code exposes the whole seed before it is
+// displayed.
+func (c *Cipher) confirmShowQR() {
+	nav := c.load.Nav
+	nav.ShowModal(NewDialog(i18n.T("cipher.qr_warning_title"), i18n.T("cipher.qr_warning"), nav.CloseModal,
+		[]string{i18n.T("onboard.cancel"), i18n.T("cipher.qr_show")},
+		nav.CloseModal,
+		func() {
+			nav.CloseModal()
+			c.showQR()
+		},
+		nav.CloseModal))
+}
+
+// showQR displays the enciphered seed hex as a QR code so it can be scanned
+// into an offline backup tool.
+func (c *Cipher) showQR() {
+	nav := c.load.Nav
+
+	qrtxt, err := shared.GenerateQRText(c.hex)
 	if err != nil {
-		c.load.Nav.ShowModal(ErrorModal(err.Error(), c.load.Nav.CloseModal))
+		nav.ShowModal(ErrorModal(err.Error(), nav.CloseModal))
 		return
 	}
 
-	if c.load != nil && c.load.Notif != nil {
-		c.load.Notif.CancelToast()
-		c.load.Notif.ShowToastWithTimeout("📋 Cipher copied", time.Second*10)
+	lines := strings.Split(strings.TrimRight(qrtxt, "\n"), "\n")
+	width := 0
+	for _, line := range lines {
+		width = max(width, tview.TaggedStringWidth(line))
 	}
 
+	qrView := tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetText(qrtxt)
+	qrView.SetBackgroundColor(tcell.ColorDefault)
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText("[gray::]" + i18n.T("cipher.qr_hint"))
+	hint.SetBackgroundColor(tcell.ColorDefault)
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow)
+	view.SetTitle(i18n.T("cipher.qr_title")).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true).
+		SetBorderPadding(1, 0, 1, 1)
+	view.AddItem(qrView, len(lines), 0, false).
+		AddItem(hint, 2, 0, true)
+
+	nav.ShowModal(NewModal(view, max(width+4, 50), len(lines)+5, nav.CloseModal))
 }
 
 // deprecated
@@ -145,6 +201,7 @@ func (c *Cipher) SetBackgroundColor(color tcell.Color) {
 	c.hexView.SetBackgroundColor(color)
 	c.container.SetBackgroundColor(color)
 	c.copyBtn.SetBackgroundColor(color)
+	c.qrBtn.SetBackgroundColor(color)
 }
 
 // makeRows calculates the number of rows needed based on the word count.
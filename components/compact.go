@@ -0,0 +1,12 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+// CompactWidth is the terminal width, in cells, below which the interface
+// switches to its compact layout.
+const CompactWidth = 90
+
+// compactCellWidth caps table cells while a table is drawn compact.
+const compactCellWidth = 22
@@ -0,0 +1,95 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"errors"
+	"os/exec"
+	"time"
+
+	"github.com/flokiorg/twallet/i18n"
+	"github.com/flokiorg/twallet/load"
+	"github.com/flokiorg/twallet/utils/clip"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const copyToastTimeout = 10 * time.Second
+
+// CopyText copies text to the clipboard and tells the user, naming what was
+// copied. It goes through the clip strategies, so over SSH or in tmux the
+// text reaches the local clipboard through the terminal. It reports whether
+// the text was copied.
+func CopyText(l *load.Load, text, what string) bool {
+	if text == "" {
+		return false
+	}
+	method, err := clip.CopyText(text)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			err = errors.New(i18n.T("copy.no_display"))
+		}
+		l.Notif.ShowToastLevel(load.SeverityError, i18n.T("copy.failed", err), copyToastTimeout)
+		return false
+	}
+	msg := i18n.T("copy.copied", what)
+	if method == clip.MethodOSC52 {
+		// The terminal gives no answer, so the copy can not be confirmed.
+		msg = i18n.T("copy.sent", what)
+	}
+	l.Notif.ShowToastLevel(load.SeveritySuccess, msg, copyToastTimeout)
+	return true
+}
+
+// CopyableText shows a value the user copies with Enter, c or a click.
+type CopyableText struct {
+	*tview.TextView
+	load  *load.Load
+	value string
+	what  string
+}
+
+// NewCopyableText shows value, copied as what names it.
+func NewCopyableText(l *load.Load, value, what string) *CopyableText {
+	c := &CopyableText{
+		TextView: tview.NewTextView().SetWrap(true).SetWordWrap(false),
+		load:     l,
+		what:     what,
+	}
+	c.SetValue(value)
+
+	c.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEnter || (event.Key() == tcell.KeyRune && event.Rune() == 'c') {
+			c.Copy()
+			return nil
+		}
+		return event
+	})
+	c.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if action == tview.MouseLeftClick && c.InRect(event.Position()) {
+			c.Copy()
+		}
+		return action, event
+	})
+	return c
+}
+
+// SetValue replaces the value shown and copied.
+func (c *CopyableText) SetValue(value string) *CopyableText {
+	c.value = value
+	c.SetText(value)
+	return c
+}
+
+// Value returns the value copied.
+func (c *CopyableText) Value() string {
+	return c.value
+}
+
+// Copy copies the value, doing nothing while it is empty.
+func (c *CopyableText) Copy() bool {
+	return CopyText(c.load, c.value, c.what)
+}
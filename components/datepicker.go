@@ -0,0 +1,262 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/shared"
+)
+
+// datePickerWidth fits the seven day columns of the month grid.
+const datePickerWidth = 7 * 3
+
+// DatePickerHeight is the rows a DatePicker draws: the month, the week days,
+// six weeks and the hint.
+const DatePickerHeight = 10
+
+// DatePicker is a month calendar driven by the keyboard: the arrows move by
+// day and week, PgUp and PgDn by month, [ and ] by year, t jumps to today.
+// Enter picks the day under the cursor; in range mode the first Enter marks
+// one end and the second the other. Backspace picks no date at all and Esc
+// cancels.
+type DatePicker struct {
+	*tview.Box
+
+	cursor time.Time
+	anchor time.Time
+	ranged bool
+	picked shared.DateRange
+
+	doneFunc   func(r shared.DateRange)
+	cancelFunc func()
+}
+
+// NewDatePicker opens on the first day of picked, or on today when it is
+// open, and highlights picked.
+func NewDatePicker(picked shared.DateRange) *DatePicker {
+	cursor := picked.From
+	if cursor.IsZero() {
+		cursor = picked.To
+	}
+	if cursor.IsZero() {
+		cursor = time.Now()
+	}
+	return &DatePicker{
+		Box:    tview.NewBox(),
+		cursor: shared.StartOfDay(cursor),
+		picked: picked,
+	}
+}
+
+// SetRanged makes Enter mark the two ends of a range rather than pick a
+// single day.
+func (d *DatePicker) SetRanged(ranged bool) *DatePicker {
+	d.ranged = ranged
+	return d
+}
+
+// SetDoneFunc sets the handler called with the picked range, a single day
+// when the picker is not ranged, or the zero range on Backspace.
+func (d *DatePicker) SetDoneFunc(handler func(r shared.DateRange)) *DatePicker {
+	d.doneFunc = handler
+	return d
+}
+
+// SetCancelFunc sets the handler called on Esc.
+func (d *DatePicker) SetCancelFunc(handler func()) *DatePicker {
+	d.cancelFunc = handler
+	return d
+}
+
+// Cursor returns the day under the cursor.
+func (d *DatePicker) Cursor() time.Time {
+	return d.cursor
+}
+
+// highlighted returns the range to draw: from the marked end to the cursor
+// while a range is being picked, the picked range otherwise.
+func (d *DatePicker) highlighted() shared.DateRange {
+	if !d.anchor.IsZero() {
+		return shared.NewDateRange(d.anchor, d.cursor)
+	}
+	return d.picked
+}
+
+func (d *DatePicker) Draw(screen tcell.Screen) {
+	d.Box.DrawForSubclass(screen, d)
+	x, y, width, height := d.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	left := x + max(0, (width-datePickerWidth)/2)
+
+	theme := shared.CurrentTheme()
+	title := fmt.Sprintf("[%s]◀[-] [%s::b]%s[-::-] [%s]▶[-]", theme.Muted, theme.Primary, d.cursor.Format("January 2006"), theme.Muted)
+	tview.Print(screen, title, x, y, width, tview.AlignCenter, theme.Text)
+
+	for i, name := range []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"} {
+		tview.Print(screen, name, left+i*3, y+1, 2, tview.AlignRight, theme.Muted)
+	}
+
+	first := time.Date(d.cursor.Year(), d.cursor.Month(), 1, 0, 0, 0, 0, shared.TimeZone())
+	// Weeks start on Monday.
+	offset := (int(first.Weekday()) + 6) % 7
+	today := shared.StartOfDay(time.Now())
+	span := d.highlighted()
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		cell := offset + day.Day() - 1
+		row := y + 2 + cell/7
+		if row >= y+height-1 {
+			break
+		}
+		style := tcell.StyleDefault.Background(d.GetBackgroundColor()).Foreground(theme.Text)
+		if !span.IsZero() && span.Contains(day) {
+			style = style.Background(theme.Selection).Foreground(theme.SelectionText)
+		}
+		if day.Equal(today) {
+			style = style.Underline(true)
+		}
+		if day.Equal(d.cursor) {
+			style = style.Reverse(true).Bold(true)
+		}
+		text := fmt.Sprintf("%2d", day.Day())
+		col := left + (cell%7)*3
+		for i, r := range text {
+			screen.SetContent(col+i, row, r, nil, style)
+		}
+	}
+
+	hint := "enter pick · ⌫ none · esc cancel"
+	if d.ranged {
+		hint = "enter start · ⌫ all dates · esc cancel"
+		if !d.anchor.IsZero() {
+			hint = "enter end · esc restart"
+		}
+	}
+	tview.Print(screen, hint, x, y+height-1, width, tview.AlignCenter, theme.Muted)
+}
+
+func (d *DatePicker) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return d.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		switch event.Key() {
+		case tcell.KeyLeft:
+			d.cursor = d.cursor.AddDate(0, 0, -1)
+		case tcell.KeyRight:
+			d.cursor = d.cursor.AddDate(0, 0, 1)
+		case tcell.KeyUp:
+			d.cursor = d.cursor.AddDate(0, 0, -7)
+		case tcell.KeyDown:
+			d.cursor = d.cursor.AddDate(0, 0, 7)
+		case tcell.KeyPgUp:
+			d.cursor = addMonths(d.cursor, -1)
+		case tcell.KeyPgDn:
+			d.cursor = addMonths(d.cursor, 1)
+		case tcell.KeyHome:
+			d.cursor = d.cursor.AddDate(0, 0, 1-d.cursor.Day())
+		case tcell.KeyEnd:
+			d.cursor = addMonths(d.cursor.AddDate(0, 0, 1-d.cursor.Day()), 1).AddDate(0, 0, -1)
+		case tcell.KeyEnter:
+			d.pick()
+		case tcell.KeyBackspace, tcell.KeyBackspace2, tcell.KeyDelete:
+			d.anchor = time.Time{}
+			d.finish(shared.DateRange{})
+		case tcell.KeyEscape:
+			if !d.anchor.IsZero() {
+				d.anchor = time.Time{}
+				return
+			}
+			if d.cancelFunc != nil {
+				d.cancelFunc()
+			}
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case '[':
+				d.cursor = addMonths(d.cursor, -12)
+			case ']':
+				d.cursor = addMonths(d.cursor, 12)
+			case 't', 'T':
+				d.cursor = shared.StartOfDay(time.Now())
+			case ' ':
+				d.pick()
+			}
+		}
+	})
+}
+
+func (d *DatePicker) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	return d.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		if !d.InRect(event.Position()) {
+			return false, nil
+		}
+		switch action {
+		case tview.MouseLeftDown:
+			setFocus(d)
+			return true, nil
+		case tview.MouseScrollUp:
+			d.cursor = addMonths(d.cursor, -1)
+			return true, nil
+		case tview.MouseScrollDown:
+			d.cursor = addMonths(d.cursor, 1)
+			return true, nil
+		case tview.MouseLeftClick:
+			if day, ok := d.dayAt(event.Position()); ok {
+				d.cursor = day
+				d.pick()
+			}
+			return true, nil
+		}
+		return false, nil
+	})
+}
+
+// dayAt returns the day drawn at the screen position.
+func (d *DatePicker) dayAt(mx, my int) (time.Time, bool) {
+	x, y, width, _ := d.GetInnerRect()
+	left := x + max(0, (width-datePickerWidth)/2)
+	if mx < left || mx >= left+datePickerWidth || my < y+2 {
+		return time.Time{}, false
+	}
+	first := time.Date(d.cursor.Year(), d.cursor.Month(), 1, 0, 0, 0, 0, shared.TimeZone())
+	offset := (int(first.Weekday()) + 6) % 7
+	day := (my-y-2)*7 + (mx-left)/3 - offset + 1
+	if day < 1 || day > first.AddDate(0, 1, -1).Day() {
+		return time.Time{}, false
+	}
+	return first.AddDate(0, 0, day-1), true
+}
+
+func (d *DatePicker) pick() {
+	if !d.ranged {
+		d.finish(shared.NewDateRange(d.cursor, d.cursor))
+		return
+	}
+	if d.anchor.IsZero() {
+		d.anchor = d.cursor
+		return
+	}
+	r := shared.NewDateRange(d.anchor, d.cursor)
+	d.anchor = time.Time{}
+	d.finish(r)
+}
+
+func (d *DatePicker) finish(r shared.DateRange) {
+	d.picked = r
+	if d.doneFunc != nil {
+		d.doneFunc(r)
+	}
+}
+
+// addMonths moves t by months, keeping to the last day of the month when
+// the day does not exist there, so Jan 31 goes to Feb 28 rather than Mar 3.
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, shared.TimeZone()).AddDate(0, months, 0)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last)-1)
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/shared"
+)
+
+// FilePickerMode is whether a FilePicker chooses a file to read or to write.
+type FilePickerMode int
+
+const (
+	// FileOpen picks an existing file.
+	FileOpen FilePickerMode = iota
+	// FileSave picks a file to write, asking before overwriting one.
+	FileSave
+)
+
+// FilePicker browses directories to choose a file. The list moves through
+// the directories, the name field takes a file name or a path, relative to
+// the current directory or starting with ~. Tab and Shift+Tab move between
+// the list and the form, Esc cancels.
+type FilePicker struct {
+	*tview.Pages
+	app  *tview.Application
+	mode FilePickerMode
+
+	dir        string
+	extensions []string
+
+	dirView   *tview.TextView
+	list      *tview.List
+	nameField *tview.InputField
+	form      *tview.Form
+	errorView *tview.TextView
+
+	selected  func(path string)
+	cancelled func()
+}
+
+// NewFilePicker starts in the directory of path, with its file name in the
+// name field in FileSave mode. A path of a directory starts in it.
+func NewFilePicker(app *tview.Application, mode FilePickerMode, path string) *FilePicker {
+	theme := shared.CurrentTheme()
+
+	p := &FilePicker{
+		Pages:     tview.NewPages(),
+		app:       app,
+		mode:      mode,
+		dirView:   tview.NewTextView().SetDynamicColors(true),
+		errorView: tview.NewTextView().SetDynamicColors(true),
+		list: tview.NewList().
+			ShowSecondaryText(false).
+			SetHighlightFullLine(true).
+			SetSelectedBackgroundColor(theme.Selection).
+			SetSelectedTextColor(theme.SelectionText),
+		form: tview.NewForm(),
+	}
+	p.dirView.SetBackgroundColor(tcell.ColorDefault)
+	p.dirView.SetBorderPadding(0, 0, 1, 1)
+	p.errorView.SetBackgroundColor(tcell.ColorDefault)
+	p.errorView.SetBorderPadding(0, 0, 1, 1)
+	p.list.SetBackgroundColor(tcell.ColorDefault)
+	p.list.SetBorderPadding(0, 0, 1, 1)
+	p.form.SetBackgroundColor(tcell.ColorDefault)
+	p.form.SetBorderPadding(0, 0, 1, 1)
+
+	dir, name := p.start(path)
+
+	p.form.AddInputField("File:", name, 0, nil, nil)
+	p.nameField = p.form.GetFormItem(0).(*tview.InputField)
+	p.nameField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Taken before the form moves the focus on to its buttons.
+		if event.Key() == tcell.KeyEnter {
+			p.submit()
+			return nil
+		}
+		return event
+	})
+	p.form.AddButton("Cancel", p.cancel)
+	if mode == FileSave {
+		p.form.AddButton("Save", p.submit)
+	} else {
+		p.form.AddButton("Open", p.submit)
+	}
+
+	browse := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.dirView, 1, 0, false).
+		AddItem(p.list, 0, 1, true).
+		AddItem(p.errorView, 1, 0, false).
+		AddItem(p.form, 3, 0, false)
+	p.AddPage("browse", browse, true, true)
+
+	p.SetInputCapture(p.handleKeys)
+	p.chdir(dir)
+	return p
+}
+
+// start returns the directory to list first and the file name to fill in.
+func (p *FilePicker) start(path string) (string, string) {
+	path, err := shared.ExpandHome(strings.TrimSpace(path))
+	if err != nil || path == "" {
+		path, _ = os.Getwd()
+	}
+	path, _ = filepath.Abs(path)
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return path, ""
+	}
+	name := filepath.Base(path)
+	if p.mode == FileOpen {
+		name = ""
+	}
+	return filepath.Dir(path), name
+}
+
+// SetExtensions lists only files with one of extensions, such as ".png".
+// In FileSave mode a name without one gets the first.
+func (p *FilePicker) SetExtensions(extensions ...string) *FilePicker {
+	p.extensions = extensions
+	p.chdir(p.dir)
+	return p
+}
+
+// SetSelectedFunc sets the handler called with the absolute path chosen.
+func (p *FilePicker) SetSelectedFunc(handler func(path string)) *FilePicker {
+	p.selected = handler
+	return p
+}
+
+// SetCancelFunc sets the handler called on Cancel or Esc.
+func (p *FilePicker) SetCancelFunc(handler func()) *FilePicker {
+	p.cancelled = handler
+	return p
+}
+
+// Dir returns the directory shown.
+func (p *FilePicker) Dir() string {
+	return p.dir
+}
+
+// Focus starts in the name field when saving, where a name is expected, and
+// in the list otherwise.
+func (p *FilePicker) Focus(delegate func(tview.Primitive)) {
+	if name, _ := p.GetFrontPage(); name != "browse" {
+		p.Pages.Focus(delegate)
+		return
+	}
+	if p.mode == FileSave {
+		delegate(p.form)
+		return
+	}
+	delegate(p.list)
+}
+
+func (p *FilePicker) handleKeys(event *tcell.EventKey) *tcell.EventKey {
+	if name, _ := p.GetFrontPage(); name != "browse" {
+		if event.Key() == tcell.KeyESC {
+			p.closeConfirm()
+			return nil
+		}
+		return event
+	}
+
+	switch event.Key() {
+	case tcell.KeyESC:
+		p.cancel()
+		return nil
+	case tcell.KeyTab:
+		if p.list.HasFocus() {
+			p.app.SetFocus(p.form)
+			return nil
+		}
+	case tcell.KeyBacktab:
+		if p.nameField.HasFocus() {
+			p.app.SetFocus(p.list)
+			return nil
+		}
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if p.list.HasFocus() {
+			p.chdir(filepath.Dir(p.dir))
+			return nil
+		}
+	}
+	return event
+}
+
+// chdir lists dir: its parent first, then its directories and files, hidden
+// ones left out.
+func (p *FilePicker) chdir(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		p.showError(err.Error())
+		return
+	}
+	p.showError("")
+
+	previous := p.dir
+	p.dir = dir
+	p.dirView.SetText(fmt.Sprintf("[%s::b]%s[-::-]", shared.CurrentTheme().Accent, tview.Escape(dir)))
+
+	p.list.Clear()
+	theme := shared.CurrentTheme()
+	if parent := filepath.Dir(dir); parent != dir {
+		p.list.AddItem(fmt.Sprintf("[%s::]../", theme.Muted), "", 0, func() { p.chdir(parent) })
+	}
+
+	var files []os.DirEntry
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if !isDir(dir, entry) {
+			files = append(files, entry)
+			continue
+		}
+		sub := filepath.Join(dir, entry.Name())
+		p.list.AddItem(fmt.Sprintf("[%s::]%s/", theme.Primary, tview.Escape(entry.Name())), "", 0, func() { p.chdir(sub) })
+		if sub == previous {
+			p.list.SetCurrentItem(p.list.GetItemCount() - 1)
+		}
+	}
+	for _, entry := range files {
+		if !p.accepts(entry.Name()) {
+			continue
+		}
+		name := entry.Name()
+		p.list.AddItem(tview.Escape(name), "", 0, func() { p.pick(name) })
+	}
+}
+
+// isDir reports whether entry is a directory, following symlinks.
+func isDir(dir string, entry os.DirEntry) bool {
+	if entry.IsDir() {
+		return true
+	}
+	if entry.Type()&os.ModeSymlink == 0 {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(dir, entry.Name()))
+	return err == nil && info.IsDir()
+}
+
+func (p *FilePicker) accepts(name string) bool {
+	if len(p.extensions) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(p.extensions, func(ext string) bool {
+		return strings.EqualFold(filepath.Ext(name), ext)
+	})
+}
+
+// pick chooses a listed file: opened right away, or put in the name field
+// to confirm when saving.
+func (p *FilePicker) pick(name string) {
+	p.nameField.SetText(name)
+	if p.mode == FileOpen {
+		p.submit()
+		return
+	}
+	p.app.SetFocus(p.form)
+}
+
+// resolve returns the absolute path of the name field.
+func (p *FilePicker) resolve() (string, error) {
+	name, err := shared.ExpandHome(strings.TrimSpace(p.nameField.GetText()))
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", fmt.Errorf("enter a file name")
+	}
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(p.dir, name)
+	}
+	return filepath.Clean(name), nil
+}
+
+func (p *FilePicker) submit() {
+	path, err := p.resolve()
+	if err != nil {
+		p.showError(err.Error())
+		p.app.SetFocus(p.nameField)
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		p.nameField.SetText("")
+		p.chdir(path)
+		p.app.SetFocus(p.list)
+		return
+	}
+
+	if p.mode == FileOpen {
+		if err != nil {
+			p.showError(fmt.Sprintf("%s does not exist", filepath.Base(path)))
+			p.app.SetFocus(p.nameField)
+			return
+		}
+		p.choose(path)
+		return
+	}
+
+	if len(p.extensions) > 0 && !p.accepts(path) {
+		path += p.extensions[0]
+		info, err = os.Stat(path)
+	}
+	if dir, statErr := os.Stat(filepath.Dir(path)); statErr != nil || !dir.IsDir() {
+		p.showError(fmt.Sprintf("%s is not a directory", filepath.Dir(path)))
+		p.app.SetFocus(p.nameField)
+		return
+	}
+	switch {
+	case err == nil && info.IsDir():
+		p.showError(fmt.Sprintf("%s is a directory", filepath.Base(path)))
+		p.app.SetFocus(p.nameField)
+	case err == nil:
+		p.confirmOverwrite(path)
+	default:
+		p.choose(path)
+	}
+}
+
+func (p *FilePicker) confirmOverwrite(path string) {
+	dialog := NewDialog(
+		"Overwrite",
+		fmt.Sprintf("%s already exists. Replace it?", filepath.Base(path)),
+		p.closeConfirm,
+		[]string{"Cancel", "Replace"},
+		p.closeConfirm,
+		func() {
+			p.closeConfirm()
+			p.choose(path)
+		},
+	)
+	p.AddPage("confirm", dialog, true, true)
+	p.app.SetFocus(dialog)
+}
+
+func (p *FilePicker) closeConfirm() {
+	p.RemovePage("confirm")
+	p.app.SetFocus(p.nameField)
+}
+
+func (p *FilePicker) choose(path string) {
+	if p.selected != nil {
+		p.selected(path)
+	}
+}
+
+func (p *FilePicker) cancel() {
+	if p.cancelled != nil {
+		p.cancelled()
+	}
+}
+
+func (p *FilePicker) showError(text string) {
+	if text == "" {
+		p.errorView.SetText("")
+		return
+	}
+	p.errorView.SetText(fmt.Sprintf("[%s::]%s[-::]", shared.CurrentTheme().Error, tview.Escape(text)))
+}
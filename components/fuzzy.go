@@ -0,0 +1,358 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/shared"
+)
+
+// fuzzyListHeight is the most options an open FuzzyDropDown lists at once.
+const fuzzyListHeight = 10
+
+// FuzzyDropDown is a drop-down whose options are filtered as a search is
+// typed, for lists too long to scroll through. Enter, Space or Down opens
+// it; typing narrows the options with shared.FuzzyFilter, Enter picks the
+// highlighted one and Esc closes it without a change.
+type FuzzyDropDown struct {
+	*tview.Box
+
+	label      string
+	labelWidth int
+	labelColor tcell.Color
+	fieldStyle tcell.Style
+	fieldWidth int
+	disabled   bool
+
+	options []string
+	current int
+
+	open    bool
+	search  string
+	matches []int
+	list    *tview.List
+
+	selected func(option string, index int)
+	finished func(key tcell.Key)
+}
+
+func NewFuzzyDropDown(label string) *FuzzyDropDown {
+	theme := shared.CurrentTheme()
+
+	d := &FuzzyDropDown{
+		Box:        tview.NewBox().SetBackgroundColor(tcell.ColorDefault),
+		label:      label,
+		labelColor: tview.Styles.SecondaryTextColor,
+		fieldStyle: tcell.StyleDefault.Background(tview.Styles.ContrastBackgroundColor).Foreground(tview.Styles.PrimaryTextColor),
+		current:    -1,
+		list: tview.NewList().
+			ShowSecondaryText(false).
+			SetHighlightFullLine(true).
+			SetSelectedBackgroundColor(theme.Selection).
+			SetSelectedTextColor(theme.SelectionText),
+	}
+	d.list.SetBackgroundColor(tview.Styles.MoreContrastBackgroundColor)
+	return d
+}
+
+// SetOptions replaces the options, selecting none, and sets the handler
+// called when one is picked.
+func (d *FuzzyDropDown) SetOptions(options []string, selected func(option string, index int)) *FuzzyDropDown {
+	d.options = options
+	d.selected = selected
+	d.current = -1
+	d.filter("")
+	return d
+}
+
+// SetCurrentOption selects the option at index, calling the selected
+// handler, or none with a negative index.
+func (d *FuzzyDropDown) SetCurrentOption(index int) *FuzzyDropDown {
+	if index < 0 || index >= len(d.options) {
+		d.current = -1
+		return d
+	}
+	d.current = index
+	if d.selected != nil {
+		d.selected(d.options[index], index)
+	}
+	return d
+}
+
+// GetCurrentOption returns the index and text of the selected option, -1
+// and "" when none is.
+func (d *FuzzyDropDown) GetCurrentOption() (int, string) {
+	if d.current < 0 {
+		return -1, ""
+	}
+	return d.current, d.options[d.current]
+}
+
+// SetSelectedFunc sets the handler called when an option is picked.
+func (d *FuzzyDropDown) SetSelectedFunc(handler func(option string, index int)) *FuzzyDropDown {
+	d.selected = handler
+	return d
+}
+
+func (d *FuzzyDropDown) SetFieldWidth(width int) *FuzzyDropDown {
+	d.fieldWidth = width
+	return d
+}
+
+func (d *FuzzyDropDown) GetLabel() string {
+	return d.label
+}
+
+func (d *FuzzyDropDown) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) tview.FormItem {
+	d.labelWidth = labelWidth
+	d.labelColor = labelColor
+	d.SetBackgroundColor(bgColor)
+	d.fieldStyle = tcell.StyleDefault.Foreground(fieldTextColor).Background(fieldBgColor)
+	return d
+}
+
+func (d *FuzzyDropDown) GetFieldWidth() int {
+	if d.fieldWidth > 0 {
+		return d.fieldWidth
+	}
+	width := 0
+	for _, option := range d.options {
+		width = max(width, tview.TaggedStringWidth(option))
+	}
+	return width + 2
+}
+
+func (d *FuzzyDropDown) GetFieldHeight() int {
+	return 1
+}
+
+func (d *FuzzyDropDown) SetFinishedFunc(handler func(key tcell.Key)) tview.FormItem {
+	d.finished = handler
+	return d
+}
+
+func (d *FuzzyDropDown) SetDisabled(disabled bool) tview.FormItem {
+	d.disabled = disabled
+	if disabled {
+		d.close()
+	}
+	return d
+}
+
+func (d *FuzzyDropDown) Focus(delegate func(p tview.Primitive)) {
+	if d.disabled && d.finished != nil {
+		d.finished(-1)
+		return
+	}
+	d.Box.Focus(delegate)
+}
+
+func (d *FuzzyDropDown) Blur() {
+	d.close()
+	d.Box.Blur()
+}
+
+// IsOpen reports whether the options are listed.
+func (d *FuzzyDropDown) IsOpen() bool {
+	return d.open
+}
+
+// filter lists the options matching search, best first, highlighting the
+// selected one when it matches.
+func (d *FuzzyDropDown) filter(search string) {
+	d.search = search
+	d.matches = shared.FuzzyFilter(search, d.options)
+	d.list.Clear()
+	for i, index := range d.matches {
+		d.list.AddItem(tview.Escape(d.options[index]), "", 0, nil)
+		if search == "" && index == d.current {
+			d.list.SetCurrentItem(i)
+		}
+	}
+}
+
+func (d *FuzzyDropDown) openList() {
+	d.open = true
+	d.filter("")
+}
+
+func (d *FuzzyDropDown) close() {
+	d.open = false
+	d.search = ""
+}
+
+// pick selects the highlighted option and closes the list.
+func (d *FuzzyDropDown) pick() {
+	item := d.list.GetCurrentItem()
+	d.close()
+	if item < 0 || item >= len(d.matches) {
+		return
+	}
+	d.SetCurrentOption(d.matches[item])
+}
+
+// fieldRect returns where the field is drawn, after the label.
+func (d *FuzzyDropDown) fieldRect() (int, int, int) {
+	x, y, width, _ := d.GetInnerRect()
+	labelWidth := d.labelWidth
+	if labelWidth <= 0 {
+		labelWidth = tview.TaggedStringWidth(d.label)
+	}
+	labelWidth = min(labelWidth, width)
+	fieldWidth := width - labelWidth
+	if d.fieldWidth > 0 {
+		fieldWidth = min(d.fieldWidth, fieldWidth)
+	}
+	return x + labelWidth, y, fieldWidth
+}
+
+func (d *FuzzyDropDown) Draw(screen tcell.Screen) {
+	d.Box.DrawForSubclass(screen, d)
+	x, y, width, height := d.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	theme := shared.CurrentTheme()
+
+	tview.Print(screen, d.label, x, y, width, tview.AlignLeft, d.labelColor)
+	fieldX, _, fieldWidth := d.fieldRect()
+
+	style := d.fieldStyle
+	if d.disabled {
+		style = style.Foreground(theme.Muted)
+	} else if d.HasFocus() && !d.open {
+		style = tcell.StyleDefault.Background(tview.Styles.PrimaryTextColor).Foreground(tview.Styles.ContrastBackgroundColor)
+	}
+	for i := 0; i < fieldWidth; i++ {
+		screen.SetContent(fieldX+i, y, ' ', nil, style)
+	}
+
+	_, fg, _ := style.Decompose()
+	switch {
+	case d.open:
+		text := tview.Escape(d.search)
+		if d.search == "" {
+			text = fmt.Sprintf("[%s]type to search", theme.Muted)
+		}
+		tview.Print(screen, text, fieldX, y, fieldWidth, tview.AlignLeft, fg)
+		if d.search != "" {
+			cursor := fieldX + min(tview.TaggedStringWidth(text), fieldWidth-1)
+			screen.ShowCursor(cursor, y)
+		}
+	case d.current >= 0:
+		tview.Print(screen, tview.Escape(d.options[d.current]), fieldX, y, fieldWidth, tview.AlignLeft, fg)
+	}
+
+	if !d.open {
+		return
+	}
+
+	// The list is drawn over the items below, as the form draws the focused
+	// item last.
+	listHeight := min(max(len(d.matches), 1), fuzzyListHeight)
+	_, screenHeight := screen.Size()
+	listY := y + 1
+	if listY+listHeight > screenHeight && y-listHeight >= 0 {
+		listY = y - listHeight
+	}
+	d.list.SetRect(fieldX, listY, fieldWidth, listHeight)
+	d.list.Draw(screen)
+	if len(d.matches) == 0 {
+		tview.Print(screen, "no match", fieldX, listY, fieldWidth, tview.AlignLeft, theme.Muted)
+	}
+}
+
+func (d *FuzzyDropDown) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return d.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		if d.disabled {
+			return
+		}
+
+		if !d.open {
+			switch key := event.Key(); key {
+			case tcell.KeyEnter, tcell.KeyDown:
+				d.openList()
+			case tcell.KeyRune:
+				if event.Rune() == ' ' {
+					d.openList()
+					return
+				}
+				d.openList()
+				d.filter(string(event.Rune()))
+			case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyESC:
+				if d.finished != nil {
+					d.finished(key)
+				}
+			}
+			return
+		}
+
+		switch event.Key() {
+		case tcell.KeyEnter:
+			d.pick()
+		case tcell.KeyESC:
+			d.close()
+		case tcell.KeyTab:
+			d.close()
+			if d.finished != nil {
+				d.finished(tcell.KeyTab)
+			}
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if d.search != "" {
+				runes := []rune(d.search)
+				d.filter(string(runes[:len(runes)-1]))
+			}
+		case tcell.KeyRune:
+			d.filter(d.search + string(event.Rune()))
+		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn, tcell.KeyHome, tcell.KeyEnd:
+			if handler := d.list.InputHandler(); handler != nil {
+				handler(event, setFocus)
+			}
+		}
+	})
+}
+
+func (d *FuzzyDropDown) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (bool, tview.Primitive) {
+	return d.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (bool, tview.Primitive) {
+		if d.disabled {
+			return false, nil
+		}
+
+		if d.open {
+			if d.list.InRect(event.Position()) {
+				if action == tview.MouseLeftClick {
+					if handler := d.list.MouseHandler(); handler != nil {
+						handler(tview.MouseLeftClick, event, setFocus)
+					}
+					d.pick()
+					return true, nil
+				}
+				if handler := d.list.MouseHandler(); handler != nil {
+					handler(action, event, setFocus)
+				}
+				return true, d
+			}
+			if action == tview.MouseLeftDown || action == tview.MouseLeftClick {
+				d.close()
+				return d.InRect(event.Position()), nil
+			}
+			return false, d
+		}
+
+		if !d.InRect(event.Position()) {
+			return false, nil
+		}
+		if action == tview.MouseLeftClick {
+			setFocus(d)
+			d.openList()
+			return true, d
+		}
+		return action == tview.MouseLeftDown, nil
+	})
+}
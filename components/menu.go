@@ -0,0 +1,57 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/shared"
+)
+
+// MenuItem is an entry of a context menu. Disabled items stay listed, greyed
+// out, so the menu keeps the same shape whatever is selected.
+type MenuItem struct {
+	Label    string
+	Shortcut rune
+	Disabled bool
+	Action   func()
+}
+
+// NewMenu returns a modal list of actions. Choosing an item runs its Action,
+// which is expected to close or replace the menu; Esc calls closeFunc.
+func NewMenu(title string, items []MenuItem, closeFunc func()) tview.Primitive {
+	theme := shared.CurrentTheme()
+
+	list := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true).
+		SetSelectedBackgroundColor(theme.Selection).
+		SetSelectedTextColor(theme.SelectionText).
+		SetShortcutColor(theme.Accent)
+	list.SetBackgroundColor(tcell.ColorDefault).
+		SetBorderPadding(1, 1, 1, 1)
+
+	width := len(title) + 8
+	for _, item := range items {
+		label, action := item.Label, item.Action
+		if item.Disabled {
+			label, action = fmt.Sprintf("[%s::]%s", theme.Muted, item.Label), nil
+		}
+		list.AddItem(label, "", item.Shortcut, action)
+		width = max(width, len(item.Label)+12)
+	}
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetTitle(title).
+		SetTitleColor(theme.ModalTitle).
+		SetBackgroundColor(theme.Modal).
+		SetBorder(true)
+	container.AddItem(list, 0, 1, true)
+
+	return NewModal(container, width, len(items)+4, closeFunc)
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"strings"
+
+	"github.com/flokiorg/flnd/aezeed"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/i18n"
+	"github.com/flokiorg/twallet/shared"
+)
+
+const maxMnemonicSuggestions = 8
+
+// MnemonicInput is a single line field for typing a seed word by word. The
+// word being typed is completed against the seed wordlist, unknown words are
+// reported as soon as they are typed and the checksum is verified once every
+// word is entered.
+type MnemonicInput struct {
+	*tview.InputField
+	statusFunc func(status string, valid bool)
+}
+
+func NewMnemonicInput(label string) *MnemonicInput {
+	m := &MnemonicInput{
+		InputField: tview.NewInputField(),
+	}
+
+	m.SetLabel(label).
+		SetFieldWidth(0).
+		SetPlaceholder(i18n.T("mnemonic.placeholder"))
+
+	m.SetAutocompleteFunc(m.suggest)
+	m.SetAutocompletedFunc(func(word string, _ int, source int) bool {
+		if source == tview.AutocompletedNavigate {
+			return false
+		}
+		text := m.GetText()
+		m.SetText(text[:len(text)-len(lastWord(text))] + word + " ")
+		return true
+	})
+	m.SetChangedFunc(func(string) {
+		m.validate()
+	})
+
+	return m
+}
+
+// SetStatusFunc registers a handler receiving a short, colored description
+// of the mnemonic state whenever the text changes, and whether the mnemonic
+// is complete and valid.
+func (m *MnemonicInput) SetStatusFunc(handler func(status string, valid bool)) *MnemonicInput {
+	m.statusFunc = handler
+	m.validate()
+	return m
+}
+
+// Words returns the typed words.
+func (m *MnemonicInput) Words() []string {
+	return strings.Fields(m.GetText())
+}
+
+func (m *MnemonicInput) suggest(text string) []string {
+	prefix := lastWord(text)
+	if prefix == "" {
+		return nil
+	}
+
+	lang := i18n.CurrentSeedLanguage()
+	var entries []string
+	for _, word := range i18n.WordList(lang) {
+		if len(entries) == maxMnemonicSuggestions {
+			break
+		}
+		if hasWordPrefix(word, prefix) {
+			entries = append(entries, word)
+		}
+	}
+
+	if len(entries) == 1 && i18n.SameWord(entries[0], prefix) {
+		return nil
+	}
+	return entries
+}
+
+func (m *MnemonicInput) validate() {
+	text := m.GetText()
+	words := strings.Fields(text)
+	lang := i18n.CurrentSeedLanguage()
+
+	status, valid := i18n.T("mnemonic.count", len(words), aezeed.NumMnemonicWords), false
+	unknown := -1
+	for i, word := range words {
+		if _, ok := i18n.WordIndex(lang, word); ok {
+			continue
+		}
+		// The word being typed is only flagged once it cannot be completed.
+		if i == len(words)-1 && !strings.HasSuffix(text, " ") && hasAnyWordPrefix(lang, word) {
+			continue
+		}
+		unknown = i
+		break
+	}
+
+	switch {
+	case unknown >= 0:
+		status = "[red::]" + i18n.T("mnemonic.unknown", unknown+1, words[unknown])
+	case len(words) == aezeed.NumMnemonicWords:
+		english, err := i18n.CanonicalMnemonic(words)
+		if err == nil {
+			err = shared.ValidateMnemonic(english)
+		}
+		if err != nil {
+			status = "[red::]" + i18n.T("mnemonic.checksum_bad")
+		} else {
+			status, valid = "[green::]"+i18n.T("mnemonic.checksum_ok"), true
+		}
+	case len(words) > aezeed.NumMnemonicWords:
+		status = "[red::]" + status
+	default:
+		status = "[gray::]" + status
+	}
+
+	if m.statusFunc != nil {
+		m.statusFunc(status, valid)
+	}
+}
+
+func lastWord(text string) string {
+	if idx := strings.LastIndexAny(text, " \t"); idx >= 0 {
+		return text[idx+1:]
+	}
+	return text
+}
+
+func hasWordPrefix(word, prefix string) bool {
+	prefix = strings.ToLower(prefix)
+	candidate := []rune(word)
+	if len([]rune(prefix)) > len(candidate) {
+		return false
+	}
+	return i18n.SameWord(string(candidate[:len([]rune(prefix))]), prefix)
+}
+
+func hasAnyWordPrefix(lang i18n.Language, prefix string) bool {
+	for _, word := range i18n.WordList(lang) {
+		if hasWordPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@
 package components
 
 import (
+	"github.com/flokiorg/twallet/shared"
 	"github.com/rivo/tview"
 
 	"github.com/gdamore/tcell/v2"
@@ -14,6 +15,23 @@ type Dialog struct {
 	*tview.Modal
 }
 
+// modalFrame centers its content and shrinks it to the screen when the
+// terminal is smaller than the requested size.
+type modalFrame struct {
+	*tview.Flex
+	view    *tview.Flex
+	content tview.Primitive
+	width   int
+	height  int
+}
+
+func (m *modalFrame) Draw(screen tcell.Screen) {
+	_, _, width, height := m.GetRect()
+	m.ResizeItem(m.view, min(m.width, width), 1)
+	m.view.ResizeItem(m.content, min(m.height, height), 1)
+	m.Flex.Draw(screen)
+}
+
 func NewModal(p tview.Primitive, width, height int, escFunc func()) tview.Primitive {
 
 	view := tview.NewFlex().SetDirection(tview.FlexRow)
@@ -21,15 +39,24 @@ func NewModal(p tview.Primitive, width, height int, escFunc func()) tview.Primit
 		AddItem(p, height, 1, true).
 		AddItem(nil, 0, 1, false)
 
-	modal := tview.NewFlex()
-	modal.SetBackgroundColor(tcell.ColorOrange)
+	modal := &modalFrame{
+		Flex:    tview.NewFlex(),
+		view:    view,
+		content: p,
+		width:   width,
+		height:  height,
+	}
+	modal.SetBackgroundColor(shared.CurrentTheme().Modal)
 	modal.AddItem(nil, 0, 1, false).
 		AddItem(view, width, 1, true).
 		AddItem(nil, 0, 1, false)
 
 	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		if escFunc != nil && event.Key() == tcell.KeyESC {
+			// Handled here only, so it closes this modal and not the one
+			// under it.
 			escFunc()
+			return nil
 		}
 
 		return event
@@ -47,7 +74,10 @@ func NewDialog(title, text string, escFunc func(), buttons []string, funcs ...fu
 	modal.Box.SetBackgroundColor(tcell.ColorDefault)
 	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		if escFunc != nil && event.Key() == tcell.KeyESC {
+			// Handled here only, so it closes this modal and not the one
+			// under it.
 			escFunc()
+			return nil
 		}
 
 		return event
@@ -77,7 +107,7 @@ func ErrorModal(text string, closeFunc func()) tview.Primitive {
 			return event
 		}
 		closeFunc()
-		return event
+		return nil
 	})
 
 	return m
@@ -87,7 +117,7 @@ func Toast(text string) tview.Primitive {
 	t := tview.NewTextView()
 	t.SetText(text).
 		SetDynamicColors(true).
-		SetTextColor(tcell.ColorWhite).
+		SetTextColor(shared.CurrentTheme().Text).
 		SetTextAlign(tview.AlignCenter)
 
 	return NewModal(t, 50, 3, nil)
@@ -0,0 +1,42 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/i18n"
+)
+
+const passwordMask = '*'
+
+// NewPasswordField returns a masked input field whose content can be revealed
+// and hidden again with Ctrl+R so users can check what they typed. It is a
+// plain InputField so forms can keep reading it back with GetFormItem.
+func NewPasswordField(label, text string) *tview.InputField {
+	field := tview.NewInputField().
+		SetLabel(label).
+		SetText(text).
+		SetFieldWidth(0).
+		SetPlaceholder(i18n.T("password.placeholder")).
+		SetMaskCharacter(passwordMask)
+
+	revealed := false
+	field.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyCtrlR {
+			return event
+		}
+		revealed = !revealed
+		if revealed {
+			field.SetMaskCharacter(0)
+		} else {
+			field.SetMaskCharacter(passwordMask)
+		}
+		return nil
+	})
+
+	return field
+}
@@ -0,0 +1,263 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flokiorg/twallet/shared"
+	"github.com/rivo/tview"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+const animationInterval = 100 * time.Millisecond
+
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// animation redraws the application at a steady pace while a primitive is
+// animated. It pauses while the primitive is not drawn, when its page is
+// hidden for instance, and resumes with the next draw.
+type animation struct {
+	mu     sync.Mutex
+	app    *tview.Application
+	frame  int
+	active bool
+	drawn  time.Time
+	stop   chan struct{}
+}
+
+func (a *animation) start(app *tview.Application) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.app = app
+	a.active = true
+	a.drawn = time.Now()
+	a.run()
+}
+
+// run starts the ticker. Callers hold mu.
+func (a *animation) run() {
+	if a.stop != nil || a.app == nil {
+		return
+	}
+	stop := make(chan struct{})
+	a.stop = stop
+	app := a.app
+
+	go func() {
+		ticker := time.NewTicker(animationInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				a.mu.Lock()
+				if time.Since(a.drawn) > 10*animationInterval {
+					// Not on screen: wait for the next draw.
+					if a.stop == stop {
+						a.stop = nil
+					}
+					a.mu.Unlock()
+					return
+				}
+				a.frame++
+				a.mu.Unlock()
+				app.QueueUpdateDraw(func() {})
+			}
+		}
+	}()
+}
+
+func (a *animation) halt() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.active = false
+	if a.stop != nil {
+		close(a.stop)
+		a.stop = nil
+	}
+}
+
+// draw returns the frame to draw and whether the animation is on, resuming
+// it if it was paused.
+func (a *animation) draw() (int, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.drawn = time.Now()
+	if a.active {
+		a.run()
+	}
+	return a.frame, a.active
+}
+
+// Spinner shows a label with a spinning indicator while work is in progress.
+// Its methods can be called from any goroutine.
+type Spinner struct {
+	*tview.Box
+	app  *tview.Application
+	anim animation
+
+	mu    sync.Mutex
+	label string
+}
+
+func NewSpinner(app *tview.Application, label string) *Spinner {
+	return &Spinner{
+		Box:   tview.NewBox().SetBackgroundColor(tcell.ColorDefault),
+		app:   app,
+		label: label,
+	}
+}
+
+func (s *Spinner) SetLabel(label string) *Spinner {
+	s.mu.Lock()
+	s.label = label
+	s.mu.Unlock()
+	return s
+}
+
+// Start animates the spinner until Stop. The label is drawn without the
+// indicator while it is stopped.
+func (s *Spinner) Start() {
+	s.anim.start(s.app)
+}
+
+func (s *Spinner) Stop() {
+	s.anim.halt()
+	if s.app != nil {
+		go s.app.QueueUpdateDraw(func() {})
+	}
+}
+
+func (s *Spinner) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	label := s.label
+	s.mu.Unlock()
+
+	if frame, running := s.anim.draw(); running {
+		label = fmt.Sprintf("[%s::b]%c[-::-] %s", shared.CurrentTheme().Primary, spinnerFrames[frame%len(spinnerFrames)], label)
+	}
+	tview.Print(screen, label, x, y, width, tview.AlignCenter, shared.CurrentTheme().Text)
+}
+
+// ProgressBar draws the completion of a task as a bar with its percentage, or
+// a moving block when the completion is not known. Its methods can be called
+// from any goroutine.
+type ProgressBar struct {
+	*tview.Box
+	app  *tview.Application
+	anim animation
+
+	mu       sync.Mutex
+	label    string
+	progress float64
+}
+
+// NewProgressBar returns an indeterminate progress bar.
+func NewProgressBar(app *tview.Application) *ProgressBar {
+	return &ProgressBar{
+		Box:      tview.NewBox().SetBackgroundColor(tcell.ColorDefault),
+		app:      app,
+		progress: -1,
+	}
+}
+
+// SetLabel sets the text drawn after the bar.
+func (p *ProgressBar) SetLabel(label string) *ProgressBar {
+	p.mu.Lock()
+	p.label = label
+	p.mu.Unlock()
+	return p
+}
+
+// SetProgress sets the completion, from 0 to 1, and stops the animation of
+// the indeterminate mode. A negative value switches to that mode.
+func (p *ProgressBar) SetProgress(progress float64) *ProgressBar {
+	if progress < 0 {
+		return p.SetIndeterminate()
+	}
+	p.anim.halt()
+	p.mu.Lock()
+	p.progress = min(progress, 1)
+	p.mu.Unlock()
+	return p
+}
+
+// SetIndeterminate animates the bar until a completion is set.
+func (p *ProgressBar) SetIndeterminate() *ProgressBar {
+	p.mu.Lock()
+	p.progress = -1
+	p.mu.Unlock()
+	p.anim.start(p.app)
+	return p
+}
+
+// Stop ends the animation of the indeterminate mode, for when the bar is no
+// longer shown.
+func (p *ProgressBar) Stop() {
+	p.anim.halt()
+}
+
+func (p *ProgressBar) Draw(screen tcell.Screen) {
+	p.Box.DrawForSubclass(screen, p)
+	x, y, width, height := p.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	p.mu.Lock()
+	label, progress := p.label, p.progress
+	p.mu.Unlock()
+
+	frame, _ := p.anim.draw()
+	text := ProgressBarText(progress, frame, barWidth(width, label))
+	if label != "" {
+		text += " " + label
+	}
+	tview.Print(screen, text, x, y, width, tview.AlignCenter, shared.CurrentTheme().Text)
+}
+
+// barWidth leaves room for the percentage and the label.
+func barWidth(width int, label string) int {
+	room := width - len(" 100%") - tview.TaggedStringWidth(label) - 1
+	return max(min(room, 40), 10)
+}
+
+// ProgressBarText renders a bar of width cells with its percentage, or the
+// frame of the indeterminate animation when progress is negative.
+func ProgressBarText(progress float64, frame, width int) string {
+	theme := shared.CurrentTheme()
+
+	if progress < 0 {
+		block := max(width/4, 1)
+		span := width - block
+		pos := frame % (2 * max(span, 1))
+		if pos > span {
+			pos = 2*span - pos
+		}
+		return fmt.Sprintf("[%s::]%s[%s::]%s[%s::]%s[-::]",
+			theme.Muted, strings.Repeat("░", pos),
+			theme.Primary, strings.Repeat("█", block),
+			theme.Muted, strings.Repeat("░", width-block-pos))
+	}
+
+	progress = min(progress, 1)
+	filled := int(progress * float64(width))
+	return fmt.Sprintf("[%s::]%s[%s::]%s[-::] %3.0f%%",
+		theme.Primary, strings.Repeat("█", filled),
+		theme.Muted, strings.Repeat("░", width-filled),
+		progress*100)
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"github.com/flokiorg/twallet/shared"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	// splitStep is how far, in percent, a key press moves the divider.
+	splitStep = 5
+	// splitMin keeps either pane from shrinking under this percentage.
+	splitMin = 10
+)
+
+// SplitPane shows two primitives side by side, or stacked, with a divider
+// moved by ctrl+arrow keys or by dragging it. Tab moves the focus to the
+// other pane.
+type SplitPane struct {
+	*tview.Box
+	panes    [2]tview.Primitive
+	stacked  bool
+	percent  int
+	focused  int
+	dragging bool
+
+	changedFunc func(percent int)
+}
+
+// NewSplitPane shows first on the left and second on the right, first taking
+// percent of the width.
+func NewSplitPane(first, second tview.Primitive, percent int) *SplitPane {
+	s := &SplitPane{
+		Box:   tview.NewBox(),
+		panes: [2]tview.Primitive{first, second},
+	}
+	s.SetPercent(percent)
+	return s
+}
+
+// SetStacked puts the first pane above the second instead of left of it.
+func (s *SplitPane) SetStacked(stacked bool) *SplitPane {
+	s.stacked = stacked
+	return s
+}
+
+// SetPercent sets the share of the first pane, kept between splitMin and
+// 100-splitMin.
+func (s *SplitPane) SetPercent(percent int) *SplitPane {
+	s.percent = max(splitMin, min(100-splitMin, percent))
+	return s
+}
+
+// Percent returns the share of the first pane.
+func (s *SplitPane) Percent() int {
+	return s.percent
+}
+
+// SetChangedFunc sets the handler told the new share of the first pane when
+// the user moves the divider.
+func (s *SplitPane) SetChangedFunc(handler func(percent int)) *SplitPane {
+	s.changedFunc = handler
+	return s
+}
+
+// FocusedPane returns 0 when the first pane has the focus, 1 for the second.
+func (s *SplitPane) FocusedPane() int {
+	return s.focused
+}
+
+// FocusPane moves the focus to the pane at index.
+func (s *SplitPane) FocusPane(index int, setFocus func(p tview.Primitive)) {
+	if index < 0 || index > 1 {
+		return
+	}
+	s.focused = index
+	setFocus(s.panes[index])
+}
+
+func (s *SplitPane) move(delta int) {
+	previous := s.percent
+	s.SetPercent(s.percent + delta)
+	if s.percent != previous && s.changedFunc != nil {
+		s.changedFunc(s.percent)
+	}
+}
+
+// layout returns the rectangles of the panes and the divider position.
+func (s *SplitPane) layout() (first, second [4]int, divider int) {
+	x, y, width, height := s.GetInnerRect()
+	if s.stacked {
+		size := max(0, (height-1)*s.percent/100)
+		first = [4]int{x, y, width, size}
+		second = [4]int{x, y + size + 1, width, max(0, height-size-1)}
+		return first, second, y + size
+	}
+	size := max(0, (width-1)*s.percent/100)
+	first = [4]int{x, y, size, height}
+	second = [4]int{x + size + 1, y, max(0, width-size-1), height}
+	return first, second, x + size
+}
+
+func (s *SplitPane) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+	first, second, divider := s.layout()
+	for i, rect := range [2][4]int{first, second} {
+		s.panes[i].SetRect(rect[0], rect[1], rect[2], rect[3])
+		s.panes[i].Draw(screen)
+	}
+
+	theme := shared.CurrentTheme()
+	style := tcell.StyleDefault.Foreground(theme.Muted)
+	if s.dragging {
+		style = style.Foreground(theme.Accent)
+	}
+	x, y, width, height := s.GetInnerRect()
+	if s.stacked {
+		for col := x; col < x+width; col++ {
+			screen.SetContent(col, divider, tview.BoxDrawingsLightHorizontal, nil, style)
+		}
+		return
+	}
+	for row := y; row < y+height; row++ {
+		screen.SetContent(divider, row, tview.BoxDrawingsLightVertical, nil, style)
+	}
+}
+
+func (s *SplitPane) Focus(delegate func(p tview.Primitive)) {
+	delegate(s.panes[s.focused])
+}
+
+func (s *SplitPane) HasFocus() bool {
+	return s.panes[0].HasFocus() || s.panes[1].HasFocus()
+}
+
+func (s *SplitPane) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return s.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		grow, shrink := tcell.KeyRight, tcell.KeyLeft
+		if s.stacked {
+			grow, shrink = tcell.KeyDown, tcell.KeyUp
+		}
+		if event.Modifiers()&tcell.ModCtrl != 0 {
+			switch event.Key() {
+			case grow:
+				s.move(splitStep)
+				return
+			case shrink:
+				s.move(-splitStep)
+				return
+			}
+		}
+		if event.Key() == tcell.KeyTab || event.Key() == tcell.KeyBacktab {
+			s.FocusPane(1-s.focused, setFocus)
+			return
+		}
+		for i, pane := range s.panes {
+			if pane.HasFocus() {
+				s.focused = i
+				if handler := pane.InputHandler(); handler != nil {
+					handler(event, setFocus)
+				}
+				return
+			}
+		}
+	})
+}
+
+func (s *SplitPane) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	return s.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		mx, my := event.Position()
+		_, _, divider := s.layout()
+		pos := mx
+		if s.stacked {
+			pos = my
+		}
+
+		switch {
+		case action == tview.MouseLeftDown && pos == divider && s.InRect(mx, my):
+			s.dragging = true
+			return true, s
+		case s.dragging && action == tview.MouseMove:
+			x, y, width, height := s.GetInnerRect()
+			start, size := x, width
+			if s.stacked {
+				start, size = y, height
+			}
+			if size > 1 {
+				s.move((pos-start)*100/(size-1) - s.percent)
+			}
+			return true, s
+		case s.dragging && action == tview.MouseLeftUp:
+			s.dragging = false
+			return true, nil
+		}
+
+		if !s.InRect(mx, my) {
+			return false, nil
+		}
+		for i, pane := range s.panes {
+			consumed, capture = pane.MouseHandler()(action, event, setFocus)
+			if consumed {
+				if action == tview.MouseLeftDown || action == tview.MouseLeftClick {
+					s.focused = i
+				}
+				return consumed, capture
+			}
+		}
+		return false, nil
+	})
+}
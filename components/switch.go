@@ -8,6 +8,7 @@ import (
 	"log"
 
 	"github.com/flokiorg/twallet/load"
+	"github.com/flokiorg/twallet/shared"
 	"github.com/rivo/tview"
 
 	"github.com/gdamore/tcell/v2"
@@ -44,6 +45,7 @@ func (s SwitchStyle) normalized() SwitchStyle {
 }
 
 func DefaultSwitchStyle() SwitchStyle {
+	theme := shared.CurrentTheme()
 	return SwitchStyle{
 		ButtonWidth:           18,
 		GapWidth:              2,
@@ -51,10 +53,10 @@ func DefaultSwitchStyle() SwitchStyle {
 		RowPadding:            1,
 		BackgroundColor:       tcell.ColorDefault,
 		ButtonBackgroundColor: tcell.ColorDefault,
-		ActiveBorderColor:     tcell.ColorOrange,
-		InactiveBorderColor:   tcell.ColorGray,
-		ActiveTextColor:       tcell.ColorOrange,
-		InactiveTextColor:     tcell.ColorWhite,
+		ActiveBorderColor:     theme.Primary,
+		InactiveBorderColor:   theme.Muted,
+		ActiveTextColor:       theme.Primary,
+		InactiveTextColor:     theme.Text,
 	}
 }
 
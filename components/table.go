@@ -6,9 +6,11 @@ package components
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/flokiorg/twallet/shared"
 	"github.com/rivo/tview"
 
 	"github.com/gdamore/tcell/v2"
@@ -22,40 +24,205 @@ const (
 )
 
 type Column struct {
-	Name     string
-	Align    int
-	IsSorted bool
-	SortDir  SortOrder
+	// Key identifies the column in configuration; it defaults to the
+	// lower-cased Name without spaces.
+	Key    string
+	Name   string
+	Align  int
+	Hidden bool
+	// Collapsible columns are dropped while the table is narrower than
+	// CompactWidth.
+	Collapsible bool
+	Sortable    bool
+	IsSorted    bool
+	// SortDir is the current direction of a sorted column, and the direction
+	// applied first when the user sorts by an unsorted one.
+	SortDir SortOrder
+}
+
+// ConfigKey returns Key, or the key derived from Name when Key is empty.
+func (c Column) ConfigKey() string {
+	if c.Key != "" {
+		return c.Key
+	}
+	return strings.ToLower(strings.ReplaceAll(c.Name, " ", ""))
 }
 
 type Table struct {
 	*tview.Table
 	title string
-
-	columns []Column
+	// titleCount, titleMore and filterLabel hold what the title shows, see
+	// UpdateTitle.
+	titleCount  int
+	titleMore   bool
+	filterLabel string
+
+	columns   []Column
+	columnsMu sync.RWMutex
 	// rows         *FLowMetricsSlice
 	scrollOnce sync.Once
 	netColor   tcell.Color
 	maxRows    int
+	sortFunc   func(column int, dir SortOrder)
+	menuFunc   func(row int)
+
+	// provider and placeholder hold the last content so the table can be
+	// redrawn when the column set or the compact state changes.
+	provider    RowProvider
+	placeholder string
+	compact     bool
+	content     *tableContent
+
+	// multiSelect enables marking rows with Space; marked holds the keys of
+	// the marked rows, see RowKeyer.
+	multiSelect bool
+	marked      map[string]struct{}
+	markedFunc  func(rows []int)
+
+	// rules holds the cell rules by column key, see AddCellRule.
+	rules map[string][]cellRule
+}
+
+// RowProvider supplies the rows of a table on demand: only the rows on screen
+// are turned into cells, so a provider can back tens of thousands of rows.
+type RowProvider interface {
+	RowCount() int
+	// GetRow returns the values of a row, starting at 0, with a value for
+	// each column, hidden or not.
+	GetRow(row int) []string
+}
+
+// RowKeyer is implemented by providers whose rows have a stable key, such as
+// an outpoint, so that marked rows stay marked when the rows are sorted or
+// refreshed. Rows of other providers are keyed by position and their marks
+// are cleared with each new provider.
+type RowKeyer interface {
+	RowKey(row int) string
+}
+
+// Rows is a RowProvider over rows already in memory.
+type Rows [][]string
+
+func (r Rows) RowCount() int { return len(r) }
+
+func (r Rows) GetRow(row int) []string { return r[row] }
+
+// tableContent renders the headers and the placeholder from the cells set on
+// the table, and the data rows from the provider as they are drawn.
+type tableContent struct {
+	tview.TableContentReadOnly
+
+	cells    [][]*tview.TableCell
+	provider RowProvider
+	columns  []int
+	aligns   []int
+	maxWidth int
+	// rules holds the cell rules of each visible column.
+	rules [][]cellRule
+	// isMarked, when set, prefixes the first column with a checkbox.
+	isMarked func(row int) bool
+
+	// The last row read, as tview asks for a row cell by cell.
+	lastRow    int
+	lastValues []string
+}
+
+func (c *tableContent) GetCell(row, column int) *tview.TableCell {
+	if row > 0 && c.provider != nil {
+		if row > c.provider.RowCount() || column < 0 || column >= len(c.columns) {
+			return nil
+		}
+		if c.lastValues == nil || c.lastRow != row {
+			c.lastRow, c.lastValues = row, c.provider.GetRow(row-1)
+		}
+		if c.columns[column] >= len(c.lastValues) {
+			return nil
+		}
+		value := c.lastValues[c.columns[column]]
+		text := value
+		if column == 0 && c.isMarked != nil {
+			text = checkbox(c.isMarked(row-1)) + text
+		}
+		cell := tview.NewTableCell(text).
+			SetExpansion(1).
+			SetMaxWidth(c.maxWidth).
+			SetAlign(c.aligns[column])
+		for _, rule := range c.rules[column] {
+			if rule.match(value) {
+				cell.SetTextColor(rule.color)
+				break
+			}
+		}
+		return cell
+	}
+	if row < 0 || row >= len(c.cells) || column < 0 || column >= len(c.cells[row]) {
+		return nil
+	}
+	return c.cells[row][column]
+}
+
+func (c *tableContent) GetRowCount() int {
+	if c.provider != nil {
+		return max(len(c.cells), c.provider.RowCount()+1)
+	}
+	return len(c.cells)
+}
+
+func (c *tableContent) GetColumnCount() int {
+	count := len(c.columns)
+	for _, row := range c.cells {
+		count = max(count, len(row))
+	}
+	return count
+}
+
+func (c *tableContent) SetCell(row, column int, cell *tview.TableCell) {
+	if row < 0 || column < 0 {
+		return
+	}
+	for len(c.cells) <= row {
+		c.cells = append(c.cells, nil)
+	}
+	for len(c.cells[row]) <= column {
+		c.cells[row] = append(c.cells[row], nil)
+	}
+	c.cells[row][column] = cell
+}
+
+func (c *tableContent) Clear() {
+	c.cells = nil
+	c.provider = nil
+	c.columns = nil
+	c.aligns = nil
+	c.rules = nil
+	c.isMarked = nil
+	c.lastValues = nil
 }
 
 func NewTable(title string, columns []Column, netColor tcell.Color, maxRows int) *Table {
+	for i := range columns {
+		columns[i].Key = columns[i].ConfigKey()
+	}
+
 	t := &Table{
 		Table:    tview.NewTable(),
 		title:    title,
 		columns:  columns,
 		netColor: netColor,
 		maxRows:  maxRows,
+		content:  &tableContent{},
 	}
 
-	t.SetFixed(1, 1).
+	t.SetContent(t.content).
+		SetFixed(1, 1).
 		SetSelectable(true, false).
 		SetBorder(true).
 		SetBorderPadding(0, 1, 1, 1)
 
+	theme := shared.CurrentTheme()
 	t.SetSelectedStyle(tcell.Style{}.
-		Background(tcell.ColorPurple).
-		Foreground(tcell.ColorWhite),
+		Background(theme.Selection).
+		Foreground(theme.SelectionText),
 	)
 
 	t.UpdateTitle(0, false)
@@ -66,25 +233,404 @@ func NewTable(title string, columns []Column, netColor tcell.Color, maxRows int)
 }
 
 func (t *Table) UpdateTitle(count int, hasMore bool) {
+	t.titleCount, t.titleMore = count, hasMore
 	strCount := fmt.Sprintf("%d", count)
 	if hasMore {
 		strCount = fmt.Sprintf("%d+", count)
 	}
 
-	t.SetTitle(fmt.Sprintf(" [::b][%s]%s [[%s]%s[%s]] ", t.netColor, strings.ToUpper(t.title), tcell.ColorWhiteSmoke, strCount, t.netColor))
+	title := fmt.Sprintf(" [::b][%s]%s [[%s]%s[%s]] ", t.netColor, strings.ToUpper(t.title), shared.CurrentTheme().Highlight, strCount, t.netColor)
+	if t.filterLabel != "" {
+		title += fmt.Sprintf("[%s::-]%s ", shared.CurrentTheme().Accent, tview.Escape(t.filterLabel))
+	}
+	t.SetTitle(title)
 }
 
-func (t *Table) DrawHeaders() {
+// SetFilterLabel names, next to the title, the filter the rows shown went
+// through. An empty label removes it.
+func (t *Table) SetFilterLabel(label string) *Table {
+	t.filterLabel = label
+	t.UpdateTitle(t.titleCount, t.titleMore)
+	return t
+}
+
+// Columns returns a copy of every column, hidden ones included.
+func (t *Table) Columns() []Column {
+	t.columnsMu.RLock()
+	defer t.columnsMu.RUnlock()
+	return append([]Column(nil), t.columns...)
+}
+
+// SetVisibleColumns shows the columns whose key is listed and hides the
+// others. Unknown keys are ignored; if no known key is listed every column is
+// shown. Rows passed to Update keep carrying a value for each column, hidden
+// or not, so callers do not have to care about the current selection.
+func (t *Table) SetVisibleColumns(keys []string) {
+	visible := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		visible[strings.ToLower(strings.TrimSpace(key))] = struct{}{}
+	}
+
+	t.columnsMu.Lock()
+	known := 0
+	for _, column := range t.columns {
+		if _, ok := visible[column.Key]; ok {
+			known++
+		}
+	}
+	for i := range t.columns {
+		_, ok := visible[t.columns[i].Key]
+		t.columns[i].Hidden = known > 0 && !ok
+	}
+	t.columnsMu.Unlock()
+
+	t.redraw()
+}
+
+// visibleColumns returns the indexes of the columns currently displayed.
+func (t *Table) visibleColumns() []int {
+	t.columnsMu.RLock()
+	defer t.columnsMu.RUnlock()
+
+	indexes := make([]int, 0, len(t.columns))
+	for cid, column := range t.columns {
+		if t.shown(column) {
+			indexes = append(indexes, cid)
+		}
+	}
+	return indexes
+}
+
+// shown reports whether column is drawn. Callers hold columnsMu.
+func (t *Table) shown(column Column) bool {
+	return !column.Hidden && !(t.compact && column.Collapsible)
+}
+
+// redraw renders the last rows or placeholder again.
+func (t *Table) redraw() {
+	switch {
+	case t.provider != nil:
+		t.showProvider(t.provider, false)
+	case t.placeholder != "":
+		t.ShowPlaceholder(t.placeholder)
+	default:
+		t.Clear()
+		t.DrawHeaders()
+	}
+}
+
+// Draw switches the table in and out of compact mode, where collapsible
+// columns are dropped and long cells are truncated, as its width crosses
+// CompactWidth.
+func (t *Table) Draw(screen tcell.Screen) {
+	_, _, width, _ := t.GetRect()
+	compact := width < CompactWidth
+
+	t.columnsMu.Lock()
+	changed := compact != t.compact
+	t.compact = compact
+	t.columnsMu.Unlock()
+
+	if changed {
+		t.redraw()
+	}
+	t.Table.Draw(screen)
+}
+
+// SetSortFunc registers the handler called when the user changes the sort
+// order, either with the column number keys or by clicking a header. The
+// table only tracks the order; the handler is expected to re-render the rows.
+func (t *Table) SetSortFunc(handler func(column int, dir SortOrder)) *Table {
+	t.sortFunc = handler
+	return t
+}
+
+// SetMenuFunc sets the handler called with the data row index, starting at
+// 0, when a row is right-clicked.
+func (t *Table) SetMenuFunc(handler func(row int)) *Table {
+	t.menuFunc = handler
+	return t
+}
+
+// SortColumn returns the column the rows are sorted by and its direction.
+func (t *Table) SortColumn() (int, SortOrder, bool) {
+	t.columnsMu.RLock()
+	defer t.columnsMu.RUnlock()
 
 	for cid, column := range t.columns {
-		header := fmt.Sprintf("[%s:-:b]%s", tcell.ColorGray, strings.ToUpper(column.Name))
+		if column.IsSorted {
+			return cid, column.SortDir, true
+		}
+	}
+	return -1, Ascending, false
+}
+
+// ToggleSort sorts by column, flipping the direction when it is already the
+// sorted column.
+func (t *Table) ToggleSort(column int) {
+	t.columnsMu.Lock()
+	if column < 0 || column >= len(t.columns) || !t.columns[column].Sortable || t.columns[column].Hidden {
+		t.columnsMu.Unlock()
+		return
+	}
+
+	dir := t.columns[column].SortDir
+	if t.columns[column].IsSorted {
+		if dir == Ascending {
+			dir = Descending
+		} else {
+			dir = Ascending
+		}
+	}
+
+	for cid := range t.columns {
+		t.columns[cid].IsSorted = cid == column
+	}
+	t.columns[column].SortDir = dir
+	t.columnsMu.Unlock()
+
+	t.DrawHeaders()
+
+	if t.sortFunc != nil {
+		t.sortFunc(column, dir)
+	}
+}
+
+func (t *Table) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	handler := t.Table.InputHandler()
+	return func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		if t.handlePaging(event.Key()) {
+			return
+		}
+		if t.multiSelect {
+			switch {
+			case event.Key() == tcell.KeyRune && event.Rune() == ' ':
+				if row, _ := t.GetSelection(); row > 0 {
+					t.ToggleMark(row - 1)
+				}
+				return
+			case event.Key() == tcell.KeyRune && event.Rune() == 'a':
+				t.MarkAll(len(t.MarkedRows()) < t.RowCount())
+				return
+			}
+		}
+		if event.Key() == tcell.KeyRune && event.Rune() >= '1' && event.Rune() <= '9' {
+			if visible := t.visibleColumns(); int(event.Rune()-'1') < len(visible) {
+				column := visible[event.Rune()-'1']
+				if t.isSortable(column) {
+					t.ToggleSort(column)
+					return
+				}
+			}
+		}
+		handler(event, setFocus)
+	}
+}
+
+func (t *Table) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (bool, tview.Primitive) {
+	handler := t.Table.MouseHandler()
+	return func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (bool, tview.Primitive) {
+		if action == tview.MouseLeftClick && t.InRect(event.Position()) {
+			if row, column := t.CellAt(event.Position()); row == 0 && column >= 0 {
+				if visible := t.visibleColumns(); column < len(visible) && t.isSortable(visible[column]) {
+					setFocus(t)
+					t.ToggleSort(visible[column])
+					return true, nil
+				}
+			}
+		}
+		if action == tview.MouseRightClick && t.menuFunc != nil && t.InRect(event.Position()) {
+			if row, _ := t.CellAt(event.Position()); row > 0 && row <= t.RowCount() {
+				setFocus(t)
+				t.Select(row, 0)
+				t.menuFunc(row - 1)
+				return true, nil
+			}
+		}
+		return handler(action, event, setFocus)
+	}
+}
+
+// handlePaging moves the selection a page at a time with PgUp/PgDn and to
+// the first or last row with Home/End, skipping the header.
+func (t *Table) handlePaging(key tcell.Key) bool {
+	count := t.RowCount()
+	if count == 0 {
+		return false
+	}
+
+	row, column := t.GetSelection()
+	_, _, _, height := t.GetInnerRect()
+	page := max(height-1, 1)
+
+	switch key {
+	case tcell.KeyPgDn:
+		row += page
+	case tcell.KeyPgUp:
+		row -= page
+	case tcell.KeyHome:
+		row = 1
+	case tcell.KeyEnd:
+		row = count
+	default:
+		return false
+	}
+	t.Select(min(max(row, 1), count), column)
+	return true
+}
+
+// SetMultiSelect turns checkbox-style marking of rows on or off: Space marks
+// or unmarks the selected row and 'a' marks all rows, or none once all are
+// marked.
+func (t *Table) SetMultiSelect(enabled bool) *Table {
+	t.multiSelect = enabled
+	if !enabled {
+		t.marked = nil
+	}
+	t.redraw()
+	return t
+}
+
+// MultiSelect reports whether rows can be marked, see SetMultiSelect.
+func (t *Table) MultiSelect() bool {
+	return t.multiSelect
+}
+
+// SetMarkedFunc sets the handler called with the marked data rows, starting
+// at 0, whenever they change.
+func (t *Table) SetMarkedFunc(handler func(rows []int)) *Table {
+	t.markedFunc = handler
+	return t
+}
+
+// ToggleMark marks the data row, starting at 0, or unmarks it.
+func (t *Table) ToggleMark(row int) {
+	if !t.multiSelect || row < 0 || row >= t.RowCount() {
+		return
+	}
+	key := t.rowKey(row)
+	if _, ok := t.marked[key]; ok {
+		delete(t.marked, key)
+	} else {
+		if t.marked == nil {
+			t.marked = make(map[string]struct{})
+		}
+		t.marked[key] = struct{}{}
+	}
+	t.notifyMarked()
+}
+
+// MarkAll marks every row, or none.
+func (t *Table) MarkAll(mark bool) {
+	if !t.multiSelect {
+		return
+	}
+	t.marked = nil
+	if mark {
+		count := t.RowCount()
+		t.marked = make(map[string]struct{}, count)
+		for row := range count {
+			t.marked[t.rowKey(row)] = struct{}{}
+		}
+	}
+	t.notifyMarked()
+}
+
+// MarkedRows returns the marked data rows, starting at 0, in order.
+func (t *Table) MarkedRows() []int {
+	if len(t.marked) == 0 {
+		return nil
+	}
+	var rows []int
+	for row := range t.RowCount() {
+		if t.isMarked(row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// pruneMarks drops the marks of the rows the provider no longer has, and
+// reports whether there were some.
+func (t *Table) pruneMarks() bool {
+	if len(t.marked) == 0 {
+		return false
+	}
+	present := make(map[string]struct{}, len(t.marked))
+	for row := range t.RowCount() {
+		key := t.rowKey(row)
+		if _, ok := t.marked[key]; ok {
+			present[key] = struct{}{}
+		}
+	}
+	if len(present) == len(t.marked) {
+		return false
+	}
+	t.marked = present
+	return true
+}
+
+func (t *Table) isMarked(row int) bool {
+	_, ok := t.marked[t.rowKey(row)]
+	return ok
+}
+
+func (t *Table) rowKey(row int) string {
+	if keyer, ok := t.provider.(RowKeyer); ok {
+		return keyer.RowKey(row)
+	}
+	return strconv.Itoa(row)
+}
+
+func (t *Table) notifyMarked() {
+	if t.markedFunc != nil {
+		t.markedFunc(t.MarkedRows())
+	}
+}
+
+func checkbox(marked bool) string {
+	if marked {
+		return "☑ "
+	}
+	return "☐ "
+}
+
+// RowCount returns the number of data rows, 0 while a placeholder is shown.
+func (t *Table) RowCount() int {
+	if t.provider == nil {
+		return 0
+	}
+	return t.provider.RowCount()
+}
+
+func (t *Table) isSortable(column int) bool {
+	t.columnsMu.RLock()
+	defer t.columnsMu.RUnlock()
+	return column >= 0 && column < len(t.columns) && t.columns[column].Sortable
+}
+
+func (t *Table) DrawHeaders() {
+
+	t.columnsMu.RLock()
+	defer t.columnsMu.RUnlock()
+
+	theme := shared.CurrentTheme()
+	cid := 0
+	for _, column := range t.columns {
+		if !t.shown(column) {
+			continue
+		}
+		header := fmt.Sprintf("[%s:-:b]%s", theme.Muted, strings.ToUpper(column.Name))
+		if t.multiSelect && cid == 0 {
+			header = "  " + header
+		}
 		if column.IsSorted {
 			switch column.SortDir {
 			case Ascending:
-				header += fmt.Sprintf("[%s:-:-]↑", tcell.ColorPurple)
+				header += fmt.Sprintf("[%s:-:-]↑", theme.Selection)
 
 			case Descending:
-				header += fmt.Sprintf("[%s:-:-]↓", tcell.ColorPurple)
+				header += fmt.Sprintf("[%s:-:-]↓", theme.Selection)
 			}
 		}
 		t.SetCell(0, cid,
@@ -93,26 +639,68 @@ func (t *Table) DrawHeaders() {
 				SetTextColor(tcell.ColorBlack).
 				SetAlign(column.Align).
 				SetSelectable(false))
+		cid++
 	}
 
 }
 
+// Update shows rows held in memory, see SetProvider.
 func (t *Table) Update(rows [][]string) {
 	if rows == nil {
 		return
 	}
+	t.SetProvider(Rows(rows))
+}
+
+// SetProvider shows the rows of provider. Cells are built as rows are drawn;
+// call it again when the rows change.
+func (t *Table) SetProvider(provider RowProvider) {
+	t.showProvider(provider, true)
+}
+
+// showProvider draws the rows of provider, fresh unless they are the rows
+// already shown, drawn again.
+func (t *Table) showProvider(provider RowProvider, fresh bool) {
+	if provider == nil {
+		return
+	}
 
 	t.Clear()
+	t.provider = provider
+	t.placeholder = ""
+
+	if _, keyed := provider.(RowKeyer); !keyed && fresh && len(t.marked) > 0 {
+		t.marked = nil
+		t.notifyMarked()
+	} else if t.pruneMarks() {
+		t.notifyMarked()
+	}
 
-	t.UpdateTitle(len(rows), false)
+	t.UpdateTitle(provider.RowCount(), false)
 	t.DrawHeaders()
 
-	for rid, row := range rows {
-		for cid, column := range row {
-			t.SetCell(rid+1, cid, tview.NewTableCell(column).
-				SetExpansion(1).
-				SetAlign(t.columns[cid].Align))
-		}
+	maxWidth := 0
+	if t.compact {
+		maxWidth = compactCellWidth
+	}
+
+	visible := t.visibleColumns()
+	aligns := make([]int, len(visible))
+	rules := make([][]cellRule, len(visible))
+	t.columnsMu.RLock()
+	for cid, column := range visible {
+		aligns[cid] = t.columns[column].Align
+		rules[cid] = t.rules[t.columns[column].Key]
+	}
+	t.columnsMu.RUnlock()
+
+	t.content.provider = provider
+	t.content.columns = visible
+	t.content.aligns = aligns
+	t.content.rules = rules
+	t.content.maxWidth = maxWidth
+	if t.multiSelect {
+		t.content.isMarked = t.isMarked
 	}
 
 	t.scrollOnce.Do(func() {
@@ -121,11 +709,14 @@ func (t *Table) Update(rows [][]string) {
 }
 
 func (t *Table) ShowPlaceholder(message string) {
-	if len(t.columns) == 0 {
+	columns := len(t.visibleColumns())
+	if columns == 0 {
 		return
 	}
 
 	t.Clear()
+	t.provider = nil
+	t.placeholder = message
 
 	t.UpdateTitle(0, false)
 	t.DrawHeaders()
@@ -165,13 +756,13 @@ func (t *Table) ShowPlaceholder(message string) {
 	}
 
 	for row := 1; row < placeholderRow; row++ {
-		for cid := range t.columns {
+		for cid := range columns {
 			t.SetCell(row, cid, blanks())
 		}
 	}
 
-	centerCol := len(t.columns) / 2
-	for cid := range t.columns {
+	centerCol := columns / 2
+	for cid := range columns {
 		cell := blanks()
 		if cid == centerCol {
 			cell = tview.NewTableCell(placeholderText).
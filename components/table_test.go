@@ -0,0 +1,122 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// keyedRows keys each row by its first value.
+type keyedRows [][]string
+
+func (r keyedRows) RowCount() int { return len(r) }
+
+func (r keyedRows) GetRow(row int) []string { return r[row] }
+
+func (r keyedRows) RowKey(row int) string { return r[row][0] }
+
+func newMarkTable(t *testing.T) *Table {
+	t.Helper()
+	table := NewTable("Test", []Column{{Name: "Key"}}, tcell.ColorWhite, 0)
+	table.SetMultiSelect(true)
+	return table
+}
+
+func pressRune(table *Table, r rune) {
+	table.InputHandler()(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone), func(tview.Primitive) {})
+}
+
+func TestTableMarks(t *testing.T) {
+	table := newMarkTable(t)
+	var notified []int
+	table.SetMarkedFunc(func(rows []int) { notified = rows })
+	table.SetProvider(keyedRows{{"a"}, {"b"}, {"c"}})
+
+	table.ToggleMark(0)
+	table.ToggleMark(2)
+	if got, want := table.MarkedRows(), []int{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("MarkedRows = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(notified, []int{0, 2}) {
+		t.Fatalf("marked func got %v, want [0 2]", notified)
+	}
+
+	table.ToggleMark(0)
+	if got, want := table.MarkedRows(), []int{2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("MarkedRows after unmarking = %v, want %v", got, want)
+	}
+
+	table.MarkAll(true)
+	if got, want := table.MarkedRows(), []int{0, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("MarkedRows after MarkAll = %v, want %v", got, want)
+	}
+	table.MarkAll(false)
+	if got := table.MarkedRows(); got != nil {
+		t.Fatalf("MarkedRows after MarkAll(false) = %v, want none", got)
+	}
+
+	table.SetMultiSelect(false)
+	table.ToggleMark(1)
+	if got := table.MarkedRows(); got != nil {
+		t.Fatalf("MarkedRows without multi-select = %v, want none", got)
+	}
+}
+
+func TestTableKeyedMarksFollowRows(t *testing.T) {
+	table := newMarkTable(t)
+	table.SetProvider(keyedRows{{"a"}, {"b"}, {"c"}})
+	table.ToggleMark(0)
+
+	table.SetProvider(keyedRows{{"c"}, {"b"}, {"a"}})
+	if got, want := table.MarkedRows(), []int{2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("MarkedRows after a re-sort = %v, want %v", got, want)
+	}
+}
+
+func TestTablePrunesStaleMarks(t *testing.T) {
+	table := newMarkTable(t)
+	var notified []int
+	table.SetMarkedFunc(func(rows []int) { notified = rows })
+	table.SetProvider(keyedRows{{"a"}, {"b"}, {"c"}})
+	table.ToggleMark(0)
+	table.ToggleMark(1)
+
+	// "a" and "b" are gone, "c" and "d" came.
+	table.SetProvider(keyedRows{{"c"}, {"d"}})
+	if got := table.MarkedRows(); got != nil {
+		t.Fatalf("MarkedRows after the marked rows left = %v, want none", got)
+	}
+	if notified != nil {
+		t.Fatalf("marked func got %v, want none", notified)
+	}
+
+	// With no row marked, 'a' marks them all, then none.
+	pressRune(table, 'a')
+	if got, want := table.MarkedRows(), []int{0, 1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("MarkedRows after 'a' = %v, want %v", got, want)
+	}
+	pressRune(table, 'a')
+	if got := table.MarkedRows(); got != nil {
+		t.Fatalf("MarkedRows after 'a' again = %v, want none", got)
+	}
+}
+
+func TestTablePositionMarks(t *testing.T) {
+	table := newMarkTable(t)
+	table.Update([][]string{{"a"}, {"b"}})
+	table.ToggleMark(1)
+
+	// Drawn again, the same rows keep their marks.
+	table.redraw()
+	if got, want := table.MarkedRows(), []int{1}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("MarkedRows after a redraw = %v, want %v", got, want)
+	}
+
+	// New rows without keys clear them.
+	table.Update([][]string{{"b"}, {"a"}})
+	if got := table.MarkedRows(); got != nil {
+		t.Fatalf("MarkedRows after new rows = %v, want none", got)
+	}
+}
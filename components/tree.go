@@ -0,0 +1,189 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"fmt"
+
+	"github.com/flokiorg/go-flokicoin/chainutil"
+	"github.com/flokiorg/twallet/shared"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// TreeItem is a node of a Tree. Items with children are groups: they show
+// the total of their children and collapse or expand with Enter.
+type TreeItem struct {
+	Label     string
+	Amount    chainutil.Amount
+	Children  []*TreeItem
+	Reference any
+}
+
+// Total returns the amount of a leaf, or the sum of the leaves under a
+// group.
+func (i *TreeItem) Total() chainutil.Amount {
+	if len(i.Children) == 0 {
+		return i.Amount
+	}
+	var total chainutil.Amount
+	for _, child := range i.Children {
+		total += child.Total()
+	}
+	return total
+}
+
+// leaves counts the leaves under the item.
+func (i *TreeItem) leaves() int {
+	if len(i.Children) == 0 {
+		return 1
+	}
+	n := 0
+	for _, child := range i.Children {
+		n += child.leaves()
+	}
+	return n
+}
+
+// Tree shows items grouped under collapsible nodes, each group with the
+// aggregate amount of what it holds.
+type Tree struct {
+	*tview.TreeView
+	root *tview.TreeNode
+
+	// expanded remembers the groups the user opened, by label, so they stay
+	// open when the items are set again.
+	expanded     map[string]bool
+	placeholder  string
+	selectedFunc func(item *TreeItem)
+}
+
+func NewTree() *Tree {
+	t := &Tree{
+		TreeView: tview.NewTreeView(),
+		root:     tview.NewTreeNode(""),
+		expanded: make(map[string]bool),
+	}
+	t.SetRoot(t.root).
+		SetTopLevel(1).
+		SetGraphicsColor(shared.CurrentTheme().Muted)
+
+	t.TreeView.SetSelectedFunc(func(node *tview.TreeNode) {
+		item, _ := node.GetReference().(*TreeItem)
+		if item == nil {
+			return
+		}
+		if len(item.Children) > 0 {
+			t.setExpanded(node, item, !node.IsExpanded())
+			return
+		}
+		if t.selectedFunc != nil {
+			t.selectedFunc(item)
+		}
+	})
+	t.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		switch event.Rune() {
+		case '+':
+			t.ExpandAll()
+			return nil
+		case '-':
+			t.CollapseAll()
+			return nil
+		}
+		return event
+	})
+	return t
+}
+
+// SetSelectedFunc sets the handler run when Enter is pressed on a leaf.
+func (t *Tree) SetSelectedFunc(handler func(item *TreeItem)) *Tree {
+	t.selectedFunc = handler
+	return t
+}
+
+// SetPlaceholder sets the text shown while there are no items.
+func (t *Tree) SetPlaceholder(text string) *Tree {
+	t.placeholder = text
+	return t
+}
+
+// SetItems replaces the items shown. Groups start collapsed unless they were
+// open before.
+func (t *Tree) SetItems(items []*TreeItem) *Tree {
+	t.root.ClearChildren()
+	if len(items) == 0 && t.placeholder != "" {
+		t.root.AddChild(tview.NewTreeNode(t.placeholder).
+			SetColor(shared.CurrentTheme().Muted).
+			SetSelectable(false))
+	}
+	for _, item := range items {
+		t.root.AddChild(t.node(item))
+	}
+	if children := t.root.GetChildren(); len(children) > 0 {
+		t.SetCurrentNode(children[0])
+	}
+	return t
+}
+
+func (t *Tree) node(item *TreeItem) *tview.TreeNode {
+	node := tview.NewTreeNode("").SetReference(item).SetSelectable(true)
+	if len(item.Children) == 0 {
+		node.SetText(fmt.Sprintf("%s  [%s]%s", tview.Escape(item.Label), shared.CurrentTheme().Accent, shared.FormatAmountView(item.Amount, 6)))
+		return node
+	}
+	for _, child := range item.Children {
+		node.AddChild(t.node(child))
+	}
+	t.setExpanded(node, item, t.expanded[item.Label])
+	return node
+}
+
+// setExpanded opens or closes a group and redraws its marker.
+func (t *Tree) setExpanded(node *tview.TreeNode, item *TreeItem, expanded bool) {
+	marker := "▸"
+	if expanded {
+		marker = "▾"
+	}
+	theme := shared.CurrentTheme()
+	node.SetExpanded(expanded).
+		SetText(fmt.Sprintf("%s %s  [%s](%d)  [%s::b]%s", marker, tview.Escape(item.Label), theme.Muted, item.leaves(), theme.Accent, shared.FormatAmountView(item.Total(), 6)))
+	if expanded {
+		t.expanded[item.Label] = true
+	} else {
+		delete(t.expanded, item.Label)
+	}
+}
+
+// ExpandAll opens every group.
+func (t *Tree) ExpandAll() {
+	t.walkGroups(true)
+}
+
+// CollapseAll closes every group.
+func (t *Tree) CollapseAll() {
+	t.walkGroups(false)
+	if current := t.GetCurrentNode(); current != nil {
+		// Keep the selection visible on the group of the leaf it was on.
+		for _, group := range t.root.GetChildren() {
+			for _, child := range group.GetChildren() {
+				if child == current {
+					t.SetCurrentNode(group)
+				}
+			}
+		}
+	}
+}
+
+func (t *Tree) walkGroups(expanded bool) {
+	t.root.Walk(func(node, _ *tview.TreeNode) bool {
+		if item, _ := node.GetReference().(*TreeItem); item != nil && len(item.Children) > 0 {
+			t.setExpanded(node, item, expanded)
+		}
+		return true
+	})
+}
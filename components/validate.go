@@ -0,0 +1,256 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/flokiorg/go-flokicoin/chaincfg"
+	"github.com/flokiorg/go-flokicoin/chainutil"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/shared"
+)
+
+// Validator checks the text of a form field and returns why it is not valid.
+type Validator func(text string) error
+
+// FormValidator checks form fields against their validators and shows the
+// first error of each field on the line under it.
+type FormValidator struct {
+	fields []*ValidatedField
+}
+
+func NewFormValidator() *FormValidator {
+	return &FormValidator{}
+}
+
+// Field wraps item so that its errors are shown under it. The returned item
+// is the one to add to the form; item itself keeps being read and focused
+// as before.
+func (v *FormValidator) Field(item tview.FormItem, validators ...Validator) *ValidatedField {
+	field := &ValidatedField{FormItem: item, validators: validators}
+	v.fields = append(v.fields, field)
+	return field
+}
+
+// Validate checks every field and shows their errors. It returns the first
+// invalid item, to focus, and its error.
+func (v *FormValidator) Validate() (tview.FormItem, error) {
+	var (
+		first    tview.FormItem
+		firstErr error
+	)
+	for _, field := range v.fields {
+		if err := field.Validate(); err != nil && firstErr == nil {
+			first, firstErr = field.FormItem, err
+		}
+	}
+	return first, firstErr
+}
+
+// Reset hides the errors shown.
+func (v *FormValidator) Reset() {
+	for _, field := range v.fields {
+		field.err = nil
+	}
+}
+
+// ValidatedField is a form item with its validators. Once an error is shown,
+// the field is checked again as it is edited so the error goes away as soon
+// as it is fixed.
+type ValidatedField struct {
+	tview.FormItem
+	validators []Validator
+	err        error
+
+	labelWidth int
+	x, y, w, h int
+	hasRect    bool
+}
+
+// Unwrap returns the item a ValidatedField wraps, or item itself, so forms
+// can keep reading their items back with GetFormItem.
+func Unwrap(item tview.FormItem) tview.FormItem {
+	if field, ok := item.(*ValidatedField); ok {
+		return field.FormItem
+	}
+	return item
+}
+
+// Validate checks the field and shows its first error, if any.
+func (f *ValidatedField) Validate() error {
+	f.err = nil
+	text := fieldText(f.FormItem)
+	for _, validate := range f.validators {
+		if err := validate(text); err != nil {
+			f.err = err
+			break
+		}
+	}
+	return f.err
+}
+
+func (f *ValidatedField) GetFieldHeight() int {
+	if f.err != nil {
+		return f.FormItem.GetFieldHeight() + 1
+	}
+	return f.FormItem.GetFieldHeight()
+}
+
+func (f *ValidatedField) SetFormAttributes(labelWidth int, labelColor, bgColor, fieldTextColor, fieldBgColor tcell.Color) tview.FormItem {
+	f.labelWidth = labelWidth
+	f.FormItem.SetFormAttributes(labelWidth, labelColor, bgColor, fieldTextColor, fieldBgColor)
+	return f
+}
+
+func (f *ValidatedField) SetFinishedFunc(handler func(key tcell.Key)) tview.FormItem {
+	f.FormItem.SetFinishedFunc(handler)
+	return f
+}
+
+func (f *ValidatedField) SetDisabled(disabled bool) tview.FormItem {
+	f.FormItem.SetDisabled(disabled)
+	return f
+}
+
+func (f *ValidatedField) SetRect(x, y, width, height int) {
+	f.x, f.y, f.w, f.h = x, y, width, height
+	f.hasRect = true
+	if f.err != nil {
+		height--
+	}
+	f.FormItem.SetRect(x, y, width, height)
+}
+
+func (f *ValidatedField) Draw(screen tcell.Screen) {
+	f.FormItem.Draw(screen)
+	if f.err == nil || !f.hasRect {
+		return
+	}
+
+	labelWidth := f.labelWidth
+	if labelWidth <= 0 {
+		labelWidth = tview.TaggedStringWidth(f.GetLabel())
+	}
+	x := f.x + min(labelWidth, f.w)
+	text := tview.Escape(f.err.Error())
+	tview.Print(screen, text, x, f.y+f.h-1, f.w-(x-f.x), tview.AlignLeft, shared.CurrentTheme().Error)
+}
+
+func (f *ValidatedField) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	handler := f.FormItem.InputHandler()
+	return func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		if handler != nil {
+			handler(event, setFocus)
+		}
+		if f.err != nil {
+			f.Validate()
+		}
+	}
+}
+
+// MouseHandler focuses the field rather than the item it wraps, so that its
+// edits keep going through InputHandler.
+func (f *ValidatedField) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (bool, tview.Primitive) {
+	handler := f.FormItem.MouseHandler()
+	return func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (bool, tview.Primitive) {
+		if handler == nil {
+			return false, nil
+		}
+		return handler(action, event, func(p tview.Primitive) {
+			if p == f.FormItem {
+				p = f
+			}
+			setFocus(p)
+		})
+	}
+}
+
+func fieldText(item tview.FormItem) string {
+	switch item := item.(type) {
+	case *tview.InputField:
+		return item.GetText()
+	case *AmountField:
+		return item.GetText()
+	case *tview.TextArea:
+		return item.GetText()
+	case *tview.DropDown:
+		_, text := item.GetCurrentOption()
+		return text
+	}
+	return ""
+}
+
+// Required rejects an empty or blank field.
+func Required(message string) Validator {
+	return func(text string) error {
+		if strings.TrimSpace(text) == "" {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// PasswordPolicy rejects passwords shorter than shared.MinPasswordLength.
+func PasswordPolicy() Validator {
+	return func(text string) error {
+		if len(text) < shared.MinPasswordLength {
+			return fmt.Errorf("password must be at least %d characters", shared.MinPasswordLength)
+		}
+		return nil
+	}
+}
+
+// Matches rejects a field that differs from the text returned by other, such
+// as a password confirmation.
+func Matches(other func() string, message string) Validator {
+	return func(text string) error {
+		if text != other() {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// AddressForNetwork rejects text that is not an address of the network.
+func AddressForNetwork(params *chaincfg.Params) Validator {
+	return func(text string) error {
+		address, err := chainutil.DecodeAddress(strings.TrimSpace(text), params)
+		if err != nil || !address.IsForNet(params) {
+			return errors.New("invalid address")
+		}
+		return nil
+	}
+}
+
+// AmountRange rejects text that is not a positive amount in the current
+// denomination between minimum and maximum, a zero maximum leaving it
+// unbounded.
+func AmountRange(minimum, maximum chainutil.Amount) Validator {
+	return func(text string) error {
+		amount, err := shared.ParseAmount(text)
+		if err != nil {
+			return err
+		}
+		return checkAmountRange(amount, minimum, maximum)
+	}
+}
+
+func checkAmountRange(amount, minimum, maximum chainutil.Amount) error {
+	if amount <= 0 {
+		return errors.New("invalid amount")
+	}
+	if amount < minimum {
+		return fmt.Errorf("amount must be at least %s", shared.FormatAmountView(minimum, 8))
+	}
+	if maximum > 0 && amount > maximum {
+		return fmt.Errorf("amount must be at most %s", shared.FormatAmountView(maximum, 8))
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/flokiorg/twallet/i18n"
+	"github.com/flokiorg/twallet/shared"
+	"github.com/rivo/tview"
+)
+
+// WizardStep is one page of a Wizard.
+type WizardStep struct {
+	Title   string
+	Content tview.Primitive
+
+	// Validate, when set, is checked before moving past the step. An error
+	// keeps the wizard on the step and is passed to the error func.
+	Validate func() error
+
+	// NoBack keeps the user from returning to the previous step, once the
+	// steps before took effect, a created wallet for instance.
+	NoBack bool
+}
+
+// Wizard walks the user through steps in order, under a progress indicator
+// naming them. Steps move on with Next and Back, either from the buttons
+// Navigation adds to their form or from their own actions when these
+// complete asynchronously.
+type Wizard struct {
+	*tview.Flex
+	app      *tview.Application
+	steps    []WizardStep
+	current  int
+	progress *tview.TextView
+	pages    *tview.Pages
+
+	doneFunc   func()
+	cancelFunc func()
+	errorFunc  func(error)
+}
+
+func NewWizard(app *tview.Application, steps ...WizardStep) *Wizard {
+	w := &Wizard{
+		Flex:  tview.NewFlex().SetDirection(tview.FlexRow),
+		app:   app,
+		steps: steps,
+		progress: tview.NewTextView().
+			SetDynamicColors(true).
+			SetTextAlign(tview.AlignCenter),
+		pages: tview.NewPages(),
+	}
+	for i, step := range steps {
+		w.pages.AddPage(strconv.Itoa(i), stepContent(step.Content), true, i == 0)
+	}
+
+	w.AddItem(w.progress, 1, 0, false).
+		AddItem(nil, 1, 0, false).
+		AddItem(w.pages, 0, 1, true)
+	w.drawProgress()
+	return w
+}
+
+func stepContent(content tview.Primitive) tview.Primitive {
+	if content == nil {
+		return tview.NewBox()
+	}
+	return content
+}
+
+// SetDoneFunc sets the handler run when Next is called on the last step.
+func (w *Wizard) SetDoneFunc(handler func()) *Wizard {
+	w.doneFunc = handler
+	return w
+}
+
+// SetCancelFunc sets the handler run when Back is called on the first step.
+func (w *Wizard) SetCancelFunc(handler func()) *Wizard {
+	w.cancelFunc = handler
+	return w
+}
+
+// SetErrorFunc sets the handler told why a step did not validate.
+func (w *Wizard) SetErrorFunc(handler func(err error)) *Wizard {
+	w.errorFunc = handler
+	return w
+}
+
+// SetStepContent replaces the content of a step, for steps built from what
+// the steps before produced.
+func (w *Wizard) SetStepContent(index int, content tview.Primitive) *Wizard {
+	if index < 0 || index >= len(w.steps) {
+		return w
+	}
+	w.steps[index].Content = content
+	name := strconv.Itoa(index)
+	w.pages.RemovePage(name).AddPage(name, stepContent(content), true, index == w.current)
+	if index == w.current {
+		w.show(index)
+	}
+	return w
+}
+
+// SetStepValidator replaces the validation of a step, for fields built along
+// with its content.
+func (w *Wizard) SetStepValidator(index int, validate func() error) *Wizard {
+	if index >= 0 && index < len(w.steps) {
+		w.steps[index].Validate = validate
+	}
+	return w
+}
+
+// Current returns the index of the step shown.
+func (w *Wizard) Current() int {
+	return w.current
+}
+
+// Next validates the current step and moves to the following one, or runs
+// the done func on the last step.
+func (w *Wizard) Next() {
+	if validate := w.steps[w.current].Validate; validate != nil {
+		if err := validate(); err != nil {
+			if w.errorFunc != nil {
+				w.errorFunc(err)
+			}
+			return
+		}
+	}
+	if w.current == len(w.steps)-1 {
+		if w.doneFunc != nil {
+			w.doneFunc()
+		}
+		return
+	}
+	w.show(w.current + 1)
+}
+
+// Back returns to the previous step, or runs the cancel func on the first
+// step. Steps marked NoBack stay where they are.
+func (w *Wizard) Back() {
+	if w.steps[w.current].NoBack {
+		return
+	}
+	if w.current == 0 {
+		if w.cancelFunc != nil {
+			w.cancelFunc()
+		}
+		return
+	}
+	w.show(w.current - 1)
+}
+
+// Navigation adds the Back and next buttons to form, and makes Esc go back.
+// The next button reads nextLabel, and Back is left out of NoBack steps.
+func (w *Wizard) Navigation(form *tview.Form, index int, nextLabel string) *tview.Form {
+	if index > 0 && !w.steps[index].NoBack {
+		form.AddButton(i18n.T("wizard.back"), w.Back)
+	}
+	form.AddButton(nextLabel, w.Next)
+	form.SetCancelFunc(w.Back)
+	return form
+}
+
+func (w *Wizard) show(index int) {
+	w.current = index
+	w.pages.SwitchToPage(strconv.Itoa(index))
+	w.drawProgress()
+	if w.app != nil {
+		w.app.SetFocus(w.pages)
+	}
+}
+
+// drawProgress names the steps, the done ones in the success color and the
+// current one highlighted.
+func (w *Wizard) drawProgress() {
+	theme := shared.CurrentTheme()
+	parts := make([]string, 0, len(w.steps))
+	for i, step := range w.steps {
+		label := fmt.Sprintf("%d %s", i+1, tview.Escape(step.Title))
+		switch {
+		case i < w.current:
+			parts = append(parts, fmt.Sprintf("[%s]✓ %s[-]", theme.Success, label))
+		case i == w.current:
+			parts = append(parts, fmt.Sprintf("[%s::b]● %s[-::-]", theme.Primary, label))
+		default:
+			parts = append(parts, fmt.Sprintf("[%s]○ %s[-]", theme.Muted, label))
+		}
+	}
+	w.progress.SetText(strings.Join(parts, fmt.Sprintf(" [%s]──[-] ", theme.Muted)))
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+
+	"github.com/flokiorg/go-flokicoin/chainutil"
+)
+
+// appName names the twallet directory under each XDG base directory.
+const appName = "twallet"
+
+// migrationDeclined is left in the legacy directory when its move to the
+// XDG layout is declined, so it is not offered again.
+const migrationDeclined = ".xdg-declined"
+
+// Dirs are where twallet keeps its files unless told otherwise.
+type Dirs struct {
+	// Config holds twallet.conf.
+	Config string
+	// Data is the wallet directory: the wallet database and the daemon data.
+	Data string
+	// State holds twallet.log.
+	State string
+}
+
+// DefaultDirs follows the XDG base directory spec on Linux. Elsewhere the
+// config file is looked up in the working directory and the logs are kept
+// in the legacy wallet directory, as before.
+func DefaultDirs() Dirs {
+	home, _ := os.UserHomeDir()
+	cwd, _ := os.Getwd()
+	return defaultDirs(runtime.GOOS, os.Getenv, home, cwd)
+}
+
+func defaultDirs(goos string, getenv func(string) string, home, cwd string) Dirs {
+	if goos != "linux" {
+		legacy := LegacyDataDir()
+		return Dirs{Config: cwd, Data: legacy, State: legacy}
+	}
+	base := func(env string, fallback ...string) string {
+		// The spec has relative paths ignored.
+		if dir := getenv(env); filepath.IsAbs(dir) {
+			return filepath.Join(dir, appName)
+		}
+		return filepath.Join(append([]string{home}, append(fallback, appName)...)...)
+	}
+	return Dirs{
+		Config: base("XDG_CONFIG_HOME", ".config"),
+		Data:   base("XDG_DATA_HOME", ".local", "share"),
+		State:  base("XDG_STATE_HOME", ".local", "state"),
+	}
+}
+
+// LegacyDataDir is the single directory twallet kept everything in before
+// the XDG layout.
+func LegacyDataDir() string {
+	return chainutil.AppDataDir("flnd", false)
+}
+
+// NeedsMigration reports whether the wallet is still in the legacy
+// directory while data, its XDG place, does not exist yet, and the move was
+// not declined before.
+func NeedsMigration(legacy, data string) bool {
+	if legacy == data || exists(data) || exists(filepath.Join(legacy, migrationDeclined)) {
+		return false
+	}
+	entries, err := os.ReadDir(legacy)
+	return err == nil && len(entries) > 0
+}
+
+// DeclineMigration keeps the wallet in legacy and stops NeedsMigration
+// from offering the move again.
+func DeclineMigration(legacy string) error {
+	return os.WriteFile(filepath.Join(legacy, migrationDeclined), nil, 0o600)
+}
+
+// MigrateDataDir moves the legacy directory to data and the twallet logs
+// found in it to state.
+func MigrateDataDir(legacy, data, state string) error {
+	if err := os.MkdirAll(filepath.Dir(data), 0o700); err != nil {
+		return err
+	}
+	if err := os.Rename(legacy, data); err != nil {
+		if errors.Is(err, syscall.EXDEV) {
+			return fmt.Errorf("%s and %s are on different filesystems, move it by hand: %w", legacy, data, err)
+		}
+		return err
+	}
+
+	// The daemon logs stay with its data, only the twallet ones move.
+	if err := os.MkdirAll(state, 0o700); err != nil {
+		return err
+	}
+	names := []string{"twallet.log", "crash.log"}
+	if rotated, err := filepath.Glob(filepath.Join(data, "twallet.log.*")); err == nil {
+		for _, path := range rotated {
+			names = append(names, filepath.Base(path))
+		}
+	}
+	for _, name := range names {
+		from := filepath.Join(data, name)
+		if !exists(from) {
+			continue
+		}
+		if err := os.Rename(from, filepath.Join(state, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultDirs(t *testing.T) {
+	env := map[string]string{"XDG_DATA_HOME": "/data", "XDG_STATE_HOME": "relative"}
+	dirs := defaultDirs("linux", func(key string) string { return env[key] }, "/home/u", "/work")
+	want := Dirs{
+		Config: "/home/u/.config/twallet",
+		Data:   "/data/twallet",
+		State:  "/home/u/.local/state/twallet",
+	}
+	if dirs != want {
+		t.Fatalf("unexpected dirs %+v, want %+v", dirs, want)
+	}
+
+	dirs = defaultDirs("darwin", func(string) string { return "" }, "/home/u", "/work")
+	if dirs.Config != "/work" || dirs.Data != LegacyDataDir() || dirs.State != dirs.Data {
+		t.Fatalf("unexpected legacy dirs %+v", dirs)
+	}
+}
+
+func TestMigrateDataDir(t *testing.T) {
+	root := t.TempDir()
+	legacy := filepath.Join(root, ".flnd")
+	data := filepath.Join(root, "share", "twallet")
+	state := filepath.Join(root, "state", "twallet")
+
+	if NeedsMigration(legacy, data) {
+		t.Fatal("a missing legacy directory needs no migration")
+	}
+	if err := os.MkdirAll(filepath.Join(legacy, "data"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacy, "twallet.log"), []byte("log"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if !NeedsMigration(legacy, data) {
+		t.Fatal("expected the legacy directory to need a migration")
+	}
+
+	if err := MigrateDataDir(legacy, data, state); err != nil {
+		t.Fatal(err)
+	}
+	if exists(legacy) || !exists(filepath.Join(data, "data")) {
+		t.Fatal("wallet directory not moved")
+	}
+	if exists(filepath.Join(data, "twallet.log")) || !exists(filepath.Join(state, "twallet.log")) {
+		t.Fatal("twallet.log not moved to the state directory")
+	}
+	if NeedsMigration(legacy, data) {
+		t.Fatal("a migrated directory needs no migration")
+	}
+}
+
+func TestDeclineMigration(t *testing.T) {
+	root := t.TempDir()
+	legacy := filepath.Join(root, ".flnd")
+	if err := os.MkdirAll(filepath.Join(legacy, "data"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeclineMigration(legacy); err != nil {
+		t.Fatal(err)
+	}
+	if NeedsMigration(legacy, filepath.Join(root, "twallet")) {
+		t.Fatal("a declined migration should not be offered again")
+	}
+}
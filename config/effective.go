@@ -0,0 +1,74 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Setting is an option of a resolved configuration, by its name in the
+// config file. Options that can be repeated have a value per line.
+type Setting struct {
+	Key    string
+	Values []string
+}
+
+// Effective lists the options cfg resolved to from the command line, the
+// config file, the environment and the defaults, in the order of the
+// configuration fields. The values of the secret options are hidden and the
+// deprecated options left out.
+func Effective(cfg *AppConfig) []Setting {
+	var settings []Setting
+	collectSettings(reflect.ValueOf(cfg).Elem(), &settings)
+	return settings
+}
+
+func collectSettings(v reflect.Value, settings *[]Setting) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectSettings(v.Field(i), settings)
+			continue
+		}
+		key := field.Tag.Get("long")
+		if key == "" || !field.IsExported() || field.Tag.Get("hidden") != "" {
+			continue
+		}
+
+		setting := Setting{Key: key}
+		value := v.Field(i)
+		if value.Kind() == reflect.Slice {
+			for j := 0; j < value.Len(); j++ {
+				setting.Values = append(setting.Values, fmt.Sprint(value.Index(j).Interface()))
+			}
+		} else {
+			setting.Values = []string{fmt.Sprint(value.Interface())}
+		}
+		if field.Tag.Get("secret") == "true" && len(setting.Values) > 0 && setting.Values[0] != "" {
+			setting.Values = []string{hiddenValue}
+		}
+		*settings = append(*settings, setting)
+	}
+}
+
+// WriteEffective writes the Effective configuration of cfg as config file
+// lines, a line per value.
+func WriteEffective(w io.Writer, cfg *AppConfig) error {
+	for _, setting := range Effective(cfg) {
+		if len(setting.Values) == 0 {
+			if _, err := fmt.Fprintf(w, "%s=\n", setting.Key); err != nil {
+				return err
+			}
+		}
+		for _, value := range setting.Values {
+			if _, err := fmt.Fprintf(w, "%s=%s\n", setting.Key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
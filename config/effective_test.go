@@ -0,0 +1,54 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEffective(t *testing.T) {
+	cfg, err := ReadFile(writeConfig(t, "walletdir=/srv/wallet\naddpeer=a:15212\naddpeer=b:15212\ndefaultpassword=hunter2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := map[string][]string{}
+	for _, s := range Effective(cfg) {
+		if _, dup := settings[s.Key]; dup {
+			t.Fatalf("%s listed twice", s.Key)
+		}
+		settings[s.Key] = s.Values
+	}
+
+	if got := settings["walletdir"]; len(got) != 1 || got[0] != "/srv/wallet" {
+		t.Fatalf("walletdir = %v", got)
+	}
+	if got := settings["addpeer"]; len(got) != 2 || got[1] != "b:15212" {
+		t.Fatalf("addpeer = %v", got)
+	}
+	if got := settings["defaultpassword"]; got[0] != hiddenValue {
+		t.Fatalf("defaultpassword not hidden: %v", got)
+	}
+	if got := settings["confirmwebhook"]; got[0] != "" {
+		t.Fatalf("an empty secret should stay empty, got %v", got)
+	}
+	if got := settings["loglevel"]; got[0] != "info" {
+		t.Fatalf("loglevel default = %v", got)
+	}
+	if _, ok := settings["testnet"]; ok {
+		t.Fatal("deprecated options should be left out")
+	}
+
+	var b strings.Builder
+	if err := WriteEffective(&b, cfg); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, line := range []string{"addpeer=a:15212\naddpeer=b:15212\n", "connect=\n", "defaultpassword=(hidden)\n"} {
+		if !strings.Contains(out, line) {
+			t.Fatalf("missing %q in\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Fatal("secret leaked")
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+)
+
+// EnvPrefix starts the environment variable of every option.
+const EnvPrefix = "TWALLET_"
+
+// EnvKey returns the environment variable of the option named long, e.g.
+// TWALLET_FIAT_URL for fiat.url.
+func EnvKey(long string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(long))
+}
+
+// BindEnv lets the environment variable of every option of parser replace
+// its default. The config file and the command line still win over it.
+// Options that can be repeated take a comma separated list.
+func BindEnv(parser *flags.Parser) {
+	var bind func(groups []*flags.Group)
+	bind = func(groups []*flags.Group) {
+		for _, group := range groups {
+			for _, option := range group.Options() {
+				if option.LongName == "" {
+					continue
+				}
+				option.EnvDefaultKey = EnvKey(option.LongName)
+				if reflect.TypeOf(option.Value()).Kind() == reflect.Slice {
+					option.EnvDefaultDelim = ","
+				}
+			}
+			bind(group.Groups())
+		}
+	}
+	bind(parser.Groups())
+}
+
+// FromEnv reports whether option took its value from the environment.
+func FromEnv(option *flags.Option) bool {
+	if option == nil || option.EnvDefaultKey == "" {
+		return false
+	}
+	_, ok := os.LookupEnv(option.EnvKeyWithNamespace())
+	return ok
+}
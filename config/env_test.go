@@ -0,0 +1,52 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestEnvKey(t *testing.T) {
+	cases := map[string]string{
+		"loglevel":                   "TWALLET_LOGLEVEL",
+		"fiat.url":                   "TWALLET_FIAT_URL",
+		"protocol.option-scid-alias": "TWALLET_PROTOCOL_OPTION_SCID_ALIAS",
+	}
+	for long, want := range cases {
+		if got := EnvKey(long); got != want {
+			t.Errorf("EnvKey(%q) = %q, want %q", long, got, want)
+		}
+	}
+}
+
+func TestReadFileFromEnv(t *testing.T) {
+	t.Setenv("TWALLET_LOGLEVEL", "debug")
+	t.Setenv("TWALLET_ADDPEER", "a:15212,b:15212")
+	t.Setenv("TWALLET_COLUMNS_TRANSACTIONS", "amount,fee")
+	t.Setenv("TWALLET_TESTNET", "true")
+
+	path := filepath.Join(t.TempDir(), "twallet.conf")
+	cfg, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.LogLevel != "debug" || !cfg.Testnet {
+		t.Fatalf("environment not applied: loglevel=%q testnet=%v", cfg.LogLevel, cfg.Testnet)
+	}
+	if !slices.Equal(cfg.AddPeers, []string{"a:15212", "b:15212"}) {
+		t.Fatalf("repeatable option not split: %v", cfg.AddPeers)
+	}
+	// A comma inside a single value is kept.
+	if cfg.TransactionColumns != "amount,fee" {
+		t.Fatalf("unexpected columns %q", cfg.TransactionColumns)
+	}
+
+	// The config file wins over the environment.
+	cfg, err = ReadFile(writeConfig(t, "loglevel=warn\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.LogLevel != "warn" {
+		t.Fatalf("config file should win, got loglevel=%q", cfg.LogLevel)
+	}
+}
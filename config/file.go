@@ -0,0 +1,126 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SetFileOption writes key=value into the INI config file at path, replacing
+// an existing uncommented assignment of key or adding one before the first
+// section header. The file is created when it does not exist yet.
+func SetFileOption(path, key, value string) error {
+	return SetFileOptionValues(path, key, []string{value})
+}
+
+// SaveOption writes the values of key to the config file twallet runs
+// with, in the section that sets key for its network and profile, see
+// SetNetworkFileOptionValues.
+func (c *AppConfig) SaveOption(key string, values ...string) error {
+	return setFileOption(c.ConfigFile, []string{c.NetworkName, profileSection(c.Profile)}, key, values)
+}
+
+// SetFileOptionValues writes one key=value line per value, for the options
+// that can be repeated, in place of the uncommented assignments of key. No
+// values removes them. Network and profile sections are left alone.
+func SetFileOptionValues(path, key string, values []string) error {
+	return setFileOption(path, nil, key, values)
+}
+
+// SetNetworkFileOptionValues is SetFileOptionValues for twallet running on
+// network: when the [<network>] section assigns key, the values replace that
+// assignment, which would override them elsewhere.
+func SetNetworkFileOptionValues(path, network, key string, values []string) error {
+	return setFileOption(path, []string{network}, key, values)
+}
+
+// setFileOption writes key in the first of scopes, the sections in the
+// order they override each other, that assigns it, outside the sections
+// otherwise.
+func setFileOption(path string, scopes []string, key string, values []string) error {
+	if path == "" {
+		return fmt.Errorf("no configuration file to write %s to", key)
+	}
+	if !IsINIFile(path) {
+		return fmt.Errorf("%s is only read by twallet, set %s in it by hand", path, key)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	var lines []string
+	if content != "" {
+		lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+
+	// sections holds the section ParseFile reads each line in, empty
+	// outside them.
+	sections := make([]string, len(lines))
+	assigned := map[string]bool{}
+	current := ""
+	for i, line := range lines {
+		if name, ok := sectionName(line); ok {
+			current = name
+		} else if isSectionHeader(line) {
+			current = ""
+		}
+		sections[i] = current
+		if name, ok := optionKey(line); ok && current != "" && strings.EqualFold(name, key) {
+			assigned[current] = true
+		}
+	}
+	scope := ""
+	for _, s := range scopes {
+		if s != "" && assigned[s] {
+			scope = s
+			break
+		}
+	}
+
+	entries := make([]string, 0, len(values))
+	for _, value := range values {
+		entries = append(entries, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	kept := make([]string, 0, len(lines)+len(entries))
+	insertAt := -1
+	section := -1
+	for i, line := range lines {
+		if isSectionHeader(line) && section < 0 {
+			section = len(kept)
+		}
+		if name, ok := optionKey(line); ok && sections[i] == scope && strings.EqualFold(name, key) {
+			if insertAt < 0 {
+				insertAt = len(kept)
+			}
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	switch {
+	case insertAt >= 0:
+	case section >= 0:
+		insertAt = section
+	default:
+		insertAt = len(kept)
+	}
+	lines = append(kept[:insertAt:insertAt], append(entries, kept[insertAt:]...)...)
+
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	} else if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), mode)
+}
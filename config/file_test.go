@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetFileOption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "twallet.conf")
+	initial := "; columns.transactions=timestamp\nloglevel=debug\ncolumns.transactions=amount\n\n[Lightning]\nalias=node\n"
+	if err := os.WriteFile(path, []byte(initial), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetFileOption(path, "columns.transactions", "amount,fee"); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if err := SetFileOption(path, "columns.addresses", "address"); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "; columns.transactions=timestamp\nloglevel=debug\ncolumns.transactions=amount,fee\n\ncolumns.addresses=address\n[Lightning]\nalias=node\n"
+	if string(got) != want {
+		t.Fatalf("unexpected file content:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestSetFileOptionCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "twallet.conf")
+	if err := SetFileOption(path, "columns.addresses", "type,address"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "columns.addresses=type,address\n" {
+		t.Fatalf("unexpected file content: %q", got)
+	}
+}
+
+func TestSetFileOptionValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "twallet.conf")
+	initial := "; addpeer=example.org\naddpeer=a:15212\nloglevel=debug\naddpeer=b:15212\n[Lightning]\nalias=node\n"
+	if err := os.WriteFile(path, []byte(initial), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetFileOptionValues(path, "addpeer", []string{"c:15212", "d:15212"}); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "; addpeer=example.org\naddpeer=c:15212\naddpeer=d:15212\nloglevel=debug\n[Lightning]\nalias=node\n"
+	if string(got) != want {
+		t.Fatalf("unexpected file content:\n%q\nwant:\n%q", got, want)
+	}
+
+	if err := SetFileOptionValues(path, "addpeer", nil); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	got, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "; addpeer=example.org\nloglevel=debug\n[Lightning]\nalias=node\n"
+	if string(got) != want {
+		t.Fatalf("unexpected file content:\n%q\nwant:\n%q", got, want)
+	}
+}
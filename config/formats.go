@@ -0,0 +1,177 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jessevdk/go-flags"
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the names twallet looks for its config file under, in order.
+var FileNames = []string{"twallet.conf", "twallet.toml", "twallet.yaml", "twallet.yml"}
+
+type fileFormat int
+
+const (
+	formatINI fileFormat = iota
+	formatTOML
+	formatYAML
+)
+
+func formatOf(path string) fileFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return formatTOML
+	case ".yaml", ".yml":
+		return formatYAML
+	}
+	return formatINI
+}
+
+// IsINIFile reports whether the config file at path is in the INI format,
+// the one twallet writes. TOML and YAML files are chosen by their extension
+// and only read.
+func IsINIFile(path string) bool {
+	return formatOf(path) == formatINI
+}
+
+// structuredToINI turns a TOML or YAML config file into the INI lines of the
+// same options: nested tables join their keys with dots, so [theme] with
+// background under it sets theme.background, a table named after a network
+// or under profile becomes its section, and a list sets one line per value. keys holds the
+// option of each line, to report an error by option rather than by a line
+// of the generated file.
+func structuredToINI(format fileFormat, data []byte) (content string, keys []string, err error) {
+	doc := map[string]any{}
+	switch format {
+	case formatTOML:
+		err = toml.Unmarshal(data, &doc)
+	case formatYAML:
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	type section struct {
+		name      string
+		options   map[string]any
+		forbidden []string
+	}
+	w := &iniWriter{}
+	var sections []section
+	for _, key := range sortedKeys(doc) {
+		table, ok := doc[key].(map[string]any)
+		if _, network := sectionNetwork("[" + key + "]"); ok && network {
+			sections = append(sections, section{key, table, sectionForbidden})
+			continue
+		}
+		if ok && key == "profile" {
+			for _, name := range sortedKeys(table) {
+				options, ok := table[name].(map[string]any)
+				if !ok {
+					return "", nil, fmt.Errorf("profile.%s: a profile is a table of options", name)
+				}
+				sections = append(sections, section{profilePrefix + name, options, profileForbidden})
+			}
+			continue
+		}
+		if err := w.flatten(key, doc[key]); err != nil {
+			return "", nil, err
+		}
+	}
+	for _, s := range sections {
+		w.line(s.name, "["+s.name+"]")
+		for _, key := range sortedKeys(s.options) {
+			if slices.Contains(s.forbidden, strings.ToLower(key)) {
+				return "", nil, fmt.Errorf("%s cannot be set in the %s table", key, s.name)
+			}
+			if err := w.flatten(key, s.options[key]); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	return strings.Join(w.lines, "\n") + "\n", w.keys, nil
+}
+
+type iniWriter struct {
+	lines []string
+	keys  []string
+}
+
+func (w *iniWriter) line(key, line string) {
+	w.lines = append(w.lines, line)
+	w.keys = append(w.keys, key)
+}
+
+func (w *iniWriter) flatten(key string, value any) error {
+	switch v := value.(type) {
+	case nil:
+	case map[string]any:
+		for _, k := range sortedKeys(v) {
+			if err := w.flatten(key+"."+k, v[k]); err != nil {
+				return err
+			}
+		}
+	case map[any]any:
+		table := make(map[string]any, len(v))
+		for k, item := range v {
+			table[fmt.Sprint(k)] = item
+		}
+		return w.flatten(key, table)
+	case []any:
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]any, map[any]any, []any:
+				return fmt.Errorf("%s: a list can only hold values", key)
+			}
+			w.line(key, key+"="+iniValue(item))
+		}
+	case []map[string]any:
+		return fmt.Errorf("%s: a list can only hold values", key)
+	default:
+		w.line(key, key+"="+iniValue(v))
+	}
+	return nil
+}
+
+// iniValue quotes the strings, which the INI parser unquotes, so they keep
+// their spaces and line breaks.
+func iniValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case time.Time:
+		return strconv.Quote(v.Format(time.RFC3339Nano))
+	}
+	return fmt.Sprint(value)
+}
+
+// structuredError names the option of the generated line err is about.
+func structuredError(path string, keys []string, err error) error {
+	var iniErr *flags.IniError
+	if errors.As(err, &iniErr) && iniErr.LineNumber > 0 && int(iniErr.LineNumber) <= len(keys) {
+		return fmt.Errorf("%s: %s: %s", path, keys[iniErr.LineNumber-1], iniErr.Message)
+	}
+	return fmt.Errorf("%s: %w", path, err)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
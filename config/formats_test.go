@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeNamedConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const tomlConfig = `network = "testnet3"
+loglevel = "debug"
+addpeer = ["common:15212"]
+rpctimeout = "30s"
+"layout.split" = 70
+
+[theme]
+background = "#101010"
+accent = "yellow"
+
+[alerts]
+webhook = "https://hooks.example.org/a b"
+
+[testnet]
+addpeer = ["test-a:35212", "test-b:35212"]
+
+[profile.work]
+walletdir = "/tmp/work"
+`
+
+const yamlConfig = `network: testnet3
+loglevel: debug
+addpeer:
+  - common:15212
+rpctimeout: 30s
+layout.split: 70
+theme:
+  background: "#101010"
+  accent: yellow
+alerts:
+  webhook: https://hooks.example.org/a b
+testnet:
+  addpeer:
+    - test-a:35212
+    - test-b:35212
+profile:
+  work:
+    walletdir: /tmp/work
+`
+
+func TestParseStructuredFiles(t *testing.T) {
+	for name, content := range map[string]string{"twallet.toml": tomlConfig, "twallet.yaml": yamlConfig} {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := ReadFile(writeNamedConfig(t, name, content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.LogLevel != "debug" || cfg.RPCTimeout != 30*time.Second || cfg.SplitPercent != 70 {
+				t.Fatalf("unexpected options: loglevel=%q rpctimeout=%v split=%d", cfg.LogLevel, cfg.RPCTimeout, cfg.SplitPercent)
+			}
+			if cfg.ThemeBackground != "#101010" || cfg.ThemeAccent != "yellow" {
+				t.Fatalf("nested tables not flattened: background=%q accent=%q", cfg.ThemeBackground, cfg.ThemeAccent)
+			}
+			if cfg.AlertWebhook != "https://hooks.example.org/a b" {
+				t.Fatalf("unexpected webhook %q", cfg.AlertWebhook)
+			}
+			if !slices.Equal(cfg.AddPeers, []string{"test-a:35212", "test-b:35212"}) {
+				t.Fatalf("the network table should override the peers, got %v", cfg.AddPeers)
+			}
+			if cfg.Walletdir != "" {
+				t.Fatalf("the profile should be left out without --profile, got walletdir=%q", cfg.Walletdir)
+			}
+			work, err := ReadProfile(writeNamedConfig(t, name, content), "work", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if work.Walletdir != "/tmp/work" {
+				t.Fatalf("profile table not applied, walletdir=%q", work.Walletdir)
+			}
+		})
+	}
+}
+
+func TestParseStructuredFileErrors(t *testing.T) {
+	tests := []struct {
+		name, file, content, want string
+	}{
+		{"unknown option", "twallet.toml", "[theme]\nbackgrnd = \"red\"\n", "theme.backgrnd"},
+		{"invalid value", "twallet.yaml", "layout:\n  split: many\n", "layout.split"},
+		{"network in section", "twallet.toml", "[testnet]\nnetwork = \"mainnet\"\n", "cannot be set in the testnet table"},
+		{"profile not a table", "twallet.toml", "[profile]\nwork = \"x\"\n", "a profile is a table of options"},
+		{"list of tables", "twallet.yaml", "addpeer:\n  - host: a\n", "a list can only hold values"},
+		{"syntax", "twallet.toml", "loglevel = \n", "twallet.toml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadFile(writeNamedConfig(t, tt.file, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got %v, want an error about %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructuredFilesAreNotWritten(t *testing.T) {
+	path := writeNamedConfig(t, "twallet.toml", tomlConfig)
+	if err := SetFileOption(path, "loglevel", "info"); err == nil {
+		t.Fatal("writing a TOML file should fail")
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != tomlConfig {
+		t.Fatal("the file should be left alone")
+	}
+}
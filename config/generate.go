@@ -0,0 +1,56 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InitAnswers are the settings asked by twallet init.
+type InitAnswers struct {
+	Network     string
+	Walletdir   string
+	AddressType string
+	Peers       []string
+}
+
+// Generate returns a commented config file setting answers, the other
+// options left to their defaults.
+func Generate(answers InitAnswers) string {
+	var b strings.Builder
+	section := func(title string) {
+		b.WriteString("; ============================================================================\n")
+		fmt.Fprintf(&b, "; %s\n", title)
+		b.WriteString("; ============================================================================\n\n")
+	}
+
+	b.WriteString("; twallet configuration written by `twallet init`.\n")
+	b.WriteString("; Every other option is described in twallet.conf.sample and in --help.\n\n")
+	b.WriteString("; Version of the layout of this file, for twallet to migrate it.\n")
+	fmt.Fprintf(&b, "%s=%d\n\n", configVersionKey, SchemaVersion)
+
+	section("Chain")
+	b.WriteString("; Network to run on (mainnet, testnet3, testnet4, signet, regtest, or simnet).\n")
+	fmt.Fprintf(&b, "network=%s\n\n", answers.Network)
+
+	b.WriteString("; Directory of the wallet database and the daemon data.\n")
+	fmt.Fprintf(&b, "walletdir=%s\n\n", answers.Walletdir)
+
+	b.WriteString("; Address type to generate (taproot, segwit, or nested-segwit).\n")
+	fmt.Fprintf(&b, "addresstype=%s\n\n", answers.AddressType)
+
+	section("Peers")
+	b.WriteString("; Peers to connect to at startup, in addition to the discovered ones.\n")
+	b.WriteString("; One peer per line. Format: hostname:port or ip:port\n")
+	if len(answers.Peers) == 0 {
+		b.WriteString("; addpeer=peer1.example.com:15212\n")
+	}
+	for _, peer := range answers.Peers {
+		fmt.Fprintf(&b, "addpeer=%s\n", peer)
+	}
+
+	return b.String()
+}
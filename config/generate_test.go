@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestGenerateParses(t *testing.T) {
+	answers := InitAnswers{
+		Network:     "testnet4",
+		Walletdir:   "/tmp/flnd",
+		AddressType: "taproot",
+		Peers:       []string{"a.example.org:35212", "1.2.3.4:35212"},
+	}
+	cfg, err := ReadFile(writeConfig(t, Generate(answers)))
+	if err != nil {
+		t.Fatalf("generated file does not parse: %v", err)
+	}
+	if cfg.NetworkName != "testnet4" {
+		t.Fatalf("unexpected network %q", cfg.NetworkName)
+	}
+	if cfg.Walletdir != answers.Walletdir || cfg.AddressType != answers.AddressType {
+		t.Fatalf("unexpected walletdir %q or address type %q", cfg.Walletdir, cfg.AddressType)
+	}
+	if !slices.Equal(cfg.AddPeers, answers.Peers) {
+		t.Fatalf("unexpected peers %v", cfg.AddPeers)
+	}
+
+	mainnet, err := ReadFile(writeConfig(t, Generate(InitAnswers{Network: "mainnet", Walletdir: "/tmp/flnd", AddressType: "segwit"})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mainnet.NetworkName != "mainnet" || len(mainnet.AddPeers) != 0 {
+		t.Fatalf("unexpected mainnet config: %+v", mainnet.ServiceConfig)
+	}
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "twallet.conf")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package config
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// The key of secret:keyring: values is kept base64 encoded under this
+// service and account: the macOS keychain, the Secret Service through
+// secret-tool elsewhere, or the Windows credential vault.
+const (
+	keyringService = "twallet"
+	keyringAccount = "secrets"
+)
+
+// windowsVault loads the Windows credential vault in PowerShell.
+const windowsVault = "[void][Windows.Security.Credentials.PasswordVault,Windows.Security.Credentials,ContentType=WindowsRuntime];" +
+	"$v=New-Object Windows.Security.Credentials.PasswordVault;"
+
+// ReadKeyringKey returns the key of secret:keyring: values from the OS
+// keyring, ErrNoSecretKey when it holds none.
+func ReadKeyringKey() ([]byte, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", keyringAccount, "-w")
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsVault+
+			fmt.Sprintf("$c=$v.Retrieve('%s','%s');$c.RetrievePassword();$c.Password", keyringService, keyringAccount))
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "account", keyringAccount)
+	}
+	out, err := cmd.Output()
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, fmt.Errorf("no OS keyring: %w", err)
+	}
+	encoded := strings.TrimSpace(string(out))
+	if err != nil || encoded == "" {
+		return nil, fmt.Errorf("%w: the OS keyring holds no twallet key", ErrNoSecretKey)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != chacha20poly1305.KeySize {
+		return nil, errors.New("the twallet key of the OS keyring is malformed")
+	}
+	return key, nil
+}
+
+// CreateKeyringKey returns the key of the OS keyring, storing a new random
+// one there first when there is none.
+func CreateKeyringKey() ([]byte, error) {
+	if key, err := ReadKeyringKey(); !errors.Is(err, ErrNoSecretKey) {
+		return key, err
+	}
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(key)
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", keyringService, "-a", keyringAccount, "-w", encoded)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsVault+
+			fmt.Sprintf("$v.Add((New-Object Windows.Security.Credentials.PasswordCredential('%s','%s',[Console]::In.ReadLine())))", keyringService, keyringAccount))
+		cmd.Stdin = strings.NewReader(encoded + "\n")
+	default:
+		// The key goes through stdin, out of the process list.
+		cmd = exec.Command("secret-tool", "store", "--label=twallet secrets", "service", keyringService, "account", keyringAccount)
+		cmd.Stdin = strings.NewReader(encoded)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("unable to store the key in the OS keyring: %s", msg)
+		}
+		return nil, fmt.Errorf("unable to store the key in the OS keyring: %w", err)
+	}
+	return key, nil
+}
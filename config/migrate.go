@@ -0,0 +1,296 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SchemaVersion is the version of the config file layout this twallet
+// writes, kept in its configversion option. A file without one is version 0.
+const SchemaVersion = 1
+
+// configVersionKey is the option holding the version of a config file.
+const configVersionKey = "configversion"
+
+// migration brings the lines of a config file to its version from the one
+// before, returning a description of every change it made.
+type migration struct {
+	version int
+	apply   func(lines []string) ([]string, []string)
+}
+
+// migrations are applied in order to the files of an older version.
+var migrations = []migration{
+	{version: 1, apply: migrateNetworkOptions},
+}
+
+// Migration reports the upgrade of a config file to SchemaVersion.
+type Migration struct {
+	Path     string
+	From, To int
+	Changes  []string
+	// Backup is the copy of the file from before the upgrade, empty when
+	// the file is only read by twallet and was left alone.
+	Backup string
+	// Pending is set by PlanMigration on a file the next start migrates.
+	Pending bool
+}
+
+func (m *Migration) String() string {
+	var b strings.Builder
+	switch {
+	case m.Backup != "":
+		fmt.Fprintf(&b, "Migrated %s from config version %d to %d, the previous file is kept as %s:\n", m.Path, m.From, m.To, m.Backup)
+	case m.Pending:
+		fmt.Fprintf(&b, "%s is in config version %d, the next start migrates it to %d:\n", m.Path, m.From, m.To)
+	default:
+		fmt.Fprintf(&b, "%s uses options changed in config version %d, update it by hand:\n", m.Path, m.To)
+	}
+	for _, change := range m.Changes {
+		fmt.Fprintf(&b, "  %s\n", change)
+	}
+	return b.String()
+}
+
+// MigrateFile upgrades the config file at path to SchemaVersion: options
+// that were renamed or moved are rewritten, the file before is copied next
+// to it and configversion is set. TOML and YAML files are not written, the
+// changes they need are returned for the user to make. Nil is returned when
+// the file is missing or has nothing to change.
+func MigrateFile(path string) (*Migration, error) {
+	m, data, lines, err := planMigration(path)
+	if err != nil || m == nil {
+		return nil, err
+	}
+	if !IsINIFile(path) {
+		return m, nil
+	}
+
+	m.Backup = fmt.Sprintf("%s.v%d.bak", path, m.From)
+	if err := writeBackup(m.Backup, data); err != nil {
+		return nil, err
+	}
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), mode); err != nil {
+		return nil, err
+	}
+	if err := SetFileOption(path, configVersionKey, strconv.Itoa(SchemaVersion)); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PlanMigration returns the changes MigrateFile would make to the config
+// file at path, leaving it as it is. The options it renames are still read
+// under their old names, so the file can be used unmigrated.
+func PlanMigration(path string) (*Migration, error) {
+	m, _, _, err := planMigration(path)
+	if err != nil || m == nil {
+		return nil, err
+	}
+	m.Pending = IsINIFile(path)
+	return m, nil
+}
+
+// planMigration applies the migrations to the content of the file at path,
+// returning it before and after them. The Migration is nil when the file is
+// missing or has nothing to change.
+func planMigration(path string) (*Migration, []byte, []string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	content := string(data)
+	if format := formatOf(path); format != formatINI {
+		if content, _, err = structuredToINI(format, data); err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	from, err := fileVersion(lines)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	m := &Migration{Path: path, From: from, To: SchemaVersion}
+	for _, step := range migrations {
+		if step.version <= from {
+			continue
+		}
+		var changes []string
+		lines, changes = step.apply(lines)
+		m.Changes = append(m.Changes, changes...)
+	}
+	// Files of the current layout are stamped by twallet init, the others
+	// only when they change, so a file is not rewritten for its version
+	// alone.
+	if len(m.Changes) == 0 {
+		return nil, nil, nil, nil
+	}
+	return m, data, lines, nil
+}
+
+// writeBackup copies data to path, keeping a backup left by an earlier
+// migration that did not finish.
+func writeBackup(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// fileVersion reads the configversion of the lines outside the sections.
+func fileVersion(lines []string) (int, error) {
+	version := 0
+	for i, scope := range lineScopes(lines) {
+		if key, ok := optionKey(lines[i]); !ok || key != configVersionKey || scope != "" {
+			continue
+		}
+		v, err := strconv.Atoi(optionValue(lines[i]))
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("line %d: invalid %s %q", i+1, configVersionKey, optionValue(lines[i]))
+		}
+		version = v
+	}
+	return version, nil
+}
+
+// lineScopes returns the section ParseFile reads every line in, empty
+// outside them.
+func lineScopes(lines []string) []string {
+	scopes := make([]string, len(lines))
+	current := ""
+	for i, line := range lines {
+		if name, ok := sectionName(line); ok {
+			current = name
+		} else if isSectionHeader(line) {
+			current = ""
+		}
+		scopes[i] = current
+	}
+	return scopes
+}
+
+// optionValue returns the value an option line assigns, unquoted.
+func optionValue(line string) string {
+	_, value, _ := strings.Cut(line, "=")
+	value = strings.TrimSpace(value)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	return value
+}
+
+// migrateNetworkOptions replaces the deprecated testnet and regtest options
+// by network, and the [testnet] section by [testnet3]. An option conflicting
+// with the network of its section is left for ResolveNetwork to report.
+func migrateNetworkOptions(lines []string) ([]string, []string) {
+	legacyNetworks := map[string]string{"testnet": "testnet3", "regtest": "regtest"}
+
+	type scopeOptions struct {
+		network string
+		legacy  []int
+	}
+	scopes := lineScopes(lines)
+	options := map[string]*scopeOptions{}
+	for i, line := range lines {
+		key, ok := optionKey(line)
+		if !ok {
+			continue
+		}
+		o := options[scopes[i]]
+		if o == nil {
+			o = &scopeOptions{}
+			options[scopes[i]] = o
+		}
+		if key == "network" {
+			o.network = optionValue(line)
+		} else if _, ok := legacyNetworks[key]; ok {
+			o.legacy = append(o.legacy, i)
+		}
+	}
+
+	// replace holds the new content of the lines changed, empty to remove
+	// them, and notes what was done to them.
+	replace := map[int]string{}
+	notes := map[int]string{}
+	for scope, o := range options {
+		if len(o.legacy) == 0 {
+			continue
+		}
+		network, at, conflict := "", -1, false
+		for _, i := range o.legacy {
+			key, _ := optionKey(lines[i])
+			value := optionValue(lines[i])
+			on, err := strconv.ParseBool(value)
+			if value == "" {
+				on, err = true, nil
+			}
+			// A value twallet does not read, or both options set.
+			if err != nil || (on && network != "") {
+				conflict = true
+				break
+			}
+			if on {
+				network, at = legacyNetworks[key], i
+			}
+		}
+		if conflict || (network != "" && o.network != "" && o.network != network) {
+			continue
+		}
+		for _, i := range o.legacy {
+			line := strings.TrimSpace(lines[i])
+			if i == at && o.network == "" {
+				replace[i] = "network=" + network
+				notes[i] = line + " → network=" + network
+			} else {
+				replace[i] = ""
+				notes[i] = line + " removed"
+			}
+			if scope != "" {
+				notes[i] += " in [" + scope + "]"
+			}
+		}
+	}
+
+	var out, changes []string
+	for i, line := range lines {
+		if name, ok := sectionHeaderName(line); ok && name == "testnet" {
+			out = append(out, "[testnet3]")
+			changes = append(changes, "[testnet] → [testnet3]")
+			continue
+		}
+		if value, ok := replace[i]; ok {
+			changes = append(changes, notes[i])
+			if value != "" {
+				out = append(out, value)
+			}
+			continue
+		}
+		out = append(out, line)
+	}
+	return out, changes
+}
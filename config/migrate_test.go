@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const legacyConfig = `; written by an old twallet
+testnet=1
+loglevel=debug
+
+[profile.local]
+regtest=true
+testnet=0
+
+[testnet]
+walletdir=/tmp/flnd-testnet
+`
+
+func TestMigrateFile(t *testing.T) {
+	path := writeConfig(t, legacyConfig)
+
+	m, err := MigrateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || m.From != 0 || m.To != SchemaVersion {
+		t.Fatalf("unexpected migration %+v", m)
+	}
+	want := []string{
+		"testnet=1 → network=testnet3",
+		"regtest=true → network=regtest in [profile.local]",
+		"testnet=0 removed in [profile.local]",
+		"[testnet] → [testnet3]",
+	}
+	if !slices.Equal(m.Changes, want) {
+		t.Fatalf("changes = %q, want %q", m.Changes, want)
+	}
+
+	backup, err := os.ReadFile(m.Backup)
+	if err != nil || string(backup) != legacyConfig {
+		t.Fatalf("backup %s = %q, %v", m.Backup, backup, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, old := range []string{"testnet=1", "regtest=", "[testnet]"} {
+		if strings.Contains(string(data), old) {
+			t.Fatalf("%q left in the migrated file:\n%s", old, data)
+		}
+	}
+
+	cfg, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.NetworkName != "testnet3" || cfg.Testnet || cfg.ConfigVersion != SchemaVersion {
+		t.Fatalf("unexpected migrated config: network %q, testnet %v, version %d", cfg.NetworkName, cfg.Testnet, cfg.ConfigVersion)
+	}
+	if cfg.Walletdir != "/tmp/flnd-testnet" {
+		t.Fatalf("the [testnet3] section was not applied: walletdir %q", cfg.Walletdir)
+	}
+	profile, err := ReadProfile(path, "local", "")
+	if err != nil || profile.NetworkName != "regtest" {
+		t.Fatalf("profile network = %q, %v", profile.NetworkName, err)
+	}
+
+	if m, err := MigrateFile(path); err != nil || m != nil {
+		t.Fatalf("second migration = %+v, %v", m, err)
+	}
+}
+
+func TestMigrateFileConflicts(t *testing.T) {
+	// Left for ResolveNetwork to report.
+	content := "network=mainnet\ntestnet=1\n"
+	path := writeConfig(t, content)
+	if m, err := MigrateFile(path); err != nil || m != nil {
+		t.Fatalf("migration = %+v, %v", m, err)
+	}
+
+	path = writeConfig(t, "network=testnet3\ntestnet=1\n")
+	m, err := MigrateFile(path)
+	if err != nil || m == nil || !slices.Equal(m.Changes, []string{"testnet=1 removed"}) {
+		t.Fatalf("migration = %+v, %v", m, err)
+	}
+}
+
+func TestMigrateStructuredFile(t *testing.T) {
+	content := "testnet = true\n"
+	path := writeNamedConfig(t, "twallet.toml", content)
+
+	m, err := MigrateFile(path)
+	if err != nil || m == nil || m.Backup != "" {
+		t.Fatalf("migration = %+v, %v", m, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Fatalf("the TOML file was written: %q", data)
+	}
+	if !strings.Contains(m.String(), "update it by hand") {
+		t.Fatalf("unexpected report %q", m.String())
+	}
+}
+
+func TestMigrateFileCurrentVersion(t *testing.T) {
+	path := writeConfig(t, Generate(InitAnswers{Network: "testnet4", Walletdir: "/tmp/flnd", AddressType: "segwit"}))
+	if m, err := MigrateFile(path); err != nil || m != nil {
+		t.Fatalf("migration of a generated file = %+v, %v", m, err)
+	}
+
+	path = writeConfig(t, "configversion=x\n")
+	if _, err := MigrateFile(path); err == nil {
+		t.Fatal("an invalid configversion should fail")
+	}
+}
+
+func TestPlanMigration(t *testing.T) {
+	content := "testnet=1\n"
+	path := writeConfig(t, content)
+
+	m, err := PlanMigration(path)
+	if err != nil || m == nil || !m.Pending || m.Backup != "" {
+		t.Fatalf("plan = %+v, %v", m, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Fatalf("the file was written: %q", data)
+	}
+	if _, err := os.Stat(path + ".v0.bak"); !os.IsNotExist(err) {
+		t.Fatalf("a backup was written: %v", err)
+	}
+	if !strings.Contains(m.String(), "the next start migrates it") {
+		t.Fatalf("unexpected report %q", m.String())
+	}
+}
@@ -1,21 +1,82 @@
 package config
 
 import (
+	"time"
+
 	"github.com/flokiorg/flnd/lnrpc"
 	"github.com/flokiorg/twallet/flnd"
 )
 
+// DefaultTransactionDisplayLimit is the display limit used when no positive
+// one is configured.
+const DefaultTransactionDisplayLimit = 121
+
 type AppConfig struct {
 	flnd.ServiceConfig
 	ConfigFile      string `short:"c" long:"config" description:"Path to configuration file"`
+	Profile         string `long:"profile" no-ini:"true" description:"Apply the [profile.<name>] section of the configuration file, e.g. a wallet directory, network and peers"`
+	ConfigVersion   int    `long:"configversion" hidden:"true" description:"Version of the layout of the configuration file, set when twallet migrates it"`
+	LogDir          string `long:"logdir" description:"Directory of twallet.log (defaults to XDG_STATE_HOME/twallet on Linux, the wallet directory elsewhere)"`
 	LogLevel        string `long:"loglevel" choice:"trace" choice:"debug" choice:"info" choice:"warn" choice:"error" choice:"fatal" choice:"panic" default:"info" description:"Logging level for twallet output"`
-	DefaultPassword string `long:"defaultpassword" description:"Use default passphrase for locking (TESTING ONLY, DO NOT USE IN MAINNET OR PRODUCTION ENVIRONMENTS)"`
+	DefaultPassword string `long:"defaultpassword" secret:"true" description:"Use default passphrase for locking (TESTING ONLY, DO NOT USE IN MAINNET OR PRODUCTION ENVIRONMENTS)"`
 	AddressType     string `long:"addresstype" choice:"taproot" choice:"segwit" choice:"nested-segwit" default:"segwit" description:"Address type to generate (taproot, segwit, or nested-segwit)."`
 	AutoUnlock      bool   `long:"autounlock" description:"Automatically unlock the wallet on startup using defaultpassword (WARNING: Use with caution)"`
 	Version         bool   `short:"v" description:"Print version"`
 
+	LogMaxSize  int           `long:"log.maxsize" default:"20" description:"Rotate twallet.log once it reaches this many MB (0 to disable)"`
+	LogMaxAge   time.Duration `long:"log.maxage" description:"Rotate twallet.log once it has been written for this long, e.g. 24h (0 to disable)"`
+	LogMaxFiles int           `long:"log.maxfiles" default:"10" description:"Rotated twallet.log files to keep (0 keeps them all)"`
+
+	LargeSend float64 `long:"largesend" description:"Sends of at least this many FLC ask to type the amount again before they are published (0 to disable)"`
+
+	ExplorerURL string `long:"explorerurl" description:"Block explorer transaction URL, %s is replaced by the txid (defaults to lokichain.info on mainnet)"`
+
+	WatchAddresses string `long:"watch.addresses" description:"Comma separated addresses not owned by the wallet to watch for activity, each as address@height (managed from the Watched view)"`
+
 	AutoRefreshInterval int `long:"autorefreshinterval" description:"Interval in seconds to automatically refresh the TUI (0 to disable)" default:"300"`
 
+	Bell           bool   `long:"bell" description:"Ring the terminal bell when an incoming transaction arrives"`
+	OnReceiveCmd   string `long:"onreceivecmd" description:"Command to run when an incoming transaction arrives (TWALLET_TX_HASH, TWALLET_TX_AMOUNT and TWALLET_TX_CONFIRMATIONS are set in its environment)"`
+	ConfirmWebhook string `long:"confirmwebhook" secret:"true" description:"URL receiving a JSON POST when a transaction reaches the confirmations requested from its detail view"`
+
+	AlertWebhook     string        `long:"alerts.webhook" secret:"true" description:"URL receiving a JSON POST whenever an alert is raised or resolved"`
+	PeerOfflineAlert time.Duration `long:"alerts.peeroffline" description:"Warn when the peer of a Lightning channel stays offline longer than this, e.g. 30m (disabled when 0)"`
+	MinBalanceAlert  float64       `long:"alerts.minbalance" description:"Warn when the confirmed balance falls below this many FLC (disabled when 0)"`
+	PriceAlerts      string        `long:"alerts.price" description:"Comma separated FLC price thresholds in the fiat currency, >x alerts above x and <x below it (needs fiat.url)"`
+	FeeRateAlert     float64       `long:"alerts.feerate" description:"Warn when the next block fee rate of the fee API reaches this many loki/vB and show its trend on the footer (disabled when 0)"`
+
+	Language     string `long:"language" choice:"en" choice:"es" default:"en" description:"Language of the interface"`
+	SeedLanguage string `long:"seedlanguage" choice:"en" choice:"es" default:"en" description:"Wordlist used to display and enter the wallet seed (other wallets expect the English wordlist)"`
+
+	Denomination string `long:"denomination" choice:"flc" choice:"loki" default:"flc" description:"Unit used to display and enter amounts"`
+
+	TimeZone   string `long:"time.zone" default:"local" description:"Time zone timestamps are shown, exported and grouped into days in: local, utc or a zone name such as Europe/Berlin"`
+	TimeFormat string `long:"time.format" default:"2006-01-02 15:04:05" description:"Layout of the timestamps shown, written with Go's reference time (exports stay RFC 3339)"`
+
+	NoFiat       bool          `long:"nofiat" description:"Disable approximate fiat values next to FLC amounts"`
+	FiatURL      string        `long:"fiat.url" secret:"true" description:"Exchange-rate endpoint returning JSON with the FLC price (fiat display is off when empty)"`
+	FiatField    string        `long:"fiat.field" default:"price" description:"Dot separated path to the FLC price inside the JSON response"`
+	FiatCurrency string        `long:"fiat.currency" default:"USD" description:"Currency code of the exchange rate"`
+	FiatCacheTTL time.Duration `long:"fiat.cachettl" default:"5m" description:"How long a fetched exchange rate is reused"`
+
+	Theme           string `long:"theme" default:"default" description:"Color theme preset (default, light, mono)"`
+	ThemeBackground string `long:"theme.background" description:"Override the theme background color (name or #rrggbb)"`
+	ThemeText       string `long:"theme.text" description:"Override the theme text color (name or #rrggbb)"`
+	ThemeBorder     string `long:"theme.border" description:"Override the theme border color (name or #rrggbb)"`
+	ThemeAccent     string `long:"theme.accent" description:"Override the theme accent color used for shortcuts (name or #rrggbb)"`
+	ThemeModal      string `long:"theme.modal" description:"Override the theme modal frame color (name or #rrggbb)"`
+	ThemeSelection  string `long:"theme.selection" description:"Override the theme table selection color (name or #rrggbb)"`
+
+	MetricsListen string `long:"metrics.listen" description:"Serve Prometheus metrics on this address under /metrics (e.g. 127.0.0.1:9110, disabled when empty)"`
+	HealthListen  string `long:"healthlisten" description:"Serve the wallet status as JSON on this address under /healthz for supervisors (e.g. 127.0.0.1:9111, disabled when empty)"`
+
+	TransactionColumns string `long:"columns.transactions" default:"timestamp,txid,address,amount,confirmations" description:"Comma separated columns of the transactions table (timestamp, txid, address, amount, fee, confirmations)"`
+	SplitPercent       int    `long:"layout.split" default:"60" description:"Share of the width, in percent, the transactions take next to the logs in the split view (ctrl+b)"`
+	AddressColumns     string `long:"columns.addresses" default:"type,address,balance,txcount" description:"Comma separated columns of the addresses table (type, address, balance, txcount)"`
+
 	UsedAddressType   lnrpc.AddressType
 	UnusedAddressType lnrpc.AddressType
+
+	// secretKeys opened the encrypted options, a reload opens them again.
+	secretKeys SecretKeys
 }
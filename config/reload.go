@@ -0,0 +1,88 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+
+	"github.com/jessevdk/go-flags"
+)
+
+// Change is an option whose value differs between two configurations, by
+// its name in the config file.
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+// hiddenValue stands for the values of the secret options in a Change.
+const hiddenValue = "(hidden)"
+
+// ReadFile parses the config file at path over the defaults and the
+// environment, leaving out the command line and the defaults twallet derives
+// at start, so two reads of the same file compare equal. A missing file
+// reads as the defaults. Encrypted options are left encrypted.
+func ReadFile(path string) (*AppConfig, error) {
+	return ReadProfile(path, "", "")
+}
+
+// ReadProfile is ReadFile applying the section of profile, for twallet
+// started with --profile, and of network, the network twallet runs on
+// whatever the file sets, as --network may have chosen it. No profile keeps
+// the one of the environment, no network the one of the file.
+func ReadProfile(path, profile, network string) (*AppConfig, error) {
+	cfg := &AppConfig{}
+	parser := flags.NewParser(cfg, flags.None)
+	BindEnv(parser)
+	if _, err := parser.ParseArgs(nil); err != nil {
+		return nil, err
+	}
+	if profile != "" {
+		cfg.Profile = profile
+	}
+	if err := parseFile(parser, cfg, path, network); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if network != "" {
+		cfg.NetworkName = network
+	}
+	return cfg, nil
+}
+
+// Changes lists the options whose value differs from old to updated, in the
+// order of the configuration fields.
+func Changes(old, updated *AppConfig) []Change {
+	var changes []Change
+	collectChanges(reflect.ValueOf(old).Elem(), reflect.ValueOf(updated).Elem(), &changes)
+	return changes
+}
+
+func collectChanges(old, updated reflect.Value, changes *[]Change) {
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectChanges(old.Field(i), updated.Field(i), changes)
+			continue
+		}
+		key := field.Tag.Get("long")
+		if key == "" || !field.IsExported() {
+			continue
+		}
+		a, b := old.Field(i).Interface(), updated.Field(i).Interface()
+		if reflect.DeepEqual(a, b) {
+			continue
+		}
+		change := Change{Key: key, Old: fmt.Sprint(a), New: fmt.Sprint(b)}
+		if field.Tag.Get("secret") == "true" {
+			// The values end up in the log.
+			change.Old, change.New = hiddenValue, hiddenValue
+		}
+		*changes = append(*changes, change)
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestReadFileChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "twallet.conf")
+
+	// A missing file reads as the defaults.
+	base, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.LogLevel != "info" || base.SplitPercent != 60 {
+		t.Fatalf("defaults not applied: loglevel=%q split=%d", base.LogLevel, base.SplitPercent)
+	}
+
+	if err := os.WriteFile(path, []byte("loglevel=debug\naddpeer=a:15212\naddpeer=b:15212\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := Changes(base, updated)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Key != "addpeer" || changes[0].New != "[a:15212 b:15212]" {
+		t.Fatalf("unexpected first change: %+v", changes[0])
+	}
+	if changes[1] != (Change{Key: "loglevel", Old: "info", New: "debug"}) {
+		t.Fatalf("unexpected second change: %+v", changes[1])
+	}
+
+	if changes := Changes(updated, updated); len(changes) != 0 {
+		t.Fatalf("expected no change, got %+v", changes)
+	}
+}
+
+func TestReadFileRejectsUnknownOption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "twallet.conf")
+	if err := os.WriteFile(path, []byte("nosuchoption=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFile(path); err == nil {
+		t.Fatal("expected an error for an unknown option")
+	}
+}
+
+func TestReadProfileRunningNetwork(t *testing.T) {
+	// twallet started with --network=testnet3, the file naming no network.
+	path := writeConfig(t, sectionsConfig)
+	base, err := ReadProfile(path, "", "testnet3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.NetworkName != "testnet3" || !slices.Equal(base.AddPeers, []string{"test-a:35212", "test-b:35212"}) {
+		t.Fatalf("expected the testnet section, got network=%q peers=%v", base.NetworkName, base.AddPeers)
+	}
+
+	// The network set in the file does not move the reload off the running
+	// one.
+	if err := os.WriteFile(path, []byte("network=mainnet\n"+sectionsConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := ReadProfile(path, "", "testnet3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Feeurl != "https://test-fees.example.org" || updated.Walletdir != "/tmp/testnet" {
+		t.Fatalf("expected the testnet section, got feeurl=%q walletdir=%q", updated.Feeurl, updated.Walletdir)
+	}
+	if changes := Changes(base, updated); len(changes) != 0 {
+		t.Fatalf("expected no change, got %+v", changes)
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package config
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Encrypted values are written secret:pass:<base64> when the wallet
+// passphrase opens them, secret:keyring:<base64> for the key kept in the OS
+// keyring.
+const (
+	secretPrefix     = "secret:"
+	secretPassphrase = secretPrefix + "pass:"
+	secretKeyring    = secretPrefix + "keyring:"
+)
+
+// Argon2id parameters deriving a key from the passphrase, for each value
+// with its own salt.
+const (
+	secretSaltSize = 16
+	argonTime      = 1
+	argonMemory    = 64 * 1024
+	argonThreads   = 4
+)
+
+// ErrNoSecretKey is returned for an encrypted value when the key opening it
+// is not available.
+var ErrNoSecretKey = errors.New("no key to decrypt the value")
+
+// SecretKeys open the encrypted options. Each is only called when a value
+// it opens is found.
+type SecretKeys struct {
+	// Passphrase returns the wallet passphrase, opening secret:pass:
+	// values.
+	Passphrase func() (string, error)
+	// Keyring returns the key of secret:keyring: values, see
+	// ReadKeyringKey.
+	Keyring func() ([]byte, error)
+}
+
+// IsSecret reports whether value is encrypted.
+func IsSecret(value string) bool {
+	return strings.HasPrefix(value, secretPrefix)
+}
+
+// SecretOptions returns the options that can be encrypted, those of the
+// embedded structs included.
+func SecretOptions() []string {
+	var names []string
+	collectSecretOptions(reflect.TypeOf(AppConfig{}), &names)
+	return names
+}
+
+func collectSecretOptions(t reflect.Type, names *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			collectSecretOptions(field.Type, names)
+			continue
+		}
+		if field.Tag.Get("secret") == "true" {
+			*names = append(*names, field.Tag.Get("long"))
+		}
+	}
+}
+
+// DecryptSecrets replaces the encrypted values of the secret options with
+// their plain text, keeping keys for SecretKeys.
+func (c *AppConfig) DecryptSecrets(keys SecretKeys) error {
+	c.secretKeys = keys
+	return decryptSecrets(reflect.ValueOf(c).Elem(), keys)
+}
+
+func decryptSecrets(v reflect.Value, keys SecretKeys) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := decryptSecrets(v.Field(i), keys); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.Tag.Get("secret") != "true" {
+			continue
+		}
+		for _, value := range secretValues(v.Field(i)) {
+			if !IsSecret(value.String()) {
+				continue
+			}
+			plain, err := DecryptSecret(value.String(), keys)
+			if err != nil {
+				return fmt.Errorf("%s: %w", field.Tag.Get("long"), err)
+			}
+			value.SetString(plain)
+		}
+	}
+	return nil
+}
+
+// secretValues returns the strings of a secret option, a string or a
+// repeated option.
+func secretValues(field reflect.Value) []reflect.Value {
+	switch {
+	case field.Kind() == reflect.String:
+		return []reflect.Value{field}
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		values := make([]reflect.Value, field.Len())
+		for i := range values {
+			values[i] = field.Index(i)
+		}
+		return values
+	}
+	return nil
+}
+
+// SecretKeys returns the keys the options were last decrypted with.
+func (c *AppConfig) SecretKeys() SecretKeys {
+	return c.secretKeys
+}
+
+// EncryptWithPassphrase encrypts plain with a key derived from the wallet
+// passphrase.
+func EncryptWithPassphrase(plain, passphrase string) (string, error) {
+	salt := make([]byte, secretSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	sealed, err := seal(passphraseKey(passphrase, salt), plain)
+	if err != nil {
+		return "", err
+	}
+	return secretPassphrase + base64.StdEncoding.EncodeToString(append(salt, sealed...)), nil
+}
+
+// EncryptWithKeyring encrypts plain with the key kept in the OS keyring.
+func EncryptWithKeyring(plain string, key []byte) (string, error) {
+	sealed, err := seal(key, plain)
+	if err != nil {
+		return "", err
+	}
+	return secretKeyring + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptSecret returns the plain text of an encrypted value.
+func DecryptSecret(value string, keys SecretKeys) (string, error) {
+	switch {
+	case strings.HasPrefix(value, secretPassphrase):
+		data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, secretPassphrase))
+		if err != nil || len(data) < secretSaltSize {
+			return "", errors.New("malformed encrypted value")
+		}
+		if keys.Passphrase == nil {
+			return "", ErrNoSecretKey
+		}
+		passphrase, err := keys.Passphrase()
+		if err != nil {
+			return "", err
+		}
+		return open(passphraseKey(passphrase, data[:secretSaltSize]), data[secretSaltSize:])
+
+	case strings.HasPrefix(value, secretKeyring):
+		data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, secretKeyring))
+		if err != nil {
+			return "", errors.New("malformed encrypted value")
+		}
+		if keys.Keyring == nil {
+			return "", ErrNoSecretKey
+		}
+		key, err := keys.Keyring()
+		if err != nil {
+			return "", err
+		}
+		return open(key, data)
+	}
+	return "", errors.New("unknown encryption of the value")
+}
+
+func passphraseKey(passphrase string, salt []byte) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, argonTime, argonMemory, argonThreads, chacha20poly1305.KeySize)
+}
+
+// seal returns the nonce followed by plain encrypted with key.
+func seal(key []byte, plain string) ([]byte, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plain)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, []byte(plain), nil), nil
+}
+
+func open(key, sealed []byte) (string, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("wrong passphrase or key")
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSecretPassphraseRoundTrip(t *testing.T) {
+	encrypted, err := EncryptWithPassphrase("https://hooks.example/token", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSecret(encrypted) || strings.Contains(encrypted, "token") {
+		t.Fatalf("value not encrypted: %q", encrypted)
+	}
+
+	passphrase := "correct horse"
+	asked := 0
+	keys := SecretKeys{Passphrase: func() (string, error) { asked++; return passphrase, nil }}
+	plain, err := DecryptSecret(encrypted, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain != "https://hooks.example/token" || asked != 1 {
+		t.Fatalf("got %q after %d prompts", plain, asked)
+	}
+
+	passphrase = "wrong"
+	if _, err := DecryptSecret(encrypted, keys); err == nil {
+		t.Fatal("expected a wrong passphrase to fail")
+	}
+	if _, err := DecryptSecret(encrypted, SecretKeys{}); !errors.Is(err, ErrNoSecretKey) {
+		t.Fatalf("expected ErrNoSecretKey without a passphrase, got %v", err)
+	}
+}
+
+func TestSecretKeyring(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	asked := 0
+	keys := SecretKeys{Keyring: func() ([]byte, error) { asked++; return key, nil }}
+
+	encrypted, err := EncryptWithKeyring("hunter2", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encrypted, secretKeyring) {
+		t.Fatalf("value not encrypted for the keyring: %q", encrypted)
+	}
+	plain, err := DecryptSecret(encrypted, keys)
+	if err != nil || plain != "hunter2" || asked != 1 {
+		t.Fatalf("got %q, %v after %d keyring reads", plain, err, asked)
+	}
+
+	if _, err := DecryptSecret(encrypted, SecretKeys{}); !errors.Is(err, ErrNoSecretKey) {
+		t.Fatalf("expected ErrNoSecretKey without a keyring, got %v", err)
+	}
+	key[0]++
+	if _, err := DecryptSecret(encrypted, keys); err == nil {
+		t.Fatal("expected another key to fail")
+	}
+}
+
+func TestDecryptSecrets(t *testing.T) {
+	encrypted, err := EncryptWithPassphrase("hunter2", "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	feeurl, err := EncryptWithPassphrase("https://fees.example/key", "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fallback, err := EncryptWithPassphrase("https://fallback.example/key", "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// feeurl and feeurl.fallback are options of the embedded ServiceConfig.
+	path := writeConfig(t, "defaultpassword="+encrypted+"\nalerts.webhook=http://plain.example\n"+
+		"feeurl="+feeurl+"\nfeeurl.fallback=https://plain.example\nfeeurl.fallback="+fallback+"\n")
+	cfg, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DefaultPassword != encrypted {
+		t.Fatal("ReadFile must leave the options encrypted")
+	}
+
+	if err := cfg.DecryptSecrets(SecretKeys{Passphrase: func() (string, error) { return "pass", nil }}); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DefaultPassword != "hunter2" || cfg.AlertWebhook != "http://plain.example" {
+		t.Fatalf("got defaultpassword=%q alerts.webhook=%q", cfg.DefaultPassword, cfg.AlertWebhook)
+	}
+	if cfg.Feeurl != "https://fees.example/key" || !slices.Equal(cfg.FeeURLFallbacks, []string{"https://plain.example", "https://fallback.example/key"}) {
+		t.Fatalf("got feeurl=%q feeurl.fallback=%q", cfg.Feeurl, cfg.FeeURLFallbacks)
+	}
+
+	cfg.DefaultPassword = encrypted
+	err = cfg.DecryptSecrets(SecretKeys{})
+	if err == nil || !strings.HasPrefix(err.Error(), "defaultpassword:") {
+		t.Fatalf("expected the error to name the option, got %v", err)
+	}
+}
+
+func TestSecretOptionsHiddenInChanges(t *testing.T) {
+	old, updated := &AppConfig{}, &AppConfig{}
+	updated.DefaultPassword = "hunter2"
+	changes := Changes(old, updated)
+	if len(changes) != 1 || changes[0].New != hiddenValue || changes[0].Old != hiddenValue {
+		t.Fatalf("secret value leaked in %+v", changes)
+	}
+
+	options := SecretOptions()
+	for _, name := range []string{"defaultpassword", "confirmwebhook", "alerts.webhook", "fiat.url", "feeurl", "feeurl.fallback"} {
+		if !slices.Contains(options, name) {
+			t.Fatalf("%s missing from %v", name, options)
+		}
+	}
+}
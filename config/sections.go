@@ -0,0 +1,207 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+// sectionAliases are section names standing for a network of another name.
+var sectionAliases = map[string]string{"testnet": "testnet3"}
+
+// sectionForbidden are the options a network section cannot set, as they
+// choose the network or the file.
+var sectionForbidden = []string{"network", "testnet", "regtest", "config"}
+
+// profilePrefix starts the name of the [profile.<name>] sections.
+const profilePrefix = "profile."
+
+// profileForbidden are the options a profile section cannot set, as they
+// choose the file or the profile. Unlike a network section, a profile can
+// choose the network.
+var profileForbidden = []string{"config", "profile"}
+
+// ParseFile reads the config file at path into parser, which fills cfg:
+// the options outside sections first, then the [profile.<name>] section of
+// cfg.Profile, then the [<network>] section of the network they and the
+// command line select, over them. A .toml, .yaml or .yml file is read as
+// the INI options it holds, see structuredToINI.
+func ParseFile(parser *flags.Parser, cfg *AppConfig, path string) error {
+	return parseFile(parser, cfg, path, "")
+}
+
+// parseFile is ParseFile reading the section of network, when given, rather
+// than the one of the network the options select.
+func parseFile(parser *flags.Parser, cfg *AppConfig, path, network string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	fileErr := func(err error) error { return fmt.Errorf("%s: %w", path, err) }
+	if format := formatOf(path); format != formatINI {
+		var keys []string
+		if content, keys, err = structuredToINI(format, data); err != nil {
+			return fileErr(err)
+		}
+		fileErr = func(err error) error { return structuredError(path, keys, err) }
+	}
+	common, sections, err := splitSections(content)
+	if err != nil {
+		return fileErr(err)
+	}
+
+	ini := flags.NewIniParser(parser)
+	if err := ini.Parse(strings.NewReader(common)); err != nil {
+		return fileErr(err)
+	}
+	if cfg.Profile != "" {
+		section, ok := sections[profileSection(cfg.Profile)]
+		if !ok {
+			known := "none"
+			if names := profileNames(sections); len(names) > 0 {
+				known = strings.Join(names, ", ")
+			}
+			return fmt.Errorf("%s: no [%s] section, the profiles are: %s", path, profileSection(cfg.Profile), known)
+		}
+		if err := ini.Parse(strings.NewReader(section)); err != nil {
+			return fileErr(err)
+		}
+	}
+	if network == "" {
+		params, err := cfg.ResolveNetwork()
+		if err != nil {
+			return err
+		}
+		network = flnd.NetworkNameOf(params)
+	}
+	if section, ok := sections[network]; ok {
+		if err := ini.Parse(strings.NewReader(section)); err != nil {
+			return fileErr(err)
+		}
+	}
+	return nil
+}
+
+// profileNames returns the profiles sections holds.
+func profileNames(sections map[string]string) []string {
+	var names []string
+	for name := range sections {
+		if profile, ok := strings.CutPrefix(name, profilePrefix); ok {
+			names = append(names, profile)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// profileSection is the section name of a profile, empty without one.
+func profileSection(profile string) string {
+	if profile == "" {
+		return ""
+	}
+	return profilePrefix + strings.ToLower(profile)
+}
+
+// sectionNetwork returns the network a section header line names, if it is
+// a network section.
+func sectionNetwork(line string) (string, bool) {
+	name, ok := sectionHeaderName(line)
+	if !ok {
+		return "", false
+	}
+	if alias, ok := sectionAliases[name]; ok {
+		name = alias
+	}
+	if !slices.Contains(flnd.NetworkNames(), name) {
+		return "", false
+	}
+	return name, true
+}
+
+// sectionName returns the section a header line starts when it is one
+// ParseFile reads: the network of a network section, or profile.<name>.
+func sectionName(line string) (string, bool) {
+	if network, ok := sectionNetwork(line); ok {
+		return network, true
+	}
+	name, ok := sectionHeaderName(line)
+	if !ok || !strings.HasPrefix(name, profilePrefix) || len(name) == len(profilePrefix) {
+		return "", false
+	}
+	return name, true
+}
+
+func sectionHeaderName(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSpace(line[1 : len(line)-1])), true
+}
+
+// isSectionHeader reports whether line starts a section, of a network or
+// not.
+func isSectionHeader(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "[")
+}
+
+// optionKey returns the option an uncommented line assigns.
+func optionKey(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#") {
+		return "", false
+	}
+	name, _, ok := strings.Cut(trimmed, "=")
+	return strings.ToLower(strings.TrimSpace(name)), ok
+}
+
+// splitSections separates the network and profile sections of content from
+// the rest. Every part keeps the lines of the others blank, so an error
+// reports the line number of the file.
+func splitSections(content string) (string, map[string]string, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	common := make([]string, len(lines))
+	parts := map[string][]string{}
+
+	section := ""
+	for i, line := range lines {
+		if name, ok := sectionName(line); ok {
+			section = name
+			if parts[name] == nil {
+				parts[name] = make([]string, len(lines))
+			}
+			continue
+		}
+		if isSectionHeader(line) {
+			section = ""
+		}
+		if section == "" {
+			common[i] = line
+			continue
+		}
+		forbidden := sectionForbidden
+		if strings.HasPrefix(section, profilePrefix) {
+			forbidden = profileForbidden
+		}
+		if key, ok := optionKey(line); ok && slices.Contains(forbidden, key) {
+			return "", nil, fmt.Errorf("line %d: %s cannot be set in the [%s] section", i+1, key, section)
+		}
+		parts[section][i] = line
+	}
+
+	sections := make(map[string]string, len(parts))
+	for name, part := range parts {
+		sections[name] = strings.Join(part, "\n")
+	}
+	return strings.Join(common, "\n"), sections, nil
+}
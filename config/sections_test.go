@@ -0,0 +1,176 @@
+package config
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const sectionsConfig = `loglevel=debug
+addpeer=common:15212
+feeurl=https://fees.example.org
+
+[mainnet]
+addpeer=main:15212
+
+[testnet]
+addpeer=test-a:35212
+addpeer=test-b:35212
+feeurl=https://test-fees.example.org
+walletdir=/tmp/testnet
+`
+
+func TestParseFileNetworkSections(t *testing.T) {
+	path := writeConfig(t, sectionsConfig)
+
+	mainnet, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(mainnet.AddPeers, []string{"main:15212"}) || mainnet.Feeurl != "https://fees.example.org" {
+		t.Fatalf("unexpected mainnet options: peers=%v feeurl=%q", mainnet.AddPeers, mainnet.Feeurl)
+	}
+
+	testnet, err := ReadFile(writeConfig(t, "network=testnet3\n"+sectionsConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(testnet.AddPeers, []string{"test-a:35212", "test-b:35212"}) {
+		t.Fatalf("unexpected testnet peers %v", testnet.AddPeers)
+	}
+	if testnet.Feeurl != "https://test-fees.example.org" || testnet.Walletdir != "/tmp/testnet" || testnet.LogLevel != "debug" {
+		t.Fatalf("unexpected testnet options: %+v", testnet.ServiceConfig)
+	}
+
+	signet, err := ReadFile(writeConfig(t, "network=signet\n"+sectionsConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(signet.AddPeers, []string{"common:15212"}) {
+		t.Fatalf("a network without a section should keep the common options, got %v", signet.AddPeers)
+	}
+}
+
+func TestParseFileRejectsNetworkInSection(t *testing.T) {
+	_, err := ReadFile(writeConfig(t, "[testnet]\nnetwork=mainnet\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected an error on line 2, got %v", err)
+	}
+}
+
+func TestSetNetworkFileOptionValues(t *testing.T) {
+	path := writeConfig(t, sectionsConfig)
+
+	// Without a testnet override the common value changes, and the other
+	// networks keep theirs.
+	if err := SetNetworkFileOptionValues(path, "testnet3", "loglevel", []string{"warn"}); err != nil {
+		t.Fatal(err)
+	}
+	// The testnet section overrides addpeer, so it is changed there.
+	if err := SetNetworkFileOptionValues(path, "testnet3", "addpeer", []string{"test-c:35212"}); err != nil {
+		t.Fatal(err)
+	}
+	// SetFileOptionValues leaves the sections alone.
+	if err := SetFileOptionValues(path, "feeurl", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `loglevel=warn
+addpeer=common:15212
+
+[mainnet]
+addpeer=main:15212
+
+[testnet]
+addpeer=test-c:35212
+feeurl=https://test-fees.example.org
+walletdir=/tmp/testnet
+`
+	if got := readFile(t, path); got != want {
+		t.Fatalf("unexpected file content:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+const profilesConfig = `walletdir=/tmp/main
+addpeer=common:15212
+loglevel=info
+
+[profile.Test]
+network=testnet3
+walletdir=/tmp/test
+addpeer=test-a:35212
+
+[testnet]
+loglevel=debug
+`
+
+func TestParseFileProfiles(t *testing.T) {
+	path := writeConfig(t, profilesConfig)
+
+	cfg, err := ReadProfile(path, "test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.NetworkName != "testnet3" || cfg.Walletdir != "/tmp/test" || !slices.Equal(cfg.AddPeers, []string{"test-a:35212"}) {
+		t.Fatalf("profile not applied: network=%q walletdir=%q peers=%v", cfg.NetworkName, cfg.Walletdir, cfg.AddPeers)
+	}
+	if cfg.LogLevel != "debug" {
+		t.Fatalf("the section of the network the profile chose should apply, got loglevel=%q", cfg.LogLevel)
+	}
+
+	cfg, err = ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Walletdir != "/tmp/main" || cfg.LogLevel != "info" {
+		t.Fatalf("without a profile its section should be left out: walletdir=%q loglevel=%q", cfg.Walletdir, cfg.LogLevel)
+	}
+
+	if _, err := ReadProfile(path, "work", ""); err == nil || !strings.Contains(err.Error(), "the profiles are: test") {
+		t.Fatalf("expected an unknown profile error, got %v", err)
+	}
+	if _, err := ReadFile(writeConfig(t, "[profile.a]\nprofile=b\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected an error on line 2, got %v", err)
+	}
+}
+
+func TestSaveOptionInProfile(t *testing.T) {
+	path := writeConfig(t, profilesConfig)
+	cfg := &AppConfig{ConfigFile: path, Profile: "test"}
+	cfg.NetworkName = "testnet3"
+
+	// The profile sets walletdir, the file nowhere else after it.
+	if err := cfg.SaveOption("walletdir", "/tmp/other"); err != nil {
+		t.Fatal(err)
+	}
+	// The network section overrides the profile.
+	if err := cfg.SaveOption("loglevel", "warn"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `walletdir=/tmp/main
+addpeer=common:15212
+loglevel=info
+
+[profile.Test]
+network=testnet3
+walletdir=/tmp/other
+addpeer=test-a:35212
+
+[testnet]
+loglevel=warn
+`
+	if got := readFile(t, path); got != want {
+		t.Fatalf("unexpected file content:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/flokiorg/twallet/shared"
+)
+
+// ResolveTheme loads the configured preset and applies any per-color
+// overrides on top of it.
+func (c *AppConfig) ResolveTheme() (shared.Theme, error) {
+	name := c.Theme
+	if name == "" {
+		name = shared.DefaultThemeName
+	}
+
+	theme, err := shared.ThemePreset(name)
+	if err != nil {
+		return shared.Theme{}, err
+	}
+
+	overrides := []struct {
+		option string
+		value  string
+		target *tcell.Color
+	}{
+		{"theme.background", c.ThemeBackground, &theme.Background},
+		{"theme.text", c.ThemeText, &theme.Text},
+		{"theme.border", c.ThemeBorder, &theme.Border},
+		{"theme.accent", c.ThemeAccent, &theme.Accent},
+		{"theme.modal", c.ThemeModal, &theme.Modal},
+		{"theme.selection", c.ThemeSelection, &theme.Selection},
+	}
+
+	for _, o := range overrides {
+		if o.value == "" {
+			continue
+		}
+		color, err := shared.ParseThemeColor(o.value)
+		if err != nil {
+			return shared.Theme{}, fmt.Errorf("%s: %w", o.option, err)
+		}
+		*o.target = color
+	}
+
+	return theme, nil
+}
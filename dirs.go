@@ -0,0 +1,91 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/flokiorg/twallet/config"
+	. "github.com/flokiorg/twallet/utils"
+	"golang.org/x/term"
+)
+
+// defaultConfigPath is the first config file found in the working
+// directory, as before the XDG layout, then in the config directory, under
+// one of config.FileNames. Without one, it is twallet.conf in the config
+// directory.
+func defaultConfigPath(dirs config.Dirs) (string, error) {
+	wd, err := GetFullPath("")
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range []string{wd, dirs.Config} {
+		for _, name := range config.FileNames {
+			if path := filepath.Join(dir, name); FileExists(path) {
+				return path, nil
+			}
+		}
+	}
+	return filepath.Join(dirs.Config, config.FileNames[0]), nil
+}
+
+// defaultWalletDir returns the XDG data directory, or the legacy one when
+// the wallet is still there. Moving it is offered on a terminal unless
+// twallet only reports; declined, the legacy directory stays in use.
+func defaultWalletDir(dirs config.Dirs, reportOnly bool) string {
+	legacy := config.LegacyDataDir()
+	if !config.NeedsMigration(legacy, dirs.Data) {
+		return dirs.Data
+	}
+	if reportOnly || !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintf(notices, "Using the wallet in %s, start twallet on a terminal to move it to %s.\n", legacy, dirs.Data)
+		return legacy
+	}
+
+	p := &prompter{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+	fmt.Fprintf(p.out, "The wallet is in %s, the directory used before twallet followed the XDG layout.\n", legacy)
+	fmt.Fprintf(p.out, "Moving it puts the wallet in %s and twallet.log in %s.\n", dirs.Data, dirs.State)
+	answer, err := p.choose("Move it now", "yes", "yes", "no")
+	if err != nil {
+		return legacy
+	}
+	if answer == "no" {
+		if err := config.DeclineMigration(legacy); err != nil {
+			fmt.Fprintf(notices, "failed to remember the answer: %v\n", err)
+		}
+		fmt.Fprintf(p.out, "Keeping %s, pass --walletdir to choose another directory.\n\n", legacy)
+		return legacy
+	}
+	if err := config.MigrateDataDir(legacy, dirs.Data, dirs.State); err != nil {
+		fmt.Fprintf(notices, "failed to move the wallet, keeping %s: %v\n\n", legacy, err)
+		return legacy
+	}
+	fmt.Fprintf(p.out, "Moved to %s.\n\n", dirs.Data)
+	return dirs.Data
+}
+
+// logDir is where twallet.log goes: --logdir, the state directory next to
+// the default wallet directory, or the wallet directory itself.
+func logDir(opts *cliOptions, dirs config.Dirs) string {
+	switch {
+	case opts.LogDir != "":
+		return opts.LogDir
+	case opts.Walletdir == dirs.Data:
+		return dirs.State
+	}
+	return opts.Walletdir
+}
+
+// metadataDir is where twallet keeps its own state: --metadatadir, or
+// metadata in the wallet directory, which the daemon does not touch.
+func metadataDir(opts *cliOptions) string {
+	if opts.MetadataDir != "" {
+		return opts.MetadataDir
+	}
+	return filepath.Join(opts.Walletdir, "metadata")
+}
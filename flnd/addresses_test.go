@@ -0,0 +1,55 @@
+package flnd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flokiorg/flnd/lnrpc/walletrpc"
+	"google.golang.org/grpc"
+)
+
+type countingWalletKit struct {
+	walletrpc.WalletKitClient
+	calls int
+}
+
+func (k *countingWalletKit) ListAddresses(ctx context.Context, in *walletrpc.ListAddressesRequest, opts ...grpc.CallOption) (*walletrpc.ListAddressesResponse, error) {
+	k.calls++
+	return &walletrpc.ListAddressesResponse{
+		AccountWithAddresses: []*walletrpc.AccountWithAddresses{{Name: "default"}},
+	}, nil
+}
+
+func TestListAddressesCache(t *testing.T) {
+	kit := &countingWalletKit{}
+	c := &Client{walletKit: kit, ctx: context.Background()}
+
+	list := func(opts ListAddressesOptions) {
+		t.Helper()
+		accounts, err := c.ListAddressesWithOptions(context.Background(), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(accounts) != 1 {
+			t.Fatalf("accounts = %v", accounts)
+		}
+	}
+
+	list(ListAddressesOptions{})
+	list(ListAddressesOptions{})
+	if kit.calls != 1 {
+		t.Fatalf("calls = %d, want 1", kit.calls)
+	}
+
+	list(ListAddressesOptions{ForceRefresh: true})
+	if kit.calls != 2 {
+		t.Fatalf("calls after forced refresh = %d, want 2", kit.calls)
+	}
+
+	c.invalidateAddressCache()
+	list(ListAddressesOptions{})
+	list(ListAddressesOptions{})
+	if kit.calls != 3 {
+		t.Fatalf("calls after invalidation = %d, want 3", kit.calls)
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/flokiorg/flnd"
+)
+
+// maxAliasLength is the longest node alias the daemon accepts, in bytes.
+const maxAliasLength = 32
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+// ConfigChange is a change of the daemon settings that ApplyConfig can make
+// without quitting twallet. Nil fields keep their value.
+type ConfigChange struct {
+	ConnectPeers     *[]string
+	AddPeers         *[]string
+	Feeurl           *string
+	Alias            *string
+	RawRPCListeners  *[]string
+	RawRESTListeners *[]string
+	RawListeners     *[]string
+}
+
+// ConfigOption is a changed setting by its name in the config file. Options
+// that can be repeated have a value per line.
+type ConfigOption struct {
+	Key    string
+	Values []string
+}
+
+// Validate reports the first setting of the change the daemon would not
+// start with.
+func (c ConfigChange) Validate() error {
+	check := func(name string, values *[]string, valid func(string) error) error {
+		if values == nil {
+			return nil
+		}
+		for _, v := range *values {
+			if err := valid(v); err != nil {
+				return fmt.Errorf("%w: %s %q: %v", ErrInvalidConfig, name, v, err)
+			}
+		}
+		return nil
+	}
+
+	if err := check("connect", c.ConnectPeers, ValidatePeer); err != nil {
+		return err
+	}
+	if err := check("addpeer", c.AddPeers, ValidatePeer); err != nil {
+		return err
+	}
+	if err := check("rpclisten", c.RawRPCListeners, ValidateListener); err != nil {
+		return err
+	}
+	if err := check("restlisten", c.RawRESTListeners, ValidateListener); err != nil {
+		return err
+	}
+	if err := check("listen", c.RawListeners, ValidateListener); err != nil {
+		return err
+	}
+	if c.RawRPCListeners != nil && len(*c.RawRPCListeners) == 0 {
+		return fmt.Errorf("%w: rpclisten: twallet needs an RPC listener to reach the daemon", ErrInvalidConfig)
+	}
+	if c.Feeurl != nil && *c.Feeurl != "" {
+		u, err := url.Parse(*c.Feeurl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: feeurl %q is not an http(s) URL", ErrInvalidConfig, *c.Feeurl)
+		}
+	}
+	if c.Alias != nil && len(*c.Alias) > maxAliasLength {
+		return fmt.Errorf("%w: alias is longer than %d bytes", ErrInvalidConfig, maxAliasLength)
+	}
+	return nil
+}
+
+// Options returns the changed settings as they are written in the config
+// file.
+func (c ConfigChange) Options() []ConfigOption {
+	var options []ConfigOption
+	addList := func(key string, values *[]string) {
+		if values != nil {
+			options = append(options, ConfigOption{Key: key, Values: *values})
+		}
+	}
+	addValue := func(key string, value *string) {
+		if value == nil {
+			return
+		}
+		var values []string
+		if *value != "" {
+			values = []string{*value}
+		}
+		options = append(options, ConfigOption{Key: key, Values: values})
+	}
+
+	addList("connect", c.ConnectPeers)
+	addList("addpeer", c.AddPeers)
+	addValue("feeurl", c.Feeurl)
+	addValue("alias", c.Alias)
+	addList("rpclisten", c.RawRPCListeners)
+	addList("restlisten", c.RawRESTListeners)
+	addList("listen", c.RawListeners)
+	return options
+}
+
+// ApplyTo sets the changed settings in cfg, so a service created from it
+// later starts with them.
+func (c ConfigChange) ApplyTo(cfg *ServiceConfig) {
+	setList(&cfg.ConnectPeers, c.ConnectPeers)
+	setList(&cfg.AddPeers, c.AddPeers)
+	setList(&cfg.RawRPCListeners, c.RawRPCListeners)
+	setList(&cfg.RawRESTListeners, c.RawRESTListeners)
+	setList(&cfg.RawListeners, c.RawListeners)
+	if c.Feeurl != nil {
+		cfg.Feeurl = *c.Feeurl
+	}
+	if c.Alias != nil {
+		cfg.Alias = *c.Alias
+	}
+}
+
+// applyToDaemon sets the changed settings in the daemon config, like New
+// does from a ServiceConfig.
+func (c ConfigChange) applyToDaemon(conf *flnd.Config) {
+	setList(&conf.NeutrinoMode.ConnectPeers, c.ConnectPeers)
+	setList(&conf.NeutrinoMode.AddPeers, c.AddPeers)
+	setList(&conf.RawRPCListeners, c.RawRPCListeners)
+	setList(&conf.RawRESTListeners, c.RawRESTListeners)
+	setList(&conf.RawListeners, c.RawListeners)
+	if c.Feeurl != nil {
+		conf.Fee.URL = *c.Feeurl
+	}
+	if c.Alias != nil {
+		conf.Alias = *c.Alias
+		if conf.Alias == "" {
+			conf.Alias = flnd.DefaultConfig().Alias
+		}
+	}
+}
+
+func setList(dst *[]string, values *[]string) {
+	if values != nil {
+		*dst = append([]string(nil), (*values)...)
+	}
+}
+
+// ApplyConfig validates change, saves it with persist and restarts the
+// daemon with it, reporting each step to progress. It returns once the
+// restarted daemon is up, usually with the wallet locked, or failed to
+// start.
+func (s *Service) ApplyConfig(ctx context.Context, change ConfigChange, persist func(ConfigOption) error, progress func(string)) error {
+	if progress == nil {
+		progress = func(string) {}
+	}
+	if err := change.Validate(); err != nil {
+		return err
+	}
+
+	progress("Saving settings…")
+	for _, option := range change.Options() {
+		if err := persist(option); err != nil {
+			return fmt.Errorf("failed to save %s: %w", option.Key, err)
+		}
+	}
+
+	// Subscribe first so the restart cannot go unseen. The first update is
+	// the state from before it.
+	updates := s.SubscribeFiltered(StatusNone, StatusLocked, StatusNoWallet, StatusUnlocked,
+		StatusSyncing, StatusReady, StatusDown, StatusFailedPermanently)
+	defer s.Unsubscribe(updates)
+
+	s.configMu.Lock()
+	s.applyChange(change)
+	s.configMu.Unlock()
+
+	progress("Stopping the wallet daemon…")
+	s.Restart(ctx)
+
+	restarted := false
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case u, ok := <-updates:
+			if !ok {
+				return ErrDaemonNotRunning
+			}
+			switch {
+			case u.State == StatusNone:
+				if !restarted {
+					progress("Starting the wallet daemon…")
+				}
+				restarted = true
+
+			case u.State == StatusFailedPermanently:
+				return fmt.Errorf("daemon failed to start with the new settings: %w", u.Err)
+
+			case !restarted:
+				// The state from before the restart.
+
+			case u.State == StatusDown:
+				if u.Err != nil {
+					return fmt.Errorf("daemon failed to start with the new settings: %w", u.Err)
+				}
+
+			default:
+				progress("Wallet daemon restarted.")
+				return nil
+			}
+		}
+	}
+}
+
+// QueueConfig validates change and sets it in the daemon config without
+// restarting it, so the daemon starts with it the next time it does.
+func (s *Service) QueueConfig(change ConfigChange) error {
+	if err := change.Validate(); err != nil {
+		return err
+	}
+	s.configMu.Lock()
+	s.applyChange(change)
+	s.configMu.Unlock()
+	return nil
+}
+
+// ValidatePeer reports why the daemon would not accept addr as a peer, a
+// host with an optional port.
+func ValidatePeer(addr string) error {
+	if addr == "" || strings.TrimSpace(addr) != addr {
+		return errors.New("empty or padded address")
+	}
+	if strings.ContainsAny(addr, " /") {
+		return errors.New("invalid host")
+	}
+	if _, _, err := net.SplitHostPort(addr); err == nil {
+		return validHostPort(addr, false)
+	}
+	// Without a port the daemon uses the default one of the network.
+	return nil
+}
+
+// ValidateListener reports why the daemon could not listen on addr, a
+// host:port or a unix:// socket.
+func ValidateListener(addr string) error {
+	if strings.HasPrefix(addr, "unix://") {
+		if len(addr) == len("unix://") {
+			return errors.New("empty socket path")
+		}
+		return nil
+	}
+	return validHostPort(addr, true)
+}
+
+func validHostPort(addr string, emptyHost bool) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if host == "" && !emptyHost {
+		return errors.New("missing host")
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n <= 0 || n > 65535 {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	return nil
+}
+
+// applyChange sets change in the daemon config, the fee URL through the
+// failover and the peers with the ones of the DNS seeds. configMu is held.
+func (s *Service) applyChange(change ConfigChange) {
+	change.applyToDaemon(s.flndConfig)
+	if change.AddPeers != nil {
+		s.flndConfig.NeutrinoMode.AddPeers = appendSeedPeers(s.flndConfig.NeutrinoMode.AddPeers, s.seedPeers)
+	}
+	if change.Feeurl != nil {
+		s.flndConfig.Fee.URL = s.fees.route(FeeSources(*change.Feeurl, s.feeFallbacks))
+	}
+}
@@ -0,0 +1,127 @@
+package flnd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/flokiorg/flnd"
+)
+
+func TestConfigChangeValidate(t *testing.T) {
+	list := func(v ...string) *[]string { return &v }
+	str := func(v string) *string { return &v }
+
+	valid := []ConfigChange{
+		{},
+		{ConnectPeers: list("node.example.org", "10.0.0.1:15212", "[::1]:15212")},
+		{RawListeners: list("0.0.0.0:5521", ":5521"), RawRPCListeners: list("unix:///tmp/flnd.sock")},
+		{Feeurl: str(""), Alias: str("")},
+		{Feeurl: str("https://lokichain.info/api/v1/fees/recommended"), Alias: str("MyLokiNode")},
+	}
+	for i, c := range valid {
+		if err := c.Validate(); err != nil {
+			t.Errorf("valid change %d: %v", i, err)
+		}
+	}
+
+	invalid := []ConfigChange{
+		{AddPeers: list("")},
+		{AddPeers: list("host:0")},
+		{AddPeers: list("bad host:15212")},
+		{ConnectPeers: list(":15212")},
+		{RawListeners: list("0.0.0.0")},
+		{RawRESTListeners: list("localhost:http")},
+		{RawRPCListeners: list()},
+		{Feeurl: str("lokichain.info/fees")},
+		{Alias: str("an alias much longer than thirty two bytes")},
+	}
+	for i, c := range invalid {
+		if err := c.Validate(); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("invalid change %d = %v, want ErrInvalidConfig", i, err)
+		}
+	}
+}
+
+func TestConfigChangeOptions(t *testing.T) {
+	peers := []string{"a:1", "b:2"}
+	alias, feeurl := "", "https://fees.example.org"
+	c := ConfigChange{AddPeers: &peers, Alias: &alias, Feeurl: &feeurl}
+
+	got := c.Options()
+	want := []ConfigOption{
+		{Key: "addpeer", Values: peers},
+		{Key: "feeurl", Values: []string{feeurl}},
+		{Key: "alias"},
+	}
+	if !slices.EqualFunc(got, want, func(a, b ConfigOption) bool {
+		return a.Key == b.Key && slices.Equal(a.Values, b.Values)
+	}) {
+		t.Fatalf("options = %+v, want %+v", got, want)
+	}
+
+	cfg := ServiceConfig{AddPeers: []string{"old:1"}, Alias: "old", ConnectPeers: []string{"kept:1"}}
+	c.ApplyTo(&cfg)
+	if !slices.Equal(cfg.AddPeers, peers) || cfg.Alias != "" || cfg.Feeurl != feeurl || !slices.Equal(cfg.ConnectPeers, []string{"kept:1"}) {
+		t.Fatalf("applied config = %+v", cfg)
+	}
+}
+
+func TestApplyConfigRestarts(t *testing.T) {
+	conf := flnd.DefaultConfig()
+	svc := &Service{lastEvent: &Update{State: StatusInit}, flndConfig: &conf}
+	svc.notifySubscribers(&Update{State: StatusReady})
+
+	peers := []string{"peer.example.org:15212"}
+	var saved []ConfigOption
+	var steps []string
+
+	// The service has no daemon to restart: play the updates of a restart.
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		svc.notifySubscribers(&Update{State: StatusDown})
+		svc.notifySubscribers(&Update{State: StatusNone})
+		svc.notifySubscribers(&Update{State: StatusLocked})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := svc.ApplyConfig(ctx, ConfigChange{AddPeers: &peers}, func(o ConfigOption) error {
+		saved = append(saved, o)
+		return nil
+	}, func(step string) {
+		steps = append(steps, step)
+	})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if len(saved) != 1 || saved[0].Key != "addpeer" {
+		t.Fatalf("saved = %+v, want the addpeer option", saved)
+	}
+	if !slices.Equal(svc.cloneConfig().NeutrinoMode.AddPeers, peers) {
+		t.Fatalf("daemon peers = %v, want %v", svc.cloneConfig().NeutrinoMode.AddPeers, peers)
+	}
+	if len(steps) == 0 || steps[len(steps)-1] != "Wallet daemon restarted." {
+		t.Fatalf("progress = %v", steps)
+	}
+}
+
+func TestApplyConfigStartFailure(t *testing.T) {
+	conf := flnd.DefaultConfig()
+	svc := &Service{lastEvent: &Update{State: StatusInit}, flndConfig: &conf}
+	boom := errors.New("listen: address in use")
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		svc.notifySubscribers(&Update{State: StatusNone})
+		svc.notifySubscribers(&Update{State: StatusDown, Err: boom})
+	}()
+
+	listeners := []string{"0.0.0.0:5521"}
+	err := svc.ApplyConfig(context.Background(), ConfigChange{RawListeners: &listeners}, func(ConfigOption) error { return nil }, nil)
+	if !errors.Is(err, boom) {
+		t.Fatalf("apply = %v, want the start error", err)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"context"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// balanceDebounce lets a burst of transaction and block updates settle into
+// a single balance call.
+const balanceDebounce = 750 * time.Millisecond
+
+// RefreshBalance asks for the balance to be fetched again. Subscribers to
+// StatusBalance receive it if it changed.
+func (s *Service) RefreshBalance() {
+	select {
+	case s.balanceKick <- struct{}{}:
+	default:
+	}
+}
+
+// trackBalance fetches the balance when asked and publishes it when it
+// changed.
+func (s *Service) trackBalance() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.balanceKick:
+		}
+
+		timer := time.NewTimer(balanceDebounce)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		ctx, cancel := context.WithTimeout(s.ctx, s.timeouts().calls())
+		balance, err := s.Balance(ctx)
+		cancel()
+		if err != nil {
+			continue
+		}
+		s.publishBalance(balance)
+	}
+}
+
+func (s *Service) publishBalance(balance *lnrpc.WalletBalanceResponse) {
+	s.subMu.Lock()
+	same := s.balanceEvent != nil && proto.Equal(s.balanceEvent.Balance, balance)
+	s.subMu.Unlock()
+	if same {
+		return
+	}
+	s.notifySubscribers(&Update{State: StatusBalance, Balance: balance})
+}
@@ -9,22 +9,23 @@ import (
 	"math"
 	"net"
 	"os"
-	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/flokiorg/flnd"
 	"github.com/flokiorg/flnd/aezeed"
 	"github.com/flokiorg/flnd/lnrpc"
 	"github.com/flokiorg/flnd/lnrpc/chainrpc"
+	"github.com/flokiorg/flnd/lnrpc/neutrinorpc"
 	"github.com/flokiorg/flnd/lnrpc/walletrpc"
-	"github.com/flokiorg/flnd/rpcperms"
+	"github.com/flokiorg/go-flokicoin/chaincfg/chainhash"
 	"github.com/flokiorg/go-flokicoin/chainutil"
 	"github.com/flokiorg/go-flokicoin/chainutil/psbt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
-	"google.golang.org/grpc/status"
 )
 
 var (
@@ -37,11 +38,31 @@ var (
 )
 
 type txCache struct {
-	Txs         []*lnrpc.Transaction
+	Txs         txIndex
 	LastIndex   uint64 // Index of the newest cached transaction in lnd
 	NextOffset  uint32 // Offset to fetch the next page (total cached count)
 	LastUpdated time.Time
 	Dirty       bool
+
+	// Truncated is set once transactions were evicted to stay under the cache
+	// limit; fetching them all pages through the history again.
+	Truncated bool
+}
+
+// TxCacheStats describes the transaction cache of a client.
+type TxCacheStats struct {
+	Transactions int
+	Bytes        int // estimated memory use
+	Limit        int // 0 when unlimited
+	Truncated    bool
+}
+
+// addressCache holds the last ListAddresses result. It is dirty once a
+// transaction or a new address may have changed it.
+type addressCache struct {
+	Accounts    []*walletrpc.AccountWithAddresses
+	LastUpdated time.Time
+	Dirty       bool
 }
 
 type Client struct {
@@ -51,6 +72,7 @@ type Client struct {
 	stateClient    lnrpc.StateClient
 	ntfClient      chainrpc.ChainNotifierClient
 	chainKit       chainrpc.ChainKitClient
+	neutrinoKit    neutrinorpc.NeutrinoKitClient
 
 	health      chan *Update
 	config      *flnd.Config
@@ -59,16 +81,23 @@ type Client struct {
 
 	subTxsOnce sync.Once
 	cache      *txCache
+	cachePath  string
+	addrCache  *addressCache
+	feeCache   map[int32]feeEstimate
 	closing    bool
 
-	syncPollingActive bool
-	syncPollingStop   chan struct{}
-	syncPollingDone   chan struct{}
-	isSynced          bool
-	syncedHeight      uint32
-	mu                sync.Mutex
+	state           stateMachine
+	syncPollingStop chan struct{}
+	mu              sync.Mutex
 
 	txFetchLimit uint32
+	txCacheLimit int
+	timeouts     atomic.Pointer[RPCTimeouts]
+}
+
+type ListAddressesOptions struct {
+	// ForceRefresh skips the cache and lists the addresses again.
+	ForceRefresh bool
 }
 
 type FetchTransactionsOptions struct {
@@ -81,7 +110,9 @@ const (
 	defaultRPCTimeout       = 5 * time.Second
 	transactionFetchTimeout = 30 * time.Second
 	transactionPageSize     = 200
+	transactionFetchWorkers = 4
 	transactionsCacheTTL    = 5 * time.Minute
+	addressesCacheTTL       = 5 * time.Minute
 	recentHeaderThreshold   = 5 * time.Minute
 
 	localhostIP           = "127.0.0.1"
@@ -96,17 +127,27 @@ func NewClient(ctx context.Context, conn *grpc.ClientConn, config *flnd.Config)
 		stateClient:    lnrpc.NewStateClient(conn),
 		ntfClient:      chainrpc.NewChainNotifierClient(conn),
 		chainKit:       chainrpc.NewChainKitClient(conn),
+		neutrinoKit:    neutrinorpc.NewNeutrinoKitClient(conn),
 		// Buffer health updates to avoid dropping important state transitions
 		health: make(chan *Update, 16),
 		ctx:    ctx,
 		config: config,
 		cache: &txCache{
-			Txs:         make([]*lnrpc.Transaction, 0),
 			LastUpdated: time.Time{},
 			Dirty:       true,
 		},
+		cachePath: txCachePath(config),
 	}
 
+	// Start from the saved transactions so the first fetch only pages
+	// through the newer ones, unless the daemon is about to rescan them.
+	if config.ResetWalletTransactions {
+		_ = removeTxCache(c.cachePath)
+	} else if cache, err := loadTxCache(c.cachePath); err == nil {
+		c.cache = cache
+	}
+
+	c.state.OnTransition(c.trackSyncPolling)
 	go c.subscribeState()
 
 	return c
@@ -128,7 +169,8 @@ func (c *Client) subscribeTransactions() {
 		}
 
 		c.invalidateTxCache()
-		c.submitHealth(Update{State: StatusTransaction, Transaction: r})
+		c.invalidateAddressCache()
+		c.state.Event(Update{State: StatusTransaction, Transaction: r}, c.submitHealth)
 	}
 }
 
@@ -141,6 +183,28 @@ func (c *Client) invalidateTxCache() {
 	}
 }
 
+func (c *Client) invalidateAddressCache() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.addrCache != nil {
+		c.addrCache.Dirty = true
+	}
+}
+
+// resetTxCache forgets the cached and saved transactions, before a new wallet
+// replaces the current one.
+func (c *Client) resetTxCache() {
+	c.mu.Lock()
+	c.cache = &txCache{
+		Dirty: true,
+	}
+	c.addrCache = nil
+	c.mu.Unlock()
+
+	_ = removeTxCache(c.cachePath)
+}
+
 func (c *Client) subscribeBlocks() {
 
 	stream, err := c.ntfClient.RegisterBlockEpochNtfn(c.withMacaroon(), &chainrpc.BlockEpoch{})
@@ -149,6 +213,8 @@ func (c *Client) subscribeBlocks() {
 		return
 	}
 
+	blocks := newBlockTracker()
+
 	for {
 		r, err := stream.Recv()
 		if err != nil {
@@ -156,23 +222,13 @@ func (c *Client) subscribeBlocks() {
 			return
 		}
 
-		state := StatusBlock
-		var syncedHeight uint32
-
-		c.mu.Lock()
-		if !c.isSynced {
-			state = StatusScanning
-			syncedHeight = c.syncedHeight
-		}
-		c.mu.Unlock()
-
+		blockHash := hex.EncodeToString(r.Hash)
 		c.invalidateTxCache()
-		c.submitHealth(Update{
-			State:        state,
-			SyncedHeight: syncedHeight,
-			BlockHeight:  r.Height,
-			BlockHash:    hex.EncodeToString(r.Hash),
-		})
+		c.state.Block(Update{
+			BlockHeight: r.Height,
+			BlockHash:   blockHash,
+			Reorg:       blocks.add(r.Height, blockHash),
+		}, c.submitHealth)
 	}
 }
 
@@ -192,10 +248,10 @@ func (c *Client) subscribeState() {
 
 		switch r.State {
 		case lnrpc.WalletState_NON_EXISTING:
-			c.submitHealth(Update{State: StatusNoWallet})
+			c.transition(Update{State: StatusNoWallet})
 
 		case lnrpc.WalletState_LOCKED:
-			c.submitHealth(Update{State: StatusLocked})
+			c.transition(Update{State: StatusLocked})
 
 		case lnrpc.WalletState_UNLOCKED:
 			adminMacHex, err := readMacaroon(c.config.AdminMacPath)
@@ -204,20 +260,23 @@ func (c *Client) subscribeState() {
 				return
 			}
 			c.adminMacHex = adminMacHex
-			c.submitHealth(Update{State: StatusUnlocked})
+			c.transition(Update{State: StatusUnlocked})
 
 		case lnrpc.WalletState_WAITING_TO_START:
-			c.submitHealth(Update{State: StatusNone})
+			c.transition(Update{State: StatusNone})
 
 		case lnrpc.WalletState_RPC_ACTIVE:
 			synced, _, blockHeight, err := c.IsSynced()
 			if err != nil {
 				continue
 			} else if synced {
-				c.submitHealth(Update{State: StatusNone, BlockHeight: blockHeight})
+				c.transition(Update{State: StatusNone, BlockHeight: blockHeight})
 			} else {
-				c.submitHealth(Update{State: StatusSyncing, BlockHeight: blockHeight})
-				go c.pollSyncStatus()
+				c.transition(Update{
+					State:               StatusSyncing,
+					BlockHeight:         blockHeight,
+					BestHeaderTimestamp: c.state.HeaderTimestamp(),
+				})
 			}
 
 		case lnrpc.WalletState_SERVER_ACTIVE:
@@ -227,8 +286,7 @@ func (c *Client) subscribeState() {
 				return
 			}
 
-			c.stopSyncPolling()
-			c.submitHealth(Update{State: StatusReady, BlockHeight: blockHeight})
+			c.transition(Update{State: StatusReady, BlockHeight: blockHeight})
 
 			c.subTxsOnce.Do(func() {
 				go c.subscribeTransactions()
@@ -240,6 +298,17 @@ func (c *Client) subscribeState() {
 	}
 }
 
+// State returns the lifecycle state of the wallet.
+func (c *Client) State() Status {
+	return c.state.Current()
+}
+
+// transition moves the wallet to the state of u and publishes u, unless the
+// current state cannot move there.
+func (c *Client) transition(u Update) bool {
+	return c.state.Transition(u, c.submitHealth)
+}
+
 func (c *Client) LoadMacaroon(path string) error {
 	adminMacHex, err := readMacaroon(path)
 	if err != nil {
@@ -249,52 +318,34 @@ func (c *Client) LoadMacaroon(path string) error {
 	return nil
 }
 
-func (c *Client) stopSyncPolling() {
-	var done chan struct{}
-
+// trackSyncPolling polls the sync progress while the wallet is syncing.
+func (c *Client) trackSyncPolling(from, to Status) {
 	c.mu.Lock()
-	if c.syncPollingActive && c.syncPollingStop != nil {
+	defer c.mu.Unlock()
+
+	switch {
+	case to == StatusSyncing && c.syncPollingStop == nil:
+		c.syncPollingStop = make(chan struct{})
+		go c.pollSyncStatus(c.syncPollingStop)
+
+	case to != StatusSyncing && c.syncPollingStop != nil:
 		close(c.syncPollingStop)
 		c.syncPollingStop = nil
-		done = c.syncPollingDone
-	}
-	c.mu.Unlock()
-
-	if done != nil {
-		<-done
 	}
 }
 
-func (c *Client) pollSyncStatus() {
-	c.mu.Lock()
-	if c.syncPollingActive {
-		c.mu.Unlock()
-		return
-	}
-	stopCh := make(chan struct{})
-	doneCh := make(chan struct{})
-	c.syncPollingActive = true
-	c.syncPollingStop = stopCh
-	c.syncPollingDone = doneCh
-	c.mu.Unlock()
-
+// pollSyncStatus reports the sync progress until the wallet is synced or
+// stop closes.
+func (c *Client) pollSyncStatus(stop <-chan struct{}) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
-	defer func() {
-		c.mu.Lock()
-		c.syncPollingActive = false
-		c.syncPollingStop = nil
-		c.syncPollingDone = nil
-		c.mu.Unlock()
-		close(doneCh)
-	}()
 
 	for {
 		select {
 		case <-c.ctx.Done():
 			return
 
-		case <-stopCh:
+		case <-stop:
 			return
 
 		case <-ticker.C:
@@ -303,22 +354,20 @@ func (c *Client) pollSyncStatus() {
 				continue
 			}
 
-			recoveryInfo, recoveryErr := c.GetRecoveryInfo()
-			if recoveryErr == nil && recoveryInfo != nil && recoveryInfo.RecoveryMode {
-				if recoveryInfo.RecoveryMode && recoveryInfo.RecoveryFinished {
-					c.submitHealth(Update{State: StatusReady, BlockHeight: blockHeight})
-					return
-				}
+			recoveryInfo, recoveryErr := c.GetRecoveryInfo(c.ctx)
+			if recoveryErr == nil && recoveryInfo != nil && recoveryInfo.RecoveryMode && recoveryInfo.RecoveryFinished {
+				synced = true
 			}
 
 			if synced {
-				c.submitHealth(Update{State: StatusReady, BlockHeight: blockHeight})
+				c.transition(Update{State: StatusReady, BlockHeight: blockHeight})
 				return
 			}
 
-			c.submitHealth(Update{
-				State:       StatusSyncing,
-				BlockHeight: blockHeight,
+			c.transition(Update{
+				State:               StatusSyncing,
+				BlockHeight:         blockHeight,
+				BestHeaderTimestamp: c.state.HeaderTimestamp(),
 			})
 		}
 	}
@@ -329,30 +378,43 @@ func (c *Client) close() {
 }
 
 func (c *Client) kill(err error) {
-	if matchRPCErrorMessage(err, context.Canceled) || c.closing {
-		c.submitHealth(Update{State: StatusDown})
+	if isRPCError(err, context.Canceled) || c.closing {
+		c.transition(Update{State: StatusDown})
 	} else {
-		c.submitHealth(Update{State: StatusDown, Err: err})
+		c.transition(Update{State: StatusDown, Err: err})
 	}
 }
 
+// submitHealth hands change to the service. Routine updates are dropped when
+// the channel is full; critical ones wait for room, for a while.
 func (c *Client) submitHealth(change Update) {
 	select {
 	case c.health <- &change:
+		return
 	default:
 	}
+	if !change.critical() {
+		return
+	}
+
+	timer := time.NewTimer(criticalHealthTimeout)
+	defer timer.Stop()
+	select {
+	case c.health <- &change:
+	case <-timer.C:
+	}
 }
 
 func (c *Client) Health() <-chan *Update {
 	return c.health
 }
 
-func (c *Client) WalletExists() (bool, error) {
+func (c *Client) WalletExists(ctx context.Context) (bool, error) {
 	if c.closing {
 		return false, ErrDaemonNotRunning
 	}
 
-	ctx, cancel := c.rpcContext(0)
+	ctx, cancel := c.rpcContext(ctx, 0)
 	defer cancel()
 	_, err := c.lnClient.GetInfo(ctx, &lnrpc.GetInfoRequest{})
 	if err == nil {
@@ -360,7 +422,7 @@ func (c *Client) WalletExists() (bool, error) {
 	}
 
 	// Wallet does not exist
-	if matchRPCErrorMessage(err, rpcperms.ErrNoWallet) {
+	if isRPCError(err, ErrWalletNotFound) {
 		return false, nil
 	}
 
@@ -374,13 +436,13 @@ func (c *Client) IsSynced() (bool, bool, uint32, error) {
 		return false, false, 0, ErrDaemonNotRunning
 	}
 
-	ctx, cancel := c.rpcContext(0)
+	ctx, cancel := c.rpcContext(c.ctx, 0)
 	defer cancel()
 	resp, err := c.lnClient.GetInfo(ctx, &lnrpc.GetInfoRequest{})
 
 	// If the RPC server is still starting up, treat it as not synced yet,
 	// but don't surface an error so callers can keep polling smoothly.
-	if err != nil && matchRPCErrorMessage(err, rpcperms.ErrRPCStarting) {
+	if err != nil && isRPCError(err, ErrRPCStarting) {
 		err = nil
 		resp = nil
 	}
@@ -399,67 +461,77 @@ func (c *Client) IsSynced() (bool, bool, uint32, error) {
 		}
 	}
 
-	c.mu.Lock()
-	c.isSynced = synced
-	c.syncedHeight = blockHeight
-	c.mu.Unlock()
+	var headerTimestamp int64
+	if resp != nil {
+		headerTimestamp = resp.BestHeaderTimestamp
+	}
+	c.state.setSync(synced, blockHeight, headerTimestamp, resp != nil)
 
 	return synced, recentHeader, blockHeight, err
 }
 
-func (c *Client) Unlock(passphrase string) error {
+func (c *Client) Unlock(ctx context.Context, passphrase string) error {
 	if c.closing {
 		return ErrDaemonNotRunning
 	}
+	ctx, cancel := c.bindContext(ctx)
+	defer cancel()
 
-	_, err := c.unlockerClient.UnlockWallet(c.ctx, &lnrpc.UnlockWalletRequest{
+	_, err := c.unlockerClient.UnlockWallet(ctx, &lnrpc.UnlockWalletRequest{
 		WalletPassword: []byte(passphrase),
 		RecoveryWindow: 255,
 	})
 
-	if err != nil && matchRPCErrorMessage(err, rpcperms.ErrWalletUnlocked) {
+	if err != nil && isRPCError(err, ErrWalletUnlocked) {
 		return nil
 	}
 	return err
 }
 
-func (c *Client) IsLocked() (bool, error) {
+func (c *Client) IsLocked(ctx context.Context) (bool, error) {
 
 	if c.closing {
 		return false, ErrDaemonNotRunning
 	}
 
-	_, err := c.lnClient.GetInfo(c.withMacaroon(), &lnrpc.GetInfoRequest{})
+	ctx, cancel := c.bindContext(ctx)
+	defer cancel()
+
+	_, err := c.lnClient.GetInfo(c.withMacaroonContext(ctx), &lnrpc.GetInfoRequest{})
 	if err == nil {
 		// Wallet is unlocked
 		return false, nil
 	}
 
-	_, err = c.unlockerClient.GenSeed(c.ctx, &lnrpc.GenSeedRequest{})
+	_, err = c.unlockerClient.GenSeed(ctx, &lnrpc.GenSeedRequest{})
 	if err == nil {
 		// Wallet is locked (GenSeed is only available when locked)
 		return true, nil
 	}
 
-	if matchRPCErrorMessage(err, rpcperms.ErrWalletUnlocked, fmt.Errorf("wallet already exists")) {
+	if isRPCError(err, ErrWalletUnlocked) || isRPCError(err, ErrWalletAlreadyExists) {
 		return true, nil
 	}
 
 	return false, err
 }
 
-func (c *Client) Create(passphrase string) (string, []string, error) {
+func (c *Client) Create(ctx context.Context, passphrase string) (string, []string, error) {
 
 	if c.closing {
 		return "", nil, ErrDaemonNotRunning
 	}
+	ctx, cancel := c.bindContext(ctx)
+	defer cancel()
 
-	seedResp, err := c.unlockerClient.GenSeed(context.Background(), &lnrpc.GenSeedRequest{})
+	seedResp, err := c.unlockerClient.GenSeed(ctx, &lnrpc.GenSeedRequest{})
 	if err != nil {
 		return "", nil, err
 	}
 
-	_, err = c.unlockerClient.InitWallet(context.Background(), &lnrpc.InitWalletRequest{
+	c.resetTxCache()
+
+	_, err = c.unlockerClient.InitWallet(ctx, &lnrpc.InitWalletRequest{
 		WalletPassword:     []byte(passphrase),
 		CipherSeedMnemonic: seedResp.CipherSeedMnemonic,
 		RecoveryWindow:     0,
@@ -471,10 +543,12 @@ func (c *Client) Create(passphrase string) (string, []string, error) {
 	return hex.EncodeToString(seedResp.EncipheredSeed), seedResp.CipherSeedMnemonic, nil
 }
 
-func (c *Client) RestoreByEncipheredSeed(strEncipheredSeed, passphrase string) ([]string, error) {
+func (c *Client) RestoreByEncipheredSeed(ctx context.Context, strEncipheredSeed, passphrase string) ([]string, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
+	ctx, cancel := c.bindContext(ctx)
+	defer cancel()
 
 	encipheredSeed, err := hex.DecodeString(strEncipheredSeed)
 	if err != nil {
@@ -494,7 +568,9 @@ func (c *Client) RestoreByEncipheredSeed(strEncipheredSeed, passphrase string) (
 		return nil, err
 	}
 
-	_, err = c.unlockerClient.InitWallet(c.ctx, &lnrpc.InitWalletRequest{
+	c.resetTxCache()
+
+	_, err = c.unlockerClient.InitWallet(ctx, &lnrpc.InitWalletRequest{
 		WalletPassword:     []byte(passphrase),
 		CipherSeedMnemonic: mnemonic[:],
 		RecoveryWindow:     255,
@@ -506,10 +582,12 @@ func (c *Client) RestoreByEncipheredSeed(strEncipheredSeed, passphrase string) (
 	return mnemonic[:], nil
 }
 
-func (c *Client) RestoreByMnemonic(mnemonic []string, passphrase string) (string, error) {
+func (c *Client) RestoreByMnemonic(ctx context.Context, mnemonic []string, passphrase string) (string, error) {
 	if c.closing {
 		return "", ErrDaemonNotRunning
 	}
+	ctx, cancel := c.bindContext(ctx)
+	defer cancel()
 	var seedMnemonic aezeed.Mnemonic
 	copy(seedMnemonic[:], mnemonic)
 	cipherSeed, err := seedMnemonic.ToCipherSeed([]byte{})
@@ -522,7 +600,9 @@ func (c *Client) RestoreByMnemonic(mnemonic []string, passphrase string) (string
 		return "", err
 	}
 
-	_, err = c.unlockerClient.InitWallet(c.ctx, &lnrpc.InitWalletRequest{
+	c.resetTxCache()
+
+	_, err = c.unlockerClient.InitWallet(ctx, &lnrpc.InitWalletRequest{
 		WalletPassword:     []byte(passphrase),
 		CipherSeedMnemonic: mnemonic,
 		RecoveryWindow:     255,
@@ -534,17 +614,17 @@ func (c *Client) RestoreByMnemonic(mnemonic []string, passphrase string) (string
 	return hex.EncodeToString(encipheredSeed[:]), nil
 }
 
-func (c *Client) Balance() (*lnrpc.WalletBalanceResponse, error) {
+func (c *Client) Balance(ctx context.Context) (*lnrpc.WalletBalanceResponse, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
-	ctx, cancel := c.rpcContext(0)
+	ctx, cancel := c.rpcContext(ctx, 0)
 	defer cancel()
 	resp, err := c.lnClient.WalletBalance(ctx, &lnrpc.WalletBalanceRequest{
 		MinConfs: 0,
 	})
 	if err != nil {
-		// if matchRPCErrorMessage(err, rpcperms.ErrRPCStarting) {
+		// if isRPCError(err, ErrRPCStarting) {
 		// 	// Treat as not-ready, return zero balance without error.
 		// 	return &lnrpc.WalletBalanceResponse{}, nil
 		// }
@@ -554,21 +634,118 @@ func (c *Client) Balance() (*lnrpc.WalletBalanceResponse, error) {
 	return resp, nil
 }
 
-func (c *Client) GetRecoveryInfo() (*lnrpc.GetRecoveryInfoResponse, error) {
+// NetworkInfo reports the active network, the chain tip and how many peers
+// the node is connected to. ChainPeers is -1 when the daemon was built
+// without the neutrino RPC sub-server.
+func (c *Client) NetworkInfo(ctx context.Context) (*NetworkInfo, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
-	ctx, cancel := c.rpcContext(0)
+	ctx, cancel := c.rpcContext(ctx, 0)
+	defer cancel()
+
+	resp, err := c.lnClient.GetInfo(ctx, &lnrpc.GetInfoRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	info := &NetworkInfo{
+		ChainPeers:     -1,
+		LightningPeers: int(resp.GetNumPeers()),
+		BlockHeight:    resp.GetBlockHeight(),
+		BlockHash:      resp.GetBlockHash(),
+	}
+	if chains := resp.GetChains(); len(chains) > 0 {
+		info.Network = chains[0].GetNetwork()
+	}
+
+	if status, err := c.neutrinoKit.Status(ctx, &neutrinorpc.StatusRequest{}); err == nil {
+		info.ChainPeers = len(status.GetPeers())
+	}
+
+	return info, nil
+}
+
+// ChainPeerAddresses lists the peers the neutrino chain backend is connected
+// to.
+func (c *Client) ChainPeerAddresses(ctx context.Context) ([]string, error) {
+	if c.closing {
+		return nil, ErrDaemonNotRunning
+	}
+	ctx, cancel := c.rpcContext(ctx, 0)
+	defer cancel()
+
+	status, err := c.neutrinoKit.Status(ctx, &neutrinorpc.StatusRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return status.GetPeers(), nil
+}
+
+// AddChainPeer connects the neutrino chain backend to the peer at addr.
+func (c *Client) AddChainPeer(ctx context.Context, addr string) error {
+	if c.closing {
+		return ErrDaemonNotRunning
+	}
+	ctx, cancel := c.rpcContext(ctx, 0)
+	defer cancel()
+
+	_, err := c.neutrinoKit.AddPeer(ctx, &neutrinorpc.AddPeerRequest{PeerAddrs: addr})
+	return err
+}
+
+// ChannelPeers lists the peer of every open Lightning channel and whether it
+// is reachable. A peer counts as online when its channel is active or it is
+// connected.
+func (c *Client) ChannelPeers(ctx context.Context) ([]ChannelPeer, error) {
+	if c.closing {
+		return nil, ErrDaemonNotRunning
+	}
+	ctx, cancel := c.rpcContext(ctx, 0)
+	defer cancel()
+
+	channels, err := c.lnClient.ListChannels(ctx, &lnrpc.ListChannelsRequest{PeerAliasLookup: true})
+	if err != nil {
+		return nil, err
+	}
+	peers, err := c.lnClient.ListPeers(ctx, &lnrpc.ListPeersRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	connected := make(map[string]struct{}, len(peers.GetPeers()))
+	for _, peer := range peers.GetPeers() {
+		connected[peer.GetPubKey()] = struct{}{}
+	}
+
+	out := make([]ChannelPeer, 0, len(channels.GetChannels()))
+	for _, channel := range channels.GetChannels() {
+		_, isConnected := connected[channel.GetRemotePubkey()]
+		out = append(out, ChannelPeer{
+			PubKey:       channel.GetRemotePubkey(),
+			ChannelPoint: channel.GetChannelPoint(),
+			Alias:        channel.GetPeerAlias(),
+			Online:       channel.GetActive() || isConnected,
+		})
+	}
+	return out, nil
+}
+
+func (c *Client) GetRecoveryInfo(ctx context.Context) (*lnrpc.GetRecoveryInfoResponse, error) {
+	if c.closing {
+		return nil, ErrDaemonNotRunning
+	}
+	ctx, cancel := c.rpcContext(ctx, 0)
 	defer cancel()
 
 	return c.lnClient.GetRecoveryInfo(ctx, &lnrpc.GetRecoveryInfoRequest{})
 }
 
-func (c *Client) ListUnspent(minConfs, maxConfs int32) ([]*lnrpc.Utxo, error) {
+func (c *Client) ListUnspent(ctx context.Context, minConfs, maxConfs int32) ([]*lnrpc.Utxo, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
-	ctx, cancel := c.rpcContext(0)
+	ctx, cancel := c.rpcContext(ctx, 0)
 	defer cancel()
 
 	if maxConfs == 0 {
@@ -581,11 +758,11 @@ func (c *Client) ListUnspent(minConfs, maxConfs int32) ([]*lnrpc.Utxo, error) {
 	return resp.GetUtxos(), nil
 }
 
-func (c *Client) VerifyMessageWithAddress(address string, message string, signature string) (*walletrpc.VerifyMessageWithAddrResponse, error) {
+func (c *Client) VerifyMessageWithAddress(ctx context.Context, address string, message string, signature string) (*walletrpc.VerifyMessageWithAddrResponse, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
-	ctx, cancel := c.rpcContext(0)
+	ctx, cancel := c.rpcContext(ctx, 0)
 	defer cancel()
 
 	return c.walletKit.VerifyMessageWithAddr(ctx, &walletrpc.VerifyMessageWithAddrRequest{
@@ -595,11 +772,45 @@ func (c *Client) VerifyMessageWithAddress(address string, message string, signat
 	})
 }
 
-func (c *Client) ChangePassphrase(old, new string) error {
+// DebugSubsystems lists the daemon subsystems whose log level can be set.
+func (c *Client) DebugSubsystems(ctx context.Context) ([]string, error) {
+	if c.closing {
+		return nil, ErrDaemonNotRunning
+	}
+	ctx, cancel := c.rpcContext(ctx, 0)
+	defer cancel()
+
+	resp, err := c.lnClient.DebugLevel(ctx, &lnrpc.DebugLevelRequest{Show: true})
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(resp.SubSystems), nil
+}
+
+// SetDebugLevel changes the daemon log levels until it restarts. levelSpec
+// is either a level for every subsystem or comma separated SUBSYS=level
+// pairs. The levels of all subsystems are returned.
+func (c *Client) SetDebugLevel(ctx context.Context, levelSpec string) (string, error) {
+	if c.closing {
+		return "", ErrDaemonNotRunning
+	}
+	ctx, cancel := c.rpcContext(ctx, 0)
+	defer cancel()
+
+	resp, err := c.lnClient.DebugLevel(ctx, &lnrpc.DebugLevelRequest{LevelSpec: levelSpec})
+	if err != nil {
+		return "", err
+	}
+	return resp.SubSystems, nil
+}
+
+func (c *Client) ChangePassphrase(ctx context.Context, old, new string) error {
 	if c.closing {
 		return ErrDaemonNotRunning
 	}
-	locked, err := c.IsLocked()
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	locked, err := c.IsLocked(ctx)
 	if err != nil {
 		return err
 	}
@@ -607,7 +818,7 @@ func (c *Client) ChangePassphrase(old, new string) error {
 		return ErrWalletMustBeLocked
 	}
 
-	_, err = c.unlockerClient.ChangePassword(c.withMacaroon(), &lnrpc.ChangePasswordRequest{
+	_, err = c.unlockerClient.ChangePassword(ctx, &lnrpc.ChangePasswordRequest{
 		CurrentPassword: []byte(old),
 		NewPassword:     []byte(new),
 	})
@@ -619,11 +830,13 @@ func (c *Client) ChangePassphrase(old, new string) error {
 	return nil
 }
 
-func (c *Client) SimpleTransfer(address chainutil.Address, amount chainutil.Amount, lokiPerVbyte uint64) (string, error) {
+func (c *Client) SimpleTransfer(ctx context.Context, address chainutil.Address, amount chainutil.Amount, lokiPerVbyte uint64) (string, error) {
 	if c.closing {
 		return "", ErrDaemonNotRunning
 	}
-	resp, err := c.lnClient.SendCoins(c.withMacaroon(), &lnrpc.SendCoinsRequest{
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	resp, err := c.lnClient.SendCoins(ctx, &lnrpc.SendCoinsRequest{
 		Addr:             address.String(),
 		Amount:           int64(amount),
 		SatPerVbyte:      lokiPerVbyte,
@@ -635,17 +848,82 @@ func (c *Client) SimpleTransfer(address chainutil.Address, amount chainutil.Amou
 	return resp.Txid, nil
 }
 
-func (c *Client) SimpleTransferFee(address chainutil.Address, amount chainutil.Amount) (*lnrpc.EstimateFeeResponse, error) {
+// FeeRate returns the fee rate, in loki per virtual byte, the wallet would
+// use to confirm within confTarget blocks. It comes from the configured fee
+// URL on mainnet and from the chain backend otherwise.
+func (c *Client) FeeRate(ctx context.Context, confTarget int32) (float64, error) {
+	rates, err := c.FeeEstimates(ctx, confTarget)
+	rate, ok := rates[confTarget]
+	if !ok {
+		return 0, err
+	}
+	return rate, nil
+}
+
+// BumpFee asks the sweeper to get the unconfirmed transaction holding the
+// given wallet output confirmed faster at lokiPerVbyte, replacing it when it
+// is ours or spending the output with a child otherwise.
+func (c *Client) BumpFee(ctx context.Context, txid string, outputIndex uint32, lokiPerVbyte uint64) error {
+	if c.closing {
+		return ErrDaemonNotRunning
+	}
+	ctx, cancel := c.rpcContext(ctx, 0)
+	defer cancel()
+
+	_, err := c.walletKit.BumpFee(ctx, &walletrpc.BumpFeeRequest{
+		Outpoint: &lnrpc.OutPoint{
+			TxidStr:     txid,
+			OutputIndex: outputIndex,
+		},
+		SatPerVbyte: lokiPerVbyte,
+		Immediate:   true,
+	})
+	if err != nil {
+		return err
+	}
+	c.invalidateTxCache()
+	return nil
+}
+
+// LabelTransaction sets, or replaces, the wallet label of a transaction.
+func (c *Client) LabelTransaction(ctx context.Context, txid, label string) error {
+	if c.closing {
+		return ErrDaemonNotRunning
+	}
+	hash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return fmt.Errorf("invalid txid: %w", err)
+	}
+	ctx, cancel := c.rpcContext(ctx, 0)
+	defer cancel()
+
+	_, err = c.walletKit.LabelTransaction(ctx, &walletrpc.LabelTransactionRequest{
+		Txid:      hash[:],
+		Label:     label,
+		Overwrite: true,
+	})
+	if err != nil {
+		return err
+	}
+	c.invalidateTxCache()
+	return nil
+}
+
+// SimpleTransferFee estimates the fee of sending amount to address with a
+// fee rate that confirms within confTarget blocks.
+func (c *Client) SimpleTransferFee(ctx context.Context, address chainutil.Address, amount chainutil.Amount, confTarget int32) (*lnrpc.EstimateFeeResponse, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
 
 	entry := map[string]int64{}
 	entry[address.String()] = int64(amount.ToUnit(chainutil.AmountLoki))
 
-	resp, err := c.lnClient.EstimateFee(c.withMacaroon(), &lnrpc.EstimateFeeRequest{
+	resp, err := c.lnClient.EstimateFee(ctx, &lnrpc.EstimateFeeRequest{
 		AddrToAmount:          entry,
-		TargetConf:            1,
+		TargetConf:            confTarget,
 		CoinSelectionStrategy: lnrpc.CoinSelectionStrategy_STRATEGY_RANDOM,
 		SpendUnconfirmed:      true,
 	})
@@ -655,10 +933,12 @@ func (c *Client) SimpleTransferFee(address chainutil.Address, amount chainutil.A
 	return resp, nil
 }
 
-func (c *Client) FundPsbt(addrToAmount map[string]int64, lokiPerVbyte uint64, lockExpirationSeconds uint64) (*FundedPsbt, error) {
+func (c *Client) FundPsbt(ctx context.Context, addrToAmount map[string]int64, lokiPerVbyte uint64, lockExpirationSeconds uint64) (*FundedPsbt, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
 
 	outputs := make(map[string]uint64, len(addrToAmount))
 	for a, v := range addrToAmount {
@@ -679,7 +959,7 @@ func (c *Client) FundPsbt(addrToAmount map[string]int64, lokiPerVbyte uint64, lo
 		LockExpirationSeconds: lockExpirationSeconds,
 	}
 
-	resp, err := c.walletKit.FundPsbt(c.withMacaroon(), req)
+	resp, err := c.walletKit.FundPsbt(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -706,17 +986,49 @@ func (c *Client) FundPsbt(addrToAmount map[string]int64, lokiPerVbyte uint64, lo
 	}, nil
 }
 
-func (c *Client) FinalizePsbt(packet *psbt.Packet) (*chainutil.Tx, error) {
+func (c *Client) SignPsbt(ctx context.Context, packet *psbt.Packet) (*SignedPsbt, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
 
 	var buf bytes.Buffer
 	if err := packet.Serialize(&buf); err != nil {
 		return nil, err
 	}
 
-	resp, err := c.walletKit.FinalizePsbt(c.withMacaroon(), &walletrpc.FinalizePsbtRequest{
+	resp, err := c.walletKit.SignPsbt(ctx, &walletrpc.SignPsbtRequest{
+		FundedPsbt: buf.Bytes(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := psbt.NewFromRawBytes(bytes.NewReader(resp.SignedPsbt), false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedPsbt{
+		Packet:       signed,
+		SignedInputs: resp.SignedInputs,
+	}, nil
+}
+
+func (c *Client) FinalizePsbt(ctx context.Context, packet *psbt.Packet) (*chainutil.Tx, error) {
+	if c.closing {
+		return nil, ErrDaemonNotRunning
+	}
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+
+	var buf bytes.Buffer
+	if err := packet.Serialize(&buf); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.walletKit.FinalizePsbt(ctx, &walletrpc.FinalizePsbtRequest{
 		FundedPsbt: buf.Bytes(),
 	})
 	if err != nil {
@@ -731,17 +1043,19 @@ func (c *Client) FinalizePsbt(packet *psbt.Packet) (*chainutil.Tx, error) {
 	return tx, nil
 }
 
-func (c *Client) PublishTransaction(tx *chainutil.Tx) error {
+func (c *Client) PublishTransaction(ctx context.Context, tx *chainutil.Tx) error {
 	if c.closing {
 		return ErrDaemonNotRunning
 	}
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
 
 	bytes, err := tx.MsgTx().Bytes()
 	if err != nil {
 		return err
 	}
 
-	resp, err := c.walletKit.PublishTransaction(c.withMacaroon(), &walletrpc.Transaction{
+	resp, err := c.walletKit.PublishTransaction(ctx, &walletrpc.Transaction{
 		TxHex: bytes,
 	})
 	if err != nil {
@@ -749,26 +1063,28 @@ func (c *Client) PublishTransaction(tx *chainutil.Tx) error {
 	}
 
 	if resp.PublishError != "" {
-		return fmt.Errorf(resp.PublishError)
+		return errors.New(resp.PublishError)
 	}
 
 	return nil
 }
 
-func (c *Client) ReleaseOutputs(locks []*OutputLock) error {
+func (c *Client) ReleaseOutputs(ctx context.Context, locks []*OutputLock) error {
 	if len(locks) == 0 {
 		return nil
 	}
 	if c.closing {
 		return ErrDaemonNotRunning
 	}
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
 
 	for _, lock := range locks {
 		if lock == nil || len(lock.ID) == 0 || lock.Outpoint == nil {
 			continue
 		}
 
-		_, err := c.walletKit.ReleaseOutput(c.withMacaroon(), &walletrpc.ReleaseOutputRequest{
+		_, err := c.walletKit.ReleaseOutput(ctx, &walletrpc.ReleaseOutputRequest{
 			Id:       lock.ID,
 			Outpoint: lock.Outpoint,
 		})
@@ -780,11 +1096,13 @@ func (c *Client) ReleaseOutputs(locks []*OutputLock) error {
 	return nil
 }
 
-func (c *Client) SimpleManyTransfer(addrToAmount map[string]int64, lokiPerVbyte uint64) (string, error) {
+func (c *Client) SimpleManyTransfer(ctx context.Context, addrToAmount map[string]int64, lokiPerVbyte uint64) (string, error) {
 	if c.closing {
 		return "", ErrDaemonNotRunning
 	}
-	resp, err := c.lnClient.SendMany(c.withMacaroon(), &lnrpc.SendManyRequest{
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	resp, err := c.lnClient.SendMany(ctx, &lnrpc.SendManyRequest{
 		AddrToAmount: addrToAmount,
 		SatPerVbyte:  lokiPerVbyte,
 	})
@@ -794,14 +1112,16 @@ func (c *Client) SimpleManyTransfer(addrToAmount map[string]int64, lokiPerVbyte
 	return resp.Txid, nil
 }
 
-func (c *Client) SimpleManyTransferFee(addrToAmount map[string]int64) (*lnrpc.EstimateFeeResponse, error) {
+func (c *Client) SimpleManyTransferFee(ctx context.Context, addrToAmount map[string]int64, confTarget int32) (*lnrpc.EstimateFeeResponse, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
 
-	resp, err := c.lnClient.EstimateFee(c.withMacaroon(), &lnrpc.EstimateFeeRequest{
+	resp, err := c.lnClient.EstimateFee(ctx, &lnrpc.EstimateFeeRequest{
 		AddrToAmount:          addrToAmount,
-		TargetConf:            1,
+		TargetConf:            confTarget,
 		CoinSelectionStrategy: lnrpc.CoinSelectionStrategy_STRATEGY_RANDOM,
 		SpendUnconfirmed:      true,
 	})
@@ -811,33 +1131,62 @@ func (c *Client) SimpleManyTransferFee(addrToAmount map[string]int64) (*lnrpc.Es
 	return resp, nil
 }
 
-func (c *Client) GetNextAddress(addrType lnrpc.AddressType) (chainutil.Address, error) {
+func (c *Client) GetNextAddress(ctx context.Context, addrType lnrpc.AddressType) (chainutil.Address, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
-	resp, err := c.lnClient.NewAddress(c.withMacaroon(), &lnrpc.NewAddressRequest{Type: addrType})
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	resp, err := c.lnClient.NewAddress(ctx, &lnrpc.NewAddressRequest{Type: addrType})
 	if err != nil {
 		return nil, err
 	}
+	c.invalidateAddressCache()
 	return chainutil.DecodeAddress(resp.Address, c.config.ActiveNetParams.Params)
 }
 
-func (c *Client) ListAddresses() ([]*walletrpc.AccountWithAddresses, error) {
+func (c *Client) ListAddresses(ctx context.Context) ([]*walletrpc.AccountWithAddresses, error) {
+	return c.ListAddressesWithOptions(ctx, ListAddressesOptions{})
+}
+
+// ListAddressesWithOptions returns the wallet addresses, from the cache while
+// no transaction or new address changed them.
+func (c *Client) ListAddressesWithOptions(ctx context.Context, opts ListAddressesOptions) ([]*walletrpc.AccountWithAddresses, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
-	resp, err := c.walletKit.ListAddresses(c.withMacaroon(), &walletrpc.ListAddressesRequest{})
+
+	c.mu.Lock()
+	cache := c.addrCache
+	if cache != nil && !cache.Dirty && !opts.ForceRefresh && time.Since(cache.LastUpdated) <= addressesCacheTTL {
+		accounts := cache.Accounts
+		c.mu.Unlock()
+		return accounts, nil
+	}
+	c.mu.Unlock()
+
+	ctx, cancel := c.callContext(ctx)
+	defer cancel()
+	resp, err := c.walletKit.ListAddresses(ctx, &walletrpc.ListAddressesRequest{})
 	if err != nil {
 		return nil, err
 	}
+
+	c.mu.Lock()
+	c.addrCache = &addressCache{
+		Accounts:    resp.AccountWithAddresses,
+		LastUpdated: time.Now(),
+	}
+	c.mu.Unlock()
+
 	return resp.AccountWithAddresses, nil
 }
 
-func (c *Client) SignMessageWithAddress(address string, message string) (string, error) {
+func (c *Client) SignMessageWithAddress(ctx context.Context, address string, message string) (string, error) {
 	if c.closing {
 		return "", ErrDaemonNotRunning
 	}
-	ctx, cancel := c.rpcContext(0)
+	ctx, cancel := c.rpcContext(ctx, 0)
 	defer cancel()
 
 	resp, err := c.walletKit.SignMessageWithAddr(ctx, &walletrpc.SignMessageWithAddrRequest{
@@ -851,11 +1200,11 @@ func (c *Client) SignMessageWithAddress(address string, message string) (string,
 	return resp.GetSignature(), nil
 }
 
-func (c *Client) VerifyMessage(message string, signature string) (*lnrpc.VerifyMessageResponse, error) {
+func (c *Client) VerifyMessage(ctx context.Context, message string, signature string) (*lnrpc.VerifyMessageResponse, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
-	ctx, cancel := c.rpcContext(0)
+	ctx, cancel := c.rpcContext(ctx, 0)
 	defer cancel()
 
 	return c.lnClient.VerifyMessage(ctx, &lnrpc.VerifyMessageRequest{
@@ -864,10 +1213,10 @@ func (c *Client) VerifyMessage(message string, signature string) (*lnrpc.VerifyM
 	})
 }
 
-func (c *Client) FetchTransactions() ([]*lnrpc.Transaction, error) {
-	return c.FetchTransactionsWithOptions(FetchTransactionsOptions{})
+func (c *Client) FetchTransactions(ctx context.Context) ([]*lnrpc.Transaction, error) {
+	return c.FetchTransactionsWithOptions(ctx, FetchTransactionsOptions{})
 }
-func (c *Client) FetchTransactionsWithOptions(opts FetchTransactionsOptions) ([]*lnrpc.Transaction, error) {
+func (c *Client) FetchTransactionsWithOptions(ctx context.Context, opts FetchTransactionsOptions) ([]*lnrpc.Transaction, error) {
 	if c.closing {
 		return nil, ErrDaemonNotRunning
 	}
@@ -878,6 +1227,12 @@ func (c *Client) FetchTransactionsWithOptions(opts FetchTransactionsOptions) ([]
 		limit = 0
 	}
 
+	// The transactions evicted from the cache are fetched again for the
+	// callers that want them all.
+	if opts.IgnoreLimit && c.cache != nil && c.cache.Truncated {
+		opts.ForceRescan = true
+	}
+
 	cache := c.cache
 	// Fast-path: valid cache, not dirty, TTL ok, and no new tx since LastIndex.
 	if cache != nil && !cache.Dirty && !opts.ForceRescan && time.Since(cache.LastUpdated) <= transactionsCacheTTL {
@@ -885,8 +1240,8 @@ func (c *Client) FetchTransactionsWithOptions(opts FetchTransactionsOptions) ([]
 		c.mu.Unlock()
 
 		// Probe for new transactions after the last known index.
-		ctx, cancel := c.rpcContext(5 * time.Second)
-		probe, err := c.lnClient.GetTransactions(ctx, &lnrpc.GetTransactionsRequest{
+		pctx, cancel := c.rpcContext(ctx, 0)
+		probe, err := c.lnClient.GetTransactions(pctx, &lnrpc.GetTransactionsRequest{
 			StartHeight: 0,
 			EndHeight:   -1,
 
@@ -896,13 +1251,10 @@ func (c *Client) FetchTransactionsWithOptions(opts FetchTransactionsOptions) ([]
 		cancel()
 
 		// If probe failed in a transient way, still serve cached but surface the error.
-		if err != nil && matchRPCErrorMessage(err, rpcperms.ErrRPCStarting) {
+		if err != nil && isRPCError(err, ErrRPCStarting) {
 			c.mu.Lock()
-			if c.cache != nil && len(c.cache.Txs) > 0 {
-				cached := append([]*lnrpc.Transaction(nil), c.cache.Txs...)
-				if limit > 0 && len(cached) > limit {
-					cached = cached[:limit]
-				}
+			if c.cache != nil && c.cache.Txs.Len() > 0 {
+				cached := c.cache.Txs.Newest(limit)
 				c.mu.Unlock()
 				return cached, fmt.Errorf("backend starting: %w", err)
 			}
@@ -914,10 +1266,7 @@ func (c *Client) FetchTransactionsWithOptions(opts FetchTransactionsOptions) ([]
 			c.mu.Lock()
 			if c.cache != nil {
 				c.cache.LastUpdated = time.Now()
-				cached := append([]*lnrpc.Transaction(nil), c.cache.Txs...)
-				if limit > 0 && len(cached) > limit {
-					cached = cached[:limit]
-				}
+				cached := c.cache.Txs.Newest(limit)
 				c.mu.Unlock()
 				return cached, nil
 			}
@@ -927,30 +1276,44 @@ func (c *Client) FetchTransactionsWithOptions(opts FetchTransactionsOptions) ([]
 		c.mu.Unlock()
 	}
 
-	// Build starting cursor and snapshot existing txs safely under lock.
+	// Build the starting cursor; the fetched pages are merged into the cache
+	// once complete.
 	var (
-		cursor   uint64
-		existing []*lnrpc.Transaction
+		cursor uint64
+		known  int
 	)
 
 	c.mu.Lock()
 	if c.cache != nil && !opts.ForceRescan {
 		// Resume from the last index we saw (+1). This avoids gaps/dups if new txs arrive during paging.
 		cursor = c.cache.LastIndex
+		known = c.cache.Txs.Len()
+	}
+	c.mu.Unlock()
 
-		// Deep copy slice header under lock (elements are pointers; snapshot is fine for read-only).
-		if len(c.cache.Txs) > 0 {
-			existing = append(existing, c.cache.Txs...)
+	// A cold cache pages through the whole history, several pages at a time.
+	if cursor == 0 && known == 0 {
+		collected, lastIndex, err := c.fetchTransactionsParallel(ctx, func(page []*lnrpc.Transaction, total int) bool {
+			if opts.OnProgress != nil {
+				opts.OnProgress(total)
+			}
+			return true
+		})
+		if err != nil {
+			if isRPCError(err, ErrRPCStarting) {
+				return []*lnrpc.Transaction{}, transactionFetchError(err)
+			}
+			return nil, transactionFetchError(err)
 		}
+		return c.storeTransactions(collected, lastIndex, opts, limit), nil
 	}
-	c.mu.Unlock()
 
 	collected := make([]*lnrpc.Transaction, 0, 256)
 	lastIndex := uint64(0)
 
 	for {
-		ctx, cancel := c.rpcContext(transactionFetchTimeout)
-		resp, err := c.lnClient.GetTransactions(ctx, &lnrpc.GetTransactionsRequest{
+		rctx, cancel := c.rpcContext(ctx, c.rpcTimeouts().transactions())
+		resp, err := c.lnClient.GetTransactions(rctx, &lnrpc.GetTransactionsRequest{
 			StartHeight: 0,
 			EndHeight:   -1,
 
@@ -960,20 +1323,17 @@ func (c *Client) FetchTransactionsWithOptions(opts FetchTransactionsOptions) ([]
 		cancel()
 		if err != nil {
 			// Serve cached data but surface the condition to the caller.
-			if matchRPCErrorMessage(err, rpcperms.ErrRPCStarting) {
+			if isRPCError(err, ErrRPCStarting) {
 				c.mu.Lock()
-				if c.cache != nil && len(c.cache.Txs) > 0 {
-					cached := append([]*lnrpc.Transaction(nil), c.cache.Txs...)
-					if limit > 0 && len(cached) > limit {
-						cached = cached[:limit]
-					}
+				if c.cache != nil && c.cache.Txs.Len() > 0 {
+					cached := c.cache.Txs.Newest(limit)
 					c.mu.Unlock()
 					return cached, fmt.Errorf("backend starting: %w", err)
 				}
 				c.mu.Unlock()
 				return []*lnrpc.Transaction{}, fmt.Errorf("backend starting: %w", err)
 			}
-			if matchRPCErrorMessage(err, context.DeadlineExceeded) {
+			if isRPCError(err, context.DeadlineExceeded) {
 				return nil, fmt.Errorf("rpc connection timeout")
 			}
 			return nil, err
@@ -988,7 +1348,7 @@ func (c *Client) FetchTransactionsWithOptions(opts FetchTransactionsOptions) ([]
 
 		collected = append(collected, resp.Transactions...)
 		if opts.OnProgress != nil {
-			opts.OnProgress(len(existing) + len(collected))
+			opts.OnProgress(known + len(collected))
 		}
 
 		// Advance cursor using server-driven index to avoid gaps/dups.
@@ -1004,94 +1364,393 @@ func (c *Client) FetchTransactionsWithOptions(opts FetchTransactionsOptions) ([]
 		}
 	}
 
-	// Merge existing cache + newly collected.
-	currentTotal := len(existing) + len(collected)
 	if opts.OnProgress != nil {
-		opts.OnProgress(currentTotal)
+		opts.OnProgress(known + len(collected))
 	}
 
-	allTxs := make([]*lnrpc.Transaction, 0, currentTotal)
-	if len(existing) > 0 {
-		allTxs = append(allTxs, existing...)
-	}
-	if len(collected) > 0 {
-		allTxs = append(allTxs, collected...)
-	}
+	return c.storeTransactions(collected, lastIndex, opts, limit), nil
+}
 
-	// Sort newest-first by (TimeStamp, BlockHeight). Stable sort preserves input order for ties.
-	sort.SliceStable(allTxs, func(i, j int) bool {
-		if allTxs[i].TimeStamp != allTxs[j].TimeStamp {
-			return allTxs[i].TimeStamp > allTxs[j].TimeStamp
+// TransactionPage is a batch of transactions delivered by StreamTransactions.
+type TransactionPage struct {
+	Transactions []*lnrpc.Transaction
+	Total        int   // transactions delivered so far, this page included
+	Err          error // set on the last page when the fetch failed
+}
+
+// StreamTransactions delivers the wallet transactions page by page: first
+// the cached ones, then those fetched after the last known index. A
+// transaction is delivered once, but pages are not ordered against each
+// other. The display limit does not apply. The channel is closed when the
+// fetch is done, failed or ctx is cancelled; the cache is only updated by a
+// fetch that completed.
+func (c *Client) StreamTransactions(ctx context.Context, opts FetchTransactionsOptions) <-chan TransactionPage {
+	pages := make(chan TransactionPage)
+
+	go func() {
+		defer close(pages)
+
+		send := func(page TransactionPage) bool {
+			select {
+			case pages <- page:
+				return true
+			case <-ctx.Done():
+				return false
+			}
 		}
-		if allTxs[i].BlockHeight != allTxs[j].BlockHeight {
-			return allTxs[i].BlockHeight > allTxs[j].BlockHeight
+
+		if c.closing {
+			send(TransactionPage{Err: ErrDaemonNotRunning})
+			return
 		}
-		return false // preserve original order for ties
-	})
 
-	// Deduplicate by TxHash while preserving order (post-sort).
-	if len(allTxs) > 1 {
-		seen := make(map[string]struct{}, len(allTxs))
-		dedup := allTxs[:0]
-		for _, tx := range allTxs {
-			h := tx.TxHash
-			if _, ok := seen[h]; ok {
-				continue
+		var (
+			cursor   uint64
+			existing []*lnrpc.Transaction
+		)
+		c.mu.Lock()
+		if c.cache != nil && !opts.ForceRescan {
+			cursor = c.cache.LastIndex
+			existing = c.cache.Txs.Newest(0)
+		}
+		c.mu.Unlock()
+
+		seen := make(map[string]struct{}, len(existing))
+		for _, tx := range existing {
+			seen[tx.TxHash] = struct{}{}
+		}
+		total := len(existing)
+		if total > 0 && !send(TransactionPage{Transactions: existing, Total: total}) {
+			return
+		}
+
+		if cursor == 0 && total == 0 {
+			collected, lastIndex, err := c.fetchTransactionsParallel(ctx, func(page []*lnrpc.Transaction, _ int) bool {
+				fresh := make([]*lnrpc.Transaction, 0, len(page))
+				for _, tx := range page {
+					if _, ok := seen[tx.TxHash]; ok {
+						continue
+					}
+					seen[tx.TxHash] = struct{}{}
+					fresh = append(fresh, tx)
+				}
+				total += len(fresh)
+				if opts.OnProgress != nil {
+					opts.OnProgress(total)
+				}
+				return len(fresh) == 0 || send(TransactionPage{Transactions: fresh, Total: total})
+			})
+			if err != nil {
+				if ctx.Err() == nil {
+					send(TransactionPage{Total: total, Err: transactionFetchError(err)})
+				}
+				return
+			}
+			c.storeTransactions(collected, lastIndex, opts, 0)
+			return
+		}
+
+		collected := make([]*lnrpc.Transaction, 0, transactionPageSize)
+		lastIndex := uint64(0)
+
+		for {
+			rctx, cancel := c.rpcContext(ctx, c.rpcTimeouts().transactions())
+			resp, err := c.lnClient.GetTransactions(rctx, &lnrpc.GetTransactionsRequest{
+				StartHeight: 0,
+				EndHeight:   -1,
+
+				MaxTransactions: transactionPageSize,
+				IndexOffset:     uint32(cursor),
+			})
+			cancel()
+			if err != nil {
+				if ctx.Err() == nil {
+					send(TransactionPage{Total: total, Err: transactionFetchError(err)})
+				}
+				return
+			}
+
+			lastIndex = resp.LastIndex
+			if len(resp.Transactions) == 0 {
+				break
+			}
+
+			fresh := make([]*lnrpc.Transaction, 0, len(resp.Transactions))
+			for _, tx := range resp.Transactions {
+				if _, ok := seen[tx.TxHash]; ok {
+					continue
+				}
+				seen[tx.TxHash] = struct{}{}
+				fresh = append(fresh, tx)
+			}
+			collected = append(collected, fresh...)
+			total += len(fresh)
+			if opts.OnProgress != nil {
+				opts.OnProgress(total)
+			}
+			if len(fresh) > 0 && !send(TransactionPage{Transactions: fresh, Total: total}) {
+				return
+			}
+
+			cursor = uint64(resp.LastIndex) + 1
+			if cursor > uint64(^uint32(0)) {
+				break
+			}
+			if uint32(len(resp.Transactions)) < transactionPageSize {
+				break
 			}
-			seen[h] = struct{}{}
-			dedup = append(dedup, tx)
 		}
-		allTxs = dedup
+
+		c.storeTransactions(collected, lastIndex, opts, 0)
+	}()
+
+	return pages
+}
+
+// fetchTransactionsParallel pages through the whole history with up to
+// transactionFetchWorkers calls in flight. Pages are requested by position
+// until one comes back short. onPage sees each page as it arrives, one at a
+// time, with the count of transactions received so far; returning false
+// stops the fetch. The pages are returned in order with the last index of the
+// last one holding transactions.
+func (c *Client) fetchTransactionsParallel(ctx context.Context, onPage func(page []*lnrpc.Transaction, total int) bool) ([]*lnrpc.Transaction, uint64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		next     int
+		end      = -1 // first short page, once known
+		received int
+		firstErr error
+		pages    = make(map[int]*lnrpc.TransactionDetails)
+	)
+
+	claim := func() (int, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr != nil || (end >= 0 && next > end) {
+			return 0, false
+		}
+		next++
+		return next - 1, true
 	}
 
-	// Persist cache atomically.
-	c.mu.Lock()
-	if c.cache != nil {
-		snapshot := append([]*lnrpc.Transaction(nil), allTxs...)
-		c.cache.Txs = snapshot
-		c.cache.LastIndex = lastIndex
-		// NextOffset is defined as the index to resume from (= lastIndex+1), clamped to uint32.
-		next := lastIndex + 1
-		if next > uint64(^uint32(0)) {
-			c.cache.NextOffset = ^uint32(0)
-		} else {
-			c.cache.NextOffset = uint32(next)
+	fail := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+			cancel()
 		}
-		c.cache.LastUpdated = time.Now()
-		c.cache.Dirty = false
+	}
 
-		result := snapshot
-		c.mu.Unlock()
+	for range transactionFetchWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				page, ok := claim()
+				if !ok {
+					return
+				}
+
+				rctx, rcancel := c.rpcContext(ctx, c.rpcTimeouts().transactions())
+				resp, err := c.lnClient.GetTransactions(rctx, &lnrpc.GetTransactionsRequest{
+					StartHeight: 0,
+					EndHeight:   -1,
+
+					MaxTransactions: transactionPageSize,
+					IndexOffset:     uint32(page * transactionPageSize),
+				})
+				rcancel()
+
+				mu.Lock()
+				if err != nil {
+					fail(err)
+					mu.Unlock()
+					return
+				}
+				pages[page] = resp
+				if uint32(len(resp.Transactions)) < transactionPageSize && (end < 0 || page < end) {
+					end = page
+				}
+				if firstErr == nil && len(resp.Transactions) > 0 && onPage != nil {
+					received += len(resp.Transactions)
+					if !onPage(resp.Transactions, received) {
+						fail(context.Canceled)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
 
-		if limit > 0 && len(result) > limit {
-			result = result[:limit]
+	if firstErr != nil {
+		return nil, 0, firstErr
+	}
+
+	var (
+		collected []*lnrpc.Transaction
+		lastIndex uint64
+	)
+	for page := 0; page <= end; page++ {
+		collected = append(collected, pages[page].Transactions...)
+		// The short page is empty when the history fills whole pages, and
+		// an empty page has no last index.
+		if len(pages[page].Transactions) > 0 {
+			lastIndex = pages[page].LastIndex
 		}
-		return append([]*lnrpc.Transaction(nil), result...), nil
+	}
+	return collected, lastIndex, nil
+}
+
+// transactionFetchError describes a failed GetTransactions call.
+func transactionFetchError(err error) error {
+	switch {
+	case isRPCError(err, ErrRPCStarting):
+		return fmt.Errorf("backend starting: %w", err)
+	case isRPCError(err, context.DeadlineExceeded):
+		return fmt.Errorf("rpc connection timeout")
+	default:
+		return err
+	}
+}
+
+// storeTransactions merges the newly fetched transactions into the cache,
+// or replaces it with them on a rescan, and returns up to limit of them all,
+// newest first. When anything changed the cache is also written for the next
+// start, unless the limit has evicted some of it.
+func (c *Client) storeTransactions(collected []*lnrpc.Transaction, lastIndex uint64, opts FetchTransactionsOptions, limit int) []*lnrpc.Transaction {
+	c.mu.Lock()
+	if c.cache == nil {
+		c.mu.Unlock()
+		idx := newTxIndex(collected)
+		return idx.Newest(limit)
+	}
+
+	if opts.ForceRescan {
+		c.cache.Txs = newTxIndex(collected)
+	} else {
+		c.cache.Txs.Merge(collected)
+	}
+	c.cache.LastIndex = lastIndex
+	// NextOffset is defined as the index to resume from (= lastIndex+1), clamped to uint32.
+	next := lastIndex + 1
+	if next > uint64(^uint32(0)) {
+		c.cache.NextOffset = ^uint32(0)
+	} else {
+		c.cache.NextOffset = uint32(next)
+	}
+	c.cache.LastUpdated = time.Now()
+	c.cache.Dirty = false
+
+	result := c.cache.Txs.Newest(limit)
+	var saved []*lnrpc.Transaction
+	tip := c.state.SyncedHeight()
+	if (opts.ForceRescan || len(collected) > 0) && tip > 0 {
+		saved = c.cache.Txs.Newest(0)
+	}
+	if c.cache.Txs.Evict(c.txCacheLimit) > 0 {
+		c.cache.Truncated = true
+	} else if opts.ForceRescan {
+		c.cache.Truncated = false
+	}
+	// Without its oldest transactions the cache is no longer the start of
+	// the history the next start resumes paging after. The file saved
+	// before the cap was reached still is, so it stays.
+	if c.cache.Truncated {
+		saved = nil
 	}
 	c.mu.Unlock()
 
-	result := append([]*lnrpc.Transaction(nil), allTxs...)
-	if limit > 0 && len(result) > limit {
-		result = result[:limit]
+	// Save the refreshed cache for the next start. This is best effort:
+	// without the file the next start pages through everything.
+	if saved != nil {
+		_ = saveTxCache(c.cachePath, saved, tip)
 	}
-	return result, nil
+
+	return result
 }
 
 func (c *Client) withMacaroon() context.Context {
+	return c.withMacaroonContext(c.ctx)
+}
+
+func (c *Client) withMacaroonContext(ctx context.Context) context.Context {
 	md := metadata.Pairs("macaroon", c.adminMacHex)
-	return metadata.NewOutgoingContext(c.ctx, md)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// bindContext derives a context from the caller's one that is also cancelled
+// when the daemon stops, so a call ends on whichever comes first.
+func (c *Client) bindContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	stop := context.AfterFunc(c.ctx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
 }
 
-func (c *Client) rpcContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+// callContext is bindContext with the admin macaroon, for calls without a
+// timeout of their own.
+func (c *Client) callContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := c.bindContext(parent)
+	return c.withMacaroonContext(ctx), cancel
+}
+
+// rpcContext is callContext with a timeout, the default one when 0.
+func (c *Client) rpcContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
 	if timeout <= 0 {
-		timeout = defaultRPCTimeout
+		timeout = c.rpcTimeouts().calls()
 	}
 	if c.config.ConnectionTimeout > 0 && timeout > c.config.ConnectionTimeout {
 		timeout = c.config.ConnectionTimeout
 	}
-	ctx, cancel := context.WithTimeout(c.ctx, timeout)
-	md := metadata.Pairs("macaroon", c.adminMacHex)
-	return metadata.NewOutgoingContext(ctx, md), cancel
+	ctx, cancel := c.bindContext(parent)
+	ctx, cancelTimeout := context.WithTimeout(ctx, timeout)
+	return c.withMacaroonContext(ctx), func() {
+		cancelTimeout()
+		cancel()
+	}
+}
+
+// SetTransactionCacheLimit caps how many transactions the cache keeps, 0 for
+// no limit.
+func (c *Client) SetTransactionCacheLimit(limit int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.txCacheLimit = limit
+	if c.cache != nil && c.cache.Txs.Evict(limit) > 0 {
+		c.cache.Truncated = true
+	}
+}
+
+func (c *Client) TxCacheStats() TxCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := TxCacheStats{Limit: c.txCacheLimit}
+	if c.cache != nil {
+		stats.Transactions = c.cache.Txs.Len()
+		stats.Bytes = c.cache.Txs.Bytes()
+		stats.Truncated = c.cache.Truncated
+	}
+	return stats
+}
+
+// SetRPCTimeouts changes the timeouts of the calls made after it.
+func (c *Client) SetRPCTimeouts(timeouts RPCTimeouts) {
+	c.timeouts.Store(&timeouts)
+}
+
+func (c *Client) rpcTimeouts() RPCTimeouts {
+	if t := c.timeouts.Load(); t != nil {
+		return *t
+	}
+	return RPCTimeouts{}
 }
 
 func (c *Client) SetMaxTransactionsLimit(limit uint32) {
@@ -1113,17 +1772,10 @@ func readMacaroon(path string) (string, error) {
 	return hex.EncodeToString(data), nil
 }
 
-func matchRPCErrorMessage(err error, targets ...error) bool {
-	st, ok := status.FromError(err)
-	if !ok {
-		return false
-	}
-	for _, t := range targets {
-		if st.Message() == t.Error() {
-			return true
-		}
-	}
-	return false
+// isRPCError reports whether err is a daemon error of the given kind, see
+// mapRPCError.
+func isRPCError(err error, kind error) bool {
+	return errors.Is(mapRPCError(err), kind)
 }
 
 type LightningConfig struct {
@@ -1135,7 +1787,7 @@ type LightningConfig struct {
 	TLSCertHex  string
 }
 
-func (c *Client) GetLightningConfig() (*LightningConfig, error) {
+func (c *Client) GetLightningConfig(ctx context.Context) (*LightningConfig, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -1186,7 +1838,7 @@ func (c *Client) GetLightningConfig() (*LightningConfig, error) {
 	}
 
 	// PubKey
-	ctx, cancel := c.rpcContext(defaultRPCTimeout)
+	ctx, cancel := c.rpcContext(ctx, 0)
 	defer cancel()
 	info, err := c.lnClient.GetInfo(ctx, &lnrpc.GetInfoRequest{})
 	if err != nil {
@@ -73,7 +73,7 @@ func TestClientWallet(t *testing.T) {
 		t.Fatal("health check failed")
 	}
 
-	mhex, mnemonic, err := c.Create(walletPassphrase)
+	mhex, mnemonic, err := c.Create(context.Background(), walletPassphrase)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -95,7 +95,7 @@ loop:
 		}
 	}
 
-	balance, err := c.Balance()
+	balance, err := c.Balance(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -153,7 +153,7 @@ func TestFetchTransactions(t *testing.T) {
 	}
 
 	// Fetch transactions
-	txs, err := c.FetchTransactions()
+	txs, err := c.FetchTransactions(context.Background())
 	if err != nil {
 		t.Fatalf("failed to fetch transactions: %v", err)
 	}
@@ -216,7 +216,7 @@ func TestFetchTransactionsWithProgress(t *testing.T) {
 		OnProgress: progressCallback,
 	}
 
-	txs, err := c.FetchTransactionsWithOptions(opts)
+	txs, err := c.FetchTransactionsWithOptions(context.Background(), opts)
 	if err != nil {
 		t.Fatalf("failed to fetch with progress: %v", err)
 	}
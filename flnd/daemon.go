@@ -19,6 +19,7 @@ import (
 
 var (
 	ErrDaemonNotRunning = errors.New("daemon is not running")
+	ErrShutdownTimeout  = errors.New("daemon did not stop in time")
 )
 
 const (
@@ -40,6 +41,13 @@ type daemon struct {
 	mu     sync.Mutex
 	wg     sync.WaitGroup
 	client *Client
+
+	// stats times the calls made on the connection, when set.
+	stats *rpcStats
+
+	// rescanFrom, when set, is the height the wallet is rewound to before
+	// the daemon opens it.
+	rescanFrom uint32
 }
 
 func newDaemon(pctx context.Context, config *flnd.Config, interceptor signal.Interceptor) (*daemon, error) {
@@ -67,6 +75,13 @@ func (d *daemon) start() (c *Client, err error) {
 		return
 	}
 
+	if d.rescanFrom > 0 {
+		if _, err = rewindWallet(walletDBPath(d.config.AdminMacPath), d.rescanFrom); err != nil {
+			err = fmt.Errorf("failed to rewind wallet to height %d: %w", d.rescanFrom, err)
+			return
+		}
+	}
+
 	impl := d.config.ImplementationConfig(d.interceptor)
 	defer func() {
 		if err != nil {
@@ -88,8 +103,16 @@ func (d *daemon) start() (c *Client, err error) {
 		return nil, fmt.Errorf("unable to open rpc connection, rpc listener is empty")
 	}
 
+	unary := []grpc.UnaryClientInterceptor{mapErrorsUnary}
+	if d.stats != nil {
+		// Outermost, so the calls are timed with the errors mapped.
+		unary = append([]grpc.UnaryClientInterceptor{d.stats.unary}, unary...)
+	}
+
 	d.conn, err = grpc.NewClient(d.config.RPCListeners[0].String(),
 		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(unary...),
+		grpc.WithChainStreamInterceptor(mapErrorsStream),
 		grpc.WithDefaultCallOptions(
 			grpc.MaxCallRecvMsgSize(maxGrpcRecvMsgSize),
 			grpc.MaxCallSendMsgSize(maxGrpcSendMsgSize),
@@ -0,0 +1,135 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+)
+
+const (
+	// dnsSeedTimeout bounds a resolution of all the seeds.
+	dnsSeedTimeout = 10 * time.Second
+	// maxSeedPeers is how many of the addresses of a seed are used.
+	maxSeedPeers = 8
+)
+
+// lookupHostFunc resolves a host name to its addresses.
+type lookupHostFunc func(ctx context.Context, host string) ([]string, error)
+
+// ResolveSeeds returns the chain peers the DNS seeds point to, host:port
+// with defaultPort for the seeds given without a port. The peers found are
+// returned with the error of the seeds that did not resolve.
+func ResolveSeeds(ctx context.Context, seeds []string, defaultPort string) ([]string, error) {
+	return resolveSeeds(ctx, net.DefaultResolver.LookupHost, seeds, defaultPort)
+}
+
+func resolveSeeds(ctx context.Context, lookup lookupHostFunc, seeds []string, defaultPort string) ([]string, error) {
+	var peers []string
+	var errs []error
+	for _, seed := range seeds {
+		host, port, err := net.SplitHostPort(seed)
+		if err != nil {
+			host, port = seed, defaultPort
+		}
+		addrs, err := lookup(ctx, host)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dnsseed %s: %w", seed, err))
+			continue
+		}
+		for _, addr := range addrs[:min(len(addrs), maxSeedPeers)] {
+			if peer := net.JoinHostPort(addr, port); !slices.Contains(peers, peer) {
+				peers = append(peers, peer)
+			}
+		}
+	}
+	return peers, errors.Join(errs...)
+}
+
+// seedsAllowed reports whether the seeds can be resolved: not when Tor is
+// on and clearnet is forbidden, as the lookups would leave Tor.
+func (cfg *ServiceConfig) seedsAllowed() bool {
+	return len(cfg.DNSSeeds) > 0 && !(cfg.TorActive && cfg.TorNoClearnet)
+}
+
+// refreshSeeds resolves the seeds every interval and adds the new peers to
+// the running daemon, the ones gone staying connected until it restarts.
+func (s *Service) refreshSeeds(seeds []string, port string, interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, peer := range s.resolveSeedPeers(seeds, port) {
+			ctx, cancel := context.WithTimeout(s.ctx, s.timeouts().calls())
+			s.AddChainPeer(ctx, peer)
+			cancel()
+		}
+	}
+}
+
+// resolveSeedPeers resolves the seeds within dnsSeedTimeout and puts the
+// peers found in the daemon config, returning the ones that are new. When
+// none resolves, the peers of the last resolution are kept.
+func (s *Service) resolveSeedPeers(seeds []string, port string) []string {
+	ctx, cancel := context.WithTimeout(s.ctx, dnsSeedTimeout)
+	defer cancel()
+	peers, _ := ResolveSeeds(ctx, seeds, port)
+	if len(peers) == 0 {
+		return nil
+	}
+	return s.setSeedPeers(peers)
+}
+
+// setSeedPeers puts peers in the daemon config in place of the ones the
+// seeds gave before, and returns the ones that are new.
+func (s *Service) setSeedPeers(peers []string) []string {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	var added []string
+	for _, peer := range peers {
+		if !slices.Contains(s.seedPeers, peer) {
+			added = append(added, peer)
+		}
+	}
+	addPeers := slices.DeleteFunc(slices.Clone(s.flndConfig.NeutrinoMode.AddPeers), func(peer string) bool {
+		return slices.Contains(s.seedPeers, peer)
+	})
+	s.seedPeers = peers
+	s.flndConfig.NeutrinoMode.AddPeers = appendSeedPeers(addPeers, peers)
+	return added
+}
+
+// appendSeedPeers returns addPeers with the seed peers it misses.
+func appendSeedPeers(addPeers, seedPeers []string) []string {
+	addPeers = slices.Clone(addPeers)
+	for _, peer := range seedPeers {
+		if !slices.Contains(addPeers, peer) {
+			addPeers = append(addPeers, peer)
+		}
+	}
+	return addPeers
+}
+
+// AddChainPeer connects the daemon to a chain peer.
+func (s *Service) AddChainPeer(ctx context.Context, addr string) error {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return ErrDaemonNotRunning
+	}
+	return s.client.AddChainPeer(ctx, addr)
+}
@@ -0,0 +1,69 @@
+package flnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/flokiorg/flnd"
+)
+
+func TestResolveSeeds(t *testing.T) {
+	lookup := func(_ context.Context, host string) ([]string, error) {
+		switch host {
+		case "seed.example.org":
+			return []string{"203.0.113.1", "203.0.113.2", "2001:db8::1"}, nil
+		case "other.example.org":
+			return []string{"203.0.113.2"}, nil
+		case "big.example.org":
+			var addrs []string
+			for i := range 20 {
+				addrs = append(addrs, fmt.Sprintf("198.51.100.%d", i))
+			}
+			return addrs, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	peers, err := resolveSeeds(context.Background(), lookup,
+		[]string{"seed.example.org", "other.example.org:15212", "other.example.org:9999", "gone.example.org"}, "15212")
+	want := []string{"203.0.113.1:15212", "203.0.113.2:15212", "[2001:db8::1]:15212", "203.0.113.2:9999"}
+	if !slices.Equal(peers, want) {
+		t.Fatalf("peers = %v, want %v", peers, want)
+	}
+	if err == nil {
+		t.Fatal("the seed that did not resolve should be reported")
+	}
+
+	peers, err = resolveSeeds(context.Background(), lookup, []string{"big.example.org"}, "15212")
+	if err != nil || len(peers) != maxSeedPeers {
+		t.Fatalf("got %d peers (%v), want %d", len(peers), err, maxSeedPeers)
+	}
+}
+
+func TestSetSeedPeers(t *testing.T) {
+	conf := flnd.DefaultConfig()
+	conf.NeutrinoMode.AddPeers = []string{"peer.example.org:15212", "203.0.113.1:15212"}
+	s := &Service{flndConfig: &conf, seedPeers: []string{"203.0.113.1:15212"}}
+
+	added := s.setSeedPeers([]string{"203.0.113.2:15212"})
+	if !slices.Equal(added, []string{"203.0.113.2:15212"}) {
+		t.Fatalf("added = %v", added)
+	}
+	want := []string{"peer.example.org:15212", "203.0.113.2:15212"}
+	if got := s.flndConfig.NeutrinoMode.AddPeers; !slices.Equal(got, want) {
+		t.Fatalf("addpeer = %v, want %v", got, want)
+	}
+
+	addPeers := []string{"other.example.org:15212"}
+	s.applyChange(ConfigChange{AddPeers: &addPeers})
+	want = []string{"other.example.org:15212", "203.0.113.2:15212"}
+	if got := s.flndConfig.NeutrinoMode.AddPeers; !slices.Equal(got, want) {
+		t.Fatalf("addpeer after a reload = %v, want %v", got, want)
+	}
+	if !slices.Equal(addPeers, []string{"other.example.org:15212"}) {
+		t.Fatalf("the reloaded list was changed: %v", addPeers)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/flokiorg/flnd/rpcperms"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Daemon errors callers can test with errors.Is. RPC errors are mapped to
+// them as they come back from the daemon.
+var (
+	ErrWalletLocked      = errors.New("wallet locked")
+	ErrWalletUnlocked    = errors.New("wallet already unlocked")
+	ErrRPCStarting       = errors.New("wallet RPC server starting")
+	ErrInvalidPassphrase = errors.New("invalid passphrase")
+)
+
+// rpcErrorKinds maps daemon errors to the errors above, first match wins.
+// The daemon sends most of them as plain messages, so the messages of the
+// upstream error values are compared where there is no better status code.
+var rpcErrorKinds = []struct {
+	kind    error
+	matches func(*status.Status) bool
+}{
+	{context.Canceled, hasCode(codes.Canceled)},
+	{context.DeadlineExceeded, hasCode(codes.DeadlineExceeded)},
+	{ErrWalletNotFound, hasMessage(rpcperms.ErrNoWallet.Error())},
+	{ErrWalletLocked, hasMessage(rpcperms.ErrWalletLocked.Error())},
+	{ErrWalletUnlocked, hasMessage(rpcperms.ErrWalletUnlocked.Error())},
+	{ErrRPCStarting, hasMessage(rpcperms.ErrRPCStarting.Error())},
+	{ErrWalletAlreadyExists, hasMessage("wallet already exists")},
+	// The wallet reports which key failed to decrypt after the prefix.
+	{ErrInvalidPassphrase, hasMessagePrefix("invalid passphrase")},
+}
+
+func hasCode(code codes.Code) func(*status.Status) bool {
+	return func(st *status.Status) bool { return st.Code() == code }
+}
+
+func hasMessage(message string) func(*status.Status) bool {
+	return func(st *status.Status) bool { return st.Message() == message }
+}
+
+func hasMessagePrefix(prefix string) func(*status.Status) bool {
+	return func(st *status.Status) bool { return strings.HasPrefix(st.Message(), prefix) }
+}
+
+// rpcError is a daemon error recognised as one of the known kinds. It keeps
+// the daemon message and status.
+type rpcError struct {
+	kind error
+	err  error
+}
+
+func (e *rpcError) Error() string   { return e.err.Error() }
+func (e *rpcError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// mapRPCError returns err matching its known kind with errors.Is, or err
+// unchanged when it has none.
+func mapRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var mapped *rpcError
+	if errors.As(err, &mapped) {
+		return err
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, k := range rpcErrorKinds {
+		if k.matches(st) {
+			return &rpcError{kind: k.kind, err: err}
+		}
+	}
+	return err
+}
+
+// mapErrorsUnary and mapErrorsStream map the errors of every call made on the
+// daemon connection.
+func mapErrorsUnary(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return mapRPCError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+func mapErrorsStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, mapRPCError(err)
+	}
+	return &errorMappingStream{ClientStream: stream}, nil
+}
+
+type errorMappingStream struct {
+	grpc.ClientStream
+}
+
+func (s *errorMappingStream) RecvMsg(m any) error {
+	return mapRPCError(s.ClientStream.RecvMsg(m))
+}
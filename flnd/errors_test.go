@@ -0,0 +1,51 @@
+package flnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/flokiorg/flnd/rpcperms"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMapRPCError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{status.Error(codes.Unknown, rpcperms.ErrWalletLocked.Error()), ErrWalletLocked},
+		{status.Error(codes.Unknown, rpcperms.ErrRPCStarting.Error()), ErrRPCStarting},
+		{status.Error(codes.Unknown, rpcperms.ErrNoWallet.Error()), ErrWalletNotFound},
+		{status.Error(codes.Unknown, "invalid passphrase for master private key"), ErrInvalidPassphrase},
+		{status.Error(codes.Canceled, "context canceled"), context.Canceled},
+		{status.Error(codes.DeadlineExceeded, "context deadline exceeded"), context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		mapped := mapRPCError(tt.err)
+		if !errors.Is(mapped, tt.want) {
+			t.Errorf("mapRPCError(%v) does not match %v", tt.err, tt.want)
+		}
+		if mapped.Error() != tt.err.Error() {
+			t.Errorf("mapped message = %q, want %q", mapped.Error(), tt.err.Error())
+		}
+		if st, ok := status.FromError(mapped); !ok || st.Code() != status.Code(tt.err) {
+			t.Errorf("mapped %v lost its status", tt.err)
+		}
+	}
+
+	wrapped := fmt.Errorf("backend starting: %w", mapRPCError(status.Error(codes.Unknown, rpcperms.ErrRPCStarting.Error())))
+	if !isRPCError(wrapped, ErrRPCStarting) {
+		t.Error("wrapped mapped error not recognised")
+	}
+
+	other := status.Error(codes.Internal, "boom")
+	if mapRPCError(other) != other {
+		t.Error("unknown error was mapped")
+	}
+	if mapRPCError(nil) != nil {
+		t.Error("nil error was mapped")
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// subscriberQueueSize is how many updates wait for a slow subscriber
+	// before the oldest ones are dropped. Critical updates are never dropped.
+	subscriberQueueSize = 256
+
+	// eventHistorySize is how many past updates EventsSince can return.
+	eventHistorySize = 256
+
+	// criticalHealthTimeout bounds how long the client waits for the service
+	// to take a critical update off a full health channel.
+	criticalHealthTimeout = 5 * time.Second
+
+	subscriberFlushTimeout = 5 * time.Second
+)
+
+// critical reports whether u is a transition subscribers must not miss.
+func (u *Update) critical() bool {
+	return u.State == StatusUnlocked || u.State == StatusDown || u.State == StatusFailedPermanently
+}
+
+// subscriber queues the updates of one Subscribe channel so a slow reader
+// neither blocks the service nor loses updates.
+type subscriber struct {
+	ch     chan *Update
+	states map[Status]bool
+
+	mu      sync.Mutex
+	queue   []*Update
+	closing bool
+
+	wake    chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func newSubscriber(states []Status) *subscriber {
+	sub := &subscriber{
+		ch:      make(chan *Update, 5),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if len(states) > 0 {
+		sub.states = make(map[Status]bool, len(states))
+		for _, state := range states {
+			sub.states[state] = true
+		}
+	}
+	go sub.run()
+	return sub
+}
+
+// wants reports whether u passes the state filter of the subscriber.
+func (sub *subscriber) wants(u *Update) bool {
+	if sub.states == nil {
+		return u.State != StatusBalance
+	}
+	return sub.states[u.State]
+}
+
+// push queues u. With a full queue the oldest update that is not critical
+// makes room.
+func (sub *subscriber) push(u *Update) {
+	sub.mu.Lock()
+	if sub.closing {
+		sub.mu.Unlock()
+		return
+	}
+	if len(sub.queue) >= subscriberQueueSize {
+		for i, queued := range sub.queue {
+			if !queued.critical() {
+				sub.queue = append(sub.queue[:i], sub.queue[i+1:]...)
+				break
+			}
+		}
+	}
+	sub.queue = append(sub.queue, u)
+	sub.mu.Unlock()
+
+	select {
+	case sub.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next pops the oldest queued update. It returns false once the queue is
+// empty and the subscriber is closing.
+func (sub *subscriber) next() (*Update, bool) {
+	for {
+		sub.mu.Lock()
+		if len(sub.queue) > 0 {
+			u := sub.queue[0]
+			sub.queue[0] = nil
+			sub.queue = sub.queue[1:]
+			sub.mu.Unlock()
+			return u, true
+		}
+		closing := sub.closing
+		sub.mu.Unlock()
+		if closing {
+			return nil, false
+		}
+
+		select {
+		case <-sub.wake:
+		case <-sub.done:
+			return nil, false
+		}
+	}
+}
+
+func (sub *subscriber) run() {
+	defer close(sub.stopped)
+	for {
+		u, ok := sub.next()
+		if !ok {
+			return
+		}
+		select {
+		case sub.ch <- u:
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// cancel stops delivering right away. The channel is left open, as readers
+// stop reading before they unsubscribe.
+func (sub *subscriber) cancel() {
+	close(sub.done)
+}
+
+// close delivers final after the queued updates, then closes the channel.
+// Updates still queued after timeout are dropped.
+func (sub *subscriber) close(final *Update, timeout time.Duration) {
+	sub.push(final)
+	sub.mu.Lock()
+	sub.closing = true
+	sub.mu.Unlock()
+
+	select {
+	case sub.wake <- struct{}{}:
+	default:
+	}
+
+	select {
+	case <-sub.stopped:
+	case <-time.After(timeout):
+		close(sub.done)
+		<-sub.stopped
+	}
+	close(sub.ch)
+}
+
+// eventHistory keeps the latest updates in order of their sequence numbers.
+type eventHistory struct {
+	events []*Update
+}
+
+func (h *eventHistory) add(u *Update) {
+	if len(h.events) >= eventHistorySize {
+		copy(h.events, h.events[1:])
+		h.events = h.events[:len(h.events)-1]
+	}
+	h.events = append(h.events, u)
+}
+
+// since returns the updates numbered after seq and whether none of them have
+// been forgotten yet.
+func (h *eventHistory) since(seq uint64) ([]*Update, bool) {
+	if len(h.events) == 0 {
+		return nil, true
+	}
+	complete := h.events[0].Seq <= seq+1
+	var events []*Update
+	for _, u := range h.events {
+		if u.Seq > seq {
+			events = append(events, u)
+		}
+	}
+	return events, complete
+}
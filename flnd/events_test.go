@@ -0,0 +1,236 @@
+package flnd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc"
+)
+
+func TestSubscriberKeepsCriticalUpdates(t *testing.T) {
+	svc := &Service{lastEvent: &Update{State: StatusInit}}
+	sub := svc.Subscribe()
+
+	// Nobody reads while the queue overflows with routine updates.
+	svc.notifySubscribers(&Update{State: StatusLocked})
+	svc.notifySubscribers(&Update{State: StatusUnlocked})
+	for i := 0; i < subscriberQueueSize*2; i++ {
+		svc.notifySubscribers(&Update{State: StatusBlock, BlockHeight: uint32(i)})
+	}
+	svc.notifySubscribers(&Update{State: StatusDown})
+
+	var (
+		lastSeq          uint64
+		unlocked, down   bool
+		received, gapped int
+	)
+	timeout := time.After(5 * time.Second)
+	for !down {
+		select {
+		case u := <-sub:
+			if lastSeq > 0 && u.Seq <= lastSeq {
+				t.Fatalf("seq %d after %d", u.Seq, lastSeq)
+			}
+			if lastSeq > 0 && u.Seq > lastSeq+1 {
+				gapped++
+			}
+			lastSeq = u.Seq
+			received++
+			unlocked = unlocked || u.State == StatusUnlocked
+			down = u.State == StatusDown
+		case <-timeout:
+			t.Fatalf("down not delivered after %d updates", received)
+		}
+	}
+	if !unlocked {
+		t.Error("unlocked update dropped")
+	}
+	if gapped == 0 {
+		t.Error("overflow left no gap in seq")
+	}
+
+	events, complete := svc.EventsSince(lastSeq - 10)
+	if !complete || len(events) != 10 || events[0].Seq != lastSeq-9 {
+		t.Errorf("EventsSince = %d events, complete %v", len(events), complete)
+	}
+	if _, complete := svc.EventsSince(0); complete {
+		t.Error("EventsSince(0) complete after the history wrapped")
+	}
+
+	svc.Unsubscribe(sub)
+	svc.notifySubscribers(&Update{State: StatusReady})
+	select {
+	case u := <-sub:
+		t.Errorf("update %v delivered after unsubscribe", u.State)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestUnsubscribeAllFlushes(t *testing.T) {
+	svc := &Service{lastEvent: &Update{State: StatusInit}}
+	sub := svc.Subscribe()
+	svc.notifySubscribers(&Update{State: StatusReady})
+
+	go svc.unsubscribeAll()
+
+	var states []Status
+	for u := range sub {
+		states = append(states, u.State)
+	}
+	if len(states) != 3 || states[1] != StatusReady || states[2] != StatusDown {
+		t.Fatalf("states = %v", states)
+	}
+}
+
+func TestSubscribeFiltered(t *testing.T) {
+	svc := &Service{lastEvent: &Update{State: StatusInit}}
+	sub := svc.SubscribeFiltered(StatusReady, StatusTransaction)
+	defer svc.Unsubscribe(sub)
+
+	for _, state := range []Status{StatusBlock, StatusReady, StatusBlock, StatusScanning, StatusTransaction} {
+		svc.notifySubscribers(&Update{State: state})
+	}
+
+	for _, want := range []Status{StatusReady, StatusTransaction} {
+		select {
+		case u := <-sub:
+			if u.State != want {
+				t.Fatalf("state = %v, want %v", u.State, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%v not delivered", want)
+		}
+	}
+	select {
+	case u := <-sub:
+		t.Errorf("unexpected update %v", u.State)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestBalanceUpdatesOptIn(t *testing.T) {
+	svc := &Service{lastEvent: &Update{State: StatusInit}, balanceKick: make(chan struct{}, 1)}
+	all := svc.Subscribe()
+	defer svc.Unsubscribe(all)
+
+	svc.notifySubscribers(&Update{State: StatusReady})
+	svc.publishBalance(&lnrpc.WalletBalanceResponse{ConfirmedBalance: 42})
+	svc.publishBalance(&lnrpc.WalletBalanceResponse{ConfirmedBalance: 42})
+
+	select {
+	case <-svc.balanceKick:
+	default:
+		t.Error("ready did not ask for the balance")
+	}
+	if b := svc.LastBalance(); b == nil || b.ConfirmedBalance != 42 {
+		t.Fatalf("LastBalance = %v", b)
+	}
+	if last := svc.GetLastEvent(); last.State != StatusReady {
+		t.Errorf("last event = %v, want ready", last.State)
+	}
+
+	balances := svc.SubscribeFiltered(StatusBalance)
+	defer svc.Unsubscribe(balances)
+	select {
+	case u := <-balances:
+		if u.State != StatusBalance || u.Balance.ConfirmedBalance != 42 {
+			t.Fatalf("update = %v %v", u.State, u.Balance)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("current balance not replayed")
+	}
+	select {
+	case u := <-balances:
+		t.Errorf("unchanged balance published again: %v", u.State)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	for _, want := range []Status{StatusInit, StatusReady} {
+		if u := <-all; u.State != want {
+			t.Fatalf("state = %v, want %v", u.State, want)
+		}
+	}
+	select {
+	case u := <-all:
+		t.Errorf("balance update on a plain subscription: %v", u.State)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	svc.notifySubscribers(&Update{State: StatusLocked})
+	if svc.LastBalance() != nil {
+		t.Error("balance kept after the wallet locked")
+	}
+}
+
+func TestWaitForState(t *testing.T) {
+	svc := &Service{lastEvent: &Update{State: StatusInit}}
+	if got := svc.Status(); got != StatusInit {
+		t.Fatalf("status = %v, want %v", got, StatusInit)
+	}
+
+	svc.notifySubscribers(&Update{State: StatusReady})
+	svc.notifySubscribers(&Update{State: StatusBlock})
+	if got := svc.Status(); got != StatusReady {
+		t.Fatalf("status after a block = %v, want %v", got, StatusReady)
+	}
+	u, err := svc.WaitForState(context.Background(), StatusReady)
+	if err != nil || u.State != StatusReady {
+		t.Fatalf("wait for the current state = %v, %v", u, err)
+	}
+
+	done := make(chan *Update, 1)
+	go func() {
+		u, err := svc.WaitForState(context.Background(), StatusLocked)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- u
+	}()
+	// The waiter subscribes before anything is published.
+	for {
+		svc.subMu.Lock()
+		n := len(svc.subs)
+		svc.subMu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	svc.notifySubscribers(&Update{State: StatusDown})
+	svc.notifySubscribers(&Update{State: StatusLocked})
+	select {
+	case u := <-done:
+		if u == nil || u.State != StatusLocked {
+			t.Fatalf("wait returned %v", u)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("wait did not return")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := svc.WaitForState(ctx, StatusReady); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wait past the deadline = %v", err)
+	}
+}
+
+func TestStopWithTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	svc := &Service{lastEvent: &Update{State: StatusInit}, ctx: ctx, cancel: cancel}
+
+	// A goroutine of the service that does not stop holds Stop.
+	svc.wg.Add(1)
+	if err := svc.StopWithTimeout(20 * time.Millisecond); !errors.Is(err, ErrShutdownTimeout) {
+		t.Fatalf("stop = %v, want %v", err, ErrShutdownTimeout)
+	}
+	if ctx.Err() == nil {
+		t.Error("service context not cancelled")
+	}
+
+	svc.wg.Done()
+	if err := svc.StopWithTimeout(time.Second); err != nil {
+		t.Fatalf("stop once stopped = %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// feeCacheTTL is how long an answer is served again without asking
+	// the sources.
+	feeCacheTTL = time.Minute
+	// feeStaleTTL is how long the last answer stands in when no source
+	// answers.
+	feeStaleTTL = 30 * time.Minute
+	// feePrimaryRetry is how long a fallback that answered is kept before
+	// the first source is tried again.
+	feePrimaryRetry = 5 * time.Minute
+
+	feeSourceTimeout     = 10 * time.Second
+	maxFeeResponseLength = 1 << 16
+)
+
+// FeeSource is the state of the fee APIs the daemon reads through twallet.
+type FeeSource struct {
+	// URL is the source of the last answer, empty before the first one.
+	URL string
+	// Fetched is when the last answer was read.
+	Fetched time.Time
+	// Sources is the number of fee APIs configured.
+	Sources int
+	// Err is why the last attempt failed, nil when it succeeded.
+	Err error
+}
+
+// FeeSources returns the fee APIs to try in order: feeurl, then the
+// fallbacks, without the empty or repeated ones.
+func FeeSources(primary string, fallbacks []string) []string {
+	var sources []string
+	for _, source := range append([]string{primary}, fallbacks...) {
+		if source = strings.TrimSpace(source); source != "" && !slices.Contains(sources, source) {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// feeProxy serves the fee API to the daemon on a local address from the
+// first of its sources that answers. The answers are cached, and the last
+// one stands in for a while when every source is down, so the daemon is
+// not left without fee estimates by one API going away.
+type feeProxy struct {
+	client *http.Client
+	now    func() time.Time
+
+	// fetchMu lets one request ask the sources, the ones waiting on it
+	// reuse its answer.
+	fetchMu sync.Mutex
+
+	mu           sync.Mutex
+	sources      []string
+	active       string
+	body         []byte
+	fetched      time.Time
+	lastErr      error
+	primaryTried time.Time
+	server       *http.Server
+	url          string
+}
+
+func newFeeProxy(transport http.RoundTripper) *feeProxy {
+	return &feeProxy{
+		client: &http.Client{Timeout: feeSourceTimeout, Transport: transport},
+		now:    time.Now,
+	}
+}
+
+// route serves sources from now on and returns the URL the daemon reads the
+// fees at: the proxy, the first source when the proxy cannot listen, or
+// none without sources.
+func (p *feeProxy) route(sources []string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !slices.Equal(sources, p.sources) {
+		p.sources = sources
+		p.active, p.body, p.fetched, p.lastErr = "", nil, time.Time{}, nil
+	}
+	if len(sources) == 0 {
+		return ""
+	}
+	if p.server == nil {
+		if err := p.listen(); err != nil {
+			p.lastErr = fmt.Errorf("fee failover unavailable: %w", err)
+			return sources[0]
+		}
+	}
+	return p.url
+}
+
+func (p *feeProxy) listen() error {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: feeSourceTimeout}
+	p.url = fmt.Sprintf("http://%s/fees", l.Addr())
+	go p.server.Serve(l)
+	return nil
+}
+
+func (p *feeProxy) close() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.server != nil {
+		p.server.Close()
+		p.server = nil
+	}
+}
+
+func (p *feeProxy) status() FeeSource {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return FeeSource{URL: p.active, Fetched: p.fetched, Sources: len(p.sources), Err: p.lastErr}
+}
+
+func (p *feeProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := p.answer()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// answer returns the cached answer while it is fresh, then asks the sources
+// in order until one answers.
+func (p *feeProxy) answer() ([]byte, error) {
+	p.fetchMu.Lock()
+	defer p.fetchMu.Unlock()
+
+	p.mu.Lock()
+	if p.body != nil && p.now().Sub(p.fetched) < feeCacheTTL {
+		body := p.body
+		p.mu.Unlock()
+		return body, nil
+	}
+	order := p.order()
+	p.mu.Unlock()
+
+	var errs []error
+	for _, source := range order {
+		body, err := p.fetch(source)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source, err))
+			continue
+		}
+		p.mu.Lock()
+		// The sources may have changed while this one was asked.
+		if slices.Contains(p.sources, source) {
+			p.active, p.body, p.fetched, p.lastErr = source, body, p.now(), nil
+		}
+		p.mu.Unlock()
+		return body, nil
+	}
+
+	err := errors.Join(errs...)
+	if err == nil {
+		err = errors.New("no fee API configured")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastErr = err
+	if p.body != nil && p.now().Sub(p.fetched) < feeStaleTTL {
+		return p.body, nil
+	}
+	return nil, err
+}
+
+// order returns the sources in the order to ask them: the fallback that
+// answered last first, until it is time to try the first source again.
+// p.mu is held.
+func (p *feeProxy) order() []string {
+	order := slices.Clone(p.sources)
+	i := slices.Index(order, p.active)
+	if i > 0 && p.now().Sub(p.primaryTried) < feePrimaryRetry {
+		return append([]string{p.active}, slices.Delete(order, i, i+1)...)
+	}
+	p.primaryTried = p.now()
+	return order
+}
+
+func (p *feeProxy) fetch(source string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("returned %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeeResponseLength))
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(body) {
+		return nil, errors.New("answered with invalid JSON")
+	}
+	return body, nil
+}
@@ -0,0 +1,106 @@
+package flnd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func feeAPI(t *testing.T, body string, up *atomic.Bool, calls *atomic.Int32) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !up.Load() {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func readFees(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestFeeProxyFailover(t *testing.T) {
+	var primaryUp, fallbackUp atomic.Bool
+	var primaryCalls, fallbackCalls atomic.Int32
+	primary := feeAPI(t, `{"fastestFee":10}`, &primaryUp, &primaryCalls)
+	fallback := feeAPI(t, `{"fastestFee":20}`, &fallbackUp, &fallbackCalls)
+	fallbackUp.Store(true)
+
+	clock := time.Now()
+	p := newFeeProxy(nil)
+	p.now = func() time.Time { return clock }
+	defer p.close()
+
+	url := p.route(FeeSources(primary, []string{fallback, primary, ""}))
+	if url == primary || url == "" {
+		t.Fatalf("route = %q, want the proxy", url)
+	}
+	if got := p.status().Sources; got != 2 {
+		t.Fatalf("sources = %d, want the repeated and empty ones dropped", got)
+	}
+
+	// The primary is down, the fallback answers.
+	if code, body := readFees(t, url); code != http.StatusOK || body != `{"fastestFee":20}` {
+		t.Fatalf("got %d %s", code, body)
+	}
+	if got := p.status().URL; got != fallback {
+		t.Fatalf("active = %s, want the fallback", got)
+	}
+
+	// Cached within feeCacheTTL.
+	readFees(t, url)
+	if fallbackCalls.Load() != 1 {
+		t.Fatalf("fallback asked %d times, want the cached answer", fallbackCalls.Load())
+	}
+
+	// The fallback stays in use while the primary is not retried.
+	primaryUp.Store(true)
+	clock = clock.Add(2 * feeCacheTTL)
+	if _, body := readFees(t, url); body != `{"fastestFee":20}` {
+		t.Fatalf("got %s, want the fallback kept", body)
+	}
+
+	// Then the primary is tried first again.
+	clock = clock.Add(feePrimaryRetry)
+	if _, body := readFees(t, url); body != `{"fastestFee":10}` {
+		t.Fatalf("got %s, want the primary back", body)
+	}
+
+	// Everything down: the last answer stands in, then it is too old.
+	primaryUp.Store(false)
+	fallbackUp.Store(false)
+	clock = clock.Add(2 * feeCacheTTL)
+	if code, body := readFees(t, url); code != http.StatusOK || body != `{"fastestFee":10}` {
+		t.Fatalf("got %d %s, want the stale answer", code, body)
+	}
+	if p.status().Err == nil {
+		t.Fatal("the failure should be reported")
+	}
+	clock = clock.Add(feeStaleTTL)
+	if code, _ := readFees(t, url); code != http.StatusBadGateway {
+		t.Fatalf("got %d, want %d", code, http.StatusBadGateway)
+	}
+}
+
+func TestFeeProxyNoSources(t *testing.T) {
+	p := newFeeProxy(nil)
+	defer p.close()
+	if url := p.route(FeeSources("", nil)); url != "" {
+		t.Fatalf("route = %q, want none", url)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc/walletrpc"
+)
+
+// feeEstimatesTTL is how long a fee rate is reused before it is asked for
+// again. Rates move with blocks, so a short reuse only saves the calls of
+// screens refreshing together.
+const feeEstimatesTTL = 30 * time.Second
+
+// FeeTargets are the confirmation targets, in blocks, offered when sending,
+// from the fastest to the cheapest.
+var FeeTargets = []int32{1, 3, 6, 144}
+
+type feeEstimate struct {
+	rate    float64
+	fetched time.Time
+}
+
+// FeeEstimates returns the fee rate, in loki per virtual byte, the wallet
+// would use to confirm within each of targets blocks. Rates fetched less than
+// feeEstimatesTTL ago are reused and the others are asked for together.
+// The targets whose rate could not be fetched are left out of the rates,
+// which come with the first of their errors.
+func (c *Client) FeeEstimates(ctx context.Context, targets ...int32) (map[int32]float64, error) {
+	if c.closing {
+		return nil, ErrDaemonNotRunning
+	}
+
+	rates := make(map[int32]float64, len(targets))
+	var missing []int32
+	c.mu.Lock()
+	for _, target := range targets {
+		if e, ok := c.feeCache[target]; ok && time.Since(e.fetched) < feeEstimatesTTL {
+			rates[target] = e.rate
+			continue
+		}
+		if !slices.Contains(missing, target) {
+			missing = append(missing, target)
+		}
+	}
+	c.mu.Unlock()
+	if len(missing) == 0 {
+		return rates, nil
+	}
+
+	ctx, cancel := c.rpcContext(ctx, c.rpcTimeouts().fees())
+	defer cancel()
+
+	type result struct {
+		target int32
+		rate   float64
+		err    error
+	}
+	results := make(chan result, len(missing))
+	for _, target := range missing {
+		go func() {
+			resp, err := c.walletKit.EstimateFee(ctx, &walletrpc.EstimateFeeRequest{ConfTarget: target})
+			if err != nil {
+				results <- result{target: target, err: err}
+				return
+			}
+			// One virtual byte weighs four weight units.
+			results <- result{target: target, rate: float64(resp.GetSatPerKw()) * 4 / 1000}
+		}()
+	}
+
+	var firstErr error
+	fetched := make([]result, 0, len(missing))
+	for range missing {
+		r := <-results
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			continue
+		}
+		fetched = append(fetched, r)
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	if c.feeCache == nil {
+		c.feeCache = make(map[int32]feeEstimate, len(fetched))
+	}
+	for _, r := range fetched {
+		c.feeCache[r.target] = feeEstimate{rate: r.rate, fetched: now}
+		rates[r.target] = r.rate
+	}
+	c.mu.Unlock()
+
+	return rates, firstErr
+}
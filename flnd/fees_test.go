@@ -0,0 +1,109 @@
+package flnd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flokiorg/flnd"
+	"github.com/flokiorg/flnd/lnrpc/walletrpc"
+	"google.golang.org/grpc"
+)
+
+type feeWalletKit struct {
+	walletrpc.WalletKitClient
+	mu    sync.Mutex
+	calls map[int32]int
+	// failing targets return an error.
+	failing map[int32]bool
+}
+
+func (k *feeWalletKit) EstimateFee(ctx context.Context, in *walletrpc.EstimateFeeRequest, opts ...grpc.CallOption) (*walletrpc.EstimateFeeResponse, error) {
+	k.mu.Lock()
+	k.calls[in.ConfTarget]++
+	failing := k.failing[in.ConfTarget]
+	k.mu.Unlock()
+	if failing {
+		return nil, errors.New("no estimate")
+	}
+	// The rate drops by 1 loki/kw per block of confirmation target.
+	return &walletrpc.EstimateFeeResponse{SatPerKw: int64(1000 - in.ConfTarget)}, nil
+}
+
+func (k *feeWalletKit) total() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	n := 0
+	for _, calls := range k.calls {
+		n += calls
+	}
+	return n
+}
+
+func TestFeeEstimatesCache(t *testing.T) {
+	kit := &feeWalletKit{calls: map[int32]int{}}
+	c := &Client{walletKit: kit, ctx: context.Background(), config: &flnd.Config{}}
+
+	rates, err := c.FeeEstimates(context.Background(), 1, 6, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 2 || rates[1] != 3.996 || rates[6] != 3.976 {
+		t.Fatalf("rates = %v", rates)
+	}
+	if n := kit.total(); n != 2 {
+		t.Fatalf("calls = %d, want 2", n)
+	}
+
+	if _, err := c.FeeRate(context.Background(), 6); err != nil {
+		t.Fatal(err)
+	}
+	rates, err = c.FeeEstimates(context.Background(), 1, 3, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 3 {
+		t.Fatalf("rates = %v", rates)
+	}
+	if n := kit.total(); n != 3 || kit.calls[3] != 1 {
+		t.Fatalf("calls = %v, want only target 3 fetched", kit.calls)
+	}
+
+	c.mu.Lock()
+	e := c.feeCache[1]
+	e.fetched = time.Now().Add(-feeEstimatesTTL)
+	c.feeCache[1] = e
+	c.mu.Unlock()
+
+	if _, err := c.FeeEstimates(context.Background(), 1, 3); err != nil {
+		t.Fatal(err)
+	}
+	if kit.calls[1] != 2 || kit.calls[3] != 1 {
+		t.Fatalf("calls = %v, want the stale target fetched again", kit.calls)
+	}
+}
+
+func TestFeeEstimatesPartial(t *testing.T) {
+	kit := &feeWalletKit{calls: map[int32]int{}, failing: map[int32]bool{144: true}}
+	c := &Client{walletKit: kit, ctx: context.Background(), config: &flnd.Config{}}
+
+	rates, err := c.FeeEstimates(context.Background(), FeeTargets...)
+	if err == nil {
+		t.Fatal("want the error of the failed target")
+	}
+	if len(rates) != len(FeeTargets)-1 || rates[1] != 3.996 {
+		t.Fatalf("rates = %v, want all but target 144", rates)
+	}
+	if _, ok := rates[144]; ok {
+		t.Fatalf("rates = %v, want target 144 left out", rates)
+	}
+
+	if rate, err := c.FeeRate(context.Background(), 6); err != nil || rate != 3.976 {
+		t.Fatalf("FeeRate(6) = %v, %v", rate, err)
+	}
+	if _, err := c.FeeRate(context.Background(), 144); err == nil {
+		t.Fatal("FeeRate(144) succeeded, want its error")
+	}
+}
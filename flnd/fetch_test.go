@@ -0,0 +1,173 @@
+package flnd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/flokiorg/flnd"
+	"github.com/flokiorg/flnd/lnrpc"
+	"google.golang.org/grpc"
+)
+
+// pagingLightning serves GetTransactions by position, as lnd does.
+type pagingLightning struct {
+	lnrpc.LightningClient
+	txs   []*lnrpc.Transaction
+	calls atomic.Int32
+}
+
+func (l *pagingLightning) GetTransactions(ctx context.Context, in *lnrpc.GetTransactionsRequest, opts ...grpc.CallOption) (*lnrpc.TransactionDetails, error) {
+	l.calls.Add(1)
+	start := min(int(in.IndexOffset), len(l.txs))
+	end := min(start+int(in.MaxTransactions), len(l.txs))
+	resp := &lnrpc.TransactionDetails{Transactions: l.txs[start:end]}
+	if end > start {
+		resp.LastIndex = uint64(end - 1)
+	}
+	return resp, nil
+}
+
+func TestFetchTransactionsColdCache(t *testing.T) {
+	ln := &pagingLightning{}
+	const count = 5*transactionPageSize + 37
+	for i := range count {
+		ln.txs = append(ln.txs, &lnrpc.Transaction{
+			TxHash:      fmt.Sprintf("tx%04d", i),
+			TimeStamp:   int64(i),
+			BlockHeight: int32(i + 1),
+		})
+	}
+
+	c := &Client{
+		lnClient: ln,
+		ctx:      context.Background(),
+		config:   &flnd.Config{},
+		cache:    &txCache{Dirty: true},
+	}
+
+	var progress int
+	txs, err := c.FetchTransactionsWithOptions(context.Background(), FetchTransactionsOptions{
+		IgnoreLimit: true,
+		OnProgress:  func(n int) { progress = n },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != count || progress != count {
+		t.Fatalf("fetched %d, progress %d, want %d", len(txs), progress, count)
+	}
+	for i, tx := range txs {
+		if want := fmt.Sprintf("tx%04d", count-1-i); tx.TxHash != want {
+			t.Fatalf("txs[%d] = %s, want %s", i, tx.TxHash, want)
+		}
+	}
+	if c.cache.LastIndex != count-1 {
+		t.Errorf("LastIndex = %d, want %d", c.cache.LastIndex, count-1)
+	}
+
+	// Warm, a new transaction is picked up after the last index.
+	ln.txs = append(ln.txs, &lnrpc.Transaction{TxHash: "new", TimeStamp: count, BlockHeight: count + 1})
+	c.cache.Dirty = true
+	txs, err = c.FetchTransactionsWithOptions(context.Background(), FetchTransactionsOptions{IgnoreLimit: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != count+1 || txs[0].TxHash != "new" {
+		t.Fatalf("after refresh got %d, newest %s", len(txs), txs[0].TxHash)
+	}
+}
+
+func TestFetchTransactionsFullPages(t *testing.T) {
+	ln := &pagingLightning{}
+	const count = 2 * transactionPageSize
+	for i := range count {
+		ln.txs = append(ln.txs, &lnrpc.Transaction{TxHash: fmt.Sprintf("tx%04d", i), TimeStamp: int64(i)})
+	}
+	c := &Client{
+		lnClient: ln,
+		ctx:      context.Background(),
+		config:   &flnd.Config{},
+	}
+
+	// The history fills whole pages, the short page ending it is empty.
+	txs, lastIndex, err := c.fetchTransactionsParallel(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != count {
+		t.Fatalf("fetched %d, want %d", len(txs), count)
+	}
+	if lastIndex != count-1 {
+		t.Errorf("last index = %d, want %d", lastIndex, count-1)
+	}
+}
+
+func TestStoreTransactionsTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), txCacheFilename)
+	c := &Client{
+		cache:        &txCache{Dirty: true},
+		cachePath:    path,
+		txCacheLimit: 2,
+	}
+	c.state.syncedHeight = 100
+
+	var txs []*lnrpc.Transaction
+	for i := range 3 {
+		txs = append(txs, &lnrpc.Transaction{TxHash: fmt.Sprintf("tx%d", i), TimeStamp: int64(i), BlockHeight: int32(i + 1)})
+	}
+	c.storeTransactions(txs[:2], 1, FetchTransactionsOptions{}, 0)
+	if _, err := loadTxCache(path); err != nil {
+		t.Fatalf("cache not saved under the limit: %v", err)
+	}
+
+	// Past the limit the oldest is evicted, the file saved before stays.
+	c.storeTransactions(txs[2:], 2, FetchTransactionsOptions{}, 0)
+	if !c.cache.Truncated {
+		t.Fatal("cache not truncated")
+	}
+	cache, err := loadTxCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cache.LastIndex != 1 || cache.Txs.Len() != 2 {
+		t.Fatalf("saved %d up to index %d, want 2 up to 1", cache.Txs.Len(), cache.LastIndex)
+	}
+}
+
+func TestStreamTransactionsColdCache(t *testing.T) {
+	ln := &pagingLightning{}
+	const count = 3 * transactionPageSize
+	for i := range count {
+		ln.txs = append(ln.txs, &lnrpc.Transaction{TxHash: fmt.Sprintf("tx%04d", i), TimeStamp: int64(i)})
+	}
+	c := &Client{
+		lnClient: ln,
+		ctx:      context.Background(),
+		config:   &flnd.Config{},
+		cache:    &txCache{Dirty: true},
+	}
+
+	seen := make(map[string]bool)
+	total := 0
+	for page := range c.StreamTransactions(context.Background(), FetchTransactionsOptions{}) {
+		if page.Err != nil {
+			t.Fatal(page.Err)
+		}
+		for _, tx := range page.Transactions {
+			if seen[tx.TxHash] {
+				t.Fatalf("%s delivered twice", tx.TxHash)
+			}
+			seen[tx.TxHash] = true
+		}
+		total = page.Total
+	}
+	if total != count || len(seen) != count {
+		t.Fatalf("streamed %d (%d distinct), want %d", total, len(seen), count)
+	}
+	if c.cache.Txs.Len() != count {
+		t.Errorf("cached %d, want %d", c.cache.Txs.Len(), count)
+	}
+}
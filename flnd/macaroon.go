@@ -0,0 +1,105 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flokiorg/flnd/lnrpc"
+)
+
+// MacaroonScope is what a macaroon handed out to another application lets
+// it do on the daemon.
+type MacaroonScope string
+
+const (
+	// MacaroonAdmin is the admin macaroon itself: full access.
+	MacaroonAdmin MacaroonScope = "admin"
+	// MacaroonReadOnly reads the node and wallet state but changes nothing.
+	MacaroonReadOnly MacaroonScope = "readonly"
+	// MacaroonInvoice creates and reads invoices and new addresses, for
+	// services that only get paid.
+	MacaroonInvoice MacaroonScope = "invoice"
+)
+
+// MacaroonScopes lists the scopes in the order they are offered, the least
+// powerful first.
+var MacaroonScopes = []MacaroonScope{MacaroonReadOnly, MacaroonInvoice, MacaroonAdmin}
+
+// readOnlyEntities are the entities of the daemon's own readonly macaroon.
+var readOnlyEntities = []string{
+	"onchain", "offchain", "address", "message", "peers", "info", "invoices", "signer", "macaroon",
+}
+
+func (s MacaroonScope) String() string {
+	switch s {
+	case MacaroonAdmin:
+		return "Admin"
+	case MacaroonReadOnly:
+		return "Read-only"
+	case MacaroonInvoice:
+		return "Invoice"
+	}
+	return string(s)
+}
+
+// Permissions returns the permissions a macaroon of the scope is baked
+// with, nil for the admin one which is not baked.
+func (s MacaroonScope) Permissions() ([]*lnrpc.MacaroonPermission, error) {
+	switch s {
+	case MacaroonAdmin:
+		return nil, nil
+
+	case MacaroonReadOnly:
+		perms := make([]*lnrpc.MacaroonPermission, 0, len(readOnlyEntities))
+		for _, entity := range readOnlyEntities {
+			perms = append(perms, &lnrpc.MacaroonPermission{Entity: entity, Action: "read"})
+		}
+		return perms, nil
+
+	case MacaroonInvoice:
+		return []*lnrpc.MacaroonPermission{
+			{Entity: "invoices", Action: "read"},
+			{Entity: "invoices", Action: "write"},
+			{Entity: "address", Action: "read"},
+			{Entity: "address", Action: "write"},
+			{Entity: "onchain", Action: "read"},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown macaroon scope %q", string(s))
+}
+
+// BakeMacaroon bakes a new macaroon with the given permissions and returns
+// it hex encoded.
+func (c *Client) BakeMacaroon(ctx context.Context, perms []*lnrpc.MacaroonPermission) (string, error) {
+	if c.closing {
+		return "", ErrDaemonNotRunning
+	}
+	if len(perms) == 0 {
+		return "", fmt.Errorf("a macaroon needs at least one permission")
+	}
+	ctx, cancel := c.rpcContext(ctx, 0)
+	defer cancel()
+
+	resp, err := c.lnClient.BakeMacaroon(ctx, &lnrpc.BakeMacaroonRequest{Permissions: perms})
+	if err != nil {
+		return "", fmt.Errorf("failed to bake macaroon: %w", err)
+	}
+	return resp.Macaroon, nil
+}
+
+// ScopedMacaroon returns a hex encoded macaroon of the scope, baking a new
+// one unless it is the admin scope.
+func (c *Client) ScopedMacaroon(ctx context.Context, scope MacaroonScope) (string, error) {
+	perms, err := scope.Permissions()
+	if err != nil {
+		return "", err
+	}
+	if scope == MacaroonAdmin {
+		return c.adminMacHex, nil
+	}
+	return c.BakeMacaroon(ctx, perms)
+}
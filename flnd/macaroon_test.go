@@ -0,0 +1,74 @@
+package flnd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flokiorg/flnd"
+	"github.com/flokiorg/flnd/lnrpc"
+	"google.golang.org/grpc"
+)
+
+// bakingLightning records the permissions macaroons are baked with.
+type bakingLightning struct {
+	lnrpc.LightningClient
+	perms []*lnrpc.MacaroonPermission
+}
+
+func (l *bakingLightning) BakeMacaroon(ctx context.Context, in *lnrpc.BakeMacaroonRequest, opts ...grpc.CallOption) (*lnrpc.BakeMacaroonResponse, error) {
+	l.perms = in.Permissions
+	return &lnrpc.BakeMacaroonResponse{Macaroon: "baked"}, nil
+}
+
+func TestMacaroonScopePermissions(t *testing.T) {
+	perms, err := MacaroonReadOnly.Permissions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range perms {
+		if p.Action != "read" {
+			t.Errorf("read-only macaroon can %s %s", p.Action, p.Entity)
+		}
+	}
+
+	perms, err = MacaroonInvoice.Permissions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range perms {
+		if p.Action == "write" && p.Entity != "invoices" && p.Entity != "address" {
+			t.Errorf("invoice macaroon can write %s", p.Entity)
+		}
+	}
+
+	if _, err := MacaroonScope("bogus").Permissions(); err == nil {
+		t.Error("unknown scope has permissions")
+	}
+}
+
+func TestScopedMacaroon(t *testing.T) {
+	ln := &bakingLightning{}
+	c := &Client{
+		lnClient:    ln,
+		ctx:         context.Background(),
+		config:      &flnd.Config{},
+		adminMacHex: "admin",
+	}
+
+	mac, err := c.ScopedMacaroon(context.Background(), MacaroonAdmin)
+	if err != nil || mac != "admin" {
+		t.Fatalf("admin macaroon = %q, %v", mac, err)
+	}
+	if ln.perms != nil {
+		t.Fatal("the admin macaroon was baked")
+	}
+
+	mac, err = c.ScopedMacaroon(context.Background(), MacaroonInvoice)
+	if err != nil || mac != "baked" {
+		t.Fatalf("invoice macaroon = %q, %v", mac, err)
+	}
+	want, _ := MacaroonInvoice.Permissions()
+	if len(ln.perms) != len(want) {
+		t.Fatalf("baked with %d permissions, want %d", len(ln.perms), len(want))
+	}
+}
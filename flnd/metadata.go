@@ -0,0 +1,137 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"google.golang.org/protobuf/proto"
+)
+
+const labelsFilename = "labels.json"
+
+// MetadataPath returns where the file name of network is kept in the
+// metadata directory, the state twallet keeps itself away from the daemon
+// data.
+func MetadataPath(dir, network, name string) string {
+	return filepath.Join(dir, network, name)
+}
+
+// labelStore keeps a copy of the transaction labels in the metadata
+// directory. The daemon keeps its labels with the wallet transactions, so a
+// reset of them or a restore drops them; the copy puts them back.
+type labelStore struct {
+	path string
+
+	mu     sync.Mutex
+	labels map[string]string // by txid
+}
+
+// newLabelStore returns the store of network under dir, nil without a
+// metadata directory.
+func newLabelStore(dir, network string) *labelStore {
+	if dir == "" || network == "" {
+		return nil
+	}
+	return &labelStore{path: MetadataPath(dir, network, labelsFilename)}
+}
+
+// load reads the file the first time. mu is held.
+func (l *labelStore) load() error {
+	if l.labels != nil {
+		return nil
+	}
+	labels := map[string]string{}
+	data, err := os.ReadFile(l.path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(data, &labels); err != nil {
+			return err
+		}
+	}
+	l.labels = labels
+	return nil
+}
+
+// save writes the labels through a temporary file, so a crash leaves the
+// previous ones. mu is held.
+func (l *labelStore) save() error {
+	data, err := json.MarshalIndent(l.labels, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return err
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.path)
+}
+
+// set records the label of txid, an empty one removing it.
+func (l *labelStore) set(txid, label string) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.load(); err != nil {
+		return err
+	}
+	if l.labels[txid] == label {
+		return nil
+	}
+	if label == "" {
+		delete(l.labels, txid)
+	} else {
+		l.labels[txid] = label
+	}
+	return l.save()
+}
+
+// apply gives the transactions the daemon has no label for the one kept,
+// replacing them by copies so the cached ones are left alone, and keeps the
+// labels of the daemon it did not have yet. The store is best effort: a
+// file that cannot be read or written leaves txs as they are.
+func (l *labelStore) apply(txs []*lnrpc.Transaction) {
+	if l == nil || len(txs) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.load() != nil {
+		return
+	}
+	changed := false
+	for i, tx := range txs {
+		if tx == nil {
+			continue
+		}
+		label, kept := l.labels[tx.TxHash]
+		switch {
+		case tx.Label != "" && tx.Label != label:
+			l.labels[tx.TxHash] = tx.Label
+			changed = true
+		case tx.Label == "" && kept:
+			labeled := proto.Clone(tx).(*lnrpc.Transaction)
+			labeled.Label = label
+			txs[i] = labeled
+		}
+	}
+	if changed {
+		_ = l.save()
+	}
+}
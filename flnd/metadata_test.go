@@ -0,0 +1,54 @@
+package flnd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/flokiorg/flnd/lnrpc"
+)
+
+func TestLabelStore(t *testing.T) {
+	dir := t.TempDir()
+	store := newLabelStore(dir, "testnet3")
+	if err := store.set("aa", "rent"); err != nil {
+		t.Fatal(err)
+	}
+
+	// After a reset of the wallet transactions the daemon has no label.
+	cached := &lnrpc.Transaction{TxHash: "aa"}
+	txs := []*lnrpc.Transaction{cached, {TxHash: "bb", Label: "salary"}}
+	reopened := newLabelStore(dir, "testnet3")
+	reopened.apply(txs)
+	if txs[0].Label != "rent" || cached.Label != "" {
+		t.Fatalf("label %q, the daemon's transaction changed to %q", txs[0].Label, cached.Label)
+	}
+
+	// The labels of the daemon are kept too.
+	again := newLabelStore(dir, "testnet3")
+	txs = []*lnrpc.Transaction{{TxHash: "bb"}}
+	again.apply(txs)
+	if txs[0].Label != "salary" {
+		t.Fatalf("label of the daemon not kept: %q", txs[0].Label)
+	}
+
+	if err := again.set("bb", ""); err != nil {
+		t.Fatal(err)
+	}
+	txs = []*lnrpc.Transaction{{TxHash: "bb"}}
+	newLabelStore(dir, "testnet3").apply(txs)
+	if txs[0].Label != "" {
+		t.Fatalf("removed label still applied: %q", txs[0].Label)
+	}
+
+	if _, err := os.Stat(MetadataPath(dir, "testnet3", labelsFilename)); err != nil {
+		t.Fatal(err)
+	}
+	if newLabelStore("", "testnet3") != nil {
+		t.Fatal("no store without a metadata directory")
+	}
+	var none *labelStore
+	none.apply(txs)
+	if err := none.set("aa", "x"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"fmt"
+
+	"github.com/flokiorg/go-flokicoin/chaincfg"
+)
+
+// networkNames are the names --network accepts, in the order they are
+// listed.
+var networkNames = []string{"mainnet", "testnet3", "testnet4", "signet", "regtest", "simnet"}
+
+var networks = map[string]*chaincfg.Params{
+	"mainnet":  &chaincfg.MainNetParams,
+	"testnet3": &chaincfg.TestNet3Params,
+	"testnet4": &chaincfg.TestNet4Params,
+	"signet":   &chaincfg.SigNetParams,
+	"regtest":  &chaincfg.RegressionNetParams,
+	"simnet":   &chaincfg.SimNetParams,
+}
+
+// NetworkNames returns the names --network accepts.
+func NetworkNames() []string {
+	return append([]string(nil), networkNames...)
+}
+
+// NetworkByName returns the chain parameters of the network called name.
+func NetworkByName(name string) (*chaincfg.Params, error) {
+	params, ok := networks[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown network %q", name)
+	}
+	return params, nil
+}
+
+// NetworkNameOf returns the name --network gives params, empty for a
+// network it does not know.
+func NetworkNameOf(params *chaincfg.Params) string {
+	for _, name := range networkNames {
+		if networks[name] == params {
+			return name
+		}
+	}
+	return ""
+}
+
+// ResolveNetwork returns the chain parameters of the configured network,
+// mainnet when none is. The deprecated --testnet and --regtest still select
+// testnet3 and regtest, unless --network names another one.
+func (cfg *ServiceConfig) ResolveNetwork() (*chaincfg.Params, error) {
+	name := cfg.NetworkName
+	var legacy string
+	switch {
+	case cfg.RegressionTest && cfg.Testnet:
+		return nil, fmt.Errorf("%w: regtest and testnet cannot both be set, use network", ErrInvalidConfig)
+	case cfg.RegressionTest:
+		legacy = "regtest"
+	case cfg.Testnet:
+		legacy = "testnet3"
+	}
+
+	switch {
+	case legacy != "" && name != "" && name != legacy:
+		return nil, fmt.Errorf("%w: network=%s conflicts with the deprecated %s option", ErrInvalidConfig, name, legacy)
+	case name == "":
+		name = legacy
+	}
+	if name == "" {
+		name = "mainnet"
+	}
+	return NetworkByName(name)
+}
@@ -0,0 +1,47 @@
+package flnd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/flokiorg/go-flokicoin/chaincfg"
+)
+
+func TestResolveNetwork(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  ServiceConfig
+		want *chaincfg.Params
+		err  bool
+	}{
+		{"default", ServiceConfig{}, &chaincfg.MainNetParams, false},
+		{"network", ServiceConfig{NetworkName: "testnet4"}, &chaincfg.TestNet4Params, false},
+		{"signet", ServiceConfig{NetworkName: "signet"}, &chaincfg.SigNetParams, false},
+		{"legacy testnet", ServiceConfig{Testnet: true}, &chaincfg.TestNet3Params, false},
+		{"legacy regtest", ServiceConfig{RegressionTest: true}, &chaincfg.RegressionNetParams, false},
+		{"legacy agrees", ServiceConfig{NetworkName: "regtest", RegressionTest: true}, &chaincfg.RegressionNetParams, false},
+		{"legacy conflicts", ServiceConfig{NetworkName: "signet", Testnet: true}, nil, true},
+		{"both legacy", ServiceConfig{Testnet: true, RegressionTest: true}, nil, true},
+		{"unknown", ServiceConfig{NetworkName: "nonet"}, nil, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.cfg.ResolveNetwork()
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", got.Name)
+				}
+				if tc.name != "unknown" && !errors.Is(err, ErrInvalidConfig) {
+					t.Fatalf("expected ErrInvalidConfig, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Fatalf("got %s, want %s", got.Name, tc.want.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// defaultTorSOCKS is where the daemon looks for Tor without tor.socks.
+const defaultTorSOCKS = "localhost:9050"
+
+// HTTPTransport returns the transport of the HTTP requests twallet makes
+// itself, to the fee APIs, the price provider and the webhooks: through the
+// Tor SOCKS proxy when tor.active is set, like the daemon connections, nil
+// for the default transport otherwise.
+func (cfg *ServiceConfig) HTTPTransport() http.RoundTripper {
+	if !cfg.TorActive {
+		return nil
+	}
+	socks := cfg.TorSOCKS
+	if socks == "" {
+		socks = defaultTorSOCKS
+	}
+	d := &socksDialer{socks: socks, noClearnet: cfg.TorNoClearnet}
+	return &http.Transport{
+		Proxy:                 nil,
+		DialContext:           d.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   30 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// socksDialer connects through a SOCKS5 proxy, which resolves the names so
+// they do not leak to the local resolver. Addresses on this host or the
+// local network, which the proxy cannot reach, are dialed directly. When the
+// proxy itself cannot be reached, the connection is made directly unless
+// noClearnet is set.
+type socksDialer struct {
+	socks      string
+	noClearnet bool
+	direct     net.Dialer
+}
+
+func (d *socksDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if isLocalAddress(addr) {
+		return d.direct.DialContext(ctx, network, addr)
+	}
+
+	forward := &forwardDialer{}
+	socks, err := proxy.SOCKS5("tcp", d.socks, nil, forward)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := socks.(proxy.ContextDialer).DialContext(ctx, network, addr)
+	if err == nil || !forward.unreachable || d.noClearnet {
+		return conn, err
+	}
+	return d.direct.DialContext(ctx, network, addr)
+}
+
+// forwardDialer connects to the proxy, noting whether it could not.
+type forwardDialer struct {
+	net.Dialer
+	unreachable bool
+}
+
+func (f *forwardDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := f.Dialer.DialContext(ctx, network, addr)
+	f.unreachable = err != nil
+	return conn, err
+}
+
+// isLocalAddress reports whether addr is localhost or an IP address of this
+// host or the local network.
+func isLocalAddress(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast())
+}
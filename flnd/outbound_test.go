@@ -0,0 +1,119 @@
+package flnd
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// socksServer accepts SOCKS5 CONNECT requests without authentication and
+// connects every one of them to target, recording the host asked for.
+func socksServer(t *testing.T, target string, asked *atomic.Value) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 262)
+				// Greeting: version, methods.
+				if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+					return
+				}
+				io.ReadFull(conn, buf[:buf[1]])
+				conn.Write([]byte{5, 0})
+				// Request: version, command, reserved, domain name.
+				if _, err := io.ReadFull(conn, buf[:4]); err != nil || buf[3] != 3 {
+					return
+				}
+				io.ReadFull(conn, buf[:1])
+				host := make([]byte, buf[0])
+				io.ReadFull(conn, host)
+				io.ReadFull(conn, buf[:2])
+				asked.Store(net.JoinHostPort(string(host), strconv.Itoa(int(binary.BigEndian.Uint16(buf[:2])))))
+
+				upstream, err := net.Dial("tcp", target)
+				if err != nil {
+					conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer upstream.Close()
+				conn.Write([]byte{5, 0, 0, 1, 127, 0, 0, 1, 0, 0})
+				go io.Copy(upstream, conn)
+				io.Copy(conn, upstream)
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestHTTPTransportThroughSOCKS(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "fees")
+	}))
+	defer srv.Close()
+
+	var asked atomic.Value
+	socks := socksServer(t, srv.Listener.Addr().String(), &asked)
+
+	cfg := &ServiceConfig{TorActive: true, TorSOCKS: socks}
+	client := &http.Client{Transport: cfg.HTTPTransport()}
+	resp, err := client.Get("http://fees.example.org/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "fees" {
+		t.Fatalf("got %q through the proxy", body)
+	}
+	if got := asked.Load(); got != "fees.example.org:80" {
+		t.Fatalf("proxy asked for %v, want the name resolved by the proxy", got)
+	}
+}
+
+func TestHTTPTransportNoClearnet(t *testing.T) {
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	socks := closed.Addr().String()
+	closed.Close()
+
+	cfg := &ServiceConfig{TorActive: true, TorSOCKS: socks, TorNoClearnet: true}
+	if _, err := (&http.Client{Transport: cfg.HTTPTransport()}).Get("http://fees.example.org/"); err == nil {
+		t.Fatal("the request should fail without the proxy")
+	}
+
+	if (&ServiceConfig{}).HTTPTransport() != nil {
+		t.Fatal("without tor.active the default transport should be used")
+	}
+}
+
+func TestIsLocalAddress(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1:8080":       true,
+		"localhost:80":         true,
+		"[::1]:443":            true,
+		"192.168.1.10:80":      true,
+		"fees.example.org:443": false,
+		"93.184.216.34:80":     false,
+	} {
+		if got := isLocalAddress(addr); got != want {
+			t.Errorf("isLocalAddress(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
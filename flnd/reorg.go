@@ -0,0 +1,68 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+// reorgWindow is how many recent block hashes are remembered to recognise a
+// reorganization.
+const reorgWindow = 144
+
+// Reorg describes a chain reorganization seen on the block stream: the block
+// at Height was replaced by another one, and so were the blocks above it up
+// to Tip, the best height known before the switch.
+type Reorg struct {
+	Height  uint32
+	Depth   uint32
+	Tip     uint32
+	OldHash string
+	NewHash string
+}
+
+// blockTracker remembers the hashes of recent blocks so a height coming back
+// with another hash can be reported as a reorganization.
+type blockTracker struct {
+	hashes map[uint32]string
+	tip    uint32
+}
+
+func newBlockTracker() *blockTracker {
+	return &blockTracker{hashes: make(map[uint32]string)}
+}
+
+// add records the block at height and returns the reorganization it reveals,
+// if any.
+func (t *blockTracker) add(height uint32, hash string) *Reorg {
+	var reorg *Reorg
+	if old, ok := t.hashes[height]; ok && old != hash {
+		reorg = &Reorg{
+			Height:  height,
+			Depth:   t.tip - height + 1,
+			Tip:     t.tip,
+			OldHash: old,
+			NewHash: hash,
+		}
+		// The blocks above were built on the replaced one.
+		for h := range t.hashes {
+			if h > height {
+				delete(t.hashes, h)
+			}
+		}
+	}
+
+	t.hashes[height] = hash
+	if reorg != nil {
+		t.tip = height
+	} else {
+		t.tip = max(t.tip, height)
+	}
+	if height >= reorgWindow {
+		for h := range t.hashes {
+			if h <= height-reorgWindow {
+				delete(t.hashes, h)
+			}
+		}
+	}
+
+	return reorg
+}
@@ -0,0 +1,41 @@
+package flnd
+
+import "testing"
+
+func TestBlockTrackerReorg(t *testing.T) {
+	tracker := newBlockTracker()
+	for h, hash := range map[uint32]string{100: "a100", 101: "a101", 102: "a102"} {
+		if reorg := tracker.add(h, hash); reorg != nil {
+			t.Fatalf("unexpected reorg at %d: %+v", h, reorg)
+		}
+	}
+
+	if reorg := tracker.add(102, "a102"); reorg != nil {
+		t.Fatalf("same block reported as reorg: %+v", reorg)
+	}
+
+	reorg := tracker.add(101, "b101")
+	if reorg == nil {
+		t.Fatal("replaced block not reported")
+	}
+	want := Reorg{Height: 101, Depth: 2, Tip: 102, OldHash: "a101", NewHash: "b101"}
+	if *reorg != want {
+		t.Fatalf("reorg = %+v, want %+v", *reorg, want)
+	}
+
+	// The new branch extends past the replaced blocks without more alerts.
+	for h, hash := range map[uint32]string{102: "b102", 103: "b103"} {
+		if reorg := tracker.add(h, hash); reorg != nil {
+			t.Fatalf("new branch block %d reported as reorg: %+v", h, reorg)
+		}
+	}
+}
+
+func TestBlockTrackerWindow(t *testing.T) {
+	tracker := newBlockTracker()
+	tracker.add(1, "old")
+	tracker.add(1+reorgWindow, "tip")
+	if reorg := tracker.add(1, "other"); reorg != nil {
+		t.Fatalf("block outside the window reported as reorg: %+v", reorg)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/flokiorg/go-flokicoin/chaincfg/chainhash"
+	"github.com/flokiorg/walletd/waddrmgr"
+	"github.com/flokiorg/walletd/walletdb"
+	_ "github.com/flokiorg/walletd/walletdb/bdb"
+)
+
+// MaxRescanDepth is how far below the chain tip a rescan can start without
+// resetting the wallet transactions. The wallet only remembers the hashes of
+// that many blocks.
+const MaxRescanDepth = waddrmgr.MaxReorgDepth
+
+var (
+	ErrRescanHeight    = errors.New("rescan height out of range")
+	ErrNoBirthdayBlock = errors.New("wallet has no birthday block yet")
+)
+
+const (
+	walletDBName      = "wallet.db"
+	walletDBTimeout   = 10 * time.Second
+	walletDBNamespace = "waddrmgr"
+	walletSyncBucket  = "sync"
+)
+
+// walletDBPath returns the path of the wallet database of the validated
+// daemon config, which sits next to its macaroons.
+func walletDBPath(adminMacPath string) string {
+	return filepath.Join(filepath.Dir(adminMacPath), walletDBName)
+}
+
+// rewindWallet moves the block the wallet is synced to back to height, so
+// the wallet scans the chain again from there once unlocked. Unlike a reset
+// the transactions it knows are kept. Heights below the wallet birthday
+// start at the birthday. The wallet must not be open.
+func rewindWallet(dbPath string, height uint32) (uint32, error) {
+	db, err := walletdb.Open("bdb", dbPath, true, walletDBTimeout, false)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open wallet database: %w", err)
+	}
+	defer db.Close()
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket([]byte(walletDBNamespace))
+		if ns == nil {
+			return fmt.Errorf("wallet database has no %s namespace", walletDBNamespace)
+		}
+
+		birthday, err := waddrmgr.FetchBirthdayBlock(ns)
+		if err != nil {
+			return ErrNoBirthdayBlock
+		}
+		if int32(height) < birthday.Height {
+			height = uint32(birthday.Height)
+		}
+
+		hash, err := syncedBlockHash(ns, int32(height))
+		if err != nil {
+			return err
+		}
+
+		// The birthday block makes the wallet check the block below the new
+		// one is known, which it is not for the birthday itself. Like the
+		// transaction reset, take it out while moving the synced block.
+		if err := waddrmgr.DeleteBirthdayBlock(ns); err != nil {
+			return err
+		}
+		stamp := &waddrmgr.BlockStamp{
+			Height:    int32(height),
+			Hash:      *hash,
+			Timestamp: birthday.Timestamp,
+		}
+		if err := waddrmgr.PutSyncedTo(ns, stamp); err != nil {
+			return err
+		}
+		return waddrmgr.PutBirthdayBlock(ns, birthday)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return height, nil
+}
+
+// syncedBlockHash returns the hash the wallet stored for the block at
+// height. The address manager keeps them by big endian height in its sync
+// bucket but does not export a way to read them without opening it.
+func syncedBlockHash(ns walletdb.ReadBucket, height int32) (*chainhash.Hash, error) {
+	bucket := ns.NestedReadBucket([]byte(walletSyncBucket))
+	if bucket == nil {
+		return nil, fmt.Errorf("wallet database has no %s bucket", walletSyncBucket)
+	}
+
+	var key [4]byte
+	binary.BigEndian.PutUint32(key[:], uint32(height))
+	raw := bucket.Get(key[:])
+	if raw == nil {
+		return nil, fmt.Errorf("%w: block %d is not known to the wallet", ErrRescanHeight, height)
+	}
+	return chainhash.NewHash(raw)
+}
@@ -0,0 +1,119 @@
+package flnd
+
+import (
+	"encoding/binary"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/flokiorg/go-flokicoin/chaincfg/chainhash"
+	"github.com/flokiorg/walletd/waddrmgr"
+	"github.com/flokiorg/walletd/walletdb"
+)
+
+func blockHash(height int32) chainhash.Hash {
+	var hash chainhash.Hash
+	binary.BigEndian.PutUint32(hash[:], uint32(height))
+	return hash
+}
+
+// newTestWalletDB creates a wallet database knowing blocks from..to, born at
+// from and synced to to.
+func newTestWalletDB(t *testing.T, from, to int32) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), walletDBName)
+	db, err := walletdb.Create("bdb", path, true, walletDBTimeout, false)
+	if err != nil {
+		t.Fatalf("create db: %v", err)
+	}
+	defer db.Close()
+
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns, err := tx.CreateTopLevelBucket([]byte(walletDBNamespace))
+		if err != nil {
+			return err
+		}
+		if _, err := ns.CreateBucket([]byte(walletSyncBucket)); err != nil {
+			return err
+		}
+		for h := from; h <= to; h++ {
+			if err := waddrmgr.PutSyncedTo(ns, &waddrmgr.BlockStamp{Height: h, Hash: blockHash(h), Timestamp: time.Unix(1700000000, 0)}); err != nil {
+				return err
+			}
+			if h == from {
+				if err := waddrmgr.PutBirthdayBlock(ns, waddrmgr.BlockStamp{Height: h, Hash: blockHash(h), Timestamp: time.Unix(1700000000, 0)}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("fill db: %v", err)
+	}
+	return path
+}
+
+func syncedHeight(t *testing.T, path string) (int32, chainhash.Hash) {
+	t.Helper()
+	db, err := walletdb.Open("bdb", path, true, walletDBTimeout, false)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	var (
+		height int32
+		hash   chainhash.Hash
+	)
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		sync := tx.ReadBucket([]byte(walletDBNamespace)).NestedReadBucket([]byte(walletSyncBucket))
+		raw := sync.Get([]byte("syncedto"))
+		height = int32(binary.LittleEndian.Uint32(raw[:4]))
+		copy(hash[:], raw[4:36])
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("read db: %v", err)
+	}
+	return height, hash
+}
+
+func TestRewindWallet(t *testing.T) {
+	path := newTestWalletDB(t, 100, 120)
+
+	height, err := rewindWallet(path, 110)
+	if err != nil {
+		t.Fatalf("rewind: %v", err)
+	}
+	if height != 110 {
+		t.Fatalf("rewound to %d, want 110", height)
+	}
+	if got, hash := syncedHeight(t, path); got != 110 || hash != blockHash(110) {
+		t.Fatalf("synced to %d, want 110 with its hash", got)
+	}
+
+	// Below the birthday the rescan starts at the birthday.
+	height, err = rewindWallet(path, 50)
+	if err != nil {
+		t.Fatalf("rewind below birthday: %v", err)
+	}
+	if height != 100 {
+		t.Fatalf("rewound to %d, want the birthday 100", height)
+	}
+	if got, _ := syncedHeight(t, path); got != 100 {
+		t.Fatalf("synced to %d, want 100", got)
+	}
+}
+
+func TestRewindWalletUnknownBlock(t *testing.T) {
+	path := newTestWalletDB(t, 100, 120)
+
+	if _, err := rewindWallet(path, 130); !errors.Is(err, ErrRescanHeight) {
+		t.Fatalf("rewind past the known blocks = %v, want ErrRescanHeight", err)
+	}
+	if got, _ := syncedHeight(t, path); got != 120 {
+		t.Fatalf("failed rewind moved the wallet to %d", got)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import "time"
+
+const (
+	defaultRestartDelay    = time.Second
+	defaultMaxRestartDelay = 30 * time.Second
+)
+
+// restartPolicy is how the service restarts a daemon that fails to start:
+// after delay, doubled on every failure in a row up to maxDelay, and at most
+// maxRestarts times in a row when set.
+type restartPolicy struct {
+	delay       time.Duration
+	maxDelay    time.Duration
+	maxRestarts int
+}
+
+func newRestartPolicy(cfg *ServiceConfig) restartPolicy {
+	p := restartPolicy{
+		delay:       cfg.RestartDelay,
+		maxDelay:    cfg.MaxRestartDelay,
+		maxRestarts: cfg.MaxRestarts,
+	}
+	if p.delay <= 0 {
+		p.delay = defaultRestartDelay
+	}
+	if p.maxDelay <= 0 {
+		p.maxDelay = defaultMaxRestartDelay
+	}
+	if p.maxDelay < p.delay {
+		p.maxDelay = p.delay
+	}
+	if p.maxRestarts < 0 {
+		p.maxRestarts = 0
+	}
+	return p
+}
+
+// restartBackoff counts the failures in a row under a restart policy.
+type restartBackoff struct {
+	policy   restartPolicy
+	failures int
+	delay    time.Duration
+}
+
+// failed records a failure and returns how long to wait before the next
+// attempt, or false once the policy allows no more.
+func (b *restartBackoff) failed() (time.Duration, bool) {
+	b.failures++
+	if b.policy.maxRestarts > 0 && b.failures > b.policy.maxRestarts {
+		return 0, false
+	}
+	if b.delay == 0 {
+		b.delay = b.policy.delay
+	} else {
+		b.delay = min(b.delay*2, b.policy.maxDelay)
+	}
+	return b.delay, true
+}
+
+// reset starts counting again after a successful start.
+func (b *restartBackoff) reset() {
+	b.failures = 0
+	b.delay = 0
+}
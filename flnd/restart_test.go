@@ -0,0 +1,52 @@
+package flnd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDoublesUpToMax(t *testing.T) {
+	b := restartBackoff{policy: newRestartPolicy(&ServiceConfig{RestartDelay: time.Second, MaxRestartDelay: 5 * time.Second})}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		delay, ok := b.failed()
+		if !ok || delay != w {
+			t.Fatalf("failure %d: delay = %v, %v, want %v", i+1, delay, ok, w)
+		}
+	}
+
+	b.reset()
+	if delay, _ := b.failed(); delay != time.Second {
+		t.Fatalf("delay after reset = %v, want 1s", delay)
+	}
+}
+
+func TestBackoffGivesUp(t *testing.T) {
+	b := restartBackoff{policy: newRestartPolicy(&ServiceConfig{MaxRestarts: 2})}
+
+	for i := range 2 {
+		if _, ok := b.failed(); !ok {
+			t.Fatalf("gave up after %d failures, want 2 restarts", i+1)
+		}
+	}
+	if _, ok := b.failed(); ok {
+		t.Fatal("restarted past the limit")
+	}
+
+	b.reset()
+	if _, ok := b.failed(); !ok {
+		t.Fatal("no restart after a successful start")
+	}
+}
+
+func TestRestartPolicyDefaults(t *testing.T) {
+	p := newRestartPolicy(&ServiceConfig{RestartDelay: time.Minute})
+	if p.delay != time.Minute || p.maxDelay != time.Minute || p.maxRestarts != 0 {
+		t.Fatalf("policy = %+v, want 1m delays and no limit", p)
+	}
+	p = newRestartPolicy(&ServiceConfig{})
+	if p.delay != defaultRestartDelay || p.maxDelay != defaultMaxRestartDelay {
+		t.Fatalf("policy = %+v, want the defaults", p)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// RPCStat sums up the calls made to one daemon RPC method.
+type RPCStat struct {
+	// Method is the service and method name, like Lightning/GetTransactions.
+	Method string
+	Calls  uint64
+	// Errors counts the failed calls, the ones the caller cancelled aside.
+	Errors    uint64
+	Total     time.Duration
+	Max       time.Duration
+	LastError string
+}
+
+// Mean returns the average duration of a call.
+func (s RPCStat) Mean() time.Duration {
+	if s.Calls == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Calls)
+}
+
+// rpcStats times the calls made on daemon connections. It outlives them, so
+// the figures add up across daemon restarts.
+type rpcStats struct {
+	mu      sync.Mutex
+	methods map[string]*RPCStat
+}
+
+func newRPCStats() *rpcStats {
+	return &rpcStats{methods: make(map[string]*RPCStat)}
+}
+
+// rpcMethodName shortens a full gRPC method, /lnrpc.Lightning/GetInfo, to
+// Lightning/GetInfo.
+func rpcMethodName(method string) string {
+	method = strings.TrimPrefix(method, "/")
+	if i := strings.Index(method, "/"); i >= 0 {
+		if dot := strings.LastIndex(method[:i], "."); dot >= 0 {
+			method = method[dot+1:]
+		}
+	}
+	return method
+}
+
+func (s *rpcStats) record(method string, elapsed time.Duration, err error) {
+	name := rpcMethodName(method)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stat, ok := s.methods[name]
+	if !ok {
+		stat = &RPCStat{Method: name}
+		s.methods[name] = stat
+	}
+	stat.Calls++
+	stat.Total += elapsed
+	if elapsed > stat.Max {
+		stat.Max = elapsed
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		stat.Errors++
+		stat.LastError = err.Error()
+	}
+}
+
+// unary times the unary calls. Streams are left out: they last as long as
+// the subscription they serve.
+func (s *rpcStats) unary(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	s.record(method, time.Since(start), err)
+	return err
+}
+
+// snapshot returns a copy of the figures, the methods that took the most
+// time first.
+func (s *rpcStats) snapshot() []RPCStat {
+	s.mu.Lock()
+	stats := make([]RPCStat, 0, len(s.methods))
+	for _, stat := range s.methods {
+		stats = append(stats, *stat)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Total != stats[j].Total {
+			return stats[i].Total > stats[j].Total
+		}
+		return stats[i].Method < stats[j].Method
+	})
+	return stats
+}
@@ -0,0 +1,44 @@
+package flnd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestRPCStats(t *testing.T) {
+	stats := newRPCStats()
+
+	call := func(method string, d time.Duration, err error) error {
+		return stats.unary(context.Background(), method, nil, nil, nil,
+			func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				time.Sleep(d)
+				return err
+			})
+	}
+
+	failure := errors.New("deadline exceeded")
+	if err := call("/lnrpc.Lightning/GetTransactions", 20*time.Millisecond, failure); err != failure {
+		t.Fatalf("call error = %v, want it returned as is", err)
+	}
+	_ = call("/lnrpc.Lightning/GetTransactions", 0, nil)
+	_ = call("/walletrpc.WalletKit/EstimateFee", 0, context.Canceled)
+
+	got := stats.snapshot()
+	if len(got) != 2 {
+		t.Fatalf("stats = %+v", got)
+	}
+	txs := got[0]
+	if txs.Method != "Lightning/GetTransactions" || txs.Calls != 2 || txs.Errors != 1 || txs.LastError != failure.Error() {
+		t.Fatalf("first stat = %+v", txs)
+	}
+	if txs.Max < 20*time.Millisecond || txs.Mean() > txs.Max {
+		t.Fatalf("durations = mean %s max %s", txs.Mean(), txs.Max)
+	}
+	if fee := got[1]; fee.Method != "WalletKit/EstimateFee" || fee.Errors != 0 {
+		t.Fatalf("cancelled call counted as failed: %+v", fee)
+	}
+}
@@ -2,6 +2,8 @@ package flnd
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -31,6 +33,15 @@ const (
 	StatusBlock       Status = "block"
 	StatusScanning    Status = "scanning"
 	StatusQuit        Status = "quit"
+
+	// StatusFailedPermanently is sent when the daemon failed to start more
+	// times in a row than the restart policy allows. The service does not
+	// try again.
+	StatusFailedPermanently Status = "failedPermanently"
+
+	// StatusBalance carries a new wallet balance. Only subscriptions asking
+	// for it by state receive it.
+	StatusBalance Status = "balance"
 )
 
 type Update struct {
@@ -39,6 +50,38 @@ type Update struct {
 	Transaction               *lnrpc.Transaction
 	BlockHeight, SyncedHeight uint32
 	BlockHash                 string
+
+	// BestHeaderTimestamp is the time of the best known block header while
+	// the chain is syncing, used to estimate the sync progress.
+	BestHeaderTimestamp int64
+
+	// Reorg is set on a block update that replaced an already seen block.
+	Reorg *Reorg
+
+	// Balance is set on StatusBalance updates.
+	Balance *lnrpc.WalletBalanceResponse
+
+	// Seq numbers the updates in the order the service published them, so
+	// subscribers can notice a gap and fetch what they missed with
+	// EventsSince. Balance updates are not numbered and repeat the Seq of
+	// the update before them.
+	Seq uint64
+}
+
+type NetworkInfo struct {
+	Network        string
+	ChainPeers     int
+	LightningPeers int
+	BlockHeight    uint32
+	BlockHash      string
+}
+
+// ChannelPeer is the remote end of an open Lightning channel.
+type ChannelPeer struct {
+	PubKey       string
+	ChannelPoint string
+	Alias        string
+	Online       bool
 }
 
 type OutputLock struct {
@@ -51,22 +94,43 @@ type FundedPsbt struct {
 	Locks  []*OutputLock
 }
 
+type SignedPsbt struct {
+	Packet       *psbt.Packet
+	SignedInputs []uint32
+}
+
 type ServiceConfig struct {
 	// Basic Configuration
 	Walletdir               string        `short:"w" long:"walletdir" description:"Directory for Flokicoin Lightning Network"`
-	RegressionTest          bool          `long:"regtest" description:"Use the regression test network"`
-	Testnet                 bool          `long:"testnet" description:"Use the test network"`
+	MetadataDir             string        `long:"metadatadir" description:"Directory of the state twallet keeps itself, the copy of the transaction labels and the journal, which daemon resets and rescans leave alone (defaults to metadata in the wallet directory)"`
+	NetworkName             string        `long:"network" choice:"mainnet" choice:"testnet3" choice:"testnet4" choice:"signet" choice:"regtest" choice:"simnet" description:"Network to run on (defaults to mainnet)"`
+	RegressionTest          bool          `long:"regtest" hidden:"true" description:"Deprecated, use network=regtest"`
+	Testnet                 bool          `long:"testnet" hidden:"true" description:"Deprecated, use network=testnet3"`
 	ConnectionTimeout       time.Duration `short:"t" long:"connectiontimeout" default:"50s" description:"The timeout value for network connections. Valid time units are {ms, s, m, h}."`
 	DebugLevel              string        `short:"d" long:"debuglevel" default:"info" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical}"`
+	DaemonLogMaxFiles       int           `long:"daemonlog.maxfiles" default:"10" description:"Rotated flnd.log files to keep (0 to disable the rotation)"`
+	DaemonLogMaxSize        int           `long:"daemonlog.maxsize" default:"20" description:"Rotate flnd.log once it reaches this many MB"`
+	DaemonLogCompressor     string        `long:"daemonlog.compressor" choice:"gzip" choice:"zstd" default:"gzip" description:"Compression of the rotated flnd.log files"`
+	RPCTimeout              time.Duration `long:"rpctimeout" default:"5s" description:"Timeout of the daemon calls, capped by connectiontimeout. Valid time units are {ms, s, m, h}."`
+	RPCTimeoutTransactions  time.Duration `long:"rpctimeout.transactions" description:"Timeout of each page of the transaction fetch (defaults to the larger of rpctimeout and 30s)"`
+	RPCTimeoutFees          time.Duration `long:"rpctimeout.fees" description:"Timeout of the fee estimates (defaults to the larger of rpctimeout and 5s)"`
 	TransactionDisplayLimit int           `long:"transactiondisplaylimit" description:"Maximum number of transactions to fetch per request"`
 	ResetWalletTransactions bool          `long:"resetwallettransactions" description:"Reset wallet transactions on startup to trigger a full rescan"`
+	TransactionCacheLimit   int           `long:"txcachelimit" default:"50000" description:"Maximum number of transactions kept in memory, the oldest confirmed ones are dropped past it (0 for no limit)"`
+	ShutdownTimeout         time.Duration `long:"shutdowntimeout" default:"30s" description:"How long to wait for the daemon to stop when quitting. Valid time units are {ms, s, m, h}."`
+	RestartDelay            time.Duration `long:"restartdelay" default:"1s" description:"How long to wait before starting the daemon again after it failed to start, doubled on every failure in a row. Valid time units are {ms, s, m, h}."`
+	MaxRestartDelay         time.Duration `long:"maxrestartdelay" default:"30s" description:"The longest wait between two daemon start attempts. Valid time units are {ms, s, m, h}."`
+	MaxRestarts             int           `long:"maxrestarts" description:"How many times in a row to try starting the daemon again before giving up (0 for no limit)"`
 
 	// Network & Peers
-	ConnectPeers []string `long:"connect" description:"Connect only to the specified peers at startup"`
-	AddPeers     []string `long:"addpeer" description:"Add peers to connect to at startup"`
+	ConnectPeers    []string      `long:"connect" description:"Connect only to the specified peers at startup"`
+	AddPeers        []string      `long:"addpeer" description:"Add peers to connect to at startup"`
+	DNSSeeds        []string      `long:"dnsseed" description:"Host name resolved to peers to connect to, at startup and every dnsseed.interval (host or host:port, repeat for more)"`
+	DNSSeedInterval time.Duration `long:"dnsseed.interval" default:"30m" description:"How often the DNS seeds are resolved again for new peers (0 to resolve them only at startup). Valid time units are {ms, s, m, h}."`
 
 	// Fee Configuration
-	Feeurl string `long:"feeurl" description:"Custom fee estimation API endpoint (Required on mainnet)"`
+	Feeurl          string   `long:"feeurl" secret:"true" description:"Custom fee estimation API endpoint (Required on mainnet)"`
+	FeeURLFallbacks []string `long:"feeurl.fallback" secret:"true" description:"Fee estimation API tried when feeurl does not answer, repeat for more"`
 
 	// TLS Configuration
 	TLSExtraIPs     []string `long:"tlsextraip" description:"Adds an extra ip to the generated certificate"`
@@ -124,6 +188,8 @@ type ServiceConfig struct {
 	TorSOCKS   string `long:"tor.socks" description:"The host:port that Tor's SOCKS proxy is listening on"`
 	TorDNS     string `long:"tor.dns" description:"The DNS server as host:port that Tor will use for SRV queries - NOTE must have TCP resolution enabled"`
 	TorControl string `long:"tor.control" description:"The host:port that Tor is listening on for control connections"`
+	// Not handed to the daemon, see HTTPTransport.
+	TorNoClearnet bool `long:"tor.noclearnet" description:"Fail twallet's own HTTP requests (fee APIs, price, webhooks) when the Tor SOCKS proxy is unreachable instead of making them directly"`
 
 	// Performance & Tuning
 	TrickleDelay             int           `long:"trickledelay" description:"Time in milliseconds between each release of announcements to the network"`
@@ -136,8 +202,14 @@ type ServiceConfig struct {
 }
 
 type Service struct {
-	subMu sync.Mutex
-	subs  []chan *Update
+	subMu   sync.Mutex
+	subs    []*subscriber
+	seq     uint64
+	history eventHistory
+	states  stateLog
+
+	balanceEvent *Update
+	balanceKick  chan struct{}
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -150,8 +222,30 @@ type Service struct {
 	wg                   sync.WaitGroup
 	running              bool
 	lastEvent            *Update
+	statusEvent          *Update
+	rpcStats             *rpcStats
 	maxTransactionsLimit uint32
+	txCacheLimit         int
+	rpcTimeouts          RPCTimeouts
+	restartPolicy        restartPolicy
 	stopOnce             sync.Once
+
+	// rescanFrom is the height the next daemon start rescans from, guarded
+	// by configMu.
+	rescanFrom uint32
+
+	// fees serves the fee APIs to the daemon. feeFallbacks is guarded by
+	// configMu.
+	fees         *feeProxy
+	feeFallbacks []string
+
+	// seedPeers are the peers the DNS seeds resolved to, added to the ones
+	// of addpeer. Guarded by configMu.
+	seedPeers []string
+
+	// labels keeps the transaction labels in the metadata directory, nil
+	// without one.
+	labels *labelStore
 }
 
 func New(pctx context.Context, cfg *ServiceConfig) *Service {
@@ -163,10 +257,16 @@ func New(pctx context.Context, cfg *ServiceConfig) *Service {
 	conf.Flokicoin.Node = "neutrino"
 	conf.NeutrinoMode.ConnectPeers = cfg.ConnectPeers
 	conf.DebugLevel = cfg.DebugLevel
-	conf.Fee.URL = cfg.Feeurl
 	conf.ProtocolOptions = &lncfg.ProtocolOptions{}
 	conf.Pprof = &lncfg.Pprof{}
 	conf.LogConfig.Console.Disable = true
+	conf.LogConfig.File.MaxLogFiles = cfg.DaemonLogMaxFiles
+	if cfg.DaemonLogMaxSize > 0 {
+		conf.LogConfig.File.MaxLogFileSize = cfg.DaemonLogMaxSize
+	}
+	if cfg.DaemonLogCompressor != "" {
+		conf.LogConfig.File.Compressor = cfg.DaemonLogCompressor
+	}
 	conf.ConnectionTimeout = cfg.ConnectionTimeout
 	conf.TLSExtraDomains = append(conf.TLSExtraDomains, cfg.TLSExtraDomains...)
 	conf.TLSExtraIPs = append(conf.TLSExtraIPs, cfg.TLSExtraIPs...)
@@ -299,7 +399,7 @@ func New(pctx context.Context, cfg *ServiceConfig) *Service {
 	case &chaincfg.TestNet4Params:
 		conf.Flokicoin.TestNet4 = true
 	case &chaincfg.SimNetParams:
-		conf.Flokicoin.SigNet = true
+		conf.Flokicoin.SimNet = true
 	case &chaincfg.RegressionNetParams:
 		conf.Flokicoin.RegTest = true
 	case &chaincfg.SigNetParams:
@@ -312,19 +412,56 @@ func New(pctx context.Context, cfg *ServiceConfig) *Service {
 		ctx:                  ctx,
 		cancel:               cancel,
 		maxTransactionsLimit: uint32(cfg.TransactionDisplayLimit),
+		txCacheLimit:         cfg.TransactionCacheLimit,
+		rpcTimeouts:          cfg.RPCTimeouts(),
+		restartPolicy:        newRestartPolicy(cfg),
+		balanceKick:          make(chan struct{}, 1),
+		rpcStats:             newRPCStats(),
+		fees:                 newFeeProxy(cfg.HTTPTransport()),
+		feeFallbacks:         cfg.FeeURLFallbacks,
+		labels:               newLabelStore(cfg.MetadataDir, NetworkNameOf(cfg.Network)),
 	}
+	conf.Fee.URL = s.fees.route(FeeSources(cfg.Feeurl, cfg.FeeURLFallbacks))
 
-	go s.run()
+	var seeds []string
+	if cfg.seedsAllowed() {
+		seeds = cfg.DNSSeeds
+	}
+	go s.run(seeds, cfg.Network.DefaultPort)
+	s.wg.Add(1)
+	go s.trackBalance()
+	if len(seeds) > 0 && cfg.DNSSeedInterval > 0 {
+		s.wg.Add(1)
+		go s.refreshSeeds(cfg.DNSSeeds, cfg.Network.DefaultPort, cfg.DNSSeedInterval)
+	}
 
 	return s
 }
 
-func (s *Service) run() {
+// run starts the daemon and restarts it when it stops, until the service
+// stops. The seeds are resolved here before the first start, so that a slow
+// resolver holds back the daemon and not New.
+func (s *Service) run(seeds []string, seedPort string) {
 	s.wg.Add(1)
 	defer s.wg.Done()
 
-	retryDelay := time.Second
-	const maxRetryDelay = 30 * time.Second
+	if len(seeds) > 0 {
+		s.resolveSeedPeers(seeds, seedPort)
+	}
+
+	retries := restartBackoff{policy: s.restartPolicy}
+
+	// retry waits before the next attempt after err, and reports whether to
+	// make it. Past the restart limit the service gives up for good.
+	retry := func(err error) bool {
+		delay, ok := retries.failed()
+		if !ok {
+			s.notifySubscribers(&Update{State: StatusFailedPermanently, Err: err})
+			return false
+		}
+		s.notifySubscribers(&Update{State: StatusDown, Err: err})
+		return s.waitForRetry(delay)
+	}
 
 	for {
 		select {
@@ -337,48 +474,29 @@ func (s *Service) run() {
 			s.notifySubscribers(&Update{State: StatusNone})
 			interceptor, err := signal.Intercept()
 			if err != nil {
-				s.notifySubscribers(&Update{State: StatusDown, Err: err})
-				if !s.waitForRetry(retryDelay) {
+				if !retry(err) {
 					return
 				}
-				if retryDelay < maxRetryDelay {
-					retryDelay = retryDelay * 2
-					if retryDelay > maxRetryDelay {
-						retryDelay = maxRetryDelay
-					}
-				}
 				continue
 			}
 
 			d, err := newDaemon(s.ctx, s.cloneConfig(), interceptor)
 			if err != nil {
-				s.notifySubscribers(&Update{State: StatusDown, Err: err})
-				if !s.waitForRetry(retryDelay) {
+				if !retry(err) {
 					return
 				}
-				if retryDelay < maxRetryDelay {
-					retryDelay = retryDelay * 2
-					if retryDelay > maxRetryDelay {
-						retryDelay = maxRetryDelay
-					}
-				}
 				continue
 			}
+			d.stats = s.rpcStats
+			d.rescanFrom = s.takeRescanHeight()
 			c, err := d.start()
 			if err != nil {
-				s.notifySubscribers(&Update{State: StatusDown, Err: err})
-				if !s.waitForRetry(retryDelay) {
+				if !retry(err) {
 					return
 				}
-				if retryDelay < maxRetryDelay {
-					retryDelay = retryDelay * 2
-					if retryDelay > maxRetryDelay {
-						retryDelay = maxRetryDelay
-					}
-				}
 				continue
 			}
-			retryDelay = time.Second
+			retries.reset()
 			s.running = true
 			ctx, cancel := context.WithCancel(s.ctx)
 			go func() {
@@ -437,6 +555,7 @@ func (s *Service) cloneConfig() *flnd.Config {
 func (s *Service) Stop() {
 	s.stopOnce.Do(func() {
 		s.stopDaemon()
+		s.fees.close()
 		s.cancel()
 		s.unsubscribeAll()
 		s.wg.Wait()
@@ -444,6 +563,40 @@ func (s *Service) Stop() {
 	})
 }
 
+// RPCStats returns the timing and error figures of the daemon RPC calls made
+// since the service started, the methods that took the most time first.
+func (s *Service) RPCStats() []RPCStat {
+	if s.rpcStats == nil {
+		return nil
+	}
+	return s.rpcStats.snapshot()
+}
+
+// StopWithTimeout is Stop giving up after timeout, as the daemon can take
+// long to stop while it syncs. The daemon keeps stopping in the background
+// and ErrShutdownTimeout is returned. No timeout waits for it.
+func (s *Service) StopWithTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		s.Stop()
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		return ErrShutdownTimeout
+	}
+}
+
 func (s *Service) stopDaemon() {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
@@ -462,12 +615,64 @@ func (s *Service) Restart(pctx context.Context) {
 	}
 }
 
+// SetMaxTransactionsLimit changes how many transactions a fetch returns,
+// for the current connection and the ones after it.
+func (s *Service) SetMaxTransactionsLimit(limit uint32) {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	s.maxTransactionsLimit = limit
+	if s.client != nil {
+		s.client.SetMaxTransactionsLimit(limit)
+	}
+}
+
+// SetFeeSources makes the daemon read the fees from primary, then from the
+// fallbacks when it does not answer. With the failover running the change
+// applies at once, otherwise on the next daemon start.
+func (s *Service) SetFeeSources(primary string, fallbacks []string) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.feeFallbacks = fallbacks
+	s.flndConfig.Fee.URL = s.fees.route(FeeSources(primary, fallbacks))
+}
+
+// FeeURL returns the URL the daemon reads the fees at.
+func (s *Service) FeeURL() string {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	return s.flndConfig.Fee.URL
+}
+
+// FeeSource returns which fee API answered last.
+func (s *Service) FeeSource() FeeSource {
+	return s.fees.status()
+}
+
+// SetRPCTimeouts changes the timeouts of the daemon calls, for the current
+// connection and the ones after it.
+func (s *Service) SetRPCTimeouts(timeouts RPCTimeouts) {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	s.rpcTimeouts = timeouts
+	if s.client != nil {
+		s.client.SetRPCTimeouts(timeouts)
+	}
+}
+
+func (s *Service) timeouts() RPCTimeouts {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	return s.rpcTimeouts
+}
+
 func (s *Service) registerConnection(d *daemon, c *Client) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	s.client = c
 	s.daemon = d
 	c.SetMaxTransactionsLimit(s.maxTransactionsLimit)
+	c.SetTransactionCacheLimit(s.txCacheLimit)
+	c.SetRPCTimeouts(s.rpcTimeouts)
 	s.configMu.Lock()
 	s.flndConfig.ResetWalletTransactions = false
 	s.configMu.Unlock()
@@ -476,46 +681,137 @@ func (s *Service) registerConnection(d *daemon, c *Client) {
 func (s *Service) TriggerRescan() error {
 	s.configMu.Lock()
 	s.flndConfig.ResetWalletTransactions = true
+	cachePath := txCachePath(s.flndConfig)
 	s.configMu.Unlock()
 
+	// The saved transactions would come back after the rescan, drop them.
+	if err := removeTxCache(cachePath); err != nil {
+		return err
+	}
+
 	s.Restart(context.Background())
 	return nil
 }
 
-func (s *Service) GetRecoveryInfo() (*lnrpc.GetRecoveryInfoResponse, error) {
+// TriggerRescanFrom restarts the daemon to scan the chain again from height
+// only, keeping the wallet transactions. It is much faster than
+// TriggerRescan to recover a missed deposit, but height can be at most
+// MaxRescanDepth blocks below the tip.
+func (s *Service) TriggerRescanFrom(height uint32) error {
+	_, _, tip, err := s.IsSynced()
+	if err != nil {
+		return err
+	}
+	if height == 0 || height > tip || tip-height >= MaxRescanDepth {
+		return fmt.Errorf("%w: %d is not within the last %d blocks", ErrRescanHeight, height, MaxRescanDepth)
+	}
+
+	s.configMu.Lock()
+	s.rescanFrom = height
+	s.configMu.Unlock()
+
+	s.Restart(context.Background())
+	return nil
+}
+
+// takeRescanHeight returns the height asked by TriggerRescanFrom and clears
+// it, so a failed rewind is not tried again on every restart.
+func (s *Service) takeRescanHeight() uint32 {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	height := s.rescanFrom
+	s.rescanFrom = 0
+	return height
+}
+
+func (s *Service) GetRecoveryInfo(ctx context.Context) (*lnrpc.GetRecoveryInfoResponse, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return nil, ErrDaemonNotRunning
 	}
-	return s.client.GetRecoveryInfo()
+	return s.client.GetRecoveryInfo(ctx)
 }
 
-func (s *Service) ListUnspent(minConfs, maxConfs int32) ([]*lnrpc.Utxo, error) {
+// IsSynced reports whether the wallet is synced to the chain, whether its
+// best header is recent, and the height of the chain tip.
+func (s *Service) IsSynced() (bool, bool, uint32, error) {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return false, false, 0, ErrDaemonNotRunning
+	}
+	return s.client.IsSynced()
+}
+
+func (s *Service) NetworkInfo(ctx context.Context) (*NetworkInfo, error) {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return nil, ErrDaemonNotRunning
+	}
+	return s.client.NetworkInfo(ctx)
+}
+
+func (s *Service) ChainPeerAddresses(ctx context.Context) ([]string, error) {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return nil, ErrDaemonNotRunning
+	}
+	return s.client.ChainPeerAddresses(ctx)
+}
+
+func (s *Service) ChannelPeers(ctx context.Context) ([]ChannelPeer, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return nil, ErrDaemonNotRunning
 	}
-	return s.client.ListUnspent(minConfs, maxConfs)
+	return s.client.ChannelPeers(ctx)
 }
 
-func (s *Service) VerifyMessage(address, message, signature string) (*walletrpc.VerifyMessageWithAddrResponse, error) {
+func (s *Service) ListUnspent(ctx context.Context, minConfs, maxConfs int32) ([]*lnrpc.Utxo, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return nil, ErrDaemonNotRunning
 	}
-	return s.client.VerifyMessageWithAddress(address, message, signature)
+	return s.client.ListUnspent(ctx, minConfs, maxConfs)
 }
 
+func (s *Service) VerifyMessage(ctx context.Context, address, message, signature string) (*walletrpc.VerifyMessageWithAddrResponse, error) {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return nil, ErrDaemonNotRunning
+	}
+	return s.client.VerifyMessageWithAddress(ctx, address, message, signature)
+}
+
+// Subscribe returns a channel receiving the last update and all the
+// following ones. Updates wait in a queue while the reader is busy; if it
+// falls far behind the oldest non critical ones are dropped, which shows as
+// a gap in Seq.
 func (s *Service) Subscribe() <-chan *Update {
-	ch := make(chan *Update, 5)
+	return s.SubscribeFiltered()
+}
+
+// SubscribeFiltered is Subscribe limited to the updates in states, the last
+// update and the current balance included. Seq skips the updates filtered
+// out. No states means all of them but the balance updates.
+func (s *Service) SubscribeFiltered(states ...Status) <-chan *Update {
+	sub := newSubscriber(states)
 	s.subMu.Lock()
-	s.subs = append(s.subs, ch)
-	ch <- s.lastEvent
+	s.subs = append(s.subs, sub)
+	if sub.wants(s.lastEvent) {
+		sub.push(s.lastEvent)
+	}
+	if s.balanceEvent != nil && sub.wants(s.balanceEvent) {
+		sub.push(s.balanceEvent)
+	}
 	s.subMu.Unlock()
-	return ch
+	return sub.ch
 }
 
 func (s *Service) Unsubscribe(ch <-chan *Update) {
@@ -523,207 +819,364 @@ func (s *Service) Unsubscribe(ch <-chan *Update) {
 	defer s.subMu.Unlock()
 
 	for i := 0; i < len(s.subs); i++ {
-		if s.subs[i] == ch {
+		if s.subs[i].ch == ch {
+			s.subs[i].cancel()
 			s.subs = append(s.subs[:i], s.subs[i+1:]...)
 			break
 		}
 	}
 }
 
+// EventsSince returns the published updates numbered after seq. complete is
+// false when some of them are too old to be kept any more.
+func (s *Service) EventsSince(seq uint64) (events []*Update, complete bool) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	return s.history.since(seq)
+}
+
 func (s *Service) notifySubscribers(u *Update) {
 	s.subMu.Lock()
 	defer s.subMu.Unlock()
-	s.lastEvent = u
 
-	for _, ch := range s.subs {
-		select {
-		case ch <- u:
-		default:
+	ev := *u
+	if ev.State == StatusBalance {
+		ev.Seq = s.seq
+		s.balanceEvent = &ev
+	} else {
+		s.seq++
+		ev.Seq = s.seq
+		s.lastEvent = &ev
+		s.history.add(&ev)
+		if ev.State.lifecycle() {
+			s.recordTransition(ev.State, ev.Err)
+			s.statusEvent = &ev
 		}
 	}
-}
 
-func (s *Service) unsubscribeAll() {
-	s.subMu.Lock()
-	defer s.subMu.Unlock()
+	for _, sub := range s.subs {
+		if sub.wants(&ev) {
+			sub.push(&ev)
+		}
+	}
 
-	if len(s.subs) == 0 {
-		return
+	switch ev.State {
+	case StatusReady, StatusUnlocked, StatusTransaction, StatusBlock:
+		s.RefreshBalance()
+	case StatusDown, StatusLocked, StatusNoWallet:
+		s.balanceEvent = nil
 	}
+}
 
+func (s *Service) unsubscribeAll() {
+	s.subMu.Lock()
+	subs := s.subs
+	s.subs = nil
+	s.seq++
 	finalUpdate := &Update{
 		State: StatusDown,
+		Seq:   s.seq,
 	}
+	s.history.add(finalUpdate)
+	s.recordTransition(StatusDown, nil)
+	s.statusEvent = finalUpdate
+	s.subMu.Unlock()
 
-	for _, ch := range s.subs {
-		select {
-		case ch <- finalUpdate:
-		case <-time.After(5 * time.Second):
-		}
-		close(ch)
+	var wg sync.WaitGroup
+	for _, sub := range subs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sub.close(finalUpdate, subscriberFlushTimeout)
+		}()
 	}
-
-	s.subs = s.subs[:0]
+	wg.Wait()
 }
 
-func (s *Service) CreateWallet(passphrase string) (string, []string, error) {
+func (s *Service) CreateWallet(ctx context.Context, passphrase string) (string, []string, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return "", nil, ErrDaemonNotRunning
 	}
-	return s.client.Create(passphrase)
+	return s.client.Create(ctx, passphrase)
 }
 
-func (s *Service) Balance() (*lnrpc.WalletBalanceResponse, error) {
+func (s *Service) Balance(ctx context.Context) (*lnrpc.WalletBalanceResponse, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return nil, ErrDaemonNotRunning
 	}
-	return s.client.Balance()
+	return s.client.Balance(ctx)
 }
 
-func (s *Service) IsLocked() (bool, error) {
+func (s *Service) IsLocked(ctx context.Context) (bool, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return false, ErrDaemonNotRunning
 	}
-	return s.client.IsLocked()
+	return s.client.IsLocked(ctx)
 }
 
-func (s *Service) Unlock(passphrase string) error {
+func (s *Service) Unlock(ctx context.Context, passphrase string) error {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return ErrDaemonNotRunning
 	}
-	return s.client.Unlock(passphrase)
+	return s.client.Unlock(ctx, passphrase)
 }
 
-func (s *Service) WalletExists() (bool, error) {
+func (s *Service) WalletExists(ctx context.Context) (bool, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return false, ErrDaemonNotRunning
 	}
-	return s.client.WalletExists()
+	return s.client.WalletExists(ctx)
 }
 
-func (s *Service) FetchTransactions() ([]*lnrpc.Transaction, error) {
-	return s.FetchTransactionsWithOptions(FetchTransactionsOptions{})
+func (s *Service) FetchTransactions(ctx context.Context) ([]*lnrpc.Transaction, error) {
+	return s.FetchTransactionsWithOptions(ctx, FetchTransactionsOptions{})
 }
 
-func (s *Service) FetchTransactionsWithOptions(opts FetchTransactionsOptions) ([]*lnrpc.Transaction, error) {
+func (s *Service) FetchTransactionsWithOptions(ctx context.Context, opts FetchTransactionsOptions) ([]*lnrpc.Transaction, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return nil, ErrDaemonNotRunning
 	}
-	return s.client.FetchTransactionsWithOptions(opts)
+	txs, err := s.client.FetchTransactionsWithOptions(ctx, opts)
+	s.labels.apply(txs)
+	return txs, err
+}
+
+// TxCacheStats describes the transaction cache of the running daemon.
+func (s *Service) TxCacheStats() (TxCacheStats, error) {
+	s.cmux.Lock()
+	client := s.client
+	s.cmux.Unlock()
+	if client == nil {
+		return TxCacheStats{}, ErrDaemonNotRunning
+	}
+	return client.TxCacheStats(), nil
 }
 
-func (s *Service) GetNextAddress(t lnrpc.AddressType) (chainutil.Address, error) {
+// StreamTransactions delivers the wallet transactions page by page, see
+// Client.StreamTransactions.
+func (s *Service) StreamTransactions(ctx context.Context, opts FetchTransactionsOptions) <-chan TransactionPage {
+	s.cmux.Lock()
+	client := s.client
+	s.cmux.Unlock()
+	if client == nil {
+		pages := make(chan TransactionPage, 1)
+		pages <- TransactionPage{Err: ErrDaemonNotRunning}
+		close(pages)
+		return pages
+	}
+	pages := client.StreamTransactions(ctx, opts)
+	if s.labels == nil {
+		return pages
+	}
+
+	labeled := make(chan TransactionPage)
+	go func() {
+		defer close(labeled)
+		for page := range pages {
+			s.labels.apply(page.Transactions)
+			select {
+			case labeled <- page:
+			case <-ctx.Done():
+				for range pages {
+				}
+				return
+			}
+		}
+	}()
+	return labeled
+}
+
+func (s *Service) GetNextAddress(ctx context.Context, t lnrpc.AddressType) (chainutil.Address, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return nil, ErrDaemonNotRunning
 	}
-	return s.client.GetNextAddress(t)
+	return s.client.GetNextAddress(ctx, t)
 }
 
-func (s *Service) SignMessage(address string, message string) (string, error) {
+func (s *Service) SignMessage(ctx context.Context, address string, message string) (string, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return "", ErrDaemonNotRunning
 	}
-	return s.client.SignMessageWithAddress(address, message)
+	return s.client.SignMessageWithAddress(ctx, address, message)
 }
 
-func (s *Service) ListAddresses() ([]*walletrpc.AccountWithAddresses, error) {
+func (s *Service) ListAddresses(ctx context.Context) ([]*walletrpc.AccountWithAddresses, error) {
+	return s.ListAddressesWithOptions(ctx, ListAddressesOptions{})
+}
+
+func (s *Service) ListAddressesWithOptions(ctx context.Context, opts ListAddressesOptions) ([]*walletrpc.AccountWithAddresses, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return nil, ErrDaemonNotRunning
 	}
-	return s.client.ListAddresses()
+	return s.client.ListAddressesWithOptions(ctx, opts)
 }
 
-func (s *Service) RestoreByMnemonic(mnemonic []string, passphrase string) (string, error) {
+func (s *Service) RestoreByMnemonic(ctx context.Context, mnemonic []string, passphrase string) (string, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return "", ErrDaemonNotRunning
 	}
-	return s.client.RestoreByMnemonic(mnemonic, passphrase)
+	return s.client.RestoreByMnemonic(ctx, mnemonic, passphrase)
 }
 
-func (s *Service) RestoreByEncipheredSeed(strEncipheredSeed, passphrase string) ([]string, error) {
+func (s *Service) RestoreByEncipheredSeed(ctx context.Context, strEncipheredSeed, passphrase string) ([]string, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return nil, ErrDaemonNotRunning
 	}
-	return s.client.RestoreByEncipheredSeed(strEncipheredSeed, passphrase)
+	return s.client.RestoreByEncipheredSeed(ctx, strEncipheredSeed, passphrase)
 }
 
-func (s *Service) ChangePassphrase(old, new string) error {
+func (s *Service) ChangePassphrase(ctx context.Context, old, new string) error {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return ErrDaemonNotRunning
 	}
-	return s.client.ChangePassphrase(old, new)
+	return s.client.ChangePassphrase(ctx, old, new)
 }
 
-func (s *Service) Transfer(address chainutil.Address, amount chainutil.Amount, lokiPerVbyte uint64) (string, error) {
+func (s *Service) Transfer(ctx context.Context, address chainutil.Address, amount chainutil.Amount, lokiPerVbyte uint64) (string, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return "", ErrDaemonNotRunning
 	}
-	return s.client.SimpleTransfer(address, amount, lokiPerVbyte)
+	return s.client.SimpleTransfer(ctx, address, amount, lokiPerVbyte)
+}
+
+func (s *Service) FeeRate(ctx context.Context, confTarget int32) (float64, error) {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return 0, ErrDaemonNotRunning
+	}
+	return s.client.FeeRate(ctx, confTarget)
 }
 
-func (s *Service) Fee(address chainutil.Address, amount chainutil.Amount) (*lnrpc.EstimateFeeResponse, error) {
+func (s *Service) FeeEstimates(ctx context.Context, targets ...int32) (map[int32]float64, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return nil, ErrDaemonNotRunning
 	}
-	return s.client.SimpleTransferFee(address, amount)
+	return s.client.FeeEstimates(ctx, targets...)
 }
 
-func (s *Service) FundPsbt(addrToAmount map[string]int64, lokiPerVbyte uint64, lockExpirationSeconds uint64) (*FundedPsbt, error) {
+func (s *Service) BumpFee(ctx context.Context, txid string, outputIndex uint32, lokiPerVbyte uint64) error {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return ErrDaemonNotRunning
+	}
+	return s.client.BumpFee(ctx, txid, outputIndex, lokiPerVbyte)
+}
+
+func (s *Service) LabelTransaction(ctx context.Context, txid, label string) error {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return ErrDaemonNotRunning
+	}
+	if err := s.client.LabelTransaction(ctx, txid, label); err != nil {
+		return err
+	}
+	if err := s.labels.set(txid, label); err != nil {
+		return fmt.Errorf("label saved in the wallet but not in the metadata directory: %w", err)
+	}
+	return nil
+}
+
+// WatchAddress blocks while reporting activity on an address the wallet does
+// not own, see Client.WatchAddress.
+func (s *Service) WatchAddress(ctx context.Context, address chainutil.Address, heightHint uint32, onActivity func(AddressActivity)) error {
+	s.cmux.Lock()
+	client := s.client
+	s.cmux.Unlock()
+	if client == nil {
+		return ErrDaemonNotRunning
+	}
+	return client.WatchAddress(ctx, address, heightHint, onActivity)
+}
+
+// WaitForConfirmations blocks until txid reaches numConfs confirmations, see
+// Client.WaitForConfirmations.
+func (s *Service) WaitForConfirmations(ctx context.Context, txid string, pkScript []byte, numConfs, heightHint uint32) (uint32, error) {
+	s.cmux.Lock()
+	client := s.client
+	s.cmux.Unlock()
+	if client == nil {
+		return 0, ErrDaemonNotRunning
+	}
+	return client.WaitForConfirmations(ctx, txid, pkScript, numConfs, heightHint)
+}
+
+func (s *Service) Fee(ctx context.Context, address chainutil.Address, amount chainutil.Amount, confTarget int32) (*lnrpc.EstimateFeeResponse, error) {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return nil, ErrDaemonNotRunning
+	}
+	return s.client.SimpleTransferFee(ctx, address, amount, confTarget)
+}
+
+func (s *Service) FundPsbt(ctx context.Context, addrToAmount map[string]int64, lokiPerVbyte uint64, lockExpirationSeconds uint64) (*FundedPsbt, error) {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return nil, ErrDaemonNotRunning
+	}
+	return s.client.FundPsbt(ctx, addrToAmount, lokiPerVbyte, lockExpirationSeconds)
+}
+
+func (s *Service) SignPsbt(ctx context.Context, packet *psbt.Packet) (*SignedPsbt, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return nil, ErrDaemonNotRunning
 	}
-	return s.client.FundPsbt(addrToAmount, lokiPerVbyte, lockExpirationSeconds)
+	return s.client.SignPsbt(ctx, packet)
 }
 
-func (s *Service) FinalizePsbt(packet *psbt.Packet) (*chainutil.Tx, error) {
+func (s *Service) FinalizePsbt(ctx context.Context, packet *psbt.Packet) (*chainutil.Tx, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return nil, ErrDaemonNotRunning
 	}
-	return s.client.FinalizePsbt(packet)
+	return s.client.FinalizePsbt(ctx, packet)
 }
 
-func (s *Service) PublishTransaction(tx *chainutil.Tx) error {
+func (s *Service) PublishTransaction(ctx context.Context, tx *chainutil.Tx) error {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return ErrDaemonNotRunning
 	}
-	return s.client.PublishTransaction(tx)
+	return s.client.PublishTransaction(ctx, tx)
 }
 
-func (s *Service) ReleaseOutputs(locks []*OutputLock) error {
+func (s *Service) ReleaseOutputs(ctx context.Context, locks []*OutputLock) error {
 	if len(locks) == 0 {
 		return nil
 	}
@@ -732,18 +1185,99 @@ func (s *Service) ReleaseOutputs(locks []*OutputLock) error {
 	if s.client == nil {
 		return ErrDaemonNotRunning
 	}
-	return s.client.ReleaseOutputs(locks)
+	return s.client.ReleaseOutputs(ctx, locks)
+}
+
+// LastBalance returns the latest balance published, nil while the wallet is
+// not open.
+func (s *Service) LastBalance() *lnrpc.WalletBalanceResponse {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	if s.balanceEvent == nil {
+		return nil
+	}
+	return s.balanceEvent.Balance
 }
 
 func (s *Service) GetLastEvent() *Update {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
 	return s.lastEvent
 }
 
-func (s *Service) GetLightningConfig() (*LightningConfig, error) {
+// Status returns the state the wallet is in. Unlike the last update, it is
+// not hidden by the transactions and blocks that came after it.
+func (s *Service) Status() Status {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	if s.statusEvent == nil {
+		return StatusInit
+	}
+	return s.statusEvent.State
+}
+
+// WaitForState blocks until the wallet is in one of states and returns the
+// update that put it there, right away when it already is. It fails when ctx
+// ends or the service stops first.
+func (s *Service) WaitForState(ctx context.Context, states ...Status) (*Update, error) {
+	sub := newSubscriber(states)
+	s.subMu.Lock()
+	if s.statusEvent != nil && slices.Contains(states, s.statusEvent.State) {
+		u := s.statusEvent
+		s.subMu.Unlock()
+		sub.cancel()
+		return u, nil
+	}
+	s.subs = append(s.subs, sub)
+	s.subMu.Unlock()
+	defer s.Unsubscribe(sub.ch)
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case u, ok := <-sub.ch:
+		// The service closes the subscription with an update of its own.
+		if !ok || !slices.Contains(states, u.State) {
+			return nil, ErrDaemonNotRunning
+		}
+		return u, nil
+	}
+}
+
+func (s *Service) DebugSubsystems(ctx context.Context) ([]string, error) {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return nil, ErrDaemonNotRunning
+	}
+	return s.client.DebugSubsystems(ctx)
+}
+
+func (s *Service) SetDebugLevel(ctx context.Context, levelSpec string) (string, error) {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return "", ErrDaemonNotRunning
+	}
+	return s.client.SetDebugLevel(ctx, levelSpec)
+}
+
+func (s *Service) GetLightningConfig(ctx context.Context) (*LightningConfig, error) {
 	s.cmux.Lock()
 	defer s.cmux.Unlock()
 	if s.client == nil {
 		return nil, ErrDaemonNotRunning
 	}
-	return s.client.GetLightningConfig()
+	return s.client.GetLightningConfig(ctx)
+}
+
+// ScopedMacaroon returns a hex encoded macaroon of the scope for another
+// application to connect with.
+func (s *Service) ScopedMacaroon(ctx context.Context, scope MacaroonScope) (string, error) {
+	s.cmux.Lock()
+	defer s.cmux.Unlock()
+	if s.client == nil {
+		return "", ErrDaemonNotRunning
+	}
+	return s.client.ScopedMacaroon(ctx, scope)
 }
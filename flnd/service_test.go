@@ -43,7 +43,7 @@ loop:
 		}
 	}
 
-	mhex, mnemonic, err := svc.CreateWallet(walletPassphrase)
+	mhex, mnemonic, err := svc.CreateWallet(context.Background(), walletPassphrase)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -89,7 +89,7 @@ func openWallet(t *testing.T, flnDir string) *Service {
 }
 
 func walletExists(t *testing.T, svc *Service) {
-	exists, err := svc.WalletExists()
+	exists, err := svc.WalletExists(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -152,7 +152,7 @@ func TestServiceBalance(t *testing.T) {
 	svc := createService(t, createTestTempDir(t))
 	defer svc.Stop()
 	createWallet(t, svc)
-	balance, err := svc.Balance()
+	balance, err := svc.Balance(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -162,12 +162,12 @@ func TestServiceBalance(t *testing.T) {
 func TestServiceMulticonnect(t *testing.T) {
 	svc := createService(t, createTestTempDir(t))
 	createWallet(t, svc)
-	isLocked, err := svc.IsLocked()
+	isLocked, err := svc.IsLocked(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
 	t.Logf("isLocked: %v", isLocked)
-	balance, err := svc.Balance()
+	balance, err := svc.Balance(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -176,11 +176,11 @@ func TestServiceMulticonnect(t *testing.T) {
 	t.Log("stoped")
 
 	svc = openWallet(t, svc.flndConfig.LndDir)
-	if err := svc.Unlock(walletPassphrase); err != nil {
+	if err := svc.Unlock(context.Background(), walletPassphrase); err != nil {
 		t.Fatal(err)
 	}
 	walletReady(t, svc)
-	balance, err = svc.Balance()
+	balance, err = svc.Balance(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -190,11 +190,11 @@ func TestServiceMulticonnect(t *testing.T) {
 
 	/////
 	svc = openWallet(t, svc.flndConfig.LndDir)
-	if err := svc.Unlock(walletPassphrase); err != nil {
+	if err := svc.Unlock(context.Background(), walletPassphrase); err != nil {
 		t.Fatal(err)
 	}
 	walletReady(t, svc)
-	balance, err = svc.Balance()
+	balance, err = svc.Balance(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -207,7 +207,7 @@ func TestServiceMulticonnect(t *testing.T) {
 func TestServiceLocks(t *testing.T) {
 	svc := createService(t, createTestTempDir(t))
 	createWallet(t, svc)
-	isLocked, err := svc.IsLocked()
+	isLocked, err := svc.IsLocked(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -215,18 +215,18 @@ func TestServiceLocks(t *testing.T) {
 	svc.Stop()
 	t.Log("stoped")
 
-	_, err = svc.IsLocked()
+	_, err = svc.IsLocked(context.Background())
 	if !errors.Is(err, ErrDaemonNotRunning) {
 		t.Fatal(err)
 	}
 
 	/////
 	svc = openWallet(t, svc.flndConfig.LndDir)
-	if err := svc.Unlock(walletPassphrase); err != nil {
+	if err := svc.Unlock(context.Background(), walletPassphrase); err != nil {
 		t.Fatal(err)
 	}
 	walletReady(t, svc)
-	balance, err := svc.Balance()
+	balance, err := svc.Balance(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -236,11 +236,11 @@ func TestServiceLocks(t *testing.T) {
 
 	///
 	svc = openWallet(t, svc.flndConfig.LndDir)
-	if err := svc.Unlock(walletPassphrase); err != nil {
+	if err := svc.Unlock(context.Background(), walletPassphrase); err != nil {
 		t.Fatal(err)
 	}
 	walletReady(t, svc)
-	balance, err = svc.Balance()
+	balance, err = svc.Balance(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -255,7 +255,7 @@ func TestServiceWalletExists(t *testing.T) {
 
 	walletStarted(t, svc)
 
-	exists, err := svc.WalletExists()
+	exists, err := svc.WalletExists(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -277,7 +277,7 @@ func TestServiceWalletManager(t *testing.T) {
 
 	createWallet(t, svc)
 
-	txs, err := svc.FetchTransactions()
+	txs, err := svc.FetchTransactions(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -287,20 +287,20 @@ func TestServiceWalletManager(t *testing.T) {
 	}
 
 	for _, at := range []lnrpc.AddressType{lnrpc.AddressType_UNUSED_NESTED_PUBKEY_HASH, lnrpc.AddressType_UNUSED_WITNESS_PUBKEY_HASH, lnrpc.AddressType_UNUSED_TAPROOT_PUBKEY} {
-		address, err := svc.GetNextAddress(at)
+		address, err := svc.GetNextAddress(context.Background(), at)
 		if err != nil {
 			t.Fatal(err)
 		}
 		t.Logf("address[%v]: %v", at, address)
 	}
 
-	balance, err := svc.Balance()
+	balance, err := svc.Balance(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
 	t.Logf("balance: %v", balance)
 
-	isLocked, err := svc.IsLocked()
+	isLocked, err := svc.IsLocked(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -324,7 +324,7 @@ func TestRestoreWalletFromMnemonic(t *testing.T) {
 		"zoo", "lazy", "install", "token", "tired", "attend",
 	}
 
-	encipheredSeed, err := svc.RestoreByMnemonic(mnemonic, walletPassphrase)
+	encipheredSeed, err := svc.RestoreByMnemonic(context.Background(), mnemonic, walletPassphrase)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -336,7 +336,7 @@ func TestRestoreWalletFromMnemonic(t *testing.T) {
 
 	walletReady(t, svc)
 
-	address, err := svc.GetNextAddress(lnrpc.AddressType_UNUSED_NESTED_PUBKEY_HASH)
+	address, err := svc.GetNextAddress(context.Background(), lnrpc.AddressType_UNUSED_NESTED_PUBKEY_HASH)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -363,7 +363,7 @@ func TestAARestoreWalletFromEncipheredSeed(t *testing.T) {
 
 	encipheredSeed := "00d4d7fa50ca971b409719f378fa888bc463f0ecf1517293afffbf3757c7f8a874"
 
-	mnemonics, err := svc.RestoreByEncipheredSeed(encipheredSeed, walletPassphrase)
+	mnemonics, err := svc.RestoreByEncipheredSeed(context.Background(), encipheredSeed, walletPassphrase)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -374,7 +374,7 @@ func TestAARestoreWalletFromEncipheredSeed(t *testing.T) {
 
 	walletReady(t, svc)
 
-	address, err := svc.GetNextAddress(lnrpc.AddressType_UNUSED_NESTED_PUBKEY_HASH)
+	address, err := svc.GetNextAddress(context.Background(), lnrpc.AddressType_UNUSED_NESTED_PUBKEY_HASH)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -397,7 +397,7 @@ func TestAAChangePassphrase(t *testing.T) {
 
 	newPassphrase := "newPassePhrase"
 	svc = openWallet(t, svc.flndConfig.LndDir)
-	if err := svc.ChangePassphrase(walletPassphrase, newPassphrase); err != nil {
+	if err := svc.ChangePassphrase(context.Background(), walletPassphrase, newPassphrase); err != nil {
 		t.Fatal(err)
 	}
 	walletReady(t, svc)
@@ -405,16 +405,16 @@ func TestAAChangePassphrase(t *testing.T) {
 	t.Log("stoped")
 
 	svc = openWallet(t, svc.flndConfig.LndDir)
-	if err := svc.Unlock(walletPassphrase); err == nil {
+	if err := svc.Unlock(context.Background(), walletPassphrase); err == nil {
 		t.Fatal("error expected")
 	}
-	if err := svc.Unlock(newPassphrase); err != nil {
+	if err := svc.Unlock(context.Background(), newPassphrase); err != nil {
 		t.Fatal(err)
 	}
 
 	walletReady(t, svc)
 
-	_, err := svc.GetNextAddress(lnrpc.AddressType_UNUSED_NESTED_PUBKEY_HASH)
+	_, err := svc.GetNextAddress(context.Background(), lnrpc.AddressType_UNUSED_NESTED_PUBKEY_HASH)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,166 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"slices"
+	"sync"
+)
+
+// walletTransitions lists the lifecycle states each state can move to. The
+// daemon reports its state as it starts: waiting, then without a wallet,
+// locked or unlocked, then serving RPCs while the chain syncs, then ready.
+// StatusDown is reachable from every state but itself and ends the
+// lifecycle: a restarted daemon gets a new client.
+var walletTransitions = map[Status][]Status{
+	StatusNone:     {StatusNone, StatusNoWallet, StatusLocked, StatusUnlocked, StatusSyncing, StatusReady},
+	StatusNoWallet: {StatusNone, StatusLocked, StatusUnlocked},
+	StatusLocked:   {StatusNone, StatusUnlocked},
+	StatusUnlocked: {StatusNone, StatusSyncing, StatusReady},
+	StatusSyncing:  {StatusSyncing, StatusReady},
+	StatusReady:    {},
+}
+
+// lifecycle reports whether st is a state of the wallet rather than an event
+// in it.
+func (st Status) lifecycle() bool {
+	switch st {
+	case StatusTransaction, StatusBlock, StatusScanning, StatusBalance:
+		return false
+	}
+	return true
+}
+
+// stateHook runs on every transition, before the update is published. It
+// must not cause another transition.
+type stateHook func(from, to Status)
+
+// stateMachine is the lifecycle of the wallet behind a client, along with
+// the chain sync progress it last saw. Transitions are serialized so the
+// updates they publish keep their order.
+type stateMachine struct {
+	transitionMu sync.Mutex
+
+	mu              sync.Mutex
+	current         Status
+	synced          bool
+	syncedHeight    uint32
+	headerTimestamp int64
+	hooks           []stateHook
+}
+
+// Current returns the lifecycle state. A new machine is in StatusNone.
+func (m *stateMachine) Current() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.current == "" {
+		return StatusNone
+	}
+	return m.current
+}
+
+// OnTransition adds a hook run on every later transition.
+func (m *stateMachine) OnTransition(hook stateHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook)
+}
+
+// canMove reports whether from may move to to.
+func canMove(from, to Status) bool {
+	if to == StatusDown {
+		return from != StatusDown
+	}
+	return slices.Contains(walletTransitions[from], to)
+}
+
+// Transition moves to the state of u and publishes u with publish. An update
+// the current state cannot move to is dropped, and false returned.
+func (m *stateMachine) Transition(u Update, publish func(Update)) bool {
+	m.transitionMu.Lock()
+	defer m.transitionMu.Unlock()
+
+	m.mu.Lock()
+	from := m.current
+	if from == "" {
+		from = StatusNone
+	}
+	if !canMove(from, u.State) {
+		m.mu.Unlock()
+		return false
+	}
+	m.current = u.State
+	hooks := m.hooks
+	m.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(from, u.State)
+	}
+	publish(u)
+	return true
+}
+
+// setSync records the sync progress reported by the daemon.
+func (m *stateMachine) setSync(synced bool, height uint32, headerTimestamp int64, hasHeader bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.synced = synced
+	m.syncedHeight = height
+	if hasHeader {
+		m.headerTimestamp = headerTimestamp
+	}
+}
+
+// SyncedHeight returns the height the wallet was last seen synced to.
+func (m *stateMachine) SyncedHeight() uint32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.syncedHeight
+}
+
+// HeaderTimestamp returns the time of the best known block header.
+func (m *stateMachine) HeaderTimestamp() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.headerTimestamp
+}
+
+// Event publishes u, an update that leaves the lifecycle state as it is, in
+// order with the transitions. Events are dropped once the wallet is down.
+func (m *stateMachine) Event(u Update, publish func(Update)) bool {
+	m.transitionMu.Lock()
+	defer m.transitionMu.Unlock()
+
+	if m.Current() == StatusDown {
+		return false
+	}
+	publish(u)
+	return true
+}
+
+// Block publishes the update of a new block: StatusBlock once the wallet is
+// synced, StatusScanning with the synced height while it catches up. Blocks
+// only count once the wallet is ready, so they never interleave with the
+// StatusSyncing updates that come before.
+func (m *stateMachine) Block(u Update, publish func(Update)) bool {
+	m.transitionMu.Lock()
+	defer m.transitionMu.Unlock()
+
+	m.mu.Lock()
+	ready := m.current == StatusReady
+	if m.synced {
+		u.State = StatusBlock
+	} else {
+		u.State = StatusScanning
+		u.SyncedHeight = m.syncedHeight
+	}
+	m.mu.Unlock()
+
+	if !ready {
+		return false
+	}
+	publish(u)
+	return true
+}
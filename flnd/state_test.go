@@ -0,0 +1,90 @@
+package flnd
+
+import (
+	"testing"
+)
+
+func TestStateMachineTransitions(t *testing.T) {
+	var m stateMachine
+	var published []Status
+	publish := func(u Update) { published = append(published, u.State) }
+
+	var moves []string
+	m.OnTransition(func(from, to Status) {
+		moves = append(moves, string(from)+">"+string(to))
+	})
+
+	if got := m.Current(); got != StatusNone {
+		t.Fatalf("initial state = %s, want %s", got, StatusNone)
+	}
+
+	steps := []struct {
+		to Status
+		ok bool
+	}{
+		{StatusLocked, true},
+		{StatusSyncing, false},
+		{StatusUnlocked, true},
+		{StatusSyncing, true},
+		{StatusSyncing, true},
+		{StatusReady, true},
+		{StatusReady, false},
+		{StatusSyncing, false},
+		{StatusDown, true},
+		{StatusDown, false},
+		{StatusNone, false},
+	}
+	for i, step := range steps {
+		if ok := m.Transition(Update{State: step.to}, publish); ok != step.ok {
+			t.Fatalf("step %d: transition to %s = %v, want %v", i, step.to, ok, step.ok)
+		}
+	}
+
+	want := []Status{StatusLocked, StatusUnlocked, StatusSyncing, StatusSyncing, StatusReady, StatusDown}
+	if len(published) != len(want) {
+		t.Fatalf("published = %v, want %v", published, want)
+	}
+	for i := range want {
+		if published[i] != want[i] {
+			t.Fatalf("published = %v, want %v", published, want)
+		}
+	}
+	if len(moves) != len(want) || moves[0] != "none>locked" || moves[len(moves)-1] != "ready>down" {
+		t.Fatalf("hook saw %v", moves)
+	}
+	if got := m.Current(); got != StatusDown {
+		t.Fatalf("final state = %s, want %s", got, StatusDown)
+	}
+}
+
+func TestStateMachineBlocks(t *testing.T) {
+	var m stateMachine
+	var published []Update
+	publish := func(u Update) { published = append(published, u) }
+
+	m.Transition(Update{State: StatusSyncing}, publish)
+	m.setSync(false, 90, 0, false)
+	if m.Block(Update{BlockHeight: 100}, publish) {
+		t.Fatal("block published before the wallet is ready")
+	}
+
+	m.Transition(Update{State: StatusReady}, publish)
+	m.Block(Update{BlockHeight: 101}, publish)
+	m.setSync(true, 101, 0, false)
+	m.Block(Update{BlockHeight: 102}, publish)
+
+	if len(published) != 4 {
+		t.Fatalf("published %d updates, want 4", len(published))
+	}
+	if u := published[2]; u.State != StatusScanning || u.SyncedHeight != 90 || u.BlockHeight != 101 {
+		t.Fatalf("block while catching up = %+v", u)
+	}
+	if u := published[3]; u.State != StatusBlock || u.BlockHeight != 102 {
+		t.Fatalf("block once synced = %+v", u)
+	}
+
+	m.Transition(Update{State: StatusDown}, publish)
+	if m.Event(Update{State: StatusTransaction}, publish) {
+		t.Fatal("event published after the wallet went down")
+	}
+}
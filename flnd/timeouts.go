@@ -0,0 +1,50 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import "time"
+
+// RPCTimeouts bound the daemon calls by class of operation, for nodes too
+// slow for the built-in timeouts. A zero field keeps the built-in timeout of
+// its class, raised to Default when that is larger.
+type RPCTimeouts struct {
+	// Default bounds the calls of no other class.
+	Default time.Duration
+	// Transactions bounds each page of a transaction fetch.
+	Transactions time.Duration
+	// Fees bounds the fee estimates.
+	Fees time.Duration
+}
+
+// RPCTimeouts returns the timeouts set by the rpctimeout options.
+func (cfg *ServiceConfig) RPCTimeouts() RPCTimeouts {
+	return RPCTimeouts{
+		Default:      cfg.RPCTimeout,
+		Transactions: cfg.RPCTimeoutTransactions,
+		Fees:         cfg.RPCTimeoutFees,
+	}
+}
+
+func (t RPCTimeouts) calls() time.Duration {
+	if t.Default > 0 {
+		return t.Default
+	}
+	return defaultRPCTimeout
+}
+
+func (t RPCTimeouts) transactions() time.Duration {
+	return t.class(t.Transactions, transactionFetchTimeout)
+}
+
+func (t RPCTimeouts) fees() time.Duration {
+	return t.class(t.Fees, defaultRPCTimeout)
+}
+
+func (t RPCTimeouts) class(timeout, builtin time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	return max(builtin, t.Default)
+}
@@ -0,0 +1,32 @@
+package flnd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRPCTimeouts(t *testing.T) {
+	tests := []struct {
+		name                      string
+		timeouts                  RPCTimeouts
+		calls, transactions, fees time.Duration
+	}{
+		{"built-in", RPCTimeouts{}, defaultRPCTimeout, transactionFetchTimeout, defaultRPCTimeout},
+		{"lower default", RPCTimeouts{Default: 2 * time.Second}, 2 * time.Second, transactionFetchTimeout, defaultRPCTimeout},
+		{"raised default", RPCTimeouts{Default: time.Minute}, time.Minute, time.Minute, time.Minute},
+		{"per class", RPCTimeouts{Default: time.Minute, Transactions: 10 * time.Second, Fees: 3 * time.Second}, time.Minute, 10 * time.Second, 3 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.timeouts.calls(); got != tt.calls {
+				t.Errorf("calls = %v, want %v", got, tt.calls)
+			}
+			if got := tt.timeouts.transactions(); got != tt.transactions {
+				t.Errorf("transactions = %v, want %v", got, tt.transactions)
+			}
+			if got := tt.timeouts.fees(); got != tt.fees {
+				t.Errorf("fees = %v, want %v", got, tt.fees)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"slices"
+	"time"
+)
+
+// maxStateTransitions is the number of lifecycle transitions kept.
+const maxStateTransitions = 16
+
+// StateTransition is a change of the lifecycle state of the wallet, with the
+// error that caused it, if any.
+type StateTransition struct {
+	Time     time.Time
+	From, To Status
+	Err      error
+}
+
+// stateLog keeps the last lifecycle transitions and since when the daemon
+// has been running.
+type stateLog struct {
+	transitions []StateTransition
+	upSince     time.Time
+}
+
+func (l *stateLog) record(from, to Status, err error, now time.Time) {
+	if from == to {
+		return
+	}
+	l.transitions = append(l.transitions, StateTransition{Time: now, From: from, To: to, Err: err})
+	if len(l.transitions) > maxStateTransitions {
+		l.transitions = slices.Delete(l.transitions, 0, len(l.transitions)-maxStateTransitions)
+	}
+	switch to {
+	case StatusDown, StatusFailedPermanently, StatusQuit:
+		l.upSince = time.Time{}
+	default:
+		if l.upSince.IsZero() {
+			l.upSince = now
+		}
+	}
+}
+
+// last returns the n latest transitions, the latest last.
+func (l *stateLog) last(n int) []StateTransition {
+	start := max(len(l.transitions)-n, 0)
+	return slices.Clone(l.transitions[start:])
+}
+
+// recordTransition notes the move from the current state to to. subMu is
+// held.
+func (s *Service) recordTransition(to Status, err error) {
+	from := StatusInit
+	if s.statusEvent != nil {
+		from = s.statusEvent.State
+	}
+	s.states.record(from, to, err, time.Now())
+}
+
+// StateTransitions returns the last n lifecycle transitions of the wallet,
+// the latest last.
+func (s *Service) StateTransitions(n int) []StateTransition {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	return s.states.last(n)
+}
+
+// Uptime returns how long the daemon has been running, 0 while it is down.
+func (s *Service) Uptime() time.Duration {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	if s.states.upSince.IsZero() {
+		return 0
+	}
+	return time.Since(s.states.upSince)
+}
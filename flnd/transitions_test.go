@@ -0,0 +1,44 @@
+package flnd
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStateLog(t *testing.T) {
+	var l stateLog
+	start := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	l.record(StatusInit, StatusNone, nil, start)
+	l.record(StatusNone, StatusNone, nil, start.Add(time.Second))
+	l.record(StatusNone, StatusLocked, nil, start.Add(2*time.Second))
+	if !l.upSince.Equal(start) {
+		t.Fatalf("up since %v, want %v", l.upSince, start)
+	}
+	if got := l.last(5); len(got) != 2 || got[1].From != StatusNone || got[1].To != StatusLocked {
+		t.Fatalf("unexpected transitions %+v", got)
+	}
+
+	failed := errors.New("exit status 1")
+	l.record(StatusLocked, StatusDown, failed, start.Add(3*time.Second))
+	if !l.upSince.IsZero() {
+		t.Fatalf("still up since %v once down", l.upSince)
+	}
+	l.record(StatusDown, StatusNone, nil, start.Add(4*time.Second))
+	if !l.upSince.Equal(start.Add(4 * time.Second)) {
+		t.Fatalf("restart up since %v", l.upSince)
+	}
+
+	for i := range 2 * maxStateTransitions {
+		l.record(StatusSyncing, StatusReady, nil, start.Add(time.Duration(10+i)*time.Second))
+	}
+	if len(l.transitions) != maxStateTransitions {
+		t.Fatalf("%d transitions kept, want %d", len(l.transitions), maxStateTransitions)
+	}
+	got := l.last(1)
+	got[0].To = StatusDown
+	if l.transitions[len(l.transitions)-1].To != StatusReady {
+		t.Fatal("last returned the kept transitions rather than a copy")
+	}
+}
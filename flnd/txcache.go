@@ -0,0 +1,104 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/flokiorg/flnd"
+	"github.com/flokiorg/flnd/lncfg"
+	"github.com/flokiorg/flnd/lnrpc"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	txCacheDirname  = "txcache"
+	txCacheFilename = "transactions.cache"
+
+	// txCacheSafeDepth is how deep a transaction must be buried to be saved.
+	// GetTransactions pages by position in a list where confirmed
+	// transactions come first in block order, so saving only those keeps the
+	// saved set a stable prefix the next start can resume paging after.
+	txCacheSafeDepth = 6
+)
+
+// txCachePath returns the file the transaction cache of the active network
+// is saved to, under the flnd directory. It is empty, keeping the cache in
+// memory only, when the config sets no network or directory.
+func txCachePath(config *flnd.Config) string {
+	if config == nil || config.ActiveNetParams.Params == nil || config.LndDir == "" {
+		return ""
+	}
+	network := lncfg.NormalizeNetwork(config.ActiveNetParams.Name)
+	return filepath.Join(config.LndDir, txCacheDirname, network, txCacheFilename)
+}
+
+// loadTxCache reads the saved transactions. The cache is returned dirty so
+// the first fetch still pages from the saved index for newer transactions.
+func loadTxCache(path string) (*txCache, error) {
+	if path == "" {
+		return nil, os.ErrNotExist
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var details lnrpc.TransactionDetails
+	if err := proto.Unmarshal(data, &details); err != nil {
+		return nil, err
+	}
+
+	return &txCache{
+		Txs:       newTxIndex(details.Transactions),
+		LastIndex: details.LastIndex,
+		Dirty:     true,
+	}, nil
+}
+
+// saveTxCache writes the transactions confirmed at least txCacheSafeDepth
+// blocks below tip, and removes the file when there are none.
+func saveTxCache(path string, txs []*lnrpc.Transaction, tip uint32) error {
+	if path == "" {
+		return nil
+	}
+	buried := make([]*lnrpc.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if tx.BlockHeight > 0 && uint32(tx.BlockHeight)+txCacheSafeDepth <= tip {
+			buried = append(buried, tx)
+		}
+	}
+	if len(buried) == 0 {
+		return removeTxCache(path)
+	}
+
+	data, err := proto.Marshal(&lnrpc.TransactionDetails{
+		Transactions: buried,
+		LastIndex:    uint64(len(buried) - 1),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func removeTxCache(path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
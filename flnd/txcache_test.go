@@ -0,0 +1,76 @@
+package flnd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flokiorg/flnd"
+	"github.com/flokiorg/flnd/lnrpc"
+)
+
+func TestTxCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), txCacheDirname, "testnet", txCacheFilename)
+
+	if _, err := loadTxCache(path); !os.IsNotExist(err) {
+		t.Fatalf("loadTxCache on a missing file = %v, want not exist", err)
+	}
+
+	txs := []*lnrpc.Transaction{
+		{TxHash: "unconfirmed"},
+		{TxHash: "shallow", BlockHeight: 98},
+		{TxHash: "buried", BlockHeight: 94, Amount: 1500},
+		{TxHash: "old", BlockHeight: 10},
+	}
+	if err := saveTxCache(path, txs, 100); err != nil {
+		t.Fatalf("saveTxCache: %v", err)
+	}
+
+	cache, err := loadTxCache(path)
+	if err != nil {
+		t.Fatalf("loadTxCache: %v", err)
+	}
+	if !cache.Dirty {
+		t.Error("loaded cache is not dirty")
+	}
+	if cache.LastIndex != 1 {
+		t.Errorf("LastIndex = %d, want 1", cache.LastIndex)
+	}
+	loaded := cache.Txs.Newest(0)
+	if len(loaded) != 2 || loaded[0].TxHash != "buried" || loaded[1].TxHash != "old" {
+		t.Fatalf("loaded txs = %v", loaded)
+	}
+	if loaded[0].Amount != 1500 {
+		t.Errorf("loaded amount = %d, want 1500", loaded[0].Amount)
+	}
+
+	// Nothing buried deep enough: the stale file goes away.
+	if err := saveTxCache(path, txs[:2], 100); err != nil {
+		t.Fatalf("saveTxCache: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("cache file kept without buried transactions: %v", err)
+	}
+}
+
+func TestTxCacheCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), txCacheFilename)
+	if err := os.WriteFile(path, []byte("not a cache"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadTxCache(path); err == nil {
+		t.Fatal("corrupt cache loaded")
+	}
+}
+
+func TestTxCacheWithoutNetwork(t *testing.T) {
+	if path := txCachePath(&flnd.Config{}); path != "" {
+		t.Fatalf("txCachePath without a network = %q, want none", path)
+	}
+	if err := saveTxCache("", []*lnrpc.Transaction{{TxHash: "buried", BlockHeight: 10}}, 100); err != nil {
+		t.Fatalf("saveTxCache without a path: %v", err)
+	}
+	if _, err := loadTxCache(""); !os.IsNotExist(err) {
+		t.Fatalf("loadTxCache without a path = %v, want not exist", err)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"sort"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// txMemoryOverhead roughly accounts for the Go structures around the fields
+// of a transaction, on top of their encoded size.
+const txMemoryOverhead = 512
+
+// txMemorySize estimates the memory held by tx.
+func txMemorySize(tx *lnrpc.Transaction) int {
+	return proto.Size(tx) + txMemoryOverhead
+}
+
+// txIndex keeps the cached transactions ordered by (TimeStamp, BlockHeight)
+// and indexed by hash. They are stored oldest first, so the newer
+// transactions a refresh brings are appended, and a known transaction is
+// replaced where it is unless its new sort key moved it.
+type txIndex struct {
+	asc   []*lnrpc.Transaction
+	pos   map[string]int
+	bytes int
+}
+
+func newTxIndex(txs []*lnrpc.Transaction) txIndex {
+	var idx txIndex
+	idx.Merge(txs)
+	return idx
+}
+
+// txOlder orders transactions oldest first.
+func txOlder(a, b *lnrpc.Transaction) bool {
+	if a.TimeStamp != b.TimeStamp {
+		return a.TimeStamp < b.TimeStamp
+	}
+	return a.BlockHeight < b.BlockHeight
+}
+
+func (idx *txIndex) Len() int {
+	return len(idx.asc)
+}
+
+// Bytes estimates the memory held by the transactions.
+func (idx *txIndex) Bytes() int {
+	return idx.bytes
+}
+
+func (idx *txIndex) Has(hash string) bool {
+	_, ok := idx.pos[hash]
+	return ok
+}
+
+// Merge adds txs, replacing the known ones. It costs a map lookup per
+// transaction, plus a shift of the newer ones for a new transaction that does
+// not belong at the end, or of the ones a known transaction moves past when
+// its sort key changed.
+func (idx *txIndex) Merge(txs []*lnrpc.Transaction) {
+	if idx.pos == nil {
+		idx.pos = make(map[string]int, len(txs))
+	}
+	for _, tx := range txs {
+		if tx == nil {
+			continue
+		}
+		if i, ok := idx.pos[tx.TxHash]; ok {
+			idx.replace(i, tx)
+			continue
+		}
+		idx.insert(tx)
+	}
+}
+
+// replace puts tx in place of the transaction at i, and moves it when its
+// sort key no longer fits between its neighbours: toward the older ones
+// before the first it is not older than, toward the newer ones after the
+// last it is not newer than. Only the positions it moves past are shifted.
+func (idx *txIndex) replace(i int, tx *lnrpc.Transaction) {
+	idx.bytes += txMemorySize(tx) - txMemorySize(idx.asc[i])
+	idx.asc[i] = tx
+
+	switch n := len(idx.asc); {
+	case i > 0 && txOlder(tx, idx.asc[i-1]):
+		j := sort.Search(i, func(j int) bool { return !txOlder(idx.asc[j], tx) })
+		copy(idx.asc[j+1:i+1], idx.asc[j:i])
+		idx.asc[j] = tx
+		idx.reindex(j, i+1)
+	case i < n-1 && txOlder(idx.asc[i+1], tx):
+		j := i + sort.Search(n-i-1, func(j int) bool { return txOlder(tx, idx.asc[i+1+j]) })
+		copy(idx.asc[i:j], idx.asc[i+1:j+1])
+		idx.asc[j] = tx
+		idx.reindex(i, j+1)
+	}
+}
+
+// insert places tx before the transactions it is not older than, keeping the
+// known ones first among ties.
+func (idx *txIndex) insert(tx *lnrpc.Transaction) {
+	idx.bytes += txMemorySize(tx)
+	n := len(idx.asc)
+	if n == 0 || txOlder(idx.asc[n-1], tx) {
+		idx.pos[tx.TxHash] = n
+		idx.asc = append(idx.asc, tx)
+		return
+	}
+
+	i := sort.Search(n, func(i int) bool { return !txOlder(idx.asc[i], tx) })
+	idx.asc = append(idx.asc, nil)
+	copy(idx.asc[i+1:], idx.asc[i:])
+	idx.asc[i] = tx
+	idx.reindex(i, len(idx.asc))
+}
+
+// Evict drops the oldest confirmed transactions until at most limit are
+// left, and returns how many it dropped. Unconfirmed transactions are kept.
+func (idx *txIndex) Evict(limit int) int {
+	drop := len(idx.asc) - limit
+	if limit <= 0 || drop <= 0 {
+		return 0
+	}
+
+	kept := idx.asc[:0]
+	evicted := 0
+	for _, tx := range idx.asc {
+		if evicted < drop && tx.BlockHeight > 0 {
+			idx.bytes -= txMemorySize(tx)
+			delete(idx.pos, tx.TxHash)
+			evicted++
+			continue
+		}
+		kept = append(kept, tx)
+	}
+	for i := len(kept); i < len(idx.asc); i++ {
+		idx.asc[i] = nil
+	}
+	idx.asc = kept
+	idx.reindex(0, len(idx.asc))
+	return evicted
+}
+
+// reindex records the positions of the transactions in [from, to).
+func (idx *txIndex) reindex(from, to int) {
+	for i := from; i < to; i++ {
+		idx.pos[idx.asc[i].TxHash] = i
+	}
+}
+
+// Newest returns a copy of up to limit transactions, newest first. No limit
+// returns them all.
+func (idx *txIndex) Newest(limit int) []*lnrpc.Transaction {
+	n := len(idx.asc)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	txs := make([]*lnrpc.Transaction, n)
+	for i := range txs {
+		txs[i] = idx.asc[len(idx.asc)-1-i]
+	}
+	return txs
+}
@@ -0,0 +1,136 @@
+package flnd
+
+import (
+	"testing"
+
+	"github.com/flokiorg/flnd/lnrpc"
+)
+
+func txHashes(txs []*lnrpc.Transaction) []string {
+	hashes := make([]string, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.TxHash
+	}
+	return hashes
+}
+
+func TestTxIndexMerge(t *testing.T) {
+	idx := newTxIndex([]*lnrpc.Transaction{
+		{TxHash: "b", TimeStamp: 20, BlockHeight: 2},
+		{TxHash: "a", TimeStamp: 10, BlockHeight: 1},
+	})
+
+	idx.Merge([]*lnrpc.Transaction{
+		{TxHash: "d", TimeStamp: 40},
+		{TxHash: "c", TimeStamp: 30},
+		{TxHash: "a", TimeStamp: 10, BlockHeight: 1, Label: "relabelled"},
+	})
+
+	want := []string{"d", "c", "b", "a"}
+	got := txHashes(idx.Newest(0))
+	if len(got) != len(want) {
+		t.Fatalf("Newest = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Newest = %v, want %v", got, want)
+		}
+	}
+	if idx.Newest(0)[3].Label != "relabelled" {
+		t.Error("known transaction not replaced in place")
+	}
+
+	// Confirming "c" in a later block moves it ahead of "d".
+	idx.Merge([]*lnrpc.Transaction{{TxHash: "c", TimeStamp: 45, BlockHeight: 5}})
+	if got := txHashes(idx.Newest(2)); got[0] != "c" || got[1] != "d" {
+		t.Fatalf("after confirmation Newest(2) = %v", got)
+	}
+	if idx.Len() != 4 || !idx.Has("c") {
+		t.Fatalf("Len = %d", idx.Len())
+	}
+	for hash, i := range idx.pos {
+		if idx.asc[i].TxHash != hash {
+			t.Fatalf("position of %s points to %s", hash, idx.asc[i].TxHash)
+		}
+	}
+}
+
+func TestTxIndexEvict(t *testing.T) {
+	idx := newTxIndex([]*lnrpc.Transaction{
+		{TxHash: "pending", TimeStamp: 5},
+		{TxHash: "a", TimeStamp: 10, BlockHeight: 1},
+		{TxHash: "b", TimeStamp: 20, BlockHeight: 2},
+		{TxHash: "c", TimeStamp: 30, BlockHeight: 3},
+	})
+	full := idx.Bytes()
+	if full <= 0 {
+		t.Fatalf("Bytes = %d", full)
+	}
+
+	if n := idx.Evict(0); n != 0 {
+		t.Fatalf("Evict(0) dropped %d", n)
+	}
+	if n := idx.Evict(2); n != 2 {
+		t.Fatalf("Evict(2) dropped %d, want 2", n)
+	}
+	got := txHashes(idx.Newest(0))
+	if len(got) != 2 || got[0] != "c" || got[1] != "pending" {
+		t.Fatalf("after eviction Newest = %v", got)
+	}
+	if idx.Has("a") || idx.Has("b") {
+		t.Error("evicted transaction still indexed")
+	}
+	if want := txMemorySize(idx.asc[0]) + txMemorySize(idx.asc[1]); idx.Bytes() != want {
+		t.Errorf("Bytes = %d, want %d", idx.Bytes(), want)
+	}
+
+	// Unconfirmed transactions stay even past the limit.
+	if n := idx.Evict(1); n != 1 || !idx.Has("pending") {
+		t.Fatalf("Evict(1) dropped %d, kept %v", n, txHashes(idx.Newest(0)))
+	}
+}
+
+func TestTxIndexMergeMoves(t *testing.T) {
+	idx := newTxIndex([]*lnrpc.Transaction{
+		{TxHash: "a", TimeStamp: 10, BlockHeight: 1},
+		{TxHash: "b", TimeStamp: 20},
+		{TxHash: "c", TimeStamp: 30, BlockHeight: 3},
+		{TxHash: "d", TimeStamp: 40, BlockHeight: 4},
+		{TxHash: "e", TimeStamp: 50, BlockHeight: 5},
+	})
+	check := func(step string, want ...string) {
+		t.Helper()
+		for i, hash := range want {
+			if idx.asc[i].TxHash != hash {
+				t.Fatalf("%s: order = %v, want %v", step, txHashes(idx.asc), want)
+			}
+			if idx.pos[hash] != i {
+				t.Fatalf("%s: position of %s = %d, want %d", step, hash, idx.pos[hash], i)
+			}
+		}
+	}
+
+	// Confirmed in a block that keeps its place, "b" is updated in place.
+	idx.Merge([]*lnrpc.Transaction{{TxHash: "b", TimeStamp: 20, BlockHeight: 2}})
+	check("confirmed in place", "a", "b", "c", "d", "e")
+
+	// Newer, "b" moves past "c" and "d", before "e".
+	idx.Merge([]*lnrpc.Transaction{{TxHash: "b", TimeStamp: 45, BlockHeight: 4}})
+	check("moved newer", "a", "c", "d", "b", "e")
+
+	// Older, "e" moves before "c", staying ahead of its equal.
+	idx.Merge([]*lnrpc.Transaction{{TxHash: "e", TimeStamp: 30, BlockHeight: 3}})
+	check("moved older", "a", "e", "c", "d", "b")
+
+	// Past either end.
+	idx.Merge([]*lnrpc.Transaction{{TxHash: "a", TimeStamp: 60}, {TxHash: "b", TimeStamp: 1}})
+	check("moved to the ends", "b", "e", "c", "d", "a")
+
+	want := 0
+	for _, tx := range idx.asc {
+		want += txMemorySize(tx)
+	}
+	if idx.Bytes() != want {
+		t.Errorf("Bytes = %d, want %d", idx.Bytes(), want)
+	}
+}
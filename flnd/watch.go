@@ -0,0 +1,182 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package flnd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/flokiorg/flnd/lnrpc/chainrpc"
+	"github.com/flokiorg/go-flokicoin/chaincfg/chainhash"
+	"github.com/flokiorg/go-flokicoin/chainutil"
+	"github.com/flokiorg/go-flokicoin/txscript"
+	"github.com/flokiorg/go-flokicoin/wire"
+	"google.golang.org/grpc/metadata"
+)
+
+// AddressActivity is a confirmed payment to, or spend from, a watched
+// address that the wallet does not own.
+type AddressActivity struct {
+	Address     string
+	Txid        string
+	BlockHeight uint32
+
+	// Amount is positive for funds received and negative for funds spent.
+	Amount chainutil.Amount
+}
+
+// WatchAddress reports confirmed activity on address from heightHint onwards
+// until ctx is cancelled or a notification stream fails. Payments are found by
+// script through the chain notifier, and every output paying the address is
+// then followed until it is spent. A light client can only match one
+// transaction per block for a script, so several payments to the address in
+// the same block are reported as the first one.
+func (c *Client) WatchAddress(ctx context.Context, address chainutil.Address, heightHint uint32, onActivity func(AddressActivity)) error {
+	if c.closing {
+		return ErrDaemonNotRunning
+	}
+
+	script, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancelCause(metadata.NewOutgoingContext(ctx, metadata.Pairs("macaroon", c.adminMacHex)))
+	defer cancel(nil)
+
+	for {
+		stream, err := c.ntfClient.RegisterConfirmationsNtfn(ctx, &chainrpc.ConfRequest{
+			Txid:       make([]byte, 32),
+			Script:     script,
+			NumConfs:   1,
+			HeightHint: heightHint,
+		})
+		if err != nil {
+			return watchError(ctx, err)
+		}
+
+		var conf *chainrpc.ConfDetails
+		for conf == nil {
+			event, err := stream.Recv()
+			if err != nil {
+				return watchError(ctx, err)
+			}
+			conf = event.GetConf()
+		}
+
+		var tx wire.MsgTx
+		if err := tx.Deserialize(bytes.NewReader(conf.RawTx)); err != nil {
+			return fmt.Errorf("invalid confirmed transaction: %w", err)
+		}
+		txHash := tx.TxHash()
+
+		var received chainutil.Amount
+		for i, out := range tx.TxOut {
+			if !bytes.Equal(out.PkScript, script) {
+				continue
+			}
+			received += chainutil.Amount(out.Value)
+
+			outpoint := &chainrpc.Outpoint{Hash: txHash[:], Index: uint32(i)}
+			go func(value int64) {
+				if err := c.watchSpend(ctx, address, script, outpoint, value, conf.BlockHeight, onActivity); err != nil {
+					cancel(err)
+				}
+			}(out.Value)
+		}
+
+		onActivity(AddressActivity{
+			Address:     address.String(),
+			Txid:        txHash.String(),
+			BlockHeight: conf.BlockHeight,
+			Amount:      received,
+		})
+
+		heightHint = conf.BlockHeight + 1
+	}
+}
+
+func (c *Client) watchSpend(ctx context.Context, address chainutil.Address, script []byte, outpoint *chainrpc.Outpoint, value int64, heightHint uint32, onActivity func(AddressActivity)) error {
+	stream, err := c.ntfClient.RegisterSpendNtfn(ctx, &chainrpc.SpendRequest{
+		Outpoint:   outpoint,
+		Script:     script,
+		HeightHint: heightHint,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		spend := event.GetSpend()
+		if spend == nil {
+			continue
+		}
+
+		txid := "-"
+		if hash, err := chainhash.NewHash(spend.SpendingTxHash); err == nil {
+			txid = hash.String()
+		}
+		onActivity(AddressActivity{
+			Address:     address.String(),
+			Txid:        txid,
+			BlockHeight: spend.SpendingHeight,
+			Amount:      -chainutil.Amount(value),
+		})
+		return nil
+	}
+}
+
+// watchError prefers the failure of a spend watcher, which cancels the
+// shared context, over the resulting cancellation of the main stream.
+func watchError(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); cause != nil {
+		return cause
+	}
+	return err
+}
+
+// WaitForConfirmations blocks until the transaction txid, identified to light
+// clients by one of its output scripts, has numConfs confirmations. It
+// returns the height of the block that mined it.
+func (c *Client) WaitForConfirmations(ctx context.Context, txid string, pkScript []byte, numConfs, heightHint uint32) (uint32, error) {
+	if c.closing {
+		return 0, ErrDaemonNotRunning
+	}
+
+	hash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs("macaroon", c.adminMacHex))
+	stream, err := c.ntfClient.RegisterConfirmationsNtfn(ctx, &chainrpc.ConfRequest{
+		Txid:       hash[:],
+		Script:     pkScript,
+		NumConfs:   numConfs,
+		HeightHint: heightHint,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if conf := event.GetConf(); conf != nil {
+			return conf.BlockHeight, nil
+		}
+	}
+}
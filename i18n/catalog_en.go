@@ -0,0 +1,98 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package i18n
+
+var englishCatalog = map[string]string{
+	"balance.label":       "Balance",
+	"balance.unconfirmed": "Unconfirmed",
+	"balance.locked":      "Locked",
+
+	"shortcut.transactions":  "Transactions",
+	"shortcut.addresses":     "Addresses",
+	"shortcut.sign_verify":   "Sign & Verify",
+	"shortcut.resync":        "Resync",
+	"shortcut.logs":          "Logs",
+	"shortcut.lightning":     "Lightning Config",
+	"shortcut.sign_psbt":     "Sign PSBT",
+	"shortcut.notifications": "Notifications",
+	"shortcut.denomination":  "FLC/loki",
+	"shortcut.columns":       "Columns",
+	"shortcut.tx_menu":       "Tx actions",
+	"shortcut.mark":          "Mark txs",
+	"shortcut.balance_chart": "Balance chart",
+	"shortcut.watched":       "Watched",
+	"shortcut.journal":       "Journal",
+	"shortcut.settings":      "Daemon settings",
+	"shortcut.coins":         "Coins",
+	"shortcut.split":         "Split view",
+	"shortcut.date_filter":   "Date filter",
+	"shortcut.export":        "Export CSV",
+	"shortcut.about":         "About",
+	"shortcut.debug_info":    "Debug info",
+	"shortcut.sort":          "Sort",
+	"shortcut.send":          "Send",
+	"shortcut.receive":       "Receive",
+
+	"onboard.new_wallet":        "New Wallet",
+	"onboard.restore_wallet":    "Restore wallet",
+	"onboard.from":              "From: ",
+	"onboard.mnemonic":          "Mnemonic: ",
+	"onboard.hex":               "Hex: ",
+	"onboard.spending_pass":     "Spending passphrase: ",
+	"onboard.confirm_pass":      "Confirm passphrase: ",
+	"onboard.lock_pass":         "Lock passphrase: ",
+	"onboard.confirm_lock_pass": "Confirm lock passphrase: ",
+	"onboard.restore":           "Restore",
+	"onboard.continue":          "Continue",
+	"onboard.restoring":         "⚡ restoring...",
+	"onboard.creating":          "⚡ creating...",
+	"onboard.written_down":      "I have written down all words",
+	"onboard.confirm_title":     "confirm?",
+	"onboard.not_saved":         "Your mnemonic is NOT saved in the database and CANNOT be restored. Make sure to save it securely.",
+	"onboard.not_saved_quiz":    "Your mnemonic is NOT saved in the database and CANNOT be restored. Prove you saved it by answering a few words, or skip at your own risk.",
+	"onboard.cancel":            "Cancel",
+	"onboard.risk_accepted":     "Risk Accepted",
+	"onboard.verify_words":      "Verify words",
+	"onboard.skip":              "Skip",
+	"onboard.quiz_word":         "Word #%d: ",
+	"onboard.quiz_verify":       "Verify",
+	"onboard.quiz_mismatch":     "The words do not match your mnemonic. Check your backup and try again.",
+	"onboard.quiz_info":         "Enter the requested words from your mnemonic backup.",
+	"onboard.invalid_mnemonic":  "invalid mnemonic: %s",
+	"onboard.restore_failed":    "failed to restore: %v",
+	"onboard.create_failed":     "failed to create: %s",
+	"onboard.wordlist_mismatch": "word %d (%q) is not in the %s wordlist",
+	"onboard.step_wallet":       "Wallet",
+	"onboard.step_backup":       "Backup",
+	"onboard.step_verify":       "Verify",
+	"wizard.back":               "Back",
+	"mnemonic.placeholder":      "type a word, tab to complete",
+	"mnemonic.count":            "%d/%d words",
+	"mnemonic.unknown":          "Unknown word #%d: %s",
+	"mnemonic.checksum_ok":      "Checksum OK",
+	"mnemonic.checksum_bad":     "Checksum mismatch, check the words and their order",
+
+	"copy.copied":     "📋 Copied %s",
+	"copy.sent":       "📋 Sent %s to the terminal clipboard",
+	"copy.failed":     "[red:-:-]Copy failed:[-:-:-] %v",
+	"copy.no_display": "right-click to copy (no display)",
+
+	"password.placeholder":    "ctrl+r to show",
+	"cipher.title":            "Cipher Card",
+	"cipher.hex":              "Hex: ",
+	"cipher.copy":             "copy",
+	"cipher.mnemonic":         "Mnemonic:",
+	"cipher.inline_mnemonic":  "Inline Mnemonic:",
+	"cipher.qr":               "qr",
+	"cipher.qr_title":         "Seed QR",
+	"cipher.qr_warning_title": "warning",
+	"cipher.qr_warning":       "The QR code holds your whole enciphered seed. Anyone who scans or photographs it can restore your wallet. Make sure nobody and no camera can see your screen.",
+	"cipher.qr_show":          "Show QR",
+	"cipher.qr_hint":          "Esc to hide",
+	"cipher.copied_what":      "cipher",
+	"cipher.wordlist_note":    "Wordlist: %s (restore with the same wordlist selected)",
+	"language.en":             "English",
+	"language.es":             "Spanish",
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package i18n
+
+var spanishCatalog = map[string]string{
+	"balance.label":       "Saldo",
+	"balance.unconfirmed": "Sin confirmar",
+	"balance.locked":      "Bloqueado",
+
+	"shortcut.transactions":  "Transacciones",
+	"shortcut.addresses":     "Direcciones",
+	"shortcut.sign_verify":   "Firmar y verificar",
+	"shortcut.resync":        "Resincronizar",
+	"shortcut.logs":          "Registros",
+	"shortcut.lightning":     "Config. Lightning",
+	"shortcut.sign_psbt":     "Firmar PSBT",
+	"shortcut.notifications": "Notificaciones",
+	"shortcut.denomination":  "FLC/loki",
+	"shortcut.columns":       "Columnas",
+	"shortcut.tx_menu":       "Acciones tx",
+	"shortcut.mark":          "Marcar txs",
+	"shortcut.balance_chart": "Gráfico de saldo",
+	"shortcut.watched":       "Vigiladas",
+	"shortcut.journal":       "Diario",
+	"shortcut.settings":      "Ajustes del daemon",
+	"shortcut.coins":         "Monedas",
+	"shortcut.split":         "Vista dividida",
+	"shortcut.date_filter":   "Filtrar fechas",
+	"shortcut.export":        "Exportar CSV",
+	"shortcut.about":         "Acerca de",
+	"shortcut.debug_info":    "Depuración",
+	"shortcut.sort":          "Ordenar",
+	"shortcut.send":          "Enviar",
+	"shortcut.receive":       "Recibir",
+
+	"onboard.new_wallet":        "Nueva cartera",
+	"onboard.restore_wallet":    "Restaurar cartera",
+	"onboard.from":              "Desde: ",
+	"onboard.mnemonic":          "Mnemónico: ",
+	"onboard.hex":               "Hex: ",
+	"onboard.spending_pass":     "Contraseña de gasto: ",
+	"onboard.confirm_pass":      "Confirmar contraseña: ",
+	"onboard.lock_pass":         "Contraseña de bloqueo: ",
+	"onboard.confirm_lock_pass": "Confirmar contraseña: ",
+	"onboard.restore":           "Restaurar",
+	"onboard.continue":          "Continuar",
+	"onboard.restoring":         "⚡ restaurando...",
+	"onboard.creating":          "⚡ creando...",
+	"onboard.written_down":      "He anotado todas las palabras",
+	"onboard.confirm_title":     "¿confirmar?",
+	"onboard.not_saved":         "Tu mnemónico NO se guarda en la base de datos y NO se puede recuperar. Guárdalo en un lugar seguro.",
+	"onboard.not_saved_quiz":    "Tu mnemónico NO se guarda en la base de datos y NO se puede recuperar. Demuestra que lo guardaste respondiendo algunas palabras, u omite bajo tu propio riesgo.",
+	"onboard.cancel":            "Cancelar",
+	"onboard.risk_accepted":     "Acepto el riesgo",
+	"onboard.verify_words":      "Verificar palabras",
+	"onboard.skip":              "Omitir",
+	"onboard.quiz_word":         "Palabra #%d: ",
+	"onboard.quiz_verify":       "Verificar",
+	"onboard.quiz_mismatch":     "Las palabras no coinciden con tu mnemónico. Revisa tu copia e inténtalo de nuevo.",
+	"onboard.quiz_info":         "Introduce las palabras solicitadas de tu copia del mnemónico.",
+	"onboard.invalid_mnemonic":  "mnemónico no válido: %s",
+	"onboard.restore_failed":    "no se pudo restaurar: %v",
+	"onboard.create_failed":     "no se pudo crear: %s",
+	"onboard.wordlist_mismatch": "la palabra %d (%q) no está en la lista %s",
+	"onboard.step_wallet":       "Cartera",
+	"onboard.step_backup":       "Copia",
+	"onboard.step_verify":       "Verificar",
+	"wizard.back":               "Atrás",
+	"mnemonic.placeholder":      "escribe una palabra, tab para completar",
+	"mnemonic.count":            "%d/%d palabras",
+	"mnemonic.unknown":          "Palabra desconocida #%d: %s",
+	"mnemonic.checksum_ok":      "Suma de verificación correcta",
+	"mnemonic.checksum_bad":     "La suma de verificación no coincide, revisa las palabras y su orden",
+
+	"copy.copied":     "📋 Copiado: %s",
+	"copy.sent":       "📋 %s enviado al portapapeles del terminal",
+	"copy.failed":     "[red:-:-]Error al copiar:[-:-:-] %v",
+	"copy.no_display": "clic derecho para copiar (sin pantalla)",
+
+	"password.placeholder":    "ctrl+r para mostrar",
+	"cipher.title":            "Tarjeta de cifrado",
+	"cipher.hex":              "Hex: ",
+	"cipher.copy":             "copiar",
+	"cipher.mnemonic":         "Mnemónico:",
+	"cipher.inline_mnemonic":  "Mnemónico en línea:",
+	"cipher.qr":               "qr",
+	"cipher.qr_title":         "QR de la semilla",
+	"cipher.qr_warning_title": "aviso",
+	"cipher.qr_warning":       "El código QR contiene toda tu semilla cifrada. Cualquiera que lo escanee o fotografíe puede restaurar tu cartera. Asegúrate de que nadie ni ninguna cámara pueda ver tu pantalla.",
+	"cipher.qr_show":          "Mostrar QR",
+	"cipher.qr_hint":          "Esc para ocultar",
+	"cipher.copied_what":      "cifrado",
+	"cipher.wordlist_note":    "Lista de palabras: %s (restaura con la misma lista seleccionada)",
+	"language.en":             "inglés",
+	"language.es":             "español",
+}
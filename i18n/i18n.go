@@ -0,0 +1,74 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+// Package i18n holds the translated UI messages and the seed wordlists.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type Language string
+
+const (
+	English Language = "en"
+	Spanish Language = "es"
+)
+
+var catalogs = map[Language]map[string]string{
+	English: englishCatalog,
+	Spanish: spanishCatalog,
+}
+
+var (
+	mu           sync.RWMutex
+	language     = English
+	seedLanguage = English
+)
+
+// Languages lists the supported languages.
+func Languages() []Language {
+	return []Language{English, Spanish}
+}
+
+// ParseLanguage validates a language code such as "en" or "es".
+func ParseLanguage(value string) (Language, error) {
+	lang := Language(strings.ToLower(strings.TrimSpace(value)))
+	if _, ok := catalogs[lang]; !ok {
+		return "", fmt.Errorf("unsupported language %q", value)
+	}
+	return lang, nil
+}
+
+// SetLanguage selects the language of the UI messages.
+func SetLanguage(lang Language) {
+	mu.Lock()
+	defer mu.Unlock()
+	language = lang
+}
+
+// CurrentLanguage returns the language of the UI messages.
+func CurrentLanguage() Language {
+	mu.RLock()
+	defer mu.RUnlock()
+	return language
+}
+
+// T returns the message registered under key in the active language,
+// formatted with args. Missing translations fall back to English, then to
+// the key itself.
+func T(key string, args ...any) string {
+	msg, ok := catalogs[CurrentLanguage()][key]
+	if !ok {
+		if msg, ok = englishCatalog[key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
@@ -0,0 +1,60 @@
+package i18n
+
+import (
+	"testing"
+)
+
+func TestWordlists(t *testing.T) {
+	for _, lang := range Languages() {
+		words := WordList(lang)
+		if len(words) != 2048 {
+			t.Fatalf("%s wordlist has %d words, want 2048", lang, len(words))
+		}
+		if len(wordIndexes[lang]) != len(words) {
+			t.Fatalf("%s wordlist has ambiguous words once folded", lang)
+		}
+	}
+}
+
+func TestMnemonicRoundTrip(t *testing.T) {
+	SetSeedLanguage(Spanish)
+	defer SetSeedLanguage(English)
+
+	english := []string{"abandon", "ability", "zoo", "action"}
+	localized, err := LocalizeMnemonic(english)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if localized[0] != WordList(Spanish)[0] || localized[2] != WordList(Spanish)[2047] {
+		t.Fatalf("unexpected localized words %v", localized)
+	}
+
+	// Users may type the words without accents.
+	typed := append([]string(nil), localized...)
+	typed[0] = "abaco"
+	back, err := CanonicalMnemonic(typed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range english {
+		if back[i] != english[i] {
+			t.Fatalf("word %d: got %q, want %q", i, back[i], english[i])
+		}
+	}
+
+	if _, err := CanonicalMnemonic([]string{"notaword"}); err == nil {
+		t.Fatal("expected an error for an unknown word")
+	}
+}
+
+func TestTFallback(t *testing.T) {
+	SetLanguage(Spanish)
+	defer SetLanguage(English)
+
+	if got := T("onboard.quiz_word", 3); got != "Palabra #3: " {
+		t.Fatalf("unexpected translation %q", got)
+	}
+	if got := T("missing.key"); got != "missing.key" {
+		t.Fatalf("unexpected fallback %q", got)
+	}
+}
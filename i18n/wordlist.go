@@ -0,0 +1,130 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package i18n
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/flokiorg/flnd/aezeed"
+)
+
+// spanishWords is the BIP39 Spanish wordlist, NFC normalized. aezeed encodes the seed with
+// 11 bit indexes into the BIP39 English list, so any 2048 word BIP39 list can
+// display the same seed.
+//
+//go:embed wordlists/spanish.txt
+var spanishWords string
+
+var wordlists = map[Language][]string{
+	English: aezeed.DefaultWordList,
+	Spanish: strings.Split(strings.TrimSpace(spanishWords), "\n"),
+}
+
+// wordIndexes maps the folded form of every word to its index.
+var wordIndexes = func() map[Language]map[string]int {
+	indexes := make(map[Language]map[string]int, len(wordlists))
+	for lang, words := range wordlists {
+		index := make(map[string]int, len(words))
+		for i, word := range words {
+			index[foldWord(word)] = i
+		}
+		indexes[lang] = index
+	}
+	return indexes
+}()
+
+// SetSeedLanguage selects the wordlist used to display and enter seeds.
+func SetSeedLanguage(lang Language) {
+	mu.Lock()
+	defer mu.Unlock()
+	seedLanguage = lang
+}
+
+// CurrentSeedLanguage returns the wordlist used to display and enter seeds.
+func CurrentSeedLanguage() Language {
+	mu.RLock()
+	defer mu.RUnlock()
+	return seedLanguage
+}
+
+// WordList returns the seed wordlist of lang.
+func WordList(lang Language) []string {
+	if words, ok := wordlists[lang]; ok {
+		return words
+	}
+	return wordlists[English]
+}
+
+// WordIndex returns the position of word in the wordlist of lang. Case and
+// accents are ignored.
+func WordIndex(lang Language, word string) (int, bool) {
+	index, ok := wordIndexes[lang][foldWord(word)]
+	return index, ok
+}
+
+// SameWord reports whether a and b are the same seed word once case and
+// accents are ignored.
+func SameWord(a, b string) bool {
+	return foldWord(a) == foldWord(b)
+}
+
+// LocalizeMnemonic converts English aezeed words to the seed language.
+func LocalizeMnemonic(words []string) ([]string, error) {
+	return translateMnemonic(words, English, CurrentSeedLanguage())
+}
+
+// CanonicalMnemonic converts words typed in the seed language to the English
+// words expected by aezeed.
+func CanonicalMnemonic(words []string) ([]string, error) {
+	return translateMnemonic(words, CurrentSeedLanguage(), English)
+}
+
+func translateMnemonic(words []string, from, to Language) ([]string, error) {
+	if from == to {
+		return words, nil
+	}
+	target := WordList(to)
+	out := make([]string, len(words))
+	for i, word := range words {
+		index, ok := WordIndex(from, word)
+		if !ok {
+			return nil, fmt.Errorf(T("onboard.wordlist_mismatch"), i+1, word, T("language."+string(from)))
+		}
+		out[i] = target[index]
+	}
+	return out, nil
+}
+
+// foldWord lower-cases word and strips the accents found in the supported
+// wordlists, so users may type them with or without diacritics.
+func foldWord(word string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(word)) {
+		switch r {
+		case 'á', 'à', 'â', 'ä':
+			r = 'a'
+		case 'é', 'è', 'ê', 'ë':
+			r = 'e'
+		case 'í', 'ì', 'î', 'ï':
+			r = 'i'
+		case 'ó', 'ò', 'ô', 'ö':
+			r = 'o'
+		case 'ú', 'ù', 'û', 'ü':
+			r = 'u'
+		case 'ñ':
+			r = 'n'
+		case 'ç':
+			r = 'c'
+		}
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
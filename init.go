@@ -0,0 +1,146 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/flokiorg/twallet/config"
+	"github.com/flokiorg/twallet/flnd"
+	. "github.com/flokiorg/twallet/utils"
+)
+
+// initCommand writes a first config file from a few questions, to the
+// --config path or where twallet looks for it by default.
+type initCommand struct {
+	Force bool `long:"force" description:"Overwrite an existing configuration file"`
+
+	opts *cliOptions
+}
+
+func (c *initCommand) Execute(args []string) error {
+	dirs := config.DefaultDirs()
+	path := c.opts.ConfigFile
+	if path == "" {
+		var err error
+		if path, err = defaultConfigPath(dirs); err != nil {
+			return err
+		}
+	}
+	if !config.IsINIFile(path) {
+		return fmt.Errorf("init writes INI config files, choose a path ending in .conf instead of %s", path)
+	}
+	if FileExists(path) && !c.Force {
+		return fmt.Errorf("%s already exists, run init with --force to overwrite it", path)
+	}
+
+	p := &prompter{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+	fmt.Fprintf(p.out, "Answer a few questions to write %s, Enter keeps the default in brackets.\n\n", path)
+
+	var answers config.InitAnswers
+	var err error
+	if answers.Network, err = p.choose("Network", "mainnet", flnd.NetworkNames()...); err != nil {
+		return err
+	}
+	if answers.Walletdir, err = p.ask("Wallet directory", dirs.Data, nil); err != nil {
+		return err
+	}
+	if answers.AddressType, err = p.choose("Address type", "segwit", "taproot", "segwit", "nested-segwit"); err != nil {
+		return err
+	}
+
+	params, err := flnd.NetworkByName(answers.Network)
+	if err != nil {
+		return err
+	}
+	port, _ := strconv.Atoi(params.DefaultPort)
+	peers, err := p.ask("Peers to add, comma separated", "", func(value string) error {
+		_, err := parsePeers(value, port)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	answers.Peers, _ = parsePeers(peers, port)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(config.Generate(answers)), 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(p.out, "\nWrote %s.\n", path)
+	if defaultPath, err := defaultConfigPath(dirs); err != nil || defaultPath != path {
+		fmt.Fprintf(p.out, "Start twallet with --config %s to use it.\n", path)
+	}
+	return nil
+}
+
+// parsePeers splits a comma separated list of peers, adding port to the
+// ones without.
+func parsePeers(value string, port int) ([]string, error) {
+	var peers []string
+	for _, peer := range strings.Split(value, ",") {
+		if strings.TrimSpace(peer) == "" {
+			continue
+		}
+		normalized, err := ValidateAndNormalizeURI(peer, port)
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, normalized)
+	}
+	return peers, nil
+}
+
+// prompter asks questions on the terminal, before the TUI starts.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask returns the answer to question, def when it is left empty. An answer
+// valid rejects is asked again.
+func (p *prompter) ask(question, def string, valid func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", question)
+		}
+		line, err := p.in.ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+			return "", err
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+		if valid != nil {
+			if err := valid(answer); err != nil {
+				fmt.Fprintf(p.out, "  %v\n", err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+func (p *prompter) choose(question, def string, choices ...string) (string, error) {
+	return p.ask(fmt.Sprintf("%s (%s)", question, strings.Join(choices, ", ")), def, func(answer string) error {
+		if !slices.Contains(choices, answer) {
+			return fmt.Errorf("choose one of %s", strings.Join(choices, ", "))
+		}
+		return nil
+	})
+}
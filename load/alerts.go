@@ -0,0 +1,88 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+const (
+	receiveCommandTimeout = 30 * time.Second
+	maxAlertedTxs         = 1024
+)
+
+// bellNotifier rings the terminal bell once per incoming transaction. The
+// bell goes through the event loop, so it is not written in the middle of a
+// draw.
+type bellNotifier struct {
+	app      *tview.Application
+	screen   tcell.Screen
+	incoming *incomingFilter
+}
+
+func newBellNotifier(app *tview.Application, screen tcell.Screen) *bellNotifier {
+	return &bellNotifier{app: app, screen: screen, incoming: newIncomingFilter()}
+}
+
+func (b *bellNotifier) Notify(ev *flnd.Update) {
+	if b.incoming.first(ev) == nil {
+		return
+	}
+	b.app.QueueUpdate(func() {
+		_ = b.screen.Beep()
+	})
+}
+
+// commandNotifier runs the configured hook once per incoming transaction.
+type commandNotifier struct {
+	command  string
+	logger   zerolog.Logger
+	incoming *incomingFilter
+}
+
+func newCommandNotifier(command string, logger zerolog.Logger) *commandNotifier {
+	return &commandNotifier{command: command, logger: logger, incoming: newIncomingFilter()}
+}
+
+func (c *commandNotifier) Notify(ev *flnd.Update) {
+	if tx := c.incoming.first(ev); tx != nil {
+		go c.run(tx)
+	}
+}
+
+func (c *commandNotifier) run(tx *lnrpc.Transaction) {
+	ctx, cancel := context.WithTimeout(context.Background(), receiveCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", c.command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", c.command)
+	}
+	cmd.Env = append(os.Environ(),
+		"TWALLET_TX_HASH="+tx.TxHash,
+		"TWALLET_TX_AMOUNT="+strconv.FormatInt(tx.Amount, 10),
+		"TWALLET_TX_CONFIRMATIONS="+strconv.FormatInt(int64(tx.NumConfirmations), 10),
+	)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		c.logger.Warn().Err(err).Bytes("output", out).Str("tx_hash", tx.TxHash).Msg("receive command failed")
+		return
+	}
+	c.logger.Debug().Str("tx_hash", tx.TxHash).Msg("receive command executed")
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/flokiorg/twallet/config"
+	"github.com/flokiorg/twallet/flnd"
+	"github.com/flokiorg/twallet/utils"
+)
+
+// bundleMaxLogBytes caps every log of a support bundle, keeping its end.
+const bundleMaxLogBytes = 5 << 20
+
+// bundleExcluded are the files a support bundle never takes, whoever asks
+// for them: the seed, macaroons, keys and the wallet itself.
+var bundleExcluded = []string{
+	"*.macaroon", "*.key", "*.pem", "*.db", "*seed*", "channel.backup", "*.bak",
+}
+
+// BundleEntry is a file of a support bundle, its content given or read from
+// Path.
+type BundleEntry struct {
+	Name string
+	Data []byte
+	Path string
+}
+
+// excludedFromBundle reports whether name is one of the files a support
+// bundle leaves out.
+func excludedFromBundle(name string) bool {
+	base := strings.ToLower(path.Base(filepath.ToSlash(name)))
+	for _, pattern := range bundleExcluded {
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportBundle collects what a bug report needs: the version, the
+// configuration with its secrets hidden, the state of the sync, the peers
+// and the end of the logs at logPaths. The daemon being down only leaves
+// its part as the error that kept it out.
+func (l *Load) SupportBundle(ctx context.Context, logPaths []string) []BundleEntry {
+	entries := []BundleEntry{
+		{Name: "version.txt", Data: []byte(utils.ReadBuildInfo().String())},
+	}
+
+	var cfg bytes.Buffer
+	if err := config.WriteEffective(&cfg, l.AppConfig); err != nil {
+		fmt.Fprintf(&cfg, "; unavailable: %v\n", err)
+	}
+	entries = append(entries, BundleEntry{Name: "config.txt", Data: cfg.Bytes()})
+
+	status, _ := json.MarshalIndent(l.bundleStatus(ctx), "", "  ")
+	entries = append(entries, BundleEntry{Name: "status.json", Data: append(status, '\n')})
+	entries = append(entries, BundleEntry{Name: "peers.txt", Data: l.bundlePeers(ctx)})
+
+	for _, p := range logPaths {
+		if p == "" {
+			continue
+		}
+		if _, err := os.Stat(p); err == nil {
+			entries = append(entries, BundleEntry{Name: "logs/" + filepath.Base(p), Path: p})
+		}
+	}
+	return entries
+}
+
+type bundleStatus struct {
+	State          flnd.Status `json:"state"`
+	Network        string      `json:"network"`
+	Synced         bool        `json:"synced"`
+	HeaderRecent   bool        `json:"header_recent"`
+	BlockHeight    uint32      `json:"block_height"`
+	ChainPeers     int         `json:"chain_peers"`
+	LightningPeers int         `json:"lightning_peers"`
+	Errors         []string    `json:"errors,omitempty"`
+}
+
+func (l *Load) bundleStatus(ctx context.Context) *bundleStatus {
+	status := &bundleStatus{State: l.Wallet.Status()}
+	if l.AppConfig.Network != nil {
+		status.Network = l.AppConfig.Network.Name
+	}
+	var err error
+	if status.Synced, status.HeaderRecent, status.BlockHeight, err = l.Wallet.IsSynced(); err != nil {
+		status.Errors = append(status.Errors, "sync: "+err.Error())
+	}
+	if info, err := l.Wallet.NetworkInfo(ctx); err != nil {
+		status.Errors = append(status.Errors, "network: "+err.Error())
+	} else {
+		status.ChainPeers, status.LightningPeers = info.ChainPeers, info.LightningPeers
+	}
+	return status
+}
+
+func (l *Load) bundlePeers(ctx context.Context) []byte {
+	var b bytes.Buffer
+	b.WriteString("Chain peers:\n")
+	if addrs, err := l.Wallet.ChainPeerAddresses(ctx); err != nil {
+		fmt.Fprintf(&b, "  unavailable: %v\n", err)
+	} else {
+		for _, addr := range addrs {
+			fmt.Fprintf(&b, "  %s\n", addr)
+		}
+	}
+	b.WriteString("\nChannel peers:\n")
+	if peers, err := l.Wallet.ChannelPeers(ctx); err != nil {
+		fmt.Fprintf(&b, "  unavailable: %v\n", err)
+	} else {
+		for _, peer := range peers {
+			state := "offline"
+			if peer.Online {
+				state = "online"
+			}
+			fmt.Fprintf(&b, "  %s %s %s\n", peer.PubKey, state, peer.Alias)
+		}
+	}
+	return b.Bytes()
+}
+
+// WriteSupportBundle writes entries to out as a tar.gz archive under a
+// directory named after now, with a manifest of what it holds. The files
+// read from disk are capped to their last bundleMaxLogBytes, and those
+// excludedFromBundle are left out whatever the entries say.
+func WriteSupportBundle(out io.Writer, entries []BundleEntry, now time.Time) error {
+	dir := "twallet-support-" + now.Format("20060102-150405")
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	var manifest strings.Builder
+	fmt.Fprintf(&manifest, "twallet support bundle, %s\n\n", now.Format(time.RFC3339))
+	add := func(name string, data []byte) error {
+		err := tw.WriteHeader(&tar.Header{
+			Name:    dir + "/" + name,
+			Mode:    0o600,
+			Size:    int64(len(data)),
+			ModTime: now,
+		})
+		if err == nil {
+			_, err = tw.Write(data)
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		if excludedFromBundle(entry.Name) || (entry.Path != "" && excludedFromBundle(entry.Path)) {
+			fmt.Fprintf(&manifest, "%s left out\n", entry.Name)
+			continue
+		}
+		data := entry.Data
+		if entry.Path != "" {
+			var err error
+			if data, err = readFileTail(entry.Path, bundleMaxLogBytes); err != nil {
+				fmt.Fprintf(&manifest, "%s unreadable: %v\n", entry.Name, err)
+				continue
+			}
+		}
+		if err := add(entry.Name, data); err != nil {
+			return err
+		}
+		fmt.Fprintf(&manifest, "%s %d bytes\n", entry.Name, len(data))
+	}
+	manifest.WriteString("\nThe seed, macaroons, keys and wallet files are never included, nor the values of the secret options.\n")
+
+	if err := add("manifest.txt", []byte(manifest.String())); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readFileTail returns the last limit bytes of the file at p, from the start
+// of a line.
+func readFileTail(p string, limit int64) ([]byte, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() <= limit {
+		return io.ReadAll(f)
+	}
+	if _, err := f.Seek(info.Size()-limit, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		data = data[i+1:]
+	}
+	return data, nil
+}
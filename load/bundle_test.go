@@ -0,0 +1,105 @@
+package load
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteSupportBundle(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "flnd.log")
+	if err := os.WriteFile(logPath, []byte(strings.Repeat("old line\n", 10)+"last line\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	macaroon := filepath.Join(dir, "admin.macaroon")
+	if err := os.WriteFile(macaroon, []byte("macaroon-bytes"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []BundleEntry{
+		{Name: "version.txt", Data: []byte("Version: test\n")},
+		{Name: "logs/flnd.log", Path: logPath},
+		{Name: "logs/admin.macaroon", Path: macaroon},
+		{Name: "logs/renamed.log", Path: macaroon},
+		{Name: "seed.txt", Data: []byte("abandon abandon")},
+	}
+	var out bytes.Buffer
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	if err := WriteSupportBundle(&out, entries, now); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readBundle(t, &out)
+	want := []string{"version.txt", "logs/flnd.log", "manifest.txt"}
+	if len(files) != len(want) {
+		t.Fatalf("bundle holds %v, want %v", keys(files), want)
+	}
+	for _, name := range want {
+		if _, ok := files["twallet-support-20240102-150405/"+name]; !ok {
+			t.Fatalf("%s missing from %v", name, keys(files))
+		}
+	}
+	for name, data := range files {
+		if strings.Contains(data, "macaroon-bytes") || strings.Contains(data, "abandon") {
+			t.Fatalf("%s holds an excluded file", name)
+		}
+	}
+	manifest := files["twallet-support-20240102-150405/manifest.txt"]
+	if !strings.Contains(manifest, "logs/admin.macaroon left out") || !strings.Contains(manifest, "seed.txt left out") {
+		t.Fatalf("unexpected manifest:\n%s", manifest)
+	}
+}
+
+func TestReadFileTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "twallet.log")
+	if err := os.WriteFile(path, []byte("first line\nsecond line\nthird\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := readFileTail(path, 14)
+	if err != nil || string(data) != "third\n" {
+		t.Fatalf("readFileTail = %q, %v", data, err)
+	}
+	data, err = readFileTail(path, 100)
+	if err != nil || string(data) != "first line\nsecond line\nthird\n" {
+		t.Fatalf("readFileTail of a small file = %q, %v", data, err)
+	}
+}
+
+func readBundle(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[header.Name] = string(data)
+	}
+}
+
+func keys(m map[string]string) []string {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/go-flokicoin/chainutil"
+
+	"github.com/flokiorg/twallet/flnd"
+	. "github.com/flokiorg/twallet/shared"
+)
+
+const confirmAlertRetryDelay = 30 * time.Second
+
+// ConfirmationEvent is the JSON body posted to the confirmation webhook.
+type ConfirmationEvent struct {
+	Txid          string `json:"txid"`
+	Confirmations uint32 `json:"confirmations"`
+	BlockHeight   uint32 `json:"block_height"`
+	Amount        int64  `json:"amount"`
+	Label         string `json:"label,omitempty"`
+}
+
+// ConfirmationAlerts notifies once a transaction reaches a number of
+// confirmations. Alerts live as long as the app runs.
+type ConfirmationAlerts struct {
+	l    *Load
+	hook *webhook
+
+	mu      sync.Mutex
+	pending map[string]*confirmationAlert
+}
+
+type confirmationAlert struct {
+	target uint32
+	cancel context.CancelFunc
+}
+
+func newConfirmationAlerts(l *Load, webhookURL string, transport http.RoundTripper) *ConfirmationAlerts {
+	return &ConfirmationAlerts{
+		l:       l,
+		hook:    newWebhook(webhookURL, transport),
+		pending: make(map[string]*confirmationAlert),
+	}
+}
+
+// Target returns the confirmation count an alert waits for on txid.
+func (a *ConfirmationAlerts) Target(txid string) (uint32, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if alert, ok := a.pending[txid]; ok {
+		return alert.target, true
+	}
+	return 0, false
+}
+
+// Add alerts when tx reaches target confirmations, replacing an alert already
+// set on it.
+func (a *ConfirmationAlerts) Add(tx *lnrpc.Transaction, target uint32) error {
+	if tx == nil {
+		return errors.New("no transaction selected")
+	}
+	if target == 0 {
+		return errors.New("confirmations must be above zero")
+	}
+	script, err := alertScript(tx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	a.mu.Lock()
+	if previous, ok := a.pending[tx.TxHash]; ok {
+		previous.cancel()
+	}
+	alert := &confirmationAlert{target: target, cancel: cancel}
+	a.pending[tx.TxHash] = alert
+	a.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-a.l.Notif.stop:
+			cancel()
+		}
+	}()
+
+	go a.wait(ctx, alert, tx, script)
+	return nil
+}
+
+// Cancel drops the alert set on txid.
+func (a *ConfirmationAlerts) Cancel(txid string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if alert, ok := a.pending[txid]; ok {
+		alert.cancel()
+		delete(a.pending, txid)
+	}
+}
+
+func alertScript(tx *lnrpc.Transaction) ([]byte, error) {
+	for _, detail := range tx.GetOutputDetails() {
+		if detail == nil || detail.PkScript == "" {
+			continue
+		}
+		return hex.DecodeString(detail.PkScript)
+	}
+	return nil, errors.New("transaction has no output script to follow")
+}
+
+func (a *ConfirmationAlerts) wait(ctx context.Context, alert *confirmationAlert, tx *lnrpc.Transaction, script []byte) {
+	heightHint := uint32(0)
+	if tx.BlockHeight > 0 {
+		heightHint = uint32(tx.BlockHeight)
+	} else if tip := a.l.Cache.GetTipHeight(); tip > 0 {
+		heightHint = uint32(tip)
+	}
+
+	for {
+		blockHeight, err := a.l.Wallet.WaitForConfirmations(ctx, tx.TxHash, script, alert.target, heightHint)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			a.fire(alert, tx, blockHeight)
+			return
+		}
+
+		if !errors.Is(err, flnd.ErrDaemonNotRunning) {
+			a.l.Logger.Warn().Err(err).Str("tx_hash", tx.TxHash).Msg("confirmation alert interrupted")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(confirmAlertRetryDelay):
+		}
+	}
+}
+
+func (a *ConfirmationAlerts) fire(alert *confirmationAlert, tx *lnrpc.Transaction, blockHeight uint32) {
+	a.mu.Lock()
+	if a.pending[tx.TxHash] != alert {
+		a.mu.Unlock()
+		return
+	}
+	delete(a.pending, tx.TxHash)
+	a.mu.Unlock()
+	alert.cancel()
+
+	a.l.Notif.ShowToastWithTimeout(fmt.Sprintf("[green:-:-]✅ %s reached %d confirmations[-:-:-] (%s)",
+		shortHash(tx.TxHash), alert.target, FormatAmountView(chainutil.Amount(tx.Amount), 8)), time.Minute)
+
+	if a.hook == nil {
+		return
+	}
+	event := ConfirmationEvent{
+		Txid:          tx.TxHash,
+		Confirmations: alert.target,
+		BlockHeight:   blockHeight,
+		Amount:        tx.Amount,
+		Label:         tx.Label,
+	}
+	if err := a.hook.post(event); err != nil {
+		a.l.Logger.Warn().Err(err).Str("tx_hash", tx.TxHash).Msg("confirmation webhook failed")
+		a.l.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] confirmation webhook: %s", err.Error()), time.Second*20)
+	}
+}
+
+func shortHash(hash string) string {
+	if len(hash) <= 16 {
+		return hash
+	}
+	return hash[:8] + "…" + hash[len(hash)-8:]
+}
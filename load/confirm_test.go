@@ -0,0 +1,42 @@
+package load
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConfirmationWebhook(t *testing.T) {
+	var got ConfirmationEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	alerts := newConfirmationAlerts(nil, srv.URL, nil)
+	want := ConfirmationEvent{Txid: "ab", Confirmations: 6, BlockHeight: 100, Amount: 5000, Label: "rent"}
+	if err := alerts.hook.post(want); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if got != want {
+		t.Fatalf("webhook received %+v, want %+v", got, want)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	if err := newConfirmationAlerts(nil, failing.URL, nil).hook.post(want); err == nil {
+		t.Fatal("expected an error for a failing webhook")
+	}
+}
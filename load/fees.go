@@ -0,0 +1,148 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	feePollInterval      = 2 * time.Minute
+	feeRequestTimeout    = 15 * time.Second
+	maxFeeResponseLength = 1 << 16
+	feeTrendLength       = 30
+)
+
+// FeeEstimates is the response of the recommended fees API, in loki/vB.
+type FeeEstimates struct {
+	FastestFee  float64 `json:"fastestFee"`
+	HalfHourFee float64 `json:"halfHourFee"`
+	HourFee     float64 `json:"hourFee"`
+	EconomyFee  float64 `json:"economyFee"`
+	MinimumFee  float64 `json:"minimumFee"`
+}
+
+// FeeMonitor follows the next block fee rate of the configured fee API for
+// the footer trend and the fee_spike alert rule.
+type FeeMonitor struct {
+	l         *Load
+	url       string
+	threshold float64
+	client    *http.Client
+
+	mu    sync.Mutex
+	trend []float64
+}
+
+func newFeeMonitor(l *Load, url string, threshold float64) *FeeMonitor {
+	return &FeeMonitor{
+		l:         l,
+		url:       url,
+		threshold: threshold,
+		client:    &http.Client{Timeout: feeRequestTimeout},
+	}
+}
+
+// Trend returns the sampled fee rates, oldest first.
+func (m *FeeMonitor) Trend() []float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]float64(nil), m.trend...)
+}
+
+// Congestion returns the latest fee rate and whether it reached the
+// threshold. The rate is 0 until the first sample.
+func (m *FeeMonitor) Congestion() (float64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.trend) == 0 {
+		return 0, false
+	}
+	rate := m.trend[len(m.trend)-1]
+	return rate, rate >= m.threshold
+}
+
+// record adds a sample to the trend.
+func (m *FeeMonitor) record(rate float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.trend = append(m.trend, rate)
+	if len(m.trend) > feeTrendLength {
+		m.trend = m.trend[len(m.trend)-feeTrendLength:]
+	}
+}
+
+// SetURL makes the next polls read the fee API at url.
+func (m *FeeMonitor) SetURL(url string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.url = url
+}
+
+func (m *FeeMonitor) fetch(ctx context.Context) (*FeeEstimates, error) {
+	m.mu.Lock()
+	url := m.url
+	m.mu.Unlock()
+	return FetchFeeEstimates(ctx, m.client, url)
+}
+
+// FetchFeeEstimates reads the recommended fees API at url.
+func FetchFeeEstimates(ctx context.Context, client *http.Client, url string) (*FeeEstimates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fee API returned %s", resp.Status)
+	}
+
+	var estimates FeeEstimates
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxFeeResponseLength)).Decode(&estimates); err != nil {
+		return nil, fmt.Errorf("invalid fee response: %w", err)
+	}
+	return &estimates, nil
+}
+
+func (m *FeeMonitor) run() {
+	ticker := time.NewTicker(feePollInterval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), feeRequestTimeout)
+		estimates, err := m.fetch(ctx)
+		cancel()
+		if err != nil {
+			m.l.Logger.Warn().Err(err).Msg("unable to fetch fee estimates")
+		} else {
+			m.check(estimates.FastestFee)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-m.l.Notif.stop:
+			return
+		}
+	}
+}
+
+func (m *FeeMonitor) check(rate float64) {
+	m.record(rate)
+	m.l.Alerts.Observe(MetricFeeRate, rate)
+}
@@ -0,0 +1,47 @@
+package load
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFeeMonitorRecord(t *testing.T) {
+	m := newFeeMonitor(nil, "", 20)
+
+	if rate, congested := m.Congestion(); rate != 0 || congested {
+		t.Errorf("Congestion before a sample = %v, %v", rate, congested)
+	}
+	m.record(35)
+	if rate, congested := m.Congestion(); rate != 35 || !congested {
+		t.Errorf("Congestion = %v, %v", rate, congested)
+	}
+	m.record(8)
+	if rate, congested := m.Congestion(); rate != 8 || congested {
+		t.Errorf("Congestion = %v, %v", rate, congested)
+	}
+
+	for i := 0; i < feeTrendLength+5; i++ {
+		m.record(float64(i))
+	}
+	trend := m.Trend()
+	if len(trend) != feeTrendLength || trend[len(trend)-1] != feeTrendLength+4 {
+		t.Errorf("Trend kept %d samples ending at %v", len(trend), trend[len(trend)-1])
+	}
+}
+
+func TestFeeMonitorFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"fastestFee":42,"halfHourFee":30,"hourFee":12,"economyFee":2,"minimumFee":1}`))
+	}))
+	defer srv.Close()
+
+	estimates, err := newFeeMonitor(nil, srv.URL, 20).fetch(context.Background())
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if estimates.FastestFee != 42 || estimates.HourFee != 12 {
+		t.Errorf("fetch = %+v", estimates)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultNotificationHistorySize = 200
+
+type NotificationSeverity int
+
+const (
+	SeverityInfo NotificationSeverity = iota
+	SeveritySuccess
+	SeverityWarning
+	SeverityError
+)
+
+func (s NotificationSeverity) String() string {
+	switch s {
+	case SeveritySuccess:
+		return "success"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+type NotificationRecord struct {
+	Time     time.Time
+	Severity NotificationSeverity
+	Message  string
+}
+
+// notificationHistory is a fixed size ring buffer of the most recent toasts.
+type notificationHistory struct {
+	mu      sync.Mutex
+	entries []NotificationRecord
+	next    int
+	full    bool
+}
+
+func newNotificationHistory(size int) *notificationHistory {
+	if size <= 0 {
+		size = defaultNotificationHistorySize
+	}
+	return &notificationHistory{entries: make([]NotificationRecord, size)}
+}
+
+func (h *notificationHistory) add(rec NotificationRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries[h.next] = rec
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// snapshot returns the recorded entries, newest first.
+func (h *notificationHistory) snapshot() []NotificationRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	count := h.next
+	if h.full {
+		count = len(h.entries)
+	}
+
+	out := make([]NotificationRecord, 0, count)
+	for i := 1; i <= count; i++ {
+		idx := (h.next - i + len(h.entries)) % len(h.entries)
+		out = append(out, h.entries[idx])
+	}
+	return out
+}
+
+func (h *notificationHistory) clear() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clear(h.entries)
+	h.next = 0
+	h.full = false
+}
+
+// severityFromText infers the severity of a toast from the color tag and
+// emoji conventions used across the pages.
+func severityFromText(text string) NotificationSeverity {
+	lower := strings.ToLower(text)
+	switch {
+	case strings.HasPrefix(lower, "[red"), strings.Contains(lower, "error:"):
+		return SeverityError
+	case strings.HasPrefix(lower, "[yellow"), strings.Contains(lower, "warning:"):
+		return SeverityWarning
+	case strings.HasPrefix(lower, "[green"), strings.HasPrefix(text, "✅"):
+		return SeveritySuccess
+	default:
+		return SeverityInfo
+	}
+}
+
+func (n *notification) record(severity NotificationSeverity, text string) {
+	if n.history == nil || strings.TrimSpace(text) == "" {
+		return
+	}
+	n.history.add(NotificationRecord{
+		Time:     time.Now(),
+		Severity: severity,
+		Message:  text,
+	})
+}
+
+// History returns the most recent notifications, newest first.
+func (n *notification) History() []NotificationRecord {
+	if n.history == nil {
+		return nil
+	}
+	return n.history.snapshot()
+}
+
+func (n *notification) ClearHistory() {
+	if n.history != nil {
+		n.history.clear()
+	}
+}
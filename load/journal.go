@@ -0,0 +1,255 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/rs/zerolog"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+const (
+	journalFileName = "journal.jsonl"
+	maxJournalLine  = 64 * 1024
+)
+
+type JournalKind string
+
+const (
+	JournalSend       JournalKind = "send"
+	JournalReceive    JournalKind = "receive"
+	JournalFeeBump    JournalKind = "fee_bump"
+	JournalUnlock     JournalKind = "unlock"
+	JournalLock       JournalKind = "lock"
+	JournalRescan     JournalKind = "rescan"
+	JournalDaemonDown JournalKind = "daemon_down"
+	JournalRestart    JournalKind = "daemon_restart"
+)
+
+// JournalEntry is one line of the event journal. Amounts are in loki,
+// negative when funds leave the wallet.
+type JournalEntry struct {
+	Time    time.Time   `json:"time"`
+	Kind    JournalKind `json:"kind"`
+	Txid    string      `json:"txid,omitempty"`
+	Address string      `json:"address,omitempty"`
+	Amount  int64       `json:"amount,omitempty"`
+	Fee     int64       `json:"fee,omitempty"`
+	Message string      `json:"message,omitempty"`
+}
+
+// Journal is an append-only record of what the wallet did, one JSON object
+// per line, kept apart from the daemon log for auditing.
+type Journal struct {
+	path   string
+	logger zerolog.Logger
+
+	mu sync.Mutex
+}
+
+// JournalPath returns where the journal of network is kept in the metadata
+// directory.
+func JournalPath(metadataDir, network string) string {
+	return flnd.MetadataPath(metadataDir, network, journalFileName)
+}
+
+// legacyJournalPath is where the journal of network was kept before the
+// metadata directory, under walletDir.
+func legacyJournalPath(walletDir, network string) string {
+	return filepath.Join(walletDir, "journal", network, journalFileName)
+}
+
+// moveLegacyJournal moves the journal at legacy to path, unless a journal
+// is there already.
+func moveLegacyJournal(legacy, path string) error {
+	if legacy == path {
+		return nil
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if _, err := os.Stat(legacy); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.Rename(legacy, path)
+}
+
+func NewJournal(path string, logger zerolog.Logger) *Journal {
+	return &Journal{path: path, logger: logger}
+}
+
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Record appends entry, stamping it with the current time when unset.
+// Failures are logged, the journal never interrupts the wallet.
+func (j *Journal) Record(entry JournalEntry) {
+	if j == nil {
+		return
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	entry.Time = entry.Time.UTC()
+
+	if err := j.append(entry); err != nil {
+		j.logger.Warn().Err(err).Str("kind", string(entry.Kind)).Msg("failed to write journal entry")
+	}
+}
+
+func (j *Journal) append(entry JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Entries returns up to limit of the latest entries, newest first. Lines that
+// cannot be parsed are skipped. A missing journal has no entries.
+func (j *Journal) Entries(limit int) ([]JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 4096), maxJournalLine)
+	for scanner.Scan() {
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Kind == "" {
+			continue
+		}
+		entries = append(entries, entry)
+		if limit > 0 && len(entries) > limit {
+			entries = entries[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, k := 0, len(entries)-1; i < k; i, k = i+1, k-1 {
+		entries[i], entries[k] = entries[k], entries[i]
+	}
+	return entries, nil
+}
+
+// journalTracker is the notifier writing service updates to the journal.
+// The service repeats states, so only transitions are written.
+type journalTracker struct {
+	journal  *Journal
+	incoming *incomingFilter
+
+	mu       sync.Mutex
+	state    flnd.Status
+	unlocked bool
+}
+
+func newJournalTracker(journal *Journal) *journalTracker {
+	return &journalTracker{
+		journal:  journal,
+		incoming: newIncomingFilter(),
+	}
+}
+
+func (t *journalTracker) Notify(ev *flnd.Update) {
+	if ev == nil {
+		return
+	}
+	if tx := t.incoming.first(ev); tx != nil {
+		t.receive(tx)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous := t.state
+	t.state = ev.State
+
+	switch ev.State {
+	case flnd.StatusNone:
+		// None is reported each time the daemon is launched; the first
+		// update replays the current state and none after init is the boot.
+		if previous != "" && previous != flnd.StatusInit && previous != flnd.StatusNone {
+			t.unlocked = false
+			t.journal.Record(JournalEntry{Kind: JournalRestart})
+		}
+	case flnd.StatusDown:
+		t.unlocked = false
+		if previous != flnd.StatusDown {
+			entry := JournalEntry{Kind: JournalDaemonDown}
+			if ev.Err != nil {
+				entry.Message = ev.Err.Error()
+			}
+			t.journal.Record(entry)
+		}
+	case flnd.StatusFailedPermanently:
+		t.unlocked = false
+		entry := JournalEntry{Kind: JournalDaemonDown, Message: "gave up restarting"}
+		if ev.Err != nil {
+			entry.Message += ": " + ev.Err.Error()
+		}
+		t.journal.Record(entry)
+	case flnd.StatusUnlocked:
+		if !t.unlocked {
+			t.unlocked = true
+			t.journal.Record(JournalEntry{Kind: JournalUnlock})
+		}
+	case flnd.StatusLocked:
+		if t.unlocked {
+			t.unlocked = false
+			t.journal.Record(JournalEntry{Kind: JournalLock})
+		}
+	}
+}
+
+func (t *journalTracker) receive(tx *lnrpc.Transaction) {
+	entry := JournalEntry{Kind: JournalReceive, Txid: tx.TxHash, Amount: tx.Amount}
+	for _, detail := range tx.GetOutputDetails() {
+		if detail != nil && detail.IsOurAddress && detail.Address != "" {
+			entry.Address = detail.Address
+			break
+		}
+	}
+	t.journal.Record(entry)
+}
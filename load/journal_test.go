@@ -0,0 +1,119 @@
+package load
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/rs/zerolog"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+func TestJournalEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal", "test", journalFileName)
+	j := NewJournal(path, zerolog.Nop())
+
+	entries, err := j.Entries(0)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Entries on a missing journal = %v, %v", entries, err)
+	}
+
+	j.Record(JournalEntry{Kind: JournalUnlock})
+	j.Record(JournalEntry{Kind: JournalSend, Txid: "aa", Amount: -500, Fee: 10})
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("not json\n")
+	f.Close()
+
+	j.Record(JournalEntry{Kind: JournalLock})
+
+	entries, err = j.Entries(0)
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	kinds := []JournalKind{JournalLock, JournalSend, JournalUnlock}
+	if len(entries) != len(kinds) {
+		t.Fatalf("Entries returned %d entries, want %d", len(entries), len(kinds))
+	}
+	for i, kind := range kinds {
+		if entries[i].Kind != kind {
+			t.Errorf("entry %d kind = %s, want %s", i, entries[i].Kind, kind)
+		}
+	}
+	if entries[1].Txid != "aa" || entries[1].Amount != -500 || entries[1].Fee != 10 {
+		t.Errorf("send entry = %+v", entries[1])
+	}
+
+	entries, _ = j.Entries(1)
+	if len(entries) != 1 || entries[0].Kind != JournalLock {
+		t.Errorf("Entries(1) = %+v", entries)
+	}
+}
+
+func TestJournalTracker(t *testing.T) {
+	j := NewJournal(filepath.Join(t.TempDir(), journalFileName), zerolog.Nop())
+	tracker := newJournalTracker(j)
+
+	tx := &lnrpc.Transaction{TxHash: "bb", Amount: 1000}
+	for _, u := range []*flnd.Update{
+		{State: flnd.StatusInit},
+		{State: flnd.StatusNone},
+		{State: flnd.StatusLocked},
+		{State: flnd.StatusUnlocked},
+		{State: flnd.StatusReady},
+		{State: flnd.StatusTransaction, Transaction: tx},
+		{State: flnd.StatusTransaction, Transaction: tx},
+		{State: flnd.StatusDown},
+		{State: flnd.StatusDown},
+		{State: flnd.StatusNone},
+		{State: flnd.StatusLocked},
+	} {
+		tracker.Notify(u)
+	}
+
+	entries, err := j.Entries(0)
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	kinds := []JournalKind{JournalRestart, JournalDaemonDown, JournalReceive, JournalUnlock}
+	if len(entries) != len(kinds) {
+		t.Fatalf("tracker wrote %+v", entries)
+	}
+	for i, kind := range kinds {
+		if entries[i].Kind != kind {
+			t.Errorf("entry %d kind = %s, want %s", i, entries[i].Kind, kind)
+		}
+	}
+}
+
+func TestMoveLegacyJournal(t *testing.T) {
+	dir := t.TempDir()
+	legacy := legacyJournalPath(dir, "testnet3")
+	NewJournal(legacy, zerolog.Nop()).Record(JournalEntry{Kind: JournalUnlock})
+
+	path := JournalPath(filepath.Join(dir, "metadata"), "testnet3")
+	if err := moveLegacyJournal(legacy, path); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := NewJournal(path, zerolog.Nop()).Entries(0)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("moved journal entries = %v, %v", entries, err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Fatalf("legacy journal left: %v", err)
+	}
+
+	// A journal already in the metadata directory is kept.
+	NewJournal(legacy, zerolog.Nop()).Record(JournalEntry{Kind: JournalLock})
+	if err := moveLegacyJournal(legacy, path); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := NewJournal(path, zerolog.Nop()).Entries(0); len(entries) != 1 || entries[0].Kind != JournalUnlock {
+		t.Fatalf("journal replaced: %+v", entries)
+	}
+}
@@ -52,11 +52,20 @@ type Load struct {
 	Nav       *Navigator
 	Notif     *notification
 	Wallet    *flnd.Service
+	Price     PriceProvider
+	Watch     *AddressWatcher
+	Confirm   *ConfirmationAlerts
+	Journal   *Journal
+	Fees      *FeeMonitor
+	Alerts    *AlertEngine
 	Logger    zerolog.Logger
 	AppConfig *config.AppConfig
+
+	// screen is the screen tapp runs on, nil when it is not known.
+	screen tcell.Screen
 }
 
-func NewLoad(cfg *config.AppConfig, flnsvc *flnd.Service, tapp *tview.Application, pages *tview.Pages) *Load {
+func NewLoad(cfg *config.AppConfig, flnsvc *flnd.Service, tapp *tview.Application, screen tcell.Screen, pages *tview.Pages) *Load {
 	logger := NamedLogger("load")
 
 	l := &Load{
@@ -66,15 +75,45 @@ func NewLoad(cfg *config.AppConfig, flnsvc *flnd.Service, tapp *tview.Applicatio
 		Logger:      logger,
 		AppConfig:   cfg,
 		Cache:       &Cache{},
+		screen:      screen,
 	}
 
+	networkName := "unknown"
+	if cfg.Network != nil {
+		networkName = cfg.Network.Name
+	}
+	journalPath := JournalPath(cfg.MetadataDir, flnd.NetworkNameOf(cfg.Network))
+	if err := moveLegacyJournal(legacyJournalPath(cfg.Walletdir, networkName), journalPath); err != nil {
+		logger.Warn().Err(err).Msg("failed to move the journal to the metadata directory")
+	}
+	l.Journal = NewJournal(journalPath, NamedLogger("journal"))
+
 	l.Notif = newNotification(flnsvc, l.Cache, NamedLogger("notification"))
+	l.registerNotifiers()
+
+	l.Watch = newAddressWatcher(l)
+	l.Confirm = newConfirmationAlerts(l, cfg.ConfirmWebhook, cfg.HTTPTransport())
+
+	l.Alerts = newAlertEngine(l)
+	l.loadAlertRules()
+	if cfg.PeerOfflineAlert > 0 {
+		l.startPeerMonitor(cfg.PeerOfflineAlert)
+	}
+	if cfg.FeeRateAlert > 0 && cfg.Feeurl != "" {
+		l.Fees = newFeeMonitor(l, flnsvc.FeeURL(), cfg.FeeRateAlert)
+		go l.Fees.run()
+	}
+
+	if !cfg.NoFiat && cfg.FiatURL != "" {
+		l.Price = NewHTTPPriceProvider(cfg.FiatURL, cfg.FiatField, cfg.FiatCurrency, cfg.FiatCacheTTL, cfg.HTTPTransport())
+		l.startPriceUpdates(cfg.FiatCacheTTL)
+	}
 
 	l.Application.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		if event.Key() != tcell.KeyESC {
 			return event
 		}
-		l.Notif.CancelToast()
+		l.Notif.DismissToasts()
 		l.Application.SetFocus(pages)
 		return event
 	})
@@ -87,7 +126,7 @@ func (l *Load) RegisterRouter(r Router) {
 }
 
 type notification struct {
-	toast chan string
+	toasts *toastQueue
 
 	mu     sync.Mutex
 	subs   []chan *NotificationEvent
@@ -98,6 +137,10 @@ type notification struct {
 	lnHealth    <-chan *flnd.Update
 	wallet      *flnd.Service
 	cache       *Cache
+	history     *notificationHistory
+	warnings    *warningBoard
+
+	notifiers []Notifier
 }
 
 type NotificationEvent struct {
@@ -112,6 +155,11 @@ type NotificationEvent struct {
 type RecoveryStatus struct {
 	Info      *lnrpc.GetRecoveryInfoResponse
 	UTXOCount int
+	// TipHeight is the chain tip the recovery scans up to, 0 when unknown.
+	TipHeight uint32
+	// Estimate is the recovery speed from the previous samples, nil until
+	// the progress moved.
+	Estimate *RecoveryEstimate
 }
 
 func (n *notification) Subscribe() (<-chan *NotificationEvent, func()) {
@@ -141,12 +189,14 @@ func (n *notification) Subscribe() (<-chan *NotificationEvent, func()) {
 
 func newNotification(flnsvc *flnd.Service, cache *Cache, logger zerolog.Logger) *notification {
 	n := &notification{
-		toast:       make(chan string, 5),
+		toasts:      newToastQueue(maxToasts),
 		subs:        make([]chan *NotificationEvent, 0),
 		stop:        make(chan struct{}),
 		logger:      logger,
 		cache:       cache,
 		healthState: make(chan HealthState),
+		history:     newNotificationHistory(defaultNotificationHistorySize),
+		warnings:    newWarningBoard(),
 	}
 
 	n.lnHealth = flnsvc.Subscribe()
@@ -180,14 +230,28 @@ func (l *Load) BroadcastBalanceRefresh() {
 	l.Notif.BroadcastBalanceRefresh()
 }
 
+// UpdateBalance stores the latest balance and feeds the confirmed balance to
+// the alert rules.
+func (l *Load) UpdateBalance(confirmed, unconfirmed, locked chainutil.Amount) {
+	l.Cache.SetBalance(confirmed, unconfirmed, locked)
+	l.Alerts.Observe(MetricBalance, float64(confirmed))
+}
+
 func (n *notification) listen() {
 
+	var lastSeq uint64
 	for {
 		select {
 		case ev, ok := <-n.lnHealth:
 			if !ok {
 				return
 			}
+			if lastSeq > 0 && ev.Seq > lastSeq+1 {
+				n.replayMissed(lastSeq, ev.Seq)
+			}
+			if ev.Seq > lastSeq {
+				lastSeq = ev.Seq
+			}
 			n.ProcessEvent(ev)
 
 		case <-n.stop:
@@ -196,6 +260,21 @@ func (n *notification) listen() {
 	}
 }
 
+// replayMissed processes the updates between after and before that were
+// dropped while the listener was behind.
+func (n *notification) replayMissed(after, before uint64) {
+	missed, complete := n.wallet.EventsSince(after)
+	if !complete {
+		n.logger.Warn().Uint64("after", after).Msg("some wallet updates were lost")
+	}
+	for _, ev := range missed {
+		if ev.Seq >= before {
+			break
+		}
+		n.ProcessEvent(ev)
+	}
+}
+
 func (n *notification) ProcessEvent(ev *flnd.Update) {
 
 	event := &NotificationEvent{
@@ -204,11 +283,20 @@ func (n *notification) ProcessEvent(ev *flnd.Update) {
 		Err:         ev.Err,
 	}
 
+	n.trackReorg(ev)
+	n.dispatch(ev)
+
 	switch ev.State {
 	case flnd.StatusDown:
 		n.reportHealth(HealthState{Level: HealthRed, Info: "disconnected", Err: ev.Err})
 		n.BroadcastWalletUpdate(event)
 
+	case flnd.StatusFailedPermanently:
+		n.logger.Error().Err(ev.Err).Msg("wallet daemon failed to start, giving up")
+		n.reportHealth(HealthState{Level: HealthRed, Info: "failed", Err: ev.Err})
+		n.ShowToastWithTimeout(FailedPermanentlyGuidance(ev.Err), time.Hour)
+		n.BroadcastWalletUpdate(event)
+
 	case flnd.StatusLocked:
 		n.reportHealth(HealthState{Level: HealthOrange, Info: "locked"})
 		n.BroadcastWalletUpdate(event)
@@ -248,7 +336,7 @@ func (n *notification) ProcessEvent(ev *flnd.Update) {
 		} else {
 			n.logger.Warn().Msg("wallet ready reported but RPC still unavailable")
 			n.reportHealth(HealthState{Level: HealthOrange, Info: "waiting for wallet"})
-			n.ShowToast("[red:-:-]Error:[-:-:-] wallet not ready")
+			n.ShowToastWithTimeout("[red:-:-]Error:[-:-:-] wallet not ready", time.Second*30)
 		}
 
 	case flnd.StatusTransaction:
@@ -268,9 +356,12 @@ func (n *notification) ProcessEvent(ev *flnd.Update) {
 		n.logger.Debug().
 			Uint32("block_height", ev.BlockHeight).
 			Msg("new block notification")
-		info := fmt.Sprintf("ready (%d)", ev.BlockHeight)
 		n.cache.updateTip(int32(ev.BlockHeight))
-		n.reportHealth(HealthState{Level: HealthGreen, Info: info})
+		if reorg := n.cache.GetReorg(); reorg != nil {
+			n.reportHealth(HealthState{Level: HealthOrange, Info: fmt.Sprintf("reorg at %d", reorg.Height)})
+		} else {
+			n.reportHealth(HealthState{Level: HealthGreen, Info: fmt.Sprintf("ready (%d)", ev.BlockHeight)})
+		}
 		n.BroadcastWalletUpdate(event)
 
 		// case flnd.StatusScanning:
@@ -289,6 +380,32 @@ func (n *notification) ProcessEvent(ev *flnd.Update) {
 	}
 }
 
+// trackReorg raises an alert when a block update replaced an already seen
+// block, and lifts it once the chain has grown past the height it had before.
+// Until then confirmations at or above the replaced block are suspect.
+func (n *notification) trackReorg(ev *flnd.Update) {
+	if ev.Reorg != nil {
+		n.logger.Warn().
+			Uint32("height", ev.Reorg.Height).
+			Uint32("depth", ev.Reorg.Depth).
+			Str("old_hash", ev.Reorg.OldHash).
+			Str("new_hash", ev.Reorg.NewHash).
+			Msg("chain reorganization detected")
+		n.cache.setReorg(ev.Reorg)
+		n.ShowToastWithTimeout(fmt.Sprintf(
+			"[red:-:-]⚠ Chain reorganization:[-:-:-] block %d replaced (depth %d). Confirmations from that height are suspect until the wallet catches up.",
+			ev.Reorg.Height, ev.Reorg.Depth), time.Minute)
+		return
+	}
+
+	reorg := n.cache.GetReorg()
+	if reorg == nil || ev.State != flnd.StatusBlock || ev.BlockHeight <= reorg.Tip {
+		return
+	}
+	n.cache.setReorg(nil)
+	n.ShowToastWithTimeout(fmt.Sprintf("[green:-:-]Chain caught up after the reorganization at %d", reorg.Height), time.Second*15)
+}
+
 func (n *notification) reportHealth(h HealthState) {
 	select {
 	case n.healthState <- h:
@@ -311,10 +428,6 @@ func (n *notification) Health() <-chan HealthState {
 	return n.healthState
 }
 
-func (n *notification) Toast() <-chan string {
-	return n.toast
-}
-
 func (n *notification) ensureWalletResponsive() bool {
 	const (
 		maxAttempts = 5
@@ -326,7 +439,7 @@ func (n *notification) ensureWalletResponsive() bool {
 	}
 
 	for attempt := 0; attempt < maxAttempts; attempt++ {
-		_, err := n.wallet.Balance()
+		_, err := n.wallet.Balance(context.Background())
 		if err == nil {
 			n.logger.Debug().Msg("wallet responsive confirmed")
 			return true
@@ -343,41 +456,21 @@ func (n *notification) ensureWalletResponsive() bool {
 		}
 
 		n.logger.Error().Err(err).Msg("wallet balance failed")
-		n.ShowToast(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()))
+		n.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
 		return false
 	}
 
-	n.ShowToast("[red:-:-]Error:[-:-:-] wallet not ready")
+	n.ShowToastWithTimeout("[red:-:-]Error:[-:-:-] wallet not ready", time.Second*30)
 	return false
 }
 
-func (n *notification) ShowToast(text string) {
-	select {
-	case n.toast <- text:
-	default:
-	}
-}
-
-func (n *notification) CancelToast() {
-	select {
-	case n.toast <- "":
-	default:
-	}
-}
-
-func (n *notification) ShowToastWithTimeout(text string, d time.Duration) {
-	n.toast <- text
-	go func() {
-		time.Sleep(d)
-		n.toast <- ""
-	}()
-}
-
 type Cache struct {
 	lockedBalance      chainutil.Amount
 	confirmedBalance   chainutil.Amount
 	unconfirmedBalance chainutil.Amount
 	tipHeight          int32
+	fiatRate           float64
+	reorg              *flnd.Reorg
 	mu                 sync.Mutex
 }
 
@@ -412,24 +505,66 @@ func (c *Cache) GetTipHeight() int32 {
 	return tip
 }
 
-func (l *Load) GetRecoveryStatus() (*RecoveryStatus, error) {
-	info, err := l.Wallet.GetRecoveryInfo()
+func (c *Cache) setReorg(reorg *flnd.Reorg) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reorg = reorg
+}
+
+// GetReorg returns the last chain reorganization while its confirmations are
+// still suspect, or nil.
+func (c *Cache) GetReorg() *flnd.Reorg {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reorg == nil {
+		return nil
+	}
+	reorg := *c.reorg
+	return &reorg
+}
+
+// ConfirmationSuspect reports whether a transaction mined at blockHeight may
+// have been affected by the last chain reorganization.
+func (c *Cache) ConfirmationSuspect(blockHeight int32) bool {
+	reorg := c.GetReorg()
+	return reorg != nil && blockHeight > 0 && uint32(blockHeight) >= reorg.Height
+}
+
+func (c *Cache) SetFiatRate(rate float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fiatRate = rate
+}
+
+func (c *Cache) GetFiatRate() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fiatRate
+}
+
+func (l *Load) GetRecoveryStatus(ctx context.Context) (*RecoveryStatus, error) {
+	info, err := l.Wallet.GetRecoveryInfo(ctx)
 	if err != nil {
 		return nil, err
 	}
-	utxos, err := l.Wallet.ListUnspent(0, math.MaxInt32)
+	utxos, err := l.Wallet.ListUnspent(ctx, 0, math.MaxInt32)
 	if err != nil {
 		return nil, err
 	}
-	return &RecoveryStatus{Info: info, UTXOCount: len(utxos)}, nil
+	status := &RecoveryStatus{Info: info, UTXOCount: len(utxos)}
+	if _, _, height, err := l.Wallet.IsSynced(); err == nil {
+		status.TipHeight = height
+	}
+	return status, nil
 }
 
 func (l *Load) MonitorRecovery(ctx context.Context, interval time.Duration, cb func(*RecoveryStatus) bool) (*RecoveryStatus, error) {
 	if interval <= 0 {
 		interval = time.Second
 	}
+	var tracker recoveryTracker
 	for {
-		status, err := l.GetRecoveryStatus()
+		status, err := l.GetRecoveryStatus(ctx)
 		if err != nil {
 			if errors.Is(err, flnd.ErrDaemonNotRunning) {
 				select {
@@ -441,6 +576,7 @@ func (l *Load) MonitorRecovery(ctx context.Context, interval time.Duration, cb f
 			}
 			return nil, err
 		}
+		status.Estimate = tracker.add(time.Now(), status)
 		cont := true
 		if cb != nil {
 			cont = cb(status)
@@ -455,3 +591,13 @@ func (l *Load) MonitorRecovery(ctx context.Context, interval time.Duration, cb f
 		}
 	}
 }
+
+// FailedPermanentlyGuidance tells what to do once the wallet daemon stopped
+// being restarted, after err.
+func FailedPermanentlyGuidance(err error) string {
+	msg := "[red:-:-]Error:[-:-:-] the wallet daemon failed to start too many times in a row and will not be restarted."
+	if err != nil {
+		msg += fmt.Sprintf(" Last error: %v.", err)
+	}
+	return msg + " Check the logs and the disk space, then restart twallet. Raise maxrestarts in twallet.conf, or set it to 0, to keep retrying."
+}
@@ -5,12 +5,30 @@
 package load
 
 import (
+	"context"
+	"fmt"
+	"sync"
+
 	"github.com/rivo/tview"
 )
 
+// modalLayer is an open modal on the navigator stack.
+type modalLayer struct {
+	name   string
+	ctx    context.Context
+	cancel context.CancelFunc
+	// focus had the focus when the modal opened, to give it back when the
+	// modal closes over another one.
+	focus tview.Primitive
+}
+
 type Navigator struct {
 	*tview.Application
 	pages *tview.Pages
+
+	mu     sync.Mutex
+	modals []*modalLayer
+	nextID int
 }
 
 func newNavigator(app *tview.Application, pages *tview.Pages) *Navigator {
@@ -20,14 +38,109 @@ func newNavigator(app *tview.Application, pages *tview.Pages) *Navigator {
 	}
 }
 
+// NavigateTo shows page in place of the current one, closing the modals.
 func (n *Navigator) NavigateTo(page tview.Primitive) {
+	n.closeModals()
 	n.pages.HidePage("main").AddAndSwitchToPage("main", page, true)
 }
 
+// ShowModal opens modal in place of the top modal, or as the only one.
 func (n *Navigator) ShowModal(modal tview.Primitive) {
-	n.pages.RemovePage("dialog").AddPage("dialog", modal, true, true)
+	n.mu.Lock()
+	var replaced *modalLayer
+	if len(n.modals) > 0 {
+		replaced = n.modals[len(n.modals)-1]
+		n.modals = n.modals[:len(n.modals)-1]
+		replaced.cancel()
+	}
+	layer := n.newLayer()
+	if replaced != nil {
+		layer.focus = replaced.focus
+	}
+	n.modals = append(n.modals, layer)
+	n.mu.Unlock()
+
+	if replaced != nil {
+		n.pages.RemovePage(replaced.name)
+	}
+	n.pages.AddPage(layer.name, modal, true, true)
 }
 
+// PushModal opens modal over the open ones, such as an error over the form
+// that caused it. Closing it gives the focus back to the modal below.
+func (n *Navigator) PushModal(modal tview.Primitive) {
+	focus := n.GetFocus()
+
+	n.mu.Lock()
+	layer := n.newLayer()
+	if len(n.modals) > 0 {
+		layer.focus = focus
+	}
+	n.modals = append(n.modals, layer)
+	n.mu.Unlock()
+
+	n.pages.AddPage(layer.name, modal, true, true)
+}
+
+// CloseModal closes the top modal only, giving the focus back to the modal
+// below if there is one.
 func (n *Navigator) CloseModal() {
-	n.pages.RemovePage("dialog")
+	n.mu.Lock()
+	if len(n.modals) == 0 {
+		n.mu.Unlock()
+		return
+	}
+	top := n.modals[len(n.modals)-1]
+	n.modals = n.modals[:len(n.modals)-1]
+	top.cancel()
+	n.mu.Unlock()
+
+	n.pages.RemovePage(top.name)
+	if top.focus != nil {
+		n.SetFocus(top.focus)
+	}
+}
+
+// ModalDepth returns the number of open modals.
+func (n *Navigator) ModalDepth() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.modals)
+}
+
+// closeModals closes every modal.
+func (n *Navigator) closeModals() {
+	n.mu.Lock()
+	modals := n.modals
+	n.modals = nil
+	n.mu.Unlock()
+
+	for _, layer := range modals {
+		layer.cancel()
+		n.pages.RemovePage(layer.name)
+	}
+}
+
+// newLayer returns a layer with its own page name and context. Callers hold
+// mu.
+func (n *Navigator) newLayer() *modalLayer {
+	n.nextID++
+	ctx, cancel := context.WithCancel(context.Background())
+	return &modalLayer{
+		name:   fmt.Sprintf("dialog-%d", n.nextID),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// ModalContext returns a context cancelled when the top modal is closed or
+// replaced, for the wallet calls it starts. Without a modal it is never
+// cancelled.
+func (n *Navigator) ModalContext() context.Context {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.modals) == 0 {
+		return context.Background()
+	}
+	return n.modals[len(n.modals)-1].ctx
 }
@@ -0,0 +1,53 @@
+package load
+
+import (
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+func TestNavigatorModalStack(t *testing.T) {
+	pages := tview.NewPages()
+	nav := newNavigator(tview.NewApplication(), pages)
+	nav.NavigateTo(tview.NewBox())
+
+	form := tview.NewInputField()
+	nav.ShowModal(form)
+	nav.SetFocus(form)
+	formCtx := nav.ModalContext()
+
+	nav.PushModal(tview.NewBox())
+	errCtx := nav.ModalContext()
+	if nav.ModalDepth() != 2 || pages.GetPageCount() != 3 {
+		t.Fatalf("got %d modals and %d pages, want 2 and 3", nav.ModalDepth(), pages.GetPageCount())
+	}
+
+	nav.CloseModal()
+	if errCtx.Err() == nil {
+		t.Fatal("closed modal context not cancelled")
+	}
+	if formCtx.Err() != nil {
+		t.Fatal("modal below cancelled by closing the top one")
+	}
+	if nav.ModalContext() != formCtx {
+		t.Fatal("context is not the one of the modal below")
+	}
+	if nav.GetFocus() != form {
+		t.Fatal("focus not given back to the modal below")
+	}
+
+	nav.ShowModal(tview.NewBox())
+	if formCtx.Err() == nil || nav.ModalDepth() != 1 {
+		t.Fatal("ShowModal did not replace the top modal")
+	}
+
+	nav.PushModal(tview.NewBox())
+	nav.NavigateTo(tview.NewBox())
+	if nav.ModalDepth() != 0 || pages.GetPageCount() != 1 {
+		t.Fatalf("navigating left %d modals and %d pages", nav.ModalDepth(), pages.GetPageCount())
+	}
+	nav.CloseModal()
+	if nav.ModalContext().Err() != nil {
+		t.Fatal("context without modal cancelled")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"sync"
+
+	"github.com/flokiorg/flnd/lnrpc"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+// Notifier is an integration told about wallet events, such as the terminal
+// bell, the receive command or the journal. Notify is called for every
+// service update, in order, from the notification goroutine and must not
+// block.
+type Notifier interface {
+	Notify(ev *flnd.Update)
+}
+
+// AlertNotifier is implemented by notifiers that also want the alerts raised
+// and resolved by the alert engine.
+type AlertNotifier interface {
+	NotifyAlert(event AlertEvent)
+}
+
+// Register adds notifiers to the ones told about each update.
+func (n *notification) Register(notifiers ...Notifier) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notifiers = append(n.notifiers, notifiers...)
+}
+
+func (n *notification) registered() []Notifier {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Notifier(nil), n.notifiers...)
+}
+
+func (n *notification) dispatch(ev *flnd.Update) {
+	for _, notifier := range n.registered() {
+		notifier.Notify(ev)
+	}
+}
+
+func (n *notification) dispatchAlert(event AlertEvent) {
+	for _, notifier := range n.registered() {
+		if alerts, ok := notifier.(AlertNotifier); ok {
+			alerts.NotifyAlert(event)
+		}
+	}
+}
+
+// registerNotifiers sets up the notifiers enabled in the configuration.
+func (l *Load) registerNotifiers() {
+	cfg := l.AppConfig
+
+	l.Notif.Register(newJournalTracker(l.Journal))
+	if cfg.Bell && l.screen != nil {
+		l.Notif.Register(newBellNotifier(l.Application, l.screen))
+	}
+	if cfg.OnReceiveCmd != "" {
+		l.Notif.Register(newCommandNotifier(cfg.OnReceiveCmd, l.Logger))
+	}
+	if cfg.AlertWebhook != "" {
+		l.Notif.Register(newWebhookNotifier(cfg.AlertWebhook, cfg.HTTPTransport(), l.Logger))
+	}
+}
+
+// incomingFilter passes each incoming transaction once. The transaction
+// stream reports a transaction again when it confirms.
+type incomingFilter struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+func newIncomingFilter() *incomingFilter {
+	return &incomingFilter{seen: make(map[string]struct{})}
+}
+
+// first returns the transaction of ev when it is an incoming transaction not
+// seen before, or nil.
+func (f *incomingFilter) first(ev *flnd.Update) *lnrpc.Transaction {
+	if ev == nil || ev.State != flnd.StatusTransaction {
+		return nil
+	}
+	tx := ev.Transaction
+	if tx == nil || tx.Amount <= 0 {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.seen[tx.TxHash]; ok {
+		return nil
+	}
+	if len(f.seen) >= maxAlertedTxs {
+		f.seen = make(map[string]struct{})
+	}
+	f.seen[tx.TxHash] = struct{}{}
+	return tx
+}
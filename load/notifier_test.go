@@ -0,0 +1,58 @@
+package load
+
+import (
+	"testing"
+
+	"github.com/flokiorg/flnd/lnrpc"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+type recordingNotifier struct {
+	updates []flnd.Status
+	alerts  []string
+}
+
+func (r *recordingNotifier) Notify(ev *flnd.Update) { r.updates = append(r.updates, ev.State) }
+
+func (r *recordingNotifier) NotifyAlert(event AlertEvent) { r.alerts = append(r.alerts, event.Kind) }
+
+type updateOnlyNotifier struct{ count int }
+
+func (u *updateOnlyNotifier) Notify(*flnd.Update) { u.count++ }
+
+func TestNotifierDispatch(t *testing.T) {
+	n := &notification{}
+	rec, plain := &recordingNotifier{}, &updateOnlyNotifier{}
+	n.Register(rec, plain)
+
+	n.dispatch(&flnd.Update{State: flnd.StatusLocked})
+	n.dispatch(&flnd.Update{State: flnd.StatusUnlocked})
+	n.dispatchAlert(AlertEvent{Kind: "low_balance"})
+
+	if len(rec.updates) != 2 || rec.updates[1] != flnd.StatusUnlocked || plain.count != 2 {
+		t.Errorf("updates = %v and %d", rec.updates, plain.count)
+	}
+	if len(rec.alerts) != 1 || rec.alerts[0] != "low_balance" {
+		t.Errorf("alerts = %v", rec.alerts)
+	}
+}
+
+func TestIncomingFilter(t *testing.T) {
+	f := newIncomingFilter()
+	in := &flnd.Update{State: flnd.StatusTransaction, Transaction: &lnrpc.Transaction{TxHash: "a", Amount: 10}}
+	out := &flnd.Update{State: flnd.StatusTransaction, Transaction: &lnrpc.Transaction{TxHash: "b", Amount: -10}}
+
+	if f.first(in) == nil {
+		t.Error("first sight of an incoming transaction was filtered")
+	}
+	if f.first(in) != nil {
+		t.Error("incoming transaction passed twice")
+	}
+	if f.first(out) != nil {
+		t.Error("outgoing transaction passed")
+	}
+	if f.first(&flnd.Update{State: flnd.StatusBlock}) != nil {
+		t.Error("block update passed")
+	}
+}
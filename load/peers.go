@@ -0,0 +1,144 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+const peerPollInterval = time.Minute
+
+// peerTracker remembers since when each channel peer has been unreachable.
+type peerTracker struct {
+	threshold    time.Duration
+	offlineSince map[string]time.Time
+	alerted      map[string]flnd.ChannelPeer
+}
+
+func newPeerTracker(threshold time.Duration) *peerTracker {
+	return &peerTracker{
+		threshold:    threshold,
+		offlineSince: make(map[string]time.Time),
+		alerted:      make(map[string]flnd.ChannelPeer),
+	}
+}
+
+// update records a poll taken at now and returns the peers that just crossed
+// the offline threshold and the alerted peers that came back. Peers whose
+// channels were closed are forgotten, and resolved if they were alerted.
+func (t *peerTracker) update(peers []flnd.ChannelPeer, now time.Time) (raised, recovered []flnd.ChannelPeer) {
+	current := make(map[string]flnd.ChannelPeer, len(peers))
+	for _, peer := range peers {
+		if known, ok := current[peer.PubKey]; ok && known.Online {
+			continue
+		}
+		current[peer.PubKey] = peer
+	}
+
+	for pubKey, peer := range current {
+		if peer.Online {
+			delete(t.offlineSince, pubKey)
+			if _, ok := t.alerted[pubKey]; ok {
+				delete(t.alerted, pubKey)
+				recovered = append(recovered, peer)
+			}
+			continue
+		}
+
+		since, ok := t.offlineSince[pubKey]
+		if !ok {
+			t.offlineSince[pubKey] = now
+			since = now
+		}
+		if _, ok := t.alerted[pubKey]; !ok && now.Sub(since) >= t.threshold {
+			t.alerted[pubKey] = peer
+			raised = append(raised, peer)
+		}
+	}
+
+	for pubKey := range t.offlineSince {
+		if _, ok := current[pubKey]; !ok {
+			delete(t.offlineSince, pubKey)
+			if peer, ok := t.alerted[pubKey]; ok {
+				delete(t.alerted, pubKey)
+				recovered = append(recovered, peer)
+			}
+		}
+	}
+
+	sortPeers(raised)
+	sortPeers(recovered)
+	return raised, recovered
+}
+
+func sortPeers(peers []flnd.ChannelPeer) {
+	sort.Slice(peers, func(i, j int) bool { return peers[i].PubKey < peers[j].PubKey })
+}
+
+func peerName(peer flnd.ChannelPeer) string {
+	if peer.Alias != "" {
+		return peer.Alias
+	}
+	if len(peer.PubKey) > 16 {
+		return peer.PubKey[:16] + "…"
+	}
+	return peer.PubKey
+}
+
+// startPeerMonitor polls the channel peers and raises a footer warning and an
+// alert webhook when one stays offline longer than threshold.
+func (l *Load) startPeerMonitor(threshold time.Duration) {
+	tracker := newPeerTracker(threshold)
+
+	go func() {
+		ticker := time.NewTicker(peerPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-l.Notif.stop:
+				return
+			case <-ticker.C:
+			}
+
+			peers, err := l.Wallet.ChannelPeers(context.Background())
+			if err != nil {
+				if !errors.Is(err, flnd.ErrDaemonNotRunning) {
+					l.Logger.Debug().Err(err).Msg("channel peer poll failed")
+				}
+				continue
+			}
+			l.checkPeers(tracker, peers, time.Now())
+		}
+	}()
+}
+
+func (l *Load) checkPeers(tracker *peerTracker, peers []flnd.ChannelPeer, now time.Time) {
+	raised, recovered := tracker.update(peers, now)
+
+	for _, peer := range raised {
+		details := map[string]string{"pubkey": peer.PubKey, "channel_point": peer.ChannelPoint}
+		l.Alerts.Raise(peerWarnKey(peer), "peer_offline",
+			fmt.Sprintf("[yellow:-:-]⚠ Channel peer %s offline[-:-:-]", peerName(peer)),
+			fmt.Sprintf("[yellow:-:-]⚠ Channel peer %s offline[-:-:-] for more than %s", peerName(peer), tracker.threshold),
+			details)
+	}
+	for _, peer := range recovered {
+		details := map[string]string{"pubkey": peer.PubKey, "channel_point": peer.ChannelPoint}
+		l.Alerts.Resolve(peerWarnKey(peer), "peer_offline_resolved",
+			fmt.Sprintf("[green:-:-]Channel peer %s is back online", peerName(peer)),
+			details)
+	}
+}
+
+func peerWarnKey(peer flnd.ChannelPeer) string {
+	return "peer:" + peer.PubKey
+}
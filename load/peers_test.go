@@ -0,0 +1,49 @@
+package load
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+func TestPeerTracker(t *testing.T) {
+	tracker := newPeerTracker(10 * time.Minute)
+	start := time.Unix(1700000000, 0)
+
+	offline := []flnd.ChannelPeer{
+		{PubKey: "a", ChannelPoint: "tx:0"},
+		{PubKey: "b", ChannelPoint: "tx:1", Online: true},
+	}
+
+	if raised, _ := tracker.update(offline, start); len(raised) != 0 {
+		t.Fatalf("raised %+v before the threshold", raised)
+	}
+	if raised, _ := tracker.update(offline, start.Add(5*time.Minute)); len(raised) != 0 {
+		t.Fatalf("raised %+v before the threshold", raised)
+	}
+
+	raised, _ := tracker.update(offline, start.Add(10*time.Minute))
+	if len(raised) != 1 || raised[0].PubKey != "a" {
+		t.Fatalf("raised = %+v, want peer a", raised)
+	}
+	if raised, _ := tracker.update(offline, start.Add(20*time.Minute)); len(raised) != 0 {
+		t.Fatalf("peer a raised twice: %+v", raised)
+	}
+
+	// A second channel with the same peer being active counts as online.
+	back := append(offline, flnd.ChannelPeer{PubKey: "a", ChannelPoint: "tx:2", Online: true})
+	_, recovered := tracker.update(back, start.Add(21*time.Minute))
+	if len(recovered) != 1 || recovered[0].PubKey != "a" {
+		t.Fatalf("recovered = %+v, want peer a", recovered)
+	}
+	if len(tracker.alerted) != 0 {
+		t.Fatalf("alerted = %+v after recovery", tracker.alerted)
+	}
+
+	tracker.update(offline, start.Add(30*time.Minute))
+	tracker.update(nil, start.Add(31*time.Minute))
+	if raised, _ := tracker.update(offline, start.Add(45*time.Minute)); len(raised) != 0 {
+		t.Fatalf("closed channel kept its offline time: %+v", raised)
+	}
+}
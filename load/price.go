@@ -0,0 +1,184 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flokiorg/go-flokicoin/chainutil"
+)
+
+const (
+	defaultPriceCacheTTL   = 5 * time.Minute
+	priceRequestTimeout    = 15 * time.Second
+	maxPriceResponseLength = 1 << 20
+)
+
+// PriceProvider returns the value of one FLC in a single fiat currency.
+type PriceProvider interface {
+	Currency() string
+	Rate(ctx context.Context) (float64, error)
+}
+
+// HTTPPriceProvider reads the FLC rate from a JSON endpoint. The rate is
+// looked up with a dot separated path (e.g. "flokicoin.usd") and cached for
+// the configured TTL.
+type HTTPPriceProvider struct {
+	url      string
+	field    string
+	currency string
+	ttl      time.Duration
+	client   *http.Client
+
+	mu        sync.Mutex
+	rate      float64
+	fetchedAt time.Time
+}
+
+// NewHTTPPriceProvider reads the rate at url, through transport, nil for the
+// default one.
+func NewHTTPPriceProvider(url, field, currency string, ttl time.Duration, transport http.RoundTripper) *HTTPPriceProvider {
+	if ttl <= 0 {
+		ttl = defaultPriceCacheTTL
+	}
+	return &HTTPPriceProvider{
+		url:      url,
+		field:    field,
+		currency: strings.ToUpper(currency),
+		ttl:      ttl,
+		client:   &http.Client{Timeout: priceRequestTimeout, Transport: transport},
+	}
+}
+
+func (p *HTTPPriceProvider) Currency() string {
+	return p.currency
+}
+
+func (p *HTTPPriceProvider) Rate(ctx context.Context) (float64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.fetchedAt.IsZero() && time.Since(p.fetchedAt) < p.ttl {
+		return p.rate, nil
+	}
+
+	rate, err := p.fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	p.rate = rate
+	p.fetchedAt = time.Now()
+	return rate, nil
+}
+
+func (p *HTTPPriceProvider) fetch(ctx context.Context) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("price provider returned %s", resp.Status)
+	}
+
+	var doc any
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxPriceResponseLength)).Decode(&doc); err != nil {
+		return 0, fmt.Errorf("invalid price response: %w", err)
+	}
+
+	return lookupJSONNumber(doc, p.field)
+}
+
+func lookupJSONNumber(doc any, path string) (float64, error) {
+	node := doc
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			obj, ok := node.(map[string]any)
+			if !ok {
+				return 0, fmt.Errorf("price field %q not found", path)
+			}
+			if node, ok = obj[key]; !ok {
+				return 0, fmt.Errorf("price field %q not found", path)
+			}
+		}
+	}
+
+	switch v := node.(type) {
+	case float64:
+		return v, nil
+	case string:
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("price field %q is not a number", path)
+		}
+		return rate, nil
+	default:
+		return 0, fmt.Errorf("price field %q is not a number", path)
+	}
+}
+
+func (l *Load) startPriceUpdates(interval time.Duration) {
+	if l.Price == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = defaultPriceCacheTTL
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), priceRequestTimeout)
+			rate, err := l.Price.Rate(ctx)
+			cancel()
+			if err != nil {
+				l.Logger.Warn().Err(err).Msg("unable to fetch exchange rate")
+			} else {
+				l.Alerts.Observe(MetricPrice, rate)
+				if rate != l.Cache.GetFiatRate() {
+					l.Cache.SetFiatRate(rate)
+					l.BroadcastBalanceRefresh()
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-l.Notif.stop:
+				return
+			}
+		}
+	}()
+}
+
+// FiatView renders the approximate fiat value of amount, or an empty string
+// when fiat display is disabled or no rate has been fetched yet.
+func (l *Load) FiatView(amount chainutil.Amount) string {
+	if l == nil || l.Price == nil {
+		return ""
+	}
+	rate := l.Cache.GetFiatRate()
+	if rate <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("≈ %.2f %s", amount.ToFLC()*rate, l.Price.Currency())
+}
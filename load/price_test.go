@@ -0,0 +1,51 @@
+package load
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPPriceProviderNestedField(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		fmt.Fprint(w, `{"flokicoin":{"usd":"0.125"}}`)
+	}))
+	defer srv.Close()
+
+	p := NewHTTPPriceProvider(srv.URL, "flokicoin.usd", "usd", time.Minute, nil)
+
+	for i := 0; i < 2; i++ {
+		rate, err := p.Rate(context.Background())
+		if err != nil {
+			t.Fatalf("rate: %v", err)
+		}
+		if rate != 0.125 {
+			t.Fatalf("unexpected rate got:%v want:0.125", rate)
+		}
+	}
+
+	if got := hits.Load(); got != 1 {
+		t.Fatalf("expected cached rate, provider hit %d times", got)
+	}
+	if p.Currency() != "USD" {
+		t.Fatalf("unexpected currency %q", p.Currency())
+	}
+}
+
+func TestHTTPPriceProviderMissingField(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"last":1.5}`)
+	}))
+	defer srv.Close()
+
+	p := NewHTTPPriceProvider(srv.URL, "price", "USD", time.Minute, nil)
+	if _, err := p.Rate(context.Background()); err == nil {
+		t.Fatal("expected an error for a missing price field")
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// recoveryRateWindow is how far back the recovery speed is measured, long
+// enough to smooth the blocks that hold many wallet transactions.
+const recoveryRateWindow = 30 * time.Second
+
+// RecoveryEstimate is the speed of a recovery and the time it has left.
+type RecoveryEstimate struct {
+	// Rate is the progress made per second, the whole recovery being 1.
+	Rate float64
+	ETA  time.Duration
+
+	// BlocksPerSecond and BlocksLeft are counted up to the chain tip from
+	// the genesis block, as the daemon does not report the wallet birthday
+	// the scan starts at. They are upper bounds, and 0 without the tip.
+	BlocksPerSecond float64
+	BlocksLeft      uint32
+}
+
+func (e *RecoveryEstimate) String() string {
+	if e == nil {
+		return ""
+	}
+	parts := make([]string, 0, 3)
+	if e.BlocksPerSecond > 0 {
+		parts = append(parts, fmt.Sprintf("≤%.0f blocks/s", e.BlocksPerSecond))
+		parts = append(parts, fmt.Sprintf("≤%d blocks left", e.BlocksLeft))
+	}
+	parts = append(parts, fmt.Sprintf("ETA %s", e.ETA.Round(time.Second)))
+	return strings.Join(parts, " • ")
+}
+
+type recoverySample struct {
+	at       time.Time
+	progress float64
+}
+
+// recoveryTracker measures the recovery speed over the samples of the last
+// recoveryRateWindow.
+type recoveryTracker struct {
+	samples []recoverySample
+}
+
+// add records the progress of status, taken at at, and estimates the time
+// left. There is no estimate before the progress moved, nor once done.
+func (t *recoveryTracker) add(at time.Time, status *RecoveryStatus) *RecoveryEstimate {
+	if status == nil || status.Info == nil || status.Info.GetRecoveryFinished() {
+		return nil
+	}
+	progress := status.Info.GetProgress()
+	if progress <= 0 || progress >= 1 {
+		return nil
+	}
+
+	// A recovery that went back started over.
+	if n := len(t.samples); n > 0 && progress < t.samples[n-1].progress {
+		t.samples = t.samples[:0]
+	}
+	t.samples = append(t.samples, recoverySample{at: at, progress: progress})
+	for len(t.samples) > 2 && at.Sub(t.samples[1].at) >= recoveryRateWindow {
+		t.samples = t.samples[1:]
+	}
+
+	first := t.samples[0]
+	elapsed := at.Sub(first.at).Seconds()
+	if elapsed <= 0 || progress <= first.progress {
+		return nil
+	}
+
+	rate := (progress - first.progress) / elapsed
+	estimate := &RecoveryEstimate{
+		Rate: rate,
+		ETA:  time.Duration((1 - progress) / rate * float64(time.Second)),
+	}
+	if status.TipHeight > 0 {
+		estimate.BlocksPerSecond = rate * float64(status.TipHeight)
+		estimate.BlocksLeft = uint32((1 - progress) * float64(status.TipHeight))
+	}
+	return estimate
+}
@@ -0,0 +1,50 @@
+package load
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc"
+)
+
+func TestRecoveryTracker(t *testing.T) {
+	var tracker recoveryTracker
+	start := time.Unix(1_700_000_000, 0)
+	sample := func(after time.Duration, progress float64) *RecoveryEstimate {
+		return tracker.add(start.Add(after), &RecoveryStatus{
+			Info:      &lnrpc.GetRecoveryInfoResponse{RecoveryMode: true, Progress: progress},
+			TipHeight: 100_000,
+		})
+	}
+
+	if e := sample(0, 0.10); e != nil {
+		t.Fatalf("estimate from one sample = %+v", e)
+	}
+	e := sample(10*time.Second, 0.20)
+	if e == nil {
+		t.Fatal("no estimate once the progress moved")
+	}
+	if e.ETA != 80*time.Second {
+		t.Errorf("ETA = %s, want 1m20s", e.ETA)
+	}
+	if int(e.BlocksPerSecond+0.5) != 1000 || e.BlocksLeft != 80_000 {
+		t.Errorf("blocks = %.1f/s, %d left; want 1000/s, 80000 left", e.BlocksPerSecond, e.BlocksLeft)
+	}
+
+	// Old samples leave the window, so the rate follows a slowdown.
+	sample(20*time.Second, 0.30)
+	sample(50*time.Second, 0.33)
+	e = sample(60*time.Second, 0.34)
+	if e == nil || e.Rate > 0.0011 {
+		t.Errorf("rate after a slowdown = %+v, want at most 0.1%%/s", e)
+	}
+
+	// A recovery that starts over drops the previous samples.
+	if e := sample(70*time.Second, 0.05); e != nil {
+		t.Errorf("estimate right after a restart = %+v", e)
+	}
+
+	if e := tracker.add(start, &RecoveryStatus{Info: &lnrpc.GetRecoveryInfoResponse{RecoveryFinished: true, Progress: 1}}); e != nil {
+		t.Errorf("estimate once finished = %+v", e)
+	}
+}
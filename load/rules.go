@@ -0,0 +1,278 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flokiorg/go-flokicoin/chainutil"
+
+	. "github.com/flokiorg/twallet/shared"
+)
+
+// Metrics observed by the alert rules.
+const (
+	MetricBalance = "balance"  // confirmed balance, in loki
+	MetricFeeRate = "fee_rate" // next block fee rate, in loki/vB
+	MetricPrice   = "price"    // FLC price, in the fiat currency
+)
+
+// AlertRule fires while a metric is above (or at) its threshold, or below it.
+type AlertRule struct {
+	Kind      string
+	Metric    string
+	Above     bool
+	Threshold float64
+
+	// Label names the condition on the footer, Hint is advice added to the
+	// toast raised when the rule fires.
+	Label string
+	Hint  string
+}
+
+func (r AlertRule) holds(value float64) bool {
+	if r.Above {
+		return value >= r.Threshold
+	}
+	return value < r.Threshold
+}
+
+// ParsePriceRules reads the alerts.price option: comma separated thresholds,
+// each prefixed with > to alert above it or < to alert below it.
+func ParsePriceRules(value string) ([]AlertRule, error) {
+	var rules []AlertRule
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if len(item) < 2 || (item[0] != '>' && item[0] != '<') {
+			return nil, fmt.Errorf("price alert %q must start with > or <", item)
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(item[1:]), 64)
+		if err != nil || threshold <= 0 {
+			return nil, fmt.Errorf("invalid price alert threshold in %q", item)
+		}
+		rule := AlertRule{Metric: MetricPrice, Above: item[0] == '>', Threshold: threshold}
+		if rule.Above {
+			rule.Kind, rule.Label = "price_above", "FLC price up"
+		} else {
+			rule.Kind, rule.Label = "price_below", "FLC price down"
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// loadAlertRules registers the rules set in the alerts.* options.
+func (l *Load) loadAlertRules() {
+	cfg := l.AppConfig
+	currency := strings.ToUpper(cfg.FiatCurrency)
+
+	l.Alerts.Format(MetricBalance, func(v float64) string { return FormatAmountView(chainutil.Amount(v), 8) })
+	l.Alerts.Format(MetricFeeRate, func(v float64) string { return fmt.Sprintf("%.0f loki/vB", v) })
+	l.Alerts.Format(MetricPrice, func(v float64) string { return fmt.Sprintf("%.6g %s", v, currency) })
+
+	if minimum, err := chainutil.NewAmount(cfg.MinBalanceAlert); err == nil && minimum > 0 {
+		l.Alerts.Add(AlertRule{
+			Kind:      "low_balance",
+			Metric:    MetricBalance,
+			Threshold: float64(minimum),
+			Label:     "Low balance",
+		})
+	}
+
+	if cfg.FeeRateAlert > 0 && cfg.Feeurl != "" {
+		l.Alerts.Add(AlertRule{
+			Kind:      "fee_spike",
+			Metric:    MetricFeeRate,
+			Above:     true,
+			Threshold: cfg.FeeRateAlert,
+			Label:     "Fees high",
+			Hint:      "Non-urgent sends can wait.",
+		})
+	}
+
+	rules, err := ParsePriceRules(cfg.PriceAlerts)
+	if err != nil {
+		l.Logger.Warn().Err(err).Msg("ignoring price alerts")
+		return
+	}
+	if len(rules) > 0 && (cfg.NoFiat || cfg.FiatURL == "") {
+		l.Logger.Warn().Msg("price alerts need fiat.url, ignoring them")
+		return
+	}
+	for _, rule := range rules {
+		l.Alerts.Add(rule)
+	}
+}
+
+type ruleState struct {
+	AlertRule
+	key    string
+	known  bool
+	firing bool
+}
+
+// AlertEngine evaluates the alert rules against the observed metrics. A rule
+// that starts to hold raises a toast, a footer warning kept until it stops
+// holding, and a webhook event; the other monitors raise and resolve their
+// alerts through it as well.
+type AlertEngine struct {
+	l *Load
+
+	mu      sync.Mutex
+	rules   []*ruleState
+	formats map[string]func(float64) string
+}
+
+func newAlertEngine(l *Load) *AlertEngine {
+	return &AlertEngine{
+		l:       l,
+		formats: make(map[string]func(float64) string),
+	}
+}
+
+// Format sets how values of metric are displayed.
+func (e *AlertEngine) Format(metric string, format func(float64) string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.formats[metric] = format
+}
+
+func (e *AlertEngine) Add(rule AlertRule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = append(e.rules, &ruleState{
+		AlertRule: rule,
+		key:       fmt.Sprintf("rule%02d", len(e.rules)),
+	})
+}
+
+type ruleChange struct {
+	rule  AlertRule
+	key   string
+	fired bool
+}
+
+// evaluate records value for the rules of metric and returns those that
+// started or stopped holding. A rule holding on its first value fires.
+func (e *AlertEngine) evaluate(metric string, value float64) []ruleChange {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var changes []ruleChange
+	for _, rule := range e.rules {
+		if rule.Metric != metric {
+			continue
+		}
+		holds := rule.holds(value)
+		switch {
+		case holds && (!rule.known || !rule.firing):
+			changes = append(changes, ruleChange{rule: rule.AlertRule, key: rule.key, fired: true})
+		case !holds && rule.known && rule.firing:
+			changes = append(changes, ruleChange{rule: rule.AlertRule, key: rule.key})
+		}
+		rule.known = true
+		rule.firing = holds
+	}
+	return changes
+}
+
+func (e *AlertEngine) format(metric string, value float64) string {
+	e.mu.Lock()
+	format := e.formats[metric]
+	e.mu.Unlock()
+	if format == nil {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return format(value)
+}
+
+// Observe feeds a new value of metric to the rules.
+func (e *AlertEngine) Observe(metric string, value float64) {
+	for _, change := range e.evaluate(metric, value) {
+		rule := change.rule
+		current := e.format(metric, value)
+		threshold := e.format(metric, rule.Threshold)
+		details := map[string]string{
+			"metric":    metric,
+			"value":     strconv.FormatFloat(value, 'f', -1, 64),
+			"threshold": strconv.FormatFloat(rule.Threshold, 'f', -1, 64),
+		}
+
+		if !change.fired {
+			e.Resolve(change.key, rule.Kind+"_resolved",
+				fmt.Sprintf("[green:-:-]%s resolved:[-:-:-] %s", rule.Label, current), details)
+			continue
+		}
+
+		op := "<"
+		if rule.Above {
+			op = "≥"
+		}
+		toast := fmt.Sprintf("[yellow:-:-]⚠ %s:[-:-:-] %s %s %s", rule.Label, current, op, threshold)
+		if rule.Hint != "" {
+			toast += ". " + rule.Hint
+		}
+		e.Raise(change.key, rule.Kind,
+			fmt.Sprintf("[yellow:-:-]⚠ %s: %s[-:-:-]", rule.Label, current),
+			toast, details)
+	}
+}
+
+// Raise shows an alert: warning stays on the footer under key until Resolve,
+// toast is shown once and the webhook receives kind with the plain text.
+func (e *AlertEngine) Raise(key, kind, warning, toast string, details map[string]string) {
+	plain := stripColorTags(toast)
+	e.l.Logger.Warn().Str("kind", kind).Msg(plain)
+	e.l.Notif.SetWarning(key, warning)
+	e.l.Notif.ShowToastWithTimeout(toast, time.Second*30)
+	e.l.Notif.dispatchAlert(AlertEvent{Kind: kind, Message: plain, Time: time.Now().UTC(), Details: details})
+}
+
+// Resolve clears the footer warning under key and announces it with toast.
+func (e *AlertEngine) Resolve(key, kind, toast string, details map[string]string) {
+	plain := stripColorTags(toast)
+	e.l.Logger.Info().Str("kind", kind).Msg(plain)
+	e.l.Notif.ClearWarning(key)
+	e.l.Notif.ShowToastWithTimeout(toast, time.Second*15)
+	e.l.Notif.dispatchAlert(AlertEvent{Kind: kind, Message: plain, Time: time.Now().UTC(), Details: details})
+}
+
+// stripColorTags drops the tview color tags used by toasts, for logs and
+// webhooks.
+func stripColorTags(text string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(text, '[')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(text[start:], ']')
+		if end < 0 {
+			break
+		}
+		tag := text[start+1 : start+end]
+		b.WriteString(text[:start])
+		if !isColorTag(tag) {
+			b.WriteString(text[start : start+end+1])
+		}
+		text = text[start+end+1:]
+	}
+	b.WriteString(text)
+	return b.String()
+}
+
+func isColorTag(tag string) bool {
+	if tag == "" || tag == "-" {
+		return true
+	}
+	return strings.Contains(tag, ":") && !strings.ContainsAny(tag, " ")
+}
@@ -0,0 +1,69 @@
+package load
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePriceRules(t *testing.T) {
+	rules, err := ParsePriceRules(" >0.05, <0.01 ,")
+	if err != nil {
+		t.Fatalf("ParsePriceRules: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("ParsePriceRules returned %d rules", len(rules))
+	}
+	if !rules[0].Above || rules[0].Threshold != 0.05 || rules[0].Kind != "price_above" {
+		t.Errorf("first rule = %+v", rules[0])
+	}
+	if rules[1].Above || rules[1].Threshold != 0.01 || rules[1].Kind != "price_below" {
+		t.Errorf("second rule = %+v", rules[1])
+	}
+
+	for _, bad := range []string{"0.05", ">", ">abc", "<-1"} {
+		if _, err := ParsePriceRules(bad); err == nil {
+			t.Errorf("ParsePriceRules accepted %q", bad)
+		}
+	}
+}
+
+func TestAlertEngineEvaluate(t *testing.T) {
+	e := newAlertEngine(nil)
+	e.Add(AlertRule{Kind: "low_balance", Metric: MetricBalance, Threshold: 1000})
+	e.Add(AlertRule{Kind: "price_above", Metric: MetricPrice, Above: true, Threshold: 2})
+
+	fired := func(changes []ruleChange) []bool {
+		var out []bool
+		for _, change := range changes {
+			out = append(out, change.fired)
+		}
+		return out
+	}
+
+	steps := []struct {
+		metric string
+		value  float64
+		want   []bool
+	}{
+		{MetricBalance, 500, []bool{true}},
+		{MetricBalance, 400, nil},
+		{MetricBalance, 1000, []bool{false}},
+		{MetricBalance, 999, []bool{true}},
+		{MetricPrice, 1, nil},
+		{MetricPrice, 2, []bool{true}},
+		{MetricPrice, 3, nil},
+		{MetricPrice, 1.5, []bool{false}},
+	}
+	for _, step := range steps {
+		if got := fired(e.evaluate(step.metric, step.value)); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("evaluate(%s, %v) = %v, want %v", step.metric, step.value, got, step.want)
+		}
+	}
+}
+
+func TestStripColorTags(t *testing.T) {
+	got := stripColorTags("[yellow:-:-]⚠ Fees high:[-:-:-] 40 loki/vB [gray::]│[-::] [note]")
+	if want := "⚠ Fees high: 40 loki/vB │ [note]"; got != want {
+		t.Errorf("stripColorTags = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxToasts is how many toasts the footer shows at once.
+const maxToasts = 3
+
+// ToastMessage is a toast on display.
+type ToastMessage struct {
+	Severity NotificationSeverity
+	Text     string
+}
+
+// ToastHandle dismisses the toast it was returned for, and only that one.
+type ToastHandle struct {
+	queue *toastQueue
+	id    uint64
+}
+
+// Dismiss removes the toast if it is still shown. A nil handle does nothing.
+func (h *ToastHandle) Dismiss() {
+	if h == nil || h.queue == nil {
+		return
+	}
+	h.queue.dismiss(h.id)
+}
+
+type toastItem struct {
+	id       uint64
+	severity NotificationSeverity
+	text     string
+	timer    *time.Timer
+}
+
+// toastQueue holds the toasts on display, oldest first. Past its limit the
+// oldest toast of the lowest severity makes room, so an error outlives the
+// info toasts that follow it.
+type toastQueue struct {
+	mu      sync.Mutex
+	items   []*toastItem
+	nextID  uint64
+	limit   int
+	changed chan struct{}
+}
+
+func newToastQueue(limit int) *toastQueue {
+	return &toastQueue{
+		limit:   limit,
+		changed: make(chan struct{}, 1),
+	}
+}
+
+// push shows text until d elapses, or until dismissed when d is 0. A toast
+// with the same text already shown is moved to the top instead.
+func (q *toastQueue) push(severity NotificationSeverity, text string, d time.Duration) *ToastHandle {
+	q.mu.Lock()
+	q.items = slices.DeleteFunc(q.items, func(item *toastItem) bool {
+		if item.text != text {
+			return false
+		}
+		item.stop()
+		return true
+	})
+
+	q.nextID++
+	item := &toastItem{id: q.nextID, severity: severity, text: text}
+	if d > 0 {
+		id := item.id
+		item.timer = time.AfterFunc(d, func() { q.dismiss(id) })
+	}
+	q.items = append(q.items, item)
+
+	for len(q.items) > q.limit {
+		drop := 0
+		for i, item := range q.items {
+			if item.severity < q.items[drop].severity {
+				drop = i
+			}
+		}
+		q.items[drop].stop()
+		q.items = slices.Delete(q.items, drop, drop+1)
+	}
+	q.mu.Unlock()
+
+	q.notify()
+	return &ToastHandle{queue: q, id: item.id}
+}
+
+func (q *toastQueue) dismiss(id uint64) {
+	q.remove(func(item *toastItem) bool { return item.id == id })
+}
+
+// remove drops the toasts matched by del.
+func (q *toastQueue) remove(del func(*toastItem) bool) {
+	q.mu.Lock()
+	n := len(q.items)
+	q.items = slices.DeleteFunc(q.items, func(item *toastItem) bool {
+		if !del(item) {
+			return false
+		}
+		item.stop()
+		return true
+	})
+	removed := len(q.items) != n
+	q.mu.Unlock()
+
+	if removed {
+		q.notify()
+	}
+}
+
+// messages returns the toasts on display, newest first.
+func (q *toastQueue) messages() []ToastMessage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	messages := make([]ToastMessage, 0, len(q.items))
+	for i := len(q.items) - 1; i >= 0; i-- {
+		messages = append(messages, ToastMessage{Severity: q.items[i].severity, Text: q.items[i].text})
+	}
+	return messages
+}
+
+func (q *toastQueue) notify() {
+	select {
+	case q.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (item *toastItem) stop() {
+	if item.timer != nil {
+		item.timer.Stop()
+	}
+}
+
+// ShowToast shows text until it is dismissed, with the severity its color
+// tag implies.
+func (n *notification) ShowToast(text string) *ToastHandle {
+	return n.ShowToastLevel(severityFromText(text), text, 0)
+}
+
+// ShowToastWithTimeout shows text for d, with the severity its color tag
+// implies.
+func (n *notification) ShowToastWithTimeout(text string, d time.Duration) *ToastHandle {
+	return n.ShowToastLevel(severityFromText(text), text, d)
+}
+
+// ShowToastLevel shows text at severity for d, or until it is dismissed when
+// d is 0. The returned handle dismisses this toast only.
+func (n *notification) ShowToastLevel(severity NotificationSeverity, text string, d time.Duration) *ToastHandle {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	n.record(severity, text)
+	return n.toasts.push(severity, text, d)
+}
+
+// CancelToast dismisses the toasts below the error severity, for a view
+// that replaces what they were about. Errors stay until they time out or are
+// dismissed.
+func (n *notification) CancelToast() {
+	n.toasts.remove(func(item *toastItem) bool { return item.severity < SeverityError })
+}
+
+// DismissToasts dismisses every toast, errors included.
+func (n *notification) DismissToasts() {
+	n.toasts.remove(func(*toastItem) bool { return true })
+}
+
+// Toasts returns the toasts on display, newest first.
+func (n *notification) Toasts() []ToastMessage {
+	return n.toasts.messages()
+}
+
+// ToastsChanged fires when a toast is shown or goes away.
+func (n *notification) ToastsChanged() <-chan struct{} {
+	return n.toasts.changed
+}
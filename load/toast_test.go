@@ -0,0 +1,76 @@
+package load
+
+import (
+	"testing"
+	"time"
+)
+
+func toastTexts(n *notification) []string {
+	var texts []string
+	for _, toast := range n.Toasts() {
+		texts = append(texts, toast.Text)
+	}
+	return texts
+}
+
+func TestToastQueue(t *testing.T) {
+	n := &notification{toasts: newToastQueue(3)}
+
+	failed := n.ShowToastLevel(SeverityError, "failed", 0)
+	n.ShowToast("first")
+	n.ShowToast("second")
+	n.ShowToast("third")
+
+	// The oldest info toast makes room, not the older error.
+	if got := toastTexts(n); len(got) != 3 || got[0] != "third" || got[1] != "second" || got[2] != "failed" {
+		t.Fatalf("toasts %q", got)
+	}
+
+	n.CancelToast()
+	if got := toastTexts(n); len(got) != 1 || got[0] != "failed" {
+		t.Fatalf("CancelToast left %q, want the error only", got)
+	}
+
+	other := n.ShowToast("other")
+	failed.Dismiss()
+	failed.Dismiss()
+	if got := toastTexts(n); len(got) != 1 || got[0] != "other" {
+		t.Fatalf("Dismiss left %q", got)
+	}
+
+	// The handle of a replaced toast no longer dismisses the new one.
+	n.ShowToast("other")
+	other.Dismiss()
+	if got := toastTexts(n); len(got) != 1 {
+		t.Fatalf("stale handle dismissed %q", got)
+	}
+
+	n.DismissToasts()
+	if got := toastTexts(n); len(got) != 0 {
+		t.Fatalf("DismissToasts left %q", got)
+	}
+}
+
+func TestToastTimeout(t *testing.T) {
+	n := &notification{toasts: newToastQueue(3)}
+
+	n.ShowToastWithTimeout("[red:-:-]Error:[-:-:-] boom", 20*time.Millisecond)
+	toasts := n.Toasts()
+	if len(toasts) != 1 || toasts[0].Severity != SeverityError {
+		t.Fatalf("toasts %+v, want one error", toasts)
+	}
+
+	select {
+	case <-n.ToastsChanged():
+	default:
+		t.Fatal("no change notified")
+	}
+	select {
+	case <-n.ToastsChanged():
+	case <-time.After(time.Second):
+		t.Fatal("toast did not time out")
+	}
+	if len(n.Toasts()) != 0 {
+		t.Fatal("expired toast still shown")
+	}
+}
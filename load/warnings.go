@@ -0,0 +1,82 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// warningBoard holds the conditions that stay on the footer until they are
+// resolved, keyed by the monitor that raised them.
+type warningBoard struct {
+	mu      sync.Mutex
+	entries map[string]string
+	changed chan struct{}
+}
+
+func newWarningBoard() *warningBoard {
+	return &warningBoard{
+		entries: make(map[string]string),
+		changed: make(chan struct{}, 1),
+	}
+}
+
+func (b *warningBoard) set(key, text string) {
+	b.mu.Lock()
+	if b.entries[key] == text {
+		b.mu.Unlock()
+		return
+	}
+	if text == "" {
+		delete(b.entries, key)
+	} else {
+		b.entries[key] = text
+	}
+	b.mu.Unlock()
+
+	select {
+	case b.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (b *warningBoard) text() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	keys := make([]string, 0, len(b.entries))
+	for key := range b.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, b.entries[key])
+	}
+	return strings.Join(parts, " [gray::]│[-::] ")
+}
+
+// SetWarning shows text on the footer whenever no toast is displayed, until
+// it is cleared with ClearWarning.
+func (n *notification) SetWarning(key, text string) {
+	n.warnings.set(key, text)
+}
+
+func (n *notification) ClearWarning(key string) {
+	n.warnings.set(key, "")
+}
+
+// Warning returns the active warnings joined for display, or "".
+func (n *notification) Warning() string {
+	return n.warnings.text()
+}
+
+// WarningsChanged fires when a warning is raised or cleared.
+func (n *notification) WarningsChanged() <-chan struct{} {
+	return n.warnings.changed
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flokiorg/go-flokicoin/chainutil"
+
+	"github.com/flokiorg/twallet/flnd"
+	. "github.com/flokiorg/twallet/shared"
+)
+
+const (
+	watchAddressesOption = "watch.addresses"
+	watchRetryDelay      = 30 * time.Second
+	maxWatchActivity     = 50
+)
+
+// WatchSpec is a watched address and the block height it is followed from.
+type WatchSpec struct {
+	Address string
+	Since   uint32
+}
+
+// ParseWatchList reads the watch.addresses option: comma separated addresses,
+// each optionally followed by @height.
+func ParseWatchList(value string) ([]WatchSpec, error) {
+	var specs []WatchSpec
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		address, height, found := strings.Cut(item, "@")
+		spec := WatchSpec{Address: strings.TrimSpace(address)}
+		if found {
+			since, err := strconv.ParseUint(strings.TrimSpace(height), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid watch height in %q", item)
+			}
+			spec.Since = uint32(since)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// FormatWatchList is the inverse of ParseWatchList.
+func FormatWatchList(specs []WatchSpec) string {
+	items := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		items = append(items, fmt.Sprintf("%s@%d", spec.Address, spec.Since))
+	}
+	return strings.Join(items, ",")
+}
+
+// WatchedAddress is a snapshot of a watched address and what was seen on it,
+// newest activity first.
+type WatchedAddress struct {
+	WatchSpec
+	Activity []flnd.AddressActivity
+	Received chainutil.Amount
+	Spent    chainutil.Amount
+	Err      error
+}
+
+type watchEntry struct {
+	WatchedAddress
+	address chainutil.Address
+	cancel  context.CancelFunc
+	seen    map[string]struct{}
+}
+
+// AddressWatcher follows addresses the wallet does not own, such as a cold
+// storage address, and raises a notification when they receive or spend
+// funds. The list is kept in the config file.
+type AddressWatcher struct {
+	l *Load
+
+	mu        sync.Mutex
+	entries   []*watchEntry
+	listeners map[int]func()
+	nextID    int
+}
+
+func newAddressWatcher(l *Load) *AddressWatcher {
+	w := &AddressWatcher{
+		l:         l,
+		listeners: make(map[int]func()),
+	}
+
+	specs, err := ParseWatchList(l.AppConfig.WatchAddresses)
+	if err != nil {
+		l.Logger.Warn().Err(err).Msg("ignoring watched addresses")
+		return w
+	}
+	for _, spec := range specs {
+		address, err := chainutil.DecodeAddress(spec.Address, l.AppConfig.Network)
+		if err != nil {
+			l.Logger.Warn().Err(err).Str("address", spec.Address).Msg("ignoring watched address")
+			continue
+		}
+		w.start(spec, address)
+	}
+
+	return w
+}
+
+// Add starts watching address from the current tip and saves the list.
+func (w *AddressWatcher) Add(text string) error {
+	text = strings.TrimSpace(text)
+	address, err := chainutil.DecodeAddress(text, w.l.AppConfig.Network)
+	if err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+	if !address.IsForNet(w.l.AppConfig.Network) {
+		return errors.New("address is for another network")
+	}
+
+	w.mu.Lock()
+	for _, e := range w.entries {
+		if e.Address == address.String() {
+			w.mu.Unlock()
+			return errors.New("address is already watched")
+		}
+	}
+	w.mu.Unlock()
+
+	since := uint32(0)
+	if tip := w.l.Cache.GetTipHeight(); tip > 0 {
+		since = uint32(tip)
+	}
+	w.start(WatchSpec{Address: address.String(), Since: since}, address)
+
+	return w.save()
+}
+
+// Remove stops watching address and saves the list.
+func (w *AddressWatcher) Remove(address string) error {
+	w.mu.Lock()
+	for i, e := range w.entries {
+		if e.Address == address {
+			e.cancel()
+			w.entries = append(w.entries[:i], w.entries[i+1:]...)
+			break
+		}
+	}
+	w.mu.Unlock()
+
+	w.changed()
+	return w.save()
+}
+
+// List returns the watched addresses in the order they were added.
+func (w *AddressWatcher) List() []WatchedAddress {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	out := make([]WatchedAddress, 0, len(w.entries))
+	for _, e := range w.entries {
+		snapshot := e.WatchedAddress
+		snapshot.Activity = append([]flnd.AddressActivity(nil), e.Activity...)
+		out = append(out, snapshot)
+	}
+	return out
+}
+
+// OnChange registers fn to run whenever the list or its activity changes. It
+// is called from the watcher goroutines; the returned func unregisters it.
+func (w *AddressWatcher) OnChange(fn func()) func() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.nextID
+	w.nextID++
+	w.listeners[id] = fn
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.listeners, id)
+	}
+}
+
+func (w *AddressWatcher) changed() {
+	w.mu.Lock()
+	listeners := make([]func(), 0, len(w.listeners))
+	for _, fn := range w.listeners {
+		listeners = append(listeners, fn)
+	}
+	w.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
+func (w *AddressWatcher) save() error {
+	w.mu.Lock()
+	specs := make([]WatchSpec, 0, len(w.entries))
+	for _, e := range w.entries {
+		specs = append(specs, e.WatchSpec)
+	}
+	w.mu.Unlock()
+
+	value := FormatWatchList(specs)
+	w.l.AppConfig.WatchAddresses = value
+	return w.l.AppConfig.SaveOption(watchAddressesOption, value)
+}
+
+func (w *AddressWatcher) start(spec WatchSpec, address chainutil.Address) {
+	ctx, cancel := context.WithCancel(context.Background())
+	entry := &watchEntry{
+		WatchedAddress: WatchedAddress{WatchSpec: spec},
+		address:        address,
+		cancel:         cancel,
+		seen:           make(map[string]struct{}),
+	}
+
+	w.mu.Lock()
+	w.entries = append(w.entries, entry)
+	w.mu.Unlock()
+	w.changed()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-w.l.Notif.stop:
+			cancel()
+		}
+	}()
+
+	go w.run(ctx, entry)
+}
+
+// run keeps the watch registered, starting over from the watch height after a
+// failure. Activity already reported is recognised and skipped.
+func (w *AddressWatcher) run(ctx context.Context, e *watchEntry) {
+	for {
+		// Only activity confirmed after this point is news, anything older is
+		// history found while catching up.
+		tip := w.l.Cache.GetTipHeight()
+
+		err := w.l.Wallet.WatchAddress(ctx, e.address, e.Since, func(activity flnd.AddressActivity) {
+			w.record(e, activity, int64(activity.BlockHeight) > int64(tip) && tip > 0)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		w.mu.Lock()
+		e.Err = err
+		w.mu.Unlock()
+		w.changed()
+		if err != nil && !errors.Is(err, flnd.ErrDaemonNotRunning) {
+			w.l.Logger.Warn().Err(err).Str("address", e.Address).Msg("address watch interrupted")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
+
+func (w *AddressWatcher) record(e *watchEntry, activity flnd.AddressActivity, notify bool) {
+	key := fmt.Sprintf("%s:%t", activity.Txid, activity.Amount < 0)
+
+	w.mu.Lock()
+	if _, ok := e.seen[key]; ok {
+		w.mu.Unlock()
+		return
+	}
+	e.seen[key] = struct{}{}
+	e.Err = nil
+	if activity.Amount < 0 {
+		e.Spent += -activity.Amount
+	} else {
+		e.Received += activity.Amount
+	}
+	e.Activity = append([]flnd.AddressActivity{activity}, e.Activity...)
+	if len(e.Activity) > maxWatchActivity {
+		e.Activity = e.Activity[:maxWatchActivity]
+	}
+	w.mu.Unlock()
+
+	w.changed()
+
+	if !notify {
+		return
+	}
+	verb := "received"
+	amount := activity.Amount
+	if amount < 0 {
+		verb, amount = "spent", -amount
+	}
+	w.l.Notif.ShowToastWithTimeout(fmt.Sprintf("[yellow:-:-]👀 Watched %s %s %s", e.Address, verb, FormatAmountView(amount, 8)), time.Minute)
+}
@@ -0,0 +1,29 @@
+package load
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWatchList(t *testing.T) {
+	specs, err := ParseWatchList(" addr1@120 , addr2,, addr3@0 ")
+	if err != nil {
+		t.Fatalf("ParseWatchList: %v", err)
+	}
+	want := []WatchSpec{
+		{Address: "addr1", Since: 120},
+		{Address: "addr2"},
+		{Address: "addr3"},
+	}
+	if !reflect.DeepEqual(specs, want) {
+		t.Fatalf("ParseWatchList = %+v, want %+v", specs, want)
+	}
+
+	if got := FormatWatchList(specs); got != "addr1@120,addr2@0,addr3@0" {
+		t.Errorf("FormatWatchList = %q", got)
+	}
+
+	if _, err := ParseWatchList("addr1@tip"); err == nil {
+		t.Error("ParseWatchList accepted a non numeric height")
+	}
+}
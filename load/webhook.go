@@ -0,0 +1,90 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package load
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+const webhookTimeout = 15 * time.Second
+
+// AlertEvent is the JSON body posted to the alert webhook.
+type AlertEvent struct {
+	Kind    string            `json:"kind"`
+	Message string            `json:"message"`
+	Time    time.Time         `json:"time"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// webhook posts JSON events to a user supplied URL.
+type webhook struct {
+	url    string
+	client *http.Client
+}
+
+// newWebhook returns nil when url is empty, so callers can skip posting.
+// A nil transport is the default one.
+func newWebhook(url string, transport http.RoundTripper) *webhook {
+	if url == "" {
+		return nil
+	}
+	return &webhook{url: url, client: &http.Client{Timeout: webhookTimeout, Transport: transport}}
+}
+
+func (h *webhook) post(event any) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// webhookNotifier posts the raised and resolved alerts to the alert webhook.
+type webhookNotifier struct {
+	hook   *webhook
+	logger zerolog.Logger
+}
+
+func newWebhookNotifier(url string, transport http.RoundTripper, logger zerolog.Logger) *webhookNotifier {
+	return &webhookNotifier{hook: newWebhook(url, transport), logger: logger}
+}
+
+func (w *webhookNotifier) Notify(*flnd.Update) {}
+
+func (w *webhookNotifier) NotifyAlert(event AlertEvent) {
+	go func() {
+		if err := w.hook.post(event); err != nil {
+			w.logger.Warn().Err(err).Str("kind", event.Kind).Msg("alert webhook failed")
+		}
+	}()
+}
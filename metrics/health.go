@@ -0,0 +1,123 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+// HealthReport is the JSON body served on /healthz.
+type HealthReport struct {
+	Healthy      bool        `json:"healthy"`
+	Status       flnd.Status `json:"status"`
+	BlockHeight  uint32      `json:"block_height"`
+	SyncedHeight uint32      `json:"synced_height"`
+	LastError    string      `json:"last_error,omitempty"`
+	LastErrorAt  *time.Time  `json:"last_error_at,omitempty"`
+	UpdatedAt    *time.Time  `json:"updated_at,omitempty"`
+}
+
+// Health follows the service updates and answers liveness probes. Only a
+// daemon that is down or quitting is reported unhealthy; a locked or syncing
+// wallet is working as intended.
+type Health struct {
+	svc *flnd.Service
+
+	mu     sync.Mutex
+	report HealthReport
+}
+
+func NewHealth(svc *flnd.Service) *Health {
+	return &Health{svc: svc}
+}
+
+// Run follows the service until ctx is cancelled or the service stops.
+func (h *Health) Run(ctx context.Context) {
+	sub := h.svc.Subscribe()
+	defer h.svc.Unsubscribe(sub)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case u, ok := <-sub:
+			if !ok {
+				h.handleUpdate(&flnd.Update{State: flnd.StatusQuit})
+				return
+			}
+			h.handleUpdate(u)
+		}
+	}
+}
+
+func (h *Health) handleUpdate(u *flnd.Update) {
+	if u == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now().UTC()
+	h.report.Status = u.State
+	h.report.UpdatedAt = &now
+	if u.BlockHeight > 0 {
+		h.report.BlockHeight = u.BlockHeight
+	}
+	if u.SyncedHeight > 0 {
+		h.report.SyncedHeight = u.SyncedHeight
+	}
+	if u.Err != nil {
+		h.report.LastError = u.Err.Error()
+		h.report.LastErrorAt = &now
+	}
+}
+
+// Report returns a snapshot of the wallet health.
+func (h *Health) Report() HealthReport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	report := h.report
+	switch report.Status {
+	case "", flnd.StatusDown, flnd.StatusFailedPermanently, flnd.StatusQuit:
+		report.Healthy = false
+	default:
+		report.Healthy = true
+	}
+	return report
+}
+
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report := h.Report()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Healthy {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// StartHealth binds addr and serves /healthz for svc.
+func StartHealth(addr string, svc *flnd.Service, logger zerolog.Logger) (*Server, error) {
+	health := NewHealth(svc)
+	return serve(addr, "/healthz", health, health.Run, logger)
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+func TestHealthEndpoint(t *testing.T) {
+	h := NewHealth(nil)
+
+	get := func() (int, HealthReport) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		var report HealthReport
+		if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return rec.Code, report
+	}
+
+	if code, _ := get(); code != http.StatusServiceUnavailable {
+		t.Fatalf("status before any update = %d, want 503", code)
+	}
+
+	h.handleUpdate(&flnd.Update{State: flnd.StatusDown, Err: errors.New("boom")})
+	h.handleUpdate(&flnd.Update{State: flnd.StatusReady, BlockHeight: 42, SyncedHeight: 42})
+
+	code, report := get()
+	if code != http.StatusOK || !report.Healthy {
+		t.Fatalf("ready wallet reported %d %+v, want healthy", code, report)
+	}
+	if report.Status != flnd.StatusReady || report.SyncedHeight != 42 {
+		t.Errorf("report = %+v, want ready at 42", report)
+	}
+	if report.LastError != "boom" || report.LastErrorAt == nil {
+		t.Errorf("last error = %q at %v, want boom", report.LastError, report.LastErrorAt)
+	}
+
+	h.handleUpdate(&flnd.Update{State: flnd.StatusDown})
+	if code, _ := get(); code != http.StatusServiceUnavailable {
+		t.Fatalf("status when down = %d, want 503", code)
+	}
+}
@@ -0,0 +1,386 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+// Package metrics exposes the wallet state in the Prometheus text format so
+// tWallet instances can be scraped and put on a dashboard.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+const (
+	refreshInterval = 30 * time.Second
+	shutdownTimeout = 5 * time.Second
+	contentType     = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// Collector keeps the latest wallet figures. It follows the service updates
+// and queries the wallet again whenever a block or transaction comes in, so a
+// scrape never waits on the daemon.
+type Collector struct {
+	svc    *flnd.Service
+	logger zerolog.Logger
+
+	mu               sync.Mutex
+	state            flnd.Status
+	hasBalance       bool
+	confirmed        int64
+	unconfirmed      int64
+	locked           int64
+	hasTransactions  bool
+	incoming         int
+	outgoing         int
+	pending          int
+	blockHeight      uint32
+	syncedHeight     uint32
+	chainPeers       int
+	lightningPeers   int
+	hasPeers         bool
+	rpcErrors        map[string]uint64
+	daemonRestarts   uint64
+	daemonDownEvents uint64
+
+	refresh chan struct{}
+}
+
+func NewCollector(svc *flnd.Service, logger zerolog.Logger) *Collector {
+	return &Collector{
+		svc:        svc,
+		logger:     logger,
+		chainPeers: -1,
+		rpcErrors:  make(map[string]uint64),
+		refresh:    make(chan struct{}, 1),
+	}
+}
+
+// Run follows the service until ctx is cancelled or the service stops.
+func (c *Collector) Run(ctx context.Context) {
+	sub := c.svc.Subscribe()
+	defer c.svc.Unsubscribe(sub)
+
+	go c.refreshLoop(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case u, ok := <-sub:
+			if !ok {
+				return
+			}
+			if c.handleUpdate(u) {
+				c.requestRefresh()
+			}
+		}
+	}
+}
+
+// handleUpdate records a service update and reports whether the wallet
+// figures may have changed.
+func (c *Collector) handleUpdate(u *flnd.Update) bool {
+	if u == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch u.State {
+	case flnd.StatusNone:
+		// The service reports none each time it launches the daemon. The
+		// first update replays the current state, and none right after init
+		// is the initial launch.
+		if c.state != "" && c.state != flnd.StatusInit && c.state != flnd.StatusNone {
+			c.daemonRestarts++
+		}
+	case flnd.StatusDown:
+		c.daemonDownEvents++
+	}
+
+	c.state = u.State
+	if u.BlockHeight > 0 {
+		c.blockHeight = u.BlockHeight
+	}
+	if u.SyncedHeight > 0 {
+		c.syncedHeight = u.SyncedHeight
+	}
+
+	return walletReachable(u.State)
+}
+
+func walletReachable(state flnd.Status) bool {
+	switch state {
+	case flnd.StatusReady, flnd.StatusSyncing, flnd.StatusUnlocked,
+		flnd.StatusTransaction, flnd.StatusBlock, flnd.StatusScanning:
+		return true
+	}
+	return false
+}
+
+func (c *Collector) requestRefresh() {
+	select {
+	case c.refresh <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Collector) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.refresh:
+		case <-ticker.C:
+		}
+
+		c.mu.Lock()
+		reachable := walletReachable(c.state)
+		c.mu.Unlock()
+		if reachable {
+			c.collect(ctx)
+		}
+	}
+}
+
+func (c *Collector) collect(ctx context.Context) {
+	if balance, err := c.svc.Balance(ctx); err != nil {
+		c.recordError("balance", err)
+	} else {
+		c.mu.Lock()
+		c.hasBalance = true
+		c.confirmed = balance.GetConfirmedBalance()
+		c.unconfirmed = balance.GetUnconfirmedBalance()
+		c.locked = balance.GetLockedBalance()
+		c.mu.Unlock()
+	}
+
+	if txs, err := c.svc.FetchTransactionsWithOptions(ctx, flnd.FetchTransactionsOptions{IgnoreLimit: true}); err != nil {
+		c.recordError("transactions", err)
+	} else {
+		var incoming, outgoing, pending int
+		for _, tx := range txs {
+			if tx == nil {
+				continue
+			}
+			if tx.Amount >= 0 {
+				incoming++
+			} else {
+				outgoing++
+			}
+			if tx.NumConfirmations == 0 {
+				pending++
+			}
+		}
+		c.mu.Lock()
+		c.hasTransactions = true
+		c.incoming, c.outgoing, c.pending = incoming, outgoing, pending
+		c.mu.Unlock()
+	}
+
+	if info, err := c.svc.NetworkInfo(ctx); err != nil {
+		c.recordError("network_info", err)
+	} else {
+		c.mu.Lock()
+		c.hasPeers = true
+		c.chainPeers = info.ChainPeers
+		c.lightningPeers = info.LightningPeers
+		c.mu.Unlock()
+	}
+}
+
+func (c *Collector) recordError(method string, err error) {
+	c.mu.Lock()
+	c.rpcErrors[method]++
+	c.mu.Unlock()
+	c.logger.Debug().Err(err).Str("method", method).Msg("metrics collection failed")
+}
+
+// WriteTo renders the current figures in the Prometheus text format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &encoder{w: w}
+
+	e.family("twallet_wallet_state", "gauge", "Current wallet service state, 1 for the active one.")
+	if c.state != "" {
+		e.sample("twallet_wallet_state", fmt.Sprintf(`state=%q`, c.state), 1)
+	}
+
+	if c.hasBalance {
+		e.family("twallet_wallet_balance_loki", "gauge", "Wallet balance in loki.")
+		e.sample("twallet_wallet_balance_loki", `state="confirmed"`, c.confirmed)
+		e.sample("twallet_wallet_balance_loki", `state="unconfirmed"`, c.unconfirmed)
+		e.sample("twallet_wallet_balance_loki", `state="locked"`, c.locked)
+	}
+
+	if c.hasTransactions {
+		e.family("twallet_wallet_transactions", "gauge", "Number of wallet transactions.")
+		e.sample("twallet_wallet_transactions", `direction="incoming"`, c.incoming)
+		e.sample("twallet_wallet_transactions", `direction="outgoing"`, c.outgoing)
+		e.family("twallet_wallet_transactions_pending", "gauge", "Number of unconfirmed wallet transactions.")
+		e.sample("twallet_wallet_transactions_pending", "", c.pending)
+	}
+
+	e.family("twallet_chain_block_height", "gauge", "Height of the best known block.")
+	e.sample("twallet_chain_block_height", "", c.blockHeight)
+	e.family("twallet_chain_synced_height", "gauge", "Height the wallet is synced to.")
+	e.sample("twallet_chain_synced_height", "", c.syncedHeight)
+
+	if c.hasPeers {
+		e.family("twallet_peers", "gauge", "Number of connected peers.")
+		if c.chainPeers >= 0 {
+			e.sample("twallet_peers", `network="chain"`, c.chainPeers)
+		}
+		e.sample("twallet_peers", `network="lightning"`, c.lightningPeers)
+	}
+
+	e.family("twallet_rpc_errors_total", "counter", "Failed wallet RPC calls made by the metrics collector.")
+	methods := make([]string, 0, len(c.rpcErrors))
+	for method := range c.rpcErrors {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		e.sample("twallet_rpc_errors_total", fmt.Sprintf(`method=%q`, method), c.rpcErrors[method])
+	}
+
+	e.family("twallet_daemon_restarts_total", "counter", "Number of times the wallet daemon was restarted.")
+	e.sample("twallet_daemon_restarts_total", "", c.daemonRestarts)
+	e.family("twallet_daemon_down_total", "counter", "Number of times the wallet daemon reported down.")
+	e.sample("twallet_daemon_down_total", "", c.daemonDownEvents)
+
+	if c.svc != nil {
+		writeRPCStats(e, c.svc.RPCStats())
+	}
+
+	return e.n, e.err
+}
+
+// writeRPCStats renders the timing of the daemon RPC calls made by the whole
+// wallet, by method.
+func writeRPCStats(e *encoder, stats []flnd.RPCStat) {
+	if len(stats) == 0 {
+		return
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Method < stats[j].Method })
+
+	e.family("twallet_daemon_rpc_duration_seconds", "summary", "Time spent in daemon RPC calls.")
+	for _, stat := range stats {
+		labels := fmt.Sprintf(`method=%q`, stat.Method)
+		e.sample("twallet_daemon_rpc_duration_seconds_sum", labels, stat.Total.Seconds())
+		e.sample("twallet_daemon_rpc_duration_seconds_count", labels, stat.Calls)
+	}
+	e.family("twallet_daemon_rpc_duration_seconds_max", "gauge", "Longest daemon RPC call.")
+	for _, stat := range stats {
+		e.sample("twallet_daemon_rpc_duration_seconds_max", fmt.Sprintf(`method=%q`, stat.Method), stat.Max.Seconds())
+	}
+	e.family("twallet_daemon_rpc_failures_total", "counter", "Failed daemon RPC calls.")
+	for _, stat := range stats {
+		e.sample("twallet_daemon_rpc_failures_total", fmt.Sprintf(`method=%q`, stat.Method), stat.Errors)
+	}
+}
+
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = c.WriteTo(w)
+}
+
+type encoder struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (e *encoder) printf(format string, args ...any) {
+	if e.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(e.w, format, args...)
+	e.n += int64(n)
+	e.err = err
+}
+
+func (e *encoder) family(name, kind, help string) {
+	e.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (e *encoder) sample(name, labels string, value any) {
+	if labels != "" {
+		e.printf("%s{%s} %v\n", name, labels, value)
+		return
+	}
+	e.printf("%s %v\n", name, value)
+}
+
+// Server serves one of the monitoring handlers over HTTP.
+type Server struct {
+	http   *http.Server
+	cancel context.CancelFunc
+}
+
+// Start binds addr and serves /metrics, collecting from svc. Binding happens
+// before returning so a wrong address is reported to the caller.
+func Start(addr string, svc *flnd.Service, logger zerolog.Logger) (*Server, error) {
+	collector := NewCollector(svc, logger)
+	return serve(addr, "/metrics", collector, collector.Run, logger)
+}
+
+func serve(addr, pattern string, handler http.Handler, run func(context.Context), logger zerolog.Logger) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("%s listener: %w", pattern, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle(pattern, handler)
+
+	s := &Server{
+		http: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		cancel: cancel,
+	}
+
+	go func() {
+		if err := s.http.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error().Err(err).Str("addr", addr).Msg("monitoring server stopped")
+		}
+	}()
+
+	logger.Info().Str("addr", ln.Addr().String()).Str("path", pattern).Msg("serving monitoring endpoint")
+	return s, nil
+}
+
+func (s *Server) Stop() {
+	s.cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	_ = s.http.Shutdown(ctx)
+}
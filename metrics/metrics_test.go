@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+func TestCollectorExposition(t *testing.T) {
+	c := NewCollector(nil, zerolog.Nop())
+
+	updates := []*flnd.Update{
+		{State: flnd.StatusInit},
+		{State: flnd.StatusNone},
+		{State: flnd.StatusSyncing, BlockHeight: 120, SyncedHeight: 100},
+		{State: flnd.StatusDown},
+		{State: flnd.StatusNone},
+		{State: flnd.StatusReady, BlockHeight: 121, SyncedHeight: 121},
+	}
+	for _, u := range updates {
+		c.handleUpdate(u)
+	}
+	c.rpcErrors["balance"] = 2
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		`twallet_wallet_state{state="ready"} 1`,
+		"twallet_chain_block_height 121",
+		"twallet_chain_synced_height 121",
+		`twallet_rpc_errors_total{method="balance"} 2`,
+		"twallet_daemon_restarts_total 1",
+		"twallet_daemon_down_total 1",
+		"# TYPE twallet_daemon_restarts_total counter",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("exposition missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "twallet_wallet_balance_loki") {
+		t.Errorf("balance exported before it was collected:\n%s", body)
+	}
+}
+
+func TestWriteRPCStats(t *testing.T) {
+	var b strings.Builder
+	e := &encoder{w: &b}
+	writeRPCStats(e, []flnd.RPCStat{
+		{Method: "WalletKit/EstimateFee", Calls: 1, Total: 10 * time.Millisecond, Max: 10 * time.Millisecond},
+		{Method: "Lightning/GetTransactions", Calls: 4, Errors: 1, Total: 2 * time.Second, Max: 1500 * time.Millisecond},
+	})
+
+	body := b.String()
+	for _, want := range []string{
+		"# TYPE twallet_daemon_rpc_duration_seconds summary",
+		`twallet_daemon_rpc_duration_seconds_sum{method="Lightning/GetTransactions"} 2`,
+		`twallet_daemon_rpc_duration_seconds_count{method="Lightning/GetTransactions"} 4`,
+		`twallet_daemon_rpc_duration_seconds_max{method="Lightning/GetTransactions"} 1.5`,
+		`twallet_daemon_rpc_failures_total{method="Lightning/GetTransactions"} 1`,
+		`twallet_daemon_rpc_failures_total{method="WalletKit/EstimateFee"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("exposition missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Index(body, "Lightning/") > strings.Index(body, "WalletKit/") {
+		t.Errorf("methods not sorted by name:\n%s", body)
+	}
+}
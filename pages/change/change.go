@@ -5,6 +5,7 @@
 package change
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -45,7 +46,7 @@ func NewPage(l *load.Load) *Change {
 	logo.SetBorder(false)
 
 	fmt.Fprintf(logo, "[%s:-:-]\n%s[-:-:-]\n", netColor, shared.LOCK_IMAGE)
-	fmt.Fprintf(logo, "Tap [[%s:-:-]u[-:-:-]] to Change", tcell.ColorLightSkyBlue)
+	fmt.Fprintf(logo, "Tap [[%s:-:-]u[-:-:-]] to Change", shared.CurrentTheme().Accent)
 
 	hFlex := tview.NewFlex().
 		AddItem(nil, 0, 1, false).
@@ -95,40 +96,34 @@ func (c *Change) showChangeForm() {
 
 	var isBusy bool
 
+	oldPassField := components.NewPasswordField("Current passphrase:", c.load.AppConfig.DefaultPassword)
+	newPassField := components.NewPasswordField("New passphrase:", c.load.AppConfig.DefaultPassword)
+	confirmField := components.NewPasswordField("Confirm passphrase:", c.load.AppConfig.DefaultPassword)
+
+	validator := components.NewFormValidator()
+
 	form := tview.NewForm()
 	form.SetBorderPadding(1, 1, 2, 3).SetBackgroundColor(tcell.ColorDefault)
-	form.AddPasswordField("Current passphrase:", c.load.AppConfig.DefaultPassword, 0, '*', nil).
-		AddPasswordField("New passphrase:", c.load.AppConfig.DefaultPassword, 0, '*', nil).
-		AddPasswordField("Confirm passphrase:", c.load.AppConfig.DefaultPassword, 0, '*', nil).
+	form.AddFormItem(validator.Field(oldPassField, components.PasswordPolicy())).
+		AddFormItem(validator.Field(newPassField, components.PasswordPolicy())).
+		AddFormItem(validator.Field(confirmField, components.Matches(newPassField.GetText, "passwords do not match"))).
 		AddButton("Cancel", c.closeModal).
 		AddButton("OK", func() {
 			if isBusy {
 				return
 			}
 
-			oldPassField := form.GetFormItem(0).(*tview.InputField)
-			newPassField := form.GetFormItem(1).(*tview.InputField)
-			confirmField := form.GetFormItem(2).(*tview.InputField)
-
-			oldPass := oldPassField.GetText()
-			newPass := newPassField.GetText()
-			confirmPass := confirmField.GetText()
-
-			if err := c.validateOldPasswordField(oldPass); err != nil {
-				c.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]error:[-:-:-] %s", err.Error()), time.Second*30)
-				c.load.QueueUpdateDraw(func() { c.load.Application.SetFocus(oldPassField) })
+			if item, err := validator.Validate(); err != nil {
+				c.load.Application.SetFocus(item)
 				return
 			}
 
-			if err := c.validateChangePasswordFields(newPass, confirmPass); err != nil {
-				c.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]error:[-:-:-] %s", err.Error()), time.Second*30)
-				c.load.QueueUpdateDraw(func() { c.load.Application.SetFocus(oldPassField) })
-				return
-			}
+			oldPass := oldPassField.GetText()
+			newPass := newPassField.GetText()
 
 			isBusy = true
 			c.load.Notif.CancelToast()
-			c.load.Notif.ShowToast("🔒 updating...")
+			updating := c.load.Notif.ShowToast("🔒 updating...")
 
 			oldPassText := oldPass
 			newPassText := newPass
@@ -136,9 +131,14 @@ func (c *Change) showChangeForm() {
 
 			go func() {
 				defer func() { isBusy = false }()
+				defer updating.Dismiss()
 
-				if err := c.load.Wallet.ChangePassphrase(oldPassText, newPassText); err != nil {
-					c.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]error:[-:-:-] %s", err.Error()), time.Second*30)
+				if err := c.load.Wallet.ChangePassphrase(context.Background(), oldPassText, newPassText); err != nil {
+					message := err.Error()
+					if errors.Is(err, flnd.ErrInvalidPassphrase) {
+						message = "current passphrase is incorrect"
+					}
+					c.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]error:[-:-:-] %s", message), time.Second*30)
 					c.load.QueueUpdateDraw(func() { c.load.Application.SetFocus(focusField) })
 					return
 				}
@@ -209,29 +209,10 @@ func (c *Change) showChangeForm() {
 		AddItem(form, 0, 1, true)
 
 	view.SetTitle("🔒 Change Password").
-		SetTitleColor(tcell.ColorGray).
-		SetBackgroundColor(tcell.ColorOrange).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
 		SetBorder(true)
 
-	c.nav.ShowModal(components.NewModal(view, 50, 18, c.nav.CloseModal))
-
-}
-
-func (c *Change) validateOldPasswordField(oldPass string) error {
-	if len(oldPass) < shared.MinPasswordLength {
-		return fmt.Errorf("old password must be at least %d characters", shared.MinPasswordLength)
-	}
-	return nil
-}
-
-func (c *Change) validateChangePasswordFields(pass, passConf string) error {
-	if len(pass) < shared.MinPasswordLength {
-		return fmt.Errorf("new password must be at least %d characters", shared.MinPasswordLength)
-	}
-
-	if pass != passConf {
-		return errors.New("passwords do not match")
-	}
+	c.nav.ShowModal(components.NewModal(view, 50, 21, c.nav.CloseModal))
 
-	return nil
 }
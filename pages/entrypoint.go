@@ -5,6 +5,8 @@
 package pages
 
 import (
+	"context"
+
 	"github.com/rivo/tview"
 
 	"github.com/flokiorg/twallet/load"
@@ -47,7 +49,7 @@ func (r *Router) Go(p Page) {
 func NewEntrypoint(l *load.Load) tview.Primitive {
 
 	var page tview.Primitive
-	exists, err := l.Wallet.WalletExists()
+	exists, err := l.Wallet.WalletExists(context.Background())
 	if err != nil {
 		l.Logger.Error().Err(err).Msg("failed")
 	}
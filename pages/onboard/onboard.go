@@ -6,7 +6,10 @@ package onboard
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,6 +17,7 @@ import (
 
 	"github.com/flokiorg/twallet/components"
 	"github.com/flokiorg/twallet/flnd"
+	"github.com/flokiorg/twallet/i18n"
 	"github.com/flokiorg/twallet/load"
 	"github.com/flokiorg/twallet/shared"
 	. "github.com/flokiorg/twallet/shared"
@@ -23,18 +27,30 @@ import (
 const (
 	NewWalletView string = "new"
 	RestoreView   string = "restore"
-	CipherView    string = "cipher"
+	WizardView    string = "wizard"
 	ToastView     string = "toast"
 )
 
+// The onboarding steps: the wallet is created or restored, its cipher card
+// shown, then a few of its words asked back.
+const (
+	walletStep = iota
+	backupStep
+	verifyStep
+)
+
+const seedQuizWords = 3
+
 type Onboard struct {
 	*tview.Flex
 	load      *load.Load
 	nav       *load.Navigator
 	view      string
 	switchBtn *components.Switch
+	wizard    *components.Wizard
 
 	pages     *tview.Pages
+	forms     *tview.Pages
 	restoring bool
 }
 
@@ -45,6 +61,7 @@ func NewPage(l *load.Load) *Onboard {
 		nav:   l.Nav,
 		view:  NewWalletView,
 		pages: tview.NewPages(),
+		forms: tview.NewPages(),
 	}
 
 	netColor := NetworkColor(*l.AppConfig.Network)
@@ -54,19 +71,30 @@ func NewPage(l *load.Load) *Onboard {
 		SetTitleColor(netColor).
 		SetBorderColor(netColor)
 
-	p.switchBtn = components.NewSwitch(p.nav, "New Wallet", "Restore wallet", 0, func(index int) {
+	p.switchBtn = components.NewSwitch(p.nav, i18n.T("onboard.new_wallet"), i18n.T("onboard.restore_wallet"), 0, func(index int) {
 		switch index {
 		case 0:
-			p.pages.SwitchToPage(NewWalletView)
+			p.forms.SwitchToPage(NewWalletView)
 		case 1:
-			p.pages.SwitchToPage(RestoreView)
+			p.forms.SwitchToPage(RestoreView)
 		}
 	})
 
-	p.pages = tview.NewPages().
-		AddPage(NewWalletView, p.buildNewWalletForm(), true, false).
+	p.forms.AddPage(NewWalletView, p.buildNewWalletForm(), true, false).
 		AddPage(RestoreView, p.buildRestoreForm(), true, false)
 
+	p.wizard = components.NewWizard(l.Application,
+		components.WizardStep{Title: i18n.T("onboard.step_wallet"), Content: p.forms},
+		components.WizardStep{Title: i18n.T("onboard.step_backup"), NoBack: true},
+		components.WizardStep{Title: i18n.T("onboard.step_verify")},
+	).
+		SetDoneFunc(p.finishOnboarding).
+		SetErrorFunc(func(err error) {
+			p.nav.ShowModal(components.ErrorModal(err.Error(), p.nav.CloseModal))
+		})
+
+	p.pages.AddPage(WizardView, p.wizard, true, true)
+
 	p.AddItem(p.pages, 0, 1, true)
 	return p
 }
@@ -80,53 +108,87 @@ func (p *Onboard) showCipherCard(phex string, words []string) error {
 	if err != nil {
 		return err
 	}
-	p.pages.RemovePage(CipherView).AddAndSwitchToPage(CipherView, view, true)
+	p.pages.RemovePage(ToastView).SwitchToPage(WizardView)
+	p.wizard.SetStepContent(backupStep, view).Next()
 	return nil
 }
 
 func (p *Onboard) buildRestoreForm() tview.Primitive {
 
 	form := tview.NewForm()
-	form.AddDropDown("From: ", []string{" Mnemonic ", " Hex "}, 0, func(label string, i int) {
-		if form.GetFormItemCount() == 0 {
+
+	seedType := MNEMONIC
+	mnemonicValid := false
+
+	mnemonicField := components.NewMnemonicInput(i18n.T("onboard.mnemonic"))
+	mnemonicStatus := tview.NewTextView().
+		SetDynamicColors(true).
+		SetSize(1, 0)
+	mnemonicField.SetStatusFunc(func(status string, valid bool) {
+		mnemonicStatus.SetText(status)
+		mnemonicValid = valid
+	})
+
+	hexField := tview.NewTextArea().
+		SetLabel(i18n.T("onboard.hex")).
+		SetSize(tview.DefaultFormFieldHeight, 0)
+
+	passField := components.NewPasswordField(i18n.T("onboard.spending_pass"), p.load.AppConfig.DefaultPassword)
+	confField := components.NewPasswordField(i18n.T("onboard.confirm_pass"), p.load.AppConfig.DefaultPassword)
+	validator, passItem, confItem := passwordValidator(passField, confField)
+
+	dropdown := tview.NewDropDown().
+		SetLabel(i18n.T("onboard.from")).
+		SetOptions([]string{" " + strings.TrimSpace(i18n.T("onboard.mnemonic")) + " ", " " + strings.TrimSpace(i18n.T("onboard.hex")) + " "}, nil).
+		SetCurrentOption(int(MNEMONIC))
+
+	// The seed field depends on the selected source, so the items are laid
+	// out again whenever it changes.
+	layout := func() {
+		form.Clear(false)
+		form.AddFormItem(dropdown)
+		if seedType == MNEMONIC {
+			form.AddFormItem(mnemonicField).AddFormItem(mnemonicStatus)
+		} else {
+			form.AddFormItem(hexField)
+		}
+		form.AddFormItem(passItem).AddFormItem(confItem)
+	}
+	layout()
+
+	dropdown.SetSelectedFunc(func(_ string, index int) {
+		if SeedType(index) == seedType {
 			return
 		}
-		seedField := form.GetFormItem(1).(*tview.TextArea)
-		switch strings.TrimSpace(strings.ToLower(label)) {
-		case "mnemonic":
-			seedField.SetLabel("Mnemonic: ")
-		case "hex":
-			seedField.SetLabel("Hex: ")
+		seedType = SeedType(index)
+		layout()
+		form.SetFocus(1)
+	})
+
+	form.AddButton(i18n.T("onboard.restore"), func() {
+		if item, err := validator.Validate(); err != nil {
+			p.load.Application.SetFocus(item)
+			return
 		}
-	}).
-		AddTextArea("Mnemonic: ", "", 0, 0, 0, nil).
-		AddPasswordField("Spending passphrase: ", p.load.AppConfig.DefaultPassword, 0, '*', nil).
-		AddPasswordField("Confirm passphrase: ", p.load.AppConfig.DefaultPassword, 0, '*', nil).
-		AddButton("Restore", func() {
-
-			dropdown := form.GetFormItem(0).(*tview.DropDown)
-			seedField := form.GetFormItem(1).(*tview.TextArea)
-			passField := form.GetFormItem(2).(*tview.InputField)
-			confField := form.GetFormItem(3).(*tview.InputField)
-
-			fromIndex, _ := dropdown.GetCurrentOption()
-			seedText := seedField.GetText()
-			pass := passField.GetText()
-			passConf := confField.GetText()
-
-			if err := p.validateFields(pass, passConf); err != nil {
-				p.nav.ShowModal(components.ErrorModal(err.Error(), p.nav.CloseModal))
+		pass := passField.GetText()
+
+		seedText := hexField.GetText()
+		if seedType == MNEMONIC {
+			if !mnemonicValid {
+				p.nav.ShowModal(components.ErrorModal(i18n.T("onboard.invalid_mnemonic", mnemonicStatus.GetText(true)), p.nav.CloseModal))
 				return
 			}
+			seedText = mnemonicField.GetText()
+		}
 
-			p.showToast("⚡ restoring...")
-			go p.restoreWallet(SeedType(fromIndex), seedText, pass)
-		})
+		p.showToast(i18n.T("onboard.restoring"))
+		go p.restoreWallet(seedType, seedText, pass)
+	})
 
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(tview.NewBox(), 0, 1, false).
 		AddItem(p.switchBtn, 5, 0, false).
-		AddItem(form, 17, 0, true).
+		AddItem(form, 19, 0, true).
 		AddItem(tview.NewBox(), 0, 1, false)
 
 	mainFlex := tview.NewFlex().
@@ -139,20 +201,21 @@ func (p *Onboard) buildRestoreForm() tview.Primitive {
 
 func (p *Onboard) buildNewWalletForm() tview.Primitive {
 
+	passField := components.NewPasswordField(i18n.T("onboard.lock_pass"), p.load.AppConfig.DefaultPassword)
+	confField := components.NewPasswordField(i18n.T("onboard.confirm_lock_pass"), p.load.AppConfig.DefaultPassword)
+	validator, passItem, confItem := passwordValidator(passField, confField)
+
 	form := tview.NewForm()
-	form.AddPasswordField("Lock passphrase: ", p.load.AppConfig.DefaultPassword, 0, '*', nil).
-		AddPasswordField("Confirm lock passphrase: ", p.load.AppConfig.DefaultPassword, 0, '*', nil).
-		AddButton("Continue", func() {
-			pass := form.GetFormItem(0).(*tview.InputField).GetText()
-			passConf := form.GetFormItem(1).(*tview.InputField).GetText()
-
-			if err := p.validateFields(pass, passConf); err != nil {
-				p.nav.ShowModal(components.ErrorModal(err.Error(), p.nav.CloseModal))
+	form.AddFormItem(passItem).
+		AddFormItem(confItem).
+		AddButton(i18n.T("onboard.continue"), func() {
+			if item, err := validator.Validate(); err != nil {
+				p.load.Application.SetFocus(item)
 				return
 			}
 
-			p.showToast("⚡ creating...")
-			go p.createWallet(pass)
+			p.showToast(i18n.T("onboard.creating"))
+			go p.createWallet(passField.GetText())
 		})
 
 	flex := tview.NewFlex().SetDirection(tview.FlexRow).
@@ -179,30 +242,32 @@ func (p *Onboard) restoreWallet(seedType SeedType, seedText, pass string) {
 	switch seedType {
 	case HEX:
 		phex = seedText
-		words, err = p.load.Wallet.RestoreByEncipheredSeed(phex, pass)
+		words, err = p.load.Wallet.RestoreByEncipheredSeed(context.Background(), phex, pass)
 
 	case MNEMONIC:
-		words = extractSeedWords(seedText)
-		phex, err = p.load.Wallet.RestoreByMnemonic(words, pass)
+		words, err = i18n.CanonicalMnemonic(extractSeedWords(seedText))
+		if err == nil {
+			phex, err = p.load.Wallet.RestoreByMnemonic(context.Background(), words, pass)
+		}
 
 	default:
 		err = fmt.Errorf("unexpected choice")
 	}
 
 	if err != nil {
-		err = fmt.Errorf("failed to restore: %v", err)
+		err = fmt.Errorf(i18n.T("onboard.restore_failed"), err)
 	}
 
 	p.load.QueueUpdateDraw(func() {
 		if err != nil {
-			p.pages.SwitchToPage(RestoreView)
+			p.pages.SwitchToPage(WizardView)
 			p.nav.ShowModal(components.ErrorModal(err.Error(), p.nav.CloseModal))
 			p.restoring = false
 			return
 		}
 		p.restoring = true
 		if err := p.showCipherCard(phex, words); err != nil {
-			p.pages.SwitchToPage(RestoreView)
+			p.pages.SwitchToPage(WizardView)
 			p.nav.ShowModal(components.ErrorModal(err.Error(), p.nav.CloseModal))
 			p.restoring = false
 		}
@@ -211,17 +276,17 @@ func (p *Onboard) restoreWallet(seedType SeedType, seedText, pass string) {
 
 func (p *Onboard) createWallet(pass string) {
 
-	phex, words, err := p.load.Wallet.CreateWallet(pass)
+	phex, words, err := p.load.Wallet.CreateWallet(context.Background(), pass)
 
 	p.load.QueueUpdateDraw(func() {
 		if err != nil {
-			p.pages.SwitchToPage(NewWalletView)
-			p.nav.ShowModal(components.ErrorModal(fmt.Sprintf("failed to create: %s", err.Error()), p.nav.CloseModal))
+			p.pages.SwitchToPage(WizardView)
+			p.nav.ShowModal(components.ErrorModal(i18n.T("onboard.create_failed", err.Error()), p.nav.CloseModal))
 			return
 		}
 		p.restoring = false
 		if err := p.showCipherCard(phex, words); err != nil {
-			p.pages.SwitchToPage(NewWalletView)
+			p.pages.SwitchToPage(WizardView)
 			p.nav.ShowModal(components.ErrorModal(err.Error(), p.nav.CloseModal))
 		}
 	})
@@ -229,24 +294,28 @@ func (p *Onboard) createWallet(pass string) {
 
 func (p *Onboard) buildCipherCard(phex string, words []string) (tview.Primitive, error) {
 
-	confirmButton := components.NewConfirmButton(p.load.Application, "I have written down all words", true, tcell.ColorBlack, 3, func() {
-		p.pages.HidePage(CipherView)
-		cancel := func() {
-			p.nav.CloseModal()
-			p.pages.SwitchToPage(CipherView)
+	// The quiz asks for the words as displayed on the card.
+	displayWords, err := i18n.LocalizeMnemonic(words)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmButton := components.NewConfirmButton(p.load.Application, i18n.T("onboard.written_down"), true, tcell.ColorBlack, 3, func() {
+		cancel := p.nav.CloseModal
+		if len(words) < seedQuizWords {
+			p.nav.ShowModal(components.NewDialog(i18n.T("onboard.confirm_title"), i18n.T("onboard.not_saved"), cancel, []string{i18n.T("onboard.cancel"), i18n.T("onboard.risk_accepted")}, cancel, func() {
+				p.nav.CloseModal()
+				p.finishOnboarding()
+			}))
+			return
 		}
-		p.nav.ShowModal(components.NewDialog("confirm?", "Your mnemonic is NOT saved in the database and CANNOT be restored. Make sure to save it securely.", cancel, []string{"Cancel", "Risk Accepted"}, cancel, func() {
+		p.nav.ShowModal(components.NewDialog(i18n.T("onboard.confirm_title"), i18n.T("onboard.not_saved_quiz"), cancel, []string{i18n.T("onboard.cancel"), i18n.T("onboard.verify_words"), i18n.T("onboard.skip")}, cancel, func() {
 			p.nav.CloseModal()
-			if p.restoring {
-				go p.monitorRestoreRecovery()
-			} else {
-				go func() {
-					p.load.QueueUpdateDraw(func() {
-						p.load.Go(shared.WALLET)
-					})
-				}()
-			}
-		}))
+			p.showSeedQuiz(displayWords)
+		}, func() {
+			p.nav.CloseModal()
+			p.finishOnboarding()
+		}, cancel))
 	})
 	cipherCard, height, err := components.NewCipher(p.load, words, phex)
 	if err != nil {
@@ -269,12 +338,69 @@ func (p *Onboard) buildCipherCard(phex string, words []string) (tview.Primitive,
 	return container, nil
 }
 
+func (p *Onboard) finishOnboarding() {
+	if p.restoring {
+		go p.monitorRestoreRecovery()
+		return
+	}
+	go func() {
+		p.load.QueueUpdateDraw(func() {
+			p.load.Go(shared.WALLET)
+		})
+	}()
+}
+
+// showSeedQuiz asks a new pick of words each time the backup is verified.
+func (p *Onboard) showSeedQuiz(words []string) {
+	p.wizard.SetStepContent(verifyStep, p.buildSeedQuiz(words)).Next()
+}
+
+func (p *Onboard) buildSeedQuiz(words []string) tview.Primitive {
+	indexes := pickQuizIndexes(len(words), seedQuizWords)
+
+	form := tview.NewForm()
+	for _, idx := range indexes {
+		form.AddInputField(i18n.T("onboard.quiz_word", idx+1), "", 0, nil, nil)
+	}
+	p.wizard.Navigation(form, verifyStep, i18n.T("onboard.quiz_verify"))
+	p.wizard.SetStepValidator(verifyStep, func() error {
+		for i, idx := range indexes {
+			answer := form.GetFormItem(i).(*tview.InputField).GetText()
+			if !i18n.SameWord(answer, words[idx]) {
+				return errors.New(i18n.T("onboard.quiz_mismatch"))
+			}
+		}
+		return nil
+	})
+
+	info := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(i18n.T("onboard.quiz_info"))
+
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewBox(), 0, 1, false).
+		AddItem(info, 2, 0, false).
+		AddItem(form, 2*len(indexes)+3, 0, true).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	mainFlex := tview.NewFlex().
+		AddItem(tview.NewBox(), 0, 1, false).
+		AddItem(flex, 50, 0, true).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	return mainFlex
+}
+
 func (p *Onboard) monitorRestoreRecovery() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	update := func(status *load.RecoveryStatus) bool {
 		msg := fmt.Sprintf("⏳ Recovery in progress… [%d] UTXO recovered\n%.2f%% complete", status.UTXOCount, status.Info.Progress*100)
+		if status.Estimate != nil {
+			msg += "\n" + status.Estimate.String()
+		}
 		p.load.QueueUpdateDraw(func() {
 			p.showToast(msg)
 		})
@@ -327,62 +453,47 @@ func (p *Onboard) monitorRestoreRecovery() {
 }
 
 func (p *Onboard) waitForWalletReady(ctx context.Context) error {
-	sub := p.load.Wallet.Subscribe()
-	defer p.load.Wallet.Unsubscribe(sub)
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case update, ok := <-sub:
-			if !ok || update == nil {
-				return fmt.Errorf("wallet subscription closed while waiting for RPC ready")
-			}
-			if update.State == flnd.StatusReady {
-				return nil
-			}
-			if update.State == flnd.StatusDown && update.Err != nil {
-				return update.Err
-			}
-		}
-	}
+	return p.waitForState(ctx, flnd.StatusReady)
 }
 
 func (p *Onboard) waitForWalletRPC(ctx context.Context) error {
-	sub := p.load.Wallet.Subscribe()
-	defer p.load.Wallet.Unsubscribe(sub)
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case update, ok := <-sub:
-			if !ok || update == nil {
-				return fmt.Errorf("wallet subscription closed while waiting for RPC")
-			}
+	return p.waitForState(ctx, flnd.StatusReady, flnd.StatusSyncing)
+}
 
-			switch update.State {
-			case flnd.StatusReady, flnd.StatusBlock, flnd.StatusTransaction, flnd.StatusSyncing:
-				return nil
-			case flnd.StatusDown:
-				if update.Err != nil {
-					return update.Err
-				}
-			}
+// waitForState waits for the wallet to reach one of states. It fails if the
+// wallet goes down with an error first, and keeps waiting through a plain
+// restart.
+func (p *Onboard) waitForState(ctx context.Context, states ...flnd.Status) error {
+	u, err := p.load.Wallet.WaitForState(ctx, append(states, flnd.StatusDown)...)
+	if err != nil {
+		return err
+	}
+	if u.State == flnd.StatusDown {
+		if u.Err != nil {
+			return u.Err
 		}
+		_, err = p.load.Wallet.WaitForState(ctx, states...)
 	}
+	return err
 }
 
-func (p *Onboard) validateFields(pass, passConf string) error {
-	if pass != passConf {
-		return fmt.Errorf("passwords do not match")
-	}
+// passwordValidator checks a new password and its confirmation.
+func passwordValidator(passField, confField *tview.InputField) (*components.FormValidator, tview.FormItem, tview.FormItem) {
+	validator := components.NewFormValidator()
+	passItem := validator.Field(passField, components.PasswordPolicy())
+	confItem := validator.Field(confField, components.Matches(passField.GetText, "passwords do not match"))
+	return validator, passItem, confItem
+}
 
-	if len(pass) < shared.MinPasswordLength {
-		return fmt.Errorf("password must be at least %d characters", shared.MinPasswordLength)
+// pickQuizIndexes returns n distinct word positions in ascending order so the
+// quiz walks the backup from top to bottom.
+func pickQuizIndexes(total, n int) []int {
+	if n > total {
+		n = total
 	}
-
-	return nil
+	indexes := rand.Perm(total)[:n]
+	sort.Ints(indexes)
+	return indexes
 }
 
 func extractSeedWords(seed string) []string {
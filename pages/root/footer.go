@@ -5,33 +5,47 @@
 package root
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/rivo/tview"
 
 	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/flnd"
 	"github.com/flokiorg/twallet/load"
-	"github.com/gdamore/tcell/v2"
+	"github.com/flokiorg/twallet/shared"
+)
+
+const (
+	networkInfoInterval = 15 * time.Second
+	feeSparklineWidth   = 8
 )
 
 type Footer struct {
 	*tview.Grid
-	load       *load.Load
-	status     *components.Circle
-	statusText *tview.TextView
-	infoText   *tview.TextView
-	leftSide   *tview.TextView
-	destroy    chan struct{}
+	load        *load.Load
+	status      *components.Circle
+	statusText  *tview.TextView
+	infoText    *tview.TextView
+	networkText *tview.TextView
+	feeText     *tview.TextView
+	leftSide    *tview.TextView
+	destroy     chan struct{}
+	compact     bool
 }
 
 func NewFooter(l *load.Load) *Footer {
 	f := &Footer{
-		Grid:       tview.NewGrid(),
-		status:     components.NewCircle(),
-		statusText: tview.NewTextView().SetTextAlign(tview.AlignRight).SetDynamicColors(true),
-		infoText:   tview.NewTextView().SetTextAlign(tview.AlignCenter).SetDynamicColors(true),
-		load:       l,
-		destroy:    make(chan struct{}),
+		Grid:        tview.NewGrid(),
+		status:      components.NewCircle(),
+		statusText:  tview.NewTextView().SetTextAlign(tview.AlignRight).SetDynamicColors(true),
+		infoText:    tview.NewTextView().SetTextAlign(tview.AlignCenter).SetDynamicColors(true),
+		networkText: tview.NewTextView().SetTextAlign(tview.AlignRight).SetDynamicColors(true),
+		feeText:     tview.NewTextView().SetTextAlign(tview.AlignRight).SetDynamicColors(true),
+		load:        l,
+		destroy:     make(chan struct{}),
 	}
 
 	f.status.SetColor(components.YELLOW)
@@ -42,25 +56,78 @@ func NewFooter(l *load.Load) *Footer {
 		SetTextAlign(tview.AlignLeft).
 		SetBorderPadding(0, 0, 1, 1)
 
-	f.SetRows(0).SetColumns(37, 0, 26, 3).
-		AddItem(f.leftSide, 0, 0, 1, 1, 0, 0, false).
-		AddItem(f.infoText, 0, 1, 1, 1, 0, 0, false).
-		AddItem(f.statusText, 0, 2, 1, 1, 0, 0, false).
-		AddItem(f.status, 0, 3, 1, 1, 0, 0, false)
+	f.arrange()
+	f.networkText.SetText(f.networkView(nil))
 
 	go f.updates()
+	go f.pollNetworkInfo()
 
 	return f
 }
 
+// arrange lays out the footer cells; the compact layout drops the shortcut
+// hints and narrows the status text.
+func (f *Footer) arrange() {
+	f.Clear()
+	if f.compact {
+		f.SetRows(0).SetColumns(0, 18, 3).
+			AddItem(f.infoText, 0, 0, 1, 1, 0, 0, false).
+			AddItem(f.statusText, 0, 1, 1, 1, 0, 0, false).
+			AddItem(f.status, 0, 2, 1, 1, 0, 0, false)
+		return
+	}
+
+	if f.load.Fees != nil {
+		f.SetRows(0).SetColumns(37, 0, 28, 16, 26, 3).
+			AddItem(f.leftSide, 0, 0, 1, 1, 0, 0, false).
+			AddItem(f.infoText, 0, 1, 1, 1, 0, 0, false).
+			AddItem(f.networkText, 0, 2, 1, 1, 0, 0, false).
+			AddItem(f.feeText, 0, 3, 1, 1, 0, 0, false).
+			AddItem(f.statusText, 0, 4, 1, 1, 0, 0, false).
+			AddItem(f.status, 0, 5, 1, 1, 0, 0, false)
+		return
+	}
+
+	f.SetRows(0).SetColumns(37, 0, 28, 26, 3).
+		AddItem(f.leftSide, 0, 0, 1, 1, 0, 0, false).
+		AddItem(f.infoText, 0, 1, 1, 1, 0, 0, false).
+		AddItem(f.networkText, 0, 2, 1, 1, 0, 0, false).
+		AddItem(f.statusText, 0, 3, 1, 1, 0, 0, false).
+		AddItem(f.status, 0, 4, 1, 1, 0, 0, false)
+}
+
+// setCompact switches between the full and compact layouts. It must run on
+// the UI goroutine.
+func (f *Footer) setCompact(compact bool) {
+	if f.compact == compact {
+		return
+	}
+	f.compact = compact
+	f.arrange()
+}
+
 func (f *Footer) updates() {
 
+	// Warnings fill the info cell while no toast is shown.
+	toastShown := false
+	f.updateInfoText(f.load.Notif.Warning())
+
 	for {
 		select {
 
-		case text := <-f.load.Notif.Toast():
+		case <-f.load.Notif.ToastsChanged():
+			text := toastView(f.load.Notif.Toasts())
+			toastShown = text != ""
+			if !toastShown {
+				text = f.load.Notif.Warning()
+			}
 			f.updateInfoText(text)
 
+		case <-f.load.Notif.WarningsChanged():
+			if !toastShown {
+				f.updateInfoText(f.load.Notif.Warning())
+			}
+
 		case hs := <-f.load.Notif.Health():
 
 			switch hs.Level {
@@ -97,6 +164,104 @@ func (f *Footer) updates() {
 	}
 }
 
+// pollNetworkInfo refreshes the network name and peer counts so a wallet
+// stuck syncing without peers is visible at a glance.
+func (f *Footer) pollNetworkInfo() {
+	ticker := time.NewTicker(networkInfoInterval)
+	defer ticker.Stop()
+
+	for {
+		info, err := f.load.Wallet.NetworkInfo(context.Background())
+		if err != nil {
+			info = nil
+		}
+		text := f.networkView(info)
+		fees := f.feeView()
+		f.load.Application.QueueUpdateDraw(func() {
+			f.networkText.SetText(text)
+			f.feeText.SetText(fees)
+		})
+
+		select {
+		case <-ticker.C:
+		case <-f.destroy:
+			return
+		}
+	}
+}
+
+func (f *Footer) networkView(info *flnd.NetworkInfo) string {
+	theme := shared.CurrentTheme()
+
+	network, netColor := "", theme.Text
+	if f.load.AppConfig.Network != nil {
+		network = f.load.AppConfig.Network.Name
+		netColor = shared.NetworkColor(*f.load.AppConfig.Network)
+	}
+	if info != nil && info.Network != "" {
+		network = info.Network
+	}
+	view := fmt.Sprintf("[%s::b]%s[-::-]", netColor, network)
+	if info == nil {
+		return view
+	}
+
+	// Lightning peers are optional for an on-chain wallet, chain peers are
+	// not: without them the wallet never syncs.
+	if info.ChainPeers >= 0 {
+		color := theme.Text
+		if info.ChainPeers == 0 {
+			color = theme.Error
+		}
+		view += fmt.Sprintf(" [gray::]│ chain [%s::]%d[-::]", color, info.ChainPeers)
+	}
+	view += fmt.Sprintf(" [gray::]│ ln [%s::]%d[-::]", theme.Text, info.LightningPeers)
+
+	return view
+}
+
+// feeView draws the recent fee rates as a sparkline followed by the latest
+// one, red while above the alert threshold.
+func (f *Footer) feeView() string {
+	if f.load.Fees == nil {
+		return ""
+	}
+	rate, congested := f.load.Fees.Congestion()
+	if rate == 0 {
+		return "[gray::]fee -"
+	}
+
+	trend := f.load.Fees.Trend()
+	if len(trend) > feeSparklineWidth {
+		trend = trend[len(trend)-feeSparklineWidth:]
+	}
+	color := shared.CurrentTheme().Text
+	if congested {
+		color = shared.CurrentTheme().Error
+	}
+	return fmt.Sprintf("[gray::]fee [%s::]%s %.0f[-::]", color, shared.Sparkline(trend), rate)
+}
+
+// toastView joins the toasts on display, newest first, each in the color of
+// its severity unless its text sets one.
+func toastView(toasts []load.ToastMessage) string {
+	theme := shared.CurrentTheme()
+	parts := make([]string, 0, len(toasts))
+	for _, toast := range toasts {
+		color := theme.Text
+		switch toast.Severity {
+		case load.SeveritySuccess:
+			color = theme.Success
+		case load.SeverityWarning:
+			color = theme.Warning
+		case load.SeverityError:
+			color = theme.Error
+		}
+		parts = append(parts, fmt.Sprintf("[%s::]%s[-::-]", color, toast.Text))
+	}
+	return strings.Join(parts, " [gray::]│[-::] ")
+}
+
 func (f *Footer) updateStatus(flagColor components.CircleColor) {
 	f.load.Application.QueueUpdateDraw(func() {
 		f.status.SetColor(flagColor)
@@ -116,7 +281,7 @@ func (f *Footer) updateStatusText(notif string) {
 }
 
 func (f *Footer) showShortcutView() {
-	f.leftSide.SetText(fmt.Sprintf("[%s:-:-]<c> [gray:-:-]Change Password [%s:-:-]<l> [gray:-:-]Lock Wallet", tcell.ColorLightSkyBlue, tcell.ColorLightSkyBlue))
+	f.leftSide.SetText(fmt.Sprintf("[%s:-:-]<c> [gray:-:-]Change Password [%s:-:-]<l> [gray:-:-]Lock Wallet", shared.CurrentTheme().Accent, shared.CurrentTheme().Accent))
 }
 
 func (f *Footer) Destroy() {
@@ -5,34 +5,65 @@
 package root
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/rivo/tview"
 
+	"github.com/flokiorg/flnd/lnrpc"
 	"github.com/flokiorg/go-flokicoin/chainutil"
 	"github.com/flokiorg/twallet/flnd"
+	"github.com/flokiorg/twallet/i18n"
 	"github.com/flokiorg/twallet/load"
 	. "github.com/flokiorg/twallet/shared"
 	"github.com/flokiorg/twallet/utils"
 	"github.com/gdamore/tcell/v2"
 )
 
+const (
+	feeViewWidth       = 17
+	feeConfTarget      = 6
+	feeRefreshInterval = 2 * time.Minute
+)
+
+// headerStates are the wallet updates the header shows. The balance comes
+// pushed by the service, recomputed on transactions and blocks.
+var headerStates = []flnd.Status{
+	flnd.StatusBalance,
+	flnd.StatusReady,
+	flnd.StatusTransaction,
+	flnd.StatusSyncing,
+	flnd.StatusDown,
+	flnd.StatusFailedPermanently,
+	flnd.StatusNone,
+	flnd.StatusNoWallet,
+	flnd.StatusLocked,
+	flnd.StatusUnlocked,
+}
+
 type Header struct {
 	*tview.Flex
 	logo              *tview.TextView
 	shortcuts         *tview.Flex
 	balance           *tview.TextView
+	fiat              *tview.TextView
+	fee               *tview.TextView
 	hotkeys           *tview.TextView
 	walletInfo        *tview.Grid
+	compactLine       *tview.TextView
 	load              *load.Load
 	destroy           chan struct{}
 	dcancel           func()
 	nsub              <-chan *load.NotificationEvent
+	wsub              <-chan *flnd.Update
 	state             flnd.Status
 	status            string
+	compactBalance    string
 	walletInfoVisible bool
-	shortcutsVisible  bool
+	compact           bool
 }
 
 func NewHeader(l *load.Load) *Header {
@@ -45,7 +76,13 @@ func NewHeader(l *load.Load) *Header {
 	h.logo = h.buildLogo()
 	h.AddItem(h.logo, 30, 1, false)
 
-	if ok, _ := l.Wallet.WalletExists(); !ok {
+	h.compactLine = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	h.compactLine.SetBorderPadding(0, 0, 1, 1)
+	h.updateCompactLine()
+
+	if ok, _ := l.Wallet.WalletExists(context.Background()); !ok {
 		return h
 	}
 
@@ -55,40 +92,54 @@ func NewHeader(l *load.Load) *Header {
 
 	h.balance = tview.NewTextView().
 		SetDynamicColors(true).
-		SetTextColor(tcell.ColorOrange).
+		SetTextColor(CurrentTheme().Primary).
 		SetTextAlign(tview.AlignLeft)
 
+	h.fiat = tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextColor(CurrentTheme().Muted).
+		SetTextAlign(tview.AlignLeft)
+
+	h.fee = tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextColor(CurrentTheme().Muted).
+		SetTextAlign(tview.AlignRight)
+
 	h.shortcuts = buildLogShortcutView()
 	// h.shortcutsWrap = buildShortcutWrapper(h.shortcuts)
 
 	statusMessage := ""
-	statusColor := tcell.ColorYellow
+	statusColor := CurrentTheme().Warning
 
 	statusMessage = "Syncing..."
-	statusColor = tcell.ColorYellow
+	statusColor = CurrentTheme().Warning
 
 	h.hotkeys = buildSendReceiveView()
 
 	h.balance.SetText(balanceStatusView(statusMessage, statusColor))
 	h.status = statusMessage
+	h.compactBalance = compactBalanceStatusView(statusMessage, statusColor)
+	h.updateCompactLine()
 
 	walletInfo := tview.NewGrid().
 		SetRows(1, 1, 1, 2).
-		SetColumns(0)
+		SetColumns(0, feeViewWidth)
 
-	walletInfo.AddItem(h.balance, 1, 0, 2, 1, 0, 0, false).
-		AddItem(h.hotkeys, 3, 0, 1, 1, 0, 0, false)
+	walletInfo.AddItem(h.fiat, 0, 0, 1, 1, 0, 0, false).
+		AddItem(h.fee, 0, 1, 1, 1, 0, 0, false).
+		AddItem(h.balance, 1, 0, 2, 2, 0, 0, false).
+		AddItem(h.hotkeys, 3, 0, 1, 2, 0, 0, false)
 
 	h.walletInfo = walletInfo
 	if h.state != flnd.StatusLocked {
-		h.AddItem(h.shortcuts, 0, 1, false)
-		h.shortcutsVisible = true
-		h.AddItem(walletInfo, 30, 1, false)
 		h.walletInfoVisible = true
+		h.relayout()
 	}
 
+	h.wsub = h.load.Wallet.SubscribeFiltered(headerStates...)
 	h.nsub, h.dcancel = h.load.Notif.Subscribe()
 	go h.updates()
+	go h.pollFeeRate()
 
 	return h
 }
@@ -97,11 +148,22 @@ func (h *Header) updates() {
 
 	for {
 		select {
+		case u, ok := <-h.wsub:
+			if !ok {
+				return
+			}
+			h.handleUpdate(u)
+
 		case evt, ok := <-h.nsub:
 			if !ok {
 				return
 			}
-			h.handleNotification(evt)
+			// Wallet updates come from wsub, only the explicit refresh
+			// requests are taken from here.
+			if evt == nil {
+				h.showLastBalance()
+				h.load.Wallet.RefreshBalance()
+			}
 
 		case <-h.destroy:
 			return
@@ -109,66 +171,70 @@ func (h *Header) updates() {
 	}
 }
 
-func (h *Header) handleNotification(evt *load.NotificationEvent) {
-	if evt != nil {
-		h.state = evt.State
+func (h *Header) handleUpdate(u *flnd.Update) {
+	if u.State != flnd.StatusBalance {
+		h.state = u.State
 	}
-
 	h.setWalletInfoVisible(h.state != flnd.StatusLocked)
 
-	if evt != nil {
-		logEvent := h.load.Logger.Trace().
-			Str("state", string(evt.State))
-		if evt.BlockHeight > 0 {
-			logEvent = logEvent.Uint32("block_height", evt.BlockHeight)
-		}
-		if evt.Err != nil {
-			logEvent = logEvent.Err(evt.Err)
-		}
-		logEvent.Msg("header received notification")
+	logEvent := h.load.Logger.Trace().
+		Str("state", string(u.State))
+	if u.BlockHeight > 0 {
+		logEvent = logEvent.Uint32("block_height", u.BlockHeight)
+	}
+	if u.Err != nil {
+		logEvent = logEvent.Err(u.Err)
 	}
+	logEvent.Msg("header received wallet update")
 
-	switch {
-	case evt == nil:
-		h.refreshBalance()
+	switch u.State {
+	case flnd.StatusBalance:
+		h.showBalance(u.Balance)
 
-	case evt.State == flnd.StatusReady, evt.State == flnd.StatusTransaction, evt.State == flnd.StatusBlock:
-		h.refreshBalance()
+	case flnd.StatusReady, flnd.StatusTransaction:
+		if !h.showLastBalance() {
+			h.showBalanceStatus("Loading balance...", CurrentTheme().Warning)
+		}
+
+	case flnd.StatusSyncing:
+		h.showBalanceStatus("Syncing...", CurrentTheme().Warning)
 
-	case evt.State == flnd.StatusSyncing:
-		h.showBalanceStatus("Syncing...", tcell.ColorYellow)
+	case flnd.StatusDown:
+		h.showBalanceStatus("Reconnecting...", CurrentTheme().Primary)
 
-	case evt.State == flnd.StatusDown:
-		h.showBalanceStatus("Reconnecting...", tcell.ColorOrange)
+	case flnd.StatusFailedPermanently:
+		h.showBalanceStatus("Wallet daemon stopped.", CurrentTheme().Error)
 
-	case evt.State == flnd.StatusNone:
-		h.showBalanceStatus("Connecting...", tcell.ColorYellow)
+	case flnd.StatusNone:
+		h.showBalanceStatus("Connecting...", CurrentTheme().Warning)
 
-	case evt.State == flnd.StatusNoWallet:
-		h.showBalanceStatus("Wallet not found.", tcell.ColorRed)
+	case flnd.StatusNoWallet:
+		h.showBalanceStatus("Wallet not found.", CurrentTheme().Error)
 
-	case evt.State == flnd.StatusLocked:
-		h.showBalanceStatus("Wallet locked.", tcell.ColorOrange)
+	case flnd.StatusLocked:
+		h.showBalanceStatus("Wallet locked.", CurrentTheme().Primary)
 
 	default:
-		h.showBalanceStatus("Loading balance...", tcell.ColorYellow)
+		h.showBalanceStatus("Loading balance...", CurrentTheme().Warning)
 	}
 }
 
-func (h *Header) refreshBalance() {
+// showLastBalance shows the balance last pushed by the service and reports
+// whether there was one.
+func (h *Header) showLastBalance() bool {
+	balance := h.load.Wallet.LastBalance()
+	if balance == nil {
+		return false
+	}
+	h.showBalance(balance)
+	return true
+}
+
+func (h *Header) showBalance(balance *lnrpc.WalletBalanceResponse) {
 	if h.balance == nil {
 		return
 	}
-	var confirmed, unconfirmed, locked chainutil.Amount
-	balance, err := h.load.Wallet.Balance()
-	if err != nil {
-		h.load.Logger.Warn().Err(err).Msg("unable to fetch balance")
-		confirmed, unconfirmed, locked = h.load.GetBalance()
-	} else {
-		confirmed, unconfirmed, locked = chainutil.Amount(balance.ConfirmedBalance), chainutil.Amount(balance.UnconfirmedBalance), chainutil.Amount(balance.LockedBalance)
-	}
-
-	h.updateBalance(confirmed, unconfirmed, locked)
+	h.updateBalance(chainutil.Amount(balance.ConfirmedBalance), chainutil.Amount(balance.UnconfirmedBalance), chainutil.Amount(balance.LockedBalance))
 }
 
 func (h *Header) showBalanceStatus(message string, color tcell.Color) {
@@ -181,13 +247,44 @@ func (h *Header) showBalanceStatus(message string, color tcell.Color) {
 		}
 		h.status = message
 		h.balance.SetText(balanceStatusView(message, color))
+		h.fiat.SetText("")
+		h.compactBalance = compactBalanceStatusView(message, color)
+		h.updateCompactLine()
 	})
 }
 
+// pollFeeRate keeps the current fee estimate on screen so the user can judge
+// whether to send now or wait for cheaper blocks. The rates of every send
+// speed are fetched together, so the send view finds them cached.
+func (h *Header) pollFeeRate() {
+	ticker := time.NewTicker(feeRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		text := ""
+		rates, _ := h.load.Wallet.FeeEstimates(context.Background(), flnd.FeeTargets...)
+		if rate, ok := rates[feeConfTarget]; ok {
+			text = feeView(rate)
+		}
+		h.load.Application.QueueUpdateDraw(func() {
+			h.fee.SetText(text)
+		})
+
+		select {
+		case <-ticker.C:
+		case <-h.destroy:
+			return
+		}
+	}
+}
+
 func (h *Header) Destroy() {
 	if h.dcancel != nil {
 		h.dcancel()
 	}
+	if h.wsub != nil {
+		h.load.Wallet.Unsubscribe(h.wsub)
+	}
 	select {
 	case <-h.destroy:
 		return
@@ -202,10 +299,13 @@ func (h *Header) updateBalance(confirmed, unconfirmed, locked chainutil.Amount)
 		Int64("unconfirmed", int64(unconfirmed)).
 		Int64("locked", int64(locked)).
 		Msg("balance updated")
-	h.load.Cache.SetBalance(confirmed, unconfirmed, locked)
+	h.load.UpdateBalance(confirmed, unconfirmed, locked)
 	h.load.Application.QueueUpdateDraw(func() {
 		h.status = ""
 		h.balance.SetText(balanceView(confirmed, unconfirmed, locked))
+		h.fiat.SetText(h.load.FiatView(confirmed))
+		h.compactBalance = compactBalanceView(confirmed, unconfirmed)
+		h.updateCompactLine()
 	})
 }
 
@@ -229,29 +329,88 @@ func (h *Header) buildLogo() *tview.TextView {
 }
 
 func buildLogShortcutView() *tview.Flex {
-	accent := tcell.ColorLightSkyBlue
+	accent := CurrentTheme().Accent
 
 	col1 := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignLeft)
 	col1.SetBorder(false)
 
-	fmt.Fprintf(col1, "\n[%s:-:-]<ctrl+t>[gray:-:-] Transactions\n", accent)
-	fmt.Fprintf(col1, "[%s:-:-]<ctrl+a>[gray:-:-] Addresses\n", accent)
-	fmt.Fprintf(col1, "[%s:-:-]<ctrl+s>[gray:-:-] Sign & Verify", accent)
+	fmt.Fprintf(col1, "\n[%s:-:-]<ctrl+t>[gray:-:-] %s\n", accent, i18n.T("shortcut.transactions"))
+	fmt.Fprintf(col1, "[%s:-:-]<ctrl+a>[gray:-:-] %s\n", accent, i18n.T("shortcut.addresses"))
+	fmt.Fprintf(col1, "[%s:-:-]<ctrl+s>[gray:-:-] %s", accent, i18n.T("shortcut.sign_verify"))
 
 	col2 := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignLeft)
 	col2.SetBorder(false)
 
-	fmt.Fprintf(col2, "\n[%s:-:-]<ctrl+x>[gray:-:-] Resync\n", accent)
-	fmt.Fprintf(col2, "[%s:-:-]<ctrl+l>[gray:-:-] Logs\n", accent)
-	fmt.Fprintf(col2, "[%s:-:-]<ctrl+n>[gray:-:-] Lightning Config", accent)
+	fmt.Fprintf(col2, "\n[%s:-:-]<ctrl+x>[gray:-:-] %s\n", accent, i18n.T("shortcut.resync"))
+	fmt.Fprintf(col2, "[%s:-:-]<ctrl+l>[gray:-:-] %s\n", accent, i18n.T("shortcut.logs"))
+	fmt.Fprintf(col2, "[%s:-:-]<ctrl+n>[gray:-:-] %s", accent, i18n.T("shortcut.lightning"))
+
+	col3 := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignLeft)
+	col3.SetBorder(false)
+
+	fmt.Fprintf(col3, "\n[%s:-:-]<ctrl+p>[gray:-:-] %s\n", accent, i18n.T("shortcut.sign_psbt"))
+	fmt.Fprintf(col3, "[%s:-:-]<u>[gray:-:-] %s\n", accent, i18n.T("shortcut.denomination"))
+	fmt.Fprintf(col3, "[%s:-:-]<b>[gray:-:-] %s", accent, i18n.T("shortcut.mark"))
+
+	col4 := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignLeft)
+	col4.SetBorder(false)
+
+	fmt.Fprintf(col4, "\n[%s:-:-]<ctrl+o>[gray:-:-] %s\n", accent, i18n.T("shortcut.columns"))
+	fmt.Fprintf(col4, "[%s:-:-]<1-9>[gray:-:-] %s\n", accent, i18n.T("shortcut.sort"))
+	fmt.Fprintf(col4, "[%s:-:-]<m>[gray:-:-] %s", accent, i18n.T("shortcut.tx_menu"))
+
+	col5 := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignLeft)
+	col5.SetBorder(false)
+
+	fmt.Fprintf(col5, "\n[%s:-:-]<ctrl+g>[gray:-:-] %s\n", accent, i18n.T("shortcut.balance_chart"))
+	fmt.Fprintf(col5, "[%s:-:-]<ctrl+w>[gray:-:-] %s\n", accent, i18n.T("shortcut.watched"))
+	fmt.Fprintf(col5, "[%s:-:-]<ctrl+e>[gray:-:-] %s", accent, i18n.T("shortcut.journal"))
+
+	col6 := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignLeft)
+	col6.SetBorder(false)
+
+	fmt.Fprintf(col6, "\n[%s:-:-]<ctrl+d>[gray:-:-] %s\n", accent, i18n.T("shortcut.debug_info"))
+	fmt.Fprintf(col6, "[%s:-:-]<ctrl+u>[gray:-:-] %s\n", accent, i18n.T("shortcut.coins"))
+	fmt.Fprintf(col6, "[%s:-:-]<ctrl+b>[gray:-:-] %s", accent, i18n.T("shortcut.split"))
+
+	col7 := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignLeft)
+	col7.SetBorder(false)
+
+	fmt.Fprintf(col7, "\n[%s:-:-]<f>[gray:-:-] %s\n", accent, i18n.T("shortcut.date_filter"))
+	fmt.Fprintf(col7, "[%s:-:-]<e>[gray:-:-] %s\n", accent, i18n.T("shortcut.export"))
+	fmt.Fprintf(col7, "[%s:-:-]<f1>[gray:-:-] %s", accent, i18n.T("shortcut.about"))
+
+	col8 := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignLeft)
+	col8.SetBorder(false)
+
+	fmt.Fprintf(col8, "\n[%s:-:-]<f2>[gray:-:-] %s\n", accent, i18n.T("shortcut.settings"))
+	fmt.Fprintf(col8, "[%s:-:-]<f3>[gray:-:-] %s", accent, i18n.T("shortcut.notifications"))
 
 	shortcuts := tview.NewFlex().
 		AddItem(col1, 0, 1, false).
-		AddItem(col2, 0, 1, false)
+		AddItem(col2, 0, 1, false).
+		AddItem(col3, 0, 1, false).
+		AddItem(col4, 0, 1, false).
+		AddItem(col5, 0, 1, false).
+		AddItem(col6, 0, 1, false).
+		AddItem(col7, 0, 1, false).
+		AddItem(col8, 0, 1, false)
 
 	// Add padding if needed via BorderPadding on the Flex or columns?
 	// Creating wrapper or setting padding on columns.
@@ -265,65 +424,107 @@ func buildLogShortcutView() *tview.Flex {
 }
 
 func buildSendReceiveView() *tview.TextView {
-	accent := tcell.ColorLightSkyBlue
+	accent := CurrentTheme().Accent
 	hotkeys := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignLeft)
 	hotkeys.SetBorderPadding(0, 0, 0, 1)
 	hotkeys.SetWrap(false).SetWordWrap(false)
 
-	fmt.Fprintf(hotkeys, "\n[%s:-:b]<s>[-:-:-] Send  ", accent)
-	fmt.Fprintf(hotkeys, "[%s:-:b]<r>[-:-:-] Receive", accent)
+	fmt.Fprintf(hotkeys, "\n[%s:-:b]<s>[-:-:-] %s  ", accent, i18n.T("shortcut.send"))
+	fmt.Fprintf(hotkeys, "[%s:-:b]<r>[-:-:-] %s", accent, i18n.T("shortcut.receive"))
 
 	return hotkeys
 }
 
 func balanceView(confirmedBalance, unconfirmedBalance, lockedBalance chainutil.Amount) string {
 
-	strBalance := fmt.Sprintf("Balance: [%s:-:b]%s\n", tcell.ColorGreen, FormatAmountView(chainutil.Amount(confirmedBalance), 6))
+	success := CurrentTheme().Success
+	strBalance := fmt.Sprintf("%s: [%s:-:b]%s\n", i18n.T("balance.label"), success, FormatAmountView(chainutil.Amount(confirmedBalance), 6))
 
 	if unconfirmedBalance > 0 || lockedBalance == 0 {
-		strBalance += fmt.Sprintf("[-:-:-]Unconfirmed: [%s:-:b]%s\n", tcell.ColorGreen, FormatAmountView(chainutil.Amount(unconfirmedBalance), 6))
+		strBalance += fmt.Sprintf("[-:-:-]%s: [%s:-:b]%s\n", i18n.T("balance.unconfirmed"), success, FormatAmountView(chainutil.Amount(unconfirmedBalance), 6))
 	}
 	if lockedBalance > 0 {
-		strBalance += fmt.Sprintf("[-:-:-]Locked: [%s:-:b]%s\n", tcell.ColorGreen, FormatAmountView(chainutil.Amount(lockedBalance), 6))
+		strBalance += fmt.Sprintf("[-:-:-]%s: [%s:-:b]%s\n", i18n.T("balance.locked"), success, FormatAmountView(chainutil.Amount(lockedBalance), 6))
 	}
 
 	return strBalance
 }
 
+func feeView(lokiPerVbyte float64) string {
+	return fmt.Sprintf("fee [%s::b]%.0f[-::-] loki/vB", CurrentTheme().Text, math.Ceil(lokiPerVbyte))
+}
+
 func balanceStatusView(message string, color tcell.Color) string {
 	if message == "" {
 		message = "loading..."
 	}
-	status := fmt.Sprintf("Balance: [%s:-:b]%s\n", color, message)
-	status += fmt.Sprintf("[-:-:-]Unconfirmed: [%s:-:b]%s\n", color, DefaultBalanceView)
+	status := fmt.Sprintf("%s: [%s:-:b]%s\n", i18n.T("balance.label"), color, message)
+	status += fmt.Sprintf("[-:-:-]%s: [%s:-:b]%s\n", i18n.T("balance.unconfirmed"), color, DefaultBalanceView)
 	return status
 }
 
+func compactBalanceView(confirmed, unconfirmed chainutil.Amount) string {
+	success := CurrentTheme().Success
+	view := fmt.Sprintf("%s: [%s:-:b]%s[-:-:-]", i18n.T("balance.label"), success, FormatAmountView(confirmed, 6))
+	if unconfirmed > 0 {
+		view += fmt.Sprintf(" (+%s)", FormatAmountView(unconfirmed, 6))
+	}
+	return view
+}
+
+func compactBalanceStatusView(message string, color tcell.Color) string {
+	if message == "" {
+		message = "loading..."
+	}
+	return fmt.Sprintf("%s: [%s:-:b]%s[-:-:-]", i18n.T("balance.label"), color, message)
+}
+
 func (h *Header) setWalletInfoVisible(visible bool) {
 	if h.load == nil {
 		return
 	}
 	h.load.Application.QueueUpdateDraw(func() {
-		if visible {
-			if !h.shortcutsVisible {
-				h.AddItem(h.shortcuts, 0, 1, false)
-				h.shortcutsVisible = true
-			}
-			if h.walletInfo != nil && !h.walletInfoVisible {
-				h.AddItem(h.walletInfo, 30, 1, false)
-				h.walletInfoVisible = true
-			}
+		if h.walletInfo == nil || h.walletInfoVisible == visible {
 			return
 		}
-		if h.walletInfoVisible && h.walletInfo != nil {
-			h.RemoveItem(h.walletInfo)
-			h.walletInfoVisible = false
-		}
-		if h.shortcutsVisible {
-			h.RemoveItem(h.shortcuts)
-			h.shortcutsVisible = false
-		}
+		h.walletInfoVisible = visible
+		h.relayout()
+		h.updateCompactLine()
 	})
 }
+
+// setCompact collapses the header to a single line. It must run on the UI
+// goroutine.
+func (h *Header) setCompact(compact bool) {
+	if h.compact == compact {
+		return
+	}
+	h.compact = compact
+	h.relayout()
+}
+
+func (h *Header) relayout() {
+	h.Clear()
+	if h.compact {
+		h.AddItem(h.compactLine, 0, 1, false)
+		return
+	}
+
+	h.AddItem(h.logo, 30, 1, false)
+	if h.walletInfoVisible && h.walletInfo != nil {
+		h.AddItem(h.shortcuts, 0, 1, false)
+		h.AddItem(h.walletInfo, 30, 1, false)
+	}
+}
+
+func (h *Header) updateCompactLine() {
+	accent := CurrentTheme().Accent
+	line := fmt.Sprintf("[%s::b]TWALLET[-:-:-] v%s", NetworkColor(*h.load.AppConfig.Network), utils.Version)
+	if h.walletInfoVisible && h.compactBalance != "" {
+		line += "  " + h.compactBalance
+		line += fmt.Sprintf("  [%s:-:b]<s>[-:-:-] %s [%s:-:b]<r>[-:-:-] %s", accent, i18n.T("shortcut.send"), accent, i18n.T("shortcut.receive"))
+	}
+	h.compactLine.SetText(line)
+}
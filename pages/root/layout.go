@@ -8,19 +8,23 @@ import (
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
+	"github.com/flokiorg/twallet/components"
 	"github.com/flokiorg/twallet/load"
 )
 
+const headerHeight = 6
+
 var (
 	currentLayout *Layout
 )
 
 type Layout struct {
 	*tview.Flex
-	header *Header
-	body   *Body
-	footer *Footer
-	load   *load.Load
+	header  *Header
+	body    *Body
+	footer  *Footer
+	load    *load.Load
+	compact bool
 }
 
 func NewLayout(l *load.Load, page tview.Primitive) tview.Primitive {
@@ -39,7 +43,7 @@ func NewLayout(l *load.Load, page tview.Primitive) tview.Primitive {
 	layout.footer = NewFooter(l)
 
 	layout.SetDirection(tview.FlexRow).
-		AddItem(layout.header, 6, 0, false).
+		AddItem(layout.header, headerHeight, 0, false).
 		AddItem(layout.body, 0, 1, true).
 		AddItem(layout.footer, 2, 0, false)
 
@@ -54,6 +58,23 @@ func NewLayout(l *load.Load, page tview.Primitive) tview.Primitive {
 	return currentLayout
 }
 
+// Draw switches to the compact layout, with a one line header, when the
+// terminal is narrower than components.CompactWidth.
+func (l *Layout) Draw(screen tcell.Screen) {
+	_, _, width, _ := l.GetRect()
+	if compact := width < components.CompactWidth; compact != l.compact {
+		l.compact = compact
+		height := headerHeight
+		if compact {
+			height = 1
+		}
+		l.ResizeItem(l.header, height, 0)
+		l.header.setCompact(compact)
+		l.footer.setCompact(compact)
+	}
+	l.Flex.Draw(screen)
+}
+
 func (l *Layout) destroy() {
 	if currentLayout.header != nil {
 		currentLayout.header.Destroy()
@@ -8,9 +8,9 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
+	"github.com/flokiorg/twallet/components"
 	. "github.com/flokiorg/twallet/shared"
 )
 
@@ -35,7 +35,37 @@ func logoView() tview.Primitive {
 	return view
 }
 
-func SplashScreen(app *tview.Application) (chan string, tview.Primitive) {
+// BootStage is a step of the startup sequence shown on the splash screen.
+type BootStage int
+
+const (
+	BootStageNone BootStage = iota
+	BootStageDaemon
+	BootStageChainSync
+	BootStageWallet
+)
+
+var bootStageLabels = []struct {
+	stage BootStage
+	label string
+}{
+	{BootStageDaemon, "Daemon"},
+	{BootStageChainSync, "Chain sync"},
+	{BootStageWallet, "Wallet"},
+}
+
+// BootEvent reports startup progress to the splash screen. A Stage other than
+// BootStageNone moves the progress indicator to that stage, with Progress its
+// completion from 0 to 1 (negative when unknown) and Detail a short status.
+// Message, when set, is appended to the boot log.
+type BootEvent struct {
+	Stage    BootStage
+	Progress float64
+	Detail   string
+	Message  string
+}
+
+func SplashScreen(app *tview.Application) (chan BootEvent, tview.Primitive) {
 
 	welcomeText := tview.NewTextView().
 		SetText(WELCOME_MESSAGE).
@@ -48,10 +78,22 @@ func SplashScreen(app *tview.Application) (chan string, tview.Primitive) {
 		AddItem(welcomeText, 1, 1, false).
 		AddItem(nil, 0, 1, false)
 
+	stagesView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	stagesView.SetBorderPadding(1, 0, 0, 0)
+
+	progressBar := components.NewProgressBar(app)
+
+	progressView := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(stagesView, 2, 0, false).
+		AddItem(progressBar, 1, 0, false)
+
 	bootTextField := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignLeft)
-	bootTextField.SetBorder(true).SetBorderColor(tcell.ColorOrange)
+	bootTextField.SetBorder(true).SetBorderColor(CurrentTheme().Primary)
 	bootTextField.SetTitle("[::b]Boot log")
 	bootTextField.SetTitleAlign(tview.AlignLeft)
 
@@ -72,29 +114,69 @@ func SplashScreen(app *tview.Application) (chan string, tview.Primitive) {
 		AddItem(nil, 0, 1, false).
 		AddItem(logoView(), 9, 1, false).
 		AddItem(welcomRow, 1, 1, false).
+		AddItem(progressView, 0, 0, false).
 		AddItem(nil, 0, 1, false).
 		AddItem(bootTextRow, 1, 1, false).
 		AddItem(nil, 0, 1, false)
 
-	bootText := make(chan string)
+	bootEvents := make(chan BootEvent)
 	go func() {
 		var logs []string
 		logShown := false
-		for t := range bootText {
-			logs = append(logs, t)
-			text := strings.Join(logs, "\n")
+		progressShown := false
+		for ev := range bootEvents {
+			progressText := ""
+			if ev.Stage != BootStageNone {
+				progressText = bootProgressText(ev)
+				progressBar.SetProgress(ev.Progress)
+			}
+			text := ""
+			if ev.Message != "" {
+				logs = append(logs, ev.Message)
+				text = strings.Join(logs, "\n")
+			}
 			app.QueueUpdateDraw(func() {
-				if !logShown {
-					bootTextRow.ResizeItem(bootTextCentered, 9, 1)
-					logShown = true
+				if progressText != "" {
+					if !progressShown {
+						view.ResizeItem(progressView, 3, 0)
+						progressShown = true
+					}
+					stagesView.SetText(progressText)
+				}
+				if text != "" {
+					if !logShown {
+						bootTextRow.ResizeItem(bootTextCentered, 9, 1)
+						logShown = true
+					}
+					bootTextField.SetText(text)
+					bootTextField.ScrollToEnd()
 				}
-				bootTextField.SetText(text)
-				bootTextField.ScrollToEnd()
 			})
 		}
 	}()
 
-	return bootText, view
+	return bootEvents, view
+}
+
+func bootProgressText(ev BootEvent) string {
+	theme := CurrentTheme()
+
+	steps := make([]string, 0, len(bootStageLabels))
+	for _, s := range bootStageLabels {
+		switch {
+		case s.stage < ev.Stage:
+			steps = append(steps, fmt.Sprintf("[%s::]✓ %s[-::]", theme.Success, s.label))
+		case s.stage == ev.Stage:
+			label := s.label
+			if ev.Detail != "" {
+				label = fmt.Sprintf("%s: %s", label, ev.Detail)
+			}
+			steps = append(steps, fmt.Sprintf("[%s::b]● %s[-::-]", theme.Warning, label))
+		default:
+			steps = append(steps, fmt.Sprintf("[%s::]○ %s[-::]", theme.Muted, s.label))
+		}
+	}
+	return strings.Join(steps, "  →  ")
 }
 
 func ReloadingScreen() *tview.Flex {
@@ -5,6 +5,8 @@
 package unlock
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 	"unicode"
@@ -52,7 +54,7 @@ func NewPage(l *load.Load, showForm bool, allowAutoUnlock bool) *Unlock {
 	logo.SetBorder(false)
 
 	fmt.Fprintf(logo, "[%s:-:-]\n%s[-:-:-]\n", netColor, shared.LOCK_IMAGE)
-	fmt.Fprintf(logo, "Tap [[%s:-:-]u[-:-:-]] to unlock", tcell.ColorLightSkyBlue)
+	fmt.Fprintf(logo, "Tap [[%s:-:-]u[-:-:-]] to unlock", shared.CurrentTheme().Accent)
 
 	hFlex := tview.NewFlex().
 		AddItem(nil, 0, 1, false).
@@ -108,7 +110,7 @@ func (p *Unlock) showUnlockForm() {
 		p.load.Logger.Info().Msg("Auto-unlocking wallet...")
 		go p.handleUnlock(p.load.AppConfig.DefaultPassword, nil, nil, nil)
 	} else {
-		form.AddPasswordField("Lock passphrase:", p.load.AppConfig.DefaultPassword, 0, '*', nil)
+		form.AddFormItem(components.NewPasswordField("Lock passphrase:", p.load.AppConfig.DefaultPassword))
 		form.AddButton("Unlock", func() {
 
 			unlockButton := form.GetButton(0)
@@ -116,13 +118,16 @@ func (p *Unlock) showUnlockForm() {
 			pass := passInput.GetText()
 
 			p.load.Notif.CancelToast()
-			p.load.Notif.ShowToast("🔒 unlocking...")
+			unlocking := p.load.Notif.ShowToast("🔒 unlocking...")
 
 			info.SetText(unlockingMessage)
 			unlockButton.SetLabel("Loading...")
 			unlockButton.SetDisabled(true)
 
-			go p.handleUnlock(pass, passInput, info, unlockButton)
+			go func() {
+				p.handleUnlock(pass, passInput, info, unlockButton)
+				unlocking.Dismiss()
+			}()
 		})
 	}
 
@@ -132,8 +137,8 @@ func (p *Unlock) showUnlockForm() {
 		AddItem(form, 0, 1, true)
 
 	view.SetTitle("🔒 Locked").
-		SetTitleColor(tcell.ColorGray).
-		SetBackgroundColor(tcell.ColorOrange).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
 		SetBorder(true)
 
 	p.nav.ShowModal(components.NewModal(view, 50, 15, p.nav.CloseModal))
@@ -141,10 +146,17 @@ func (p *Unlock) showUnlockForm() {
 }
 
 func (p *Unlock) handleUnlock(pass string, passInput *tview.InputField, info *tview.TextView, unlockButton *tview.Button) {
-	err := p.load.Wallet.Unlock(pass)
+	err := p.load.Wallet.Unlock(context.Background(), pass)
 	if err != nil {
+		message := err.Error()
+		switch {
+		case errors.Is(err, flnd.ErrInvalidPassphrase):
+			message = "invalid passphrase"
+		case errors.Is(err, flnd.ErrRPCStarting):
+			message = "wallet is still starting, try again in a moment"
+		}
 		p.load.QueueUpdateDraw(func() {
-			p.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
+			p.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", message), time.Second*30)
 			if passInput != nil {
 				passInput.SetText(p.load.AppConfig.DefaultPassword)
 				info.SetText(unlockInstructions)
@@ -160,82 +172,53 @@ func (p *Unlock) handleUnlock(pass string, passInput *tview.InputField, info *tv
 		return
 	}
 
-	sub := p.load.Wallet.Subscribe()
-	defer p.load.Wallet.Unsubscribe(sub)
-
-	timer := time.NewTimer(time.Second * 20)
-	defer timer.Stop()
-
-	for {
-		select {
-		case u, ok := <-sub:
-			if !ok || u == nil {
-				p.load.QueueUpdateDraw(func() {
-					p.load.Notif.ShowToast("🔒 Unlock failed. Try again.")
-					if passInput != nil {
-						info.SetText(unlockInstructions)
-						unlockButton.SetLabel("Unlock")
-						unlockButton.SetDisabled(false)
-						p.load.Application.SetFocus(passInput)
-					} else {
-						// Auto-unlock failed, switch to manual mode
-						p.allowAutoUnlock = false
-						p.showUnlockForm()
-					}
-				})
-				return
-			}
-			switch u.State {
-			case flnd.StatusDown:
-				event := u
-				p.load.QueueUpdateDraw(func() {
-					if event.Err != nil {
-						p.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", event.Err.Error()), time.Second*30)
-					}
-					if passInput != nil {
-						info.SetText(unlockInstructions)
-						unlockButton.SetLabel("Unlock")
-						unlockButton.SetDisabled(false)
-						p.load.Application.SetFocus(passInput)
-					} else {
-						// Auto-unlock failed, switch to manual mode
-						p.allowAutoUnlock = false
-						p.showUnlockForm()
-					}
-				})
-				return
-
-			case flnd.StatusReady, flnd.StatusSyncing, flnd.StatusUnlocked:
-				p.load.QueueUpdateDraw(func() {
-					p.load.Notif.ShowToastWithTimeout("🔓 Unlocked", time.Second*1)
-					if passInput != nil {
-						info.SetText(unlockedMessage)
-						unlockButton.SetLabel("Unlock")
-						unlockButton.SetDisabled(false)
-					}
-					p.load.Go(shared.WALLET)
-				})
-				return
-
-			default:
-				continue
+	// resetForm lets the user try again, by hand once auto-unlock failed.
+	resetForm := func() {
+		if passInput != nil {
+			info.SetText(unlockInstructions)
+			unlockButton.SetLabel("Unlock")
+			unlockButton.SetDisabled(false)
+			p.load.Application.SetFocus(passInput)
+		} else {
+			p.allowAutoUnlock = false
+			p.showUnlockForm()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*20)
+	defer cancel()
+
+	u, err := p.load.Wallet.WaitForState(ctx, flnd.StatusReady, flnd.StatusSyncing, flnd.StatusUnlocked, flnd.StatusDown)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		p.load.QueueUpdateDraw(func() {
+			p.load.Notif.ShowToast("🔒 Unlock timed out. Try again.")
+			resetForm()
+		})
+
+	case err != nil:
+		p.load.QueueUpdateDraw(func() {
+			p.load.Notif.ShowToast("🔒 Unlock failed. Try again.")
+			resetForm()
+		})
+
+	case u.State == flnd.StatusDown:
+		p.load.QueueUpdateDraw(func() {
+			if u.Err != nil {
+				p.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", u.Err.Error()), time.Second*30)
 			}
+			resetForm()
+		})
 
-		case <-timer.C:
-			p.load.QueueUpdateDraw(func() {
-				p.load.Notif.ShowToast("🔒 Unlock timed out. Try again.")
-				if passInput != nil {
-					info.SetText(unlockInstructions)
-					unlockButton.SetLabel("Unlock")
-					unlockButton.SetDisabled(false)
-					p.load.Application.SetFocus(passInput)
-				} else {
-					// Auto-unlock failed, switch to manual mode
-					p.allowAutoUnlock = false
-					p.showUnlockForm()
-				}
-			})
-			return
-		}
+	default:
+		p.load.QueueUpdateDraw(func() {
+			p.load.Notif.ShowToastWithTimeout("🔓 Unlocked", time.Second*1)
+			if passInput != nil {
+				info.SetText(unlockedMessage)
+				unlockButton.SetLabel("Unlock")
+				unlockButton.SetDisabled(false)
+			}
+			p.load.Go(shared.WALLET)
+		})
 	}
 }
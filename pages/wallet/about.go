@@ -0,0 +1,58 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/shared"
+	"github.com/flokiorg/twallet/utils"
+)
+
+// showAbout shows the build of twallet, as twallet --version prints it, to
+// copy into a bug report, and generates a support bundle with b.
+func (w *Wallet) showAbout() {
+	w.load.Notif.CancelToast()
+
+	accent := shared.CurrentTheme().Accent
+	var b strings.Builder
+	for _, field := range utils.ReadBuildInfo().Fields() {
+		fmt.Fprintf(&b, "[%s:-:-]%-11s[-:-:-] %s\n", accent, field[0]+":", tview.Escape(field[1]))
+	}
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(strings.TrimSuffix(b.String(), "\n"))
+	view.SetBorderPadding(1, 0, 2, 2)
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'b' {
+			w.closeModal()
+			w.showSupportBundleDialog()
+			return nil
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s:-:-]<esc>[gray:-:-] close  [%s:-:-]b[gray:-:-] support bundle  [gray:-:-]twallet --version prints the same", accent, accent))
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetTitle("About twallet").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	container.AddItem(view, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	w.nav.ShowModal(components.NewModal(container, 70, 13, w.closeModal))
+	w.load.Application.SetFocus(view)
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/flnd/lnrpc/walletrpc"
+	"github.com/flokiorg/go-flokicoin/chainutil"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/flnd"
+	"github.com/flokiorg/twallet/shared"
+)
+
+// addressHistory sums what an address received and what was spent from it,
+// and lists the transactions that did so with their net effect on it.
+type addressHistory struct {
+	Received chainutil.Amount
+	Sent     chainutil.Amount
+	Entries  []addressHistoryEntry
+}
+
+type addressHistoryEntry struct {
+	Tx     *lnrpc.Transaction
+	Amount chainutil.Amount
+}
+
+// addressHistories groups the wallet transactions by the addresses they pay
+// to or spend from. Spends are matched through the previous outpoints since
+// transactions only carry the addresses of their outputs.
+func (w *Wallet) addressHistories(ctx context.Context) (map[string]*addressHistory, error) {
+	txs, err := w.load.Wallet.FetchTransactionsWithOptions(ctx, flnd.FetchTransactionsOptions{
+		IgnoreLimit: true,
+	})
+	if err != nil {
+		return map[string]*addressHistory{}, err
+	}
+	return buildAddressHistories(txs), nil
+}
+
+func buildAddressHistories(txs []*lnrpc.Transaction) map[string]*addressHistory {
+	type output struct {
+		address string
+		amount  chainutil.Amount
+	}
+
+	outputs := make(map[string]output)
+	for _, tx := range txs {
+		if tx == nil {
+			continue
+		}
+		for _, detail := range tx.GetOutputDetails() {
+			if detail == nil || strings.TrimSpace(detail.Address) == "" {
+				continue
+			}
+			outpoint := fmt.Sprintf("%s:%d", tx.TxHash, detail.OutputIndex)
+			outputs[outpoint] = output{
+				address: strings.TrimSpace(detail.Address),
+				amount:  chainutil.Amount(detail.Amount),
+			}
+		}
+	}
+
+	histories := make(map[string]*addressHistory)
+	get := func(address string) *addressHistory {
+		h, ok := histories[address]
+		if !ok {
+			h = &addressHistory{}
+			histories[address] = h
+		}
+		return h
+	}
+
+	for _, tx := range txs {
+		if tx == nil {
+			continue
+		}
+		net := make(map[string]chainutil.Amount)
+		order := make([]string, 0)
+		touch := func(address string, amount chainutil.Amount) {
+			if _, ok := net[address]; !ok {
+				order = append(order, address)
+			}
+			net[address] += amount
+		}
+
+		for _, detail := range tx.GetOutputDetails() {
+			if detail == nil || strings.TrimSpace(detail.Address) == "" {
+				continue
+			}
+			address := strings.TrimSpace(detail.Address)
+			amount := chainutil.Amount(detail.Amount)
+			get(address).Received += amount
+			touch(address, amount)
+		}
+
+		for _, prev := range tx.GetPreviousOutpoints() {
+			if prev == nil || !prev.IsOurOutput {
+				continue
+			}
+			spent, ok := outputs[prev.Outpoint]
+			if !ok {
+				continue
+			}
+			get(spent.address).Sent += spent.amount
+			touch(spent.address, -spent.amount)
+		}
+
+		for _, address := range order {
+			h := get(address)
+			h.Entries = append(h.Entries, addressHistoryEntry{Tx: tx, Amount: net[address]})
+		}
+	}
+
+	for _, h := range histories {
+		sort.SliceStable(h.Entries, func(i, j int) bool {
+			return h.Entries[i].Tx.TimeStamp > h.Entries[j].Tx.TimeStamp
+		})
+	}
+
+	return histories
+}
+
+func formatAccountAddressType(t walletrpc.AddressType) string {
+	switch t {
+	case walletrpc.AddressType_WITNESS_PUBKEY_HASH:
+		return "segwit"
+	case walletrpc.AddressType_NESTED_WITNESS_PUBKEY_HASH,
+		walletrpc.AddressType_HYBRID_NESTED_WITNESS_PUBKEY_HASH:
+		return "nested-segwit"
+	case walletrpc.AddressType_TAPROOT_PUBKEY:
+		return "taproot"
+	default:
+		return "unknown"
+	}
+}
+
+// addressDetailView shows everything known about a single address: where it
+// comes from, how much went through it, the transactions touching it and its
+// QR code.
+func (w *Wallet) addressDetailView(entry addressRow) (tview.Primitive, error) {
+	qrtxt, err := shared.GenerateQRText(entry.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	history := entry.History
+	if history == nil {
+		history = &addressHistory{}
+	}
+
+	derivationPath := entry.DerivationPath
+	if derivationPath == "" {
+		derivationPath = "-"
+	}
+
+	addressLabel := tview.NewTextView().SetDynamicColors(true).SetText("[gray::]Address:[-:-:-]")
+	addressLabel.SetBackgroundColor(tcell.ColorDefault)
+	address := components.NewCopyableText(w.load, entry.Address, fmt.Sprintf("%s (%s)", shortAddress(entry.Address), entry.TypeLabel))
+	address.SetBackgroundColor(tcell.ColorDefault)
+
+	info := tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	info.SetBackgroundColor(tcell.ColorDefault)
+	info.SetText(strings.Join([]string{
+		fmt.Sprintf("[gray::]Type:[-:-:-] %s (%s)", entry.TypeLabel, entry.AccountType),
+		fmt.Sprintf("[gray::]Derivation path:[-:-:-] %s", derivationPath),
+		fmt.Sprintf("[gray::]Balance:[-:-:-] %s", shared.FormatAmountView(entry.Balance, 6)),
+		fmt.Sprintf("[gray::]Total received:[-:-:-] [green:-:-]%s[-:-:-]", shared.FormatAmountView(history.Received, 6)),
+		fmt.Sprintf("[gray::]Total sent:[-:-:-] [red:-:-]%s[-:-:-]", shared.FormatAmountView(history.Sent, 6)),
+		"",
+		"[gray::]<c> copy address · <esc> back",
+	}, "\n"))
+
+	qrText := tview.NewTextView().SetWrap(false)
+	qrText.SetBackgroundColor(tcell.ColorDefault)
+	qrText.SetText(qrtxt).SetTextAlign(tview.AlignCenter)
+
+	netColor := shared.NetworkColor(*w.load.AppConfig.Network)
+	txTable := components.NewTable("Transactions", []components.Column{
+		{Name: "Timestamp", Align: tview.AlignLeft},
+		{Name: "Tx ID", Align: tview.AlignLeft},
+		{Name: "Amount", Align: tview.AlignRight},
+		{Name: "Confirmations", Align: tview.AlignRight},
+	}, netColor, 0)
+	txTable.SetBorderColor(shared.CurrentTheme().Primary)
+	txTable.AddCellRule("amount", components.Below(0), shared.CurrentTheme().Error).
+		AddCellRule("amount", components.Any, shared.CurrentTheme().Success)
+
+	if len(history.Entries) == 0 {
+		txTable.ShowPlaceholder("No transactions")
+	} else {
+		tipHeight := w.load.Cache.GetTipHeight()
+		rows := make([][]string, 0, len(history.Entries))
+		for _, e := range history.Entries {
+			rows = append(rows, []string{
+				shared.FormatUnixTime(e.Tx.TimeStamp),
+				shortTxID(e.Tx.TxHash),
+				shared.FormatAmountView(e.Amount, 6),
+				strconv.FormatInt(txConfirmations(e.Tx, tipHeight), 10),
+			})
+		}
+		txTable.Update(rows)
+		txTable.Select(1, 0)
+	}
+
+	details := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(addressLabel, 1, 0, false).
+		AddItem(address, 3, 0, false).
+		AddItem(info, 0, 1, false)
+	details.SetBorderPadding(1, 0, 2, 1)
+
+	top := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(details, 0, 1, false).
+		AddItem(qrText, 0, 1, false)
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(top, 20, 0, false).
+		AddItem(txTable, 0, 1, true)
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && (event.Rune() == 'c' || event.Rune() == 'C') {
+			address.Copy()
+			return nil
+		}
+		return event
+	})
+
+	return view, nil
+}
@@ -7,6 +7,7 @@ package wallet
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,10 +27,13 @@ const (
 )
 
 type addressRow struct {
-	TypeLabel string
-	Address   string
-	Balance   chainutil.Amount
-	TxCount   int
+	TypeLabel      string
+	AccountType    string
+	DerivationPath string
+	Address        string
+	Balance        chainutil.Amount
+	TxCount        int
+	History        *addressHistory
 }
 
 func (w *Wallet) showUsedAddresses() {
@@ -41,16 +45,18 @@ func (w *Wallet) showUsedAddresses() {
 
 	netColor := shared.NetworkColor(*w.load.AppConfig.Network)
 
-	columns := []components.Column{
-		{Name: "Type", Align: tview.AlignLeft},
-		{Name: "Address", Align: tview.AlignLeft},
-		{Name: "Balance", Align: tview.AlignRight},
-		{Name: "Tx Count", Align: tview.AlignRight},
-	}
-
-	table := components.NewTable("Used Addresses", columns, netColor, 0)
+	table := components.NewTable("Used Addresses", addressColumns(), netColor, 0)
+	table.SetVisibleColumns(splitColumnKeys(w.load.AppConfig.AddressColumns))
+	theme := shared.CurrentTheme()
+	table.AddCellRule("type", components.Equals("Change"), theme.Warning).
+		AddCellRule("type", components.Equals("External"), theme.Success).
+		AddCellRule("type", components.Any, theme.Muted).
+		AddCellRule("balance", components.Above(0), theme.Success).
+		AddCellRule("balance", components.Any, theme.Muted).
+		AddCellRule("txcount", components.Above(0), theme.Accent).
+		AddCellRule("txcount", components.Any, theme.Muted)
 	table.SetBorder(true)
-	table.SetBorderColor(tcell.ColorOrange)
+	table.SetBorderColor(shared.CurrentTheme().Primary)
 	table.SetTitle("")
 	table.SetBorderPadding(0, 0, 2, 2)
 	table.ShowPlaceholder("Loading addresses...")
@@ -65,12 +71,12 @@ func (w *Wallet) showUsedAddresses() {
 	searchField.SetLabel("Search: ")
 	searchField.SetFieldWidth(0)
 	searchField.SetPlaceholder("address prefix or substring")
-	searchField.SetPlaceholderTextColor(tcell.ColorWhite)
+	searchField.SetPlaceholderTextColor(shared.CurrentTheme().Text)
 	searchField.SetBorder(false)
 	searchField.SetBorderPadding(1, 1, 1, 1)
 
 	searchRow := tview.NewFlex().SetDirection(tview.FlexColumn)
-	searchRow.SetBackgroundColor(tcell.ColorOrange)
+	searchRow.SetBackgroundColor(shared.CurrentTheme().Modal)
 	searchRow.AddItem(tview.NewBox(), 1, 0, false).
 		AddItem(searchField, 0, 4, true).
 		AddItem(statusView, 0, 2, false).
@@ -78,13 +84,19 @@ func (w *Wallet) showUsedAddresses() {
 
 	container := tview.NewFlex().SetDirection(tview.FlexRow)
 	container.SetTitle("Addresses").
-		SetTitleColor(tcell.ColorGray).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
 		SetBorder(true).
-		SetBackgroundColor(tcell.ColorOrange)
+		SetBackgroundColor(shared.CurrentTheme().Modal)
 
-	container.AddItem(searchRow, 3, 0, true).
+	list := tview.NewFlex().SetDirection(tview.FlexRow)
+	list.AddItem(searchRow, 3, 0, true).
 		AddItem(table, 0, 1, true)
 
+	pages := tview.NewPages()
+	pages.AddPage("list", list, true, true)
+	container.AddItem(pages, 0, 1, true)
+	detailOpen := false
+
 	allRows := make([]addressRow, 0)
 	visibleRows := make([]addressRow, 0)
 	totalActive := 0
@@ -123,29 +135,12 @@ func (w *Wallet) showUsedAddresses() {
 			visibleRows = append(visibleRows, entry)
 
 			balance := shared.FormatAmountView(entry.Balance, 6)
-			balanceCell := fmt.Sprintf("[gray::]%s", balance)
-			if entry.Balance > 0 {
-				balanceCell = fmt.Sprintf("[green:-:-]%s", balance)
-			}
-			typeCell := entry.TypeLabel
-			switch entry.TypeLabel {
-			case "Change":
-				typeCell = "[yellow:-:-]Change"
-			case "External":
-				typeCell = "[green:-:-]External"
-			default:
-				typeCell = fmt.Sprintf("[gray::]%s", entry.TypeLabel)
-			}
-			txCell := fmt.Sprintf("[gray::]%d", entry.TxCount)
-			if entry.TxCount > 0 {
-				txCell = fmt.Sprintf("[%s:-:-]%d", tcell.ColorLightSkyBlue, entry.TxCount)
-			}
 			displayAddr := shortenAddressForDisplay(entry.Address)
 			data = append(data, []string{
-				typeCell,
+				entry.TypeLabel,
 				displayAddr,
-				balanceCell,
-				txCell,
+				balance,
+				strconv.Itoa(entry.TxCount),
 			})
 		}
 
@@ -196,23 +191,32 @@ func (w *Wallet) showUsedAddresses() {
 		w.load.Application.SetFocus(searchField)
 	}
 
-	copyAddress := func(row int) {
+	closeDetail := func() {
+		detailOpen = false
+		pages.RemovePage("detail")
+		pages.SwitchToPage("list")
+		container.SetTitle("Addresses")
+		w.load.Application.SetFocus(table)
+	}
+
+	showDetail := func(row int) {
 		if row <= 0 || row-1 >= len(visibleRows) {
 			return
 		}
 		entry := visibleRows[row-1]
-		if err := shared.ClipboardCopy(entry.Address); err != nil {
+		detail, err := w.addressDetailView(entry)
+		if err != nil {
 			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*10)
 			return
 		}
-		w.load.Notif.ShowToastWithTimeout(
-			fmt.Sprintf("📋 Copied %s (%s)", shortAddress(entry.Address), entry.TypeLabel),
-			time.Second*10,
-		)
+		detailOpen = true
+		container.SetTitle(fmt.Sprintf("Address %s", shortAddress(entry.Address)))
+		pages.AddAndSwitchToPage("detail", detail, true)
+		w.load.Application.SetFocus(detail)
 	}
 
 	table.SetSelectedFunc(func(row int, column int) {
-		copyAddress(row)
+		showDetail(row)
 	})
 
 	table.SetDoneFunc(func(key tcell.Key) {
@@ -234,8 +238,16 @@ func (w *Wallet) showUsedAddresses() {
 		}
 	})
 
+	var loadAddresses func(force bool)
+
 	container.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch {
+		case event.Key() == tcell.KeyCtrlR && !detailOpen:
+			loadAddresses(true)
+			return nil
+		case event.Key() == tcell.KeyEsc && detailOpen:
+			closeDetail()
+			return nil
 		case event.Key() == tcell.KeyEsc:
 			if strings.TrimSpace(searchField.GetText()) == "" {
 				w.closeModal()
@@ -253,33 +265,46 @@ func (w *Wallet) showUsedAddresses() {
 	w.nav.ShowModal(components.NewModal(container, 96, 30, w.closeModal))
 	w.load.Application.SetFocus(searchField)
 
-	go func() {
-		accounts, err := w.load.Wallet.ListAddresses()
-		txCounts, txErr := w.addressTransactionCounts()
-
-		if txCounts == nil {
-			txCounts = map[string]int{}
-		}
-
-		w.load.Application.QueueUpdateDraw(func() {
-			if err != nil {
-				table.ShowPlaceholder("Unable to load addresses")
-				w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*20)
-				updateTotal(0, 0)
+	ctx := w.nav.ModalContext()
+	// Ctrl+R lists the addresses again instead of reading the cache.
+	loadAddresses = func(force bool) {
+		go func() {
+			accounts, err := w.load.Wallet.ListAddressesWithOptions(ctx, flnd.ListAddressesOptions{ForceRefresh: force})
+			histories, txErr := w.addressHistories(ctx)
+			if ctx.Err() != nil {
 				return
 			}
-			if txErr != nil {
-				w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[yellow:-:-]Warning:[-:-:-] transactions unavailable: %s", txErr.Error()), time.Second*15)
-			}
 
-			allRows = buildAddressRows(accounts, txCounts)
-			totalActive = countActive(allRows)
-			applyFilter(strings.TrimSpace(searchField.GetText()))
-		})
-	}()
+			w.load.Application.QueueUpdateDraw(func() {
+				if err != nil {
+					table.ShowPlaceholder("Unable to load addresses")
+					w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*20)
+					updateTotal(0, 0)
+					return
+				}
+				if txErr != nil {
+					w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[yellow:-:-]Warning:[-:-:-] transactions unavailable: %s", txErr.Error()), time.Second*15)
+				}
+
+				allRows = buildAddressRows(accounts, histories)
+				totalActive = countActive(allRows)
+				applyFilter(strings.TrimSpace(searchField.GetText()))
+			})
+		}()
+	}
+	loadAddresses(false)
+}
+
+func addressColumns() []components.Column {
+	return []components.Column{
+		{Name: "Type", Align: tview.AlignLeft, Collapsible: true},
+		{Name: "Address", Align: tview.AlignLeft},
+		{Name: "Balance", Align: tview.AlignRight},
+		{Name: "Tx Count", Align: tview.AlignRight},
+	}
 }
 
-func buildAddressRows(accounts []*walletrpc.AccountWithAddresses, txCounts map[string]int) []addressRow {
+func buildAddressRows(accounts []*walletrpc.AccountWithAddresses, histories map[string]*addressHistory) []addressRow {
 	rows := make([]addressRow, 0)
 	for _, acct := range accounts {
 		if acct == nil {
@@ -299,11 +324,19 @@ func buildAddressRows(accounts []*walletrpc.AccountWithAddresses, txCounts map[s
 				typeLabel = "Change"
 			}
 
+			history := histories[address]
+			if history == nil {
+				history = &addressHistory{}
+			}
+
 			rows = append(rows, addressRow{
-				TypeLabel: typeLabel,
-				Address:   address,
-				Balance:   balance,
-				TxCount:   txCounts[address],
+				TypeLabel:      typeLabel,
+				AccountType:    formatAccountAddressType(acct.GetAddressType()),
+				DerivationPath: addr.GetDerivationPath(),
+				Address:        address,
+				Balance:        balance,
+				TxCount:        len(history.Entries),
+				History:        history,
 			})
 		}
 	}
@@ -379,39 +412,6 @@ func shortAddress(addr string) string {
 	return fmt.Sprintf("%s...%s", addr[:6], addr[len(addr)-6:])
 }
 
-func (w *Wallet) addressTransactionCounts() (map[string]int, error) {
-	txs, err := w.load.Wallet.FetchTransactionsWithOptions(flnd.FetchTransactionsOptions{
-		IgnoreLimit: true,
-	})
-	if err != nil {
-		return map[string]int{}, err
-	}
-
-	counts := make(map[string]int)
-	for _, tx := range txs {
-		if tx == nil {
-			continue
-		}
-		seen := make(map[string]struct{})
-		for _, detail := range tx.GetOutputDetails() {
-			if detail == nil {
-				continue
-			}
-			addr := strings.TrimSpace(detail.Address)
-			if addr == "" {
-				continue
-			}
-			if _, ok := seen[addr]; ok {
-				continue
-			}
-			counts[addr]++
-			seen[addr] = struct{}{}
-		}
-	}
-
-	return counts, nil
-}
-
 func shortenAddressForDisplay(address string) string {
 	if len(address) <= maxAddressDisplayLen {
 		return address
@@ -0,0 +1,56 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/load"
+)
+
+// bundleTimeout bounds the calls to the daemon gathering a support bundle.
+const bundleTimeout = 30 * time.Second
+
+// showSupportBundleDialog writes a support bundle to the file picked: the
+// version, the configuration without its secrets, the sync state, the peers
+// and the end of flnd.log, twallet.log and crash.log.
+func (w *Wallet) showSupportBundleDialog() {
+	name := fmt.Sprintf("twallet-support-%s.tar.gz", time.Now().Format(logExportStamp))
+	w.showFilePicker("Generate support bundle", components.FileSave, "~/"+name, []string{".gz"}, func(path string) error {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		go w.writeSupportBundle(f)
+		return nil
+	})
+}
+
+func (w *Wallet) writeSupportBundle(f *os.File) {
+	w.load.Notif.ShowToast("Generating the support bundle...")
+
+	logs := []string{w.logPath}
+	if dir := w.load.AppConfig.LogDir; dir != "" {
+		logs = append(logs, filepath.Join(dir, "twallet.log"), filepath.Join(dir, "crash.log"))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), bundleTimeout)
+	entries := w.load.SupportBundle(ctx, logs)
+	cancel()
+
+	err := load.WriteSupportBundle(f, entries, time.Now())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
+		return
+	}
+	w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[green:-:-]Support bundle written to %s, no seed or macaroon included", f.Name()), time.Second*15)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/go-flokicoin/chainutil"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/shared"
+)
+
+const chartDateLayout = "2006-01-02"
+
+// balanceHistory is the confirmed balance replayed over equal time slices
+// between the first confirmation and the end of the chart.
+type balanceHistory struct {
+	Start    time.Time
+	End      time.Time
+	Balances []chainutil.Amount // balance at the end of each slice
+	Deltas   []chainutil.Amount // net amount that moved during each slice
+	Peak     chainutil.Amount   // highest balance ever reached
+}
+
+func balanceSeries(txs []*lnrpc.Transaction, end time.Time, buckets int) *balanceHistory {
+	confirmed := make([]*lnrpc.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if tx != nil && tx.BlockHeight > 0 {
+			confirmed = append(confirmed, tx)
+		}
+	}
+	if len(confirmed) == 0 || buckets <= 0 {
+		return nil
+	}
+	sort.SliceStable(confirmed, func(i, j int) bool {
+		return confirmed[i].TimeStamp < confirmed[j].TimeStamp
+	})
+
+	h := &balanceHistory{
+		Start:    time.Unix(confirmed[0].TimeStamp, 0),
+		End:      end,
+		Balances: make([]chainutil.Amount, buckets),
+		Deltas:   make([]chainutil.Amount, buckets),
+	}
+	if last := time.Unix(confirmed[len(confirmed)-1].TimeStamp, 0); last.After(h.End) {
+		h.End = last
+	}
+	span := h.End.Sub(h.Start)
+
+	var balance chainutil.Amount
+	next := 0
+	for i := range buckets {
+		bucketEnd := h.Start.Add(span * time.Duration(i+1) / time.Duration(buckets))
+		for next < len(confirmed) && (i == buckets-1 || time.Unix(confirmed[next].TimeStamp, 0).Before(bucketEnd)) {
+			amount := chainutil.Amount(confirmed[next].Amount)
+			balance += amount
+			h.Deltas[i] += amount
+			h.Peak = max(h.Peak, balance)
+			next++
+		}
+		h.Balances[i] = balance
+	}
+
+	return h
+}
+
+func (w *Wallet) newBalanceChart() tview.Primitive {
+	chart := tview.NewBox()
+	chart.SetBackgroundColor(tcell.ColorDefault)
+	chart.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		w.drawBalanceChart(screen, x, y, width, height)
+		return x, y, width, height
+	})
+
+	netColor := shared.NetworkColor(*w.load.AppConfig.Network)
+	view := tview.NewFlex().SetDirection(tview.FlexRow)
+	view.SetBorder(true).
+		SetTitle(" Balance history ").
+		SetTitleAlign(tview.AlignCenter).
+		SetTitleColor(netColor).
+		SetBorderColor(netColor).
+		SetBorderPadding(1, 1, 2, 2)
+	view.AddItem(chart, 0, 1, false)
+	return view
+}
+
+// drawBalanceChart plots the confirmed balance from the cached transaction
+// list. Markers under the bars show the slices in which funds arrived or left.
+func (w *Wallet) drawBalanceChart(screen tcell.Screen, x, y, width, height int) {
+	theme := shared.CurrentTheme()
+
+	w.stateMu.Lock()
+	placeholder := w.placeholder
+	w.stateMu.Unlock()
+	if placeholder != "" {
+		tview.Print(screen, placeholder, x, y+height/2, width, tview.AlignCenter, theme.Muted)
+		return
+	}
+
+	w.txMu.Lock()
+	txs := append([]*lnrpc.Transaction(nil), w.txs...)
+	w.txMu.Unlock()
+
+	// Summary line, axis labels and the marker and date rows take 5 lines.
+	chartHeight := height - 5
+	if chartHeight < 3 || width < 30 {
+		tview.Print(screen, "Window too small for the chart", x, y+height/2, width, tview.AlignCenter, theme.Muted)
+		return
+	}
+
+	// The axis labels are sized for the peak balance, which does not depend on
+	// how the history is sliced, so measure it before fitting the plot.
+	now := time.Now()
+	history := balanceSeries(txs, now, 1)
+	if history == nil {
+		tview.Print(screen, "No confirmed transactions yet", x, y+height/2, width, tview.AlignCenter, theme.Muted)
+		return
+	}
+	peakLabel := shared.FormatAmountView(history.Peak, 2)
+	zeroLabel := shared.FormatAmountView(0, 2)
+	axisWidth := max(tview.TaggedStringWidth(peakLabel), tview.TaggedStringWidth(zeroLabel)) + 2
+	plotWidth := width - axisWidth
+	plotX := x + axisWidth
+
+	history = balanceSeries(txs, now, plotWidth)
+
+	low, high := history.Balances[0], history.Balances[0]
+	values := make([]float64, len(history.Balances))
+	for i, b := range history.Balances {
+		low = min(low, b)
+		high = max(high, b)
+		values[i] = float64(b)
+	}
+	current := history.Balances[len(history.Balances)-1]
+
+	summary := fmt.Sprintf("[gray::]Current:[-:-:-] %s   [gray::]High:[-:-:-] %s   [gray::]Low:[-:-:-] %s",
+		shared.FormatAmountView(current, 6), shared.FormatAmountView(high, 6), shared.FormatAmountView(low, 6))
+	tview.Print(screen, summary, x, y, width, tview.AlignLeft, theme.Text)
+
+	top := y + 2
+	tview.Print(screen, peakLabel, x, top, axisWidth-2, tview.AlignRight, theme.Muted)
+	tview.Print(screen, zeroLabel, x, top+chartHeight-1, axisWidth-2, tview.AlignRight, theme.Muted)
+	for i, line := range shared.BlockChart(values, float64(history.Peak), chartHeight) {
+		screen.SetContent(plotX-1, top+i, tview.BoxDrawingsLightVertical, nil, tcell.StyleDefault.Foreground(theme.Muted))
+		tview.Print(screen, line, plotX, top+i, plotWidth, tview.AlignLeft, theme.Primary)
+	}
+
+	var markers strings.Builder
+	for _, delta := range history.Deltas {
+		switch {
+		case delta > 0:
+			markers.WriteString("[green::]▲")
+		case delta < 0:
+			markers.WriteString("[red::]▼")
+		default:
+			markers.WriteString(" ")
+		}
+	}
+	tview.Print(screen, markers.String(), plotX, top+chartHeight, plotWidth, tview.AlignLeft, theme.Text)
+
+	dates := top + chartHeight + 1
+	tview.Print(screen, history.Start.In(shared.TimeZone()).Format(chartDateLayout), plotX, dates, plotWidth, tview.AlignLeft, theme.Muted)
+	tview.Print(screen, history.End.In(shared.TimeZone()).Format(chartDateLayout), plotX, dates, plotWidth, tview.AlignRight, theme.Muted)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+	"unicode"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/go-flokicoin/chainutil"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/shared"
+)
+
+// coinGrouping is how the coins view groups the wallet UTXOs.
+type coinGrouping int
+
+const (
+	groupByAddress coinGrouping = iota
+	groupByLabel
+)
+
+const unlabeledCoins = "(no label)"
+
+// showCoins lists the wallet UTXOs in a tree, grouped by address or by the
+// label of the transaction that created them.
+func (w *Wallet) showCoins() {
+	w.load.Notif.CancelToast()
+
+	tree := components.NewTree().SetPlaceholder("No unspent outputs")
+	tree.SetBackgroundColor(shared.CurrentTheme().Modal)
+	tree.SetSelectedFunc(func(item *components.TreeItem) {
+		utxo, _ := item.Reference.(*lnrpc.Utxo)
+		if utxo == nil || utxo.Outpoint == nil {
+			return
+		}
+		components.CopyText(w.load, outpointString(utxo.Outpoint), "outpoint "+shortTxID(utxo.Outpoint.TxidStr))
+	})
+
+	accent := shared.CurrentTheme().Accent
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s:-:-]<enter>[gray:-:-] expand / copy outpoint  [%s:-:-]<+/->[gray:-:-] expand / collapse all  [%s:-:-]<g>[gray:-:-] group by  [%s:-:-]<r>[gray:-:-] reload  [%s:-:-]<esc>[gray:-:-] close",
+			accent, accent, accent, accent, accent))
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetTitle("Coins").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	container.AddItem(tree, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	grouping := groupByAddress
+	var utxos []*lnrpc.Utxo
+
+	render := func() {
+		items := w.groupCoins(utxos, grouping)
+		by := "address"
+		if grouping == groupByLabel {
+			by = "label"
+		}
+		container.SetTitle(fmt.Sprintf("Coins · %d UTXOs by %s", len(utxos), by))
+		tree.SetItems(items)
+	}
+
+	reload := func() {
+		container.SetTitle("Coins · loading…")
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			list, err := w.load.Wallet.ListUnspent(ctx, 0, math.MaxInt32)
+			w.load.QueueUpdateDraw(func() {
+				if err != nil {
+					w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*10)
+					return
+				}
+				utxos = list
+				render()
+			})
+		}()
+	}
+
+	container.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		switch unicode.ToLower(event.Rune()) {
+		case 'g':
+			if grouping == groupByAddress {
+				grouping = groupByLabel
+			} else {
+				grouping = groupByAddress
+			}
+			render()
+			return nil
+		case 'r':
+			reload()
+			return nil
+		}
+		return event
+	})
+
+	w.nav.ShowModal(components.NewModal(container, 110, 30, w.closeModal))
+	w.load.Application.SetFocus(tree)
+	reload()
+}
+
+// groupCoins builds the tree of utxos, largest groups first and the coins of
+// a group largest first.
+func (w *Wallet) groupCoins(utxos []*lnrpc.Utxo, grouping coinGrouping) []*components.TreeItem {
+	labels := make(map[string]string)
+	if grouping == groupByLabel {
+		w.txMu.Lock()
+		for _, tx := range w.txs {
+			if tx != nil && tx.Label != "" {
+				labels[tx.TxHash] = tx.Label
+			}
+		}
+		w.txMu.Unlock()
+	}
+
+	groups := make(map[string]*components.TreeItem)
+	var items []*components.TreeItem
+	for _, utxo := range utxos {
+		if utxo == nil || utxo.Outpoint == nil {
+			continue
+		}
+		key := utxo.Address
+		if grouping == groupByLabel {
+			key = labels[utxo.Outpoint.TxidStr]
+			if key == "" {
+				key = unlabeledCoins
+			}
+		}
+		group := groups[key]
+		if group == nil {
+			group = &components.TreeItem{Label: key}
+			groups[key] = group
+			items = append(items, group)
+		}
+		group.Children = append(group.Children, &components.TreeItem{
+			Label:     fmt.Sprintf("%s · %d conf", outpointString(utxo.Outpoint), utxo.Confirmations),
+			Amount:    chainutil.Amount(utxo.AmountSat),
+			Reference: utxo,
+		})
+	}
+
+	for _, group := range items {
+		sort.SliceStable(group.Children, func(i, j int) bool {
+			return group.Children[i].Amount > group.Children[j].Amount
+		})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Total() > items[j].Total()
+	})
+	return items
+}
+
+func outpointString(op *lnrpc.OutPoint) string {
+	return fmt.Sprintf("%s:%d", op.TxidStr, op.OutputIndex)
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/shared"
+)
+
+const (
+	transactionColumnsOption = "columns.transactions"
+	addressColumnsOption     = "columns.addresses"
+)
+
+type columnChoice struct {
+	column  components.Column
+	checked bool
+}
+
+func (w *Wallet) showColumnPicker() {
+	if w.load == nil || w.load.AppConfig == nil {
+		return
+	}
+
+	w.load.Notif.CancelToast()
+
+	txChoices := columnChoices(transactionColumns(), splitColumnKeys(w.load.AppConfig.TransactionColumns))
+	addrChoices := columnChoices(addressColumns(), splitColumnKeys(w.load.AppConfig.AddressColumns))
+
+	form := tview.NewForm()
+	form.SetBackgroundColor(tcell.ColorDefault).
+		SetBorderPadding(1, 1, 2, 2)
+
+	addChoices := func(title string, choices []*columnChoice) {
+		form.AddTextView(title, "", 0, 1, true, false)
+		for _, choice := range choices {
+			form.AddCheckbox("  "+choice.column.Name, choice.checked, func(checked bool) {
+				choice.checked = checked
+			})
+		}
+	}
+	addChoices("[::b]Transactions", txChoices)
+	addChoices("[::b]Addresses", addrChoices)
+
+	form.AddButton("Cancel", w.closeModal)
+	form.AddButton("Save", func() {
+		txKeys := checkedColumnKeys(txChoices)
+		addrKeys := checkedColumnKeys(addrChoices)
+		if len(txKeys) == 0 || len(addrKeys) == 0 {
+			w.load.Notif.ShowToastWithTimeout("[red:-:-]Error:[-:-:-] each table needs at least one column", time.Second*10)
+			return
+		}
+
+		cfg := w.load.AppConfig
+		txValue, addrValue := strings.Join(txKeys, ","), strings.Join(addrKeys, ",")
+		if err := cfg.SaveOption(transactionColumnsOption, txValue); err != nil {
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*15)
+			return
+		}
+		if err := cfg.SaveOption(addressColumnsOption, addrValue); err != nil {
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*15)
+			return
+		}
+		cfg.TransactionColumns = txValue
+		cfg.AddressColumns = addrValue
+
+		w.table.SetVisibleColumns(txKeys)
+
+		w.closeModal()
+		w.load.Notif.ShowToastWithTimeout("Columns saved", time.Second*5)
+	})
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetTitle("Columns").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	container.AddItem(form, 0, 1, true)
+
+	height := len(txChoices) + len(addrChoices) + 9
+	w.nav.ShowModal(components.NewModal(container, 44, height, w.closeModal))
+	w.load.Application.SetFocus(form)
+}
+
+// columnChoices mirrors Table.SetVisibleColumns: when none of keys names a
+// known column, every column is checked.
+func columnChoices(columns []components.Column, keys []string) []*columnChoice {
+	selected := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		selected[key] = struct{}{}
+	}
+
+	known := 0
+	for _, column := range columns {
+		if _, ok := selected[column.ConfigKey()]; ok {
+			known++
+		}
+	}
+
+	choices := make([]*columnChoice, 0, len(columns))
+	for _, column := range columns {
+		_, ok := selected[column.ConfigKey()]
+		choices = append(choices, &columnChoice{column: column, checked: known == 0 || ok})
+	}
+	return choices
+}
+
+func checkedColumnKeys(choices []*columnChoice) []string {
+	keys := make([]string, 0, len(choices))
+	for _, choice := range choices {
+		if choice.checked {
+			keys = append(keys, choice.column.ConfigKey())
+		}
+	}
+	return keys
+}
+
+func splitColumnKeys(value string) []string {
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/shared"
+)
+
+// debugTransitions is the number of state transitions the debug info lists.
+const debugTransitions = 5
+
+// showDebugInfo tells in one place what state the wallet is in: where it
+// runs from, the chain tip, the peers, how long the daemon has been up, the
+// caches, the RPC latencies and the last state transitions.
+func (w *Wallet) showDebugInfo() {
+	w.load.Notif.CancelToast()
+
+	accent := shared.CurrentTheme().Accent
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetText("[gray::]Loading...")
+	view.SetBorderPadding(1, 0, 2, 2)
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s:-:-]<esc>[gray:-:-] close  [%s:-:-]<r>[gray:-:-] reload", accent, accent))
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetTitle("Debug info").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	container.AddItem(view, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	render := func() {
+		go func() {
+			text := w.debugInfoText()
+			w.load.Application.QueueUpdateDraw(func() {
+				view.SetText(text)
+			})
+		}()
+	}
+	container.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && unicode.ToLower(event.Rune()) == 'r' {
+			render()
+			return nil
+		}
+		return event
+	})
+
+	w.nav.ShowModal(components.NewModal(container, 100, 28, w.closeModal))
+	render()
+	w.load.Application.SetFocus(view)
+}
+
+func (w *Wallet) debugInfoText() string {
+	theme := shared.CurrentTheme()
+	var b strings.Builder
+	field := func(name, format string, args ...any) {
+		fmt.Fprintf(&b, "[%s:-:-]%-13s[-:-:-] %s\n", theme.Accent, name+":", fmt.Sprintf(format, args...))
+	}
+	failed := func(err error) string {
+		return fmt.Sprintf("[%s:-:-]%s[-:-:-]", theme.Error, tview.Escape(err.Error()))
+	}
+
+	cfg := w.load.AppConfig
+	svc := w.load.Wallet
+	field("Wallet dir", "%s", tview.Escape(cfg.Walletdir))
+	if cfg.Network != nil {
+		field("Network", "%s", cfg.Network.Name)
+	}
+	field("State", "%s", svc.Status())
+	if uptime := svc.Uptime(); uptime > 0 {
+		field("Daemon up", "%s", uptime.Round(time.Second))
+	} else {
+		field("Daemon up", "[gray::]not running")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if info, err := svc.NetworkInfo(ctx); err != nil {
+		field("Tip", "%s", failed(err))
+		field("Peers", "%s", failed(err))
+	} else {
+		synced := "syncing"
+		if ok, _, _, err := svc.IsSynced(); err == nil && ok {
+			synced = "synced"
+		}
+		field("Tip", "%d %s [gray::](%s)", info.BlockHeight, info.BlockHash, synced)
+		chainPeers := fmt.Sprintf("%d chain", info.ChainPeers)
+		if info.ChainPeers < 0 {
+			chainPeers = "[gray::]chain unknown[-:-:-]"
+		}
+		field("Peers", "%s, %d lightning", chainPeers, info.LightningPeers)
+	}
+
+	if stats, err := svc.TxCacheStats(); err != nil {
+		field("Tx cache", "%s", failed(err))
+	} else {
+		capped := ""
+		if stats.Truncated {
+			capped = ", capped"
+		}
+		field("Tx cache", "%d txs ≈ %s%s", stats.Transactions, formatByteSize(stats.Bytes), capped)
+	}
+	w.txMu.Lock()
+	rows := len(w.txs)
+	w.txMu.Unlock()
+	w.logMu.Lock()
+	logLines := len(w.logLines)
+	w.logMu.Unlock()
+	field("Views", "%d transactions, %d log lines", rows, logLines)
+
+	var calls, errs uint64
+	var total, slowest time.Duration
+	slowestMethod := ""
+	for _, stat := range svc.RPCStats() {
+		calls += stat.Calls
+		errs += stat.Errors
+		total += stat.Total
+		if stat.Max > slowest {
+			slowest, slowestMethod = stat.Max, stat.Method
+		}
+	}
+	if calls == 0 {
+		field("RPC", "[gray::]no calls yet")
+	} else {
+		field("RPC", "%d calls, %d errors, mean %s, slowest %s %s [gray::](p in the logs for more)",
+			calls, errs, formatRPCDuration(total/time.Duration(calls)), slowestMethod, formatRPCDuration(slowest))
+	}
+
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "[%s:-:-]Last state transitions[-:-:-]\n", theme.Accent)
+	transitions := svc.StateTransitions(debugTransitions)
+	if len(transitions) == 0 {
+		b.WriteString("  [gray::]none yet\n")
+	}
+	for i := len(transitions) - 1; i >= 0; i-- {
+		t := transitions[i]
+		fmt.Fprintf(&b, "  %s  %s → %s", t.Time.Format("2006-01-02 15:04:05"), t.From, t.To)
+		if t.Err != nil {
+			fmt.Fprintf(&b, "  %s", failed(t.Err))
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
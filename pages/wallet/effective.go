@@ -0,0 +1,57 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/config"
+	"github.com/flokiorg/twallet/shared"
+)
+
+// showEffectiveConfig lists the options twallet runs with, wherever they
+// came from, to tell which wallet directory or fee API is in use without
+// reading the flags, the config file and the defaults together.
+func (w *Wallet) showEffectiveConfig() {
+	w.load.Notif.CancelToast()
+
+	accent := shared.CurrentTheme().Accent
+	var b strings.Builder
+	for _, setting := range config.Effective(w.load.AppConfig) {
+		value := strings.Join(setting.Values, ", ")
+		if value == "" {
+			value = "[gray::]-"
+		} else {
+			value = tview.Escape(value)
+		}
+		fmt.Fprintf(&b, "[%s:-:-]%s[-:-:-] %s\n", accent, setting.Key, value)
+	}
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetText(strings.TrimSuffix(b.String(), "\n"))
+	view.SetBorderPadding(0, 0, 1, 1)
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s:-:-]<esc>[gray:-:-] close  [gray:-:-]secrets hidden", accent))
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetTitle("Configuration").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	container.AddItem(view, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	w.nav.ShowModal(components.NewModal(container, 100, 30, w.closeModal))
+	w.load.Application.SetFocus(view)
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/flnd"
+	"github.com/flokiorg/twallet/shared"
+)
+
+// showDateFilter picks the days the transactions table is narrowed to.
+func (w *Wallet) showDateFilter() {
+	w.txMu.Lock()
+	current := w.dateFilter
+	w.txMu.Unlock()
+
+	picker := components.NewDatePicker(current).
+		SetRanged(true).
+		SetCancelFunc(w.closeModal)
+	picker.SetBackgroundColor(shared.CurrentTheme().Modal)
+	picker.SetDoneFunc(func(r shared.DateRange) {
+		w.closeModal()
+		w.setDateFilter(r)
+	})
+
+	w.nav.ShowModal(components.NewModal(datePickerFrame(picker, "Filter by date"), 34, components.DatePickerHeight+2, nil))
+	w.load.Application.SetFocus(picker)
+}
+
+// setDateFilter narrows the transactions table to r, or shows them all for
+// the zero range.
+func (w *Wallet) setDateFilter(r shared.DateRange) {
+	w.txMu.Lock()
+	w.dateFilter = r
+	w.txMu.Unlock()
+
+	label := ""
+	if !r.IsZero() {
+		label = r.String()
+	}
+	w.table.SetFilterLabel(label)
+	go w.renderRows()
+}
+
+// showExportDialog writes the transactions of the picked days to a CSV
+// file, the whole history rather than only the rows the table keeps.
+func (w *Wallet) showExportDialog() {
+	w.txMu.Lock()
+	current := w.dateFilter
+	w.txMu.Unlock()
+
+	picker := components.NewDatePicker(current).
+		SetRanged(true).
+		SetCancelFunc(w.closeModal)
+	picker.SetBackgroundColor(shared.CurrentTheme().Modal)
+	picker.SetDoneFunc(func(r shared.DateRange) {
+		name := "transactions.csv"
+		if !r.IsZero() {
+			name = fmt.Sprintf("transactions-%s.csv", strings.NewReplacer(" → ", "_", " ", "-").Replace(r.String()))
+		}
+		w.showFilePicker("Export transactions", components.FileSave, "~/"+name, []string{".csv"}, func(path string) error {
+			// Opening the file here keeps the picker up on a path that
+			// cannot be written; the history is fetched in the background.
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+			if err != nil {
+				return err
+			}
+			// The file picker closes itself once this returns, the date
+			// picker under it goes after.
+			w.load.Application.QueueUpdateDraw(func() {
+				w.closeModal()
+				go w.exportTransactions(f, r)
+			})
+			return nil
+		})
+	})
+
+	w.nav.ShowModal(components.NewModal(datePickerFrame(picker, "Export transactions"), 34, components.DatePickerHeight+2, nil))
+	w.load.Application.SetFocus(picker)
+}
+
+func datePickerFrame(picker *components.DatePicker, title string) tview.Primitive {
+	frame := tview.NewFlex().SetDirection(tview.FlexRow)
+	frame.SetTitle(title).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	frame.AddItem(picker, 0, 1, true)
+	return frame
+}
+
+// exportTransactions writes the wallet transactions made on the days of r
+// to f, oldest first, and reports the outcome in a toast.
+func (w *Wallet) exportTransactions(f *os.File, r shared.DateRange) {
+	w.load.Notif.ShowToast("Exporting transactions...")
+	count, err := w.writeTransactions(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
+		return
+	}
+	w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[green:-:-]Exported %d transaction(s) to %s", count, f.Name()), time.Second*10)
+}
+
+func (w *Wallet) writeTransactions(f *os.File, r shared.DateRange) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	txs, err := w.load.Wallet.FetchTransactionsWithOptions(ctx, flnd.FetchTransactionsOptions{IgnoreLimit: true})
+	if err != nil {
+		return 0, err
+	}
+	txs = filterTransactionsByDate(txs, r)
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].TimeStamp < txs[j].TimeStamp })
+	if err := writeTransactionsCSV(f, txs, w.load.Cache.GetTipHeight()); err != nil {
+		return 0, err
+	}
+	return len(txs), nil
+}
+
+// writeTransactionsCSV writes plain values, amounts in loki, so the file
+// sorts and sums in a spreadsheet.
+func writeTransactionsCSV(f *os.File, txs []*lnrpc.Transaction, tipHeight int32) error {
+	out := csv.NewWriter(f)
+	if err := out.Write([]string{"time", "txid", "amount_loki", "fee_loki", "confirmations", "block_height", "label", "addresses"}); err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		addresses := make([]string, 0, len(tx.OutputDetails))
+		for _, detail := range tx.OutputDetails {
+			if detail != nil && detail.Address != "" {
+				addresses = append(addresses, detail.Address)
+			}
+		}
+		if err := out.Write([]string{
+			shared.FormatExportTime(time.Unix(tx.TimeStamp, 0)),
+			tx.TxHash,
+			strconv.FormatInt(tx.Amount, 10),
+			strconv.FormatInt(tx.TotalFees, 10),
+			strconv.FormatInt(txConfirmations(tx, tipHeight), 10),
+			strconv.FormatInt(int64(tx.BlockHeight), 10),
+			tx.Label,
+			strings.Join(addresses, " "),
+		}); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// filterTransactionsByDate keeps the transactions made on the days of r,
+// all of them for the zero range.
+func filterTransactionsByDate(txs []*lnrpc.Transaction, r shared.DateRange) []*lnrpc.Transaction {
+	if r.IsZero() {
+		return txs
+	}
+	kept := make([]*lnrpc.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if r.Contains(time.Unix(tx.TimeStamp, 0)) {
+			kept = append(kept, tx)
+		}
+	}
+	return kept
+}
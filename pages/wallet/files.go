@@ -0,0 +1,43 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/shared"
+)
+
+// showFilePicker opens a file picker over the current modal. The picker
+// closes once done accepts the chosen path; an error from done is shown and
+// the picker stays open to choose another.
+func (w *Wallet) showFilePicker(title string, mode components.FilePickerMode, path string, extensions []string, done func(path string) error) {
+	picker := components.NewFilePicker(w.load.Application, mode, path).
+		SetExtensions(extensions...).
+		SetCancelFunc(w.nav.CloseModal)
+	picker.SetSelectedFunc(func(path string) {
+		if err := done(path); err != nil {
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
+			return
+		}
+		w.nav.CloseModal()
+	})
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow)
+	view.SetTitle(title).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	view.AddItem(picker, 0, 1, true)
+
+	// The picker handles Esc itself, to close its overwrite confirmation
+	// first.
+	w.nav.PushModal(components.NewModal(view, 70, 22, nil))
+	w.load.Application.SetFocus(picker)
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/flokiorg/go-flokicoin/chainutil"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/load"
+	"github.com/flokiorg/twallet/shared"
+)
+
+const journalViewLimit = 1000
+
+// showJournal lists the latest entries of the event journal, newest first.
+func (w *Wallet) showJournal() {
+	if w.load == nil || w.load.Journal == nil {
+		return
+	}
+
+	w.load.Notif.CancelToast()
+
+	columns := []components.Column{
+		{Name: "Time", Align: tview.AlignLeft},
+		{Name: "Event", Align: tview.AlignLeft},
+		{Name: "Amount", Align: tview.AlignRight},
+		{Name: "Details", Align: tview.AlignLeft},
+	}
+
+	table := components.NewTable("Journal", columns, shared.NetworkColor(*w.load.AppConfig.Network), 0)
+	table.SetBorder(false)
+	table.SetBorderPadding(0, 0, 1, 1)
+	theme := shared.CurrentTheme()
+	table.AddCellRule("amount", components.Above(0), theme.Success).
+		AddCellRule("amount", components.Below(0), theme.Error).
+		AddCellRule("amount", components.Equals("-"), theme.Muted)
+
+	accent := shared.CurrentTheme().Accent
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[gray::]%s\n[%s:-:-]<esc>[gray:-:-] close  [%s:-:-]<r>[gray:-:-] reload  [%s:-:-]<c>[gray:-:-] copy txid",
+			tview.Escape(w.load.Journal.Path()), accent, accent, accent))
+
+	var entries []load.JournalEntry
+
+	render := func() {
+		var err error
+		entries, err = w.load.Journal.Entries(journalViewLimit)
+		if err != nil {
+			entries = nil
+			table.ShowPlaceholder(fmt.Sprintf("Journal unavailable: %s", err.Error()))
+			return
+		}
+		if len(entries) == 0 {
+			table.ShowPlaceholder("No journal entries yet.")
+			return
+		}
+		rows := make([][]string, 0, len(entries))
+		for _, entry := range entries {
+			rows = append(rows, []string{
+				fmt.Sprintf("[gray::]%s", shared.FormatTime(entry.Time)),
+				journalKindCell(entry.Kind),
+				journalAmountCell(entry.Amount),
+				tview.Escape(journalDetails(entry)),
+			})
+		}
+		table.Update(rows)
+		table.Select(1, 0)
+		table.ScrollToBeginning()
+	}
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetTitle("Journal").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	container.AddItem(table, 0, 1, true).
+		AddItem(hint, 2, 0, false)
+
+	container.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		switch unicode.ToLower(event.Rune()) {
+		case 'r':
+			render()
+			return nil
+		case 'c':
+			row, _ := table.GetSelection()
+			if row <= 0 || row-1 >= len(entries) || entries[row-1].Txid == "" {
+				w.load.Notif.ShowToastWithTimeout("[yellow:-:-]No transaction on this entry", time.Second*5)
+				return nil
+			}
+			components.CopyText(w.load, entries[row-1].Txid, shortTxID(entries[row-1].Txid))
+			return nil
+		}
+		return event
+	})
+
+	w.nav.ShowModal(components.NewModal(container, 120, 30, w.closeModal))
+	render()
+	w.load.Application.SetFocus(table)
+}
+
+func journalKindCell(kind load.JournalKind) string {
+	theme := shared.CurrentTheme()
+	switch kind {
+	case load.JournalReceive:
+		return fmt.Sprintf("[%s:-:-]%s", theme.Success, kind)
+	case load.JournalSend, load.JournalFeeBump:
+		return fmt.Sprintf("[%s:-:-]%s", theme.Error, kind)
+	case load.JournalDaemonDown, load.JournalRestart:
+		return fmt.Sprintf("[%s:-:-]%s", theme.Warning, kind)
+	default:
+		return fmt.Sprintf("[%s:-:-]%s", theme.Muted, kind)
+	}
+}
+
+func journalAmountCell(amount int64) string {
+	switch {
+	case amount > 0:
+		return "+" + shared.FormatAmountView(chainutil.Amount(amount), 6)
+	case amount < 0:
+		return shared.FormatAmountView(chainutil.Amount(amount), 6)
+	default:
+		return "-"
+	}
+}
+
+func journalDetails(entry load.JournalEntry) string {
+	var parts []string
+	if entry.Txid != "" {
+		parts = append(parts, entry.Txid)
+	}
+	if entry.Address != "" {
+		parts = append(parts, "to "+shortenAddressForDisplay(entry.Address))
+	}
+	if entry.Fee != 0 {
+		parts = append(parts, "fee "+shared.FormatAmountView(chainutil.Amount(entry.Fee), 8))
+	}
+	if entry.Message != "" {
+		parts = append(parts, entry.Message)
+	}
+	return strings.Join(parts, " · ")
+}
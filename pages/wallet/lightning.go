@@ -5,7 +5,9 @@
 package wallet
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -23,12 +25,12 @@ func (w *Wallet) showLightningConfigView() {
 
 	w.load.Notif.CancelToast()
 
-	cfg, err := w.load.Wallet.GetLightningConfig()
+	cfg, err := w.load.Wallet.GetLightningConfig(context.Background())
 	if err != nil {
 		if err == flnd.ErrDaemonNotRunning {
-			w.load.Notif.ShowToast("[red:-:-]Wallet not running")
+			w.load.Notif.ShowToastWithTimeout("[red:-:-]Wallet not running", time.Second*30)
 		} else {
-			w.load.Notif.ShowToast(fmt.Sprintf("[red:-:-]Error: %v", err))
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error: %v", err), time.Second*30)
 		}
 		return
 	}
@@ -58,17 +60,72 @@ func (w *Wallet) showLightningConfigView() {
 
 	formHub := createBaseForm()
 	addField(formHub, "RPC Address", cfg.RpcAddress)
-	addField(formHub, "Macaroon Hex", cfg.MacaroonHex)
+	scopes := make([]string, len(flnd.MacaroonScopes))
+	for i, scope := range flnd.MacaroonScopes {
+		scopes[i] = scope.String()
+	}
+	scopeDropDown := tview.NewDropDown().
+		SetLabel("Macaroon Scope").
+		SetOptions(scopes, nil)
+	scopeDropDown.SetFieldBackgroundColor(tview.Styles.ContrastBackgroundColor)
+	scopeDropDown.SetFieldTextColor(tview.Styles.PrimaryTextColor)
+	scopeDropDown.SetLabelColor(tview.Styles.SecondaryTextColor)
+	formHub.AddFormItem(scopeDropDown)
+	addField(formHub, "Macaroon Hex", "")
+	macaroonField := formHub.GetFormItem(formHub.GetFormItemCount() - 1).(*tview.InputField)
 	addField(formHub, "TLS Cert Hex", cfg.TLSCertHex)
 
+	// The admin macaroon gives full control of the wallet, it is only shown
+	// once picked. The read-only one is shown first and the other scopes are
+	// baked on demand, so an application only gets what it needs.
+	var scope flnd.MacaroonScope
+	cfg.MacaroonHex = ""
+	baking := false
+	selectScope := func(next flnd.MacaroonScope) {
+		if next == scope || baking {
+			return
+		}
+		baking = true
+		macaroonField.SetText("baking…")
+		go func() {
+			mac, err := w.load.Wallet.ScopedMacaroon(context.Background(), next)
+			w.load.QueueUpdateDraw(func() {
+				baking = false
+				if err != nil {
+					macaroonField.SetText(cfg.MacaroonHex)
+					if scope != "" {
+						scopeDropDown.SetCurrentOption(slices.Index(flnd.MacaroonScopes, scope))
+					}
+					w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %v", err), 5*time.Second)
+					return
+				}
+				scope = next
+				cfg.MacaroonHex = mac
+				macaroonField.SetText(mac)
+				if scope == flnd.MacaroonAdmin {
+					w.load.Notif.ShowToastWithTimeout("[orange:-:-]The admin macaroon gives full control of the wallet, only hand it to software you trust", 10*time.Second)
+				}
+			})
+		}()
+	}
+	scopeDropDown.SetSelectedFunc(func(_ string, index int) {
+		selectScope(flnd.MacaroonScopes[index])
+	})
+	scopeDropDown.SetCurrentOption(slices.Index(flnd.MacaroonScopes, flnd.MacaroonReadOnly))
+
 	formNode := createBaseForm()
 	addField(formNode, "Peer Address", cfg.PeerAddress)
 	addField(formNode, "Identity PubKey", cfg.PubKey)
 	addField(formNode, "Alias", cfg.Alias)
 
 	copyFunc := func() {
+		if cfg.MacaroonHex == "" {
+			w.load.Notif.ShowToastWithTimeout("[red:-:-]Error:[-:-:-] no macaroon yet, pick a scope", 5*time.Second)
+			return
+		}
 		var sb strings.Builder
 		sb.WriteString(fmt.Sprintf("RPC Address: %s\n", cfg.RpcAddress))
+		sb.WriteString(fmt.Sprintf("Macaroon Scope: %s\n", scope))
 		sb.WriteString(fmt.Sprintf("Macaroon Hex: %s\n", cfg.MacaroonHex))
 		sb.WriteString(fmt.Sprintf("TLS Cert Hex: %s\n", cfg.TLSCertHex))
 		sb.WriteString("\n\n")
@@ -76,11 +133,7 @@ func (w *Wallet) showLightningConfigView() {
 		sb.WriteString(fmt.Sprintf("Identity PubKey: %s\n", cfg.PubKey))
 		sb.WriteString(fmt.Sprintf("Alias: %s\n", cfg.Alias))
 
-		if err := shared.ClipboardCopy(sb.String()); err != nil {
-			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Copy failed:[-:-:-] %v", err), 2*time.Second)
-			return
-		}
-		w.load.Notif.ShowToastWithTimeout("📋 Configuration copied!", 2*time.Second)
+		components.CopyText(w.load, sb.String(), "configuration")
 	}
 
 	cpyBtn := components.NewConfirmButton(w.nav.Application, "Copy Config", true, bgColor, 3, copyFunc)
@@ -98,7 +151,7 @@ func (w *Wallet) showLightningConfigView() {
 	middleContainer.AddItem(formHub, 0, 1, false)
 	middleContainer.AddItem(tview.NewTextView().SetBackgroundColor(bgColor), 1, 0, false)
 
-	sep := tview.NewTextView().SetTextColor(tcell.ColorDarkGray).SetDynamicColors(true)
+	sep := tview.NewTextView().SetTextColor(shared.CurrentTheme().Muted).SetDynamicColors(true)
 	sep.SetBackgroundColor(bgColor)
 	sep.SetText(strings.Repeat("─", 100))
 	middleContainer.AddItem(sep, 1, 0, false)
@@ -110,8 +163,8 @@ func (w *Wallet) showLightningConfigView() {
 
 	borderedContainer := tview.NewFlex().SetDirection(tview.FlexColumn)
 	borderedContainer.SetTitle(" Lightning Connection Details ").
-		SetTitleColor(tcell.ColorGray).
-		SetBackgroundColor(tcell.ColorOrange).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
 		SetBorder(true)
 
 	borderedContainer.AddItem(tview.NewTextView().SetBackgroundColor(bgColor), 2, 1, false)
@@ -119,5 +172,5 @@ func (w *Wallet) showLightningConfigView() {
 	borderedContainer.AddItem(tview.NewTextView().SetBackgroundColor(bgColor), 2, 1, false)
 
 	// Modal
-	w.nav.ShowModal(components.NewModal(borderedContainer, 85, 22, w.closeModal))
+	w.nav.ShowModal(components.NewModal(borderedContainer, 85, 23, w.closeModal))
 }
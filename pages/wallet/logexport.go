@@ -0,0 +1,61 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/flokiorg/twallet/components"
+)
+
+// logExportStamp names the files the log view is saved to.
+const logExportStamp = "20060102-150405"
+
+// visibleLogText returns the lines the log view shows, through its level
+// filter, and the name of their source. It is empty while the view shows a
+// status instead of a log.
+func (w *Wallet) visibleLogText() (string, string) {
+	w.logMu.Lock()
+	defer w.logMu.Unlock()
+	if w.logStatus != "" {
+		return "", w.logSource.label
+	}
+	lines := w.shownLogLines()
+	if len(lines) == 0 {
+		return "", w.logSource.label
+	}
+	return strings.Join(lines, "\n") + "\n", w.logSource.label
+}
+
+// copyVisibleLog copies the lines the log view shows to the clipboard.
+func (w *Wallet) copyVisibleLog() {
+	text, source := w.visibleLogText()
+	if text == "" {
+		w.load.Notif.ShowToastWithTimeout("[red:-:-]Error:[-:-:-] no log lines to copy", time.Second*10)
+		return
+	}
+	components.CopyText(w.load, text, fmt.Sprintf("%d lines of %s", strings.Count(text, "\n"), source))
+}
+
+// saveVisibleLog writes the lines the log view shows to a file named after
+// their source and the time, to attach to a bug report.
+func (w *Wallet) saveVisibleLog() {
+	text, source := w.visibleLogText()
+	if text == "" {
+		w.load.Notif.ShowToastWithTimeout("[red:-:-]Error:[-:-:-] no log lines to save", time.Second*10)
+		return
+	}
+	name := fmt.Sprintf("%s-%s.log", strings.TrimSuffix(source, ".log"), time.Now().Format(logExportStamp))
+	w.showFilePicker("Save the log", components.FileSave, "~/"+name, []string{".log", ".txt"}, func(path string) error {
+		if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+			return err
+		}
+		w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[green:-:-]Saved %d log lines to %s", strings.Count(text, "\n"), path), time.Second*10)
+		return nil
+	})
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/shared"
+)
+
+// logTimeLayout is the timestamp starting the log lines, which the daemon
+// follows with milliseconds.
+const logTimeLayout = "2006-01-02 15:04:05"
+
+// logJumpLayouts are the times accepted by the jump prompt, those without a
+// date being on the day of the latest line.
+var logJumpLayouts = []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "15:04:05", "15:04"}
+
+// showNewLogLines shows the n lines the tail added, or counts them in the
+// title while the view is paused.
+func (w *Wallet) showNewLogLines(n int) {
+	w.logMu.Lock()
+	if !w.logPaused {
+		w.logMu.Unlock()
+		w.updateLogView()
+		return
+	}
+	w.logPending += n
+	title := w.logTitle()
+	w.logMu.Unlock()
+
+	w.showLogTitle(title)
+}
+
+func (w *Wallet) showLogTitle(title string) {
+	w.load.Application.QueueUpdateDraw(func() {
+		if w.logView != nil {
+			w.logView.SetTitle(title)
+		}
+	})
+}
+
+// toggleLogFollow pauses the log view where it is, or shows the lines that
+// came since and follows the end of the log again.
+func (w *Wallet) toggleLogFollow() {
+	w.logMu.Lock()
+	w.logPaused = !w.logPaused
+	paused := w.logPaused
+	w.logMu.Unlock()
+
+	if paused {
+		w.load.Notif.ShowToastWithTimeout("Log paused, press f to follow it again", time.Second*5)
+	} else {
+		w.load.Notif.ShowToastWithTimeout("Following the log", time.Second*5)
+	}
+	go w.updateLogView()
+}
+
+// lineLogTime returns the time a log line starts with.
+func lineLogTime(line string) (time.Time, bool) {
+	if len(line) < len(logTimeLayout) {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(logTimeLayout, line[:len(logTimeLayout)])
+	return t, err == nil
+}
+
+// parseLogJump reads the time typed in the jump prompt, one without a date
+// being on day.
+func parseLogJump(text string, day time.Time) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	for _, layout := range logJumpLayouts {
+		t, err := time.Parse(layout, text)
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(layout, "2006") {
+			t = time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a time such as 15:04, 15:04:05 or 2006-01-02 15:04", text)
+}
+
+// logRowAt returns the first of lines written at target or after.
+func logRowAt(lines []string, target time.Time) (int, error) {
+	for i, line := range lines {
+		if t, ok := lineLogTime(line); ok && !t.Before(target) {
+			return i, nil
+		}
+	}
+	return 0, errors.New("no log line at or after " + target.Format("2006-01-02 15:04:05"))
+}
+
+// jumpToLogTime scrolls the log view to the first line shown from text on,
+// pausing it so the lines that come do not move it away.
+func (w *Wallet) jumpToLogTime(text string) error {
+	w.logMu.Lock()
+	lines := w.shownLogLines()
+	var day time.Time
+	for i := len(lines) - 1; i >= 0; i-- {
+		if t, ok := lineLogTime(lines[i]); ok {
+			day = t
+			break
+		}
+	}
+	if day.IsZero() {
+		w.logMu.Unlock()
+		return errors.New("no timestamped line in the log")
+	}
+	target, err := parseLogJump(text, day)
+	if err != nil {
+		w.logMu.Unlock()
+		return err
+	}
+	row, err := logRowAt(lines, target)
+	if err != nil {
+		w.logMu.Unlock()
+		return err
+	}
+	w.logPaused = true
+	w.logMu.Unlock()
+
+	go w.showLogLines(row)
+	return nil
+}
+
+// promptLogJump asks for the time to scroll the log view to.
+func (w *Wallet) promptLogJump() {
+	form := tview.NewForm()
+	form.SetBackgroundColor(tcell.ColorDefault).
+		SetBorderPadding(1, 1, 2, 2)
+	form.AddInputField("Time:", "", 0, nil, nil)
+	timeField := form.GetFormItem(0).(*tview.InputField)
+	timeField.SetPlaceholder("15:04, 15:04:05 or 2006-01-02 15:04")
+	timeField.SetPlaceholderTextColor(shared.CurrentTheme().Text)
+
+	jump := func() {
+		if err := w.jumpToLogTime(timeField.GetText()); err != nil {
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err), time.Second*10)
+			w.load.Application.SetFocus(timeField)
+			return
+		}
+		w.closeModal()
+	}
+	timeField.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			jump()
+		case tcell.KeyEscape:
+			w.closeModal()
+		}
+	})
+	form.AddButton("Cancel", w.closeModal)
+	form.AddButton("Jump", jump)
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow)
+	view.SetTitle("Jump to time").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	view.AddItem(form, 0, 1, true)
+
+	w.nav.ShowModal(components.NewModal(view, 60, 9, w.closeModal))
+	w.load.Application.SetFocus(timeField)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/shared"
+)
+
+const allSubsystems = "all"
+
+var logLevels = []string{"trace", "debug", "info", "warn", "error", "critical"}
+
+// showLogLevelPicker changes the log level of the daemon, for every subsystem
+// or a single one, until the wallet restarts.
+func (w *Wallet) showLogLevelPicker() {
+	w.load.Notif.CancelToast()
+
+	form := tview.NewForm()
+	form.SetBackgroundColor(tcell.ColorDefault).
+		SetBorderPadding(1, 1, 2, 2)
+
+	level := "info"
+	form.AddDropDown("Level", logLevels, 2, func(option string, _ int) {
+		level = option
+	})
+
+	subsystem := allSubsystems
+	subsystems := components.NewFuzzyDropDown("Subsystem").
+		SetOptions([]string{allSubsystems}, func(option string, _ int) {
+			subsystem = option
+		}).
+		SetCurrentOption(0)
+	form.AddFormItem(subsystems)
+	form.AddTextView("", "[gray::]Applies until the wallet restarts.", 0, 1, true, false)
+
+	form.AddButton("Cancel", w.closeModal)
+	form.AddButton("Apply", func() {
+		spec := level
+		if subsystem != allSubsystems {
+			spec = fmt.Sprintf("%s=%s", subsystem, level)
+		}
+
+		go func() {
+			_, err := w.load.Wallet.SetDebugLevel(context.Background(), spec)
+			w.load.Application.QueueUpdateDraw(func() {
+				if err != nil {
+					w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err), time.Second*15)
+					return
+				}
+				w.closeModal()
+				w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("Log level set to %s", spec), time.Second*5)
+			})
+		}()
+	})
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetTitle("Log level").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	container.AddItem(form, 0, 1, true)
+
+	w.nav.ShowModal(components.NewModal(container, 44, 13, w.closeModal))
+	w.load.Application.SetFocus(form)
+
+	ctx := w.nav.ModalContext()
+	go func() {
+		names, err := w.load.Wallet.DebugSubsystems(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		w.load.Application.QueueUpdateDraw(func() {
+			if err != nil {
+				w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[yellow:-:-]Warning:[-:-:-] subsystems unavailable: %s", err), time.Second*10)
+				return
+			}
+			subsystems.SetOptions(append([]string{allSubsystems}, names...), func(option string, _ int) {
+				subsystem = option
+			})
+			subsystems.SetCurrentOption(0)
+		})
+	}()
+}
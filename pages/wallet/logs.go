@@ -7,13 +7,13 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 )
 
 const (
 	logPollInterval    = 750 * time.Millisecond
 	maxInitialLogBytes = int64(2 * 1024 * 1024)
+	cacheStatsInterval = 5 * time.Second
 )
 
 func (w *Wallet) startLogTail() {
@@ -26,16 +26,66 @@ func (w *Wallet) startLogTail() {
 		networkName = w.load.AppConfig.Network.Name
 	}
 	w.logPath = filepath.Join(w.load.AppConfig.Walletdir, "logs", "flokicoin", networkName, "flnd.log")
+	w.logMu.Lock()
+	w.logSource = logSource{label: filepath.Base(w.logPath), path: w.logPath}
+	w.logMu.Unlock()
 	w.setLogStatus(fmt.Sprintf("Loading log from %s", w.logPath))
 
 	go w.tailLog()
+	go w.trackCacheStats(w.logQuit)
+}
+
+// trackCacheStats shows the size of the transaction cache in the logs title.
+func (w *Wallet) trackCacheStats(quit <-chan struct{}) {
+	ticker := time.NewTicker(cacheStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		info := ""
+		if stats, err := w.load.Wallet.TxCacheStats(); err == nil {
+			capped := ""
+			if stats.Truncated {
+				capped = ", capped"
+			}
+			info = fmt.Sprintf("tx cache %d txs ≈ %s%s", stats.Transactions, formatByteSize(stats.Bytes), capped)
+		}
+		w.logMu.Lock()
+		changed := info != w.logCacheInfo
+		w.logCacheInfo = info
+		title := w.logTitle()
+		w.logMu.Unlock()
+		if changed {
+			w.showLogTitle(title)
+		}
+
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func formatByteSize(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
 }
 
 func (w *Wallet) tailLog() {
 	ticker := time.NewTicker(logPollInterval)
 	defer ticker.Stop()
 
-	var offset int64
+	var (
+		path   string
+		offset int64
+		loaded bool
+	)
 
 	for {
 		select {
@@ -44,10 +94,25 @@ func (w *Wallet) tailLog() {
 		case <-ticker.C:
 		}
 
-		info, err := os.Stat(w.logPath)
+		w.logMu.Lock()
+		source := w.logSource
+		w.logMu.Unlock()
+		if source.path != path {
+			path, offset, loaded = source.path, 0, false
+		}
+		// A compressed log was rotated and no longer grows.
+		if source.compressed() {
+			if !loaded {
+				w.loadCompressedLog(path)
+				loaded = true
+			}
+			continue
+		}
+
+		info, err := os.Stat(path)
 		if err != nil {
 			if os.IsNotExist(err) {
-				w.setLogStatus(fmt.Sprintf("Waiting for log file at %s", w.logPath))
+				w.setLogStatus(fmt.Sprintf("Waiting for log file at %s", path))
 			} else {
 				w.setLogStatus(fmt.Sprintf("Log unavailable: %v", err))
 			}
@@ -59,7 +124,7 @@ func (w *Wallet) tailLog() {
 			offset = 0
 		}
 
-		f, err := os.Open(w.logPath)
+		f, err := os.Open(path)
 		if err != nil {
 			w.setLogStatus(fmt.Sprintf("Unable to open log file: %v", err))
 			continue
@@ -135,10 +200,9 @@ func (w *Wallet) replaceLogLines(lines []string) {
 	w.logMu.Lock()
 	w.logLines = append([]string{}, lines...)
 	w.logStatus = ""
-	text := strings.Join(w.logLines, "\n")
 	w.logMu.Unlock()
 
-	w.updateLogView(text)
+	w.showNewLogLines(len(lines))
 }
 
 func (w *Wallet) appendLogLines(lines []string) {
@@ -163,10 +227,9 @@ func (w *Wallet) appendLogLines(lines []string) {
 	} else {
 		w.logLines = append(w.logLines, lines...)
 	}
-	text := strings.Join(w.logLines, "\n")
 	w.logMu.Unlock()
 
-	w.updateLogView(text)
+	w.showNewLogLines(len(lines))
 }
 
 func (w *Wallet) setLogStatus(message string) {
@@ -178,19 +241,55 @@ func (w *Wallet) setLogStatus(message string) {
 	w.logStatus = message
 	w.logReady = false
 	w.logLines = []string{message}
-	text := message
 	w.logMu.Unlock()
 
-	w.updateLogView(text)
+	w.updateLogView()
+}
+
+// updateLogView shows the lines kept through the level filter, following
+// the current match while searching, and the end of the log unless paused.
+func (w *Wallet) updateLogView() {
+	w.showLogLines(-1)
 }
 
-func (w *Wallet) updateLogView(text string) {
+// showLogLines renders the log view, scrolled to row when it is not
+// negative.
+func (w *Wallet) showLogLines(row int) {
 	if w.load == nil || w.load.Application == nil {
 		return
 	}
+
+	w.logMu.Lock()
+	text, matches := w.renderLogText()
+	w.logMatches = matches
+	if w.logMatch >= matches {
+		w.logMatch = max(matches-1, 0)
+	}
+	region := ""
+	if matches > 0 {
+		region = logMatchRegion(w.logMatch)
+	}
+	w.logPending = 0
+	paused := w.logPaused
+	title := w.logTitle()
+	w.logMu.Unlock()
+
 	w.load.Application.QueueUpdateDraw(func() {
-		if w.logView != nil {
-			w.logView.SetText(text)
+		if w.logView == nil {
+			return
+		}
+		offset, column := w.logView.GetScrollOffset()
+		w.logView.SetText(text)
+		w.logView.SetTitle(title)
+		switch {
+		case row >= 0:
+			w.logView.Highlight(region).ScrollTo(row, 0)
+		case region != "":
+			w.logView.Highlight(region).ScrollToHighlight()
+		case paused:
+			w.logView.Highlight().ScrollTo(offset, column)
+		default:
+			w.logView.Highlight().ScrollToEnd()
 		}
 	})
 }
@@ -0,0 +1,274 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/shared"
+)
+
+// logFilter hides the daemon log lines below a level.
+type logFilter int
+
+const (
+	logFilterAll logFilter = iota
+	logFilterWarnings
+	logFilterErrors
+)
+
+// logLevelRanks orders the level tags of the daemon log and of twallet.log.
+var logLevelRanks = map[string]int{
+	"TRC": 0, "DBG": 1, "INF": 2, "WRN": 3, "ERR": 4, "CRT": 5, "FTL": 5, "PNC": 6,
+}
+
+func (f logFilter) String() string {
+	switch f {
+	case logFilterWarnings:
+		return "WRN+"
+	case logFilterErrors:
+		return "ERR+"
+	default:
+		return "all"
+	}
+}
+
+// minLevel is the rank of the lowest level shown.
+func (f logFilter) minLevel() int {
+	switch f {
+	case logFilterWarnings:
+		return logLevelRanks["WRN"]
+	case logFilterErrors:
+		return logLevelRanks["ERR"]
+	default:
+		return 0
+	}
+}
+
+func (f logFilter) next() logFilter {
+	return (f + 1) % (logFilterErrors + 1)
+}
+
+// lineLogLevel returns the rank of the level of a log line, written as
+// "2024-01-02 15:04:05.000 [WRN] LNWL: ..." by the daemon and
+// "2024-01-02 15:04:05 WRN ..." by twallet. The lines continuing a message
+// over several lines have none.
+func lineLogLevel(line string) (int, bool) {
+	head := line
+	if len(head) > 40 {
+		head = head[:40]
+	}
+	fields := strings.Fields(head)
+	if len(fields) < 3 {
+		return 0, false
+	}
+	rank, ok := logLevelRanks[strings.TrimSuffix(strings.TrimPrefix(fields[2], "["), "]")]
+	return rank, ok
+}
+
+func logMatchRegion(i int) string {
+	return "match" + strconv.Itoa(i)
+}
+
+// renderLogText builds the text of the log view from the lines kept,
+// leaving out those below the level filter and marking each match of the
+// search as a region n and N move through. It returns the number of
+// matches. logMu is held.
+func (w *Wallet) renderLogText() (string, int) {
+	if w.logStatus != "" {
+		return tview.Escape(w.logStatus), 0
+	}
+
+	query := strings.ToLower(w.logQuery)
+	matches := 0
+
+	var b strings.Builder
+	for i, line := range w.shownLogLines() {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		if query == "" {
+			b.WriteString(tview.Escape(line))
+			continue
+		}
+		matches = writeLogMatches(&b, line, query, matches)
+	}
+	return b.String(), matches
+}
+
+// shownLogLines returns the lines kept that pass the level filter. A line
+// without a level goes with the last one that had one, the lines before any
+// are only shown without a filter. logMu is held.
+func (w *Wallet) shownLogLines() []string {
+	minLevel := w.logFilter.minLevel()
+	if minLevel == 0 {
+		return w.logLines
+	}
+	var lines []string
+	shown := false
+	for _, line := range w.logLines {
+		if rank, ok := lineLogLevel(line); ok {
+			shown = rank >= minLevel
+		}
+		if shown {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// writeLogMatches writes line with the matches of the lowercase query in
+// regions numbered from n, returning the number after the last.
+func writeLogMatches(b *strings.Builder, line, query string, n int) int {
+	lower := strings.ToLower(line)
+	if len(lower) != len(line) {
+		// The offsets would not line up, match the case as written.
+		lower = line
+	}
+	for {
+		i := strings.Index(lower, query)
+		if i < 0 {
+			break
+		}
+		end := i + len(query)
+		fmt.Fprintf(b, `%s["%s"][yellow::b]%s[-::-][""]`, tview.Escape(line[:i]), logMatchRegion(n), tview.Escape(line[i:end]))
+		line, lower = line[end:], lower[end:]
+		n++
+	}
+	b.WriteString(tview.Escape(line))
+	return n
+}
+
+// logTitle returns the title of the log view, with the search and filter in
+// use and the keys of the pane. logMu is held.
+func (w *Wallet) logTitle() string {
+	var b strings.Builder
+	b.WriteString(" Logs")
+	if w.logSource.label != "" && w.logSource.path != w.logPath {
+		fmt.Fprintf(&b, " · %s", tview.Escape(w.logSource.label))
+	}
+	if w.logQuery != "" {
+		current := 0
+		if w.logMatches > 0 {
+			current = w.logMatch + 1
+		}
+		fmt.Fprintf(&b, " · %s %d/%d", tview.Escape(strconv.Quote(w.logQuery)), current, w.logMatches)
+	}
+	if w.logFilter != logFilterAll {
+		fmt.Fprintf(&b, " · %s", w.logFilter)
+	}
+	if w.logPaused {
+		b.WriteString(" · paused")
+		if w.logPending > 0 {
+			fmt.Fprintf(&b, ", %d new", w.logPending)
+		}
+		b.WriteString(" · f follow")
+	} else {
+		b.WriteString(" · f pause")
+	}
+	b.WriteString(" · o source · / search · w filter · t time · y copy · x save · v level · p rpc · g config")
+	if w.logCacheInfo != "" {
+		b.WriteString(" · ")
+		b.WriteString(w.logCacheInfo)
+	}
+	b.WriteString(" ")
+	return b.String()
+}
+
+// setLogQuery searches the log for query, starting from its latest match.
+// An empty query ends the search.
+func (w *Wallet) setLogQuery(query string) {
+	w.logMu.Lock()
+	w.logQuery = query
+	_, w.logMatches = w.renderLogText()
+	w.logMatch = max(w.logMatches-1, 0)
+	matches := w.logMatches
+	w.logMu.Unlock()
+
+	if query != "" && matches == 0 {
+		w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("No match for %q in the log", query), time.Second*5)
+	}
+	go w.updateLogView()
+}
+
+// moveLogMatch highlights the match by steps after the current one, wrapping
+// around the ends of the log.
+func (w *Wallet) moveLogMatch(by int) {
+	w.logMu.Lock()
+	if w.logQuery == "" || w.logMatches == 0 {
+		w.logMu.Unlock()
+		return
+	}
+	w.logMatch = ((w.logMatch+by)%w.logMatches + w.logMatches) % w.logMatches
+	w.logMu.Unlock()
+
+	go w.updateLogView()
+}
+
+// cycleLogFilter shows every level, then only warnings and errors, then only
+// errors.
+func (w *Wallet) cycleLogFilter() {
+	w.logMu.Lock()
+	w.logFilter = w.logFilter.next()
+	filter := w.logFilter
+	w.logMu.Unlock()
+
+	message := "Showing every log line"
+	switch filter {
+	case logFilterWarnings:
+		message = "Showing the WRN and ERR log lines"
+	case logFilterErrors:
+		message = "Showing the ERR log lines"
+	}
+	w.load.Notif.ShowToastWithTimeout(message, time.Second*5)
+	go w.updateLogView()
+}
+
+// promptLogSearch asks what to search the log for.
+func (w *Wallet) promptLogSearch() {
+	w.logMu.Lock()
+	current := w.logQuery
+	w.logMu.Unlock()
+
+	form := tview.NewForm()
+	form.SetBackgroundColor(tcell.ColorDefault).
+		SetBorderPadding(1, 1, 2, 2)
+	form.AddInputField("Search:", current, 0, nil, nil)
+	queryField := form.GetFormItem(0).(*tview.InputField)
+	queryField.SetPlaceholder("text to find, empty to clear")
+	queryField.SetPlaceholderTextColor(shared.CurrentTheme().Text)
+
+	search := func() {
+		w.closeModal()
+		w.setLogQuery(queryField.GetText())
+	}
+	queryField.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			search()
+		case tcell.KeyEscape:
+			w.closeModal()
+		}
+	})
+	form.AddButton("Cancel", w.closeModal)
+	form.AddButton("Search", search)
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow)
+	view.SetTitle("Search the log").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	view.AddItem(form, 0, 1, true)
+
+	w.nav.ShowModal(components.NewModal(view, 60, 9, w.closeModal))
+	w.load.Application.SetFocus(queryField)
+}
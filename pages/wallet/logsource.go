@@ -0,0 +1,184 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/flokiorg/twallet/components"
+)
+
+// logSource is a log file the log view can show.
+type logSource struct {
+	label string
+	path  string
+}
+
+// compressed reports whether the file is a rotated log the daemon
+// compressed, read once rather than tailed.
+func (s logSource) compressed() bool {
+	switch filepath.Ext(s.path) {
+	case ".gz", ".zst":
+		return true
+	}
+	return false
+}
+
+// logSources lists flnd.log and its rotated files, then twallet.log, its
+// rotated files and crash.log, the rotated files newest first.
+func (w *Wallet) logSources() []logSource {
+	var sources []logSource
+	add := func(dir, name string, rotated bool) {
+		sources = append(sources, logSource{label: name, path: filepath.Join(dir, name)})
+		if !rotated {
+			return
+		}
+		for _, old := range rotatedLogs(dir, name) {
+			sources = append(sources, logSource{label: old, path: filepath.Join(dir, old)})
+		}
+	}
+	add(filepath.Dir(w.logPath), filepath.Base(w.logPath), true)
+	if dir := w.load.AppConfig.LogDir; dir != "" {
+		add(dir, "twallet.log", true)
+		if _, err := os.Stat(filepath.Join(dir, "crash.log")); err == nil {
+			add(dir, "crash.log", false)
+		}
+	}
+	return sources
+}
+
+// rotatedLogs returns the rotated files of the log name in dir, newest
+// first: the daemon numbers them from 1 and twallet stamps them with the
+// time they were rotated.
+func rotatedLogs(dir, name string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	prefix := name + "."
+	var names []string
+	for _, entry := range entries {
+		if n := entry.Name(); strings.HasPrefix(n, prefix) && !entry.IsDir() {
+			names = append(names, n)
+		}
+	}
+	rotation := func(n string) string {
+		r, _, _ := strings.Cut(strings.TrimPrefix(n, prefix), ".")
+		return r
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		na, errA := strconv.Atoi(rotation(a))
+		nb, errB := strconv.Atoi(rotation(b))
+		if errA == nil && errB == nil {
+			return cmp.Compare(na, nb)
+		}
+		return strings.Compare(b, a)
+	})
+	return names
+}
+
+// showLogSources picks the log file the view shows.
+func (w *Wallet) showLogSources() {
+	w.logMu.Lock()
+	current := w.logSource
+	w.logMu.Unlock()
+
+	var items []components.MenuItem
+	for _, source := range w.logSources() {
+		label := source.label
+		if source.path == current.path {
+			label += " (shown)"
+		}
+		items = append(items, components.MenuItem{Label: label, Action: func() {
+			w.closeModal()
+			w.setLogSource(source)
+		}})
+	}
+	w.nav.ShowModal(components.NewMenu("Log source", items, w.closeModal))
+}
+
+// setLogSource shows source from its start, following it if it still grows.
+// The search and level filter are kept.
+func (w *Wallet) setLogSource(source logSource) {
+	w.logMu.Lock()
+	if w.logSource.path == source.path {
+		w.logMu.Unlock()
+		return
+	}
+	w.logSource = source
+	w.logPaused = false
+	w.logMu.Unlock()
+
+	go w.setLogStatus(fmt.Sprintf("Loading log from %s", source.path))
+}
+
+// loadCompressedLog shows the end of the rotated log at path.
+func (w *Wallet) loadCompressedLog(path string) {
+	data, err := readCompressedLog(path)
+	if err != nil {
+		w.setLogStatus(fmt.Sprintf("Unable to read log file: %v", err))
+		return
+	}
+	lines := w.readLogLines(data)
+	if len(lines) == 0 {
+		w.setLogStatus("Log file is empty.")
+		return
+	}
+	w.replaceLogLines(lines)
+	w.logReady = true
+}
+
+// readCompressedLog decompresses the log at path, keeping its last
+// maxInitialLogBytes from the start of a line.
+func readCompressedLog(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader
+	switch filepath.Ext(path) {
+	case ".gz":
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	case ".zst":
+		zr, err := zstd.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		r = f
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxInitialLogBytes {
+		data = data[int64(len(data))-maxInitialLogBytes:]
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			data = data[i+1:]
+		}
+	}
+	return data, nil
+}
@@ -170,12 +170,16 @@ func (w *Wallet) showMessageTools() {
 		if signOutputView != nil {
 			signOutputView.SetText("Signing...", false)
 		}
-		w.load.Notif.ShowToast("✍️ signing message...")
+		signing := w.load.Notif.ShowToast("✍️ signing message...")
 
+		ctx := w.nav.ModalContext()
 		go func(msg, addr string) {
-			signature, err := w.load.Wallet.SignMessage(addr, msg)
+			signature, err := w.load.Wallet.SignMessage(ctx, addr, msg)
+			if ctx.Err() != nil {
+				return
+			}
 			w.load.Application.QueueUpdateDraw(func() {
-				w.load.Notif.CancelToast()
+				signing.Dismiss()
 				disableSignInputs(false)
 				if signButton != nil {
 					signButton.SetLabel("Sign")
@@ -221,14 +225,12 @@ func (w *Wallet) showMessageTools() {
 		if currentSignature == "" {
 			return
 		}
-		if err := shared.ClipboardCopy(currentSignature); err != nil {
-			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*10)
+		if !components.CopyText(w.load, currentSignature, "signature") {
 			return
 		}
 		if verifySignatureField != nil {
 			verifySignatureField.SetText(currentSignature, false)
 		}
-		w.load.Notif.ShowToastWithTimeout("📋 Signature copied", time.Second*10)
 	}
 
 	verifyHandler = func() {
@@ -288,12 +290,16 @@ func (w *Wallet) showMessageTools() {
 		if copyPubKeyButton != nil {
 			copyPubKeyButton.SetDisabled(true)
 		}
-		w.load.Notif.ShowToast("🔍 verifying signature...")
+		verifying := w.load.Notif.ShowToast("🔍 verifying signature...")
 
+		ctx := w.nav.ModalContext()
 		go func(msg, addr, sig string) {
-			resp, err := w.load.Wallet.VerifyMessage(addr, msg, sig)
+			resp, err := w.load.Wallet.VerifyMessage(ctx, addr, msg, sig)
+			if ctx.Err() != nil {
+				return
+			}
 			w.load.Application.QueueUpdateDraw(func() {
-				w.load.Notif.CancelToast()
+				verifying.Dismiss()
 				disableVerifyInputs(false)
 				if verifyButton != nil {
 					verifyButton.SetLabel("Verify")
@@ -353,12 +359,13 @@ func (w *Wallet) showMessageTools() {
 	verifyBtn := tview.NewButton("Verify")
 
 	styleToggle := func(btn *tview.Button, active bool) {
+		theme := shared.CurrentTheme()
 		if active {
-			btn.SetBackgroundColor(tcell.ColorWhite)
-			btn.SetLabelColor(tcell.ColorBlack)
+			btn.SetBackgroundColor(theme.Text)
+			btn.SetLabelColor(theme.Background)
 		} else {
-			btn.SetBackgroundColor(tcell.ColorOrange)
-			btn.SetLabelColor(tcell.ColorWhite)
+			btn.SetBackgroundColor(theme.Modal)
+			btn.SetLabelColor(theme.Text)
 		}
 	}
 
@@ -421,8 +428,8 @@ func (w *Wallet) showMessageTools() {
 
 	container := tview.NewFlex().SetDirection(tview.FlexRow)
 	container.SetTitle("Sign & Verify").
-		SetTitleColor(tcell.ColorGray).
-		SetBackgroundColor(tcell.ColorOrange).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
 		SetBorder(true)
 
 	container.AddItem(tview.NewBox().SetBackgroundColor(tcell.ColorDefault), 2, 0, false).AddItem(toggleRow, 1, 0, false).
@@ -0,0 +1,94 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/load"
+	"github.com/flokiorg/twallet/shared"
+)
+
+func (w *Wallet) showNotificationHistory() {
+	if w.load == nil || w.load.Notif == nil {
+		return
+	}
+
+	w.load.Notif.CancelToast()
+
+	columns := []components.Column{
+		{Name: "Time", Align: tview.AlignLeft},
+		{Name: "Severity", Align: tview.AlignLeft},
+		{Name: "Message", Align: tview.AlignLeft},
+	}
+
+	table := components.NewTable("Notifications", columns, shared.NetworkColor(*w.load.AppConfig.Network), 0)
+	table.SetBorder(false)
+	table.SetBorderPadding(0, 0, 1, 1)
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s:-:-]<esc>[gray:-:-] close  [%s:-:-]<x>[gray:-:-] clear", shared.CurrentTheme().Accent, shared.CurrentTheme().Accent))
+
+	render := func() {
+		records := w.load.Notif.History()
+		if len(records) == 0 {
+			table.ShowPlaceholder("No notifications yet.")
+			return
+		}
+		rows := make([][]string, 0, len(records))
+		for _, rec := range records {
+			rows = append(rows, []string{
+				fmt.Sprintf("[gray::]%s", shared.FormatTime(rec.Time)),
+				severityCell(rec.Severity),
+				rec.Message,
+			})
+		}
+		table.Update(rows)
+		table.Select(1, 0)
+		table.ScrollToBeginning()
+	}
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetTitle("Notifications").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	container.AddItem(table, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	container.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && unicode.ToLower(event.Rune()) == 'x' {
+			w.load.Notif.ClearHistory()
+			render()
+			return nil
+		}
+		return event
+	})
+
+	w.nav.ShowModal(components.NewModal(container, 110, 26, w.closeModal))
+	render()
+	w.load.Application.SetFocus(table)
+}
+
+func severityCell(severity load.NotificationSeverity) string {
+	theme := shared.CurrentTheme()
+	switch severity {
+	case load.SeverityError:
+		return fmt.Sprintf("[%s:-:-]%s", theme.Error, severity)
+	case load.SeverityWarning:
+		return fmt.Sprintf("[%s:-:-]%s", theme.Warning, severity)
+	case load.SeveritySuccess:
+		return fmt.Sprintf("[%s:-:-]%s", theme.Success, severity)
+	default:
+		return fmt.Sprintf("[%s:-:-]%s", theme.Muted, severity)
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/go-flokicoin/chainutil/psbt"
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/shared"
+)
+
+func (w *Wallet) showPsbtSignView() {
+	if w.load == nil || w.load.Wallet == nil {
+		return
+	}
+
+	w.load.Notif.CancelToast()
+
+	form := tview.NewForm()
+	form.SetBackgroundColor(tcell.ColorDefault).
+		SetBorderPadding(1, 1, 2, 2)
+
+	form.AddTextArea("PSBT (base64/hex):", "", 0, 6, 0, nil).
+		AddTextView("Signed inputs:", "[gray::]-", 0, 1, true, false).
+		AddTextArea("Signed PSBT:", "Not signed yet", 0, 6, 0, nil)
+
+	inputField, _ := form.GetFormItem(0).(*tview.TextArea)
+	statusView, _ := form.GetFormItem(1).(*tview.TextView)
+	outputView, _ := form.GetFormItem(2).(*tview.TextArea)
+
+	var (
+		signHandler func()
+		copyHandler func()
+		signedB64   string
+	)
+
+	form.AddButton("Cancel", w.closeModal)
+	form.AddButton("Sign", func() {
+		if signHandler != nil {
+			signHandler()
+		}
+	})
+	form.AddButton("Copy PSBT", func() {
+		if copyHandler != nil {
+			copyHandler()
+		}
+	})
+	form.AddButton("Load", func() {
+		w.showFilePicker("Load PSBT", components.FileOpen, "~", nil, func(path string) error {
+			raw, err := readPsbtFile(path)
+			if err != nil {
+				return err
+			}
+			if inputField != nil {
+				inputField.SetText(raw, false)
+				w.load.Application.SetFocus(inputField)
+			}
+			return nil
+		})
+	})
+	form.AddButton("Save", func() {
+		if signedB64 == "" {
+			return
+		}
+		w.showFilePicker("Save signed PSBT", components.FileSave, "~/signed.psbt", []string{".psbt"}, func(path string) error {
+			if err := writePsbtFile(path, signedB64); err != nil {
+				return err
+			}
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("💾 Signed PSBT saved to %s", path), time.Second*15)
+			return nil
+		})
+	})
+
+	var signButton, copyButton, saveButton *tview.Button
+	if idx := form.GetButtonIndex("Sign"); idx >= 0 {
+		signButton = form.GetButton(idx)
+	}
+	if idx := form.GetButtonIndex("Copy PSBT"); idx >= 0 {
+		copyButton = form.GetButton(idx)
+		copyButton.SetDisabled(true)
+	}
+	if idx := form.GetButtonIndex("Save"); idx >= 0 {
+		saveButton = form.GetButton(idx)
+		saveButton.SetDisabled(true)
+	}
+
+	setBusy := func(busy bool) {
+		if inputField != nil {
+			inputField.SetDisabled(busy)
+		}
+		if signButton != nil {
+			signButton.SetDisabled(busy)
+			if busy {
+				signButton.SetLabel("Signing...")
+			} else {
+				signButton.SetLabel("Sign")
+			}
+		}
+	}
+
+	signHandler = func() {
+		raw := ""
+		if inputField != nil {
+			raw = inputField.GetText()
+		}
+
+		packet, err := decodePsbt(raw)
+		if err != nil {
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*10)
+			if inputField != nil {
+				w.load.Application.SetFocus(inputField)
+			}
+			return
+		}
+
+		setBusy(true)
+		signedB64 = ""
+		if copyButton != nil {
+			copyButton.SetDisabled(true)
+		}
+		if saveButton != nil {
+			saveButton.SetDisabled(true)
+		}
+		if outputView != nil {
+			outputView.SetText("Signing...", false)
+		}
+		signing := w.load.Notif.ShowToast("✍️ signing psbt...")
+
+		ctx := w.nav.ModalContext()
+		go func() {
+			signed, err := w.load.Wallet.SignPsbt(ctx, packet)
+			if ctx.Err() != nil {
+				return
+			}
+			var encoded string
+			if err == nil {
+				encoded, err = signed.Packet.B64Encode()
+			}
+			w.load.Application.QueueUpdateDraw(func() {
+				signing.Dismiss()
+				setBusy(false)
+
+				if err != nil {
+					if outputView != nil {
+						outputView.SetText("Signing failed", false)
+					}
+					if statusView != nil {
+						statusView.SetText("[gray::]-")
+					}
+					w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
+					return
+				}
+
+				signedB64 = encoded
+				if outputView != nil {
+					outputView.SetText(encoded, false)
+				}
+				if copyButton != nil {
+					copyButton.SetDisabled(false)
+				}
+				if saveButton != nil {
+					saveButton.SetDisabled(false)
+				}
+
+				if len(signed.SignedInputs) == 0 {
+					if statusView != nil {
+						statusView.SetText("[yellow::]none (no wallet-owned inputs)")
+					}
+					w.load.Notif.ShowToastWithTimeout("[yellow:-:-]No inputs owned by this wallet", time.Second*20)
+					return
+				}
+
+				if statusView != nil {
+					statusView.SetText(fmt.Sprintf("[green::]%s", formatSignedInputs(signed.SignedInputs)))
+				}
+				w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[green:-:-]Signed %d input(s)", len(signed.SignedInputs)), time.Second*20)
+			})
+		}()
+	}
+
+	copyHandler = func() {
+		if signedB64 == "" {
+			return
+		}
+		components.CopyText(w.load, signedB64, "signed PSBT")
+	}
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetTitle("Sign PSBT").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	container.AddItem(form, 0, 1, true)
+
+	w.nav.ShowModal(components.NewModal(container, 74, 22, w.closeModal))
+	if inputField != nil {
+		w.load.Application.SetFocus(inputField)
+	}
+}
+
+// psbtMagic starts the binary form of a PSBT.
+var psbtMagic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+// decodePsbt accepts a PSBT either base64 or hex encoded, the two forms other
+// wallets and coordinators commonly export.
+func decodePsbt(raw string) (*psbt.Packet, error) {
+	raw = strings.Join(strings.Fields(raw), "")
+	if raw == "" {
+		return nil, fmt.Errorf("psbt cannot be empty")
+	}
+
+	if decoded, err := hex.DecodeString(raw); err == nil {
+		return psbt.NewFromRawBytes(bytes.NewReader(decoded), false)
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(raw); err != nil {
+		return nil, fmt.Errorf("psbt must be base64 or hex encoded")
+	}
+
+	return psbt.NewFromRawBytes(strings.NewReader(raw), true)
+}
+
+// readPsbtFile returns the PSBT of a file as text for the input field,
+// base64 encoding it if the file holds the binary form.
+func readPsbtFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if bytes.HasPrefix(data, psbtMagic) {
+		return base64.StdEncoding.EncodeToString(data), nil
+	}
+	if _, err := decodePsbt(string(data)); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// writePsbtFile writes a base64 PSBT to path in the binary form other
+// wallets import.
+func writePsbtFile(path, b64 string) error {
+	data, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func formatSignedInputs(indexes []uint32) string {
+	parts := make([]string, 0, len(indexes))
+	for _, idx := range indexes {
+		parts = append(parts, fmt.Sprintf("#%d", idx))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,8 +20,6 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-var errInvalidPassphrase = errors.New("invalid wallet passphrase")
-
 type rescanUI struct {
 	instructions  string
 	pages         *tview.Pages
@@ -78,7 +77,7 @@ func (w *Wallet) promptRescan() {
 	}
 	w.mu.Unlock()
 
-	if rs, err := w.load.GetRecoveryStatus(); err == nil && rs != nil && rs.Info != nil {
+	if rs, err := w.load.GetRecoveryStatus(context.Background()); err == nil && rs != nil && rs.Info != nil {
 		if rs.Info.GetRecoveryMode() && !rs.Info.GetRecoveryFinished() && rs.Info.GetProgress() < 1 {
 			w.nav.ShowModal(components.NewDialog(
 				"Rescan Already Running",
@@ -95,6 +94,11 @@ func (w *Wallet) promptRescan() {
 	progressView, logProgress, recordStatus, getLastStatus := w.newRescanProgressView(netColor)
 
 	instructions := "Resetting wallet transactions requires a full blockchain rescan. The wallet will restart, lock, and remain unavailable until the process completes."
+	var tip uint32
+	if _, _, height, err := w.load.Wallet.IsSynced(); err == nil && height > 0 {
+		tip = height
+		instructions += fmt.Sprintf("\n\nTo look for a missed deposit, enter a start height instead: only the blocks from it to the tip (%d) are scanned and the transactions are kept. It must be within the last %d blocks.", tip, flnd.MaxRescanDepth)
+	}
 
 	info := tview.NewTextView()
 	info.SetWrap(true)
@@ -108,7 +112,10 @@ func (w *Wallet) promptRescan() {
 	form.SetBorderPadding(1, 1, 2, 2)
 
 	defaultPass := strings.TrimSpace(w.load.AppConfig.DefaultPassword)
-	form.AddPasswordField("Wallet passphrase:", defaultPass, 0, '*', nil)
+	form.AddFormItem(components.NewPasswordField("Wallet passphrase:", defaultPass))
+	form.AddInputField("From height:", "", 0, tview.InputFieldInteger, nil)
+	heightField := form.GetFormItem(1).(*tview.InputField)
+	heightField.SetPlaceholder("blank for a full rescan")
 
 	form.AddButton("Cancel", func() {
 		w.closeRescanModal()
@@ -133,6 +140,16 @@ func (w *Wallet) promptRescan() {
 			w.load.Application.SetFocus(passField)
 			return
 		}
+		var fromHeight uint32
+		if text := strings.TrimSpace(heightField.GetText()); text != "" {
+			height, err := strconv.ParseUint(text, 10, 32)
+			if err != nil || height == 0 || (tip > 0 && (uint32(height) > tip || tip-uint32(height) >= flnd.MaxRescanDepth)) {
+				info.SetText(fmt.Sprintf("[red]Enter a height within the last %d blocks, or leave it blank for a full rescan.[-]\n\n", flnd.MaxRescanDepth) + instructions)
+				w.load.Application.SetFocus(heightField)
+				return
+			}
+			fromHeight = uint32(height)
+		}
 		ui.startButton = form.GetButton(1)
 		ui.setStartState("Starting…", true)
 		info.SetText("Preparing wallet rescan…")
@@ -140,7 +157,7 @@ func (w *Wallet) promptRescan() {
 		go func() {
 			ui.logProgress("Preparing wallet rescan…")
 			ui.showProgress(w)
-			w.startRescan(pass, ui)
+			w.startRescan(pass, fromHeight, ui)
 		}()
 	})
 
@@ -158,11 +175,13 @@ func (w *Wallet) promptRescan() {
 	pages.AddPage("form", view, true, true)
 	pages.AddPage("progress", progressView, true, false)
 
-	w.nav.ShowModal(components.NewModal(pages, 80, 16, nil))
+	w.nav.ShowModal(components.NewModal(pages, 80, 20, nil))
 	w.load.Application.SetFocus(form.GetFormItem(0))
 }
 
-func (w *Wallet) startRescan(pass string, ui *rescanUI) {
+// startRescan restarts the wallet to rescan the chain, from fromHeight when
+// set and after resetting its transactions otherwise.
+func (w *Wallet) startRescan(pass string, fromHeight uint32, ui *rescanUI) {
 	w.mu.Lock()
 	if w.busy {
 		w.mu.Unlock()
@@ -194,15 +213,21 @@ func (w *Wallet) startRescan(pass string, ui *rescanUI) {
 
 		started := time.Now()
 
-		if err := w.load.Wallet.TriggerRescan(); err != nil {
+		trigger, startedMsg := w.load.Wallet.TriggerRescan, "started"
+		if fromHeight > 0 {
+			trigger = func() error { return w.load.Wallet.TriggerRescanFrom(fromHeight) }
+			startedMsg = fmt.Sprintf("started from height %d", fromHeight)
+		}
+		if err := trigger(); err != nil {
 			w.finalizeRescan(log, started, nil, fmt.Errorf("failed to start rescan: %w", err))
 			return
 		}
 
+		w.load.Journal.Record(load.JournalEntry{Kind: load.JournalRescan, Message: startedMsg})
 		log("⏳ Waiting for wallet to restart…")
 
 		if err := w.autoUnlockAfterRescan(ctx, pass, log); err != nil {
-			if errors.Is(err, errInvalidPassphrase) {
+			if errors.Is(err, flnd.ErrInvalidPassphrase) {
 				w.mu.Lock()
 				w.busy = false
 				w.rescanInProgress = false
@@ -222,6 +247,15 @@ func (w *Wallet) startRescan(pass string, ui *rescanUI) {
 			return
 		}
 
+		if fromHeight > 0 {
+			// No recovery runs from a height: the wallet is done once it
+			// is synced to the tip again.
+			log(fmt.Sprintf("⏳ Rescanning from height %d…", fromHeight))
+			_, err := w.load.Wallet.WaitForState(ctx, flnd.StatusReady)
+			w.finalizeRescan(log, started, nil, err)
+			return
+		}
+
 		log("✅ Wallet RPC ready. Monitoring recovery…")
 
 		status, err := w.load.MonitorRecovery(ctx, time.Second, func(rs *load.RecoveryStatus) bool {
@@ -231,7 +265,6 @@ func (w *Wallet) startRescan(pass string, ui *rescanUI) {
 			if ui.recordStatus != nil {
 				ui.recordStatus(rs)
 			}
-			log(w.recoveryProgressMessage(rs))
 			return ctx.Err() == nil
 		})
 
@@ -272,7 +305,7 @@ func (w *Wallet) finalizeRescan(logProgress func(string), started time.Time, sta
 	}
 
 	if runErr == nil && (status == nil || count == 0) && w.load != nil && w.load.Wallet != nil {
-		if latest, err := w.load.GetRecoveryStatus(); err == nil && latest != nil {
+		if latest, err := w.load.GetRecoveryStatus(context.Background()); err == nil && latest != nil {
 			if latest.UTXOCount > count {
 				status = latest
 				count = latest.UTXOCount
@@ -284,8 +317,10 @@ func (w *Wallet) finalizeRescan(logProgress func(string), started time.Time, sta
 
 	if runErr == nil {
 		logProgress(fmt.Sprintf("✅ Rescan complete! [%d] UTXO recovered", count))
+		w.load.Journal.Record(load.JournalEntry{Kind: load.JournalRescan, Message: fmt.Sprintf("completed in %s, %d UTXOs recovered", rescanDuration, count)})
 	} else {
 		logProgress(fmt.Sprintf("[red:-:-]Rescan error:[-:-:-] %v", runErr))
+		w.load.Journal.Record(load.JournalEntry{Kind: load.JournalRescan, Message: fmt.Sprintf("failed: %v", runErr)})
 	}
 
 	w.load.QueueUpdateDraw(func() {
@@ -321,196 +356,113 @@ func (w *Wallet) autoUnlockAfterRescan(ctx context.Context, pass string, logProg
 		retryDelay  = 5 * time.Second
 	)
 
-	sub := w.load.Wallet.Subscribe()
-	defer w.load.Wallet.Unsubscribe(sub)
-
-	timer := time.NewTimer(0)
-	defer timer.Stop()
-
-	resetTimer := func(d time.Duration) {
-		if !timer.Stop() {
-			select {
-			case <-timer.C:
-			default:
-			}
-		}
-		timer.Reset(d)
+	unlocked := func() {
+		w.load.QueueUpdateDraw(func() {
+			w.load.Notif.ShowToastWithTimeout("🔓 Wallet unlocked.", time.Second*2)
+		})
 	}
 
-	attempts := 0
-	awaitingConfirmation := false
-
-	for {
-		select {
-		case <-ctx.Done():
+	// The restarted wallet comes back locked. Waiting for it keeps the state
+	// of the wallet before the restart from passing for an unlock.
+	restartCtx, cancel := context.WithTimeout(ctx, maxAttempts*retryDelay)
+	_, err := w.load.Wallet.WaitForState(restartCtx, flnd.StatusLocked)
+	cancel()
+	if err != nil {
+		if ctx.Err() != nil {
 			return ctx.Err()
+		}
+		return fmt.Errorf("wallet did not restart: %w", err)
+	}
 
-		case update, ok := <-sub:
-			if !ok || update == nil {
-				return errors.New("wallet subscription closed while unlocking")
-			}
+	for attempts := 1; attempts <= maxAttempts; attempts++ {
+		logProgress(fmt.Sprintf("Attempting to unlock wallet (%d/%d)…", attempts, maxAttempts))
 
-			switch update.State {
-			case flnd.StatusUnlocked, flnd.StatusReady, flnd.StatusSyncing:
+		err := w.load.Wallet.Unlock(ctx, pass)
+		switch {
+		case err == nil:
+			logProgress("Unlock RPC accepted. Awaiting confirmation…")
+			confirmCtx, cancel := context.WithTimeout(ctx, retryDelay)
+			_, err := w.load.Wallet.WaitForState(confirmCtx, flnd.StatusUnlocked, flnd.StatusSyncing, flnd.StatusReady)
+			cancel()
+			if err == nil {
 				logProgress("🔓 Wallet unlock confirmed.")
-				w.load.QueueUpdateDraw(func() {
-					w.load.Notif.ShowToastWithTimeout("🔓 Wallet unlocked.", time.Second*2)
-				})
+				unlocked()
 				return nil
-
-			case flnd.StatusLocked:
-				if awaitingConfirmation {
-					logProgress("Wallet reported locked again. Retrying unlock…")
-				}
-				awaitingConfirmation = false
-				resetTimer(0)
-
-			case flnd.StatusDown:
-				if update.Err != nil {
-					logProgress(fmt.Sprintf("[red:-:-]Wallet down:[-:-:-] %v", update.Err))
-				} else {
-					logProgress("Wallet service reported down state during unlock.")
-				}
-				awaitingConfirmation = false
-				resetTimer(retryDelay)
-
-			case flnd.StatusNone:
-				logProgress("Wallet initializing…")
-				awaitingConfirmation = false
-				resetTimer(retryDelay)
-			}
-
-		case <-timer.C:
-			if awaitingConfirmation {
-				logProgress("Unlock confirmation timed out. Retrying…")
-				awaitingConfirmation = false
-				resetTimer(0)
-				continue
 			}
-
-			if attempts >= maxAttempts {
-				logProgress("Exceeded maximum unlock attempts.")
-				return errors.New("wallet did not unlock after multiple attempts")
+			if ctx.Err() != nil {
+				return ctx.Err()
 			}
+			logProgress("Unlock confirmation timed out. Retrying…")
+			continue
 
-			attempts++
-			logProgress(fmt.Sprintf("Attempting to unlock wallet (%d/%d)…", attempts, maxAttempts))
+		case errors.Is(err, flnd.ErrWalletUnlocked):
+			logProgress("Wallet already unlocked.")
+			unlocked()
+			return nil
 
-			err := w.load.Wallet.Unlock(pass)
-			if err == nil {
-				awaitingConfirmation = true
-				logProgress("Unlock RPC accepted. Awaiting confirmation…")
-				resetTimer(retryDelay)
-				continue
-			}
-
-			st, ok := status.FromError(err)
-			if ok {
-				msg := strings.ToLower(st.Message())
-				if strings.Contains(msg, "already unlocked") {
-					logProgress("Wallet already unlocked.")
-					w.load.QueueUpdateDraw(func() {
-						w.load.Notif.ShowToastWithTimeout("🔓 Wallet unlocked.", time.Second*2)
-					})
-					return nil
-				}
-				if strings.Contains(msg, "invalid passphrase") {
-					logProgress("[red:-:-]Unlock failed:[-:-:-] invalid passphrase provided.")
-					return errInvalidPassphrase
-				}
-				switch st.Code() {
-				case codes.Unavailable, codes.Canceled, codes.DeadlineExceeded, codes.FailedPrecondition, codes.Unknown:
-					logProgress("Wallet service not ready. Waiting before retry…")
-					resetTimer(retryDelay)
-					continue
-				default:
-					logProgress(fmt.Sprintf("[red:-:-]Unlock failed:[-:-:-] %v", err))
-					return err
-				}
-			}
+		case errors.Is(err, flnd.ErrInvalidPassphrase):
+			logProgress("[red:-:-]Unlock failed:[-:-:-] invalid passphrase provided.")
+			return flnd.ErrInvalidPassphrase
+		}
 
-			lower := strings.ToLower(err.Error())
-			if strings.Contains(lower, "already unlocked") {
-				logProgress("Wallet already unlocked.")
-				w.load.QueueUpdateDraw(func() {
-					w.load.Notif.ShowToastWithTimeout("🔓 Wallet unlocked.", time.Second*2)
-				})
-				return nil
-			}
-			if strings.Contains(lower, "invalid passphrase") {
-				logProgress("[red:-:-]Unlock failed:[-:-:-] invalid passphrase provided.")
-				return errInvalidPassphrase
+		if st, ok := status.FromError(err); ok {
+			switch st.Code() {
+			case codes.Unavailable, codes.Canceled, codes.DeadlineExceeded, codes.FailedPrecondition, codes.Unknown:
+				logProgress("Wallet service not ready. Waiting before retry…")
+			default:
+				logProgress(fmt.Sprintf("[red:-:-]Unlock failed:[-:-:-] %v", err))
+				return err
 			}
-
+		} else {
 			logProgress(fmt.Sprintf("[red:-:-]Unlock failed:[-:-:-] %v", err))
-			resetTimer(retryDelay)
-			continue
 		}
-	}
-}
 
-func (w *Wallet) waitForWalletRPC(ctx context.Context, logProgress func(string)) error {
-	sub := w.load.Wallet.Subscribe()
-	defer w.load.Wallet.Unsubscribe(sub)
-
-	logProgress("Waiting for wallet RPC readiness signals…")
-
-	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case <-time.After(retryDelay):
+		}
+	}
 
-		case update, ok := <-sub:
-			if !ok || update == nil {
-				return errors.New("wallet subscription closed while waiting for RPC")
-			}
-
-			switch update.State {
-			case flnd.StatusReady, flnd.StatusBlock, flnd.StatusTransaction, flnd.StatusSyncing:
-				logProgress("Wallet RPC ready.")
-				return nil
-
-			case flnd.StatusUnlocked:
-				logProgress("Wallet unlocked. Waiting for RPC to become active…")
-
-			case flnd.StatusDown:
-				if update.Err != nil {
-					logProgress(fmt.Sprintf("[red:-:-]Wallet down:[-:-:-] %v", update.Err))
-				} else {
-					logProgress("Wallet service reported down state. Waiting…")
-				}
-
-			case flnd.StatusNone:
-				logProgress("Wallet initializing RPC services…")
+	logProgress("Exceeded maximum unlock attempts.")
+	return errors.New("wallet did not unlock after multiple attempts")
+}
 
-			case flnd.StatusLocked:
-				logProgress("Wallet locked; still waiting for RPC availability…")
+func (w *Wallet) waitForWalletRPC(ctx context.Context, logProgress func(string)) error {
+	logProgress("Waiting for wallet RPC readiness signals…")
 
-			default:
-				// ignore other states
-			}
-		}
+	if _, err := w.load.Wallet.WaitForState(ctx, flnd.StatusReady, flnd.StatusSyncing); err != nil {
+		return err
 	}
+	logProgress("Wallet RPC ready.")
+	return nil
 }
 
-func (w *Wallet) newRescanProgressView(netColor tcell.Color) (*tview.TextView, func(string), func(*load.RecoveryStatus), func() *load.RecoveryStatus) {
+func (w *Wallet) newRescanProgressView(netColor tcell.Color) (tview.Primitive, func(string), func(*load.RecoveryStatus), func() *load.RecoveryStatus) {
 	progressView := tview.NewTextView()
 	progressView.SetDynamicColors(true)
 	progressView.SetWrap(true)
 	progressView.SetScrollable(true)
 	progressView.SetChangedFunc(func() { progressView.ScrollToEnd() })
 	progressView.SetTextAlign(tview.AlignLeft)
-	progressView.SetBorder(true)
-	progressView.SetTitle("Wallet Rescan")
-	progressView.SetTitleAlign(tview.AlignCenter)
-	progressView.SetTitleColor(netColor)
-	progressView.SetBorderColor(netColor)
-	progressView.SetBorderPadding(1, 1, 2, 2)
 	progressView.SetBackgroundColor(tcell.ColorDefault)
 
+	progressBar := components.NewProgressBar(w.load.Application)
+	progressBar.SetIndeterminate()
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(progressView, 0, 1, false).
+		AddItem(progressBar, 1, 0, false)
+	view.SetBorder(true)
+	view.SetTitle("Wallet Rescan")
+	view.SetTitleAlign(tview.AlignCenter)
+	view.SetTitleColor(netColor)
+	view.SetBorderColor(netColor)
+	view.SetBorderPadding(1, 1, 2, 2)
+	view.SetBackgroundColor(tcell.ColorDefault)
+
 	var mu sync.Mutex
 	logLines := make([]string, 0, 8)
-	progressLineIdx := -1
 	var lastStatus *load.RecoveryStatus
 	var bestStatus *load.RecoveryStatus
 
@@ -519,21 +471,9 @@ func (w *Wallet) newRescanProgressView(netColor tcell.Color) (*tview.TextView, f
 			return
 		}
 
-		timestamp := time.Now().Format("15:04:05")
+		timestamp := time.Now().In(shared.TimeZone()).Format("15:04:05")
 		mu.Lock()
-		line := fmt.Sprintf("[%s] %s", timestamp, message)
-
-		if strings.Contains(message, "Recovery in progress") {
-			if progressLineIdx >= 0 && progressLineIdx < len(logLines) {
-				logLines[progressLineIdx] = line
-			} else {
-				progressLineIdx = len(logLines)
-				logLines = append(logLines, line)
-			}
-		} else {
-			logLines = append(logLines, line)
-			progressLineIdx = -1
-		}
+		logLines = append(logLines, fmt.Sprintf("[%s] %s", timestamp, message))
 		content := strings.Join(logLines, "\n")
 		mu.Unlock()
 
@@ -562,9 +502,14 @@ func (w *Wallet) newRescanProgressView(netColor tcell.Color) (*tview.TextView, f
 			bestStatus = &bestCopy
 		}
 		mu.Unlock()
+
+		progressBar.SetLabel(recoveryProgressLabel(&copyStatus))
+		if progress, ok := recoveryProgress(&copyStatus); ok {
+			progressBar.SetProgress(progress)
+		}
 	}
 
-	return progressView, appendLine, updateStatus, func() *load.RecoveryStatus {
+	return view, appendLine, updateStatus, func() *load.RecoveryStatus {
 		mu.Lock()
 		defer mu.Unlock()
 		if bestStatus != nil {
@@ -579,27 +524,26 @@ func (w *Wallet) newRescanProgressView(netColor tcell.Color) (*tview.TextView, f
 	}
 }
 
-func (w *Wallet) recoveryProgressMessage(rs *load.RecoveryStatus) string {
-	count := 0
-	if rs != nil {
-		count = rs.UTXOCount
+// recoveryProgress returns the completion of a recovery, false until the
+// wallet reports one.
+func recoveryProgress(rs *load.RecoveryStatus) (float64, bool) {
+	if rs == nil || rs.Info == nil {
+		return 0, false
 	}
-
-	var percentText string
-	if rs != nil && rs.Info != nil {
-		progress := rs.Info.GetProgress() * 100
-		if progress > 100 {
-			progress = 100
-		}
-		if rs.Info.GetRecoveryFinished() {
-			progress = 100
-		}
-		if progress > 0 {
-			percentText = fmt.Sprintf(" • %.2f%% complete", progress)
-		}
+	if rs.Info.GetRecoveryFinished() {
+		return 1, true
 	}
+	progress := rs.Info.GetProgress()
+	return min(progress, 1), progress > 0
+}
 
-	return fmt.Sprintf("⏳ Recovery in progress… [%d] UTXO recovered %s", count, percentText)
+// recoveryProgressLabel is the text shown next to the rescan progress bar.
+func recoveryProgressLabel(rs *load.RecoveryStatus) string {
+	label := fmt.Sprintf("[%d] UTXO recovered", rs.UTXOCount)
+	if rs.Estimate != nil {
+		label += " • " + rs.Estimate.String()
+	}
+	return label
 }
 
 func humanDuration(d time.Duration) string {
@@ -643,7 +587,7 @@ func (w *Wallet) isWalletLocked() bool {
 	if w == nil || w.load == nil || w.load.Wallet == nil {
 		return false
 	}
-	locked, err := w.load.Wallet.IsLocked()
+	locked, err := w.load.Wallet.IsLocked(context.Background())
 	if err == nil {
 		return locked
 	}
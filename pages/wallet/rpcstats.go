@@ -0,0 +1,125 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/flnd"
+	"github.com/flokiorg/twallet/shared"
+)
+
+// showRPCStats lists the daemon RPC calls made since the wallet started, the
+// ones that took the most time first, to tell which call a slowdown comes
+// from.
+func (w *Wallet) showRPCStats() {
+	w.load.Notif.CancelToast()
+
+	columns := []components.Column{
+		{Name: "Method", Align: tview.AlignLeft},
+		{Name: "Calls", Align: tview.AlignRight},
+		{Name: "Errors", Align: tview.AlignRight},
+		{Name: "Mean", Align: tview.AlignRight},
+		{Name: "Max", Align: tview.AlignRight},
+		{Name: "Total", Align: tview.AlignRight},
+		{Name: "Last error", Align: tview.AlignLeft},
+	}
+
+	table := components.NewTable("RPC calls", columns, shared.NetworkColor(*w.load.AppConfig.Network), 0)
+	table.SetBorder(false)
+	table.SetBorderPadding(0, 0, 1, 1)
+
+	accent := shared.CurrentTheme().Accent
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s:-:-]<esc>[gray:-:-] close  [%s:-:-]<r>[gray:-:-] reload", accent, accent))
+
+	feeStatus := tview.NewTextView().SetDynamicColors(true)
+	feeStatus.SetBorderPadding(0, 0, 1, 1)
+
+	render := func() {
+		feeStatus.SetText(formatFeeSource(w.load.Wallet.FeeSource()))
+		stats := w.load.Wallet.RPCStats()
+		if len(stats) == 0 {
+			table.ShowPlaceholder("No RPC calls yet.")
+			return
+		}
+		rows := make([][]string, 0, len(stats))
+		for _, stat := range stats {
+			errors := "[gray::]0"
+			if stat.Errors > 0 {
+				errors = fmt.Sprintf("[%s:-:-]%d", shared.CurrentTheme().Error, stat.Errors)
+			}
+			rows = append(rows, []string{
+				stat.Method,
+				fmt.Sprintf("%d", stat.Calls),
+				errors,
+				formatRPCDuration(stat.Mean()),
+				formatRPCDuration(stat.Max),
+				formatRPCDuration(stat.Total),
+				fmt.Sprintf("[gray::]%s", tview.Escape(stat.LastError)),
+			})
+		}
+		table.Update(rows)
+		table.Select(1, 0)
+		table.ScrollToBeginning()
+	}
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetTitle("RPC calls").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	container.AddItem(feeStatus, 1, 0, false).
+		AddItem(table, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	container.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && unicode.ToLower(event.Rune()) == 'r' {
+			render()
+			return nil
+		}
+		return event
+	})
+
+	w.nav.ShowModal(components.NewModal(container, 120, 30, w.closeModal))
+	render()
+	w.load.Application.SetFocus(table)
+}
+
+func formatRPCDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	case d >= time.Millisecond:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	default:
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	}
+}
+
+// formatFeeSource tells which fee API the daemon reads through the failover.
+func formatFeeSource(source flnd.FeeSource) string {
+	switch {
+	case source.Sources == 0:
+		return "[gray::]Fee API: none configured"
+	case source.URL == "" && source.Err != nil:
+		return fmt.Sprintf("[%s:-:-]Fee API: no source answered:[-:-:-] %s", shared.CurrentTheme().Error, tview.Escape(source.Err.Error()))
+	case source.URL == "":
+		return fmt.Sprintf("[gray::]Fee API: not asked yet (%d configured)", source.Sources)
+	}
+	text := fmt.Sprintf("Fee API: %s [gray::]answered %s ago, %d configured", tview.Escape(source.URL), time.Since(source.Fetched).Round(time.Second), source.Sources)
+	if source.Err != nil {
+		text += fmt.Sprintf(" [%s:-:-]· serving the last answer, the sources failed", shared.CurrentTheme().Error)
+	}
+	return text
+}
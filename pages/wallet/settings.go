@@ -0,0 +1,212 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/flnd"
+	"github.com/flokiorg/twallet/shared"
+)
+
+// applyConfigTimeout bounds the wait for the daemon to come back with new
+// settings.
+const applyConfigTimeout = 3 * time.Minute
+
+// daemonSetting is a field of the daemon settings form and where its value
+// goes in a config change.
+type daemonSetting struct {
+	label   string
+	current []string
+	list    bool
+	set     func(*flnd.ConfigChange, []string)
+}
+
+func daemonSettings(cfg *flnd.ServiceConfig) []daemonSetting {
+	value := func(v string) []string {
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	}
+	return []daemonSetting{
+		{"Connect only to:", cfg.ConnectPeers, true, func(c *flnd.ConfigChange, v []string) { c.ConnectPeers = &v }},
+		{"Add peers:", cfg.AddPeers, true, func(c *flnd.ConfigChange, v []string) { c.AddPeers = &v }},
+		{"Fee URL:", value(cfg.Feeurl), false, func(c *flnd.ConfigChange, v []string) { c.Feeurl = first(v) }},
+		{"Alias:", value(cfg.Alias), false, func(c *flnd.ConfigChange, v []string) { c.Alias = first(v) }},
+		{"Peer listen:", cfg.RawListeners, true, func(c *flnd.ConfigChange, v []string) { c.RawListeners = &v }},
+		{"RPC listen:", cfg.RawRPCListeners, true, func(c *flnd.ConfigChange, v []string) { c.RawRPCListeners = &v }},
+		{"REST listen:", cfg.RawRESTListeners, true, func(c *flnd.ConfigChange, v []string) { c.RawRESTListeners = &v }},
+	}
+}
+
+func first(values []string) *string {
+	v := ""
+	if len(values) > 0 {
+		v = values[0]
+	}
+	return &v
+}
+
+// showDaemonSettings edits the daemon settings that can change while
+// twallet runs. Applying them saves them to the config file and restarts the
+// daemon, which comes back locked.
+func (w *Wallet) showDaemonSettings() {
+	if w.load == nil || w.load.AppConfig == nil {
+		return
+	}
+
+	w.load.Notif.CancelToast()
+
+	netColor := shared.NetworkColor(*w.load.AppConfig.Network)
+	settings := daemonSettings(&w.load.AppConfig.ServiceConfig)
+
+	instructions := "Lists are comma separated. Applying saves the settings and restarts the wallet daemon: the wallet locks and has to be unlocked again."
+
+	info := tview.NewTextView()
+	info.SetWrap(true)
+	info.SetDynamicColors(true)
+	info.SetText(instructions)
+	info.SetBackgroundColor(tcell.ColorDefault)
+	info.SetBorderPadding(1, 0, 2, 2)
+
+	form := tview.NewForm()
+	form.SetBackgroundColor(tcell.ColorDefault)
+	form.SetBorderPadding(1, 1, 2, 2)
+	for _, s := range settings {
+		form.AddInputField(s.label, strings.Join(s.current, ","), 0, nil, nil)
+	}
+
+	progressView := tview.NewTextView()
+	progressView.SetDynamicColors(true)
+	progressView.SetWrap(true)
+	progressView.SetBackgroundColor(tcell.ColorDefault)
+	progressView.SetBorderPadding(1, 1, 2, 2)
+
+	pages := tview.NewPages()
+
+	form.AddButton("Cancel", w.closeModal)
+	form.AddButton("Apply", func() {
+		var change flnd.ConfigChange
+		changed := false
+		for i, s := range settings {
+			values := splitSettingValues(form.GetFormItem(i).(*tview.InputField).GetText(), s.list)
+			if slices.Equal(values, s.current) {
+				continue
+			}
+			s.set(&change, values)
+			changed = true
+		}
+		if !changed {
+			info.SetText("[yellow]Nothing changed.[-]\n\n" + instructions)
+			return
+		}
+		if err := change.Validate(); err != nil {
+			info.SetText(fmt.Sprintf("[red]%s[-]\n\n%s", err.Error(), instructions))
+			return
+		}
+
+		pages.SwitchToPage("progress")
+		go w.applyDaemonSettings(change, progressView)
+	})
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(info, 4, 0, false).
+		AddItem(form, 0, 1, true)
+
+	pages.AddPage("form", view, true, true)
+	pages.AddPage("progress", progressView, true, false)
+	pages.SetBorder(true).
+		SetTitle(" Daemon Settings ").
+		SetTitleAlign(tview.AlignCenter).
+		SetTitleColor(netColor).
+		SetBorderColor(netColor)
+
+	w.nav.ShowModal(components.NewModal(pages, 90, 26, w.closeModal))
+	w.load.Application.SetFocus(form)
+}
+
+// applyDaemonSettings saves change and restarts the daemon with it, logging
+// the steps to progressView.
+func (w *Wallet) applyDaemonSettings(change flnd.ConfigChange, progressView *tview.TextView) {
+	w.mu.Lock()
+	if w.busy {
+		w.mu.Unlock()
+		return
+	}
+	w.busy = true
+	w.mu.Unlock()
+	defer func() {
+		w.mu.Lock()
+		w.busy = false
+		w.mu.Unlock()
+	}()
+
+	var lines []string
+	progress := func(step string) {
+		lines = append(lines, fmt.Sprintf("[%s] %s", time.Now().Format("15:04:05"), step))
+		text := strings.Join(lines, "\n")
+		w.load.QueueUpdateDraw(func() {
+			progressView.SetText(text)
+		})
+	}
+
+	cfg := w.load.AppConfig
+	persist := func(o flnd.ConfigOption) error {
+		return cfg.SaveOption(o.Key, o.Values...)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), applyConfigTimeout)
+	defer cancel()
+	err := w.load.Wallet.ApplyConfig(ctx, change, persist, progress)
+	if err == nil || !errors.Is(err, flnd.ErrInvalidConfig) {
+		// What was saved is what the next start uses, even if this one
+		// failed.
+		change.ApplyTo(&cfg.ServiceConfig)
+	}
+
+	w.load.QueueUpdateDraw(func() {
+		w.nav.CloseModal()
+		if err != nil {
+			w.nav.ShowModal(components.ErrorModal(fmt.Sprintf("Applying the settings failed: %v", err), func() {
+				w.nav.CloseModal()
+				w.focusActiveView()
+			}))
+			return
+		}
+		w.load.Notif.ShowToastWithTimeout("Daemon settings applied", time.Second*5)
+		if !w.navigateToUnlockPage() {
+			w.focusActiveView()
+		}
+	})
+}
+
+// splitSettingValues parses a form field: comma separated values for lists,
+// the trimmed text otherwise.
+func splitSettingValues(text string, list bool) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if !list {
+		return []string{text}
+	}
+	var values []string
+	for _, v := range strings.Split(text, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
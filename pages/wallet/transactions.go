@@ -3,6 +3,7 @@ package wallet
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -10,59 +11,202 @@ import (
 	"github.com/flokiorg/flnd/lnrpc"
 	"github.com/flokiorg/go-flokicoin/chainutil"
 
+	"github.com/flokiorg/twallet/components"
 	"github.com/flokiorg/twallet/flnd"
 	"github.com/flokiorg/twallet/load"
 	"github.com/flokiorg/twallet/shared"
 )
 
-const transactionsUpdateRetryInterval = 5 * time.Second
+const (
+	transactionsUpdateRetryInterval = 5 * time.Second
+
+	// walletRefreshInterval is the least time between two refreshes driven
+	// by block or syncing updates.
+	walletRefreshInterval = 3 * time.Second
+)
+
+// Transactions table column indexes.
+const (
+	txColTimestamp = iota
+	txColTxID
+	txColAddress
+	txColAmount
+	txColFee
+	txColConfirmations
+)
 
 type txRetryHandle struct {
 	cancel context.CancelFunc
 }
 
-func (w *Wallet) fetchTransactionsRows() [][]string {
+// fetchTransactions streams the wallet transactions and keeps the newest
+// ones, with the tip height they were fetched at, so the table can be
+// re-sorted without another round trip. The table is redrawn as pages
+// arrive, and the fetch is cancelled when the wallet page quits.
+func (w *Wallet) fetchTransactions() bool {
 	tipHeight := w.load.Cache.GetTipHeight()
-	opts := flnd.FetchTransactionsOptions{
-		OnProgress: func(count int) {
-			w.showPlaceholder(fmt.Sprintf("Loading transactions... (%d)", count))
-		},
+	limit := w.load.AppConfig.TransactionDisplayLimit
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-w.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var txs []*lnrpc.Transaction
+	for page := range w.load.Wallet.StreamTransactions(ctx, flnd.FetchTransactionsOptions{}) {
+		if page.Err != nil {
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", page.Err.Error()), time.Second*30)
+			return false
+		}
+
+		txs = append(txs, page.Transactions...)
+		sortTransactions(txs, tipHeight, txColTimestamp, components.Descending)
+		if limit > 0 && len(txs) > limit {
+			txs = txs[:limit]
+		}
+
+		w.txMu.Lock()
+		w.txs = append([]*lnrpc.Transaction(nil), txs...)
+		w.txTipHeight = tipHeight
+		w.txMu.Unlock()
+
+		w.renderRows()
 	}
-	txs, err := w.load.Wallet.FetchTransactionsWithOptions(opts)
-	if err != nil {
-		w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
-		return nil
+
+	if ctx.Err() != nil {
+		return false
+	}
+	if txs == nil {
+		w.txMu.Lock()
+		w.txs = nil
+		w.txTipHeight = tipHeight
+		w.txMu.Unlock()
+	}
+	return true
+}
+
+func (w *Wallet) transactionsRows() *transactionRows {
+	w.txMu.Lock()
+	txs := filterTransactionsByDate(append([]*lnrpc.Transaction(nil), w.txs...), w.dateFilter)
+	tipHeight := w.txTipHeight
+	w.txMu.Unlock()
+
+	if column, dir, ok := w.table.SortColumn(); ok {
+		sortTransactions(txs, tipHeight, column, dir)
 	}
 
-	rows := [][]string{}
-	for _, tx := range txs {
+	w.txMu.Lock()
+	w.txRows = txs
+	w.txMu.Unlock()
 
-		row := []string{}
-		row = append(row, timestampToLocalString(tx.TimeStamp))
-		row = append(row, shortTxID(tx.TxHash))
-		row = append(row, formatOutputAddresses(tx.OutputDetails))
-		flcAmount := chainutil.Amount(tx.Amount)
+	return &transactionRows{w: w, txs: txs, tipHeight: tipHeight}
+}
 
-		if flcAmount > 0 {
-			row = append(row, fmt.Sprintf("[green:-:-]%s", shared.FormatAmountView(flcAmount, 6)))
-		} else {
-			row = append(row, fmt.Sprintf("[red:-:-]%s", shared.FormatAmountView(flcAmount, 6)))
-		}
-		numConfirmations := int64(tipHeight - tx.BlockHeight + 1)
-		if tx.BlockHeight < 1 {
-			numConfirmations = 0
-		}
+// transactionRows formats the transactions for the table as they are drawn,
+// so a large history does not cost a row of strings per transaction.
+type transactionRows struct {
+	w         *Wallet
+	txs       []*lnrpc.Transaction
+	tipHeight int32
+}
 
-		if numConfirmations < 1 {
-			row = append(row, strconv.FormatInt(0, 10))
-		} else {
-			row = append(row, strconv.FormatInt(numConfirmations, 10))
-		}
-		rows = append(rows, row)
+func (r *transactionRows) RowCount() int { return len(r.txs) }
+
+// RowKey keeps the marks of the table on the transactions as they move.
+func (r *transactionRows) RowKey(index int) string { return r.txs[index].TxHash }
+
+func (r *transactionRows) GetRow(index int) []string {
+	tx := r.txs[index]
+
+	row := []string{}
+	row = append(row, shared.FormatUnixTime(tx.TimeStamp))
+	row = append(row, shortTxID(tx.TxHash))
+	row = append(row, formatOutputAddresses(tx.OutputDetails))
+	flcAmount := chainutil.Amount(tx.Amount)
+
+	amountCell := shared.FormatAmountView(flcAmount, 6)
+	if fiat := r.w.load.FiatView(flcAmount); fiat != "" {
+		amountCell += fmt.Sprintf(" (%s)", fiat)
+	}
+	row = append(row, amountCell)
+	feeCell := "-"
+	if tx.TotalFees > 0 {
+		feeCell = shared.FormatAmountView(chainutil.Amount(tx.TotalFees), 6)
+	}
+	row = append(row, feeCell)
+	confirmations := strconv.FormatInt(txConfirmations(tx, r.tipHeight), 10)
+	if r.w.load.Cache.ConfirmationSuspect(tx.BlockHeight) {
+		confirmations += "?"
+	}
+	row = append(row, confirmations)
+	return row
+}
+
+// resortTransactions re-renders the cached transactions in a new order. It
+// runs on the UI goroutine from the table sort handler.
+func (w *Wallet) resortTransactions(int, components.SortOrder) {
+	w.renderCachedTransactions()
+}
+
+// renderCachedTransactions redraws the table from the last fetched
+// transactions, or the current placeholder, without querying the wallet. It
+// must run on the UI goroutine.
+func (w *Wallet) renderCachedTransactions() {
+	w.stateMu.Lock()
+	placeholder := w.placeholder
+	w.stateMu.Unlock()
+	if placeholder != "" {
+		w.table.ShowPlaceholder(placeholder)
+		return
 	}
 
-	return rows
+	rows := w.transactionsRows()
+	if rows.RowCount() == 0 {
+		return
+	}
+	w.table.SetProvider(rows)
+	w.table.ScrollToBeginning()
+}
+
+func txConfirmations(tx *lnrpc.Transaction, tipHeight int32) int64 {
+	if tx.BlockHeight < 1 {
+		return 0
+	}
+	numConfirmations := int64(tipHeight - tx.BlockHeight + 1)
+	if numConfirmations < 1 {
+		return 0
+	}
+	return numConfirmations
+}
+
+func sortTransactions(txs []*lnrpc.Transaction, tipHeight int32, column int, dir components.SortOrder) {
+	var less func(a, b *lnrpc.Transaction) bool
+	switch column {
+	case txColTimestamp:
+		less = func(a, b *lnrpc.Transaction) bool { return a.TimeStamp < b.TimeStamp }
+	case txColAmount:
+		less = func(a, b *lnrpc.Transaction) bool { return a.Amount < b.Amount }
+	case txColFee:
+		less = func(a, b *lnrpc.Transaction) bool { return a.TotalFees < b.TotalFees }
+	case txColConfirmations:
+		less = func(a, b *lnrpc.Transaction) bool {
+			return txConfirmations(a, tipHeight) < txConfirmations(b, tipHeight)
+		}
+	default:
+		return
+	}
 
+	sort.SliceStable(txs, func(i, j int) bool {
+		if dir == components.Descending {
+			return less(txs[j], txs[i])
+		}
+		return less(txs[i], txs[j])
+	})
 }
 
 func (w *Wallet) listenNewTransactions() {
@@ -90,13 +234,14 @@ func (w *Wallet) handleNotification(evt *load.NotificationEvent) {
 	w.cancelTransactionsUpdateRetry()
 
 	switch evt.State {
-	case flnd.StatusReady, flnd.StatusTransaction, flnd.StatusBlock:
-		w.onceReady.Do(func() {
-			w.showPlaceholder("Loading transactions...")
-		})
-		if !w.updateRows() {
-			w.scheduleTransactionsUpdateRetry()
-		}
+	case flnd.StatusReady, flnd.StatusTransaction:
+		w.refreshTransactions()
+		return
+
+	case flnd.StatusBlock:
+		// Blocks come in bursts while the wallet catches up, each one
+		// refetching would only hammer the daemon.
+		w.blockRefresh.Trigger()
 		return
 
 	case flnd.StatusScanning:
@@ -108,26 +253,7 @@ func (w *Wallet) handleNotification(evt *load.NotificationEvent) {
 		return
 
 	case flnd.StatusSyncing:
-		msg := "Syncing transactions..."
-
-		if rs, err := w.load.GetRecoveryStatus(); err == nil && rs != nil && rs.Info != nil {
-			progress := rs.Info.GetProgress()
-			switch {
-			case rs.Info.GetRecoveryFinished():
-				msg = "Syncing transactions... finalizing recovery"
-
-			case progress > 1:
-				recoveryPercent := (2 - progress) * 100
-				if recoveryPercent < 0 {
-					recoveryPercent = 0
-				}
-				msg = fmt.Sprintf("Syncing transactions... recovering... %.2f%% complete", recoveryPercent)
-			case progress > 0:
-				msg = fmt.Sprintf("Syncing transactions... %.2f%% complete", progress*100)
-			}
-		}
-
-		w.showPlaceholder(msg)
+		w.syncRefresh.Trigger()
 		return
 
 	case flnd.StatusUnlocked:
@@ -146,6 +272,10 @@ func (w *Wallet) handleNotification(evt *load.NotificationEvent) {
 		w.showPlaceholder("Reconnecting to wallet...")
 		return
 
+	case flnd.StatusFailedPermanently:
+		w.showPlaceholder("The wallet daemon failed to start. Restart twallet to try again.")
+		return
+
 	case flnd.StatusLocked:
 		if w.isRescanActive() {
 			w.showPlaceholder("Wallet rescan: waiting for unlock...")
@@ -158,6 +288,44 @@ func (w *Wallet) handleNotification(evt *load.NotificationEvent) {
 	w.showPlaceholder("Loading transactions...")
 }
 
+func (w *Wallet) refreshTransactions() {
+	w.onceReady.Do(func() {
+		w.showPlaceholder("Loading transactions...")
+	})
+	if !w.updateRows() {
+		w.scheduleTransactionsUpdateRetry()
+	}
+}
+
+// showSyncProgress shows how far the recovery got while the wallet syncs.
+// It is throttled as the syncing updates come much faster than it changes.
+func (w *Wallet) showSyncProgress() {
+	if w.load.Wallet.Status() != flnd.StatusSyncing {
+		return
+	}
+
+	msg := "Syncing transactions..."
+
+	if rs, err := w.load.GetRecoveryStatus(context.Background()); err == nil && rs != nil && rs.Info != nil {
+		progress := rs.Info.GetProgress()
+		switch {
+		case rs.Info.GetRecoveryFinished():
+			msg = "Syncing transactions... finalizing recovery"
+
+		case progress > 1:
+			recoveryPercent := (2 - progress) * 100
+			if recoveryPercent < 0 {
+				recoveryPercent = 0
+			}
+			msg = fmt.Sprintf("Syncing transactions... recovering... %.2f%% complete", recoveryPercent)
+		case progress > 0:
+			msg = fmt.Sprintf("Syncing transactions... %.2f%% complete", progress*100)
+		}
+	}
+
+	w.showPlaceholder(msg)
+}
+
 func (w *Wallet) showPlaceholder(message string) {
 	if message == "" {
 		return
@@ -207,13 +375,25 @@ func (w *Wallet) clearPlaceholder() {
 }
 
 func (w *Wallet) updateRows() bool {
-	rows := w.fetchTransactionsRows()
-	if rows == nil {
+	if !w.fetchTransactions() {
 		return false
 	}
+	w.renderRows()
+	return true
+}
+
+// renderRows queues a redraw of the table from the fetched transactions.
+func (w *Wallet) renderRows() {
+	rows := w.transactionsRows()
+	w.txMu.Lock()
+	filter := w.dateFilter
+	w.txMu.Unlock()
 	w.load.Application.QueueUpdateDraw(func() {
-		if len(rows) == 0 {
+		if rows.RowCount() == 0 {
 			message := "No transactions yet."
+			if !filter.IsZero() {
+				message = fmt.Sprintf("No transactions for %s.", filter.String())
+			}
 			w.updatePlaceholderState(message)
 			w.stateMu.Lock()
 			defer w.stateMu.Unlock()
@@ -224,9 +404,8 @@ func (w *Wallet) updateRows() bool {
 			return
 		}
 		w.clearPlaceholder()
-		w.table.Update(rows)
+		w.table.SetProvider(rows)
 	})
-	return true
 }
 
 func (w *Wallet) scheduleTransactionsUpdateRetry() {
@@ -281,11 +460,6 @@ func (w *Wallet) cancelTransactionsUpdateRetry() {
 	}
 }
 
-func timestampToLocalString(ts int64) string {
-	t := time.Unix(ts, 0).Local()
-	return t.Format("2006-01-02 15:04:05")
-}
-
 func shortTxID(txID string) string {
 	if len(txID) < 10 {
 		return txID // not enough characters to shorten
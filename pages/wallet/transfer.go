@@ -1,8 +1,10 @@
 package wallet
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/flokiorg/go-flokicoin/chainutil"
@@ -32,18 +34,74 @@ type sendViewModel struct {
 	finalTx                *chainutil.Tx
 	locks                  []*flnd.OutputLock
 	feeCalcID              uint64
+	confTarget             int32
+}
+
+// feeSpeeds names the confirmation targets of flnd.FeeTargets.
+var feeSpeeds = map[int32]string{
+	1:   "Fast (~1 block)",
+	3:   "Normal (~3 blocks)",
+	6:   "Slow (~6 blocks)",
+	144: "Economy (~1 day)",
+}
+
+// feeSpeedOptions labels the confirmation targets, with their fee rate once
+// it is known. Once fetched, a target left out of rates is unavailable.
+func feeSpeedOptions(rates map[int32]float64, fetched bool) []string {
+	options := make([]string, len(flnd.FeeTargets))
+	for i, target := range flnd.FeeTargets {
+		options[i] = feeSpeeds[target]
+		if rate, ok := rates[target]; ok {
+			options[i] = fmt.Sprintf("%s · %.0f loki/vB", options[i], rate)
+		} else if fetched {
+			options[i] += " · unavailable"
+		}
+	}
+	return options
 }
 
 func (w *Wallet) showTransfertView() {
 
 	w.load.Notif.CancelToast()
+	if w.load.Fees != nil {
+		if rate, congested := w.load.Fees.Congestion(); congested {
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[yellow:-:-]Fees are high (%.0f loki/vB).[-:-:-] Consider deferring this send if it is not urgent.", rate), time.Second*15)
+		}
+	}
 
 	confirmedBalanceView := shared.FormatAmountView(w.confirmedBalance(), 6)
 
 	form := tview.NewForm()
 	form.SetBackgroundColor(tcell.ColorDefault).SetBorderPadding(2, 2, 3, 3)
-	form.AddTextArea("Destination Address:", "", 0, 2, 0, func(text string) { w.transferAmountChanged(form) }).
-		AddInputField("Amount:", "", 0, nil, func(text string) { w.transferAmountChanged(form) }).
+	onSpeed := func(_ string, index int) {
+		if index < 0 {
+			return
+		}
+		w.mu.Lock()
+		w.svCache.confTarget = flnd.FeeTargets[index]
+		w.mu.Unlock()
+		w.transferAmountChanged(form)
+	}
+	addressField := tview.NewTextArea().
+		SetLabel("Destination Address:").
+		SetSize(2, 0)
+	addressField.SetChangedFunc(func() { w.transferAmountChanged(form) })
+	amountField := components.NewAmountField("Amount").
+		SetChangedFunc(func(text string) { w.transferAmountChanged(form) })
+	// 'u' in the field switches the whole wallet, so the amounts of the form
+	// are in the unit of its label.
+	amountField.SetToggledFunc(func(unit shared.Denomination) {
+		w.setDenomination(unit)
+		if balanceField, ok := form.GetFormItem(5).(*tview.TextView); ok {
+			balanceField.SetText(fmt.Sprintf("[gray::]%s", shared.FormatAmountView(w.confirmedBalance(), 6)))
+		}
+		w.transferAmountChanged(form)
+	})
+
+	validator := components.NewFormValidator()
+	form.AddFormItem(validator.Field(addressField, components.AddressForNetwork(w.load.AppConfig.Network))).
+		AddFormItem(validator.Field(amountField, amountField.InRange(1, 0))).
+		AddDropDown("Speed:", feeSpeedOptions(nil, false), 0, onSpeed).
 		AddTextView("Fee:", fmt.Sprintf("[gray::]%d", 0), 0, 1, true, false).
 		AddTextView("", "", 0, 1, true, false).
 		AddTextView("Available balance:", fmt.Sprintf("[gray::]%s", confirmedBalanceView), 0, 1, true, false).
@@ -52,6 +110,7 @@ func (w *Wallet) showTransfertView() {
 
 	var nextHandler func()
 	var nextButton, cancelButton *tview.Button
+	spinner := components.NewSpinner(w.load.Application, "")
 
 	form.AddButton("Cancel", func() {
 		w.closeModal()
@@ -70,10 +129,9 @@ func (w *Wallet) showTransfertView() {
 	}
 
 	disableInputs := func(disable bool) {
-		if item, ok := form.GetFormItem(0).(*tview.TextArea); ok {
-			item.SetDisabled(disable)
-		}
-		if item, ok := form.GetFormItem(1).(*tview.InputField); ok {
+		addressField.SetDisabled(disable)
+		amountField.SetDisabled(disable)
+		if item, ok := form.GetFormItem(2).(*tview.DropDown); ok {
 			item.SetDisabled(disable)
 		}
 		if nextButton != nil {
@@ -87,18 +145,18 @@ func (w *Wallet) showTransfertView() {
 	nextHandler = func() {
 		w.load.Notif.CancelToast()
 
-		addressField := form.GetFormItem(0).(*tview.TextArea)
-		amountField := form.GetFormItem(1).(*tview.InputField)
-		feeField := form.GetFormItem(2).(*tview.TextView)
-		totalCostField := form.GetFormItem(5).(*tview.TextView)
-		newBalanceField := form.GetFormItem(6).(*tview.TextView)
+		feeField := form.GetFormItem(3).(*tview.TextView)
+		totalCostField := form.GetFormItem(6).(*tview.TextView)
+		newBalanceField := form.GetFormItem(7).(*tview.TextView)
 
-		address, amount, err := w.validateTransferFields(addressField.GetText(), amountField.GetText())
-		if err != nil {
-			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
-			w.load.Application.SetFocus(addressField)
+		if item, err := validator.Validate(); err != nil {
+			w.load.Application.SetFocus(item)
 			return
 		}
+		address, _ := chainutil.DecodeAddress(strings.TrimSpace(addressField.GetText()), w.load.AppConfig.Network)
+		amount, _ := amountField.Amount()
+		w.svCache.address = address
+		w.svCache.amount = amount
 
 		w.mu.Lock()
 		if w.svCache.isPreparing {
@@ -112,13 +170,13 @@ func (w *Wallet) showTransfertView() {
 		if nextButton != nil {
 			nextButton.SetLabel("Please wait...")
 		}
-		w.load.Notif.ShowToast("⏳ preparing transaction...")
+		spinner.SetLabel("Preparing transaction…").Start()
 
 		go func(addr chainutil.Address, amt chainutil.Amount, dstAddress string) {
 			err := w.prepareTransfer(addr, amt)
 
 			w.load.Application.QueueUpdateDraw(func() {
-				w.load.Notif.CancelToast()
+				spinner.SetLabel("").Stop()
 
 				w.mu.Lock()
 				w.svCache.isPreparing = false
@@ -146,24 +204,47 @@ func (w *Wallet) showTransfertView() {
 		}(address, amount, addressField.GetText())
 	}
 
-	view := tview.NewFlex()
+	view := tview.NewFlex().SetDirection(tview.FlexRow)
 	view.SetTitle("Send").
-		SetTitleColor(tcell.ColorGray).
-		SetBackgroundColor(tcell.ColorOrange).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
 		SetBorder(true)
 
-	view.AddItem(form, 0, 1, true)
+	view.AddItem(form, 0, 1, true).
+		AddItem(spinner, 1, 0, false)
+
+	w.nav.ShowModal(components.NewModal(view, 50, 27, w.closeModal))
 
-	w.nav.ShowModal(components.NewModal(view, 50, 22, w.closeModal))
+	ctx := w.nav.ModalContext()
+	go func() {
+		rates, err := w.load.Wallet.FeeEstimates(ctx, flnd.FeeTargets...)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			w.load.Logger.Warn().Err(err).Msg("fee estimates incomplete")
+		}
+		w.load.Application.QueueUpdateDraw(func() {
+			speed, ok := form.GetFormItem(2).(*tview.DropDown)
+			if !ok {
+				return
+			}
+			current, _ := speed.GetCurrentOption()
+			speed.SetOptions(feeSpeedOptions(rates, true), nil)
+			speed.SetCurrentOption(current)
+			speed.SetSelectedFunc(onSpeed)
+		})
+	}()
 }
 
 func (w *Wallet) prepareTransfer(address chainutil.Address, amount chainutil.Amount) error {
 	w.mu.Lock()
 	w.svCache.finalTx = nil
 	w.svCache.locks = nil
+	confTarget := w.svCache.confTarget
 	w.mu.Unlock()
 
-	feeResp, err := w.load.Wallet.Fee(address, amount)
+	feeResp, err := w.load.Wallet.Fee(context.Background(), address, amount, confTarget)
 	if err != nil {
 		return err
 	}
@@ -176,14 +257,14 @@ func (w *Wallet) prepareTransfer(address chainutil.Address, amount chainutil.Amo
 		address.String(): int64(amount),
 	}
 
-	funded, err := w.load.Wallet.FundPsbt(entry, feeResp.SatPerVbyte, DefaultLockExpirationSeconds)
+	funded, err := w.load.Wallet.FundPsbt(context.Background(), entry, feeResp.SatPerVbyte, DefaultLockExpirationSeconds)
 	if err != nil {
 		return err
 	}
 
-	finalTx, err := w.load.Wallet.FinalizePsbt(funded.Packet)
+	finalTx, err := w.load.Wallet.FinalizePsbt(context.Background(), funded.Packet)
 	if err != nil {
-		if err := w.load.Wallet.ReleaseOutputs(funded.Locks); err != nil {
+		if err := w.load.Wallet.ReleaseOutputs(context.Background(), funded.Locks); err != nil {
 			w.load.Logger.Warn().Err(err).Msg("failed to release outputs after finalize failure")
 		}
 		w.load.BroadcastBalanceRefresh()
@@ -212,7 +293,7 @@ func (w *Wallet) showTransferConfirmation(address string, amount chainutil.Amoun
 	recap.SetBorderPadding(1, 2, 2, 2)
 	fmt.Fprintf(recap, "\n")
 	fmt.Fprintf(recap, " Destination Address:\n [gray::]%s[-::]\n\n", address)
-	fmt.Fprintf(recap, " Amount:\n [gray::]%s[-::]\n\n", shared.FormatAmountView(amount, 6))
+	fmt.Fprintf(recap, " Amount:\n [gray::]%s %s[-::]\n\n", shared.FormatAmountView(amount, 6), w.load.FiatView(amount))
 	recap.SetBackgroundColor(tcell.ColorDefault)
 
 	cForm := tview.NewForm()
@@ -221,8 +302,18 @@ func (w *Wallet) showTransferConfirmation(address string, amount chainutil.Amoun
 	cForm.AddTextView("Available balance:", fmt.Sprintf("[gray::]%s", shared.FormatAmountView(w.confirmedBalance(), 6)), 0, 1, true, false).
 		AddTextView("Fee:", fmt.Sprintf("[gray::]%s", shared.FormatAmountView(w.svCache.fee, 6)), 0, 1, true, false).
 		AddTextView("Total cost:", totalCostText, 0, 1, true, false).
-		AddTextView("Balance After send:", newBalanceText, 0, 1, true, false).
-		AddButton("Cancel", w.closeModal).
+		AddTextView("Balance After send:", newBalanceText, 0, 1, true, false)
+
+	// Large sends are held until the amount is typed a second time.
+	var retypeField *components.AmountField
+	modalHeight := 22
+	if w.isLargeSend(amount) {
+		retypeField = components.NewAmountField("Retype amount")
+		cForm.AddFormItem(retypeField)
+		modalHeight += 2
+	}
+
+	cForm.AddButton("Cancel", w.closeModal).
 		AddButton("Send", func() {
 			sendIdx := cForm.GetButtonIndex("Send")
 
@@ -231,12 +322,21 @@ func (w *Wallet) showTransferConfirmation(address string, amount chainutil.Amoun
 				sendBtn = cForm.GetButton(sendIdx)
 			}
 
+			if retypeField != nil {
+				if err := confirmRetypedAmount(retypeField, amount); err != nil {
+					w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
+					w.load.Application.SetFocus(retypeField)
+					return
+				}
+			}
+
 			w.mu.Lock()
 			if w.svCache.isSending {
 				w.mu.Unlock()
 				return
 			}
 			tx := w.svCache.finalTx
+			fee := w.svCache.fee
 			w.svCache.isSending = true
 			w.mu.Unlock()
 
@@ -258,9 +358,9 @@ func (w *Wallet) showTransferConfirmation(address string, amount chainutil.Amoun
 			}
 
 			go func(tx *chainutil.Tx) {
-				w.load.Notif.ShowToastWithTimeout("⚡ publishing...", time.Second*60)
+				publishing := w.load.Notif.ShowToastWithTimeout("⚡ publishing...", time.Second*60)
 
-				err := w.load.Wallet.PublishTransaction(tx)
+				err := w.load.Wallet.PublishTransaction(context.Background(), tx)
 				hash := tx.Hash()
 				var txHash string
 				if hash != nil {
@@ -271,6 +371,7 @@ func (w *Wallet) showTransferConfirmation(address string, amount chainutil.Amoun
 				}
 
 				w.load.Application.QueueUpdateDraw(func() {
+					publishing.Dismiss()
 					w.mu.Lock()
 					w.svCache.isSending = false
 					if err == nil {
@@ -283,7 +384,8 @@ func (w *Wallet) showTransferConfirmation(address string, amount chainutil.Amoun
 							sendBtn.SetDisabled(false)
 							sendBtn.SetLabel("Send")
 						}
-						w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
+						// Over the confirmation, which stays open to retry.
+						w.nav.PushModal(components.ErrorModal(fmt.Sprintf("Sending failed: %s", err.Error()), w.nav.CloseModal))
 						return
 					}
 
@@ -295,6 +397,13 @@ func (w *Wallet) showTransferConfirmation(address string, amount chainutil.Amoun
 					w.load.Logger.Info().
 						Str("tx_hash", txHash).
 						Msg("Transaction published, waiting for confirmation")
+					w.load.Journal.Record(load.JournalEntry{
+						Kind:    load.JournalSend,
+						Txid:    txHash,
+						Address: address,
+						Amount:  -int64(amount),
+						Fee:     int64(fee),
+					})
 					w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("✅ Transaction Sent! Waiting for confirmation… (%s)", shortHash), time.Second*60)
 					w.load.Notif.BroadcastWalletUpdate(&load.NotificationEvent{State: flnd.StatusTransaction})
 					w.nav.CloseModal()
@@ -303,19 +412,48 @@ func (w *Wallet) showTransferConfirmation(address string, amount chainutil.Amoun
 		})
 
 	cView := tview.NewFlex().SetDirection(tview.FlexRow)
-	cView.SetTitle("Confirm Send").SetTitleColor(tcell.ColorGray).SetBackgroundColor(tcell.ColorOrange).SetBorder(true)
+	cView.SetTitle("Confirm Send").SetTitleColor(shared.CurrentTheme().ModalTitle).SetBackgroundColor(shared.CurrentTheme().Modal).SetBorder(true)
 
 	cView.AddItem(recap, 9, 1, false).
 		AddItem(cForm, 0, 1, true)
 
-	w.nav.ShowModal(components.NewModal(cView, 50, 22, w.closeModal))
+	w.nav.ShowModal(components.NewModal(cView, 50, modalHeight, w.closeModal))
+	if retypeField != nil {
+		w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[yellow:-:-]Large send:[-:-:-] type the amount again to confirm %s", shared.FormatAmountView(amount, 6)), time.Second*30)
+	}
+}
+
+// isLargeSend reports whether amount reaches the configured largesend
+// threshold.
+func (w *Wallet) isLargeSend(amount chainutil.Amount) bool {
+	threshold, err := chainutil.NewAmount(w.load.AppConfig.LargeSend)
+	if err != nil || threshold <= 0 {
+		return false
+	}
+	return amount >= threshold
+}
+
+// confirmRetypedAmount checks the amount typed again for a large send.
+func confirmRetypedAmount(field *components.AmountField, amount chainutil.Amount) error {
+	if strings.TrimSpace(field.GetText()) == "" {
+		return errors.New("retype the amount to confirm this send")
+	}
+	retyped, err := field.Amount()
+	if err != nil {
+		return err
+	}
+	if retyped != amount {
+		return errors.New("retyped amount does not match")
+	}
+	return nil
 }
 
 func (w *Wallet) showReceiveView() {
 
 	w.load.Notif.CancelToast()
 
-	address, err := w.load.Wallet.GetNextAddress(w.load.AppConfig.UnusedAddressType)
+	usedType := w.load.AppConfig.UsedAddressType
+	address, err := w.load.Wallet.GetNextAddress(context.Background(), w.load.AppConfig.UnusedAddressType)
 	if err != nil {
 		w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
 		return
@@ -332,133 +470,164 @@ func (w *Wallet) showReceiveView() {
 	}
 
 	label := tview.NewTextView()
-	label.SetDynamicColors(true).
-		SetText(fmt.Sprintf("[gray::-]Address:[-:-:-] \n%s", strAddress))
-	label.SetBackgroundColor(tcell.ColorDefault).SetBorderPadding(1, 2, 2, 2)
+	label.SetDynamicColors(true).SetWordWrap(true).
+		SetText(fmt.Sprintf("[gray::-]Address:[-:-:-]\n%s", shared.AddressVerifyView(strAddress)))
+	label.SetBackgroundColor(tcell.ColorDefault).SetBorderPadding(1, 1, 2, 2)
 
 	qrText := tview.NewTextView().SetWrap(true).SetWordWrap(true)
 	qrText.SetBackgroundColor(tcell.ColorDefault)
 	qrText.SetText(qrtxt).
 		SetTextAlign(tview.AlignCenter)
 
-	cpyBtn := components.NewConfirmButton(w.nav.Application, "copy", true, tcell.ColorDefault, 3, func() {
-		w.load.Notif.CancelToast()
-		if err := shared.ClipboardCopy(strAddress); err != nil {
+	showAddress := func(address chainutil.Address) {
+		newAddress := address.String()
+		newQR, err := shared.GenerateQRText(newAddress)
+		if err != nil {
 			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
 			return
 		}
-		shortAddr := strAddress
-		if len(shortAddr) > 14 {
-			shortAddr = fmt.Sprintf("%s...%s", strAddress[:6], strAddress[len(strAddress)-6:])
+		strAddress, qrtxt = newAddress, newQR
+		w.load.Logger.Trace().Str("address", strAddress).Msg("address requested")
+		go func() {
+			w.load.Application.QueueUpdateDraw(func() {
+				label.SetText(fmt.Sprintf("[gray::-]Address:[-:-:-]\n%s", shared.AddressVerifyView(strAddress)))
+				qrText.SetText(qrtxt)
+			})
+		}()
+	}
+
+	typeDropDown := tview.NewDropDown().
+		SetLabel("Address type: ").
+		SetOptions(receiveAddressTypes, nil)
+	typeDropDown.SetBackgroundColor(tcell.ColorDefault).SetBorderPadding(1, 0, 2, 2)
+	for i, name := range receiveAddressTypes {
+		if name == w.load.AppConfig.AddressType {
+			typeDropDown.SetCurrentOption(i)
+		}
+	}
+	// The type of the address shown, the one Next Address keeps to.
+	shownType := w.load.AppConfig.AddressType
+	typeDropDown.SetSelectedFunc(func(text string, _ int) {
+		if text == shownType {
+			return
+		}
+		used, unused, err := utils.GetAddressTypesFromName(text)
+		if err != nil {
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
+			return
 		}
-		w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("📋 Copied %s", shortAddr), time.Second*10)
-	})
-	nextAddrBtn := components.NewConfirmButton(w.nav.Application, "Next Address", true, tcell.ColorDefault, 3, func() {
-		w.load.Notif.CancelToast()
-		address, err := w.load.Wallet.GetNextAddress(w.load.AppConfig.UsedAddressType)
+		address, err := w.load.Wallet.GetNextAddress(context.Background(), unused)
 		if err != nil {
 			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
 			return
 		}
-		strAddress = address.String()
-		qrtxt, err = shared.GenerateQRText(strAddress)
+		shownType, usedType = text, used
+		showAddress(address)
+	})
+
+	cpyBtn := components.NewConfirmButton(w.nav.Application, "copy", true, tcell.ColorDefault, 3, func() {
+		w.load.Notif.CancelToast()
+		components.CopyText(w.load, strAddress, shortAddress(strAddress))
+	})
+	nextAddrBtn := components.NewConfirmButton(w.nav.Application, "Next Address", true, tcell.ColorDefault, 3, func() {
+		w.load.Notif.CancelToast()
+		address, err := w.load.Wallet.GetNextAddress(context.Background(), usedType)
 		if err != nil {
 			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
 			return
 		}
-		go func() {
-			w.load.Application.QueueUpdateDraw(func() {
-				label.SetText(fmt.Sprintf("[gray::-]Address:[-:-:-] \n%s", strAddress))
-				qrText.SetText(qrtxt)
-			})
-		}()
+		showAddress(address)
+	})
+
+	saveBtn := components.NewConfirmButton(w.nav.Application, "Save PNG", true, tcell.ColorDefault, 3, func() {
+		w.load.Notif.CancelToast()
+		w.showSaveQRView(strAddress)
 	})
 
 	buttons := tview.NewFlex()
 	buttons.Box = tview.NewBox().SetBackgroundColor(tcell.ColorDefault).SetBorderPadding(0, 0, 2, 2)
-	buttons.AddItem(cpyBtn, 0, 1, true).
-		AddItem(nextAddrBtn, 0, 1, false)
+	buttons.AddItem(cpyBtn, 0, 1, false).
+		AddItem(nextAddrBtn, 0, 1, false).
+		AddItem(saveBtn, 0, 1, false)
 
 	view := tview.NewFlex().SetDirection(tview.FlexRow)
 	view.SetTitle("Receive").
-		SetTitleColor(tcell.ColorGray).
-		SetBackgroundColor(tcell.ColorOrange).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
 		SetBorder(true)
 
-	var expTaprootSize int
-	if utils.IsTaprootAddressType(w.load.AppConfig.UnusedAddressType) {
-		expTaprootSize = 2
-	}
-
-	view.AddItem(label, 5+expTaprootSize, 0, false).
-		AddItem(qrText, 19+expTaprootSize, 1, false).
-		AddItem(buttons, 5, 1, true)
+	// Leave room for the longer taproot addresses whatever type is picked
+	// so switching types does not need a resize.
+	view.AddItem(typeDropDown, 2, 0, true).
+		AddItem(label, 8, 0, false).
+		AddItem(qrText, 21, 1, false).
+		AddItem(buttons, 5, 1, false)
 
-	w.nav.ShowModal(components.NewModal(view, 50, 31+expTaprootSize+expTaprootSize, w.nav.CloseModal))
+	w.nav.ShowModal(components.NewModal(view, 50, 38, w.nav.CloseModal))
 }
 
-func (w *Wallet) validateTransferFields(strAddress string, strAmount string) (chainutil.Address, chainutil.Amount, error) {
-
-	address, err := chainutil.DecodeAddress(strAddress, w.load.AppConfig.Network)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid address")
-	}
-
-	amountNum, err := strconv.ParseFloat(strAmount, 64)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid amount")
-	}
-
-	if amountNum <= 0 {
-		return nil, 0, fmt.Errorf("invalid amount")
+// showSaveQRView asks, over the receive view, where to write the QR of
+// address as a PNG image.
+func (w *Wallet) showSaveQRView(address string) {
+	defaultPath := fmt.Sprintf("~/flokicoin-%s.png", address)
+	if len(address) > 8 {
+		defaultPath = fmt.Sprintf("~/flokicoin-%s.png", address[len(address)-8:])
 	}
 
-	amount, err := chainutil.NewAmount(amountNum)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid amount")
-	}
-
-	w.svCache.address = address
-	w.svCache.amount = amount
-
-	return address, amount, nil
+	w.showFilePicker("Save QR as PNG", components.FileSave, defaultPath, []string{".png"}, func(path string) error {
+		path, err := shared.SaveQRImage(address, path)
+		if err != nil {
+			return err
+		}
+		w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("🖼 QR saved to %s", path), time.Second*15)
+		return nil
+	})
 }
 
+// receiveAddressTypes are the address types offered by the receive view,
+// named as the addresstype option.
+var receiveAddressTypes = []string{"segwit", "nested-segwit", "taproot"}
+
 func (w *Wallet) confirmedBalance() chainutil.Amount {
 	balance, _, _ := w.load.GetBalance()
 	return balance
 }
 
 func (w *Wallet) transferAmountChanged(form *tview.Form) {
-	if form.GetFormItemCount() < 6 {
+	if form.GetFormItemCount() < 8 {
 		return
 	}
 
-	addressField, ok := form.GetFormItem(0).(*tview.TextArea)
+	addressField, ok := components.Unwrap(form.GetFormItem(0)).(*tview.TextArea)
+	if !ok {
+		return
+	}
+	amountField, ok := components.Unwrap(form.GetFormItem(1)).(*components.AmountField)
 	if !ok {
 		return
 	}
-	amountField, ok := form.GetFormItem(1).(*tview.InputField)
+	feeField, ok := form.GetFormItem(3).(*tview.TextView)
 	if !ok {
 		return
 	}
-	feeField, ok := form.GetFormItem(2).(*tview.TextView)
+	fiatField, ok := form.GetFormItem(4).(*tview.TextView)
 	if !ok {
 		return
 	}
-	totalCostField, ok := form.GetFormItem(5).(*tview.TextView)
+	totalCostField, ok := form.GetFormItem(6).(*tview.TextView)
 	if !ok {
 		return
 	}
-	newBalanceField, ok := form.GetFormItem(6).(*tview.TextView)
+	newBalanceField, ok := form.GetFormItem(7).(*tview.TextView)
 	if !ok {
 		return
 	}
 
 	resetFields := func() {
+		fiatField.SetText("")
 		feeField.SetText(fmt.Sprintf("[gray::]%s", shared.FormatAmountView(0, 6)))
 		totalCostField.SetText(fmt.Sprintf("[gray::]%s", shared.FormatAmountView(0, 6)))
-		newBalanceField.SetText(fmt.Sprintf("[gray::]%s", w.confirmedBalance()))
+		newBalanceField.SetText(fmt.Sprintf("[gray::]%s", shared.FormatAmountView(w.confirmedBalance(), 6)))
 	}
 
 	address, err := chainutil.DecodeAddress(addressField.GetText(), w.load.AppConfig.Network)
@@ -467,18 +636,14 @@ func (w *Wallet) transferAmountChanged(form *tview.Form) {
 		return
 	}
 
-	baseAmount, err := strconv.ParseFloat(amountField.GetText(), 64)
-	if err != nil {
-		resetFields()
-		return
-	}
-	amount, err := chainutil.NewAmount(baseAmount)
+	amount, err := amountField.Amount()
 	if err != nil {
 		resetFields()
 		return
 	}
 
 	w.load.Notif.CancelToast()
+	fiatField.SetText(fmt.Sprintf("[gray::]%s", w.load.FiatView(amount)))
 
 	w.mu.Lock()
 	w.svCache.address = address
@@ -489,6 +654,7 @@ func (w *Wallet) transferAmountChanged(form *tview.Form) {
 	w.svCache.balanceAfter = 0
 	w.svCache.feeCalcID++
 	reqID := w.svCache.feeCalcID
+	confTarget := w.svCache.confTarget
 	w.svCache.finalTx = nil
 	w.mu.Unlock()
 
@@ -497,8 +663,12 @@ func (w *Wallet) transferAmountChanged(form *tview.Form) {
 	totalCostField.SetText(placeholder)
 	newBalanceField.SetText(placeholder)
 
+	ctx := w.nav.ModalContext()
 	go func(id uint64, addr chainutil.Address, amt chainutil.Amount) {
-		feeResp, feeErr := w.load.Wallet.Fee(addr, amt)
+		feeResp, feeErr := w.load.Wallet.Fee(ctx, addr, amt, confTarget)
+		if ctx.Err() != nil {
+			return
+		}
 
 		var (
 			txFee      chainutil.Amount
@@ -564,7 +734,7 @@ func (w *Wallet) releasePreparedOutputs() {
 	w.mu.Unlock()
 
 	go func() {
-		if err := w.load.Wallet.ReleaseOutputs(locks); err != nil {
+		if err := w.load.Wallet.ReleaseOutputs(context.Background(), locks); err != nil {
 			w.load.Logger.Warn().Err(err).Msg("failed to release prepared outputs")
 
 			w.mu.Lock()
@@ -0,0 +1,452 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/go-flokicoin/chainutil"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/load"
+	"github.com/flokiorg/twallet/shared"
+)
+
+// bumpFeeConfTarget is the confirmation target of the fee rate suggested
+// when bumping a transaction.
+const bumpFeeConfTarget = 2
+
+// defaultAlertConfirmations is offered when setting a confirmation alert.
+const defaultAlertConfirmations = 6
+
+// transactionAt returns the transaction shown on the given data row of the
+// transactions table, in display order.
+func (w *Wallet) transactionAt(index int) *lnrpc.Transaction {
+	w.stateMu.Lock()
+	placeholder := w.placeholder
+	w.stateMu.Unlock()
+	if placeholder != "" {
+		return nil
+	}
+
+	w.txMu.Lock()
+	defer w.txMu.Unlock()
+	if index < 0 || index >= len(w.txRows) {
+		return nil
+	}
+	return w.txRows[index]
+}
+
+// markedTransactions returns the transactions marked on the table.
+func (w *Wallet) markedTransactions() []*lnrpc.Transaction {
+	var txs []*lnrpc.Transaction
+	for _, row := range w.table.MarkedRows() {
+		if tx := w.transactionAt(row); tx != nil {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
+}
+
+func (w *Wallet) showSelectedTransactionMenu() {
+	row, _ := w.table.GetSelection()
+	w.showTransactionMenu(row - 1)
+}
+
+// showTransactionMenu gathers the actions available on a transaction row.
+func (w *Wallet) showTransactionMenu(index int) {
+	tx := w.transactionAt(index)
+	if tx == nil {
+		return
+	}
+
+	w.load.Notif.CancelToast()
+
+	address := transactionAddress(tx)
+	explorerURL := w.explorerURL(tx.TxHash)
+	_, bumpable := bumpOutput(tx)
+
+	items := []components.MenuItem{
+		{Label: "Copy txid", Shortcut: 't', Action: func() {
+			w.closeModal()
+			components.CopyText(w.load, tx.TxHash, "txid "+shortTxID(tx.TxHash))
+		}},
+		{Label: "Copy address", Shortcut: 'a', Disabled: address == "", Action: func() {
+			w.closeModal()
+			components.CopyText(w.load, address, shortAddress(address))
+		}},
+		{Label: "View details", Shortcut: 'd', Action: func() {
+			w.showTransactionDetails(tx)
+		}},
+		{Label: "Open in explorer", Shortcut: 'e', Disabled: explorerURL == "", Action: func() {
+			w.closeModal()
+			if err := shared.OpenURL(explorerURL); err != nil {
+				w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*10)
+				return
+			}
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("🌐 Opened %s", explorerURL), time.Second*10)
+		}},
+		{Label: "Bump fee", Shortcut: 'b', Disabled: tx.BlockHeight > 0 || !bumpable, Action: func() {
+			w.promptBumpFee(tx)
+		}},
+		{Label: "Add label", Shortcut: 'l', Action: func() {
+			w.promptTransactionLabel(tx)
+		}},
+	}
+	if marked := w.markedTransactions(); len(marked) > 0 {
+		items = append(items, components.MenuItem{
+			Label: fmt.Sprintf("Label %d marked", len(marked)), Shortcut: 'k', Action: func() {
+				w.promptTransactionLabel(marked...)
+			}})
+	}
+
+	w.nav.ShowModal(components.NewMenu(fmt.Sprintf("Transaction %s", shortTxID(tx.TxHash)), items, w.closeModal))
+}
+
+// explorerURL returns the block explorer page of txid, or an empty string when
+// no explorer is configured.
+func (w *Wallet) explorerURL(txid string) string {
+	base := strings.TrimSpace(w.load.AppConfig.ExplorerURL)
+	if base == "" {
+		return ""
+	}
+	if strings.Contains(base, "%s") {
+		return strings.ReplaceAll(base, "%s", txid)
+	}
+	return strings.TrimRight(base, "/") + "/" + txid
+}
+
+// transactionAddress picks the address a transaction is about: the
+// counterparty for a send, the wallet address for a receive.
+func transactionAddress(tx *lnrpc.Transaction) string {
+	outgoing := tx.Amount < 0
+	for _, detail := range tx.GetOutputDetails() {
+		if detail != nil && detail.Address != "" && detail.IsOurAddress != outgoing {
+			return detail.Address
+		}
+	}
+	if outputs := tx.GetOutputDetails(); len(outputs) > 0 && outputs[0] != nil {
+		return outputs[0].Address
+	}
+	return ""
+}
+
+// bumpOutput returns the wallet output the sweeper can use to speed up tx.
+func bumpOutput(tx *lnrpc.Transaction) (uint32, bool) {
+	for _, detail := range tx.GetOutputDetails() {
+		if detail != nil && detail.IsOurAddress {
+			return uint32(detail.OutputIndex), true
+		}
+	}
+	return 0, false
+}
+
+func (w *Wallet) showTransactionDetails(tx *lnrpc.Transaction) {
+	tipHeight := w.load.Cache.GetTipHeight()
+
+	amount := chainutil.Amount(tx.Amount)
+	amountColor := "green"
+	if amount < 0 {
+		amountColor = "red"
+	}
+
+	label := tx.Label
+	if label == "" {
+		label = "-"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[gray::]Txid:[-:-:-]\n%s\n\n", tx.TxHash)
+	fmt.Fprintf(&b, "[gray::]Time:[-:-:-] %s\n", shared.FormatUnixTime(tx.TimeStamp))
+	fmt.Fprintf(&b, "[gray::]Amount:[-:-:-] [%s:-:-]%s[-:-:-]\n", amountColor, shared.FormatAmountView(amount, 8))
+	if fiat := w.load.FiatView(amount); fiat != "" {
+		fmt.Fprintf(&b, "[gray::]Value:[-:-:-] %s\n", fiat)
+	}
+	fmt.Fprintf(&b, "[gray::]Fee:[-:-:-] %s\n", shared.FormatAmountView(chainutil.Amount(tx.TotalFees), 8))
+	fmt.Fprintf(&b, "[gray::]Confirmations:[-:-:-] %d", txConfirmations(tx, tipHeight))
+	if w.load.Cache.ConfirmationSuspect(tx.BlockHeight) {
+		fmt.Fprintf(&b, " [yellow::](suspect after a chain reorganization)[-:-:-]")
+	}
+	fmt.Fprintln(&b)
+	if target, ok := w.load.Confirm.Target(tx.TxHash); ok {
+		fmt.Fprintf(&b, "[gray::]Alert:[-:-:-] at %d confirmations\n", target)
+	}
+	if tx.BlockHeight > 0 {
+		fmt.Fprintf(&b, "[gray::]Block:[-:-:-] %d %s\n", tx.BlockHeight, tx.BlockHash)
+	}
+	fmt.Fprintf(&b, "[gray::]Label:[-:-:-] %s\n", tview.Escape(label))
+
+	fmt.Fprintf(&b, "\n[gray::]Outputs:[-:-:-]\n")
+	for _, detail := range tx.GetOutputDetails() {
+		if detail == nil {
+			continue
+		}
+		owner := ""
+		if detail.IsOurAddress {
+			owner = " [green:-:-](wallet)[-:-:-]"
+		}
+		fmt.Fprintf(&b, " #%d %s %s%s\n", detail.OutputIndex, detail.Address,
+			shared.FormatAmountView(chainutil.Amount(detail.Amount), 8), owner)
+	}
+
+	if prevs := tx.GetPreviousOutpoints(); len(prevs) > 0 {
+		fmt.Fprintf(&b, "\n[gray::]Inputs:[-:-:-]\n")
+		for _, prev := range prevs {
+			if prev == nil {
+				continue
+			}
+			owner := ""
+			if prev.IsOurOutput {
+				owner = " [green:-:-](wallet)[-:-:-]"
+			}
+			fmt.Fprintf(&b, " %s%s\n", prev.Outpoint, owner)
+		}
+	}
+
+	details := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true).
+		SetText(b.String())
+	details.SetBackgroundColor(tcell.ColorDefault).SetBorderPadding(1, 1, 2, 2)
+	details.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		switch event.Rune() {
+		case 'c', 'C':
+			components.CopyText(w.load, tx.TxHash, "txid "+shortTxID(tx.TxHash))
+			return nil
+		case 'n', 'N':
+			w.promptConfirmationAlert(tx)
+			return nil
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText("[gray::]<c> copy txid · <n> alert at N confirmations · <esc> close")
+	hint.SetBackgroundColor(tcell.ColorDefault)
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow)
+	view.SetTitle("Transaction").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	view.AddItem(details, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	w.nav.ShowModal(components.NewModal(view, 96, 30, w.closeModal))
+	w.load.Application.SetFocus(details)
+}
+
+func (w *Wallet) promptBumpFee(tx *lnrpc.Transaction) {
+	outputIndex, ok := bumpOutput(tx)
+	if !ok {
+		return
+	}
+
+	form := tview.NewForm()
+	form.SetBackgroundColor(tcell.ColorDefault).
+		SetBorderPadding(1, 1, 2, 2)
+	form.AddInputField("Fee rate (loki/vB):", "", 0, tview.InputFieldInteger, nil)
+	rateField := form.GetFormItem(0).(*tview.InputField)
+	rateField.SetPlaceholder("fetching estimate...")
+
+	busy := false
+	form.AddButton("Cancel", w.closeModal)
+	form.AddButton("Bump", func() {
+		if busy {
+			return
+		}
+		rate, err := strconv.ParseUint(strings.TrimSpace(rateField.GetText()), 10, 64)
+		if err != nil || rate == 0 {
+			w.load.Notif.ShowToastWithTimeout("[red:-:-]Error:[-:-:-] enter a fee rate above zero", time.Second*10)
+			w.load.Application.SetFocus(rateField)
+			return
+		}
+
+		busy = true
+		bumping := w.load.Notif.ShowToast("⛽ bumping fee...")
+		go func() {
+			err := w.load.Wallet.BumpFee(context.Background(), tx.TxHash, outputIndex, rate)
+			w.load.Application.QueueUpdateDraw(func() {
+				bumping.Dismiss()
+				busy = false
+				if err != nil {
+					w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
+					return
+				}
+				w.load.Journal.Record(load.JournalEntry{
+					Kind:    load.JournalFeeBump,
+					Txid:    tx.TxHash,
+					Message: fmt.Sprintf("%d loki/vB", rate),
+				})
+				w.closeModal()
+				w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("⛽ Fee bump requested for %s at %d loki/vB", shortTxID(tx.TxHash), rate), time.Second*15)
+				go w.updateRows()
+			})
+		}()
+	})
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow)
+	view.SetTitle(fmt.Sprintf("Bump fee %s", shortTxID(tx.TxHash))).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	view.AddItem(form, 0, 1, true)
+
+	w.nav.ShowModal(components.NewModal(view, 50, 9, w.closeModal))
+	w.load.Application.SetFocus(rateField)
+
+	ctx := w.nav.ModalContext()
+	go func() {
+		rate, err := w.load.Wallet.FeeRate(ctx, bumpFeeConfTarget)
+		if ctx.Err() != nil {
+			return
+		}
+		w.load.Application.QueueUpdateDraw(func() {
+			rateField.SetPlaceholder("")
+			if err == nil && rateField.GetText() == "" {
+				rateField.SetText(strconv.FormatFloat(math.Ceil(rate), 'f', 0, 64))
+			}
+		})
+	}()
+}
+
+// promptTransactionLabel labels txs, the marked transactions when there are
+// several.
+func (w *Wallet) promptTransactionLabel(txs ...*lnrpc.Transaction) {
+	if len(txs) == 0 {
+		return
+	}
+	title := fmt.Sprintf("Label %s", shortTxID(txs[0].TxHash))
+	if len(txs) > 1 {
+		title = fmt.Sprintf("Label %d transactions", len(txs))
+	}
+
+	form := tview.NewForm()
+	form.SetBackgroundColor(tcell.ColorDefault).
+		SetBorderPadding(1, 1, 2, 2)
+	form.AddInputField("Label:", txs[0].Label, 0, nil, nil)
+	labelField := form.GetFormItem(0).(*tview.InputField)
+
+	busy := false
+	form.AddButton("Cancel", w.closeModal)
+	form.AddButton("Save", func() {
+		if busy {
+			return
+		}
+		label := strings.TrimSpace(labelField.GetText())
+		if label == "" {
+			w.load.Notif.ShowToastWithTimeout("[red:-:-]Error:[-:-:-] label cannot be empty", time.Second*10)
+			w.load.Application.SetFocus(labelField)
+			return
+		}
+
+		busy = true
+		go func() {
+			var err error
+			labeled := 0
+			for _, tx := range txs {
+				if err = w.load.Wallet.LabelTransaction(context.Background(), tx.TxHash, label); err != nil {
+					err = fmt.Errorf("%s: %w", shortTxID(tx.TxHash), err)
+					break
+				}
+				labeled++
+			}
+			w.load.Application.QueueUpdateDraw(func() {
+				busy = false
+				if err != nil {
+					if labeled > 0 {
+						err = fmt.Errorf("%w, %d of %d labeled", err, labeled, len(txs))
+					}
+					w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*30)
+					return
+				}
+				w.closeModal()
+				if len(txs) > 1 {
+					w.table.MarkAll(false)
+					w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("🏷 Labeled %d transactions", len(txs)), time.Second*10)
+				} else {
+					w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("🏷 Labeled %s", shortTxID(txs[0].TxHash)), time.Second*10)
+				}
+				go w.updateRows()
+			})
+		}()
+	})
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow)
+	view.SetTitle(title).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	view.AddItem(form, 0, 1, true)
+
+	w.nav.ShowModal(components.NewModal(view, 60, 9, w.closeModal))
+	w.load.Application.SetFocus(labelField)
+}
+
+// promptConfirmationAlert asks how many confirmations to wait for before
+// notifying about tx.
+func (w *Wallet) promptConfirmationAlert(tx *lnrpc.Transaction) {
+	target := uint32(defaultAlertConfirmations)
+	if current, ok := w.load.Confirm.Target(tx.TxHash); ok {
+		target = current
+	}
+
+	form := tview.NewForm()
+	form.SetBackgroundColor(tcell.ColorDefault).
+		SetBorderPadding(1, 1, 2, 2)
+	form.AddInputField("Confirmations:", strconv.FormatUint(uint64(target), 10), 0, tview.InputFieldInteger, nil)
+	targetField := form.GetFormItem(0).(*tview.InputField)
+
+	form.AddButton("Cancel", func() { w.showTransactionDetails(tx) })
+	if _, ok := w.load.Confirm.Target(tx.TxHash); ok {
+		form.AddButton("Remove", func() {
+			w.load.Confirm.Cancel(tx.TxHash)
+			w.closeModal()
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("🔕 Alert removed for %s", shortTxID(tx.TxHash)), time.Second*10)
+		})
+	}
+	form.AddButton("Set", func() {
+		value, err := strconv.ParseUint(strings.TrimSpace(targetField.GetText()), 10, 32)
+		if err != nil || value == 0 {
+			w.load.Notif.ShowToastWithTimeout("[red:-:-]Error:[-:-:-] enter a number of confirmations above zero", time.Second*10)
+			w.load.Application.SetFocus(targetField)
+			return
+		}
+		if confs := txConfirmations(tx, w.load.Cache.GetTipHeight()); confs >= int64(value) {
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[yellow:-:-]%s already has %d confirmations", shortTxID(tx.TxHash), confs), time.Second*10)
+			return
+		}
+		if err := w.load.Confirm.Add(tx, uint32(value)); err != nil {
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*10)
+			return
+		}
+		w.closeModal()
+		w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("🔔 Alert set for %s at %d confirmations", shortTxID(tx.TxHash), value), time.Second*10)
+	})
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow)
+	view.SetTitle(fmt.Sprintf("Alert %s", shortTxID(tx.TxHash))).
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBackgroundColor(shared.CurrentTheme().Modal).
+		SetBorder(true)
+	view.AddItem(form, 0, 1, true)
+
+	w.nav.ShowModal(components.NewModal(view, 54, 9, func() { w.showTransactionDetails(tx) }))
+	w.load.Application.SetFocus(targetField)
+}
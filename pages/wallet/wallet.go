@@ -5,11 +5,15 @@
 package wallet
 
 import (
+	"fmt"
+	"strconv"
 	"sync"
+	"time"
 	"unicode"
 
 	"github.com/rivo/tview"
 
+	"github.com/flokiorg/flnd/lnrpc"
 	"github.com/flokiorg/twallet/components"
 	"github.com/flokiorg/twallet/load"
 	"github.com/flokiorg/twallet/shared"
@@ -21,17 +25,27 @@ type walletView int
 const (
 	transactionsView walletView = iota
 	logsView
+	chartView
+	splitView
 )
 
 const (
 	transactionsPageName = "transactions"
 	logsPageName         = "logs"
+	chartPageName        = "chart"
+	splitPageName        = "split"
+
+	splitPercentOption = "layout.split"
+	// splitSaveInterval spaces the writes of the divider position to the
+	// config file while it is dragged.
+	splitSaveInterval = 2 * time.Second
 )
 
 type Wallet struct {
 	view     *tview.Pages
 	table    *components.Table
 	logView  *tview.TextView
+	split    *components.SplitPane
 	nav      *load.Navigator
 	load     *load.Load
 	viewMode walletView
@@ -40,8 +54,14 @@ type Wallet struct {
 	stateMu     sync.Mutex
 	logMu       sync.Mutex
 	txRetryMu   sync.Mutex
+	txMu        sync.Mutex
 	placeholder string
 
+	txs         []*lnrpc.Transaction
+	txRows      []*lnrpc.Transaction
+	txTipHeight int32
+	dateFilter  shared.DateRange
+
 	svCache          *sendViewModel
 	quit             chan struct{}
 	busy             bool
@@ -49,6 +69,9 @@ type Wallet struct {
 	nsub             <-chan *load.NotificationEvent
 	cancelN          func()
 	txRetryHandle    *txRetryHandle
+	blockRefresh     *shared.Throttle
+	syncRefresh      *shared.Throttle
+	splitSave        *shared.Throttle
 	quitOnce         sync.Once
 
 	logLines   []string
@@ -57,35 +80,67 @@ type Wallet struct {
 	logReady   bool
 	logMaxLine int
 	logStatus  string
-	onceReady  sync.Once
+	// The search and level filter of the log view, and the match shown.
+	logQuery     string
+	logFilter    logFilter
+	logMatch     int
+	logMatches   int
+	logCacheInfo string
+	// The log view stays where it is while paused, counting the lines
+	// that came since.
+	logPaused  bool
+	logPending int
+	// logSource is the log file shown, flnd.log at logPath by default.
+	logSource logSource
+	onceReady sync.Once
 }
 
-func NewPage(l *load.Load) tview.Primitive {
-
-	columns := []components.Column{
+func transactionColumns() []components.Column {
+	return []components.Column{
 		{
-			Name:  "Timestamp",
-			Align: tview.AlignLeft,
+			Name:     "Timestamp",
+			Align:    tview.AlignLeft,
+			Sortable: true,
+			SortDir:  components.Descending,
 		}, {
-			Name:  "Tx ID",
-			Align: tview.AlignLeft,
+			Name:        "Tx ID",
+			Align:       tview.AlignLeft,
+			Collapsible: true,
 		}, {
 			Name:  "Address",
 			Align: tview.AlignLeft,
 		}, {
-			Name:  "Amount",
-			Align: tview.AlignRight,
+			Name:     "Amount",
+			Align:    tview.AlignRight,
+			Sortable: true,
+			SortDir:  components.Descending,
+		}, {
+			Name:     "Fee",
+			Align:    tview.AlignRight,
+			Sortable: true,
+			SortDir:  components.Descending,
 		}, {
 			Name:     "Confirmations",
 			Align:    tview.AlignCenter,
+			Sortable: true,
 			IsSorted: true,
 			SortDir:  components.Ascending,
 		},
 	}
+}
+
+func NewPage(l *load.Load) tview.Primitive {
 
 	netColor := shared.NetworkColor(*l.AppConfig.Network)
 
-	table := components.NewTable("Transactions", columns, netColor, l.AppConfig.TransactionDisplayLimit)
+	table := components.NewTable("Transactions", transactionColumns(), netColor, l.AppConfig.TransactionDisplayLimit)
+	table.SetVisibleColumns(splitColumnKeys(l.AppConfig.TransactionColumns))
+	theme := shared.CurrentTheme()
+	table.AddCellRule("amount", components.Above(0), theme.Success).
+		AddCellRule("amount", components.Below(0), theme.Error).
+		AddCellRule("fee", components.Equals("-"), theme.Muted).
+		AddCellRule("confirmations", components.HasSuffix("?"), theme.Warning).
+		AddCellRule("confirmations", components.Below(6), theme.Warning)
 	table.SetBorder(true).
 		SetTitleAlign(tview.AlignCenter).
 		SetTitleColor(netColor).
@@ -93,7 +148,9 @@ func NewPage(l *load.Load) tview.Primitive {
 
 	logView := tview.NewTextView()
 	logView.SetWrap(false).
-		SetDynamicColors(false).
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetToggleHighlights(false).
 		SetScrollable(true).
 		SetBorder(true).
 		SetTitle(" Logs ").
@@ -101,9 +158,6 @@ func NewPage(l *load.Load) tview.Primitive {
 		SetTitleColor(netColor).
 		SetBorderColor(netColor)
 	logView.SetBorderPadding(1, 1, 2, 2)
-	logView.SetChangedFunc(func() {
-		logView.ScrollToEnd()
-	})
 
 	pages := tview.NewPages()
 	pages.AddPage(transactionsPageName, table, true, true)
@@ -122,7 +176,24 @@ func NewPage(l *load.Load) tview.Primitive {
 		logMaxLine: 2000,
 	}
 
+	w.blockRefresh = shared.NewThrottle(walletRefreshInterval, w.refreshTransactions)
+	w.syncRefresh = shared.NewThrottle(walletRefreshInterval, w.showSyncProgress)
+
+	pages.AddPage(chartPageName, w.newBalanceChart(), true, false)
+
+	// The split page shows the same table and log view as their own pages,
+	// only one of which is drawn at a time.
+	w.split = components.NewSplitPane(table, logView, l.AppConfig.SplitPercent)
+	w.splitSave = shared.NewThrottle(splitSaveInterval, w.saveSplitPercent)
+	w.split.SetChangedFunc(func(percent int) {
+		l.AppConfig.SplitPercent = percent
+		w.splitSave.Trigger()
+	})
+	pages.AddPage(splitPageName, w.split, true, false)
+
 	w.view.SetInputCapture(w.handleKeys)
+	table.SetSortFunc(w.resortTransactions)
+	table.SetMenuFunc(w.showTransactionMenu)
 
 	w.nsub, w.cancelN = l.Notif.Subscribe()
 	go w.listenNewTransactions()
@@ -147,15 +218,48 @@ func (w *Wallet) handleKeys(event *tcell.EventKey) *tcell.EventKey {
 	case tcell.KeyCtrlT:
 		w.showTransactionsView()
 		return nil
+	case tcell.KeyCtrlG:
+		w.showChartView()
+		return nil
+	case tcell.KeyCtrlB:
+		w.showSplitView()
+		return nil
 	case tcell.KeyCtrlS:
 		w.showMessageTools()
 		return nil
 	case tcell.KeyCtrlA:
 		w.showUsedAddresses()
 		return nil
+	case tcell.KeyCtrlW:
+		w.showWatchedAddresses()
+		return nil
+	case tcell.KeyCtrlU:
+		w.showCoins()
+		return nil
+	case tcell.KeyCtrlE:
+		w.showJournal()
+		return nil
 	case tcell.KeyCtrlX:
 		w.promptRescan()
 		return nil
+	case tcell.KeyCtrlP:
+		w.showPsbtSignView()
+		return nil
+	case tcell.KeyCtrlO:
+		w.showColumnPicker()
+		return nil
+	case tcell.KeyCtrlD:
+		w.showDebugInfo()
+		return nil
+	case tcell.KeyF1:
+		w.showAbout()
+		return nil
+	case tcell.KeyF2:
+		w.showDaemonSettings()
+		return nil
+	case tcell.KeyF3:
+		w.showNotificationHistory()
+		return nil
 	}
 
 	if event.Key() != tcell.KeyRune {
@@ -163,6 +267,22 @@ func (w *Wallet) handleKeys(event *tcell.EventKey) *tcell.EventKey {
 	}
 
 	switch unicode.ToLower(event.Rune()) {
+	case '/':
+		if w.activePane() == logsView {
+			w.promptLogSearch()
+		}
+	case 'n':
+		if w.activePane() == logsView {
+			if event.Rune() == 'N' {
+				w.moveLogMatch(-1)
+			} else {
+				w.moveLogMatch(1)
+			}
+		}
+	case 'w':
+		if w.activePane() == logsView {
+			w.cycleLogFilter()
+		}
 	case 's':
 		w.showTransfertView()
 	case 'r':
@@ -171,12 +291,83 @@ func (w *Wallet) handleKeys(event *tcell.EventKey) *tcell.EventKey {
 		w.changePassword()
 	case 'l':
 		w.lockWallet()
+	case 'u':
+		w.toggleDenomination()
+	case 'm':
+		if w.activePane() == transactionsView {
+			w.showSelectedTransactionMenu()
+		}
+	case 'v':
+		if w.activePane() == logsView {
+			w.showLogLevelPicker()
+		}
+	case 'p':
+		if w.activePane() == logsView {
+			w.showRPCStats()
+		}
+	case 'g':
+		if w.activePane() == logsView {
+			w.showEffectiveConfig()
+		}
+	case 'f':
+		switch w.activePane() {
+		case transactionsView:
+			w.showDateFilter()
+		case logsView:
+			w.toggleLogFollow()
+		}
+	case 't':
+		if w.activePane() == logsView {
+			w.promptLogJump()
+		}
+	case 'o':
+		if w.activePane() == logsView {
+			w.showLogSources()
+		}
+	case 'y':
+		if w.activePane() == logsView {
+			w.copyVisibleLog()
+		}
+	case 'x':
+		if w.activePane() == logsView {
+			w.saveVisibleLog()
+		}
+	case 'e':
+		w.showExportDialog()
+	case 'b':
+		if w.activePane() == transactionsView {
+			w.toggleMarking()
+		}
 	}
 
 	return event
 
 }
 
+func (w *Wallet) toggleDenomination() {
+	w.setDenomination(shared.ToggleDenomination())
+}
+
+// setDenomination shows the amounts of the wallet in unit.
+func (w *Wallet) setDenomination(unit shared.Denomination) {
+	shared.SetDenomination(unit)
+	w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("Amounts shown in %s", unit.Label()), time.Second*5)
+	w.load.BroadcastBalanceRefresh()
+	go w.updateRows()
+}
+
+// toggleMarking turns the marking of transactions, to label several at once,
+// on or off.
+func (w *Wallet) toggleMarking() {
+	marking := !w.table.MultiSelect()
+	w.table.SetMultiSelect(marking)
+	if marking {
+		w.load.Notif.ShowToastWithTimeout("☑ Space marks a transaction, a marks them all, m offers to label the marked ones", time.Second*10)
+	} else {
+		w.load.Notif.ShowToastWithTimeout("Marking off", time.Second*5)
+	}
+}
+
 func (w *Wallet) showLogsView() {
 	if w.viewMode == logsView {
 		return
@@ -195,6 +386,44 @@ func (w *Wallet) showTransactionsView() {
 	w.focusActiveView()
 }
 
+func (w *Wallet) showChartView() {
+	if w.viewMode == chartView {
+		return
+	}
+	w.view.SwitchToPage(chartPageName)
+	w.viewMode = chartView
+	w.focusActiveView()
+}
+
+// showSplitView shows the transactions and the log tail side by side.
+func (w *Wallet) showSplitView() {
+	if w.viewMode == splitView {
+		return
+	}
+	w.view.SwitchToPage(splitPageName)
+	w.viewMode = splitView
+	w.focusActiveView()
+}
+
+// activePane returns the view the keys of the wallet page apply to: the
+// view shown, or the focused pane of the split view.
+func (w *Wallet) activePane() walletView {
+	if w.viewMode != splitView {
+		return w.viewMode
+	}
+	if w.split.FocusedPane() == 1 {
+		return logsView
+	}
+	return transactionsView
+}
+
+func (w *Wallet) saveSplitPercent() {
+	cfg := w.load.AppConfig
+	if err := cfg.SaveOption(splitPercentOption, strconv.Itoa(cfg.SplitPercent)); err != nil {
+		w.load.Logger.Warn().Err(err).Msg("failed to save the split layout")
+	}
+}
+
 func (w *Wallet) focusActiveView() {
 	if w.load == nil || w.load.Application == nil {
 		return
@@ -202,6 +431,10 @@ func (w *Wallet) focusActiveView() {
 	switch w.viewMode {
 	case logsView:
 		w.load.Application.SetFocus(w.logView)
+	case chartView:
+		w.load.Application.SetFocus(w.view)
+	case splitView:
+		w.load.Application.SetFocus(w.split)
 	default:
 		w.load.Application.SetFocus(w.table)
 	}
@@ -210,6 +443,9 @@ func (w *Wallet) focusActiveView() {
 func (w *Wallet) Destroy() {
 	w.quitOnce.Do(func() {
 		w.cancelTransactionsUpdateRetry()
+		w.blockRefresh.Stop()
+		w.syncRefresh.Stop()
+		w.splitSave.Stop()
 		if w.cancelN != nil {
 			w.cancelN()
 		}
@@ -0,0 +1,209 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package wallet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/flokiorg/twallet/components"
+	"github.com/flokiorg/twallet/load"
+	"github.com/flokiorg/twallet/shared"
+)
+
+func watchColumns() []components.Column {
+	return []components.Column{
+		{Name: "Address", Align: tview.AlignLeft},
+		{Name: "Since", Align: tview.AlignRight},
+		{Name: "Received", Align: tview.AlignRight},
+		{Name: "Spent", Align: tview.AlignRight},
+		{Name: "Last activity", Align: tview.AlignRight},
+	}
+}
+
+// showWatchedAddresses lists the addresses followed by the address watcher,
+// with the activity of the selected one underneath.
+func (w *Wallet) showWatchedAddresses() {
+	if w.load == nil || w.load.Watch == nil {
+		return
+	}
+
+	w.load.Notif.CancelToast()
+
+	netColor := shared.NetworkColor(*w.load.AppConfig.Network)
+
+	table := components.NewTable("Watched", watchColumns(), netColor, 0)
+	table.SetBorder(true)
+	table.SetBorderColor(shared.CurrentTheme().Primary)
+	table.SetTitle("")
+	table.SetBorderPadding(0, 0, 2, 2)
+
+	addField := tview.NewInputField()
+	addField.SetLabel("Watch: ")
+	addField.SetFieldWidth(0)
+	addField.SetPlaceholder("paste an address and press enter")
+	addField.SetPlaceholderTextColor(shared.CurrentTheme().Text)
+	addField.SetBorderPadding(1, 1, 1, 1)
+
+	activityView := tview.NewTextView()
+	activityView.SetDynamicColors(true).
+		SetScrollable(true).
+		SetBorderPadding(0, 0, 2, 2)
+	activityView.SetBackgroundColor(tcell.ColorDefault)
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText("[gray::]<tab> switch · <d> stop watching · <c> copy · <esc> close")
+	hint.SetBackgroundColor(tcell.ColorDefault)
+
+	container := tview.NewFlex().SetDirection(tview.FlexRow)
+	container.SetTitle("Watched addresses").
+		SetTitleColor(shared.CurrentTheme().ModalTitle).
+		SetBorder(true).
+		SetBackgroundColor(shared.CurrentTheme().Modal)
+	container.AddItem(addField, 3, 0, true).
+		AddItem(table, 0, 2, false).
+		AddItem(activityView, 0, 1, false).
+		AddItem(hint, 1, 0, false)
+
+	var entries []load.WatchedAddress
+
+	selected := func() (load.WatchedAddress, bool) {
+		row, _ := table.GetSelection()
+		if row <= 0 || row-1 >= len(entries) {
+			return load.WatchedAddress{}, false
+		}
+		return entries[row-1], true
+	}
+
+	renderActivity := func() {
+		entry, ok := selected()
+		if !ok {
+			activityView.SetText("")
+			return
+		}
+		var b strings.Builder
+		if entry.Err != nil {
+			fmt.Fprintf(&b, "[yellow::]Watch interrupted:[-:-:-] %s (retrying)\n", tview.Escape(entry.Err.Error()))
+		}
+		if len(entry.Activity) == 0 {
+			fmt.Fprintf(&b, "[gray::]No activity since block %d", entry.Since)
+		}
+		for _, activity := range entry.Activity {
+			amountCell := fmt.Sprintf("[green:-:-]+%s[-:-:-]", shared.FormatAmountView(activity.Amount, 8))
+			if activity.Amount < 0 {
+				amountCell = fmt.Sprintf("[red:-:-]%s[-:-:-]", shared.FormatAmountView(activity.Amount, 8))
+			}
+			fmt.Fprintf(&b, "#%d  %s  %s\n", activity.BlockHeight, activity.Txid, amountCell)
+		}
+		activityView.SetText(b.String()).ScrollToBeginning()
+	}
+
+	render := func() {
+		entries = w.load.Watch.List()
+		if len(entries) == 0 {
+			table.ShowPlaceholder("No watched addresses")
+			activityView.SetText("")
+			return
+		}
+
+		row, _ := table.GetSelection()
+		rows := make([][]string, 0, len(entries))
+		for _, entry := range entries {
+			last := "[gray::]-"
+			if len(entry.Activity) > 0 {
+				last = fmt.Sprintf("#%d", entry.Activity[0].BlockHeight)
+			}
+			address := shortenAddressForDisplay(entry.Address)
+			if entry.Err != nil {
+				address = fmt.Sprintf("[yellow::]%s", address)
+			}
+			rows = append(rows, []string{
+				address,
+				fmt.Sprintf("#%d", entry.Since),
+				fmt.Sprintf("[green:-:-]%s", shared.FormatAmountView(entry.Received, 6)),
+				fmt.Sprintf("[red:-:-]%s", shared.FormatAmountView(entry.Spent, 6)),
+				last,
+			})
+		}
+		table.Update(rows)
+		table.Select(min(max(row, 1), len(rows)), 0)
+		renderActivity()
+	}
+
+	stopUpdates := w.load.Watch.OnChange(func() {
+		w.load.Application.QueueUpdateDraw(render)
+	})
+	closeView := func() {
+		stopUpdates()
+		w.closeModal()
+	}
+
+	table.SetSelectionChangedFunc(func(row, column int) {
+		renderActivity()
+	})
+
+	addField.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+		text := strings.TrimSpace(addField.GetText())
+		if text == "" {
+			w.load.Application.SetFocus(table)
+			return
+		}
+		if err := w.load.Watch.Add(text); err != nil {
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*10)
+			return
+		}
+		addField.SetText("")
+		w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("👀 Watching %s", shortAddress(text)), time.Second*10)
+	})
+
+	container.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc, tcell.KeyCtrlC:
+			closeView()
+			return nil
+		case tcell.KeyTab, tcell.KeyBacktab:
+			if addField.HasFocus() {
+				w.load.Application.SetFocus(table)
+			} else {
+				w.load.Application.SetFocus(addField)
+			}
+			return nil
+		}
+
+		if addField.HasFocus() || event.Key() != tcell.KeyRune {
+			return event
+		}
+		entry, ok := selected()
+		if !ok {
+			return event
+		}
+		switch event.Rune() {
+		case 'd', 'D':
+			if err := w.load.Watch.Remove(entry.Address); err != nil {
+				w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s", err.Error()), time.Second*10)
+				return nil
+			}
+			w.load.Notif.ShowToastWithTimeout(fmt.Sprintf("Stopped watching %s", shortAddress(entry.Address)), time.Second*10)
+			return nil
+		case 'c', 'C':
+			components.CopyText(w.load, entry.Address, shortAddress(entry.Address))
+			return nil
+		}
+		return event
+	})
+
+	render()
+	w.nav.ShowModal(components.NewModal(container, 110, 32, closeView))
+	w.load.Application.SetFocus(addField)
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/go-flokicoin/chainutil"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+// queryOutput is the JSON printed by the --print-* options, a member for
+// each of the options given, or the error that kept them from an answer.
+// It goes to stdout, stderr being the crash log once the logger is set.
+type queryOutput struct {
+	Status  *queryStatus  `json:"status,omitempty"`
+	Balance *queryBalance `json:"balance,omitempty"`
+	Address *queryAddress `json:"address,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+type queryStatus struct {
+	State          flnd.Status `json:"state"`
+	Network        string      `json:"network"`
+	BlockHeight    uint32      `json:"block_height"`
+	Synced         bool        `json:"synced"`
+	ChainPeers     int         `json:"chain_peers"`
+	LightningPeers int         `json:"lightning_peers"`
+}
+
+// queryBalance holds the amounts in loki.
+type queryBalance struct {
+	Total       int64 `json:"total"`
+	Confirmed   int64 `json:"confirmed"`
+	Unconfirmed int64 `json:"unconfirmed"`
+	Locked      int64 `json:"locked"`
+	Synced      bool  `json:"synced"`
+}
+
+type queryAddress struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+}
+
+// queryRequested reports whether one of the --print-* options asking the
+// wallet was given.
+func queryRequested(opts *cliOptions) bool {
+	return opts.PrintStatus || opts.PrintBalance || opts.PrintAddress
+}
+
+// runQuery starts the wallet daemon without the UI, prints what the
+// --print-* options ask as one JSON object and stops it. It reports whether
+// it got an answer.
+func runQuery(out io.Writer, opts *cliOptions) bool {
+	output, err := queryWallet(opts)
+	if err != nil {
+		output = &queryOutput{Error: err.Error()}
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(output) == nil && err == nil
+}
+
+// queryWallet answers the --print-* options. A locked wallet is unlocked
+// with defaultpassword, or the passphrase asked on the terminal.
+func queryWallet(opts *cliOptions) (*queryOutput, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), opts.PrintTimeout)
+	defer cancel()
+
+	svc := flnd.New(ctx, &opts.ServiceConfig)
+	defer svc.StopWithTimeout(opts.ShutdownTimeout)
+
+	u, err := waitForWallet(ctx, svc, flnd.StatusLocked, flnd.StatusNoWallet, flnd.StatusUnlocked, flnd.StatusSyncing, flnd.StatusReady)
+	if err != nil {
+		return nil, err
+	}
+	switch u.State {
+	case flnd.StatusNoWallet:
+		return nil, errors.New("no wallet in " + opts.Walletdir + ", start twallet to create one")
+	case flnd.StatusLocked:
+		if err := unlockForQuery(ctx, svc, opts.DefaultPassword); err != nil {
+			return nil, err
+		}
+		if _, err := waitForWallet(ctx, svc, flnd.StatusUnlocked, flnd.StatusSyncing, flnd.StatusReady); err != nil {
+			return nil, err
+		}
+	}
+
+	return answerQuery(ctx, svc, opts)
+}
+
+// walletQuerier is the part of the wallet service the --print-* options ask.
+type walletQuerier interface {
+	Status() flnd.Status
+	IsSynced() (bool, bool, uint32, error)
+	NetworkInfo(ctx context.Context) (*flnd.NetworkInfo, error)
+	Balance(ctx context.Context) (*lnrpc.WalletBalanceResponse, error)
+	GetNextAddress(ctx context.Context, t lnrpc.AddressType) (chainutil.Address, error)
+}
+
+// answerQuery builds the output of the --print-* options from the unlocked
+// wallet.
+func answerQuery(ctx context.Context, svc walletQuerier, opts *cliOptions) (*queryOutput, error) {
+	synced, _, height, err := svc.IsSynced()
+	if err != nil {
+		return nil, err
+	}
+
+	output := &queryOutput{}
+	if opts.PrintStatus {
+		info, err := svc.NetworkInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		output.Status = &queryStatus{
+			State:          svc.Status(),
+			Network:        opts.Network.Name,
+			BlockHeight:    height,
+			Synced:         synced,
+			ChainPeers:     info.ChainPeers,
+			LightningPeers: info.LightningPeers,
+		}
+	}
+	if opts.PrintBalance {
+		balance, err := svc.Balance(ctx)
+		if err != nil {
+			return nil, err
+		}
+		output.Balance = &queryBalance{
+			Total:       balance.TotalBalance,
+			Confirmed:   balance.ConfirmedBalance,
+			Unconfirmed: balance.UnconfirmedBalance,
+			Locked:      balance.LockedBalance,
+			Synced:      synced,
+		}
+	}
+	if opts.PrintAddress {
+		address, err := svc.GetNextAddress(ctx, opts.UnusedAddressType)
+		if err != nil {
+			return nil, err
+		}
+		output.Address = &queryAddress{Address: address.String(), Type: opts.AddressType}
+	}
+	return output, nil
+}
+
+// waitForWallet waits until the daemon reaches one of states, failing as
+// soon as it cannot start rather than waiting for its restarts.
+func waitForWallet(ctx context.Context, svc *flnd.Service, states ...flnd.Status) (*flnd.Update, error) {
+	u, err := svc.WaitForState(ctx, append(states, flnd.StatusDown, flnd.StatusFailedPermanently)...)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, errors.New("the wallet daemon did not start in time, raise --print-timeout")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if u.State == flnd.StatusDown || u.State == flnd.StatusFailedPermanently {
+		if u.Err != nil {
+			return nil, fmt.Errorf("the wallet daemon failed to start: %w", u.Err)
+		}
+		return nil, errors.New("the wallet daemon stopped")
+	}
+	return u, nil
+}
+
+func unlockForQuery(ctx context.Context, svc *flnd.Service, defaultPassword string) error {
+	passphrase := defaultPassword
+	if passphrase == "" {
+		var err error
+		if passphrase, err = readSecret("Lock passphrase"); err != nil {
+			return fmt.Errorf("the wallet is locked: %w, or set defaultpassword", err)
+		}
+	}
+	if err := svc.Unlock(ctx, passphrase); err != nil {
+		return fmt.Errorf("failed to unlock the wallet: %w", err)
+	}
+	return nil
+}
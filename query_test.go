@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flokiorg/flnd/lnrpc"
+	"github.com/flokiorg/go-flokicoin/chaincfg"
+	"github.com/flokiorg/go-flokicoin/chainutil"
+
+	"github.com/flokiorg/twallet/flnd"
+)
+
+// syncQuerier answers IsSynced with synced and recentHeader.
+type syncQuerier struct {
+	synced, recentHeader bool
+}
+
+func (q *syncQuerier) Status() flnd.Status { return flnd.StatusSyncing }
+
+func (q *syncQuerier) IsSynced() (bool, bool, uint32, error) {
+	return q.synced, q.recentHeader, 100, nil
+}
+
+func (q *syncQuerier) NetworkInfo(context.Context) (*flnd.NetworkInfo, error) {
+	return &flnd.NetworkInfo{}, nil
+}
+
+func (q *syncQuerier) Balance(context.Context) (*lnrpc.WalletBalanceResponse, error) {
+	return &lnrpc.WalletBalanceResponse{}, nil
+}
+
+func (q *syncQuerier) GetNextAddress(context.Context, lnrpc.AddressType) (chainutil.Address, error) {
+	return nil, nil
+}
+
+func TestAnswerQuerySynced(t *testing.T) {
+	opts := &cliOptions{PrintStatus: true, PrintBalance: true}
+	opts.Network = &chaincfg.MainNetParams
+
+	for _, tc := range []struct {
+		name                 string
+		synced, recentHeader bool
+	}{
+		{"headers recent, not synced", false, true},
+		{"synced", true, true},
+		{"behind", false, false},
+	} {
+		output, err := answerQuery(context.Background(), &syncQuerier{synced: tc.synced, recentHeader: tc.recentHeader}, opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if output.Status.Synced != tc.synced || output.Balance.Synced != tc.synced {
+			t.Fatalf("%s: status synced %v, balance synced %v, want %v", tc.name, output.Status.Synced, output.Balance.Synced, tc.synced)
+		}
+		if output.Status.BlockHeight != 100 {
+			t.Fatalf("%s: block height %d, want 100", tc.name, output.Status.BlockHeight)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/flokiorg/twallet/config"
+	"golang.org/x/term"
+)
+
+// walletPassphraseEnv holds the wallet passphrase opening the encrypted
+// options, for the starts without a terminal to ask it on.
+const walletPassphraseEnv = config.EnvPrefix + "WALLET_PASSPHRASE"
+
+// uiStarted is set once the UI owns the terminal, the passphrase cannot be
+// asked on it anymore.
+var uiStarted atomic.Bool
+
+// secretKeys opens the encrypted options, asking the wallet passphrase at
+// most once. A defaultpassword in plain text, or opened from the keyring,
+// is the wallet passphrase and is not asked again.
+func secretKeys(opts *cliOptions) config.SecretKeys {
+	return config.SecretKeys{
+		Passphrase: sync.OnceValues(func() (string, error) {
+			if passphrase, ok := os.LookupEnv(walletPassphraseEnv); ok {
+				return passphrase, nil
+			}
+			if opts.DefaultPassword != "" && !config.IsSecret(opts.DefaultPassword) {
+				return opts.DefaultPassword, nil
+			}
+			if uiStarted.Load() {
+				return "", fmt.Errorf("restart twallet to enter the wallet passphrase opening the encrypted options, or set %s", walletPassphraseEnv)
+			}
+			passphrase, err := readSecret("Wallet passphrase")
+			if err != nil {
+				return "", fmt.Errorf("%w, or set %s", err, walletPassphraseEnv)
+			}
+			return passphrase, nil
+		}),
+		Keyring: sync.OnceValues(config.ReadKeyringKey),
+	}
+}
+
+// readSecret asks for a value on the terminal without echoing it.
+func readSecret(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("no terminal to ask the %s", strings.ToLower(prompt))
+	}
+	fmt.Fprintf(notices, "%s: ", prompt)
+	value, err := term.ReadPassword(fd)
+	fmt.Fprintln(notices)
+	return string(value), err
+}
+
+// encryptCommand encrypts the value of a sensitive option into the config
+// file, so it can be backed up without the value in plain text.
+type encryptCommand struct {
+	Keyring bool `long:"keyring" description:"Encrypt with a key kept in the OS keyring instead of the wallet passphrase, storing the key there when missing"`
+
+	Args struct {
+		Option string `positional-arg-name:"option" description:"Option to encrypt"`
+	} `positional-args:"yes" required:"yes"`
+
+	opts *cliOptions
+}
+
+func (c *encryptCommand) Execute(args []string) error {
+	options := config.SecretOptions()
+	if !slices.Contains(options, c.Args.Option) {
+		return fmt.Errorf("%s cannot be encrypted, choose one of %s", c.Args.Option, strings.Join(options, ", "))
+	}
+
+	dirs := config.DefaultDirs()
+	path := c.opts.ConfigFile
+	if path == "" {
+		var err error
+		if path, err = defaultConfigPath(dirs); err != nil {
+			return err
+		}
+	}
+
+	value, err := readSecret("Value of " + c.Args.Option)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		return errors.New("empty value, nothing to encrypt")
+	}
+
+	var encrypted string
+	if c.Keyring {
+		key, err := config.CreateKeyringKey()
+		if err != nil {
+			return err
+		}
+		if encrypted, err = config.EncryptWithKeyring(value, key); err != nil {
+			return err
+		}
+		fmt.Println("Encrypted with the key of the OS keyring, a backup restored elsewhere needs the value again.")
+	} else {
+		passphrase, err := readSecret("Wallet passphrase")
+		if err != nil {
+			return err
+		}
+		confirm, err := readSecret("Wallet passphrase again")
+		if err != nil {
+			return err
+		}
+		if passphrase != confirm {
+			return errors.New("the passphrases differ")
+		}
+		if passphrase == "" {
+			return errors.New("empty passphrase")
+		}
+		if encrypted, err = config.EncryptWithPassphrase(value, passphrase); err != nil {
+			return err
+		}
+	}
+
+	if !config.IsINIFile(path) {
+		fmt.Printf("twallet does not write %s, set %s to this value in it:\n%s\n", path, c.Args.Option, encrypted)
+		return nil
+	}
+	if err := config.SetFileOption(path, c.Args.Option, encrypted); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote the encrypted %s to %s.\n", c.Args.Option, path)
+	return nil
+}
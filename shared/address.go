@@ -0,0 +1,74 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package shared
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	addressChunkSize = 4
+	addressEdgeSize  = 6
+)
+
+// ChunkString splits s into groups of size runes, the last one possibly
+// shorter.
+func ChunkString(s string, size int) []string {
+	runes := []rune(s)
+	if size <= 0 || len(runes) == 0 {
+		return nil
+	}
+	chunks := make([]string, 0, (len(runes)+size-1)/size)
+	for len(runes) > size {
+		chunks = append(chunks, string(runes[:size]))
+		runes = runes[size:]
+	}
+	return append(chunks, string(runes))
+}
+
+// FullWidth converts printable ASCII to its double width form so short
+// strings stand out in a terminal.
+func FullWidth(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == ' ':
+			b.WriteRune('　')
+		case r > ' ' && r <= '~':
+			b.WriteRune(r - '!' + '！')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// AddressVerifyView renders address for checking it against another device:
+// its first and last characters in double width, then the whole address in
+// groups of four with alternating colors.
+func AddressVerifyView(address string) string {
+	theme := CurrentTheme()
+
+	edges := FullWidth(address)
+	if runes := []rune(address); len(runes) > 2*addressEdgeSize {
+		edges = fmt.Sprintf("%s [%s::]…[-::] %s",
+			FullWidth(string(runes[:addressEdgeSize])),
+			theme.Muted,
+			FullWidth(string(runes[len(runes)-addressEdgeSize:])))
+	}
+
+	chunks := ChunkString(address, addressChunkSize)
+	colored := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		color := theme.Text
+		if i%2 == 1 {
+			color = theme.Accent
+		}
+		colored[i] = fmt.Sprintf("[%s::b]%s[-::-]", color, chunk)
+	}
+
+	return fmt.Sprintf("[::b]%s[::-]\n\n%s", edges, strings.Join(colored, " "))
+}
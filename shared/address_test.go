@@ -0,0 +1,30 @@
+package shared
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChunkString(t *testing.T) {
+	got := ChunkString("fc1qabcdefgh", 4)
+	want := []string{"fc1q", "abcd", "efgh"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ChunkString = %q, want %q", got, want)
+	}
+
+	got = ChunkString("fc1qab", 4)
+	want = []string{"fc1q", "ab"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ChunkString = %q, want %q", got, want)
+	}
+
+	if got := ChunkString("", 4); got != nil {
+		t.Fatalf("ChunkString(\"\") = %q, want nil", got)
+	}
+}
+
+func TestFullWidth(t *testing.T) {
+	if got, want := FullWidth("Fc1 q"), "Ｆｃ１　ｑ"; got != want {
+		t.Fatalf("FullWidth = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package shared
+
+import "strings"
+
+var chartBlocks = []rune{' ', '▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// BlockChart draws values as vertical bars, one column per value, scaled so
+// peak fills height rows. Each row is an eighth-block resolution line and rows
+// are returned top to bottom.
+func BlockChart(values []float64, peak float64, height int) []string {
+	if height <= 0 {
+		return nil
+	}
+
+	steps := height * (len(chartBlocks) - 1)
+	levels := make([]int, len(values))
+	for i, v := range values {
+		if peak <= 0 || v <= 0 {
+			continue
+		}
+		level := int(v / peak * float64(steps))
+		if level == 0 {
+			// Keep tiny but non-zero values visible.
+			level = 1
+		}
+		levels[i] = min(level, steps)
+	}
+
+	rows := make([]string, height)
+	for r := range rows {
+		floor := (height - 1 - r) * (len(chartBlocks) - 1)
+		var b strings.Builder
+		for _, level := range levels {
+			fill := min(max(level-floor, 0), len(chartBlocks)-1)
+			b.WriteRune(chartBlocks[fill])
+		}
+		rows[r] = b.String()
+	}
+	return rows
+}
+
+// Sparkline renders values on a single line of block characters.
+func Sparkline(values []float64) string {
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	return BlockChart(values, peak, 1)[0]
+}
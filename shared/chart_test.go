@@ -0,0 +1,26 @@
+package shared
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBlockChart(t *testing.T) {
+	got := BlockChart([]float64{0, 1, 2, 4, 0.01}, 4, 2)
+	want := []string{
+		"   █ ",
+		" ▄██▁",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BlockChart() = %q, want %q", got, want)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got, want := Sparkline([]float64{0, 4, 8}), " ▄█"; got != want {
+		t.Fatalf("Sparkline() = %q, want %q", got, want)
+	}
+	if got := Sparkline(nil); got != "" {
+		t.Fatalf("Sparkline(nil) = %q, want empty", got)
+	}
+}
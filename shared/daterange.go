@@ -0,0 +1,65 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package shared
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout is how dates are written when picked or filtered on.
+const DateLayout = "2006-01-02"
+
+// DateRange is a span of whole days in TimeZone, both ends included. A zero bound leaves that side open.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// StartOfDay returns midnight, in TimeZone, of the day t falls on.
+func StartOfDay(t time.Time) time.Time {
+	loc := TimeZone()
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// NewDateRange spans the days from a to b, in whichever order they come.
+func NewDateRange(a, b time.Time) DateRange {
+	a, b = StartOfDay(a), StartOfDay(b)
+	if b.Before(a) {
+		a, b = b, a
+	}
+	return DateRange{From: a, To: b}
+}
+
+// IsZero reports whether the range is open on both sides.
+func (r DateRange) IsZero() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+// Contains reports whether t falls on one of the days of the range.
+func (r DateRange) Contains(t time.Time) bool {
+	if !r.From.IsZero() && t.Before(StartOfDay(r.From)) {
+		return false
+	}
+	if !r.To.IsZero() && !t.Before(StartOfDay(r.To).AddDate(0, 0, 1)) {
+		return false
+	}
+	return true
+}
+
+func (r DateRange) String() string {
+	switch {
+	case r.IsZero():
+		return "all dates"
+	case r.To.IsZero():
+		return "since " + r.From.Format(DateLayout)
+	case r.From.IsZero():
+		return "until " + r.To.Format(DateLayout)
+	case StartOfDay(r.From).Equal(StartOfDay(r.To)):
+		return r.From.Format(DateLayout)
+	}
+	return fmt.Sprintf("%s → %s", r.From.Format(DateLayout), r.To.Format(DateLayout))
+}
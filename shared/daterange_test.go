@@ -0,0 +1,35 @@
+package shared
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateRange(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.Local) }
+
+	r := NewDateRange(day(12).Add(15*time.Hour), day(10).Add(time.Hour))
+	if !r.From.Equal(day(10)) || !r.To.Equal(day(12)) {
+		t.Fatalf("range = %v, want the 10th to the 12th", r)
+	}
+	if !r.Contains(day(10)) || !r.Contains(day(12).Add(23*time.Hour+59*time.Minute)) {
+		t.Fatal("both ends of the range must be included")
+	}
+	if r.Contains(day(9).Add(23*time.Hour)) || r.Contains(day(13)) {
+		t.Fatal("days outside the range matched")
+	}
+	if got := r.String(); got != "2024-03-10 → 2024-03-12" {
+		t.Fatalf("String() = %q", got)
+	}
+
+	since := DateRange{From: day(10)}
+	if !since.Contains(day(30)) || since.Contains(day(9)) {
+		t.Fatal("an open end must not bound the range")
+	}
+	if got := NewDateRange(day(5), day(5)).String(); got != "2024-03-05" {
+		t.Fatalf("single day String() = %q", got)
+	}
+	if !(DateRange{}).Contains(time.Unix(0, 0)) {
+		t.Fatal("the zero range must contain every time")
+	}
+}
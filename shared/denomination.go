@@ -0,0 +1,163 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package shared
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/flokiorg/go-flokicoin/chainutil"
+)
+
+// Denomination selects the unit amounts are displayed and entered in.
+type Denomination int32
+
+const (
+	DenominationFLC Denomination = iota
+	DenominationLoki
+)
+
+const lokiSign = "loki"
+
+var activeDenomination atomic.Int32
+
+func (d Denomination) String() string {
+	switch d {
+	case DenominationLoki:
+		return "loki"
+	default:
+		return "flc"
+	}
+}
+
+// Label is the unit name shown to the user.
+func (d Denomination) Label() string {
+	switch d {
+	case DenominationLoki:
+		return lokiSign
+	default:
+		return "FLC"
+	}
+}
+
+func ParseDenomination(name string) (Denomination, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "flc":
+		return DenominationFLC, nil
+	case "loki":
+		return DenominationLoki, nil
+	default:
+		return DenominationFLC, fmt.Errorf("unknown denomination %q (available: flc, loki)", name)
+	}
+}
+
+func CurrentDenomination() Denomination {
+	return Denomination(activeDenomination.Load())
+}
+
+func SetDenomination(d Denomination) {
+	activeDenomination.Store(int32(d))
+}
+
+// ToggleDenomination switches between FLC and loki and returns the new unit.
+func ToggleDenomination() Denomination {
+	next := DenominationLoki
+	if CurrentDenomination() == DenominationLoki {
+		next = DenominationFLC
+	}
+	SetDenomination(next)
+	return next
+}
+
+// Precision is the number of decimals an amount can have in d.
+func (d Denomination) Precision() int {
+	if d == DenominationLoki {
+		return 0
+	}
+	return 8
+}
+
+// ParseAmount reads a user supplied amount in the active denomination.
+func ParseAmount(text string) (chainutil.Amount, error) {
+	return ParseAmountIn(text, CurrentDenomination())
+}
+
+// ParseAmountIn reads a user supplied amount in d. The decimal text is read
+// exactly, thousands separators aside, so large amounts keep every loki; more
+// decimals than d has are rejected rather than rounded.
+func ParseAmountIn(text string, d Denomination) (chainutil.Amount, error) {
+	text = strings.ReplaceAll(strings.TrimSpace(text), ",", "")
+
+	whole, frac, hasPoint := strings.Cut(text, ".")
+	if whole == "" && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("invalid amount")
+	}
+	if hasPoint && d.Precision() == 0 {
+		return 0, fmt.Errorf("%s amounts have no decimals", d.Label())
+	}
+	if len(frac) > d.Precision() {
+		return 0, fmt.Errorf("%s amounts have at most %d decimals", d.Label(), d.Precision())
+	}
+
+	digits := whole + frac + strings.Repeat("0", d.Precision()-len(frac))
+	loki, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount")
+	}
+	return chainutil.Amount(loki), nil
+}
+
+// FormatAmountIn returns amount in d as ParseAmountIn reads it back, with
+// its thousands grouped and no trailing zero decimals.
+func FormatAmountIn(amount chainutil.Amount, d Denomination) string {
+	negative := amount < 0
+	if negative {
+		amount = -amount
+	}
+	text := strconv.FormatInt(int64(amount), 10)
+	if precision := d.Precision(); precision > 0 {
+		text = fmt.Sprintf("%0*s", precision+1, text)
+		whole, frac := text[:len(text)-precision], strings.TrimRight(text[len(text)-precision:], "0")
+		text = whole
+		if frac != "" {
+			text += "." + frac
+		}
+	}
+	text = GroupThousands(text)
+	if negative {
+		text = "-" + text
+	}
+	return text
+}
+
+// GroupThousands puts separators between the thousands of the integer part
+// of an amount, dropping the ones already there.
+func GroupThousands(text string) string {
+	text = strings.ReplaceAll(text, ",", "")
+	whole, frac, hasPoint := strings.Cut(text, ".")
+
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if hasPoint {
+		b.WriteString("." + frac)
+	}
+	return b.String()
+}
+
+func isDigits(text string) bool {
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
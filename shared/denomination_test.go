@@ -0,0 +1,65 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/flokiorg/go-flokicoin/chainutil"
+)
+
+func TestParseAmountIn(t *testing.T) {
+	tests := []struct {
+		text  string
+		denom Denomination
+		want  chainutil.Amount
+		ok    bool
+	}{
+		{"1", DenominationFLC, 100_000_000, true},
+		{"1,234.5", DenominationFLC, 123_450_000_000, true},
+		{".00000001", DenominationFLC, 1, true},
+		{"92,233,720,368.54775807", DenominationFLC, 9_223_372_036_854_775_807, true},
+		{"92233720368.54775808", DenominationFLC, 0, false},
+		{"0.000000001", DenominationFLC, 0, false},
+		{"1e3", DenominationFLC, 0, false},
+		{"-1", DenominationFLC, 0, false},
+		{".", DenominationFLC, 0, false},
+		{"", DenominationFLC, 0, false},
+		{"12,345", DenominationLoki, 12_345, true},
+		{"1.5", DenominationLoki, 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmountIn(tt.text, tt.denom)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseAmountIn(%q, %s) = %d, %v", tt.text, tt.denom, got, err)
+		}
+	}
+}
+
+func TestFormatAmountIn(t *testing.T) {
+	tests := []struct {
+		amount chainutil.Amount
+		denom  Denomination
+		want   string
+	}{
+		{0, DenominationFLC, "0"},
+		{1, DenominationFLC, "0.00000001"},
+		{123_450_000_000, DenominationFLC, "1,234.5"},
+		{-150_000_000, DenominationFLC, "-1.5"},
+		{1_234_567, DenominationLoki, "1,234,567"},
+	}
+	for _, tt := range tests {
+		got := FormatAmountIn(tt.amount, tt.denom)
+		if got != tt.want {
+			t.Errorf("FormatAmountIn(%d, %s) = %q, want %q", tt.amount, tt.denom, got, tt.want)
+		}
+		if tt.amount < 0 {
+			continue
+		}
+		if back, err := ParseAmountIn(got, tt.denom); err != nil || back != tt.amount {
+			t.Errorf("ParseAmountIn(%q) = %d, %v, want %d", got, back, err, tt.amount)
+		}
+	}
+
+	if got := GroupThousands("1234567.1234"); got != "1,234,567.1234" {
+		t.Errorf("GroupThousands = %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package shared
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FuzzyScore reports whether the runes of pattern appear in text in order,
+// ignoring case, and scores the match: runes found next to each other or at
+// the start of a word count more, so "in" ranks "wallet_info" above
+// "neutrino".
+func FuzzyScore(pattern, text string) (int, bool) {
+	pattern = strings.ToLower(pattern)
+	if pattern == "" {
+		return 0, true
+	}
+
+	want := []rune(pattern)
+	score, matched, last := 0, 0, -2
+	prev := ' '
+	for i, r := range []rune(strings.ToLower(text)) {
+		if matched < len(want) && r == want[matched] {
+			score++
+			if i == last+1 {
+				score += 5
+			}
+			if i == 0 || !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 8
+			}
+			last = i
+			matched++
+		}
+		prev = r
+	}
+	if matched < len(want) {
+		return 0, false
+	}
+	return score, true
+}
+
+// FuzzyFilter returns the indexes of the options pattern matches, best match
+// first and in their order otherwise.
+func FuzzyFilter(pattern string, options []string) []int {
+	type match struct{ index, score int }
+
+	matches := make([]match, 0, len(options))
+	for i, option := range options {
+		if score, ok := FuzzyScore(pattern, option); ok {
+			matches = append(matches, match{i, score})
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].score > matches[b].score
+	})
+
+	indexes := make([]int, len(matches))
+	for i, m := range matches {
+		indexes[i] = m.index
+	}
+	return indexes
+}
@@ -0,0 +1,26 @@
+package shared
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFuzzyFilter(t *testing.T) {
+	options := []string{"wallet_info", "NTFN", "CHDB", "neutrino", "HSWC"}
+
+	if got := FuzzyFilter("", options); !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Fatalf("empty pattern = %v, want every option in order", got)
+	}
+	if got := FuzzyFilter("in", options); !slices.Equal(got, []int{0, 3}) {
+		t.Fatalf("in = %v, want wallet_info first", got)
+	}
+	if got := FuzzyFilter("neu", options); !slices.Equal(got, []int{3}) {
+		t.Fatalf("neu = %v", got)
+	}
+	if got := FuzzyFilter("xyz", options); len(got) != 0 {
+		t.Fatalf("xyz = %v, want no match", got)
+	}
+	if _, ok := FuzzyScore("fni", "wallet_info"); ok {
+		t.Fatal("runes out of order matched")
+	}
+}
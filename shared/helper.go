@@ -6,19 +6,24 @@ package shared
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/flokiorg/go-flokicoin/chaincfg"
 	"github.com/flokiorg/go-flokicoin/chainutil"
 	"github.com/flokiorg/go-flokicoin/wire"
-	"github.com/flokiorg/twallet/utils/clip"
 	"github.com/gdamore/tcell/v2"
 	"github.com/skip2/go-qrcode"
 )
 
 const (
 	flcSign = "𝔽"
+
+	qrImageSize = 512
 )
 
 func FormatAmountView(value chainutil.Amount, precision int) string {
@@ -30,6 +35,11 @@ func FormatAmountView(value chainutil.Amount, precision int) string {
 
 	// Format the number with the specified precision
 	formatted := fmt.Sprintf("%.*f", precision, value.ToFLC())
+	sign := flcSign
+	if CurrentDenomination() == DenominationLoki {
+		formatted = strconv.FormatInt(int64(value), 10)
+		sign = lokiSign
+	}
 
 	// Split into integer and decimal parts
 	parts := strings.Split(formatted, ".")
@@ -62,32 +72,40 @@ func FormatAmountView(value chainutil.Amount, precision int) string {
 
 	// Add currency sign and handle negative numbers
 	if isNegative {
-		return fmt.Sprintf("-%s %s", finalAmount, flcSign) // Negative formatting
+		return fmt.Sprintf("-%s %s", finalAmount, sign) // Negative formatting
 	}
-	return fmt.Sprintf("%s %s", finalAmount, flcSign)
+	return fmt.Sprintf("%s %s", finalAmount, sign)
 }
 
-func ClipboardCopy(text string) error {
-	_, err := clip.CopyText(text)
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-			return fmt.Errorf("right-click to copy (no display)")
-		}
-		return fmt.Errorf("clipboard copy failed: %w", err)
+// OpenURL opens url with the desktop's default handler.
+func OpenURL(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("unable to open browser: %w", err)
 	}
+	go cmd.Wait()
 	return nil
 }
 
 func NetworkColor(network chaincfg.Params) tcell.Color {
 	var logoColor tcell.Color
 
+	theme := CurrentTheme()
 	switch network.Net {
 	case wire.MainNet:
-		logoColor = tcell.ColorOrange
-	case wire.TestNet3:
-		logoColor = tcell.ColorRed
+		logoColor = theme.Mainnet
+	case wire.TestNet3, wire.TestNet4:
+		logoColor = theme.Testnet
 	default:
-		logoColor = tcell.ColorYellowGreen
+		logoColor = theme.Regtest
 	}
 
 	return logoColor
@@ -101,3 +119,36 @@ func GenerateQRText(txt string) (string, error) {
 	qr.DisableBorder = true
 	return qr.ToSmallString(true), err
 }
+
+// ExpandHome replaces a leading ~ of path with the home directory.
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path[1:], "/")), nil
+}
+
+// SaveQRImage writes txt as a PNG QR code to path, expanding a leading ~ to
+// the home directory, and returns the path written. It is an alternative for
+// terminals that draw the unicode QR unreadably.
+func SaveQRImage(txt, path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", fmt.Errorf("file path cannot be empty")
+	}
+	path, err := ExpandHome(path)
+	if err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".png") {
+		path += ".png"
+	}
+	if err := qrcode.WriteFile(txt, qrcode.High, qrImageSize, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,32 @@
+package shared
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveQRImage(t *testing.T) {
+	dir := t.TempDir()
+
+	path, err := SaveQRImage("FTestAddress", filepath.Join(dir, "qr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "qr.png"); path != want {
+		t.Fatalf("path = %q, want %q", path, want)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("\x89PNG")) {
+		t.Fatal("file is not a png")
+	}
+
+	if _, err := SaveQRImage("FTestAddress", "  "); err == nil {
+		t.Fatal("empty path accepted")
+	}
+}
@@ -35,7 +35,8 @@ func ParseLogLevel(level string) zerolog.Level {
 }
 
 // CreateFileLogger configures the global loggers writing to both console and file targets.
-func CreateFileLogger(logpath string, level zerolog.Level) zerolog.Logger {
+// The file is rotated as rotation says.
+func CreateFileLogger(logpath string, level zerolog.Level, rotation LogRotation) zerolog.Logger {
 	if level == zerolog.NoLevel {
 		level = zerolog.InfoLevel
 	}
@@ -47,7 +48,7 @@ func CreateFileLogger(logpath string, level zerolog.Level) zerolog.Logger {
 		panic(fmt.Errorf("failed to create log directory: %w", err))
 	}
 
-	logFile, err := os.OpenFile(logpath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	logFile, err := openRotatingFile(logpath, rotation)
 	if err != nil {
 		panic(fmt.Errorf("failed to open log file: %w", err))
 	}
@@ -70,7 +71,8 @@ func CreateFileLogger(logpath string, level zerolog.Level) zerolog.Logger {
 	// We only write to file to avoid corrupting the TUI.
 	multiWriter := zerolog.MultiLevelWriter(fileConsoleWriter)
 
-	logger := zerolog.New(multiWriter).With().Timestamp().Logger().Level(level)
+	// The global level alone filters, so SetLogLevel reaches every logger.
+	logger := zerolog.New(multiWriter).With().Timestamp().Logger()
 
 	// Redirect standard library generic logs to the file logger
 	stdlog.SetOutput(logFile)
@@ -91,6 +93,14 @@ func CreateFileLogger(logpath string, level zerolog.Level) zerolog.Logger {
 	return logger
 }
 
+// SetLogLevel changes the level of every logger while twallet runs.
+func SetLogLevel(level zerolog.Level) {
+	if level == zerolog.NoLevel {
+		level = zerolog.InfoLevel
+	}
+	zerolog.SetGlobalLevel(level)
+}
+
 // NamedLogger returns a child logger annotated with the given component name.
 func NamedLogger(component string) zerolog.Logger {
 	if !loggerConfigured.Load() {
@@ -0,0 +1,147 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package shared
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatedSuffix stamps a rotated log with the time it was rotated, which
+// sorts them oldest first.
+const rotatedSuffix = "20060102-150405.000"
+
+// LogRotation limits how large a log file grows and how many rotated ones
+// are kept next to it.
+type LogRotation struct {
+	// MaxSize rotates the file once it would grow past this many bytes,
+	// never when 0.
+	MaxSize int64
+	// MaxAge rotates the file once it has been written for this long,
+	// never when 0.
+	MaxAge time.Duration
+	// MaxFiles is the number of rotated files kept, all of them when 0.
+	MaxFiles int
+}
+
+// rotatingFile appends to a log file, moving it aside to <name>.<time> when
+// the rotation limits are reached.
+type rotatingFile struct {
+	path     string
+	rotation LogRotation
+	now      func() time.Time
+
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	started time.Time
+}
+
+func openRotatingFile(path string, rotation LogRotation) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, rotation: rotation, now: time.Now}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	// The file was started when the previous one was rotated.
+	r.started = r.now()
+	if rotated := r.rotated(); len(rotated) > 0 {
+		name := rotated[len(rotated)-1]
+		if t, err := time.ParseInLocation(rotatedSuffix, strings.TrimPrefix(name, filepath.Base(path)+"."), time.Local); err == nil {
+			r.started = t
+		}
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file, r.size = f, info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.due(len(p)) {
+		if err := r.rotate(); err != nil {
+			// Keep logging to the current file rather than losing lines.
+			fmt.Fprintf(os.Stderr, "log rotation failed: %v\n", err)
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// due reports whether writing n more bytes should go to a new file.
+func (r *rotatingFile) due(n int) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.rotation.MaxSize > 0 && r.size+int64(n) > r.rotation.MaxSize {
+		return true
+	}
+	return r.rotation.MaxAge > 0 && r.now().Sub(r.started) >= r.rotation.MaxAge
+}
+
+func (r *rotatingFile) rotate() error {
+	now := r.now()
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	renameErr := os.Rename(r.path, r.path+"."+now.Format(rotatedSuffix))
+	if err := r.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		return renameErr
+	}
+	r.started = now
+	r.prune()
+	return nil
+}
+
+// rotated returns the names of the rotated files, oldest first.
+func (r *rotatingFile) rotated() []string {
+	entries, err := os.ReadDir(filepath.Dir(r.path))
+	if err != nil {
+		return nil
+	}
+	prefix := filepath.Base(r.path) + "."
+	var names []string
+	for _, entry := range entries {
+		if name := entry.Name(); strings.HasPrefix(name, prefix) && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// prune removes the oldest rotated files past MaxFiles.
+func (r *rotatingFile) prune() {
+	if r.rotation.MaxFiles <= 0 {
+		return
+	}
+	rotated := r.rotated()
+	for len(rotated) > r.rotation.MaxFiles {
+		_ = os.Remove(filepath.Join(filepath.Dir(r.path), rotated[0]))
+		rotated = rotated[1:]
+	}
+}
@@ -0,0 +1,83 @@
+package shared
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "twallet.log")
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+
+	r, err := openRotatingFile(path, LogRotation{MaxSize: 10, MaxFiles: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.now = func() time.Time { clock = clock.Add(time.Second); return clock }
+
+	for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "dddddddd\n" {
+		t.Fatalf("current file = %q", data)
+	}
+	rotated := r.rotated()
+	if len(rotated) != 2 {
+		t.Fatalf("expected 2 rotated files kept, got %v", rotated)
+	}
+	oldest, err := os.ReadFile(filepath.Join(dir, rotated[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(oldest) != "bbbbbbbb\n" {
+		t.Fatalf("oldest kept file = %q, the first one should be pruned", oldest)
+	}
+}
+
+func TestRotatingFileAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "twallet.log")
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+	now := func() time.Time { return clock }
+
+	r, err := openRotatingFile(path, LogRotation{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.now, r.started = now, clock
+
+	r.Write([]byte("first\n"))
+	clock = clock.Add(30 * time.Minute)
+	r.Write([]byte("second\n"))
+	if len(r.rotated()) != 0 {
+		t.Fatal("rotated before MaxAge")
+	}
+	clock = clock.Add(31 * time.Minute)
+	r.Write([]byte("third\n"))
+	rotated := r.rotated()
+	if len(rotated) != 1 || !strings.HasPrefix(rotated[0], "twallet.log.20260102-040505") {
+		t.Fatalf("rotated = %v", rotated)
+	}
+	r.file.Close()
+
+	// A restart picks up the age of the current file from the last rotation.
+	reopened, err := openRotatingFile(path, LogRotation{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.file.Close()
+	if !reopened.started.Equal(clock.Truncate(time.Millisecond)) {
+		t.Fatalf("started = %v, want %v", reopened.started, clock)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package shared
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/flokiorg/flnd/aezeed"
+)
+
+var aezeedChecksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ValidateMnemonic checks the length, the words, the version and the
+// checksum of an English aezeed mnemonic without deciphering it, so typos are
+// caught before the wallet is asked to restore.
+func ValidateMnemonic(words []string) error {
+	if len(words) != aezeed.NumMnemonicWords {
+		return fmt.Errorf("mnemonic must have %d words, got %d", aezeed.NumMnemonicWords, len(words))
+	}
+
+	var cipherText [aezeed.EncipheredCipherSeedSize]byte
+	bit := 0
+	for i, word := range words {
+		index, ok := aezeed.ReverseWordMap[word]
+		if !ok {
+			return aezeed.ErrUnknownMnemonicWord{Word: word, Index: uint8(i)}
+		}
+		for b := aezeed.BitsPerWord - 1; b >= 0; b-- {
+			if index>>b&1 == 1 {
+				cipherText[bit/8] |= 1 << (7 - bit%8)
+			}
+			bit++
+		}
+	}
+
+	if cipherText[0] != aezeed.CipherSeedVersion {
+		return aezeed.ErrIncorrectVersion
+	}
+
+	checksumOffset := len(cipherText) - 4
+	checksum := crc32.Checksum(cipherText[:checksumOffset], aezeedChecksumTable)
+	if checksum != binary.BigEndian.Uint32(cipherText[checksumOffset:]) {
+		return aezeed.ErrIncorrectMnemonic
+	}
+
+	return nil
+}
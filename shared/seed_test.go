@@ -0,0 +1,46 @@
+package shared
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flokiorg/flnd/aezeed"
+)
+
+func TestValidateMnemonic(t *testing.T) {
+	var entropy [aezeed.EntropySize]byte
+	seed, err := aezeed.New(aezeed.CipherSeedVersion, &entropy, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mnemonic, err := seed.ToMnemonic(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	words := mnemonic[:]
+	if err := ValidateMnemonic(words); err != nil {
+		t.Fatalf("valid mnemonic rejected: %v", err)
+	}
+
+	if err := ValidateMnemonic(words[:12]); err == nil {
+		t.Fatal("short mnemonic accepted")
+	}
+
+	typo := append([]string(nil), words...)
+	typo[5] = "notaword"
+	var unknown aezeed.ErrUnknownMnemonicWord
+	if err := ValidateMnemonic(typo); !errors.As(err, &unknown) || unknown.Index != 5 {
+		t.Fatalf("expected unknown word at index 5, got %v", err)
+	}
+
+	swapped := append([]string(nil), words...)
+	swapped[10], swapped[11] = swapped[11], swapped[10]
+	if swapped[10] == swapped[11] {
+		t.Skip("swapped words are identical")
+	}
+	if err := ValidateMnemonic(swapped); !errors.Is(err, aezeed.ErrIncorrectMnemonic) && !errors.Is(err, aezeed.ErrIncorrectVersion) {
+		t.Fatalf("expected checksum error, got %v", err)
+	}
+}
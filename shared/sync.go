@@ -0,0 +1,26 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package shared
+
+import "time"
+
+// ChainSyncProgress estimates how far header sync has come, from 0 to 1, by
+// placing the best header time between the genesis block and now. Block
+// heights cannot be used since a light client does not know the chain tip
+// until it has caught up.
+func ChainSyncProgress(genesis, header, now time.Time) float64 {
+	total := now.Sub(genesis)
+	if total <= 0 || header.IsZero() {
+		return 0
+	}
+	progress := float64(header.Sub(genesis)) / float64(total)
+	switch {
+	case progress < 0:
+		return 0
+	case progress > 1:
+		return 1
+	}
+	return progress
+}
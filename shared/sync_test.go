@@ -0,0 +1,26 @@
+package shared
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChainSyncProgress(t *testing.T) {
+	genesis := time.Unix(1700000000, 0)
+	now := genesis.Add(100 * time.Hour)
+
+	tests := []struct {
+		header time.Time
+		want   float64
+	}{
+		{time.Time{}, 0},
+		{genesis.Add(-time.Hour), 0},
+		{genesis.Add(25 * time.Hour), 0.25},
+		{now.Add(time.Hour), 1},
+	}
+	for _, tc := range tests {
+		if got := ChainSyncProgress(genesis, tc.header, now); got != tc.want {
+			t.Errorf("ChainSyncProgress(%v) = %v, want %v", tc.header, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package shared
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const DefaultThemeName = "default"
+
+// Theme groups every color the TUI draws with so the whole interface can be
+// restyled from the config file.
+type Theme struct {
+	Name string
+
+	Background         tcell.Color
+	ContrastBackground tcell.Color
+	Text               tcell.Color
+	Border             tcell.Color
+	Accent             tcell.Color
+	Muted              tcell.Color
+	Highlight          tcell.Color
+	Primary            tcell.Color
+
+	Modal      tcell.Color
+	ModalTitle tcell.Color
+
+	Selection     tcell.Color
+	SelectionText tcell.Color
+
+	Success tcell.Color
+	Warning tcell.Color
+	Error   tcell.Color
+
+	Mainnet tcell.Color
+	Testnet tcell.Color
+	Regtest tcell.Color
+}
+
+var themePresets = map[string]Theme{
+	DefaultThemeName: {
+		Name:               DefaultThemeName,
+		Background:         tcell.ColorBlack,
+		ContrastBackground: tcell.ColorGray,
+		Text:               tcell.ColorWhite,
+		Border:             tcell.ColorWhite,
+		Accent:             tcell.ColorLightSkyBlue,
+		Muted:              tcell.ColorGray,
+		Highlight:          tcell.ColorWhiteSmoke,
+		Primary:            tcell.ColorOrange,
+		Modal:              tcell.ColorOrange,
+		ModalTitle:         tcell.ColorGray,
+		Selection:          tcell.ColorPurple,
+		SelectionText:      tcell.ColorWhite,
+		Success:            tcell.ColorGreen,
+		Warning:            tcell.ColorYellow,
+		Error:              tcell.ColorRed,
+		Mainnet:            tcell.ColorOrange,
+		Testnet:            tcell.ColorRed,
+		Regtest:            tcell.ColorYellowGreen,
+	},
+	"light": {
+		Name:               "light",
+		Background:         tcell.ColorWhite,
+		ContrastBackground: tcell.ColorLightGray,
+		Text:               tcell.ColorBlack,
+		Border:             tcell.ColorBlack,
+		Accent:             tcell.ColorNavy,
+		Muted:              tcell.ColorDimGray,
+		Highlight:          tcell.ColorBlack,
+		Primary:            tcell.ColorDarkOrange,
+		Modal:              tcell.ColorDarkOrange,
+		ModalTitle:         tcell.ColorBlack,
+		Selection:          tcell.ColorLightSteelBlue,
+		SelectionText:      tcell.ColorBlack,
+		Success:            tcell.ColorDarkGreen,
+		Warning:            tcell.ColorDarkGoldenrod,
+		Error:              tcell.ColorDarkRed,
+		Mainnet:            tcell.ColorDarkOrange,
+		Testnet:            tcell.ColorDarkRed,
+		Regtest:            tcell.ColorOliveDrab,
+	},
+	"mono": {
+		Name:               "mono",
+		Background:         tcell.ColorBlack,
+		ContrastBackground: tcell.ColorDimGray,
+		Text:               tcell.ColorWhite,
+		Border:             tcell.ColorWhite,
+		Accent:             tcell.ColorWhite,
+		Muted:              tcell.ColorGray,
+		Highlight:          tcell.ColorWhite,
+		Primary:            tcell.ColorWhite,
+		Modal:              tcell.ColorDimGray,
+		ModalTitle:         tcell.ColorWhite,
+		Selection:          tcell.ColorWhite,
+		SelectionText:      tcell.ColorBlack,
+		Success:            tcell.ColorWhite,
+		Warning:            tcell.ColorSilver,
+		Error:              tcell.ColorWhite,
+		Mainnet:            tcell.ColorWhite,
+		Testnet:            tcell.ColorSilver,
+		Regtest:            tcell.ColorGray,
+	},
+}
+
+var (
+	themeMu     sync.RWMutex
+	activeTheme = themePresets[DefaultThemeName]
+)
+
+// ThemePresetNames lists the built-in themes in a stable order.
+func ThemePresetNames() []string {
+	names := make([]string, 0, len(themePresets))
+	for name := range themePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ThemePreset returns the built-in theme registered under name.
+func ThemePreset(name string) (Theme, error) {
+	t, ok := themePresets[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(ThemePresetNames(), ", "))
+	}
+	return t, nil
+}
+
+// CurrentTheme returns the theme in use.
+func CurrentTheme() Theme {
+	themeMu.RLock()
+	defer themeMu.RUnlock()
+	return activeTheme
+}
+
+// ApplyTheme makes t the active theme and pushes it into tview's global
+// styles. It must run before any primitive is created.
+func ApplyTheme(t Theme) {
+	themeMu.Lock()
+	activeTheme = t
+	themeMu.Unlock()
+
+	tview.Styles = tview.Theme{
+		PrimitiveBackgroundColor:    t.Background,
+		ContrastBackgroundColor:     t.ContrastBackground,
+		MoreContrastBackgroundColor: t.Modal,
+		BorderColor:                 t.Border,
+		TitleColor:                  t.Text,
+		GraphicsColor:               t.Border,
+		PrimaryTextColor:            t.Text,
+		SecondaryTextColor:          t.Text,
+		TertiaryTextColor:           t.Success,
+		InverseTextColor:            tcell.ColorBlue,
+		ContrastSecondaryTextColor:  tcell.ColorDarkSlateGray,
+	}
+}
+
+// ParseThemeColor accepts a W3C color name or a #rrggbb value.
+func ParseThemeColor(value string) (tcell.Color, error) {
+	value = strings.TrimSpace(value)
+	color := tcell.GetColor(strings.ToLower(value))
+	if color == tcell.ColorDefault && !strings.EqualFold(value, "default") {
+		return tcell.ColorDefault, fmt.Errorf("invalid color %q", value)
+	}
+	return color, nil
+}
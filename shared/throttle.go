@@ -0,0 +1,89 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package shared
+
+import (
+	"sync"
+	"time"
+)
+
+// Throttle runs a function at most once per interval however often it is
+// triggered. A trigger inside the interval is not lost: the triggers of a
+// burst coalesce into a single run at the end of the interval.
+type Throttle struct {
+	interval time.Duration
+	fn       func()
+
+	mu      sync.Mutex
+	last    time.Time
+	timer   *time.Timer
+	running bool
+	again   bool
+	stopped bool
+}
+
+func NewThrottle(interval time.Duration, fn func()) *Throttle {
+	return &Throttle{interval: interval, fn: fn}
+}
+
+// Trigger runs the function now if it has not run for an interval, and
+// schedules a run at the end of the interval otherwise.
+func (t *Throttle) Trigger() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case t.stopped || t.timer != nil:
+		return
+	case t.running:
+		t.again = true
+		return
+	}
+
+	if wait := t.interval - time.Since(t.last); wait > 0 {
+		t.timer = time.AfterFunc(wait, t.fire)
+		return
+	}
+	t.running = true
+	go t.run()
+}
+
+func (t *Throttle) fire() {
+	t.mu.Lock()
+	t.timer = nil
+	if t.stopped {
+		t.mu.Unlock()
+		return
+	}
+	t.running = true
+	t.mu.Unlock()
+	t.run()
+}
+
+// run calls the function, and once more at the end of the interval if it
+// was triggered meanwhile.
+func (t *Throttle) run() {
+	t.fn()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running = false
+	t.last = time.Now()
+	if t.again && !t.stopped {
+		t.again = false
+		t.timer = time.AfterFunc(t.interval, t.fire)
+	}
+}
+
+// Stop drops the scheduled run, if any, and ignores later triggers.
+func (t *Throttle) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopped = true
+	if t.timer != nil {
+		t.timer.Stop()
+		t.timer = nil
+	}
+}
@@ -0,0 +1,49 @@
+package shared
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestThrottleCoalescesBurst(t *testing.T) {
+	var runs atomic.Int32
+	th := NewThrottle(100*time.Millisecond, func() { runs.Add(1) })
+	defer th.Stop()
+
+	for range 20 {
+		th.Trigger()
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("runs during the burst = %d, want 1", got)
+	}
+
+	// The triggers after the first run coalesce into a single trailing one.
+	time.Sleep(100 * time.Millisecond)
+	if got := runs.Load(); got != 2 {
+		t.Fatalf("runs after the burst = %d, want 2", got)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if got := runs.Load(); got != 2 {
+		t.Fatalf("runs without triggers = %d, want 2", got)
+	}
+}
+
+func TestThrottleStop(t *testing.T) {
+	var runs atomic.Int32
+	th := NewThrottle(20*time.Millisecond, func() { runs.Add(1) })
+
+	th.Trigger()
+	time.Sleep(5 * time.Millisecond)
+	th.Trigger()
+	th.Stop()
+	th.Trigger()
+
+	time.Sleep(50 * time.Millisecond)
+	if got := runs.Load(); got != 1 {
+		t.Fatalf("runs = %d, want only the one before Stop", got)
+	}
+}
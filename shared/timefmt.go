@@ -0,0 +1,88 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package shared
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeLayout is how timestamps are shown without time.format.
+const DefaultTimeLayout = "2006-01-02 15:04:05"
+
+var (
+	activeTimeZone   atomic.Pointer[time.Location]
+	activeTimeLayout atomic.Pointer[string]
+)
+
+// ParseTimeZone returns the zone a time.zone value names: local, utc or an
+// IANA zone name.
+func ParseTimeZone(name string) (*time.Location, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "local":
+		return time.Local, nil
+	case "utc":
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(strings.TrimSpace(name))
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q (available: local, utc or a zone name such as Europe/Berlin)", name)
+	}
+	return loc, nil
+}
+
+// ValidateTimeLayout rejects the layouts that would show every timestamp
+// the same, having none of the elements of the reference time.
+func ValidateTimeLayout(layout string) error {
+	// Any time other than the reference one changes every element.
+	probe := time.Date(2011, time.November, 12, 13, 14, 15, 0, time.UTC)
+	if strings.TrimSpace(layout) == "" || probe.Format(layout) == layout {
+		return fmt.Errorf("time format %q has no date or time element, write it with the reference time, e.g. %s", layout, DefaultTimeLayout)
+	}
+	return nil
+}
+
+// TimeZone is the zone timestamps are shown, exported and grouped into days
+// in.
+func TimeZone() *time.Location {
+	if loc := activeTimeZone.Load(); loc != nil {
+		return loc
+	}
+	return time.Local
+}
+
+func SetTimeZone(loc *time.Location) {
+	activeTimeZone.Store(loc)
+}
+
+// TimeLayout is the layout timestamps are shown with.
+func TimeLayout() string {
+	if layout := activeTimeLayout.Load(); layout != nil {
+		return *layout
+	}
+	return DefaultTimeLayout
+}
+
+func SetTimeLayout(layout string) {
+	activeTimeLayout.Store(&layout)
+}
+
+// FormatTime shows t in the configured zone and layout.
+func FormatTime(t time.Time) string {
+	return t.In(TimeZone()).Format(TimeLayout())
+}
+
+// FormatUnixTime is FormatTime for a timestamp in seconds.
+func FormatUnixTime(ts int64) string {
+	return FormatTime(time.Unix(ts, 0))
+}
+
+// FormatExportTime writes t in the configured zone as RFC 3339, whatever the
+// layout shown, so the exported files parse back.
+func FormatExportTime(t time.Time) string {
+	return t.In(TimeZone()).Format(time.RFC3339)
+}
@@ -0,0 +1,65 @@
+package shared
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeFormat(t *testing.T) {
+	defer SetTimeZone(time.Local)
+	defer SetTimeLayout(DefaultTimeLayout)
+
+	ts := time.Date(2024, 3, 10, 23, 30, 0, 0, time.UTC)
+
+	utc, err := ParseTimeZone("UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetTimeZone(utc)
+	if got := FormatTime(ts); got != "2024-03-10 23:30:00" {
+		t.Fatalf("FormatTime = %q", got)
+	}
+	if got := FormatExportTime(ts); got != "2024-03-10T23:30:00Z" {
+		t.Fatalf("FormatExportTime = %q", got)
+	}
+
+	tokyo, err := ParseTimeZone("Asia/Tokyo")
+	if err != nil {
+		t.Skip("no zone database:", err)
+	}
+	SetTimeZone(tokyo)
+	SetTimeLayout("02 Jan 2006 15:04 MST")
+	if got := FormatUnixTime(ts.Unix()); got != "11 Mar 2024 08:30 JST" {
+		t.Fatalf("FormatUnixTime = %q", got)
+	}
+	if got := FormatExportTime(ts); got != "2024-03-11T08:30:00+09:00" {
+		t.Fatalf("FormatExportTime = %q, want RFC 3339 whatever the layout", got)
+	}
+	// Days are the ones of the zone: 23:30 UTC is already the 11th in Tokyo.
+	if !NewDateRange(ts, ts).Contains(time.Date(2024, 3, 11, 12, 0, 0, 0, tokyo)) {
+		t.Fatal("the date range should follow the time zone")
+	}
+}
+
+func TestParseTimeSettings(t *testing.T) {
+	for _, name := range []string{"", "local", "Local"} {
+		if loc, err := ParseTimeZone(name); err != nil || loc != time.Local {
+			t.Errorf("ParseTimeZone(%q) = %v, %v", name, loc, err)
+		}
+	}
+	if _, err := ParseTimeZone("Mars/Olympus"); err == nil {
+		t.Error("an unknown zone should fail")
+	}
+
+	for layout, ok := range map[string]bool{
+		DefaultTimeLayout:       true,
+		"02 Jan 2006 15:04 MST": true,
+		time.Kitchen:            true,
+		"":                      false,
+		"yyyy-mm-dd":            false,
+	} {
+		if err := ValidateTimeLayout(layout); (err == nil) != ok {
+			t.Errorf("ValidateTimeLayout(%q) = %v", layout, err)
+		}
+	}
+}
@@ -6,13 +6,18 @@ package tui
 
 import (
 	"os"
+	"sync"
 	"time"
 
-	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
 	"github.com/flokiorg/twallet/config"
 	"github.com/flokiorg/twallet/flnd"
+	"github.com/flokiorg/twallet/load"
+	"github.com/flokiorg/twallet/metrics"
+	"github.com/flokiorg/twallet/pages"
+	"github.com/flokiorg/twallet/shared"
+	"github.com/gdamore/tcell/v2"
 )
 
 func init() {
@@ -23,19 +28,7 @@ func init() {
 	tview.Borders.BottomLeftFocus = tview.BoxDrawingsLightUpAndRight
 	tview.Borders.BottomRightFocus = tview.BoxDrawingsLightUpAndLeft
 
-	tview.Styles = tview.Theme{
-		PrimitiveBackgroundColor:    tcell.ColorBlack,
-		ContrastBackgroundColor:     tcell.ColorGray,
-		MoreContrastBackgroundColor: tcell.ColorOrange,
-		BorderColor:                 tcell.ColorWhite,
-		TitleColor:                  tcell.ColorWhite,
-		GraphicsColor:               tcell.ColorWhite,
-		PrimaryTextColor:            tcell.ColorWhite,
-		SecondaryTextColor:          tcell.ColorWhite,
-		TertiaryTextColor:           tcell.ColorGreen,
-		InverseTextColor:            tcell.ColorBlue,
-		ContrastSecondaryTextColor:  tcell.ColorDarkSlateGray,
-	}
+	shared.ApplyTheme(shared.CurrentTheme())
 }
 
 type App struct {
@@ -43,10 +36,25 @@ type App struct {
 	pages            *tview.Pages
 	cfg              *config.AppConfig
 	flnsvc           *flnd.Service
+	metrics          *metrics.Server
+	health           *metrics.Server
 	recoveryRequests chan struct{}
-	bootLog          chan string
+	bootLog          chan pages.BootEvent
 	autoRecover      bool
 	restartRecovery  bool
+	shuttingDown     bool
+	closeOnce        sync.Once
+
+	// screen is the screen the application runs on, see Run.
+	screen tcell.Screen
+
+	// load is set once the wallet pages are up.
+	load *load.Load
+
+	// configBase is the config file as last read, see watchConfig.
+	configBase     *config.AppConfig
+	reloadSignals  chan os.Signal
+	pendingRestart []string
 }
 
 func NewApp(cfg *config.AppConfig) *App {
@@ -60,13 +68,41 @@ func NewApp(cfg *config.AppConfig) *App {
 
 	app.EnablePaste(true).EnableMouse(true)
 	app.SetInputCapture(app.captureStartupKeys)
+	app.interceptQuit()
 
 	app.startBoot()
 	app.startAutoRefreshLoop()
+	app.watchConfig()
 
 	return app
 }
 
+// Run runs the application on a screen made here instead of by tview, which
+// does not give its screen out, so the wallet pages can ring the bell.
+func (app *App) Run() error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+	if err := screen.Init(); err != nil {
+		return err
+	}
+	// tview turns these on only for the screens it makes.
+	screen.EnableMouse()
+	screen.EnablePaste()
+	app.screen = screen
+	app.SetScreen(initializedScreen{screen})
+	return app.Application.Run()
+}
+
+// initializedScreen is a screen Init was already called on. SetScreen calls
+// Init again and drops its error.
+type initializedScreen struct {
+	tcell.Screen
+}
+
+func (initializedScreen) Init() error { return nil }
+
 func (app *App) startAutoRefreshLoop() {
 	if app.cfg.AutoRefreshInterval <= 0 {
 		return
@@ -80,10 +116,60 @@ func (app *App) startAutoRefreshLoop() {
 	}()
 }
 
+// Close stops the servers and the wallet service. The daemon gets
+// ShutdownTimeout to stop.
 func (app *App) Close() {
-	if app.flnsvc != nil {
-		app.flnsvc.Stop()
+	app.closeOnce.Do(func() {
+		app.stopWatchingConfig()
+		if app.metrics != nil {
+			app.metrics.Stop()
+		}
+		if app.health != nil {
+			app.health.Stop()
+		}
+		if app.flnsvc != nil {
+			if err := app.flnsvc.StopWithTimeout(app.cfg.ShutdownTimeout); err != nil {
+				logger := shared.NamedLogger("shutdown")
+				logger.Warn().Err(err).Dur("timeout", app.cfg.ShutdownTimeout).Msg("exiting without waiting for the daemon")
+			}
+		}
+	})
+}
+
+// interceptQuit makes Ctrl+C shut down through shutdown, in front of the
+// input capture already set.
+func (app *App) interceptQuit() {
+	next := app.GetInputCapture()
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlC {
+			app.shutdown()
+			return nil
+		}
+		if next != nil {
+			return next(event)
+		}
+		return event
+	})
+}
+
+// shutdown closes the application behind a shutdown screen, so it does not
+// look hung while the daemon stops.
+func (app *App) shutdown() {
+	if app.shuttingDown {
+		return
 	}
+	app.shuttingDown = true
+
+	view := tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetText("\n\nShutting down… (waiting on daemon)")
+	view.SetBackgroundColor(tcell.ColorDefault)
+	app.pages.AddAndSwitchToPage("shutdown", view, true)
+
+	go func() {
+		app.Close()
+		app.Stop()
+	}()
 }
 
 func (app *App) ShouldRestartForRecovery() bool {
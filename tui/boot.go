@@ -16,7 +16,9 @@ import (
 
 	"github.com/flokiorg/twallet/flnd"
 	"github.com/flokiorg/twallet/load"
+	"github.com/flokiorg/twallet/metrics"
 	"github.com/flokiorg/twallet/pages"
+	"github.com/flokiorg/twallet/shared"
 	"github.com/flokiorg/twallet/utils"
 )
 
@@ -27,8 +29,8 @@ const (
 )
 
 func (app *App) startBoot() {
-	bootText, splashscreen := pages.SplashScreen(app.Application)
-	app.bootLog = bootText
+	bootEvents, splashscreen := pages.SplashScreen(app.Application)
+	app.bootLog = bootEvents
 	app.pages.AddPage("splashscreen", splashscreen, true, true).
 		AddPage("reloading", pages.ReloadingScreen(), true, false)
 
@@ -70,7 +72,9 @@ bootLoop:
 		}
 
 		if app.flnsvc == nil {
+			app.progress(pages.BootStageDaemon, -1, "starting")
 			app.flnsvc = flnd.New(context.Background(), &app.cfg.ServiceConfig)
+			app.startHealth()
 		}
 
 		sub := app.flnsvc.Subscribe()
@@ -93,7 +97,11 @@ bootLoop:
 				}
 
 				switch update.State {
-				case flnd.StatusNone, flnd.StatusInit:
+				case flnd.StatusInit:
+					app.progress(pages.BootStageDaemon, -1, "starting")
+					continue
+				case flnd.StatusNone:
+					app.progress(pages.BootStageChainSync, -1, "waiting")
 					continue
 				case flnd.StatusDown:
 					msg := "wallet reported down during startup"
@@ -123,10 +131,28 @@ bootLoop:
 					app.log(fmt.Sprintf("[red:-:-]Error:[-:-:-] %s. Press Ctrl+C to quit. If it keeps happening, reach out to the Lokichain community.", msg))
 					app.stopService()
 					return
+				case flnd.StatusFailedPermanently:
+					app.flnsvc.Unsubscribe(sub)
+					app.log(load.FailedPermanentlyGuidance(update.Err) + " Press Ctrl+C to quit.")
+					app.stopService()
+					return
 				case flnd.StatusQuit:
 					app.stopService()
 					return
+				case flnd.StatusUnlocked:
+					// Unlocked at start, the chain sync comes next.
+					app.progress(pages.BootStageChainSync, -1, "waiting")
+					continue
+				case flnd.StatusSyncing:
+					// The splash screen stays until the chain is synced, its
+					// progress moving with every update.
+					app.progress(pages.BootStageChainSync, app.syncProgress(update), "")
+					continue
+				case flnd.StatusBalance, flnd.StatusTransaction, flnd.StatusBlock, flnd.StatusScanning:
+					// Not a state of the wallet, the sync goes on.
+					continue
 				default:
+					app.progress(pages.BootStageWallet, 1, bootWalletDetail(update.State))
 					app.flnsvc.Unsubscribe(sub)
 					app.launchMain()
 					return
@@ -204,13 +230,45 @@ func (app *App) log(msg string) {
 	if strings.TrimSpace(msg) == "" {
 		return
 	}
+	app.sendBootEvent(pages.BootEvent{Message: msg})
+}
+
+// progress moves the splash screen progress indicator to stage.
+func (app *App) progress(stage pages.BootStage, progress float64, detail string) {
+	app.sendBootEvent(pages.BootEvent{Stage: stage, Progress: progress, Detail: detail})
+}
+
+func (app *App) sendBootEvent(ev pages.BootEvent) {
 	defer func() {
 		if recover() != nil {
-			// Channel closed; drop the log to avoid crashing during shutdown.
+			// Channel closed; drop the event to avoid crashing during shutdown.
 		}
 	}()
 	if app.bootLog != nil {
-		app.bootLog <- msg
+		app.bootLog <- ev
+	}
+}
+
+// syncProgress estimates the neutrino header sync progress of a syncing
+// update, or returns -1 when the daemon has not reported a header yet.
+func (app *App) syncProgress(update *flnd.Update) float64 {
+	if update.BestHeaderTimestamp <= 0 || app.cfg.Network == nil {
+		return -1
+	}
+	genesis := app.cfg.Network.GenesisBlock.Header.Timestamp
+	return shared.ChainSyncProgress(genesis, time.Unix(update.BestHeaderTimestamp, 0), time.Now())
+}
+
+func bootWalletDetail(state flnd.Status) string {
+	switch state {
+	case flnd.StatusLocked:
+		return "locked"
+	case flnd.StatusNoWallet:
+		return "not found"
+	case flnd.StatusUnlocked:
+		return "unlocked"
+	default:
+		return "ready"
 	}
 }
 
@@ -254,6 +312,7 @@ func (app *App) recoverWallet(reason string) error {
 
 	app.log("[gray]Restarting wallet service…")
 	app.flnsvc = flnd.New(context.Background(), &app.cfg.ServiceConfig)
+	app.startHealth()
 
 	health, err := load.CheckWalletHealth(context.Background(), app.flnsvc, startupHealthTimeout)
 	if err != nil {
@@ -276,8 +335,47 @@ func (app *App) recoverWallet(reason string) error {
 }
 
 func (app *App) launchMain() {
+	app.startMetrics()
 	app.QueueUpdateDraw(func() {
-		loader := load.NewLoad(app.cfg, app.flnsvc, app.Application, app.pages)
+		if app.shuttingDown {
+			return
+		}
+		loader := load.NewLoad(app.cfg, app.flnsvc, app.Application, app.screen, app.pages)
+		app.load = loader
 		app.pages.AddAndSwitchToPage("main", pages.NewEntrypoint(loader), true)
+		app.interceptQuit()
 	})
 }
+
+func (app *App) startMetrics() {
+	if app.cfg.MetricsListen == "" || app.metrics != nil {
+		return
+	}
+	logger := shared.NamedLogger("metrics")
+	server, err := metrics.Start(app.cfg.MetricsListen, app.flnsvc, logger)
+	if err != nil {
+		logger.Error().Err(err).Msg("unable to serve metrics")
+		return
+	}
+	app.metrics = server
+}
+
+// startHealth serves /healthz for the current service. It runs from boot so
+// supervisors can probe the wallet while it starts, and again when recovery
+// replaces the service.
+func (app *App) startHealth() {
+	if app.cfg.HealthListen == "" {
+		return
+	}
+	if app.health != nil {
+		app.health.Stop()
+		app.health = nil
+	}
+	logger := shared.NamedLogger("health")
+	server, err := metrics.StartHealth(app.cfg.HealthListen, app.flnsvc, logger)
+	if err != nil {
+		logger.Error().Err(err).Msg("unable to serve health endpoint")
+		return
+	}
+	app.health = server
+}
@@ -0,0 +1,281 @@
+// Copyright (c) 2024 The Flokicoin developers
+// Distributed under the MIT software license, see the accompanying
+// file COPYING or http://www.opensource.org/licenses/mit-license.php.
+
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/flokiorg/twallet/config"
+	"github.com/flokiorg/twallet/flnd"
+	"github.com/flokiorg/twallet/shared"
+)
+
+// liveOptions are applied as soon as the config file is reloaded. The
+// theme.* colors go through the theme applier.
+var liveOptions = map[string]func(app *App, updated *config.AppConfig) error{
+	"loglevel":                (*App).applyLogLevel,
+	"feeurl":                  (*App).applyFeeURL,
+	"feeurl.fallback":         (*App).applyFeeURL,
+	"transactiondisplaylimit": (*App).applyDisplayLimit,
+	"theme":                   (*App).applyTheme,
+	"explorerurl": func(app *App, updated *config.AppConfig) error {
+		app.cfg.ExplorerURL = updated.ExplorerURL
+		return nil
+	},
+	"largesend": func(app *App, updated *config.AppConfig) error {
+		app.cfg.LargeSend = updated.LargeSend
+		return nil
+	},
+	"rpctimeout":              (*App).applyRPCTimeouts,
+	"rpctimeout.transactions": (*App).applyRPCTimeouts,
+	"rpctimeout.fees":         (*App).applyRPCTimeouts,
+}
+
+// daemonOptions are handed to the daemon, which starts with them the next
+// time it restarts.
+var daemonOptions = map[string]func(c *flnd.ConfigChange, updated *config.AppConfig){
+	"connect":    func(c *flnd.ConfigChange, u *config.AppConfig) { c.ConnectPeers = &u.ConnectPeers },
+	"addpeer":    func(c *flnd.ConfigChange, u *config.AppConfig) { c.AddPeers = &u.AddPeers },
+	"alias":      func(c *flnd.ConfigChange, u *config.AppConfig) { c.Alias = &u.Alias },
+	"rpclisten":  func(c *flnd.ConfigChange, u *config.AppConfig) { c.RawRPCListeners = &u.RawRPCListeners },
+	"restlisten": func(c *flnd.ConfigChange, u *config.AppConfig) { c.RawRESTListeners = &u.RawRESTListeners },
+	"listen":     func(c *flnd.ConfigChange, u *config.AppConfig) { c.RawListeners = &u.RawListeners },
+}
+
+// reloadResult sorts the options of a reload by how they took effect.
+type reloadResult struct {
+	applied []string
+	daemon  []string
+	failed  []string
+}
+
+// watchConfig reloads the config file on SIGHUP. The file as read now is
+// the reference the next reload is compared to.
+func (app *App) watchConfig() {
+	if app.cfg.ConfigFile == "" {
+		return
+	}
+	base, err := app.readConfigFile()
+	if err != nil {
+		logger := shared.NamedLogger("config")
+		logger.Warn().Err(err).Msg("config reload is off")
+		return
+	}
+	app.configBase = base
+
+	app.reloadSignals = make(chan os.Signal, 1)
+	signal.Notify(app.reloadSignals, syscall.SIGHUP)
+	go func() {
+		for range app.reloadSignals {
+			app.reloadConfig()
+		}
+	}()
+}
+
+func (app *App) stopWatchingConfig() {
+	if app.reloadSignals != nil {
+		signal.Stop(app.reloadSignals)
+		close(app.reloadSignals)
+	}
+}
+
+// readConfigFile reads the config file with its encrypted options opened,
+// so they compare with the running ones. The section read is the one of the
+// running network, which the command line may have chosen.
+func (app *App) readConfigFile() (*config.AppConfig, error) {
+	cfg, err := config.ReadProfile(app.cfg.ConfigFile, app.cfg.Profile, app.cfg.NetworkName)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.DecryptSecrets(app.cfg.SecretKeys()); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// reloadConfig applies the options changed in the config file since it was
+// last read, on the UI goroutine as the pages read them there.
+func (app *App) reloadConfig() {
+	logger := shared.NamedLogger("config")
+	updated, err := app.readConfigFile()
+	if err != nil {
+		logger.Error().Err(err).Msg("config reload failed")
+		app.notifyReload(fmt.Sprintf("[red:-:-]Config reload failed:[-:-:-] %s", err.Error()))
+		return
+	}
+
+	app.QueueUpdateDraw(func() {
+		changes := config.Changes(app.configBase, updated)
+		app.configBase = updated
+		// Options the UI saved itself, like the columns, are already in
+		// effect.
+		current := map[string]bool{}
+		for _, c := range config.Changes(app.cfg, updated) {
+			current[c.Key] = true
+		}
+		changes = slices.DeleteFunc(changes, func(c config.Change) bool { return !current[c.Key] })
+		if len(changes) == 0 {
+			logger.Info().Msg("config reloaded, nothing changed")
+			return
+		}
+
+		result := app.applyChanges(changes, updated)
+		for _, c := range changes {
+			logger.Info().Str("option", c.Key).Str("old", c.Old).Str("new", c.New).Msg("config option changed")
+		}
+		app.notifyReload(result.summary(app.pendingRestart))
+	})
+}
+
+func (app *App) applyChanges(changes []config.Change, updated *config.AppConfig) reloadResult {
+	var result reloadResult
+	var daemon flnd.ConfigChange
+	applied := map[string]error{}
+
+	for _, c := range changes {
+		handled := false
+		applier := c.Key
+		if strings.HasPrefix(applier, "theme.") {
+			applier = "theme"
+		}
+		if apply, ok := liveOptions[applier]; ok {
+			handled = true
+			err, done := applied[applier]
+			if !done {
+				err = apply(app, updated)
+				applied[applier] = err
+			}
+			if err != nil {
+				result.failed = append(result.failed, fmt.Sprintf("%s (%s)", c.Key, err.Error()))
+				continue
+			}
+			result.applied = append(result.applied, c.Key)
+		}
+		if set, ok := daemonOptions[c.Key]; ok {
+			handled = true
+			set(&daemon, updated)
+			result.daemon = append(result.daemon, c.Key)
+		}
+		if !handled && !slices.Contains(app.pendingRestart, c.Key) {
+			app.pendingRestart = append(app.pendingRestart, c.Key)
+		}
+	}
+
+	if len(result.daemon) > 0 {
+		if app.flnsvc == nil {
+			result.failed = append(result.failed, strings.Join(result.daemon, ", ")+" (the daemon is not running)")
+			result.daemon = nil
+		} else if err := app.flnsvc.QueueConfig(daemon); err != nil {
+			result.failed = append(result.failed, err.Error())
+			result.daemon = nil
+		}
+	}
+	return result
+}
+
+// summary reports what the reload changed and what waits for a restart,
+// pending listing the options only a restart of twallet applies.
+func (r reloadResult) summary(pending []string) string {
+	lines := []string{"[green:-:-]Config reloaded.[-:-:-]"}
+	if len(r.applied) > 0 {
+		lines = append(lines, "Applied: "+strings.Join(r.applied, ", "))
+	}
+	if len(r.daemon) > 0 {
+		lines = append(lines, "[yellow:-:-]On the next daemon restart:[-:-:-] "+strings.Join(r.daemon, ", "))
+	}
+	if len(pending) > 0 {
+		lines = append(lines, "[yellow:-:-]Restart twallet to apply:[-:-:-] "+strings.Join(pending, ", "))
+	}
+	if len(r.failed) > 0 {
+		lines = append(lines, "[red:-:-]Not applied:[-:-:-] "+strings.Join(r.failed, "; "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// notifyReload shows text over the wallet once it is loaded, and logs it
+// to the boot screen before.
+func (app *App) notifyReload(text string) {
+	if app.load == nil {
+		app.log(text)
+		return
+	}
+	app.load.Notif.ShowToastWithTimeout(text, 15*time.Second)
+}
+
+func (app *App) applyLogLevel(updated *config.AppConfig) error {
+	app.cfg.LogLevel = updated.LogLevel
+	shared.SetLogLevel(shared.ParseLogLevel(updated.LogLevel))
+	return nil
+}
+
+// applyFeeURL points the daemon and the fee monitor at the new fee APIs,
+// through the failover of the service.
+func (app *App) applyFeeURL(updated *config.AppConfig) error {
+	for _, source := range flnd.FeeSources(updated.Feeurl, updated.FeeURLFallbacks) {
+		if err := (flnd.ConfigChange{Feeurl: &source}).Validate(); err != nil {
+			return err
+		}
+	}
+	app.cfg.Feeurl = updated.Feeurl
+	app.cfg.FeeURLFallbacks = updated.FeeURLFallbacks
+	if app.flnsvc == nil {
+		return nil
+	}
+	app.flnsvc.SetFeeSources(updated.Feeurl, updated.FeeURLFallbacks)
+	if app.load != nil && app.load.Fees != nil && app.flnsvc.FeeURL() != "" {
+		app.load.Fees.SetURL(app.flnsvc.FeeURL())
+	}
+	return nil
+}
+
+func (app *App) applyDisplayLimit(updated *config.AppConfig) error {
+	limit := updated.TransactionDisplayLimit
+	if limit <= 0 {
+		limit = config.DefaultTransactionDisplayLimit
+	}
+	app.cfg.TransactionDisplayLimit = limit
+	if app.flnsvc != nil {
+		app.flnsvc.SetMaxTransactionsLimit(uint32(limit))
+	}
+	if app.load != nil {
+		app.load.BroadcastBalanceRefresh()
+	}
+	return nil
+}
+
+// applyRPCTimeouts bounds the next daemon calls with the updated timeouts.
+func (app *App) applyRPCTimeouts(updated *config.AppConfig) error {
+	app.cfg.RPCTimeout = updated.RPCTimeout
+	app.cfg.RPCTimeoutTransactions = updated.RPCTimeoutTransactions
+	app.cfg.RPCTimeoutFees = updated.RPCTimeoutFees
+	if app.flnsvc != nil {
+		app.flnsvc.SetRPCTimeouts(app.cfg.RPCTimeouts())
+	}
+	return nil
+}
+
+// applyTheme changes the colors the views read as they draw. Views that
+// took theirs when created keep them until they are opened again.
+func (app *App) applyTheme(updated *config.AppConfig) error {
+	theme, err := updated.ResolveTheme()
+	if err != nil {
+		return err
+	}
+	app.cfg.Theme = updated.Theme
+	app.cfg.ThemeBackground = updated.ThemeBackground
+	app.cfg.ThemeText = updated.ThemeText
+	app.cfg.ThemeBorder = updated.ThemeBorder
+	app.cfg.ThemeAccent = updated.ThemeAccent
+	app.cfg.ThemeModal = updated.ThemeModal
+	app.cfg.ThemeSelection = updated.ThemeSelection
+	shared.ApplyTheme(theme)
+	return nil
+}
@@ -7,14 +7,16 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime/debug"
 	"time"
 
 	"github.com/flokiorg/go-flokicoin/chaincfg"
-	"github.com/flokiorg/go-flokicoin/chainutil"
 	"github.com/flokiorg/twallet/config"
+	"github.com/flokiorg/twallet/flnd"
+	"github.com/flokiorg/twallet/i18n"
 	"github.com/flokiorg/twallet/shared"
 	"github.com/flokiorg/twallet/tui"
 	. "github.com/flokiorg/twallet/utils"
@@ -25,12 +27,8 @@ import (
 
 var (
 	defaultConnectionTimeout = 60 * time.Second
-	defaultNetwork           = &chaincfg.MainNetParams
-	defaultAppDataDir        = "flnd"
-	defaultConfigFilename    = "twallet.conf"
 	defaultMainnetFeeURL     = "https://lokichain.info/api/v1/fees/recommended"
-
-	defaultTransactionDisplayLimit = 121
+	defaultMainnetExplorer   = "https://lokichain.info/tx/%s"
 
 	defaultRPCListener  = "127.0.0.1:10005"
 	defaultRESTListener = "127.0.0.1:5050"
@@ -38,10 +36,28 @@ var (
 	defaultPeerListener = "0.0.0.0:5521"
 
 	parser *flags.Parser
+
+	// notices is where twallet writes what it tells the user outside the
+	// TUI: prompts, migration reports and warnings. It is stderr, so that
+	// stdout carries only the --print-* output and can be piped.
+	notices io.Writer = os.Stderr
 )
 
 type cliOptions struct {
 	config.AppConfig
+	CheckConfig bool `long:"check-config" no-ini:"true" description:"Check the configuration, report the problems found and exit, nonzero when there are some"`
+	PrintConfig bool `long:"print-config" no-ini:"true" description:"Print the resolved configuration, secrets hidden, and exit"`
+
+	PrintStatus  bool          `long:"print-status" no-ini:"true" description:"Start the wallet without the UI, print its state, block height and peers as JSON, and exit"`
+	PrintBalance bool          `long:"print-balance" no-ini:"true" description:"Start the wallet without the UI, print its balance in loki as JSON, and exit"`
+	PrintAddress bool          `long:"print-address" no-ini:"true" description:"Start the wallet without the UI, print a new receive address as JSON, and exit"`
+	PrintTimeout time.Duration `long:"print-timeout" no-ini:"true" default:"2m" description:"How long the --print-* options wait for the wallet. Valid time units are {ms, s, m, h}."`
+}
+
+// reportOnly reports whether twallet was asked to print something and exit,
+// without the UI and without changing the files it reads.
+func reportOnly(opts *cliOptions) bool {
+	return opts.CheckConfig || opts.PrintConfig || queryRequested(opts)
 }
 
 func init() {
@@ -54,22 +70,43 @@ func main() {
 
 	parser = flags.NewParser(&opts, flags.Default|flags.PassDoubleDash)
 	parser.SubcommandsOptional = true
+	if _, err := parser.AddCommand("init", "Write a first configuration file",
+		"Ask for the network, wallet directory, address type and peers, then write a commented twallet.conf.",
+		&initCommand{opts: &opts}); err != nil {
+		log.Fatal().Err(err).Msg("failed to register init command")
+	}
+	if _, err := parser.AddCommand("encrypt", "Encrypt a sensitive option in the configuration file",
+		"Ask for the value of the option and write it encrypted with a passphrase, or with a key kept in the OS keyring when --keyring is given.",
+		&encryptCommand{opts: &opts}); err != nil {
+		log.Fatal().Err(err).Msg("failed to register encrypt command")
+	}
+	config.BindEnv(parser)
 	if _, err := parser.Parse(); err != nil {
 		var flagsErr *flags.Error
 		if errors.As(err, &flagsErr) && flagsErr.Type == flags.ErrHelp {
 			os.Exit(0)
 		}
+		if parser.Active != nil {
+			// The command failed, go-flags printed why.
+			os.Exit(1)
+		}
 		log.Fatal().Err(err).Msg("failed to parse command line")
 	}
+	if parser.Active != nil {
+		return
+	}
 
 	if opts.Version {
-		fmt.Println("Version:", Version)
+		fmt.Print(ReadBuildInfo())
 		return
 	}
 
-	fmt.Println(ArtOrange + ArtBright + ArtText + "\nv" + Version + "\n" + ArtReset)
+	if !reportOnly(&opts) {
+		fmt.Println(ArtOrange + ArtBright + ArtText + "\nv" + Version + "\n" + ArtReset)
+	}
 
-	defaultConfigPath, err := GetFullPath(defaultConfigFilename)
+	dirs := config.DefaultDirs()
+	defaultConfigPath, err := defaultConfigPath(dirs)
 	if err != nil {
 		showHelpAndExit("failed to resolve default config path", err)
 	}
@@ -78,10 +115,34 @@ func main() {
 	}
 
 	if opts.ConfigFile != "" {
-		err := flags.NewIniParser(parser).ParseFile(opts.ConfigFile)
+		// The modes that only report leave the file as it is, the old
+		// options are still read.
+		migrate := config.MigrateFile
+		if reportOnly(&opts) {
+			migrate = config.PlanMigration
+		}
+		migration, err := migrate(opts.ConfigFile)
+		if err != nil {
+			showHelpAndExit("failed to migrate configuration file", err)
+		}
+		if migration != nil {
+			fmt.Fprint(notices, migration)
+		}
+		err = config.ParseFile(parser, &opts.AppConfig, opts.ConfigFile)
 		if err != nil {
 			showHelpAndExit("failed to parse configuration file", err)
 		}
+	} else {
+		if opts.Profile != "" {
+			showHelpAndExit("invalid profile", fmt.Errorf("no configuration file to read the profile %s from", opts.Profile))
+		}
+		// Settings changed from the UI are saved to the default location.
+		opts.ConfigFile = defaultConfigPath
+	}
+
+	// After the file, which can hold the encrypted options.
+	if err := opts.DecryptSecrets(secretKeys(&opts)); err != nil {
+		showHelpAndExit("failed to decrypt the configuration", err)
 	}
 
 	if opt := parser.FindOptionByShortName('t'); !optionDefined(opt) {
@@ -89,23 +150,26 @@ func main() {
 	}
 
 	if opt := parser.FindOptionByShortName('w'); !optionDefined(opt) {
-		opts.Walletdir = chainutil.AppDataDir(defaultAppDataDir, false)
+		opts.Walletdir = defaultWalletDir(dirs, reportOnly(&opts))
 	}
 
 	if opts.TransactionDisplayLimit <= 0 {
-		opts.TransactionDisplayLimit = defaultTransactionDisplayLimit
+		opts.TransactionDisplayLimit = config.DefaultTransactionDisplayLimit
 	}
 
-	opts.Network = defaultNetwork
-	if opts.RegressionTest {
-		opts.Network = &chaincfg.RegressionNetParams
-	} else if opts.Testnet {
-		opts.Network = &chaincfg.TestNet3Params
+	opts.Network, err = opts.ResolveNetwork()
+	if err != nil {
+		showHelpAndExit("invalid network", err)
 	}
+	// Settings saved from the UI go to the section of this network.
+	opts.NetworkName = flnd.NetworkNameOf(opts.Network)
 
 	if opt := parser.FindOptionByLongName("feeurl"); !optionDefined(opt) && opts.Network.Name == chaincfg.MainNetParams.Name {
 		opts.Feeurl = defaultMainnetFeeURL
 	}
+	if opt := parser.FindOptionByLongName("explorerurl"); !optionDefined(opt) && opts.Network.Name == chaincfg.MainNetParams.Name {
+		opts.ExplorerURL = defaultMainnetExplorer
+	}
 
 	// Security Hardening: Set secure defaults if not configured
 	if len(opts.RawRPCListeners) == 0 {
@@ -138,11 +202,81 @@ func main() {
 	opts.UsedAddressType = usedType
 	opts.UnusedAddressType = unusedType
 
+	theme, err := opts.ResolveTheme()
+	if err != nil {
+		showHelpAndExit("invalid theme", err)
+	}
+	shared.ApplyTheme(theme)
+
+	language, err := i18n.ParseLanguage(opts.Language)
+	if err != nil {
+		showHelpAndExit("invalid language", err)
+	}
+	i18n.SetLanguage(language)
+
+	seedLanguage, err := i18n.ParseLanguage(opts.SeedLanguage)
+	if err != nil {
+		showHelpAndExit("invalid seed language", err)
+	}
+	i18n.SetSeedLanguage(seedLanguage)
+
+	denomination, err := shared.ParseDenomination(opts.Denomination)
+	if err != nil {
+		showHelpAndExit("invalid denomination", err)
+	}
+	shared.SetDenomination(denomination)
+
+	timeZone, err := shared.ParseTimeZone(opts.TimeZone)
+	if err != nil {
+		showHelpAndExit("invalid time zone", err)
+	}
+	shared.SetTimeZone(timeZone)
+	if err := shared.ValidateTimeLayout(opts.TimeFormat); err != nil {
+		showHelpAndExit("invalid time format", err)
+	}
+	shared.SetTimeLayout(opts.TimeFormat)
+
+	// The defaults of these depend on the other options, show where they
+	// point to.
+	opts.LogDir = logDir(&opts, dirs)
+	opts.MetadataDir = metadataDir(&opts)
+
+	if opts.PrintConfig {
+		if err := config.WriteEffective(os.Stdout, &opts.AppConfig); err != nil {
+			log.Fatal().Err(err).Msg("failed to print the configuration")
+		}
+		return
+	}
+
+	if opts.CheckConfig {
+		if checkConfig(os.Stdout, &opts, dirs) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
 	logLevel := shared.ParseLogLevel(opts.LogLevel)
-	logPath := filepath.Join(opts.Walletdir, "twallet.log")
-	log.Logger = shared.CreateFileLogger(logPath, logLevel)
-	fmt.Printf("Starting twallet (network=%s, wallet_dir=%s)\n",
-		opts.Network.Name, opts.Walletdir)
+	logPath := filepath.Join(opts.LogDir, "twallet.log")
+	log.Logger = shared.CreateFileLogger(logPath, logLevel, shared.LogRotation{
+		MaxSize:  int64(opts.LogMaxSize) << 20,
+		MaxAge:   opts.LogMaxAge,
+		MaxFiles: opts.LogMaxFiles,
+	})
+
+	if queryRequested(&opts) {
+		if !runQuery(os.Stdout, &opts) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if opts.Profile != "" {
+		fmt.Printf("Starting twallet (profile=%s, network=%s, wallet_dir=%s)\n",
+			opts.Profile, opts.Network.Name, opts.Walletdir)
+	} else {
+		fmt.Printf("Starting twallet (network=%s, wallet_dir=%s)\n",
+			opts.Network.Name, opts.Walletdir)
+	}
 
 	origAutoRecover := os.Getenv("TWALLET_AUTO_RECOVER")
 	restartForRecovery := false
@@ -167,6 +301,7 @@ func main() {
 				}
 			}()
 
+			uiStarted.Store(true)
 			if err := app.Run(); err != nil {
 				app.Stop()
 				log.Fatal().Err(err).Msg("app failed")
@@ -203,6 +338,8 @@ func showHelpAndExit(msg string, err error) {
 	os.Exit(1)
 }
 
+// optionDefined reports whether the user gave opt a value, on the command
+// line, in the config file or in its environment variable.
 func optionDefined(opt *flags.Option) bool {
-	return opt != nil && opt.IsSet()
+	return opt != nil && (opt.IsSet() || config.FromEnv(opt))
 }
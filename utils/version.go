@@ -4,10 +4,112 @@
 
 package utils
 
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
 var (
 	Version string = ".dev"
+
+	// Commit and BuildDate are set by the release builds with -ldflags
+	// "-X github.com/flokiorg/twallet/utils.Commit=...", the ones recorded by
+	// go build stand in otherwise.
+	Commit    string
+	BuildDate string
+)
+
+// Modules whose versions BuildInfo reports, as the wallet behaviour depends
+// on them as much as on twallet.
+const (
+	flndModule     = "github.com/flokiorg/flnd"
+	neutrinoModule = "github.com/flokiorg/flokicoin-neutrino"
 )
 
+// BuildInfo describes the twallet binary, for the bug reports.
+type BuildInfo struct {
+	Version   string
+	Commit    string
+	BuildDate string
+	GoVersion string
+	Platform  string
+	Flnd      string
+	Neutrino  string
+}
+
+// ReadBuildInfo returns the build of the running binary. What go build did
+// not record reads "unknown".
+func ReadBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		modified := false
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		if modified && Commit == "" && info.Commit != "" {
+			info.Commit += "-dirty"
+		}
+		for _, dep := range build.Deps {
+			if dep.Replace != nil {
+				dep = dep.Replace
+			}
+			switch dep.Path {
+			case flndModule:
+				info.Flnd = dep.Version
+			case neutrinoModule:
+				info.Neutrino = dep.Version
+			}
+		}
+	}
+	for _, field := range []*string{&info.Commit, &info.BuildDate, &info.Flnd, &info.Neutrino} {
+		if *field == "" {
+			*field = "unknown"
+		}
+	}
+	return info
+}
+
+// Fields returns the label and value of every part of the build, in the
+// order to show them.
+func (b BuildInfo) Fields() [][2]string {
+	return [][2]string{
+		{"Version", b.Version},
+		{"Commit", b.Commit},
+		{"Build date", b.BuildDate},
+		{"Go", b.GoVersion},
+		{"Platform", b.Platform},
+		{"flnd", b.Flnd},
+		{"neutrino", b.Neutrino},
+	}
+}
+
+func (b BuildInfo) String() string {
+	var s strings.Builder
+	for _, field := range b.Fields() {
+		fmt.Fprintf(&s, "%-11s %s\n", field[0]+":", field[1])
+	}
+	return s.String()
+}
+
 const (
 	ArtReset  = "\033[0m"
 	ArtOrange = "\033[38;2;177;128;10m"